@@ -0,0 +1,471 @@
+package abi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Arc56Contract represents an ARC-56 application specification, which extends
+// the ARC-4 contract description with the information needed to deploy and
+// call the app: its state, its structs, its programs and how to map their
+// program counters back to errors.
+type Arc56Contract struct {
+	// The ARCs the specification conforms to
+	Arcs []uint64 `json:"arcs"`
+	// A user-friendly name for the contract
+	Name string `json:"name"`
+	// Optional, user-friendly description for the contract
+	Desc string `json:"desc,omitempty"`
+	// Optional information about the contract's instances across different
+	// networks
+	Networks map[string]ContractNetworkInfo `json:"networks,omitempty"`
+	// The structs used by the contract, by name
+	Structs map[string][]Arc56StructField `json:"structs"`
+	// The methods that the contract implements
+	Methods []Arc56Method `json:"methods"`
+	// The state of the contract
+	State Arc56State `json:"state"`
+	// The OnComplete actions the contract supports for bare calls, i.e. calls
+	// without a method selector
+	BareActions Arc56Actions `json:"bareActions"`
+	// Optional, information about the approval and clear programs
+	SourceInfo *Arc56SourceInfo `json:"sourceInfo,omitempty"`
+	// Optional, the base64 TEAL source of the approval and clear programs
+	Source *Arc56Programs `json:"source,omitempty"`
+	// Optional, the base64 bytecode of the approval and clear programs
+	ByteCode *Arc56Programs `json:"byteCode,omitempty"`
+	// Optional, the compiler used to produce the programs
+	CompilerInfo *Arc56CompilerInfo `json:"compilerInfo,omitempty"`
+	// Optional, the events the contract may emit
+	Events []Event `json:"events,omitempty"`
+	// Optional, the variables to substitute in the TEAL source before
+	// compiling it, by name
+	TemplateVariables map[string]Arc56TemplateVariable `json:"templateVariables,omitempty"`
+	// Optional, the scratch variables of the programs, by name
+	ScratchVariables map[string]Arc56ScratchVariable `json:"scratchVariables,omitempty"`
+}
+
+// Arc56StructField represents a field of an ARC-56 struct. Its type is either
+// an ABI type, the name of another struct, or a nested anonymous struct given
+// by Fields.
+type Arc56StructField struct {
+	// The name of the field
+	Name string
+	// The ABI type or struct name of the field. Empty if Fields is set.
+	Type string
+	// The fields of a nested anonymous struct
+	Fields []Arc56StructField
+}
+
+type arc56StructFieldJSON struct {
+	Name string          `json:"name"`
+	Type json.RawMessage `json:"type"`
+}
+
+// MarshalJSON encodes the field with its type as a string, or as the array of
+// the nested struct fields.
+func (f Arc56StructField) MarshalJSON() ([]byte, error) {
+	var typ interface{} = f.Type
+	if f.Fields != nil {
+		typ = f.Fields
+	}
+	return json.Marshal(struct {
+		Name string      `json:"name"`
+		Type interface{} `json:"type"`
+	}{f.Name, typ})
+}
+
+// UnmarshalJSON decodes a field whose type is a string or an array of nested
+// struct fields.
+func (f *Arc56StructField) UnmarshalJSON(data []byte) error {
+	var raw arc56StructFieldJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*f = Arc56StructField{Name: raw.Name}
+	if strings.HasPrefix(strings.TrimSpace(string(raw.Type)), "[") {
+		return json.Unmarshal(raw.Type, &f.Fields)
+	}
+	return json.Unmarshal(raw.Type, &f.Type)
+}
+
+// Arc56Actions lists the OnComplete actions allowed when creating the app and
+// when calling it once created, as "NoOp", "OptIn", "CloseOut",
+// "UpdateApplication" or "DeleteApplication".
+type Arc56Actions struct {
+	Create []string `json:"create"`
+	Call   []string `json:"call"`
+}
+
+// Arc56MethodArg represents an argument of an ARC-56 method
+type Arc56MethodArg struct {
+	// The ABI, transaction or reference type of the argument
+	Type string `json:"type"`
+	// Optional, the name of the struct the argument is an instance of, in
+	// which case Type is the equivalent tuple type
+	Struct string `json:"struct,omitempty"`
+	// Optional, user-friendly name for the argument
+	Name string `json:"name,omitempty"`
+	// Optional, user-friendly description for the argument
+	Desc string `json:"desc,omitempty"`
+	// Optional, where to get the value of the argument when it is not given
+	DefaultValue *Arc56DefaultValue `json:"defaultValue,omitempty"`
+}
+
+// Arc56DefaultValue describes the default value of a method argument
+type Arc56DefaultValue struct {
+	// The base64 encoded value for a "literal", the base64 key for "box",
+	// "global" and "local", or the method signature for "method"
+	Data string `json:"data"`
+	// Optional, how Data is encoded if not as the type of the argument
+	Type string `json:"type,omitempty"`
+	// One of "box", "global", "local", "literal" or "method"
+	Source string `json:"source"`
+}
+
+// Arc56Return represents the return value of an ARC-56 method
+type Arc56Return struct {
+	// The ABI type of the return value, or "void"
+	Type string `json:"type"`
+	// Optional, the name of the struct the return value is an instance of
+	Struct string `json:"struct,omitempty"`
+	// Optional, user-friendly description for the return value
+	Desc string `json:"desc,omitempty"`
+}
+
+// Arc56Recommendations are the resources a method call is recommended to be
+// made with
+type Arc56Recommendations struct {
+	InnerTransactionCount uint64          `json:"innerTransactionCount,omitempty"`
+	Boxes                 *Arc56BoxAccess `json:"boxes,omitempty"`
+	Accounts              []string        `json:"accounts,omitempty"`
+	Apps                  []uint64        `json:"apps,omitempty"`
+	Assets                []uint64        `json:"assets,omitempty"`
+}
+
+// Arc56BoxAccess describes a box a method call accesses
+type Arc56BoxAccess struct {
+	// Optional, the app owning the box, if not the called app
+	App uint64 `json:"app,omitempty"`
+	// The base64 key of the box
+	Key        string `json:"key"`
+	ReadBytes  uint64 `json:"readBytes"`
+	WriteBytes uint64 `json:"writeBytes"`
+}
+
+// Arc56Method represents a method of an ARC-56 contract
+type Arc56Method struct {
+	// The name of the method
+	Name string `json:"name"`
+	// Optional, user-friendly description for the method
+	Desc string `json:"desc,omitempty"`
+	// The arguments of the method, in order
+	Args []Arc56MethodArg `json:"args"`
+	// Information about the method's return value
+	Returns Arc56Return `json:"returns"`
+	// The OnComplete actions the method may be called with
+	Actions Arc56Actions `json:"actions"`
+	// Optional, whether the method does not modify the state of the app
+	ReadOnly bool `json:"readonly,omitempty"`
+	// Optional, the events the method may emit
+	Events []Event `json:"events,omitempty"`
+	// Optional, the resources the method is recommended to be called with
+	Recommendations *Arc56Recommendations `json:"recommendations,omitempty"`
+}
+
+// Method returns the ARC-4 description of the method.
+func (m *Arc56Method) Method() Method {
+	args := make([]Arg, len(m.Args))
+	for i, arg := range m.Args {
+		args[i] = Arg{Name: arg.Name, Type: arg.Type, Desc: arg.Desc}
+	}
+	return Method{
+		Name:     m.Name,
+		Desc:     m.Desc,
+		Args:     args,
+		Returns:  Return{Type: m.Returns.Type, Desc: m.Returns.Desc},
+		ReadOnly: m.ReadOnly,
+		Events:   m.Events,
+	}
+}
+
+// Arc56State describes the state of an ARC-56 contract
+type Arc56State struct {
+	Schema Arc56Schema `json:"schema"`
+	// The known keys of the global, local and box state, by name
+	Keys Arc56StorageKeys `json:"keys"`
+	// The maps stored in the global, local and box state, by name
+	Maps Arc56StorageMaps `json:"maps"`
+}
+
+// Arc56Schema is the number of global and local state values the app
+// allocates
+type Arc56Schema struct {
+	Global Arc56SchemaSize `json:"global"`
+	Local  Arc56SchemaSize `json:"local"`
+}
+
+// Arc56SchemaSize is a number of integer and byte slice state values
+type Arc56SchemaSize struct {
+	Ints  uint64 `json:"ints"`
+	Bytes uint64 `json:"bytes"`
+}
+
+// Arc56StorageKeys are the known keys of the global, local and box state
+type Arc56StorageKeys struct {
+	Global map[string]Arc56StorageKey `json:"global"`
+	Local  map[string]Arc56StorageKey `json:"local"`
+	Box    map[string]Arc56StorageKey `json:"box"`
+}
+
+// Arc56StorageKey describes a single state value
+type Arc56StorageKey struct {
+	// Optional, user-friendly description for the value
+	Desc string `json:"desc,omitempty"`
+	// The ABI type, AVM type ("AVMBytes", "AVMString" or "AVMUint64") or
+	// struct name of the key
+	KeyType string `json:"keyType"`
+	// The ABI type, AVM type or struct name of the value
+	ValueType string `json:"valueType"`
+	// The base64 key
+	Key string `json:"key"`
+}
+
+// Arc56StorageMaps are the maps stored in the global, local and box state
+type Arc56StorageMaps struct {
+	Global map[string]Arc56StorageMap `json:"global"`
+	Local  map[string]Arc56StorageMap `json:"local"`
+	Box    map[string]Arc56StorageMap `json:"box"`
+}
+
+// Arc56StorageMap describes a set of state values whose keys share a prefix
+type Arc56StorageMap struct {
+	// Optional, user-friendly description for the map
+	Desc string `json:"desc,omitempty"`
+	// The ABI type, AVM type or struct name of the keys, without the prefix
+	KeyType string `json:"keyType"`
+	// The ABI type, AVM type or struct name of the values
+	ValueType string `json:"valueType"`
+	// Optional, the base64 prefix of the keys
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// Arc56SourceInfo maps the program counters of the approval and clear
+// programs to their source
+type Arc56SourceInfo struct {
+	Approval Arc56ProgramSourceInfo `json:"approval"`
+	Clear    Arc56ProgramSourceInfo `json:"clear"`
+}
+
+// Arc56ProgramSourceInfo maps the program counters of a program to its source
+type Arc56ProgramSourceInfo struct {
+	// "none" if the program counters are those of the program, or "cblocks"
+	// if they are offset to ignore the constant blocks at its start
+	PcOffsetMethod string `json:"pcOffsetMethod"`
+	// The source information of sets of program counters
+	SourceInfo []Arc56PcSourceInfo `json:"sourceInfo"`
+}
+
+// Arc56PcSourceInfo is the source of a set of program counters
+type Arc56PcSourceInfo struct {
+	Pc []uint64 `json:"pc"`
+	// Optional, the error raised when the program fails at these counters
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	// Optional, the line of the TEAL source
+	Teal uint64 `json:"teal,omitempty"`
+	// Optional, the location in the high level source
+	Source string `json:"source,omitempty"`
+}
+
+// ErrorMessage returns the error message of the program failing at the program
+// counter pc, which must already be offset as PcOffsetMethod requires.
+func (p *Arc56ProgramSourceInfo) ErrorMessage(pc uint64) (string, bool) {
+	for _, info := range p.SourceInfo {
+		if info.ErrorMessage == "" {
+			continue
+		}
+		for _, infoPc := range info.Pc {
+			if infoPc == pc {
+				return info.ErrorMessage, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Arc56Programs holds the base64 encoded approval and clear programs
+type Arc56Programs struct {
+	Approval string `json:"approval"`
+	Clear    string `json:"clear"`
+}
+
+// Decode returns the decoded approval and clear programs.
+func (p *Arc56Programs) Decode() (approval []byte, clear []byte, err error) {
+	approval, err = base64.StdEncoding.DecodeString(p.Approval)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid approval program: %w", err)
+	}
+	clear, err = base64.StdEncoding.DecodeString(p.Clear)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid clear program: %w", err)
+	}
+	return approval, clear, nil
+}
+
+// Arc56CompilerInfo identifies the compiler used to produce the programs
+type Arc56CompilerInfo struct {
+	// "algod" or "puya"
+	Compiler        string               `json:"compiler"`
+	CompilerVersion Arc56CompilerVersion `json:"compilerVersion"`
+}
+
+// Arc56CompilerVersion is the version of a compiler
+type Arc56CompilerVersion struct {
+	Major      uint64 `json:"major"`
+	Minor      uint64 `json:"minor"`
+	Patch      uint64 `json:"patch"`
+	CommitHash string `json:"commitHash,omitempty"`
+}
+
+// Arc56TemplateVariable describes a variable to substitute in the TEAL source,
+// written as TMPL_<name>
+type Arc56TemplateVariable struct {
+	// The ABI type, AVM type or struct name of the variable
+	Type string `json:"type"`
+	// Optional, the base64 value of the variable if it is fixed
+	Value string `json:"value,omitempty"`
+}
+
+// Arc56ScratchVariable describes a scratch slot used by the programs
+type Arc56ScratchVariable struct {
+	Slot uint64 `json:"slot"`
+	// The ABI type, AVM type or struct name of the variable
+	Type string `json:"type"`
+}
+
+// ParseArc56 parses an ARC-56 application specification and checks that the
+// types of its methods, events and structs are valid.
+func ParseArc56(data []byte) (Arc56Contract, error) {
+	var c Arc56Contract
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Arc56Contract{}, err
+	}
+	for name := range c.Structs {
+		if _, err := c.StructType(name); err != nil {
+			return Arc56Contract{}, err
+		}
+	}
+	methods := make([]Method, len(c.Methods))
+	for i := range c.Methods {
+		methods[i] = c.Methods[i].Method()
+		if err := c.checkStruct(c.Methods[i].Returns.Struct); err != nil {
+			return Arc56Contract{}, err
+		}
+		for _, arg := range c.Methods[i].Args {
+			if err := c.checkStruct(arg.Struct); err != nil {
+				return Arc56Contract{}, err
+			}
+		}
+	}
+	if err := validateMethods(methods, c.Events); err != nil {
+		return Arc56Contract{}, err
+	}
+	return c, nil
+}
+
+func (c *Arc56Contract) checkStruct(name string) error {
+	if _, ok := c.Structs[name]; name != "" && !ok {
+		return fmt.Errorf("unknown struct %s", name)
+	}
+	return nil
+}
+
+// Contract returns the ARC-4 description of the contract.
+func (c *Arc56Contract) Contract() Contract {
+	methods := make([]Method, len(c.Methods))
+	for i := range c.Methods {
+		methods[i] = c.Methods[i].Method()
+	}
+	return Contract{
+		Name:     c.Name,
+		Desc:     c.Desc,
+		Networks: c.Networks,
+		Methods:  methods,
+		Events:   c.Events,
+	}
+}
+
+// GetMethodByNameOrSignature returns the method of the contract with the
+// signature nameOrSig if it contains an opening parenthesis, and the method
+// named nameOrSig otherwise.
+func (c *Arc56Contract) GetMethodByNameOrSignature(nameOrSig string) (Arc56Method, error) {
+	contract := c.Contract()
+	method, err := GetMethodByNameOrSignature(contract.Methods, nameOrSig)
+	if err != nil {
+		return Arc56Method{}, err
+	}
+	sig := method.GetSignature()
+	for i := range contract.Methods {
+		if contract.Methods[i].GetSignature() == sig {
+			return c.Methods[i], nil
+		}
+	}
+	return Arc56Method{}, fmt.Errorf("found 0 methods with the signature %s", sig)
+}
+
+// StructType returns the tuple type of the struct named name.
+func (c *Arc56Contract) StructType(name string) (Type, error) {
+	typeStr, err := c.structTypeString(name, nil)
+	if err != nil {
+		return Type{}, err
+	}
+	return TypeOf(typeStr)
+}
+
+// StructFieldNames returns the names of the fields of the struct named name, in
+// the order of the elements of its tuple type.
+func (c *Arc56Contract) StructFieldNames(name string) ([]string, error) {
+	fields, ok := c.Structs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown struct %s", name)
+	}
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	return names, nil
+}
+
+func (c *Arc56Contract) structTypeString(name string, seen []string) (string, error) {
+	for _, s := range seen {
+		if s == name {
+			return "", fmt.Errorf("struct %s is recursive", name)
+		}
+	}
+	fields, ok := c.Structs[name]
+	if !ok {
+		return "", fmt.Errorf("unknown struct %s", name)
+	}
+	return c.fieldsTypeString(fields, append(seen, name))
+}
+
+func (c *Arc56Contract) fieldsTypeString(fields []Arc56StructField, seen []string) (string, error) {
+	elems := make([]string, len(fields))
+	for i, field := range fields {
+		var err error
+		switch {
+		case field.Fields != nil:
+			elems[i], err = c.fieldsTypeString(field.Fields, seen)
+		case c.Structs[field.Type] != nil:
+			elems[i], err = c.structTypeString(field.Type, seen)
+		default:
+			elems[i] = field.Type
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "(" + strings.Join(elems, ",") + ")", nil
+}
@@ -0,0 +1,96 @@
+package abi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const arc56TestSpec = `{
+  "arcs": [4, 56],
+  "name": "Orders",
+  "structs": {
+    "Price": [{"name": "amount", "type": "ufixed64x2"}, {"name": "unit", "type": "string"}],
+    "Order": [
+      {"name": "id", "type": "uint64"},
+      {"name": "price", "type": "Price"},
+      {"name": "window", "type": [{"name": "first", "type": "uint64"}, {"name": "last", "type": "uint64"}]}
+    ]
+  },
+  "methods": [
+    {"name": "create", "args": [], "returns": {"type": "void"}, "actions": {"create": ["NoOp"], "call": []}},
+    {"name": "place", "args": [{"type": "(uint64,(ufixed64x2,string),(uint64,uint64))", "struct": "Order", "name": "order"},
+                               {"type": "uint64", "name": "qty", "defaultValue": {"data": "AAAAAAAAAAE=", "source": "literal"}}],
+     "returns": {"type": "(ufixed64x2,string)", "struct": "Price"}, "actions": {"create": [], "call": ["NoOp", "OptIn"]}}
+  ],
+  "state": {
+    "schema": {"global": {"ints": 1, "bytes": 2}, "local": {"ints": 0, "bytes": 1}},
+    "keys": {"global": {"count": {"keyType": "AVMString", "valueType": "AVMUint64", "key": "Y291bnQ="}}, "local": {}, "box": {}},
+    "maps": {"global": {}, "local": {}, "box": {"orders": {"keyType": "uint64", "valueType": "Order", "prefix": "bw=="}}}
+  },
+  "bareActions": {"create": [], "call": ["DeleteApplication"]},
+  "sourceInfo": {
+    "approval": {"pcOffsetMethod": "none", "sourceInfo": [{"pc": [12, 40], "errorMessage": "unknown order"}, {"pc": [50], "teal": 7}]},
+    "clear": {"pcOffsetMethod": "none", "sourceInfo": []}
+  },
+  "byteCode": {"approval": "CoEBQw==", "clear": "CoEBQw=="},
+  "compilerInfo": {"compiler": "puya", "compilerVersion": {"major": 4, "minor": 1, "patch": 0}},
+  "templateVariables": {"FEE": {"type": "uint64", "value": "AAAAAAAAA+g="}}
+}`
+
+func TestParseArc56(t *testing.T) {
+	spec, err := ParseArc56([]byte(arc56TestSpec))
+	require.NoError(t, err)
+	require.Equal(t, []uint64{4, 56}, spec.Arcs)
+	require.Equal(t, uint64(2), spec.State.Schema.Global.Bytes)
+	require.Equal(t, "Y291bnQ=", spec.State.Keys.Global["count"].Key)
+	require.Equal(t, "Order", spec.State.Maps.Box["orders"].ValueType)
+	require.Equal(t, "puya", spec.CompilerInfo.Compiler)
+	require.Equal(t, "AAAAAAAAA+g=", spec.TemplateVariables["FEE"].Value)
+	require.Equal(t, []string{"DeleteApplication"}, spec.BareActions.Call)
+
+	orderType, err := spec.StructType("Order")
+	require.NoError(t, err)
+	require.Equal(t, "(uint64,(ufixed64x2,string),(uint64,uint64))", orderType.String())
+	names, err := spec.StructFieldNames("Order")
+	require.NoError(t, err)
+	require.Equal(t, []string{"id", "price", "window"}, names)
+
+	method, err := spec.GetMethodByNameOrSignature("place")
+	require.NoError(t, err)
+	require.Equal(t, "Order", method.Args[0].Struct)
+	arc4Method := method.Method()
+	require.Equal(t, "place((uint64,(ufixed64x2,string),(uint64,uint64)),uint64)(ufixed64x2,string)", arc4Method.GetSignature())
+
+	message, ok := spec.SourceInfo.Approval.ErrorMessage(40)
+	require.True(t, ok)
+	require.Equal(t, "unknown order", message)
+	_, ok = spec.SourceInfo.Approval.ErrorMessage(50)
+	require.False(t, ok)
+
+	approval, clear, err := spec.ByteCode.Decode()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x0a, 0x81, 0x01, 0x43}, approval)
+	require.Equal(t, approval, clear)
+
+	contract := spec.Contract()
+	require.Len(t, contract.Methods, 2)
+
+	encoded, err := json.Marshal(spec)
+	require.NoError(t, err)
+	reparsed, err := ParseArc56(encoded)
+	require.NoError(t, err)
+	require.Equal(t, spec, reparsed)
+}
+
+func TestParseArc56Invalid(t *testing.T) {
+	_, err := ParseArc56([]byte(`{"name": "c", "structs": {"A": [{"name": "b", "type": "B"}], "B": [{"name": "a", "type": "A"}]}, "methods": []}`))
+	require.Error(t, err)
+
+	_, err = ParseArc56([]byte(`{"name": "c", "structs": {}, "methods": [{"name": "m", "args": [], "returns": {"type": "void", "struct": "Missing"}}]}`))
+	require.Error(t, err)
+
+	_, err = ParseArc56([]byte(`{"name": "c", "structs": {"A": [{"name": "x", "type": "uint7"}]}, "methods": []}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,153 @@
+package abi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// AppendEncode appends the encoding of value as the ABI type t to buf and
+// returns the extended buffer. It takes the values Type.Encode does.
+//
+// Type.Encode converts every element of an array through reflection into a
+// separately allocated encoding, which is slow for box-sized payloads.
+// AppendEncode instead copies a []byte, or a byte array such as a
+// types.Address, directly for byte[], byte[N] and address, and writes
+// []uint64, []uint32 and []uint16 directly for static and dynamic arrays of
+// uint<N>, so that encoding into a reused buffer does not allocate. Other
+// types and values are encoded with Type.Encode.
+func AppendEncode(buf []byte, t Type, value interface{}) ([]byte, error) {
+	out, ok, err := appendEncodeArray(buf, t.String(), value)
+	if ok || err != nil {
+		return out, err
+	}
+	encoded, err := t.Encode(value)
+	if err != nil {
+		return buf, err
+	}
+	return append(buf, encoded...), nil
+}
+
+// appendEncodeArray encodes value as the array type typeStr if it is one of the
+// cases AppendEncode handles directly, and returns false otherwise.
+func appendEncodeArray(buf []byte, typeStr string, value interface{}) ([]byte, bool, error) {
+	if typeStr == "address" {
+		typeStr = "byte[32]"
+	}
+	open := strings.LastIndexByte(typeStr, '[')
+	if open < 0 || !strings.HasSuffix(typeStr, "]") {
+		return buf, false, nil
+	}
+	elem, lengthStr := typeStr[:open], typeStr[open+1:len(typeStr)-1]
+	length := -1
+	if lengthStr != "" {
+		var err error
+		if length, err = strconv.Atoi(lengthStr); err != nil {
+			return buf, false, nil
+		}
+	}
+
+	var elemSize int
+	switch elem {
+	case "byte", "uint8":
+		elemSize = 1
+	case "uint16":
+		elemSize = 2
+	case "uint32":
+		elemSize = 4
+	case "uint64":
+		elemSize = 8
+	default:
+		return buf, false, nil
+	}
+
+	n := -1
+	switch v := value.(type) {
+	case []byte:
+		if elemSize == 1 {
+			n = len(v)
+		}
+	case []uint16:
+		if elemSize >= 2 {
+			n = len(v)
+		}
+	case []uint32:
+		if elemSize >= 4 {
+			n = len(v)
+		}
+	case []uint64:
+		n = len(v)
+	default:
+		rv := reflect.ValueOf(value)
+		if elemSize == 1 && rv.Kind() == reflect.Array && rv.Type().Elem() == byteType {
+			n = rv.Len()
+		}
+	}
+	if n < 0 {
+		return buf, false, nil
+	}
+
+	if length < 0 {
+		if n > math.MaxUint16 {
+			return buf, true, fmt.Errorf("dynamic array length %d exceeds %d", n, math.MaxUint16)
+		}
+		buf = append(buf, byte(n>>8), byte(n))
+	} else if n != length {
+		return buf, true, fmt.Errorf("value length %d does not match static array length %d", n, length)
+	}
+
+	start := len(buf)
+	buf = growBuf(buf, n*elemSize)
+	out := buf[start:]
+	switch v := value.(type) {
+	case []byte:
+		copy(out, v)
+	case []uint16:
+		for i, x := range v {
+			putUint(out[i*elemSize:], elemSize, uint64(x))
+		}
+	case []uint32:
+		for i, x := range v {
+			putUint(out[i*elemSize:], elemSize, uint64(x))
+		}
+	case []uint64:
+		for i, x := range v {
+			if elemSize < 8 && x>>(8*elemSize) != 0 {
+				return buf[:start], true, fmt.Errorf("%d overflows %s", x, elem)
+			}
+			putUint(out[i*elemSize:], elemSize, x)
+		}
+	default:
+		reflect.Copy(reflect.ValueOf(out), reflect.ValueOf(value))
+	}
+	return buf, true, nil
+}
+
+// growBuf extends buf by n bytes, reallocating only if its capacity is too
+// small.
+func growBuf(buf []byte, n int) []byte {
+	if cap(buf)-len(buf) < n {
+		grown := make([]byte, len(buf), 2*cap(buf)+n)
+		copy(grown, buf)
+		buf = grown
+	}
+	return buf[:len(buf)+n]
+}
+
+var byteType = reflect.TypeOf(byte(0))
+
+func putUint(b []byte, size int, x uint64) {
+	switch size {
+	case 1:
+		b[0] = byte(x)
+	case 2:
+		binary.BigEndian.PutUint16(b, uint16(x))
+	case 4:
+		binary.BigEndian.PutUint32(b, uint32(x))
+	case 8:
+		binary.BigEndian.PutUint64(b, x)
+	}
+}
@@ -0,0 +1,127 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendEncode(t *testing.T) {
+	tests := []struct {
+		typeStr string
+		value   interface{}
+	}{
+		{"byte[]", []byte{1, 2, 3}},
+		{"byte[3]", []byte{1, 2, 3}},
+		{"byte[3]", [3]byte{1, 2, 3}},
+		{"address", [32]byte{31: 7}},
+		{"uint8[]", []uint64{1, 255}},
+		{"uint16[2]", []uint16{1, 65535}},
+		{"uint32[]", []uint16{1, 2}},
+		{"uint32[]", []uint32{1, 1 << 31}},
+		{"uint64[]", []uint64{1, 1 << 63}},
+		{"uint64[0]", []uint64{}},
+		{"bool[]", []bool{true, false}},
+		{"(uint64,byte[])", []interface{}{uint64(1), []byte{2}}},
+	}
+	for _, test := range tests {
+		abiType, err := TypeOf(test.typeStr)
+		require.NoError(t, err)
+
+		expected, err := abiType.Encode(test.value)
+		require.NoError(t, err, test.typeStr)
+
+		prefix := []byte{0xaa}
+		encoded, err := AppendEncode(prefix, abiType, test.value)
+		require.NoError(t, err, test.typeStr)
+		require.Equal(t, append([]byte{0xaa}, expected...), encoded, test.typeStr)
+
+		marshalled, err := Marshal(abiType, test.value)
+		require.NoError(t, err, test.typeStr)
+		require.Equal(t, expected, marshalled, test.typeStr)
+	}
+
+	invalid := []struct {
+		typeStr string
+		value   interface{}
+	}{
+		{"byte[3]", []byte{1, 2}},
+		{"address", []byte{1}},
+		{"uint8[]", []uint64{256}},
+		{"uint16[]", []uint64{1 << 16}},
+		{"byte[]", make([]byte, 1<<16)},
+	}
+	for _, test := range invalid {
+		abiType, err := TypeOf(test.typeStr)
+		require.NoError(t, err)
+		_, err = AppendEncode(nil, abiType, test.value)
+		require.Error(t, err, test.typeStr)
+	}
+}
+
+func TestAppendEncodeReusesBuffer(t *testing.T) {
+	abiType, err := TypeOf("byte[]")
+	require.NoError(t, err)
+	payload := make([]byte, 4096)
+
+	buf := make([]byte, 0, len(payload)+2)
+	allocs := testing.AllocsPerRun(10, func() {
+		buf, err = AppendEncode(buf[:0], abiType, payload)
+	})
+	require.NoError(t, err)
+	require.Len(t, buf, len(payload)+2)
+	// the only allocations are the type name built by Type.String and the
+	// payload converted to an interface
+	require.LessOrEqual(t, allocs, 2.0)
+}
+
+// boxPayload is the size of the largest box.
+const boxPayload = 32768 - 2
+
+func BenchmarkEncodeBytes(b *testing.B) {
+	abiType, err := TypeOf("byte[]")
+	require.NoError(b, err)
+	payload := make([]byte, boxPayload)
+
+	b.Run("Type.Encode", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := abiType.Encode(payload); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("AppendEncode", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []byte
+		for i := 0; i < b.N; i++ {
+			if buf, err = AppendEncode(buf[:0], abiType, payload); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkEncodeUint64Array(b *testing.B) {
+	abiType, err := TypeOf("uint64[4096]")
+	require.NoError(b, err)
+	payload := make([]uint64, 4096)
+
+	b.Run("Type.Encode", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := abiType.Encode(payload); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("AppendEncode", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []byte
+		for i := 0; i < b.N; i++ {
+			if buf, err = AppendEncode(buf[:0], abiType, payload); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
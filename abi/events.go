@@ -0,0 +1,132 @@
+package abi
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// DecodedEvent is an event decoded from a log entry
+type DecodedEvent struct {
+	// The definition of the event
+	Event Event
+	// The decoded arguments, in order, as returned by Type.Decode
+	Args []interface{}
+}
+
+// ArgsByName returns the decoded arguments of the event by name. Arguments
+// without a name are omitted.
+func (e *DecodedEvent) ArgsByName() map[string]interface{} {
+	args := make(map[string]interface{}, len(e.Args))
+	for i, arg := range e.Event.Args {
+		if arg.Name != "" {
+			args[arg.Name] = e.Args[i]
+		}
+	}
+	return args
+}
+
+// argsType returns the tuple type of the arguments of the event, which are
+// logged after its selector.
+func (event *Event) argsType() (Type, error) {
+	argTypes := make([]Type, len(event.Args))
+	for i := range event.Args {
+		var err error
+		if argTypes[i], err = event.Args[i].GetTypeObject(); err != nil {
+			return Type{}, err
+		}
+	}
+	return MakeTupleType(argTypes)
+}
+
+// Decode decodes a log entry of the event, made of its selector followed by
+// its ABI encoded arguments.
+func (event *Event) Decode(log []byte) (DecodedEvent, error) {
+	if !bytes.HasPrefix(log, event.GetSelector()) {
+		return DecodedEvent{}, fmt.Errorf("log does not start with the selector of event %s", event.GetSignature())
+	}
+	argsType, err := event.argsType()
+	if err != nil {
+		return DecodedEvent{}, err
+	}
+	decoded, err := argsType.Decode(log[4:])
+	if err != nil {
+		return DecodedEvent{}, fmt.Errorf("could not decode event %s: %w", event.GetSignature(), err)
+	}
+	return DecodedEvent{Event: *event, Args: decoded.([]interface{})}, nil
+}
+
+// Encode returns the log entry of the event with the arguments args, as taken
+// by Type.Encode.
+func (event *Event) Encode(args ...interface{}) ([]byte, error) {
+	if len(args) != len(event.Args) {
+		return nil, fmt.Errorf("event %s takes %d arguments, not %d", event.Name, len(event.Args), len(args))
+	}
+	argsType, err := event.argsType()
+	if err != nil {
+		return nil, err
+	}
+	return AppendEncode(event.GetSelector(), argsType, args)
+}
+
+// EventDecoder matches log entries to a set of events by their selectors.
+type EventDecoder struct {
+	events map[string]Event
+}
+
+// NewEventDecoder returns an EventDecoder for events. An error is returned if
+// an event type is invalid or two different events have the same selector.
+func NewEventDecoder(events []Event) (*EventDecoder, error) {
+	d := &EventDecoder{events: make(map[string]Event, len(events))}
+	for _, event := range events {
+		if _, err := event.argsType(); err != nil {
+			return nil, fmt.Errorf("invalid event %s: %w", event.Name, err)
+		}
+		selector := string(event.GetSelector())
+		if other, ok := d.events[selector]; ok && other.GetSignature() != event.GetSignature() {
+			return nil, fmt.Errorf("events %s and %s have the same selector", other.GetSignature(), event.GetSignature())
+		}
+		d.events[selector] = event
+	}
+	return d, nil
+}
+
+// NewContractEventDecoder returns an EventDecoder for the events of the
+// contract and of its methods.
+func NewContractEventDecoder(c *Contract) (*EventDecoder, error) {
+	events := append([]Event{}, c.Events...)
+	for _, method := range c.Methods {
+		events = append(events, method.Events...)
+	}
+	return NewEventDecoder(events)
+}
+
+// Decode decodes log if it is an entry of one of the events, and returns false
+// if it is not. An error is returned if the log starts with the selector of
+// an event but its arguments cannot be decoded.
+func (d *EventDecoder) Decode(log []byte) (DecodedEvent, bool, error) {
+	if len(log) < 4 {
+		return DecodedEvent{}, false, nil
+	}
+	event, ok := d.events[string(log[:4])]
+	if !ok {
+		return DecodedEvent{}, false, nil
+	}
+	decoded, err := event.Decode(log)
+	return decoded, true, err
+}
+
+// DecodeLogs decodes the logs of a transaction which are entries of the
+// events, in order, skipping the other logs such as the return value.
+func (d *EventDecoder) DecodeLogs(logs [][]byte) ([]DecodedEvent, error) {
+	var events []DecodedEvent
+	for i, log := range logs {
+		decoded, ok, err := d.Decode(log)
+		if err != nil {
+			return nil, fmt.Errorf("log %d: %w", i, err)
+		}
+		if ok {
+			events = append(events, decoded)
+		}
+	}
+	return events, nil
+}
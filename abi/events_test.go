@@ -0,0 +1,60 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventDecoder(t *testing.T) {
+	contract, err := ParseContract([]byte(`{
+  "name": "market",
+  "methods": [{"name": "buy", "args": [{"type": "uint64"}], "returns": {"type": "void"},
+    "events": [{"name": "Bought", "args": [{"type": "address", "name": "buyer"}, {"type": "uint64", "name": "amount"}, {"type": "string"}]}]}],
+  "events": [{"name": "Closed", "args": []}]
+}`))
+	require.NoError(t, err)
+
+	decoder, err := NewContractEventDecoder(&contract)
+	require.NoError(t, err)
+
+	bought := contract.Methods[0].Events[0]
+	buyer := make([]byte, 32)
+	buyer[0] = 1
+	boughtLog, err := bought.Encode(buyer, uint64(5), "note")
+	require.NoError(t, err)
+	require.Equal(t, bought.GetSelector(), boughtLog[:4])
+
+	closed := contract.Events[0]
+	closedLog, err := closed.Encode()
+	require.NoError(t, err)
+	require.Equal(t, closed.GetSelector(), closedLog)
+
+	returnLog := append(append([]byte{}, ReturnPrefix...), 1)
+	events, err := decoder.DecodeLogs([][]byte{[]byte("x"), boughtLog, returnLog, closedLog})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	require.Equal(t, "Bought", events[0].Event.Name)
+	require.Equal(t, []interface{}{buyer, uint64(5), "note"}, events[0].Args)
+	require.Equal(t, map[string]interface{}{"buyer": buyer, "amount": uint64(5)}, events[0].ArgsByName())
+	require.Equal(t, "Closed", events[1].Event.Name)
+	require.Empty(t, events[1].Args)
+
+	_, err = decoder.DecodeLogs([][]byte{boughtLog[:10]})
+	require.Error(t, err)
+
+	_, err = bought.Encode(buyer)
+	require.Error(t, err)
+	_, err = closed.Decode(boughtLog)
+	require.Error(t, err)
+}
+
+func TestNewEventDecoderInvalid(t *testing.T) {
+	_, err := NewEventDecoder([]Event{{Name: "E", Args: []Arg{{Type: "uint7"}}}})
+	require.Error(t, err)
+
+	event := Event{Name: "E", Args: []Arg{{Type: "uint64"}}}
+	_, err = NewEventDecoder([]Event{event, event})
+	require.NoError(t, err)
+}
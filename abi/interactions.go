@@ -2,6 +2,7 @@ package abi
 
 import (
 	"crypto/sha512"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -94,6 +95,11 @@ type Method struct {
 	Args []Arg `json:"args"`
 	// Information about the method's return value
 	Returns Return `json:"returns"`
+	// Optional, whether the method does not modify the state of the app, so
+	// that it can be simulated instead of submitted
+	ReadOnly bool `json:"readonly,omitempty"`
+	// Optional, the events the method may emit
+	Events []Event `json:"events,omitempty"`
 }
 
 // MethodFromSignature decoded a method signature string into a Method object.
@@ -169,6 +175,9 @@ func (method *Method) GetTxCount() int {
 	return cnt
 }
 
+// GetMethodByName returns the only method of methods named name. An error is
+// returned if there is no such method, or several overloads of it, in which
+// case GetMethodBySignature must be used instead.
 func GetMethodByName(methods []Method, name string) (Method, error) {
 	var filteredMethods []Method
 	for _, method := range methods {
@@ -193,6 +202,97 @@ func GetMethodByName(methods []Method, name string) (Method, error) {
 	return filteredMethods[0], nil
 }
 
+// GetMethodBySignature returns the method of methods with the signature sig,
+// e.g. "add(uint64,uint64)uint128".
+func GetMethodBySignature(methods []Method, sig string) (Method, error) {
+	for _, method := range methods {
+		if method.GetSignature() == sig {
+			return method, nil
+		}
+	}
+	return Method{}, fmt.Errorf("found 0 methods with the signature %s", sig)
+}
+
+// GetMethodByNameOrSignature returns the method of methods with the signature
+// nameOrSig if it contains an opening parenthesis, and the method named
+// nameOrSig otherwise.
+func GetMethodByNameOrSignature(methods []Method, nameOrSig string) (Method, error) {
+	if strings.Contains(nameOrSig, "(") {
+		return GetMethodBySignature(methods, nameOrSig)
+	}
+	return GetMethodByName(methods, nameOrSig)
+}
+
+// Event represents an ARC-28 event, which a method emits by logging its
+// selector followed by its ABI encoded arguments
+type Event struct {
+	// The name of the event
+	Name string `json:"name"`
+	// Optional, user-friendly description for the event
+	Desc string `json:"desc,omitempty"`
+	// The arguments of the event, in order
+	Args []Arg `json:"args"`
+}
+
+// GetSignature calculates and returns the signature of the event
+func (event *Event) GetSignature() string {
+	var strTypes []string
+	for _, arg := range event.Args {
+		strTypes = append(strTypes, arg.Type)
+	}
+	return event.Name + "(" + strings.Join(strTypes, ",") + ")"
+}
+
+// GetSelector calculates and returns the 4-byte selector of the event
+func (event *Event) GetSelector() []byte {
+	sigHash := sha512.Sum512_256([]byte(event.GetSignature()))
+	return sigHash[:4]
+}
+
+// validateMethods fills the type object caches of the arguments and return
+// values of methods and events, and returns an error if a type is invalid.
+func validateMethods(methods []Method, events []Event) error {
+	for i := range methods {
+		method := &methods[i]
+		if method.Name == "" {
+			return fmt.Errorf("method at index %d has no name", i)
+		}
+		for j := range method.Args {
+			arg := &method.Args[j]
+			if arg.IsTransactionArg() || arg.IsReferenceArg() {
+				continue
+			}
+			if _, err := arg.GetTypeObject(); err != nil {
+				return fmt.Errorf("Could not parse argument type at index %d of method %s: %w", j, method.Name, err)
+			}
+		}
+		if !method.Returns.IsVoid() {
+			if _, err := method.Returns.GetTypeObject(); err != nil {
+				return fmt.Errorf("Could not parse return type of method %s: %w", method.Name, err)
+			}
+		}
+		if err := validateEvents(method.Events); err != nil {
+			return err
+		}
+	}
+	return validateEvents(events)
+}
+
+func validateEvents(events []Event) error {
+	for i := range events {
+		event := &events[i]
+		if event.Name == "" {
+			return fmt.Errorf("event at index %d has no name", i)
+		}
+		for j := range event.Args {
+			if _, err := event.Args[j].GetTypeObject(); err != nil {
+				return fmt.Errorf("Could not parse argument type at index %d of event %s: %w", j, event.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
 // Interface represents an ABI interface, which is a logically grouped
 // collection of methods
 type Interface struct {
@@ -204,10 +304,30 @@ type Interface struct {
 	Methods []Method `json:"methods"`
 }
 
+// ParseInterface parses the ARC-4 JSON description of an interface and checks
+// that the types of its methods are valid.
+func ParseInterface(data []byte) (Interface, error) {
+	var i Interface
+	if err := json.Unmarshal(data, &i); err != nil {
+		return Interface{}, err
+	}
+	if err := validateMethods(i.Methods, nil); err != nil {
+		return Interface{}, err
+	}
+	return i, nil
+}
+
+// GetMethodByName returns the method of the interface named name.
 func (i *Interface) GetMethodByName(name string) (Method, error) {
 	return GetMethodByName(i.Methods, name)
 }
 
+// GetMethodBySignature returns the method of the interface with the signature
+// sig.
+func (i *Interface) GetMethodBySignature(sig string) (Method, error) {
+	return GetMethodBySignature(i.Methods, sig)
+}
+
 // ContractNetworkInfo contains network-specific information about the contract
 type ContractNetworkInfo struct {
 	// The application ID of the contract for this network
@@ -226,8 +346,52 @@ type Contract struct {
 	Networks map[string]ContractNetworkInfo `json:"networks,omitempty"`
 	// The methods that the contract implements
 	Methods []Method `json:"methods"`
+	// Optional, the events the contract may emit
+	Events []Event `json:"events,omitempty"`
 }
 
+// ParseContract parses the ARC-4 JSON description of a contract and checks
+// that the types of its methods and events are valid.
+func ParseContract(data []byte) (Contract, error) {
+	var c Contract
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Contract{}, err
+	}
+	if err := validateMethods(c.Methods, c.Events); err != nil {
+		return Contract{}, err
+	}
+	return c, nil
+}
+
+// GetMethodByName returns the method of the contract named name.
 func (c *Contract) GetMethodByName(name string) (Method, error) {
 	return GetMethodByName(c.Methods, name)
 }
+
+// GetMethodBySignature returns the method of the contract with the signature
+// sig.
+func (c *Contract) GetMethodBySignature(sig string) (Method, error) {
+	return GetMethodBySignature(c.Methods, sig)
+}
+
+// AppID returns the application ID of the contract on the network with the
+// base64 genesis hash genesisHash, if it is known.
+func (c *Contract) AppID(genesisHash string) (uint64, bool) {
+	info, ok := c.Networks[genesisHash]
+	return info.AppID, ok
+}
+
+// GetEventBySelector returns the event of the contract, or of one of its
+// methods, with the 4-byte selector selector, as logged before its arguments.
+func (c *Contract) GetEventBySelector(selector []byte) (Event, error) {
+	events := c.Events
+	for _, method := range c.Methods {
+		events = append(events[:len(events):len(events)], method.Events...)
+	}
+	for _, event := range events {
+		if string(event.GetSelector()) == string(selector) {
+			return event, nil
+		}
+	}
+	return Event{}, fmt.Errorf("found 0 events with the selector %x", selector)
+}
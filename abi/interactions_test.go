@@ -299,3 +299,72 @@ func TestEncodeJsonContractWithDescription(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expected, string(jsonContract))
 }
+
+func TestParseContract(t *testing.T) {
+	contractJSON := `{
+  "name": "calculator",
+  "desc": "A calculator",
+  "networks": {"wGHE2Pwdvd7S12BL5FaOP20EGYesN73ktiC1qzkkit8=": {"appID": 1234}},
+  "methods": [
+    {"name": "add", "desc": "Adds", "args": [{"type": "uint64", "name": "a"}, {"type": "uint64", "name": "b"}], "returns": {"type": "uint128"}, "readonly": true},
+    {"name": "add", "args": [{"type": "uint32"}, {"type": "uint32"}], "returns": {"type": "uint32"}},
+    {"name": "pay", "args": [{"type": "pay"}, {"type": "account"}], "returns": {"type": "void"},
+     "events": [{"name": "Paid", "args": [{"type": "address", "name": "to"}, {"type": "uint64", "name": "amount"}]}]}
+  ],
+  "events": [{"name": "Reset", "desc": "The calculator was reset", "args": []}]
+}`
+
+	contract, err := ParseContract([]byte(contractJSON))
+	require.NoError(t, err)
+	require.Equal(t, "calculator", contract.Name)
+	require.Equal(t, "A calculator", contract.Desc)
+	require.Len(t, contract.Methods, 3)
+	require.True(t, contract.Methods[0].ReadOnly)
+
+	appID, ok := contract.AppID("wGHE2Pwdvd7S12BL5FaOP20EGYesN73ktiC1qzkkit8=")
+	require.True(t, ok)
+	require.Equal(t, uint64(1234), appID)
+	_, ok = contract.AppID("unknown")
+	require.False(t, ok)
+
+	_, err = contract.GetMethodByName("add")
+	require.Error(t, err)
+	method, err := contract.GetMethodBySignature("add(uint32,uint32)uint32")
+	require.NoError(t, err)
+	require.Equal(t, "uint32", method.Returns.Type)
+	_, err = contract.GetMethodBySignature("add(uint8,uint8)uint8")
+	require.Error(t, err)
+
+	method, err = GetMethodByNameOrSignature(contract.Methods, "pay")
+	require.NoError(t, err)
+	require.Equal(t, 2, method.GetTxCount())
+
+	paid := method.Events[0]
+	require.Equal(t, "Paid(address,uint64)", paid.GetSignature())
+	event, err := contract.GetEventBySelector(paid.GetSelector())
+	require.NoError(t, err)
+	require.Equal(t, paid.Name, event.Name)
+
+	event, err = contract.GetEventBySelector(contract.Events[0].GetSelector())
+	require.NoError(t, err)
+	require.Equal(t, "Reset", event.Name)
+	_, err = contract.GetEventBySelector([]byte{0, 0, 0, 0})
+	require.Error(t, err)
+}
+
+func TestParseContractInvalid(t *testing.T) {
+	_, err := ParseContract([]byte(`{"name": "c", "methods": [{"name": "m", "args": [{"type": "uint7"}], "returns": {"type": "void"}}]}`))
+	require.Error(t, err)
+
+	_, err = ParseContract([]byte(`{"name": "c", "methods": [], "events": [{"name": "E", "args": [{"type": "pay"}]}]}`))
+	require.Error(t, err)
+
+	_, err = ParseInterface([]byte(`{"name": "i", "methods": [{"args": [], "returns": {"type": "void"}}]}`))
+	require.Error(t, err)
+
+	iface, err := ParseInterface([]byte(`{"name": "i", "methods": [{"name": "m", "args": [], "returns": {"type": "byte[]"}}]}`))
+	require.NoError(t, err)
+	method, err := iface.GetMethodBySignature("m()byte[]")
+	require.NoError(t, err)
+	require.Equal(t, "m", method.Name)
+}
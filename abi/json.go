@@ -0,0 +1,185 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// MarshalValueJSON returns a human-readable JSON representation of value, a
+// value of the ABI type t as taken by Marshal, e.g. to display the arguments
+// or return value of a method call:
+//
+//	uint<N> and byte are numbers, and ufixed<N>x<M> numbers with M decimal
+//	places, written exactly whatever their size
+//	bool and string are booleans and strings
+//	address is the base32 address
+//	byte[] and byte[N] are base64 strings
+//	other arrays and tuples are arrays
+//
+// indent is used to indent nested arrays as json.MarshalIndent does, or the
+// representation is compact if it is empty.
+func MarshalValueJSON(t Type, value interface{}, indent string) ([]byte, error) {
+	encoded, err := Marshal(t, value)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := t.Decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	jsonValue, err := toJSONValue(t, decoded)
+	if err != nil {
+		return nil, err
+	}
+	if indent == "" {
+		return json.Marshal(jsonValue)
+	}
+	return json.MarshalIndent(jsonValue, "", indent)
+}
+
+// UnmarshalValueJSON parses the JSON representation of a value of the ABI type
+// t, as returned by MarshalValueJSON, and returns the value as Type.Decode
+// would. Byte arrays may also be given as arrays of numbers.
+func UnmarshalValueJSON(t Type, data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var jsonValue interface{}
+	if err := dec.Decode(&jsonValue); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	value, err := fromJSONValue(t, jsonValue)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := t.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+	return t.Decode(encoded)
+}
+
+func isByteArray(info typeInfo) bool {
+	return !info.tuple && len(info.elems) > 0 && info.elems[0].String() == "byte"
+}
+
+// toJSONValue converts decoded, a value returned by Type.Decode for t, to the
+// value of its JSON representation.
+func toJSONValue(t Type, decoded interface{}) (interface{}, error) {
+	info, err := infoOf(t)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case info.name == "address":
+		var addr types.Address
+		copy(addr[:], decoded.([]byte))
+		return addr.String(), nil
+	case info.precision >= 0:
+		s, err := FormatUfixed(t, decoded)
+		return json.Number(s), err
+	case isByteArray(info):
+		values := decoded.([]interface{})
+		b := make([]byte, len(values))
+		for i, v := range values {
+			b[i] = v.(byte)
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	case len(info.elems) > 0 || info.tuple:
+		values := decoded.([]interface{})
+		jsonValues := make([]interface{}, len(values))
+		for i, v := range values {
+			elem := info.elems[0]
+			if !info.dynamic {
+				elem = info.elems[i]
+			}
+			if jsonValues[i], err = toJSONValue(elem, v); err != nil {
+				return nil, err
+			}
+		}
+		return jsonValues, nil
+	}
+	switch decoded.(type) {
+	case bool, string:
+		return decoded, nil
+	}
+	i, err := decodedBigInt(decoded)
+	if err != nil {
+		return nil, err
+	}
+	return json.Number(i.String()), nil
+}
+
+// fromJSONValue converts jsonValue, decoded from JSON with numbers as
+// json.Number, to a value of t as taken by Type.Encode.
+func fromJSONValue(t Type, jsonValue interface{}) (interface{}, error) {
+	info, err := infoOf(t)
+	if err != nil {
+		return nil, err
+	}
+	invalid := fmt.Errorf("invalid JSON value %v for %s", jsonValue, t)
+
+	switch v := jsonValue.(type) {
+	case bool:
+		if info.name != "bool" {
+			return nil, invalid
+		}
+		return v, nil
+	case string:
+		switch {
+		case info.name == "string":
+			return v, nil
+		case info.name == "address":
+			addr, err := types.DecodeAddress(v)
+			if err != nil {
+				return nil, err
+			}
+			return addr[:], nil
+		case isByteArray(info):
+			return base64.StdEncoding.DecodeString(v)
+		}
+		return nil, invalid
+	case json.Number:
+		if info.precision >= 0 {
+			return ParseUfixed(t, v.String(), RoundExact)
+		}
+		i, ok := new(big.Int).SetString(v.String(), 10)
+		if !ok || len(info.elems) > 0 || info.tuple || info.name == "bool" || info.name == "string" {
+			return nil, invalid
+		}
+		if info.name == "byte" {
+			if !i.IsUint64() || i.Uint64() > 255 {
+				return nil, fmt.Errorf("%s overflows byte", i)
+			}
+			return byte(i.Uint64()), nil
+		}
+		return i, nil
+	case []interface{}:
+		if len(info.elems) == 0 && !info.tuple {
+			return nil, invalid
+		}
+		if !info.dynamic && len(v) != len(info.elems) {
+			return nil, fmt.Errorf("expected %d elements for %s, got %d", len(info.elems), t, len(v))
+		}
+		values := make([]interface{}, len(v))
+		for i := range v {
+			elem := info.elems[0]
+			if !info.dynamic {
+				elem = info.elems[i]
+			}
+			if values[i], err = fromJSONValue(elem, v[i]); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	default:
+		return nil, invalid
+	}
+}
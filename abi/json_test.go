@@ -0,0 +1,78 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueJSON(t *testing.T) {
+	abiType, err := TypeOf("(uint64,uint256,ufixed64x3,bool,string,address,byte[],byte[2],uint8[],(bool,byte))")
+	require.NoError(t, err)
+
+	addr := make([]byte, 32)
+	large, ok := new(big.Int).SetString("123456789012345678901234567890", 10)
+	require.True(t, ok)
+	value := []interface{}{
+		uint64(1 << 63),
+		large,
+		uint64(12345),
+		true,
+		"hi",
+		addr,
+		[]byte{1, 2, 3},
+		[]byte{4, 5},
+		[]interface{}{uint8(7), uint8(8)},
+		[]interface{}{false, byte(9)},
+	}
+
+	expected := `[9223372036854775808,123456789012345678901234567890,12.345,true,"hi","AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAY5HFKQ","AQID","BAU=",[7,8],[false,9]]`
+	encoded, err := MarshalValueJSON(abiType, value, "")
+	require.NoError(t, err)
+	require.Equal(t, expected, string(encoded))
+
+	decoded, err := UnmarshalValueJSON(abiType, encoded)
+	require.NoError(t, err)
+	expectedDecoded, err := abiType.Encode(value)
+	require.NoError(t, err)
+	reencoded, err := abiType.Encode(decoded)
+	require.NoError(t, err)
+	require.Equal(t, expectedDecoded, reencoded)
+
+	indented, err := MarshalValueJSON(abiType, value, "  ")
+	require.NoError(t, err)
+	require.Contains(t, string(indented), "\n  12.345,\n")
+
+	bytesType, err := TypeOf("byte[2]")
+	require.NoError(t, err)
+	decoded, err = UnmarshalValueJSON(bytesType, []byte(`[4, 5]`))
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{byte(4), byte(5)}, decoded)
+}
+
+func TestUnmarshalValueJSONInvalid(t *testing.T) {
+	tests := []struct {
+		typeStr string
+		json    string
+	}{
+		{"uint8", `256`},
+		{"uint64", `-1`},
+		{"uint64", `1.5`},
+		{"uint64", `"1"`},
+		{"ufixed64x2", `1.234`},
+		{"byte", `300`},
+		{"bool", `1`},
+		{"address", `"not an address"`},
+		{"byte[2]", `"AQID"`},
+		{"(uint64,bool)", `[1]`},
+		{"uint64[]", `{}`},
+		{"uint64", `1 2`},
+	}
+	for _, test := range tests {
+		abiType, err := TypeOf(test.typeStr)
+		require.NoError(t, err)
+		_, err = UnmarshalValueJSON(abiType, []byte(test.json))
+		require.Error(t, err, "%s %s", test.typeStr, test.json)
+	}
+}
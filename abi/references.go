@@ -0,0 +1,128 @@
+package abi
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// ForeignReferences are the foreign arrays of an application call: the
+// accounts, apps and assets it references besides its sender and the called
+// app.
+type ForeignReferences struct {
+	Accounts []types.Address
+	Apps     []uint64
+	Assets   []uint64
+}
+
+// Resolve returns the index by which a method argument of the reference type
+// refType and value value is passed to the app, adding the value to the
+// foreign arrays unless it is already there. The sender is account 0 and the
+// called app is app 0, so they are never added, and the other accounts and
+// apps are numbered from 1. Accounts are given as types.Address, a base32
+// string or the value of an ABI address, and apps and assets as the value of
+// an ABI uint64.
+func (f *ForeignReferences) Resolve(refType string, value interface{}, sender types.Address, appID uint64) (int, error) {
+	switch refType {
+	case AccountReferenceType:
+		address, err := referenceAddress(value)
+		if err != nil {
+			return 0, err
+		}
+		if address == sender {
+			return 0, nil
+		}
+		for i, account := range f.Accounts {
+			if account == address {
+				return i + 1, nil
+			}
+		}
+		f.Accounts = append(f.Accounts, address)
+		return len(f.Accounts), nil
+	case ApplicationReferenceType:
+		app, err := referenceUint64(value)
+		if err != nil {
+			return 0, err
+		}
+		if app == appID {
+			return 0, nil
+		}
+		for i, foreignApp := range f.Apps {
+			if foreignApp == app {
+				return i + 1, nil
+			}
+		}
+		f.Apps = append(f.Apps, app)
+		return len(f.Apps), nil
+	case AssetReferenceType:
+		asset, err := referenceUint64(value)
+		if err != nil {
+			return 0, err
+		}
+		for i, foreignAsset := range f.Assets {
+			if foreignAsset == asset {
+				return i, nil
+			}
+		}
+		f.Assets = append(f.Assets, asset)
+		return len(f.Assets) - 1, nil
+	default:
+		return 0, fmt.Errorf("Unknown reference type: %s", refType)
+	}
+}
+
+// ResolveMethodArgs returns args, the arguments of a call to method, with the
+// values of its reference arguments replaced by the indexes Resolve returns
+// for them, as they are encoded in the call. The other arguments, including
+// transactions, are returned unchanged.
+func ResolveMethodArgs(method *Method, args []interface{}, sender types.Address, appID uint64, foreign *ForeignReferences) ([]interface{}, error) {
+	if len(args) != len(method.Args) {
+		return nil, fmt.Errorf("the incorrect number of arguments were provided: %d != %d", len(args), len(method.Args))
+	}
+	resolved := make([]interface{}, len(args))
+	for i, arg := range method.Args {
+		if !arg.IsReferenceArg() {
+			resolved[i] = args[i]
+			continue
+		}
+		index, err := foreign.Resolve(arg.Type, args[i], sender, appID)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d of type %s: %w", i, arg.Type, err)
+		}
+		resolved[i] = index
+	}
+	return resolved, nil
+}
+
+func referenceAddress(value interface{}) (types.Address, error) {
+	switch v := value.(type) {
+	case types.Address:
+		return v, nil
+	case string:
+		return types.DecodeAddress(v)
+	}
+	var address types.Address
+	encoded, err := Marshal(addressType, value)
+	if err != nil {
+		return address, err
+	}
+	copy(address[:], encoded)
+	return address, nil
+}
+
+func referenceUint64(value interface{}) (uint64, error) {
+	encoded, err := Marshal(uint64Type, value)
+	if err != nil {
+		return 0, err
+	}
+	decoded, err := uint64Type.Decode(encoded)
+	if err != nil {
+		return 0, err
+	}
+	return decoded.(uint64), nil
+}
+
+var (
+	addressType, _ = TypeOf("address")
+	uint64Type, _  = TypeOf("uint64")
+)
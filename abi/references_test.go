@@ -0,0 +1,48 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveMethodArgs(t *testing.T) {
+	method, err := MethodFromSignature("use(account,application,asset,uint64,account,application,asset,account,pay)void")
+	require.NoError(t, err)
+
+	sender := types.Address{1}
+	other := types.Address{2}
+	existing := types.Address{3}
+	foreign := ForeignReferences{
+		Accounts: []types.Address{existing},
+		Apps:     []uint64{7},
+		Assets:   []uint64{9},
+	}
+
+	args := []interface{}{other, uint64(5), 9, 42, sender, 7, uint64(11), existing.String(), "txn"}
+	resolved, err := ResolveMethodArgs(&method, args, sender, 5, &foreign)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{2, 0, 0, 42, 0, 1, 1, 1, "txn"}, resolved)
+	require.Equal(t, ForeignReferences{
+		Accounts: []types.Address{existing, other},
+		Apps:     []uint64{7},
+		Assets:   []uint64{9, 11},
+	}, foreign)
+
+	_, err = ResolveMethodArgs(&method, args[:1], sender, 5, &foreign)
+	require.Error(t, err)
+
+	args[0] = "not an address"
+	_, err = ResolveMethodArgs(&method, args, sender, 5, &foreign)
+	require.Error(t, err)
+
+	_, err = foreign.Resolve(AssetReferenceType, -1, sender, 5)
+	require.Error(t, err)
+	_, err = foreign.Resolve("box", 1, sender, 5)
+	require.Error(t, err)
+
+	index, err := foreign.Resolve(AccountReferenceType, other[:], sender, 5)
+	require.NoError(t, err)
+	require.Equal(t, 2, index)
+}
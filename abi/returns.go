@@ -0,0 +1,50 @@
+package abi
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ReturnPrefix is the 4-byte prefix of the log holding the return value of a
+// method call, from https://github.com/algorandfoundation/ARCs/blob/main/ARCs/arc-0004.md#standard-format
+var ReturnPrefix = []byte{0x15, 0x1f, 0x7c, 0x75}
+
+// ErrNoReturnValue is returned when the logs of a method call do not end with
+// a return value.
+var ErrNoReturnValue = errors.New("method call did not log a return value")
+
+// RawReturnValue returns the encoded return value of a method call from the
+// logs of its confirmed transaction, i.e. the last log without ReturnPrefix.
+// ErrNoReturnValue is returned if the last log does not start with
+// ReturnPrefix.
+func RawReturnValue(logs [][]byte) ([]byte, error) {
+	if len(logs) == 0 {
+		return nil, ErrNoReturnValue
+	}
+	lastLog := logs[len(logs)-1]
+	if !bytes.HasPrefix(lastLog, ReturnPrefix) {
+		return nil, ErrNoReturnValue
+	}
+	return lastLog[len(ReturnPrefix):], nil
+}
+
+// DecodeReturnValue extracts the return value of a call to the method from the
+// logs of its confirmed transaction, e.g. as returned by the pending
+// transaction or indexer endpoints, and decodes it as the return type of the
+// method. The raw return value is returned along with the decoded one, and is
+// empty for a void method, which may log anything.
+func (method *Method) DecodeReturnValue(logs [][]byte) (raw []byte, value interface{}, err error) {
+	if method.Returns.IsVoid() {
+		return []byte{}, nil, nil
+	}
+	raw, err = RawReturnValue(logs)
+	if err != nil {
+		return nil, nil, err
+	}
+	abiType, err := method.Returns.GetTypeObject()
+	if err != nil {
+		return raw, nil, err
+	}
+	value, err = abiType.Decode(raw)
+	return raw, value, err
+}
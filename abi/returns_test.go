@@ -0,0 +1,35 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeReturnValue(t *testing.T) {
+	method, err := MethodFromSignature("add(uint64,uint64)uint64")
+	require.NoError(t, err)
+
+	payload := []byte{0, 0, 0, 0, 0, 0, 0, 42}
+	logs := [][]byte{[]byte("debug"), append(append([]byte{}, ReturnPrefix...), payload...)}
+
+	raw, value, err := method.DecodeReturnValue(logs)
+	require.NoError(t, err)
+	require.Equal(t, payload, raw)
+	require.Equal(t, uint64(42), value)
+
+	_, _, err = method.DecodeReturnValue(logs[:1])
+	require.ErrorIs(t, err, ErrNoReturnValue)
+	_, _, err = method.DecodeReturnValue(nil)
+	require.ErrorIs(t, err, ErrNoReturnValue)
+
+	_, _, err = method.DecodeReturnValue([][]byte{append(append([]byte{}, ReturnPrefix...), 1, 2)})
+	require.Error(t, err)
+
+	void, err := MethodFromSignature("reset()void")
+	require.NoError(t, err)
+	raw, value, err = void.DecodeReturnValue(logs[:1])
+	require.NoError(t, err)
+	require.Empty(t, raw)
+	require.Nil(t, value)
+}
@@ -0,0 +1,377 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Marshal encodes a Go value as the ABI type t. Unlike Type.Encode, which
+// only takes the generic values it decodes to, it takes typed Go values:
+//
+//	structs, or pointers to them, for tuples, with one exported field per
+//	element in declaration order; fields tagged `abi:"-"` are skipped, and
+//	other tags name the element
+//	slices and arrays for static and dynamic arrays and addresses, including
+//	types.Address and []byte, and for tuples, with one element per field
+//	any integer type, *big.Int or big.Int for uint<N> and byte
+//	*big.Rat for ufixed<N>x<M>, as well as the raw integer value
+//	bool and string, including named types with those kinds
+//	interface{} holding any of the above
+func Marshal(t Type, v interface{}) ([]byte, error) {
+	if encoded, ok, err := appendEncodeArray([]byte{}, t.String(), v); ok || err != nil {
+		return encoded, err
+	}
+	value, err := toABIValue(t, reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	return t.Encode(value)
+}
+
+// Unmarshal decodes the encoding of a value of the ABI type t into the value
+// pointed to by v, which takes the Go types Marshal does. Integers must fit in
+// the integer type they are decoded to, and *big.Rat gets the value of a
+// ufixed<N>x<M> scaled down by 10^M. An interface{} gets the generic value
+// returned by Type.Decode.
+func Unmarshal(t Type, encoded []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot unmarshal into non-pointer %T", v)
+	}
+	decoded, err := t.Decode(encoded)
+	if err != nil {
+		return err
+	}
+	return fromABIValue(t, decoded, rv.Elem())
+}
+
+var (
+	bigIntType = reflect.TypeOf(big.Int{})
+	bigRatType = reflect.TypeOf(big.Rat{})
+)
+
+// typeInfo is the structure of an ABI type, which Type does not expose.
+type typeInfo struct {
+	// elems are the element types of tuples, arrays and addresses.
+	elems []Type
+	// dynamic whether an array is dynamic, so elems has only the element type
+	dynamic bool
+	tuple   bool
+	// precision the M of ufixed<N>x<M>, or -1 for other types
+	precision int
+	name      string
+}
+
+func infoOf(t Type) (typeInfo, error) {
+	s := t.String()
+	info := typeInfo{precision: -1, name: s}
+	switch {
+	case s == "address":
+		info.elems = make([]Type, 32)
+		for i := range info.elems {
+			info.elems[i], _ = TypeOf("byte")
+		}
+	case strings.HasSuffix(s, "[]"):
+		elem, err := TypeOf(s[:len(s)-2])
+		if err != nil {
+			return info, err
+		}
+		info.elems = []Type{elem}
+		info.dynamic = true
+	case strings.HasSuffix(s, "]"):
+		i := strings.LastIndexByte(s, '[')
+		length, err := strconv.Atoi(s[i+1 : len(s)-1])
+		if err != nil {
+			return info, err
+		}
+		elem, err := TypeOf(s[:i])
+		if err != nil {
+			return info, err
+		}
+		info.elems = make([]Type, length)
+		for j := range info.elems {
+			info.elems[j] = elem
+		}
+	case strings.HasPrefix(s, "("):
+		info.tuple = true
+		for _, elemStr := range splitTuple(s[1 : len(s)-1]) {
+			elem, err := TypeOf(elemStr)
+			if err != nil {
+				return info, err
+			}
+			info.elems = append(info.elems, elem)
+		}
+	case strings.HasPrefix(s, "ufixed"):
+		precision, err := strconv.Atoi(s[strings.LastIndexByte(s, 'x')+1:])
+		if err != nil {
+			return info, err
+		}
+		info.precision = precision
+	}
+	return info, nil
+}
+
+// splitTuple splits the content of a tuple type string into its elements.
+func splitTuple(content string) []string {
+	if content == "" {
+		return nil
+	}
+	var elems []string
+	depth, start := 0, 0
+	for i, c := range content {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				elems = append(elems, content[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(elems, content[start:])
+}
+
+// structFields returns the fields of a struct type which are tuple elements.
+func structFields(t reflect.Type) []int {
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Tag.Get("abi") == "-" {
+			continue
+		}
+		fields = append(fields, i)
+	}
+	return fields
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// toABIValue converts v to the generic value Type.Encode takes for t.
+func toABIValue(t Type, v reflect.Value) (interface{}, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, fmt.Errorf("cannot encode nil as %s", t)
+		}
+		v = v.Elem()
+	}
+	info, err := infoOf(t)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v.Type() {
+	case bigIntType:
+		i := v.Interface().(big.Int)
+		return &i, nil
+	case bigRatType:
+		if info.precision < 0 {
+			return nil, fmt.Errorf("cannot encode *big.Rat as %s", t)
+		}
+		r := v.Interface().(big.Rat)
+		return UfixedFromRat(t, &r, RoundExact)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if info.name == "byte" {
+			if v.Uint() > 255 {
+				return nil, fmt.Errorf("%d overflows byte", v.Uint())
+			}
+			return byte(v.Uint()), nil
+		}
+		return v.Uint(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if info.name == "byte" {
+			if v.Int() < 0 || v.Int() > 255 {
+				return nil, fmt.Errorf("%d overflows byte", v.Int())
+			}
+			return byte(v.Int()), nil
+		}
+		return v.Int(), nil
+	case reflect.Slice, reflect.Array:
+		if len(info.elems) == 0 {
+			if info.name == "string" && v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+				return string(v.Bytes()), nil
+			}
+			return nil, fmt.Errorf("cannot encode %s as %s", v.Type(), t)
+		}
+		if !info.dynamic && len(info.elems) != v.Len() {
+			return nil, fmt.Errorf("cannot encode %s of length %d as %s", v.Type(), v.Len(), t)
+		}
+		values := make([]interface{}, v.Len())
+		for i := range values {
+			elem := info.elems[0]
+			if !info.dynamic {
+				elem = info.elems[i]
+			}
+			if values[i], err = toABIValue(elem, v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	case reflect.Struct:
+		fields := structFields(v.Type())
+		if !info.tuple || len(fields) != len(info.elems) {
+			return nil, fmt.Errorf("cannot encode %s with %d fields as %s", v.Type(), len(fields), t)
+		}
+		values := make([]interface{}, len(fields))
+		for i, field := range fields {
+			if values[i], err = toABIValue(info.elems[i], v.Field(field)); err != nil {
+				return nil, fmt.Errorf("field %s: %w", v.Type().Field(field).Name, err)
+			}
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("cannot encode %s as %s", v.Type(), t)
+	}
+}
+
+// fromABIValue stores decoded, a generic value returned by Type.Decode for t,
+// in dst.
+func fromABIValue(t Type, decoded interface{}, dst reflect.Value) error {
+	if dst.Kind() == reflect.Interface && dst.NumMethod() == 0 {
+		dst.Set(reflect.ValueOf(decoded))
+		return nil
+	}
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return fromABIValue(t, decoded, dst.Elem())
+	}
+	info, err := infoOf(t)
+	if err != nil {
+		return err
+	}
+
+	switch dst.Type() {
+	case bigIntType:
+		i, err := decodedBigInt(decoded)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(*i))
+		return nil
+	case bigRatType:
+		if info.precision < 0 {
+			return fmt.Errorf("cannot decode %s into *big.Rat", t)
+		}
+		r, err := UfixedToRat(t, decoded)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(*r))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Bool:
+		b, ok := decoded.(bool)
+		if !ok {
+			return fmt.Errorf("cannot decode %s into %s", t, dst.Type())
+		}
+		dst.SetBool(b)
+	case reflect.String:
+		s, ok := decoded.(string)
+		if !ok {
+			return fmt.Errorf("cannot decode %s into %s", t, dst.Type())
+		}
+		dst.SetString(s)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, err := decodedBigInt(decoded)
+		if err != nil {
+			return err
+		}
+		if !i.IsUint64() || dst.OverflowUint(i.Uint64()) {
+			return fmt.Errorf("%s overflows %s", i, dst.Type())
+		}
+		dst.SetUint(i.Uint64())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := decodedBigInt(decoded)
+		if err != nil {
+			return err
+		}
+		if !i.IsInt64() || dst.OverflowInt(i.Int64()) {
+			return fmt.Errorf("%s overflows %s", i, dst.Type())
+		}
+		dst.SetInt(i.Int64())
+	case reflect.Slice, reflect.Array:
+		var values []interface{}
+		switch decoded := decoded.(type) {
+		case []interface{}:
+			values = decoded
+		case []byte:
+			for _, b := range decoded {
+				values = append(values, b)
+			}
+		case string:
+			if dst.Type().Elem().Kind() != reflect.Uint8 || dst.Kind() != reflect.Slice {
+				return fmt.Errorf("cannot decode %s into %s", t, dst.Type())
+			}
+			dst.SetBytes([]byte(decoded))
+			return nil
+		default:
+			return fmt.Errorf("cannot decode %s into %s", t, dst.Type())
+		}
+		if dst.Kind() == reflect.Array {
+			if dst.Len() != len(values) {
+				return fmt.Errorf("cannot decode %s of length %d into %s", t, len(values), dst.Type())
+			}
+		} else {
+			dst.Set(reflect.MakeSlice(dst.Type(), len(values), len(values)))
+		}
+		for i, value := range values {
+			elem := info.elems[0]
+			if !info.dynamic {
+				elem = info.elems[i]
+			}
+			if err := fromABIValue(elem, value, dst.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		values, ok := decoded.([]interface{})
+		fields := structFields(dst.Type())
+		if !ok || !info.tuple || len(fields) != len(values) {
+			return fmt.Errorf("cannot decode %s into %s with %d fields", t, dst.Type(), len(fields))
+		}
+		for i, field := range fields {
+			if err := fromABIValue(info.elems[i], values[i], dst.Field(field)); err != nil {
+				return fmt.Errorf("field %s: %w", dst.Type().Field(field).Name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("cannot decode %s into %s", t, dst.Type())
+	}
+	return nil
+}
+
+// decodedBigInt returns the value of a decoded uint<N>, ufixed<N>x<M> or byte.
+func decodedBigInt(decoded interface{}) (*big.Int, error) {
+	switch decoded := decoded.(type) {
+	case uint8:
+		return new(big.Int).SetUint64(uint64(decoded)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(decoded)), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(decoded)), nil
+	case uint64:
+		return new(big.Int).SetUint64(decoded), nil
+	case *big.Int:
+		return decoded, nil
+	default:
+		return nil, fmt.Errorf("cannot decode %T as an integer", decoded)
+	}
+}
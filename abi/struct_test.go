@@ -0,0 +1,116 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type structTestPrice struct {
+	Amount *big.Rat
+	Unit   string `abi:"unit"`
+}
+
+type structTestOrder struct {
+	ID       uint64
+	Owner    [32]byte
+	Active   bool
+	Prices   []structTestPrice
+	Flags    [2]uint8
+	Big      *big.Int
+	internal int
+	Ignored  string `abi:"-"`
+}
+
+func TestMarshalStruct(t *testing.T) {
+	orderType, err := TypeOf("(uint64,address,bool,(ufixed64x2,string)[],byte[2],uint256)")
+	require.NoError(t, err)
+
+	order := structTestOrder{
+		ID:     7,
+		Active: true,
+		Prices: []structTestPrice{
+			{Amount: big.NewRat(1234, 100), Unit: "usd"},
+			{Amount: big.NewRat(1, 2), Unit: "eur"},
+		},
+		Flags:   [2]uint8{1, 2},
+		Big:     new(big.Int).Lsh(big.NewInt(1), 200),
+		Ignored: "not encoded",
+	}
+	order.Owner[0] = 0xff
+
+	encoded, err := Marshal(orderType, order)
+	require.NoError(t, err)
+
+	expected, err := orderType.Encode([]interface{}{
+		uint64(7),
+		order.Owner[:],
+		true,
+		[]interface{}{
+			[]interface{}{uint64(1234), "usd"},
+			[]interface{}{uint64(50), "eur"},
+		},
+		[]interface{}{byte(1), byte(2)},
+		order.Big,
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected, encoded)
+
+	var decoded structTestOrder
+	require.NoError(t, Unmarshal(orderType, encoded, &decoded))
+	order.Ignored = ""
+	require.Equal(t, order.ID, decoded.ID)
+	require.Equal(t, order.Owner, decoded.Owner)
+	require.Equal(t, order.Active, decoded.Active)
+	require.Equal(t, order.Flags, decoded.Flags)
+	require.Zero(t, order.Big.Cmp(decoded.Big))
+	require.Len(t, decoded.Prices, 2)
+	for i, price := range order.Prices {
+		require.Equal(t, price.Unit, decoded.Prices[i].Unit)
+		require.Zero(t, price.Amount.Cmp(decoded.Prices[i].Amount))
+	}
+	require.Empty(t, decoded.Ignored)
+
+	var generic interface{}
+	require.NoError(t, Unmarshal(orderType, encoded, &generic))
+	require.Len(t, generic, 6)
+}
+
+func TestMarshalErrors(t *testing.T) {
+	pairType, err := TypeOf("(uint8,ufixed64x2)")
+	require.NoError(t, err)
+
+	type pair struct {
+		Small  uint64
+		Amount *big.Rat
+	}
+
+	_, err = Marshal(pairType, pair{Small: 1, Amount: big.NewRat(1, 1000)})
+	require.Error(t, err)
+
+	_, err = Marshal(pairType, struct{ A uint64 }{1})
+	require.Error(t, err)
+
+	_, err = Marshal(pairType, pair{Small: 1})
+	require.Error(t, err)
+
+	encoded, err := Marshal(pairType, pair{Small: 200, Amount: big.NewRat(3, 2)})
+	require.NoError(t, err)
+
+	var overflow struct {
+		Small  int8
+		Amount uint64
+	}
+	require.Error(t, Unmarshal(pairType, encoded, &overflow))
+
+	var raw struct {
+		Small  int
+		Amount uint64
+	}
+	require.NoError(t, Unmarshal(pairType, encoded, &raw))
+	require.Equal(t, 200, raw.Small)
+	require.Equal(t, uint64(150), raw.Amount)
+
+	require.Error(t, Unmarshal(pairType, encoded, raw))
+}
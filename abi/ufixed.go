@@ -0,0 +1,146 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode selects how a value with more decimal places than a
+// ufixed<N>x<M> type allows is rounded to M places.
+type RoundingMode int
+
+const (
+	// RoundExact rejects values which would need rounding.
+	RoundExact RoundingMode = iota
+	// RoundDown rounds toward zero, i.e. truncates the extra decimal places.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundHalfUp rounds to the nearest value, and ties away from zero.
+	RoundHalfUp
+	// RoundHalfEven rounds to the nearest value, and ties to the value whose
+	// last decimal place is even, as banks do.
+	RoundHalfEven
+)
+
+// ErrUfixedRounding is returned by RoundExact conversions of values with more
+// decimal places than the type allows.
+var ErrUfixedRounding = errors.New("value cannot be represented without rounding")
+
+// ufixedParams returns the N and M of the ufixed<N>x<M> type t.
+func ufixedParams(t Type) (size int, precision int, err error) {
+	s := t.String()
+	x := strings.LastIndexByte(s, 'x')
+	if !strings.HasPrefix(s, "ufixed") || x < 0 {
+		return 0, 0, fmt.Errorf("%s is not a ufixed type", s)
+	}
+	if size, err = strconv.Atoi(s[len("ufixed"):x]); err != nil {
+		return 0, 0, err
+	}
+	if precision, err = strconv.Atoi(s[x+1:]); err != nil {
+		return 0, 0, err
+	}
+	return size, precision, nil
+}
+
+// UfixedFromRat returns the ABI value of r as the ufixed<N>x<M> type t, i.e. r
+// multiplied by 10^M, rounded to an integer with mode. An error is returned if
+// r is negative or its value does not fit in N bits.
+func UfixedFromRat(t Type, r *big.Rat, mode RoundingMode) (*big.Int, error) {
+	size, precision, err := ufixedParams(t)
+	if err != nil {
+		return nil, err
+	}
+	if r.Sign() < 0 {
+		return nil, fmt.Errorf("%s is negative and cannot be a %s", r.RatString(), t)
+	}
+
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(precision)))
+	value, rem := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		// compare the remainder with half the denominator
+		half := new(big.Int).Lsh(rem, 1).Cmp(scaled.Denom())
+		roundUp := false
+		switch mode {
+		case RoundExact:
+			return nil, fmt.Errorf("%s has more than %d decimal places for %s: %w", r.RatString(), precision, t, ErrUfixedRounding)
+		case RoundDown:
+		case RoundUp:
+			roundUp = true
+		case RoundHalfUp:
+			roundUp = half >= 0
+		case RoundHalfEven:
+			roundUp = half > 0 || half == 0 && value.Bit(0) == 1
+		default:
+			return nil, fmt.Errorf("unknown rounding mode %d", mode)
+		}
+		if roundUp {
+			value.Add(value, big.NewInt(1))
+		}
+	}
+
+	if value.BitLen() > size {
+		return nil, fmt.Errorf("%s overflows %s", r.RatString(), t)
+	}
+	return value, nil
+}
+
+// UfixedToRat returns the number represented by value, an ABI value of the
+// ufixed<N>x<M> type t as returned by Type.Decode, i.e. value divided by 10^M.
+func UfixedToRat(t Type, value interface{}) (*big.Rat, error) {
+	_, precision, err := ufixedParams(t)
+	if err != nil {
+		return nil, err
+	}
+	i, err := decodedBigInt(value)
+	if err != nil {
+		return nil, err
+	}
+	if i.Sign() < 0 {
+		return nil, fmt.Errorf("%s is negative and cannot be a %s", i, t)
+	}
+	return new(big.Rat).SetFrac(i, pow10(precision)), nil
+}
+
+// ParseUfixed returns the ABI value of the decimal string s, e.g. "12.345", as
+// the ufixed<N>x<M> type t, rounded to M decimal places with mode.
+func ParseUfixed(t Type, s string, mode RoundingMode) (*big.Int, error) {
+	intPart, fracPart := s, ""
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		intPart, fracPart = s[:dot], s[dot+1:]
+	}
+	if intPart == "" && fracPart == "" || !isDecimalDigits(intPart) || !isDecimalDigits(fracPart) {
+		return nil, fmt.Errorf("invalid decimal %q", s)
+	}
+	r, ok := new(big.Rat).SetString("0" + intPart + "." + fracPart + "0")
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal %q", s)
+	}
+	return UfixedFromRat(t, r, mode)
+}
+
+// FormatUfixed returns value, an ABI value of the ufixed<N>x<M> type t, as a
+// decimal string with exactly M decimal places.
+func FormatUfixed(t Type, value interface{}) (string, error) {
+	_, precision, err := ufixedParams(t)
+	if err != nil {
+		return "", err
+	}
+	r, err := UfixedToRat(t, value)
+	if err != nil {
+		return "", err
+	}
+	return r.FloatString(precision), nil
+}
+
+func isDecimalDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUfixedFromRat(t *testing.T) {
+	ufixed, err := TypeOf("ufixed16x1")
+	require.NoError(t, err)
+
+	tests := []struct {
+		value    string
+		mode     RoundingMode
+		expected int64
+	}{
+		{"1.25", RoundDown, 12},
+		{"1.25", RoundUp, 13},
+		{"1.25", RoundHalfUp, 13},
+		{"1.25", RoundHalfEven, 12},
+		{"1.35", RoundHalfEven, 14},
+		{"1.26", RoundHalfEven, 13},
+		{"1.24", RoundHalfUp, 12},
+		{"1.2", RoundExact, 12},
+		{"0.01", RoundUp, 1},
+	}
+	for _, test := range tests {
+		r, ok := new(big.Rat).SetString(test.value)
+		require.True(t, ok)
+		value, err := UfixedFromRat(ufixed, r, test.mode)
+		require.NoError(t, err, test.value)
+		require.Equal(t, big.NewInt(test.expected), value, "%s rounded with mode %d", test.value, test.mode)
+	}
+
+	_, err = UfixedFromRat(ufixed, big.NewRat(5, 4), RoundExact)
+	require.ErrorIs(t, err, ErrUfixedRounding)
+	_, err = UfixedFromRat(ufixed, big.NewRat(-1, 1), RoundDown)
+	require.Error(t, err)
+	_, err = UfixedFromRat(ufixed, big.NewRat(65536, 10), RoundExact)
+	require.Error(t, err)
+	_, err = UfixedFromRat(ufixed, big.NewRat(65535, 10), RoundExact)
+	require.NoError(t, err)
+
+	uint64Type, err := TypeOf("uint64")
+	require.NoError(t, err)
+	_, err = UfixedFromRat(uint64Type, big.NewRat(1, 1), RoundExact)
+	require.Error(t, err)
+}
+
+func TestParseAndFormatUfixed(t *testing.T) {
+	ufixed, err := TypeOf("ufixed64x4")
+	require.NoError(t, err)
+
+	value, err := ParseUfixed(ufixed, "12.3456", RoundExact)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(123456), value)
+
+	value, err = ParseUfixed(ufixed, "12.34565", RoundHalfEven)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(123456), value)
+
+	value, err = ParseUfixed(ufixed, ".5", RoundExact)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(5000), value)
+
+	for _, invalid := range []string{"", ".", "-1", "1e3", "1/2", "1.2.3", " 1"} {
+		_, err = ParseUfixed(ufixed, invalid, RoundDown)
+		require.Error(t, err, invalid)
+	}
+
+	encoded, err := ufixed.Encode(uint64(123456))
+	require.NoError(t, err)
+	decoded, err := ufixed.Decode(encoded)
+	require.NoError(t, err)
+	formatted, err := FormatUfixed(ufixed, decoded)
+	require.NoError(t, err)
+	require.Equal(t, "12.3456", formatted)
+
+	r, err := UfixedToRat(ufixed, decoded)
+	require.NoError(t, err)
+	require.Equal(t, "7716/625", r.RatString())
+}
@@ -0,0 +1,213 @@
+// Package accounthistory walks the full transaction history of an account
+// through the indexer and converts it into normalized ledger entries, one per
+// change to the account's balance, suitable for accounting export.
+package accounthistory
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/indexer"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// Kind is the kind of balance change recorded by an Entry.
+type Kind string
+
+const (
+	// KindTransfer a payment or asset transfer.
+	KindTransfer Kind = "transfer"
+	// KindClose the remaining balance sent when an account or asset holding
+	// is closed.
+	KindClose Kind = "close"
+	// KindFee a transaction fee paid by the account.
+	KindFee Kind = "fee"
+	// KindReward participation rewards credited to the account.
+	KindReward Kind = "reward"
+)
+
+// Direction is whether an Entry credits or debits the account.
+type Direction string
+
+const (
+	// In the account received the amount.
+	In Direction = "in"
+	// Out the account sent the amount.
+	Out Direction = "out"
+)
+
+// Entry is a single change to the balance of one asset of an account.
+type Entry struct {
+	Round uint64
+	Time  time.Time
+
+	// TxID the id of the top level transaction, shared by the entries of its
+	// inner transactions.
+	TxID string
+
+	// Inner whether the entry comes from an inner transaction.
+	Inner bool
+
+	// Type the transaction type, e.g. pay or axfer.
+	Type string
+
+	Kind      Kind
+	Direction Direction
+
+	// AssetID the asset whose balance changed, 0 for Algos.
+	AssetID uint64
+	Amount  uint64
+
+	// Counterparty the other account of a transfer or close, empty for fees
+	// and rewards.
+	Counterparty string
+}
+
+// Options configures Walk.
+type Options struct {
+	// MinRound include transactions at or after this round.
+	MinRound uint64
+
+	// MaxRound include transactions at or before this round, zero for no limit.
+	MaxRound uint64
+
+	// PageSize the number of transactions fetched per request, zero for the
+	// indexer's default.
+	PageSize uint64
+}
+
+// Walk calls fn with the entries of every transaction of the account, in the
+// order returned by the indexer. Inner transactions are included, and an error
+// returned by fn stops the walk.
+func Walk(ctx context.Context, client *indexer.Client, address string, opts Options, fn func(Entry) error) error {
+	lookup := client.LookupAccountTransactions(address).MinRound(opts.MinRound)
+	if opts.MaxRound != 0 {
+		lookup.MaxRound(opts.MaxRound)
+	}
+	if opts.PageSize != 0 {
+		lookup.Limit(opts.PageSize)
+	}
+
+	it := lookup.Iterate(ctx)
+	for it.Next() {
+		for _, entry := range Entries(address, it.Transaction()) {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Err()
+}
+
+// Entries returns the entries of a transaction, including its inner
+// transactions, which change the balance of the account.
+func Entries(address string, txn models.Transaction) []Entry {
+	var entries []Entry
+	appendEntries(&entries, address, txn, txn.Id, false)
+	return entries
+}
+
+func appendEntries(entries *[]Entry, address string, txn models.Transaction, txid string, inner bool) {
+	add := func(kind Kind, direction Direction, assetID, amount uint64, counterparty string) {
+		if amount == 0 {
+			return
+		}
+		*entries = append(*entries, Entry{
+			Round:        txn.ConfirmedRound,
+			Time:         time.Unix(int64(txn.RoundTime), 0).UTC(),
+			TxID:         txid,
+			Inner:        inner,
+			Type:         txn.Type,
+			Kind:         kind,
+			Direction:    direction,
+			AssetID:      assetID,
+			Amount:       amount,
+			Counterparty: counterparty,
+		})
+	}
+
+	sender := txn.Sender == address
+	if sender {
+		add(KindFee, Out, 0, txn.Fee, "")
+		add(KindReward, In, 0, txn.SenderRewards, "")
+	}
+
+	switch types.TxType(txn.Type) {
+	case types.PaymentTx:
+		pay := txn.PaymentTransaction
+		if sender {
+			add(KindTransfer, Out, 0, pay.Amount, pay.Receiver)
+			add(KindClose, Out, 0, txn.ClosingAmount, pay.CloseRemainderTo)
+		}
+		if pay.Receiver == address {
+			add(KindTransfer, In, 0, pay.Amount, txn.Sender)
+			add(KindReward, In, 0, txn.ReceiverRewards, "")
+		}
+		if pay.CloseRemainderTo != "" && pay.CloseRemainderTo == address {
+			add(KindClose, In, 0, txn.ClosingAmount, txn.Sender)
+			add(KindReward, In, 0, txn.CloseRewards, "")
+		}
+	case types.AssetTransferTx:
+		axfer := txn.AssetTransferTransaction
+		// clawbacks move the asset out of the revocation target rather than
+		// the sender
+		source := txn.Sender
+		if axfer.Sender != "" {
+			source = axfer.Sender
+		}
+		if source == address {
+			add(KindTransfer, Out, axfer.AssetId, axfer.Amount, axfer.Receiver)
+			add(KindClose, Out, axfer.AssetId, axfer.CloseAmount, axfer.CloseTo)
+		}
+		if axfer.Receiver == address {
+			add(KindTransfer, In, axfer.AssetId, axfer.Amount, source)
+		}
+		if axfer.CloseTo != "" && axfer.CloseTo == address {
+			add(KindClose, In, axfer.AssetId, axfer.CloseAmount, source)
+		}
+	}
+
+	for _, innerTxn := range txn.InnerTxns {
+		appendEntries(entries, address, innerTxn, txid, true)
+	}
+}
+
+// CSVHeader is the header row written by WriteCSV.
+var CSVHeader = []string{"round", "time", "txid", "inner", "type", "kind", "direction", "asset_id", "amount", "counterparty"}
+
+// Record returns the entry as a CSV row matching CSVHeader.
+func (e Entry) Record() []string {
+	return []string{
+		strconv.FormatUint(e.Round, 10),
+		e.Time.Format(time.RFC3339),
+		e.TxID,
+		strconv.FormatBool(e.Inner),
+		e.Type,
+		string(e.Kind),
+		string(e.Direction),
+		strconv.FormatUint(e.AssetID, 10),
+		strconv.FormatUint(e.Amount, 10),
+		e.Counterparty,
+	}
+}
+
+// WriteCSV walks the history of the account and writes its entries to w as
+// CSV, preceded by CSVHeader.
+func WriteCSV(ctx context.Context, client *indexer.Client, address string, opts Options, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(CSVHeader); err != nil {
+		return err
+	}
+	err := Walk(ctx, client, address, opts, func(entry Entry) error {
+		return writer.Write(entry.Record())
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
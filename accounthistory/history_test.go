@@ -0,0 +1,143 @@
+package accounthistory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/indexer"
+)
+
+const (
+	alice = "ALICE"
+	bob   = "BOB"
+	carol = "CAROL"
+)
+
+func TestEntriesPayment(t *testing.T) {
+	txn := models.Transaction{
+		Id:             "PAY",
+		Type:           "pay",
+		Sender:         alice,
+		Fee:            1000,
+		ConfirmedRound: 10,
+		RoundTime:      1700000000,
+		SenderRewards:  5,
+		ClosingAmount:  300,
+		PaymentTransaction: models.TransactionPayment{
+			Amount:           2000,
+			Receiver:         bob,
+			CloseRemainderTo: carol,
+		},
+	}
+
+	entries := Entries(alice, txn)
+	require.Len(t, entries, 4)
+	require.Equal(t, Entry{Kind: KindFee, Direction: Out, Amount: 1000}, strip(entries[0]))
+	require.Equal(t, Entry{Kind: KindReward, Direction: In, Amount: 5}, strip(entries[1]))
+	require.Equal(t, Entry{Kind: KindTransfer, Direction: Out, Amount: 2000, Counterparty: bob}, strip(entries[2]))
+	require.Equal(t, Entry{Kind: KindClose, Direction: Out, Amount: 300, Counterparty: carol}, strip(entries[3]))
+	require.Equal(t, uint64(10), entries[0].Round)
+	require.Equal(t, "PAY", entries[0].TxID)
+	require.Equal(t, int64(1700000000), entries[0].Time.Unix())
+
+	entries = Entries(bob, txn)
+	require.Equal(t, []Entry{{Kind: KindTransfer, Direction: In, Amount: 2000, Counterparty: alice}}, stripAll(entries))
+
+	entries = Entries(carol, txn)
+	require.Equal(t, []Entry{{Kind: KindClose, Direction: In, Amount: 300, Counterparty: alice}}, stripAll(entries))
+}
+
+func TestEntriesInnerAssetTransfer(t *testing.T) {
+	txn := models.Transaction{
+		Id:     "APPL",
+		Type:   "appl",
+		Sender: alice,
+		Fee:    2000,
+		InnerTxns: []models.Transaction{{
+			Type:   "axfer",
+			Sender: bob,
+			AssetTransferTransaction: models.TransactionAssetTransfer{
+				AssetId:  7,
+				Amount:   50,
+				Receiver: alice,
+			},
+		}, {
+			// clawback from carol
+			Type:   "axfer",
+			Sender: bob,
+			AssetTransferTransaction: models.TransactionAssetTransfer{
+				AssetId:  7,
+				Amount:   20,
+				Sender:   carol,
+				Receiver: bob,
+			},
+		}},
+	}
+
+	entries := Entries(alice, txn)
+	require.Equal(t, []Entry{
+		{Kind: KindFee, Direction: Out, Amount: 2000},
+		{Kind: KindTransfer, Direction: In, AssetID: 7, Amount: 50, Counterparty: bob},
+	}, stripAll(entries))
+	require.False(t, entries[0].Inner)
+	require.True(t, entries[1].Inner)
+	require.Equal(t, "APPL", entries[1].TxID)
+
+	entries = Entries(carol, txn)
+	require.Equal(t, []Entry{{Kind: KindTransfer, Direction: Out, AssetID: 7, Amount: 20, Counterparty: bob}}, stripAll(entries))
+}
+
+func TestWriteCSV(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/accounts/"+alice+"/transactions", r.URL.Path)
+		response := models.TransactionsResponse{}
+		switch r.URL.Query().Get("next") {
+		case "":
+			response.NextToken = "page2"
+			response.Transactions = []models.Transaction{{
+				Id: "A", Type: "pay", Sender: bob, ConfirmedRound: 1,
+				PaymentTransaction: models.TransactionPayment{Amount: 10, Receiver: alice},
+			}}
+		case "page2":
+			response.Transactions = []models.Transaction{{
+				Id: "B", Type: "pay", Sender: alice, Fee: 1, ConfirmedRound: 2,
+				PaymentTransaction: models.TransactionPayment{Receiver: alice},
+			}}
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, err := indexer.MakeClient(server.URL, "")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(context.Background(), client, alice, Options{}, &buf))
+	require.Equal(t, strings.Join([]string{
+		"round,time,txid,inner,type,kind,direction,asset_id,amount,counterparty",
+		"1,1970-01-01T00:00:00Z,A,false,pay,transfer,in,0,10,BOB",
+		"2,1970-01-01T00:00:00Z,B,false,pay,fee,out,0,1,",
+		"",
+	}, "\n"), buf.String())
+}
+
+// strip keeps only the balance change fields of an entry.
+func strip(e Entry) Entry {
+	return Entry{Kind: e.Kind, Direction: e.Direction, AssetID: e.AssetID, Amount: e.Amount, Counterparty: e.Counterparty}
+}
+
+func stripAll(entries []Entry) []Entry {
+	var stripped []Entry
+	for _, e := range entries {
+		stripped = append(stripped, strip(e))
+	}
+	return stripped
+}
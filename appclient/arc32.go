@@ -0,0 +1,274 @@
+package appclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+)
+
+// arc32Actions maps the OnComplete actions of ARC-32 call configs to their
+// ARC-56 names.
+var arc32Actions = map[string]string{
+	"no_op":              "NoOp",
+	"opt_in":             "OptIn",
+	"close_out":          "CloseOut",
+	"update_application": "UpdateApplication",
+	"delete_application": "DeleteApplication",
+}
+
+// arc32Spec is an ARC-32 application specification.
+type arc32Spec struct {
+	Hints          map[string]arc32Hint `json:"hints"`
+	Source         *abi.Arc56Programs   `json:"source"`
+	State          arc32State           `json:"state"`
+	Schema         arc32Schema          `json:"schema"`
+	Contract       json.RawMessage      `json:"contract"`
+	BareCallConfig map[string]string    `json:"bare_call_config"`
+}
+
+type arc32Hint struct {
+	Structs          map[string]arc32Struct          `json:"structs"`
+	ReadOnly         bool                            `json:"read_only"`
+	DefaultArguments map[string]arc32DefaultArgument `json:"default_arguments"`
+	CallConfig       map[string]string               `json:"call_config"`
+}
+
+type arc32Struct struct {
+	Name     string      `json:"name"`
+	Elements [][2]string `json:"elements"`
+}
+
+type arc32DefaultArgument struct {
+	Source string          `json:"source"`
+	Data   json.RawMessage `json:"data"`
+}
+
+type arc32State struct {
+	Global arc32StateSize `json:"global"`
+	Local  arc32StateSize `json:"local"`
+}
+
+type arc32StateSize struct {
+	NumByteSlices uint64 `json:"num_byte_slices"`
+	NumUints      uint64 `json:"num_uints"`
+}
+
+type arc32Schema struct {
+	Global arc32StateSchema `json:"global"`
+	Local  arc32StateSchema `json:"local"`
+}
+
+type arc32StateSchema struct {
+	Declared map[string]arc32DeclaredState `json:"declared"`
+}
+
+type arc32DeclaredState struct {
+	Type  string `json:"type"`
+	Key   string `json:"key"`
+	Descr string `json:"descr"`
+}
+
+// ParseArc32 parses an ARC-32 application specification into the equivalent
+// ARC-56 specification: the actions, structs, read only flags and default
+// arguments of its hints are set on the methods, the TEAL source of its
+// programs becomes the source of the specification, and its declared state
+// becomes the global and local keys.
+func ParseArc32(data []byte) (abi.Arc56Contract, error) {
+	var spec arc32Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return abi.Arc56Contract{}, err
+	}
+	if len(spec.Contract) == 0 {
+		return abi.Arc56Contract{}, fmt.Errorf("ARC-32 specification has no contract")
+	}
+	contract, err := abi.ParseContract(spec.Contract)
+	if err != nil {
+		return abi.Arc56Contract{}, err
+	}
+
+	c := abi.Arc56Contract{
+		Arcs:     []uint64{4, 32},
+		Name:     contract.Name,
+		Desc:     contract.Desc,
+		Networks: contract.Networks,
+		Structs:  make(map[string][]abi.Arc56StructField),
+		Methods:  make([]abi.Arc56Method, len(contract.Methods)),
+		Source:   spec.Source,
+		Events:   contract.Events,
+	}
+	c.BareActions, err = arc32CallConfig(spec.BareCallConfig)
+	if err != nil {
+		return abi.Arc56Contract{}, fmt.Errorf("bare call config: %w", err)
+	}
+
+	for i, method := range contract.Methods {
+		m := abi.Arc56Method{
+			Name:     method.Name,
+			Desc:     method.Desc,
+			Args:     make([]abi.Arc56MethodArg, len(method.Args)),
+			Returns:  abi.Arc56Return{Type: method.Returns.Type, Desc: method.Returns.Desc},
+			ReadOnly: method.ReadOnly,
+			Events:   method.Events,
+		}
+		for j, arg := range method.Args {
+			m.Args[j] = abi.Arc56MethodArg{Type: arg.Type, Name: arg.Name, Desc: arg.Desc}
+		}
+
+		sig := method.GetSignature()
+		hint, ok := spec.Hints[sig]
+		if !ok {
+			m.Actions.Call = []string{"NoOp"}
+			c.Methods[i] = m
+			continue
+		}
+		if m.Actions, err = arc32CallConfig(hint.CallConfig); err != nil {
+			return abi.Arc56Contract{}, fmt.Errorf("method %s: %w", sig, err)
+		}
+		m.ReadOnly = m.ReadOnly || hint.ReadOnly
+		for argName, s := range hint.Structs {
+			fields := make([]abi.Arc56StructField, len(s.Elements))
+			for k, element := range s.Elements {
+				fields[k] = abi.Arc56StructField{Name: element[0], Type: element[1]}
+			}
+			c.Structs[s.Name] = fields
+			if argName == "output" {
+				m.Returns.Struct = s.Name
+			}
+			for j := range m.Args {
+				if m.Args[j].Name == argName {
+					m.Args[j].Struct = s.Name
+				}
+			}
+		}
+		for j := range m.Args {
+			if def, ok := hint.DefaultArguments[m.Args[j].Name]; ok {
+				if m.Args[j].DefaultValue, err = arc32DefaultValue(m.Args[j].Type, def); err != nil {
+					return abi.Arc56Contract{}, fmt.Errorf("default argument %s of method %s: %w", m.Args[j].Name, sig, err)
+				}
+			}
+		}
+		c.Methods[i] = m
+	}
+
+	c.State.Schema = abi.Arc56Schema{
+		Global: abi.Arc56SchemaSize{Ints: spec.State.Global.NumUints, Bytes: spec.State.Global.NumByteSlices},
+		Local:  abi.Arc56SchemaSize{Ints: spec.State.Local.NumUints, Bytes: spec.State.Local.NumByteSlices},
+	}
+	c.State.Keys.Global = arc32Keys(spec.Schema.Global.Declared)
+	c.State.Keys.Local = arc32Keys(spec.Schema.Local.Declared)
+	c.State.Keys.Box = map[string]abi.Arc56StorageKey{}
+
+	for name := range c.Structs {
+		if _, err := c.StructType(name); err != nil {
+			return abi.Arc56Contract{}, err
+		}
+	}
+	return c, nil
+}
+
+// arc32CallConfig returns the ARC-56 actions of an ARC-32 call config, in
+// which each OnComplete action is "CALL", "CREATE", "ALL" or "NEVER".
+func arc32CallConfig(config map[string]string) (abi.Arc56Actions, error) {
+	actions := abi.Arc56Actions{Create: []string{}, Call: []string{}}
+	for name, when := range config {
+		action, ok := arc32Actions[name]
+		if !ok {
+			return abi.Arc56Actions{}, fmt.Errorf("unknown OnComplete action %s", name)
+		}
+		switch when {
+		case "CALL":
+			actions.Call = append(actions.Call, action)
+		case "CREATE":
+			actions.Create = append(actions.Create, action)
+		case "ALL":
+			actions.Call = append(actions.Call, action)
+			actions.Create = append(actions.Create, action)
+		case "NEVER":
+		default:
+			return abi.Arc56Actions{}, fmt.Errorf("unknown call config %s of %s", when, name)
+		}
+	}
+	sort.Strings(actions.Create)
+	sort.Strings(actions.Call)
+	return actions, nil
+}
+
+// arc32DefaultValue returns the ARC-56 default value of an ARC-32 default
+// argument of the type typeStr.
+func arc32DefaultValue(typeStr string, def arc32DefaultArgument) (*abi.Arc56DefaultValue, error) {
+	switch def.Source {
+	case "constant":
+		abiType, err := abi.TypeOf(typeStr)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if strings.HasPrefix(typeStr, "uint") {
+			var i uint64
+			if err := json.Unmarshal(def.Data, &i); err != nil {
+				return nil, err
+			}
+			value = i
+		} else if err := json.Unmarshal(def.Data, &value); err != nil {
+			return nil, err
+		}
+		encoded, err := abiType.Encode(value)
+		if err != nil {
+			return nil, err
+		}
+		return &abi.Arc56DefaultValue{Source: "literal", Data: base64.StdEncoding.EncodeToString(encoded)}, nil
+	case "global-state", "local-state":
+		var key string
+		if err := json.Unmarshal(def.Data, &key); err != nil {
+			return nil, err
+		}
+		source := strings.TrimSuffix(def.Source, "-state")
+		return &abi.Arc56DefaultValue{Source: source, Data: base64.StdEncoding.EncodeToString([]byte(key))}, nil
+	case "abi-method":
+		var method abi.Method
+		if err := json.Unmarshal(def.Data, &method); err != nil {
+			return nil, err
+		}
+		return &abi.Arc56DefaultValue{Source: "method", Data: method.GetSignature()}, nil
+	}
+	return nil, fmt.Errorf("unknown source %s", def.Source)
+}
+
+// arc32Keys returns the ARC-56 keys of the declared state of an ARC-32
+// schema, whose keys are strings and values are "uint64" or "bytes".
+func arc32Keys(declared map[string]arc32DeclaredState) map[string]abi.Arc56StorageKey {
+	keys := make(map[string]abi.Arc56StorageKey, len(declared))
+	for name, state := range declared {
+		valueType := "AVMBytes"
+		if state.Type == "uint64" {
+			valueType = "AVMUint64"
+		}
+		keys[name] = abi.Arc56StorageKey{
+			Desc:      state.Descr,
+			KeyType:   "AVMString",
+			ValueType: valueType,
+			Key:       base64.StdEncoding.EncodeToString([]byte(state.Key)),
+		}
+	}
+	return keys
+}
+
+// ParseSpec parses an ARC-56 application specification, or an ARC-32 one
+// converted with ParseArc32.
+func ParseSpec(data []byte) (abi.Arc56Contract, error) {
+	var probe struct {
+		Arcs     []uint64        `json:"arcs"`
+		Contract json.RawMessage `json:"contract"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return abi.Arc56Contract{}, err
+	}
+	if probe.Arcs == nil && len(probe.Contract) != 0 {
+		return ParseArc32(data)
+	}
+	return abi.ParseArc56(data)
+}
@@ -0,0 +1,137 @@
+package appclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+)
+
+var (
+	approvalSource = base64.StdEncoding.EncodeToString([]byte("#pragma version 8\nint TMPL_ANSWER\nreturn\n"))
+	clearSource    = base64.StdEncoding.EncodeToString([]byte("#pragma version 8\nint 1\n"))
+)
+
+var counterArc32 = []byte(`{
+	"hints": {
+		"create(string,uint64)void": {
+			"call_config": {"no_op": "CREATE"},
+			"default_arguments": {"start": {"source": "constant", "data": 5}}
+		},
+		"add(uint64,uint64)uint64": {
+			"call_config": {"no_op": "CALL"}
+		},
+		"get_pair()(uint64,uint64)": {
+			"read_only": true,
+			"structs": {"output": {"name": "Pair", "elements": [["a", "uint64"], ["b", "uint64"]]}},
+			"call_config": {"no_op": "CALL"}
+		},
+		"set(uint64)void": {
+			"call_config": {"no_op": "ALL", "opt_in": "CALL"},
+			"default_arguments": {"value": {"source": "global-state", "data": "counter"}}
+		},
+		"update()void": {
+			"call_config": {"update_application": "CALL"}
+		}
+	},
+	"source": {"approval": "` + approvalSource + `", "clear": "` + clearSource + `"},
+	"state": {
+		"global": {"num_byte_slices": 1, "num_uints": 1},
+		"local": {"num_byte_slices": 0, "num_uints": 1}
+	},
+	"schema": {
+		"global": {"declared": {
+			"counter": {"type": "uint64", "key": "counter", "descr": "The counter"},
+			"name": {"type": "bytes", "key": "name"}
+		}},
+		"local": {"declared": {
+			"balance": {"type": "uint64", "key": "bal"}
+		}}
+	},
+	"contract": {
+		"name": "Counter",
+		"methods": [
+			{"name": "create", "args": [{"type": "string", "name": "name"}, {"type": "uint64", "name": "start"}], "returns": {"type": "void"}},
+			{"name": "add", "args": [{"type": "uint64", "name": "a"}, {"type": "uint64", "name": "b"}], "returns": {"type": "uint64"}},
+			{"name": "get_pair", "args": [], "returns": {"type": "(uint64,uint64)"}},
+			{"name": "set", "args": [{"type": "uint64", "name": "value"}], "returns": {"type": "void"}},
+			{"name": "update", "args": [], "returns": {"type": "void"}},
+			{"name": "hello", "args": [], "returns": {"type": "string"}}
+		]
+	},
+	"bare_call_config": {"no_op": "CREATE", "opt_in": "CALL", "update_application": "CALL", "delete_application": "NEVER"}
+}`)
+
+func TestParseArc32(t *testing.T) {
+	spec, err := ParseArc32(counterArc32)
+	require.NoError(t, err)
+	require.Equal(t, "Counter", spec.Name)
+	require.Equal(t, []uint64{4, 32}, spec.Arcs)
+	require.Equal(t, abi.Arc56Actions{Create: []string{"NoOp"}, Call: []string{"OptIn", "UpdateApplication"}}, spec.BareActions)
+	require.Equal(t, approvalSource, spec.Source.Approval)
+
+	create, err := spec.GetMethodByNameOrSignature("create")
+	require.NoError(t, err)
+	require.Equal(t, abi.Arc56Actions{Create: []string{"NoOp"}, Call: []string{}}, create.Actions)
+	encoded, err := abi.Marshal(mustType(t, "uint64"), uint64(5))
+	require.NoError(t, err)
+	require.Equal(t, &abi.Arc56DefaultValue{Source: "literal", Data: base64.StdEncoding.EncodeToString(encoded)}, create.Args[1].DefaultValue)
+	require.Nil(t, create.Args[0].DefaultValue)
+
+	getPair, err := spec.GetMethodByNameOrSignature("get_pair")
+	require.NoError(t, err)
+	require.True(t, getPair.ReadOnly)
+	require.Equal(t, "Pair", getPair.Returns.Struct)
+	require.Equal(t, []abi.Arc56StructField{{Name: "a", Type: "uint64"}, {Name: "b", Type: "uint64"}}, spec.Structs["Pair"])
+
+	set, err := spec.GetMethodByNameOrSignature("set")
+	require.NoError(t, err)
+	require.Equal(t, abi.Arc56Actions{Create: []string{"NoOp"}, Call: []string{"NoOp", "OptIn"}}, set.Actions)
+	require.Equal(t, &abi.Arc56DefaultValue{Source: "global", Data: base64.StdEncoding.EncodeToString([]byte("counter"))}, set.Args[0].DefaultValue)
+
+	hello, err := spec.GetMethodByNameOrSignature("hello")
+	require.NoError(t, err)
+	require.Equal(t, []string{"NoOp"}, hello.Actions.Call)
+
+	require.Equal(t, abi.Arc56SchemaSize{Ints: 1, Bytes: 1}, spec.State.Schema.Global)
+	require.Equal(t, abi.Arc56SchemaSize{Ints: 1}, spec.State.Schema.Local)
+	require.Equal(t, abi.Arc56StorageKey{
+		Desc:      "The counter",
+		KeyType:   "AVMString",
+		ValueType: "AVMUint64",
+		Key:       base64.StdEncoding.EncodeToString([]byte("counter")),
+	}, spec.State.Keys.Global["counter"])
+	require.Equal(t, "AVMBytes", spec.State.Keys.Global["name"].ValueType)
+	require.Equal(t, base64.StdEncoding.EncodeToString([]byte("bal")), spec.State.Keys.Local["balance"].Key)
+
+	_, err = ParseArc32([]byte(`{"hints": {}}`))
+	require.Error(t, err)
+	_, err = ParseArc32([]byte(`{"contract": {"name": "c", "methods": []}, "bare_call_config": {"no_op": "SOMETIMES"}}`))
+	require.Error(t, err)
+}
+
+func TestParseSpec(t *testing.T) {
+	spec, err := ParseSpec(counterArc32)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{4, 32}, spec.Arcs)
+
+	arc56, err := json.Marshal(spec)
+	require.NoError(t, err)
+	parsed, err := ParseSpec(arc56)
+	require.NoError(t, err)
+	require.Equal(t, spec.Name, parsed.Name)
+	require.Equal(t, spec.BareActions, parsed.BareActions)
+	require.Len(t, parsed.Methods, len(spec.Methods))
+
+	_, err = ParseSpec([]byte(`not json`))
+	require.Error(t, err)
+}
+
+func mustType(t *testing.T, typeStr string) abi.Type {
+	abiType, err := abi.TypeOf(typeStr)
+	require.NoError(t, err)
+	return abiType
+}
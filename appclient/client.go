@@ -0,0 +1,456 @@
+// Package appclient calls a deployed app, or deploys it, from its ARC-56 or
+// ARC-32 application specification. A Client looks methods up by name or
+// signature, creates and updates the app with the programs of the
+// specification, submits its calls through an AtomicTransactionComposer,
+// simulates the calls of read only methods, decodes return values into Go
+// values and reads the state the specification declares.
+package appclient
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/logic"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// DefaultWaitRounds is the number of rounds a Client waits for its calls to
+// be confirmed when its WaitRounds is 0.
+const DefaultWaitRounds = 4
+
+// actionNames are the ARC-56 names of OnComplete actions.
+var actionNames = map[types.OnCompletion]string{
+	types.NoOpOC:              "NoOp",
+	types.OptInOC:             "OptIn",
+	types.CloseOutOC:          "CloseOut",
+	types.UpdateApplicationOC: "UpdateApplication",
+	types.DeleteApplicationOC: "DeleteApplication",
+}
+
+// Client calls the app described by an application specification through an
+// algod client.
+type Client struct {
+	// The specification of the app
+	Spec abi.Arc56Contract
+	// The ID of the app, or 0 until Create creates it
+	AppID uint64
+	// The sender of the calls
+	Sender types.Address
+	// A transaction Signer that can authorize the calls from Sender
+	Signer transaction.TransactionSigner
+	// The algod client the calls are made through
+	Algod *algod.Client
+	// The values of the template variables of the TEAL source of the
+	// specification, used when it has no bytecode
+	TemplateValues map[string]interface{}
+	// The number of rounds to wait for calls to be confirmed, or 0 for
+	// DefaultWaitRounds
+	WaitRounds uint64
+}
+
+// New returns a Client calling the app appID described by spec from sender
+// through algodClient. appID is 0 for an app to create with Create.
+func New(spec abi.Arc56Contract, algodClient *algod.Client, appID uint64, sender types.Address, signer transaction.TransactionSigner) *Client {
+	return &Client{Spec: spec, AppID: appID, Sender: sender, Signer: signer, Algod: algodClient}
+}
+
+// NewFromSpec returns a Client for the app described by the ARC-56 or ARC-32
+// specification data, see New and ParseSpec.
+func NewFromSpec(data []byte, algodClient *algod.Client, appID uint64, sender types.Address, signer transaction.TransactionSigner) (*Client, error) {
+	spec, err := ParseSpec(data)
+	if err != nil {
+		return nil, err
+	}
+	return New(spec, algodClient, appID, sender, signer), nil
+}
+
+// Programs returns the approval and clear programs of the specification: its
+// bytecode, or else its TEAL source assembled offline with the values of
+// TemplateValues substituted for the template variables of each program.
+func (c *Client) Programs() (approval []byte, clear []byte, err error) {
+	if c.Spec.ByteCode != nil {
+		return c.Spec.ByteCode.Decode()
+	}
+	if c.Spec.Source == nil {
+		return nil, nil, fmt.Errorf("the specification of %s has no bytecode or source", c.Spec.Name)
+	}
+	approvalSource, clearSource, err := c.Spec.Source.Decode()
+	if err != nil {
+		return nil, nil, err
+	}
+	approvalTemplate, err := logic.AssembleTemplate(string(approvalSource))
+	if err != nil {
+		return nil, nil, fmt.Errorf("approval program: %w", err)
+	}
+	clearTemplate, err := logic.AssembleTemplate(string(clearSource))
+	if err != nil {
+		return nil, nil, fmt.Errorf("clear program: %w", err)
+	}
+
+	approvalVars := templateVars(approvalTemplate, c.TemplateValues)
+	clearVars := templateVars(clearTemplate, c.TemplateValues)
+	for name := range c.TemplateValues {
+		_, inApproval := approvalVars[name]
+		_, inClear := clearVars[name]
+		if !inApproval && !inClear {
+			return nil, nil, fmt.Errorf("unknown template variable %s", name)
+		}
+	}
+	if approval, err = approvalTemplate.Program(approvalVars); err != nil {
+		return nil, nil, fmt.Errorf("approval program: %w", err)
+	}
+	if clear, err = clearTemplate.Program(clearVars); err != nil {
+		return nil, nil, fmt.Errorf("clear program: %w", err)
+	}
+	return approval, clear, nil
+}
+
+// templateVars returns the values of values for the template variables of
+// template, keyed as in values.
+func templateVars(template *logic.Template, values map[string]interface{}) map[string]interface{} {
+	vars := make(map[string]interface{})
+	for _, name := range template.Variables() {
+		for _, key := range []string{name, strings.TrimPrefix(name, "TMPL_")} {
+			if value, ok := values[key]; ok {
+				vars[key] = value
+			}
+		}
+	}
+	return vars
+}
+
+// appClient returns the transaction.AppClient adding the method calls of c,
+// with the programs of the specification when creating or updating the app.
+func (c *Client) appClient(onComplete types.OnCompletion) (*transaction.AppClient, error) {
+	spec := c.Spec
+	if spec.ByteCode == nil && (c.AppID == 0 || onComplete == types.UpdateApplicationOC) {
+		approval, clear, err := c.Programs()
+		if err != nil {
+			return nil, err
+		}
+		spec.ByteCode = &abi.Arc56Programs{
+			Approval: base64.StdEncoding.EncodeToString(approval),
+			Clear:    base64.StdEncoding.EncodeToString(clear),
+		}
+	}
+	return transaction.NewAppClient(spec, c.AppID, c.Sender, c.Signer), nil
+}
+
+// AddCall adds a call to the method with the name or signature method to atc,
+// or a bare call when method is empty, with OnComplete onComplete. Arguments
+// are given as taken by transaction.AppClient.AddMethodCall. When AppID is 0,
+// the call creates the app, and with UpdateApplication, it updates the app to
+// the programs of the specification.
+func (c *Client) AddCall(atc *transaction.AtomicTransactionComposer, method string, args []interface{}, onComplete types.OnCompletion, sp types.SuggestedParams) error {
+	if method != "" {
+		app, err := c.appClient(onComplete)
+		if err != nil {
+			return err
+		}
+		return app.AddMethodCall(atc, method, args, onComplete, sp)
+	}
+	if len(args) != 0 {
+		return fmt.Errorf("a bare call takes no arguments")
+	}
+
+	actions := c.Spec.BareActions.Call
+	if c.AppID == 0 {
+		actions = c.Spec.BareActions.Create
+	}
+	allowed := false
+	for _, action := range actions {
+		if action == actionNames[onComplete] {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%s does not allow bare calls with OnComplete %d", c.Spec.Name, onComplete)
+	}
+
+	var approval, clear []byte
+	var globalSchema, localSchema types.StateSchema
+	var extraPages uint32
+	if c.AppID == 0 || onComplete == types.UpdateApplicationOC {
+		var err error
+		if approval, clear, err = c.Programs(); err != nil {
+			return err
+		}
+	}
+	if c.AppID == 0 {
+		schema := c.Spec.State.Schema
+		globalSchema = types.StateSchema{NumUint: schema.Global.Ints, NumByteSlice: schema.Global.Bytes}
+		localSchema = types.StateSchema{NumUint: schema.Local.Ints, NumByteSlice: schema.Local.Bytes}
+		extraPages = transaction.ExtraProgramPages(approval, clear)
+	}
+	txn, err := transaction.MakeApplicationCallTxWithBoxes(c.AppID, nil, nil, nil, nil, nil, onComplete,
+		approval, clear, globalSchema, localSchema, extraPages, sp, c.Sender, nil, types.Digest{}, [32]byte{}, types.Address{})
+	if err != nil {
+		return err
+	}
+	return atc.AddTransaction(transaction.TransactionWithSigner{Txn: txn, Signer: c.Signer})
+}
+
+// Create creates the app with a call to the method with the name or signature
+// method, or a bare call when method is empty, and sets AppID to the ID of the
+// created app. See Send for args and result.
+func (c *Client) Create(ctx context.Context, method string, args []interface{}, result interface{}) (transaction.ABIMethodResult, error) {
+	if c.AppID != 0 {
+		return transaction.ABIMethodResult{}, fmt.Errorf("app %d is already created", c.AppID)
+	}
+	methodResult, err := c.Send(ctx, method, args, types.NoOpOC, result)
+	if err != nil {
+		return methodResult, err
+	}
+	if methodResult.TransactionInfo.ApplicationIndex == 0 {
+		return methodResult, fmt.Errorf("the app was not created by transaction %s", methodResult.TxID)
+	}
+	c.AppID = methodResult.TransactionInfo.ApplicationIndex
+	return methodResult, nil
+}
+
+// Call calls the method with the name or signature method with OnComplete
+// NoOp, or makes a bare NoOp call when method is empty. See Send for args and
+// result.
+func (c *Client) Call(ctx context.Context, method string, args []interface{}, result interface{}) (transaction.ABIMethodResult, error) {
+	return c.Send(ctx, method, args, types.NoOpOC, result)
+}
+
+// Update updates the app to the programs of the specification with a call to
+// the method with the name or signature method, or a bare call when method is
+// empty. See Send for args and result.
+func (c *Client) Update(ctx context.Context, method string, args []interface{}, result interface{}) (transaction.ABIMethodResult, error) {
+	return c.Send(ctx, method, args, types.UpdateApplicationOC, result)
+}
+
+// OptIn opts Sender in to the app with a call to the method with the name or
+// signature method, or a bare call when method is empty. See Send for args
+// and result.
+func (c *Client) OptIn(ctx context.Context, method string, args []interface{}, result interface{}) (transaction.ABIMethodResult, error) {
+	return c.Send(ctx, method, args, types.OptInOC, result)
+}
+
+// Send calls the method with the name or signature method, or makes a bare
+// call when method is empty, with OnComplete onComplete, see AddCall, and
+// returns the result of the call. The return value of a method is decoded
+// into the value pointed to by result if it is not nil, as done by
+// transaction.AppClient.DecodeReturnValue.
+//
+// The NoOp calls of read only methods are simulated without signatures, and
+// the others are submitted and waited for. Errors of the approval program are located with
+// the source information of the specification.
+func (c *Client) Send(ctx context.Context, method string, args []interface{}, onComplete types.OnCompletion, result interface{}) (transaction.ABIMethodResult, error) {
+	sp, err := c.Algod.SuggestedParams().Do(ctx)
+	if err != nil {
+		return transaction.ABIMethodResult{}, err
+	}
+	simulate := method != "" && c.AppID != 0 && onComplete == types.NoOpOC && c.isReadOnly(method)
+	caller := *c
+	if simulate {
+		caller.Signer = transaction.EmptyTransactionSigner{}
+	}
+	var atc transaction.AtomicTransactionComposer
+	if err := caller.AddCall(&atc, method, args, onComplete, sp); err != nil {
+		return transaction.ABIMethodResult{}, err
+	}
+	app := transaction.NewAppClient(c.Spec, c.AppID, c.Sender, c.Signer)
+
+	var methodResult transaction.ABIMethodResult
+	if simulate {
+		response, err := atc.Simulate(c.Algod, ctx, models.SimulateRequest{AllowEmptySignatures: true})
+		if err != nil {
+			return transaction.ABIMethodResult{}, err
+		}
+		methodResult = response.MethodResults[0]
+	} else {
+		waitRounds := c.WaitRounds
+		if waitRounds == 0 {
+			waitRounds = DefaultWaitRounds
+		}
+		response, err := atc.Execute(c.Algod, ctx, waitRounds)
+		if err != nil {
+			return transaction.ABIMethodResult{}, app.MapError(err)
+		}
+		if method != "" {
+			methodResult = response.MethodResults[0]
+		} else {
+			methodResult.TxID = response.TxIDs[0]
+			if methodResult.TransactionInfo, _, err = c.Algod.PendingTransactionInformation(methodResult.TxID).Do(ctx); err != nil {
+				return methodResult, err
+			}
+		}
+	}
+
+	if method == "" {
+		return methodResult, nil
+	}
+	if methodResult.DecodeError != nil {
+		return methodResult, app.MapError(methodResult.DecodeError)
+	}
+	if result != nil {
+		if err := app.DecodeReturnValue(methodResult, result); err != nil {
+			return methodResult, err
+		}
+	}
+	return methodResult, nil
+}
+
+// isReadOnly returns whether the method with the name or signature method is
+// read only.
+func (c *Client) isReadOnly(method string) bool {
+	spec, err := c.Spec.GetMethodByNameOrSignature(method)
+	return err == nil && spec.ReadOnly
+}
+
+// ReadState returns the global state of the app declared by the keys of the
+// specification, by name, as decoded by ReadGlobalState into an interface{}.
+// Keys the app has not set are omitted.
+func (c *Client) ReadState(ctx context.Context) (map[string]interface{}, error) {
+	state, err := c.globalState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{})
+	for name, key := range c.Spec.State.Keys.Global {
+		value, ok := state[key.Key]
+		if !ok {
+			continue
+		}
+		var v interface{}
+		if err := c.decodeValue(key.ValueType, value, &v); err != nil {
+			return nil, fmt.Errorf("global state %s: %w", name, err)
+		}
+		values[name] = v
+	}
+	return values, nil
+}
+
+// ReadGlobalState decodes the value of the global key named name by the
+// specification into the value pointed to by v: a uint64 for AVMUint64, a
+// []byte for AVMBytes, a string for AVMString, and as abi.Unmarshal does for
+// ABI types and structs.
+func (c *Client) ReadGlobalState(ctx context.Context, name string, v interface{}) error {
+	key, ok := c.Spec.State.Keys.Global[name]
+	if !ok {
+		return fmt.Errorf("unknown global state %s", name)
+	}
+	state, err := c.globalState(ctx)
+	if err != nil {
+		return err
+	}
+	value, ok := state[key.Key]
+	if !ok {
+		return fmt.Errorf("global state %s is not set", name)
+	}
+	return c.decodeValue(key.ValueType, value, v)
+}
+
+// ReadLocalState decodes the value of the local key named name by the
+// specification of account into the value pointed to by v, see
+// ReadGlobalState.
+func (c *Client) ReadLocalState(ctx context.Context, account types.Address, name string, v interface{}) error {
+	key, ok := c.Spec.State.Keys.Local[name]
+	if !ok {
+		return fmt.Errorf("unknown local state %s", name)
+	}
+	info, err := c.Algod.AccountApplicationInformation(account.String(), c.AppID).Do(ctx)
+	if err != nil {
+		return err
+	}
+	for _, kv := range info.AppLocalState.KeyValue {
+		if kv.Key == key.Key {
+			return c.decodeValue(key.ValueType, kv.Value, v)
+		}
+	}
+	return fmt.Errorf("local state %s of %s is not set", name, account)
+}
+
+// ReadBox decodes the value of the box named name by the specification into
+// the value pointed to by v, see ReadGlobalState.
+func (c *Client) ReadBox(ctx context.Context, name string, v interface{}) error {
+	key, ok := c.Spec.State.Keys.Box[name]
+	if !ok {
+		return fmt.Errorf("unknown box %s", name)
+	}
+	boxName, err := base64.StdEncoding.DecodeString(key.Key)
+	if err != nil {
+		return fmt.Errorf("invalid key of box %s: %w", name, err)
+	}
+	box, err := c.Algod.GetApplicationBoxByName(c.AppID, boxName).Do(ctx)
+	if err != nil {
+		return err
+	}
+	value := models.TealValue{Type: uint64(types.TealBytesType), Bytes: base64.StdEncoding.EncodeToString(box.Value)}
+	return c.decodeValue(key.ValueType, value, v)
+}
+
+// globalState returns the global state of the app by base64 key.
+func (c *Client) globalState(ctx context.Context) (map[string]models.TealValue, error) {
+	app, err := c.Algod.GetApplicationByID(c.AppID).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	state := make(map[string]models.TealValue, len(app.Params.GlobalState))
+	for _, kv := range app.Params.GlobalState {
+		state[kv.Key] = kv.Value
+	}
+	return state, nil
+}
+
+// decodeValue decodes a state value of the type valueType into the value
+// pointed to by v.
+func (c *Client) decodeValue(valueType string, value models.TealValue, v interface{}) error {
+	if value.Type == uint64(types.TealUintType) {
+		uint64Type, err := abi.TypeOf("uint64")
+		if err != nil {
+			return err
+		}
+		encoded := make([]byte, 8)
+		binary.BigEndian.PutUint64(encoded, value.Uint)
+		return abi.Unmarshal(uint64Type, encoded, v)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(value.Bytes)
+	if err != nil {
+		return err
+	}
+	switch valueType {
+	case "AVMBytes":
+		return assign(v, raw)
+	case "AVMString":
+		return assign(v, string(raw))
+	case "AVMUint64":
+		return fmt.Errorf("%s value is stored as bytes", valueType)
+	}
+	abiType, err := abi.TypeOf(valueType)
+	if err != nil {
+		if _, ok := c.Spec.Structs[valueType]; !ok {
+			return err
+		}
+		if abiType, err = c.Spec.StructType(valueType); err != nil {
+			return err
+		}
+	}
+	return abi.Unmarshal(abiType, raw, v)
+}
+
+// assign sets the value pointed to by v to value.
+func assign(v interface{}, value interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot decode into non-pointer %T", v)
+	}
+	elem := rv.Elem()
+	if !reflect.TypeOf(value).AssignableTo(elem.Type()) {
+		return fmt.Errorf("cannot decode %T into %s", value, elem.Type())
+	}
+	elem.Set(reflect.ValueOf(value))
+	return nil
+}
@@ -0,0 +1,290 @@
+package appclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/logic"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// fakeAlgod serves the algod endpoints used by Client for the app 42 of the
+// counterArc32 specification.
+type fakeAlgod struct {
+	t         *testing.T
+	sent      []types.SignedTxn
+	simulated []types.SignedTxn
+	account   types.Address
+}
+
+func (f *fakeAlgod) returnLog(signature string, value interface{}) []byte {
+	method, err := abi.MethodFromSignature(signature)
+	require.NoError(f.t, err)
+	returnType, err := method.Returns.GetTypeObject()
+	require.NoError(f.t, err)
+	encoded, err := abi.Marshal(returnType, value)
+	require.NoError(f.t, err)
+	return append(append([]byte(nil), abi.ReturnPrefix...), encoded...)
+}
+
+func (f *fakeAlgod) logs(txn types.Transaction) [][]byte {
+	if len(txn.ApplicationArgs) == 0 {
+		return nil
+	}
+	returns := map[string]func() interface{}{
+		"add(uint64,uint64)uint64": func() interface{} {
+			return binary.BigEndian.Uint64(txn.ApplicationArgs[1]) + binary.BigEndian.Uint64(txn.ApplicationArgs[2])
+		},
+		"get_pair()(uint64,uint64)": func() interface{} { return []interface{}{uint64(3), uint64(4)} },
+	}
+	for signature, value := range returns {
+		method, err := abi.MethodFromSignature(signature)
+		require.NoError(f.t, err)
+		if bytes.Equal(method.GetSelector(), txn.ApplicationArgs[0]) {
+			return [][]byte{f.returnLog(signature, value())}
+		}
+	}
+	return nil
+}
+
+func (f *fakeAlgod) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	stateValue := func(key string, value models.TealValue) models.TealKeyValue {
+		return models.TealKeyValue{Key: base64.StdEncoding.EncodeToString([]byte(key)), Value: value}
+	}
+	switch {
+	case r.URL.Path == "/v2/transactions/params":
+		fmt.Fprintf(w, `{"consensus-version":"future","fee":0,"genesis-hash":"%s","genesis-id":"test","last-round":10,"min-fee":1000}`,
+			base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	case r.URL.Path == "/v2/transactions/simulate":
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(f.t, err)
+		var request models.SimulateRequest
+		require.NoError(f.t, msgpack.Decode(body, &request))
+		require.True(f.t, request.AllowEmptySignatures)
+		stxn := request.TxnGroups[0].Txns[0]
+		f.simulated = append(f.simulated, stxn)
+		result := models.SimulateTransactionResult{TxnResult: models.PendingTransactionResponse{Logs: f.logs(stxn.Txn)}}
+		w.Write(json.Encode(models.SimulateResponse{TxnGroups: []models.SimulateTransactionGroupResult{{TxnResults: []models.SimulateTransactionResult{result}}}}))
+	case r.URL.Path == "/v2/transactions":
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(f.t, err)
+		var stxn types.SignedTxn
+		require.NoError(f.t, msgpack.Decode(body, &stxn))
+		f.sent = append(f.sent, stxn)
+		fmt.Fprintf(w, `{"txId":"%s"}`, crypto.GetTxID(stxn.Txn))
+	case strings.HasPrefix(r.URL.Path, "/v2/status"):
+		fmt.Fprint(w, `{"last-round":10}`)
+	case strings.HasPrefix(r.URL.Path, "/v2/transactions/pending/"):
+		txn := f.sent[len(f.sent)-1].Txn
+		require.Equal(f.t, crypto.GetTxID(txn), strings.TrimPrefix(r.URL.Path, "/v2/transactions/pending/"))
+		info := models.PendingTransactionInfoResponse{ConfirmedRound: 11, Logs: f.logs(txn)}
+		if txn.ApplicationID == 0 {
+			info.ApplicationIndex = 42
+		}
+		w.Write(msgpack.Encode(info))
+	case r.URL.Path == "/v2/applications/42":
+		w.Write(json.Encode(models.Application{Id: 42, Params: models.ApplicationParams{GlobalState: []models.TealKeyValue{
+			stateValue("counter", models.TealValue{Type: uint64(types.TealUintType), Uint: 7}),
+			stateValue("name", models.TealValue{Type: uint64(types.TealBytesType), Bytes: base64.StdEncoding.EncodeToString([]byte("counter"))}),
+			stateValue("undeclared", models.TealValue{Type: uint64(types.TealUintType), Uint: 1}),
+		}}}))
+	case r.URL.Path == "/v2/accounts/"+f.account.String()+"/applications/42":
+		w.Write(json.Encode(models.AccountApplicationResponse{AppLocalState: models.ApplicationLocalState{Id: 42, KeyValue: []models.TealKeyValue{
+			stateValue("bal", models.TealValue{Type: uint64(types.TealUintType), Uint: 9}),
+		}}}))
+	default:
+		f.t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+	}
+}
+
+func newTestClient(t *testing.T) (*Client, *fakeAlgod) {
+	sender := crypto.GenerateAccount()
+	fake := &fakeAlgod{t: t, account: sender.Address}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	algodClient, err := algod.MakeClient(server.URL, "")
+	require.NoError(t, err)
+	client, err := NewFromSpec(counterArc32, algodClient, 0, sender.Address, transaction.BasicAccountTransactionSigner{Account: sender})
+	require.NoError(t, err)
+	client.TemplateValues = map[string]interface{}{"ANSWER": 1}
+	return client, fake
+}
+
+func TestClientPrograms(t *testing.T) {
+	client, _ := newTestClient(t)
+	approval, clear, err := client.Programs()
+	require.NoError(t, err)
+	template, err := logic.AssembleTemplate("#pragma version 8\nint TMPL_ANSWER\nreturn\n")
+	require.NoError(t, err)
+	expected, err := template.Program(map[string]interface{}{"ANSWER": 1})
+	require.NoError(t, err)
+	require.Equal(t, expected, approval)
+	expected, err = logic.Assemble("#pragma version 8\nint 1\n")
+	require.NoError(t, err)
+	require.Equal(t, expected, clear)
+
+	client.TemplateValues = nil
+	_, _, err = client.Programs()
+	require.Error(t, err)
+	client.TemplateValues = map[string]interface{}{"ANSWER": 1, "UNUSED": 2}
+	_, _, err = client.Programs()
+	require.Error(t, err)
+
+	client.Spec.Source = nil
+	_, _, err = client.Programs()
+	require.Error(t, err)
+}
+
+func TestClientCreateAndCall(t *testing.T) {
+	client, fake := newTestClient(t)
+	ctx := context.Background()
+	approval, clear, err := client.Programs()
+	require.NoError(t, err)
+
+	_, err = client.Call(ctx, "add", []interface{}{uint64(1), uint64(2)}, nil)
+	require.Error(t, err)
+	require.Empty(t, fake.sent)
+
+	result, err := client.Create(ctx, "create", []interface{}{"counter", uint64(5)}, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), client.AppID)
+	require.Equal(t, uint64(11), result.TransactionInfo.ConfirmedRound)
+	create := fake.sent[0].Txn
+	require.Equal(t, types.AppIndex(0), create.ApplicationID)
+	require.Equal(t, approval, create.ApprovalProgram)
+	require.Equal(t, clear, create.ClearStateProgram)
+	require.Equal(t, types.StateSchema{NumUint: 1, NumByteSlice: 1}, create.GlobalStateSchema)
+	require.Equal(t, types.StateSchema{NumUint: 1}, create.LocalStateSchema)
+
+	_, err = client.Create(ctx, "", nil, nil)
+	require.Error(t, err)
+
+	var sum uint64
+	result, err = client.Call(ctx, "add", []interface{}{uint64(1), uint64(2)}, &sum)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), sum)
+	require.Equal(t, uint64(3), result.ReturnValue)
+	call := fake.sent[1].Txn
+	require.Equal(t, types.AppIndex(42), call.ApplicationID)
+	require.Empty(t, call.ApprovalProgram)
+
+	var pair struct{ A, B uint64 }
+	_, err = client.Call(ctx, "get_pair", nil, &pair)
+	require.NoError(t, err)
+	require.Equal(t, struct{ A, B uint64 }{3, 4}, pair)
+	require.Len(t, fake.sent, 2)
+	require.Len(t, fake.simulated, 1)
+	require.Equal(t, types.Signature{}, fake.simulated[0].Sig)
+
+	_, err = client.Call(ctx, "missing", nil, nil)
+	require.Error(t, err)
+	_, err = client.Call(ctx, "", nil, nil)
+	require.Error(t, err)
+}
+
+func TestClientBareCalls(t *testing.T) {
+	client, fake := newTestClient(t)
+	ctx := context.Background()
+	approval, _, err := client.Programs()
+	require.NoError(t, err)
+
+	result, err := client.Create(ctx, "", nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), client.AppID)
+	require.Equal(t, crypto.GetTxID(fake.sent[0].Txn), result.TxID)
+	require.Equal(t, approval, fake.sent[0].Txn.ApprovalProgram)
+	require.Equal(t, types.StateSchema{NumUint: 1, NumByteSlice: 1}, fake.sent[0].Txn.GlobalStateSchema)
+
+	_, err = client.OptIn(ctx, "", nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, types.OptInOC, fake.sent[1].Txn.OnCompletion)
+	require.Empty(t, fake.sent[1].Txn.ApprovalProgram)
+
+	_, err = client.OptIn(ctx, "set", []interface{}{uint64(1)}, nil)
+	require.NoError(t, err)
+	require.Equal(t, types.OptInOC, fake.sent[2].Txn.OnCompletion)
+
+	_, err = client.Update(ctx, "", nil, nil)
+	require.NoError(t, err)
+	update := fake.sent[3].Txn
+	require.Equal(t, types.UpdateApplicationOC, update.OnCompletion)
+	require.Equal(t, approval, update.ApprovalProgram)
+	require.Equal(t, types.StateSchema{}, update.GlobalStateSchema)
+
+	_, err = client.Update(ctx, "update", nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, approval, fake.sent[4].Txn.ApprovalProgram)
+
+	_, err = client.Send(ctx, "", nil, types.DeleteApplicationOC, nil)
+	require.Error(t, err)
+	_, err = client.OptIn(ctx, "", []interface{}{uint64(1)}, nil)
+	require.Error(t, err)
+	require.Len(t, fake.sent, 5)
+}
+
+func TestClientReadState(t *testing.T) {
+	client, fake := newTestClient(t)
+	client.AppID = 42
+	ctx := context.Background()
+
+	state, err := client.ReadState(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"counter": uint64(7), "name": []byte("counter")}, state)
+
+	var counter uint32
+	require.NoError(t, client.ReadGlobalState(ctx, "counter", &counter))
+	require.Equal(t, uint32(7), counter)
+	var name []byte
+	require.NoError(t, client.ReadGlobalState(ctx, "name", &name))
+	require.Equal(t, []byte("counter"), name)
+	var wrong string
+	require.Error(t, client.ReadGlobalState(ctx, "name", &wrong))
+	require.Error(t, client.ReadGlobalState(ctx, "undeclared", &counter))
+
+	var balance uint64
+	require.NoError(t, client.ReadLocalState(ctx, fake.account, "balance", &balance))
+	require.Equal(t, uint64(9), balance)
+	require.Error(t, client.ReadBox(ctx, "missing", &name))
+}
+
+func TestClientDecodeValue(t *testing.T) {
+	client, _ := newTestClient(t)
+	client.Spec.Structs["Pair"] = []abi.Arc56StructField{{Name: "a", Type: "uint64"}, {Name: "b", Type: "uint64"}}
+	bytesValue := func(raw []byte) models.TealValue {
+		return models.TealValue{Type: uint64(types.TealBytesType), Bytes: base64.StdEncoding.EncodeToString(raw)}
+	}
+
+	var s string
+	require.NoError(t, client.decodeValue("AVMString", bytesValue([]byte("hi")), &s))
+	require.Equal(t, "hi", s)
+
+	encoded, err := abi.Marshal(mustType(t, "(uint64,uint64)"), []interface{}{uint64(1), uint64(2)})
+	require.NoError(t, err)
+	var pair struct{ A, B uint64 }
+	require.NoError(t, client.decodeValue("Pair", bytesValue(encoded), &pair))
+	require.Equal(t, struct{ A, B uint64 }{1, 2}, pair)
+	var v interface{}
+	require.NoError(t, client.decodeValue("(uint64,uint64)", bytesValue(encoded), &v))
+	require.Equal(t, []interface{}{uint64(1), uint64(2)}, v)
+
+	require.Error(t, client.decodeValue("AVMUint64", bytesValue(encoded), &v))
+	require.Error(t, client.decodeValue("Unknown", bytesValue(encoded), &v))
+	require.Error(t, client.decodeValue("AVMString", bytesValue(encoded), s))
+}
@@ -0,0 +1,311 @@
+// Package arc1 provides the ARC-1 payloads asking wallets to sign
+// transactions, so that backends can produce signing requests that wallets
+// accept and validate the requests they receive.
+package arc1
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// MultisigMetadata describes the multisig account a transaction is signed
+// with.
+type MultisigMetadata struct {
+	Version   uint8    `json:"version"`
+	Threshold uint8    `json:"threshold"`
+	Addrs     []string `json:"addrs"`
+}
+
+// Account returns the multisig account described by the metadata.
+func (m MultisigMetadata) Account() (crypto.MultisigAccount, error) {
+	addrs := make([]types.Address, len(m.Addrs))
+	for i, addr := range m.Addrs {
+		var err error
+		if addrs[i], err = types.DecodeAddress(addr); err != nil {
+			return crypto.MultisigAccount{}, fmt.Errorf("invalid multisig address %s: %w", addr, err)
+		}
+	}
+	return crypto.MultisigAccountWithParams(m.Version, m.Threshold, addrs)
+}
+
+// WalletTransaction is a transaction of a signing request.
+type WalletTransaction struct {
+	// Txn is the base64 msgpack encoding of the transaction
+	Txn string `json:"txn"`
+	// AuthAddr is the address the sender is rekeyed to, if any
+	AuthAddr string `json:"authAddr,omitempty"`
+	// Msig describes the multisig account signing the transaction, if any
+	Msig *MultisigMetadata `json:"msig,omitempty"`
+	// Signers are the addresses the wallet signs the transaction with. When
+	// nil, the wallet signs with the authorizing address of the transaction.
+	// When empty but not nil, the wallet does not sign the transaction, which
+	// is only given as part of its group.
+	Signers []string `json:"signers,omitempty"`
+	// Stxn is the base64 msgpack encoding of the signed transaction, only
+	// given along empty Signers
+	Stxn string `json:"stxn,omitempty"`
+	// Message explains the transaction to the user
+	Message string `json:"message,omitempty"`
+	// GroupMessage explains the group of the transaction to the user
+	GroupMessage string `json:"groupMessage,omitempty"`
+}
+
+// walletTransactionJSON is the JSON encoding of a WalletTransaction, which
+// tells nil Signers from empty Signers.
+type walletTransactionJSON struct {
+	Txn          string            `json:"txn"`
+	AuthAddr     string            `json:"authAddr,omitempty"`
+	Msig         *MultisigMetadata `json:"msig,omitempty"`
+	Signers      *[]string         `json:"signers,omitempty"`
+	Stxn         string            `json:"stxn,omitempty"`
+	Message      string            `json:"message,omitempty"`
+	GroupMessage string            `json:"groupMessage,omitempty"`
+}
+
+// MarshalJSON encodes the transaction to JSON, writing empty Signers as an
+// empty array.
+func (w WalletTransaction) MarshalJSON() ([]byte, error) {
+	encoded := walletTransactionJSON{
+		Txn:          w.Txn,
+		AuthAddr:     w.AuthAddr,
+		Msig:         w.Msig,
+		Stxn:         w.Stxn,
+		Message:      w.Message,
+		GroupMessage: w.GroupMessage,
+	}
+	if w.Signers != nil {
+		encoded.Signers = &w.Signers
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes the transaction from JSON, reading an empty signers
+// array as empty but not nil Signers.
+func (w *WalletTransaction) UnmarshalJSON(data []byte) error {
+	var decoded walletTransactionJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*w = WalletTransaction{
+		Txn:          decoded.Txn,
+		AuthAddr:     decoded.AuthAddr,
+		Msig:         decoded.Msig,
+		Stxn:         decoded.Stxn,
+		Message:      decoded.Message,
+		GroupMessage: decoded.GroupMessage,
+	}
+	if decoded.Signers != nil {
+		w.Signers = *decoded.Signers
+		if w.Signers == nil {
+			w.Signers = []string{}
+		}
+	}
+	return nil
+}
+
+// MakeWalletTransaction returns the WalletTransaction asking to sign txn with
+// the authorizing address of its sender.
+func MakeWalletTransaction(txn types.Transaction) WalletTransaction {
+	return WalletTransaction{Txn: base64.StdEncoding.EncodeToString(msgpack.Encode(txn))}
+}
+
+// MakeUnsignedWalletTransaction returns the WalletTransaction giving txn as
+// part of its group without asking to sign it. stxn is the signed
+// transaction, if available.
+func MakeUnsignedWalletTransaction(txn types.Transaction, stxn *types.SignedTxn) WalletTransaction {
+	w := MakeWalletTransaction(txn)
+	w.Signers = []string{}
+	if stxn != nil {
+		w.Stxn = base64.StdEncoding.EncodeToString(msgpack.Encode(stxn))
+	}
+	return w
+}
+
+// IsSigned returns whether the wallet is asked to sign the transaction.
+func (w WalletTransaction) IsSigned() bool {
+	return w.Signers == nil || len(w.Signers) > 0
+}
+
+// Transaction decodes the transaction.
+func (w WalletTransaction) Transaction() (types.Transaction, error) {
+	var txn types.Transaction
+	encoded, err := base64.StdEncoding.DecodeString(w.Txn)
+	if err != nil {
+		return txn, fmt.Errorf("invalid base64 transaction: %w", err)
+	}
+	if err := msgpack.Decode(encoded, &txn); err != nil {
+		return txn, fmt.Errorf("invalid msgpack transaction: %w", err)
+	}
+	return txn, nil
+}
+
+// SignedTransaction decodes the signed transaction of Stxn.
+func (w WalletTransaction) SignedTransaction() (types.SignedTxn, error) {
+	var stxn types.SignedTxn
+	encoded, err := base64.StdEncoding.DecodeString(w.Stxn)
+	if err != nil {
+		return stxn, fmt.Errorf("invalid base64 signed transaction: %w", err)
+	}
+	if err := msgpack.Decode(encoded, &stxn); err != nil {
+		return stxn, fmt.Errorf("invalid msgpack signed transaction: %w", err)
+	}
+	return stxn, nil
+}
+
+// Validate returns an error when the transaction is not a valid ARC-1 wallet
+// transaction:
+//   - the transaction or AuthAddr cannot be decoded
+//   - Msig is not a valid multisig account, or its address is not AuthAddr,
+//     or the sender without AuthAddr
+//   - a signer is not an address of Msig, or without Msig, is not AuthAddr or
+//     the sender, or more than one signer is given
+//   - Stxn is given along Signers that are not empty, or is not the signed
+//     transaction
+func (w WalletTransaction) Validate() error {
+	txn, err := w.Transaction()
+	if err != nil {
+		return err
+	}
+	authAddr := txn.Sender
+	if w.AuthAddr != "" {
+		if authAddr, err = types.DecodeAddress(w.AuthAddr); err != nil {
+			return fmt.Errorf("invalid authAddr %s: %w", w.AuthAddr, err)
+		}
+	}
+
+	if w.Msig != nil {
+		account, err := w.Msig.Account()
+		if err != nil {
+			return err
+		}
+		address, err := account.Address()
+		if err != nil {
+			return err
+		}
+		if address != authAddr {
+			return fmt.Errorf("multisig address %s is not the authorizing address %s", address, authAddr)
+		}
+		seen := make(map[string]bool)
+		for _, signer := range w.Signers {
+			if seen[signer] {
+				return fmt.Errorf("duplicate signer %s", signer)
+			}
+			seen[signer] = true
+			if !containsString(w.Msig.Addrs, signer) {
+				return fmt.Errorf("signer %s is not an address of the multisig account", signer)
+			}
+		}
+	} else {
+		if len(w.Signers) > 1 {
+			return fmt.Errorf("%d signers given without a multisig account", len(w.Signers))
+		}
+		if len(w.Signers) == 1 && w.Signers[0] != authAddr.String() {
+			return fmt.Errorf("signer %s is not the authorizing address %s", w.Signers[0], authAddr)
+		}
+	}
+
+	if w.Stxn != "" {
+		if w.IsSigned() {
+			return fmt.Errorf("stxn given for a transaction to sign")
+		}
+		stxn, err := w.SignedTransaction()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(msgpack.Encode(stxn.Txn), msgpack.Encode(txn)) {
+			return fmt.Errorf("stxn is not a signature of the transaction")
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SignTxnsOpts are the options of a signing request.
+type SignTxnsOpts struct {
+	// Message explains the request to the user
+	Message string `json:"message,omitempty"`
+}
+
+// SignTxnsRequest asks a wallet to sign transactions.
+type SignTxnsRequest struct {
+	Txns []WalletTransaction `json:"txns"`
+	Opts *SignTxnsOpts       `json:"opts,omitempty"`
+}
+
+// MakeSignTxnsRequest returns the request to sign txns with the authorizing
+// addresses of their senders.
+func MakeSignTxnsRequest(txns []types.Transaction) SignTxnsRequest {
+	request := SignTxnsRequest{Txns: make([]WalletTransaction, len(txns))}
+	for i, txn := range txns {
+		request.Txns[i] = MakeWalletTransaction(txn)
+	}
+	return request
+}
+
+// Validate returns an error when the request is not a valid ARC-1 signing
+// request: when a transaction is invalid, no transaction is to be signed, or
+// the transactions of a group are not all given, consecutively and in order,
+// with the group ID they compute.
+func (r SignTxnsRequest) Validate() error {
+	if len(r.Txns) == 0 {
+		return fmt.Errorf("no transactions to sign")
+	}
+	txns := make([]types.Transaction, len(r.Txns))
+	signed := false
+	for i, w := range r.Txns {
+		if err := w.Validate(); err != nil {
+			return fmt.Errorf("transaction %d: %w", i, err)
+		}
+		txns[i], _ = w.Transaction()
+		signed = signed || w.IsSigned()
+	}
+	if !signed {
+		return fmt.Errorf("no transactions to sign")
+	}
+
+	seen := make(map[types.Digest]bool)
+	for start := 0; start < len(txns); {
+		group := txns[start].Group
+		end := start + 1
+		if group == (types.Digest{}) {
+			start = end
+			continue
+		}
+		for end < len(txns) && txns[end].Group == group {
+			end++
+		}
+		if seen[group] {
+			return fmt.Errorf("transactions %d to %d are not consecutive with the rest of their group", start, end-1)
+		}
+		seen[group] = true
+
+		ungrouped := make([]types.Transaction, end-start)
+		copy(ungrouped, txns[start:end])
+		for i := range ungrouped {
+			ungrouped[i].Group = types.Digest{}
+		}
+		gid, err := crypto.ComputeGroupID(ungrouped)
+		if err != nil {
+			return fmt.Errorf("transactions %d to %d: %w", start, end-1, err)
+		}
+		if gid != group {
+			return fmt.Errorf("transactions %d to %d are not their whole group in order", start, end-1)
+		}
+		start = end
+	}
+	return nil
+}
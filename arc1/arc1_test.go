@@ -0,0 +1,134 @@
+package arc1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+func makePayment(sender, receiver types.Address, amount uint64) types.Transaction {
+	txn := types.Transaction{Type: types.PaymentTx}
+	txn.Sender = sender
+	txn.Fee = 1000
+	txn.FirstValid = 1
+	txn.LastValid = 1001
+	txn.Receiver = receiver
+	txn.Amount = types.MicroAlgos(amount)
+	return txn
+}
+
+func TestWalletTransactionJSON(t *testing.T) {
+	txn := makePayment(crypto.GenerateAccount().Address, crypto.GenerateAccount().Address, 5)
+	w := MakeWalletTransaction(txn)
+	encoded, err := json.Marshal(w)
+	require.NoError(t, err)
+	require.Equal(t, `{"txn":"`+w.Txn+`"}`, string(encoded))
+
+	decoded, err := w.Transaction()
+	require.NoError(t, err)
+	require.Equal(t, txn, decoded)
+
+	unsigned := MakeUnsignedWalletTransaction(txn, nil)
+	encoded, err = json.Marshal(unsigned)
+	require.NoError(t, err)
+	require.Equal(t, `{"txn":"`+w.Txn+`","signers":[]}`, string(encoded))
+
+	var parsed WalletTransaction
+	require.NoError(t, json.Unmarshal(encoded, &parsed))
+	require.NotNil(t, parsed.Signers)
+	require.False(t, parsed.IsSigned())
+	require.NoError(t, json.Unmarshal([]byte(`{"txn":"`+w.Txn+`"}`), &parsed))
+	require.Nil(t, parsed.Signers)
+	require.True(t, parsed.IsSigned())
+}
+
+func TestWalletTransactionValidate(t *testing.T) {
+	sender := crypto.GenerateAccount()
+	other := crypto.GenerateAccount()
+	txn := makePayment(sender.Address, other.Address, 5)
+
+	w := MakeWalletTransaction(txn)
+	require.NoError(t, w.Validate())
+	w.Signers = []string{sender.Address.String()}
+	require.NoError(t, w.Validate())
+	w.Signers = []string{other.Address.String()}
+	require.Error(t, w.Validate())
+	w.AuthAddr = other.Address.String()
+	require.NoError(t, w.Validate())
+	w.Signers = []string{sender.Address.String(), other.Address.String()}
+	require.Error(t, w.Validate())
+	w.AuthAddr = "invalid"
+	w.Signers = nil
+	require.Error(t, w.Validate())
+
+	msig, err := crypto.MultisigAccountWithParams(1, 1, []types.Address{sender.Address, other.Address})
+	require.NoError(t, err)
+	msigAddress, err := msig.Address()
+	require.NoError(t, err)
+	w = MakeWalletTransaction(txn)
+	w.Msig = &MultisigMetadata{Version: 1, Threshold: 1, Addrs: []string{sender.Address.String(), other.Address.String()}}
+	require.Error(t, w.Validate())
+	w.AuthAddr = msigAddress.String()
+	require.NoError(t, w.Validate())
+	w.Signers = []string{other.Address.String()}
+	require.NoError(t, w.Validate())
+	w.Signers = []string{other.Address.String(), other.Address.String()}
+	require.Error(t, w.Validate())
+	w.Signers = []string{crypto.GenerateAccount().Address.String()}
+	require.Error(t, w.Validate())
+	w.Signers = nil
+	w.Msig.Threshold = 3
+	require.Error(t, w.Validate())
+
+	_, signed, err := crypto.SignTransaction(sender.PrivateKey, txn)
+	require.NoError(t, err)
+	w = MakeWalletTransaction(txn)
+	w.Stxn = MakeUnsignedWalletTransaction(txn, nil).Txn
+	require.Error(t, w.Validate())
+	var stxn types.SignedTxn
+	require.NoError(t, msgpack.Decode(signed, &stxn))
+	w = MakeUnsignedWalletTransaction(txn, &stxn)
+	require.NoError(t, w.Validate())
+	stxn.Txn.Amount++
+	w = MakeUnsignedWalletTransaction(txn, &stxn)
+	require.Error(t, w.Validate())
+
+	require.Error(t, WalletTransaction{Txn: "not base64"}.Validate())
+}
+
+func TestSignTxnsRequestValidate(t *testing.T) {
+	sender := crypto.GenerateAccount().Address
+	receiver := crypto.GenerateAccount().Address
+	group := []types.Transaction{makePayment(sender, receiver, 1), makePayment(sender, receiver, 2)}
+	gid, err := crypto.ComputeGroupID(group)
+	require.NoError(t, err)
+	for i := range group {
+		group[i].Group = gid
+	}
+	single := makePayment(sender, receiver, 3)
+
+	request := MakeSignTxnsRequest([]types.Transaction{single, group[0], group[1]})
+	require.NoError(t, request.Validate())
+	request.Txns[1] = MakeUnsignedWalletTransaction(group[0], nil)
+	require.NoError(t, request.Validate())
+
+	require.Error(t, SignTxnsRequest{}.Validate())
+	require.Error(t, MakeSignTxnsRequest(group[:1]).Validate())
+	require.Error(t, MakeSignTxnsRequest([]types.Transaction{group[1], group[0]}).Validate())
+	require.Error(t, MakeSignTxnsRequest([]types.Transaction{group[0], single, group[1]}).Validate())
+
+	unsigned := MakeSignTxnsRequest(group)
+	for i := range unsigned.Txns {
+		unsigned.Txns[i].Signers = []string{}
+	}
+	require.Error(t, unsigned.Validate())
+
+	invalid := MakeSignTxnsRequest([]types.Transaction{single})
+	invalid.Txns[0].Signers = []string{receiver.String()}
+	require.EqualError(t, invalid.Validate(), "transaction 0: signer "+receiver.String()+" is not the authorizing address "+sender.String())
+}
@@ -0,0 +1,334 @@
+// Package arc18 transfers royalty enforced assets following ARC-18. The
+// clawback of such an asset is an enforcer app, which moves the asset between
+// accounts only when paid, splitting the payment between the seller and the
+// royalty recipient of its policy, and only for the amounts its holders offer
+// to sell through an authorized address, such as a marketplace.
+package arc18
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// BasisPoints is the royalty basis of a policy taking the whole payment.
+const BasisPoints = 10000
+
+// The signatures of the methods of ARC-18 enforcer apps called by Enforcer.
+const (
+	SetAdministratorSignature     = "set_administrator(address)void"
+	SetPolicySignature            = "set_policy(uint64,account)void"
+	SetPaymentAssetSignature      = "set_payment_asset(asset,bool)void"
+	TransferAlgoPaymentSignature  = "transfer_algo_payment(asset,uint64,account,account,account,pay,uint64)void"
+	TransferAssetPaymentSignature = "transfer_asset_payment(asset,uint64,account,account,account,axfer,asset,uint64)void"
+	OfferSignature                = "offer(asset,uint64,address,uint64,address)void"
+	RoyaltyFreeMoveSignature      = "royalty_free_move(asset,uint64,account,account,uint64)void"
+	GetPolicySignature            = "get_policy()(address,uint64)"
+	GetOfferSignature             = "get_offer(uint64,account)(address,uint64)"
+	GetAdministratorSignature     = "get_administrator()address"
+)
+
+// The number of inner transactions of the enforcer methods, whose fees are
+// paid by their callers: the transfers pay the royalty and the seller and move
+// the asset, and setting a payment asset opts the enforcer in to it.
+const (
+	transferInnerTxns        = 3
+	royaltyFreeMoveInnerTxns = 1
+	setPaymentAssetInnerTxns = 1
+)
+
+// Policy is the royalty policy of an enforcer, as returned by get_policy.
+type Policy struct {
+	// Recipient receives the royalties
+	Recipient types.Address
+	// Basis is the share of payments paid as royalties, in basis points
+	Basis uint64
+}
+
+// Validate returns an error when the basis of the policy is more than
+// BasisPoints.
+func (p Policy) Validate() error {
+	if p.Basis > BasisPoints {
+		return fmt.Errorf("royalty basis %d exceeds %d basis points", p.Basis, BasisPoints)
+	}
+	return nil
+}
+
+// Split returns the royalty and the amount the seller receives of a payment of
+// amount, with the royalty rounded down as done by the enforcer.
+func (p Policy) Split(amount uint64) (royalty, seller uint64, err error) {
+	if err := p.Validate(); err != nil {
+		return 0, 0, err
+	}
+	hi, lo := bits.Mul64(amount, p.Basis)
+	royalty, _ = bits.Div64(hi, lo, BasisPoints)
+	return royalty, amount - royalty, nil
+}
+
+// Offer is the offer of a holder to sell an amount of an asset through an
+// authorized address, as returned by get_offer.
+type Offer struct {
+	// AuthAddress is the address authorized to transfer the asset
+	AuthAddress types.Address
+	// Amount is the amount of the asset offered
+	Amount uint64
+}
+
+// ValidateTransfer returns an error when a transfer of amount of the offered
+// asset from sender is not allowed by the offer: when there is no offer,
+// sender is not its authorized address, or amount is zero or more than
+// offered.
+func (o Offer) ValidateTransfer(sender types.Address, amount uint64) error {
+	if o.AuthAddress.IsZero() {
+		return fmt.Errorf("the asset is not offered")
+	}
+	if sender != o.AuthAddress {
+		return fmt.Errorf("%s is not the authorized address %s of the offer", sender, o.AuthAddress)
+	}
+	if amount == 0 || amount > o.Amount {
+		return fmt.Errorf("transfer of %d exceeds the offered amount %d", amount, o.Amount)
+	}
+	return nil
+}
+
+// ValidateOffer returns an error when an offer of amount of an asset through
+// authAddress is invalid for a holder of balance of the asset.
+func ValidateOffer(authAddress types.Address, amount, balance uint64) error {
+	if authAddress.IsZero() {
+		return fmt.Errorf("the authorized address of an offer must not be the zero address")
+	}
+	if amount > balance {
+		return fmt.Errorf("offer of %d exceeds the balance %d", amount, balance)
+	}
+	return nil
+}
+
+// Enforcer adds calls to an ARC-18 enforcer app to an
+// AtomicTransactionComposer.
+type Enforcer struct {
+	// The ID of the enforcer app
+	AppID uint64
+	// The sender of the method calls
+	Sender types.Address
+	// A transaction Signer that can authorize the method calls from Sender
+	Signer transaction.TransactionSigner
+}
+
+// NewEnforcer returns an Enforcer calling the enforcer app appID from sender.
+func NewEnforcer(appID uint64, sender types.Address, signer transaction.TransactionSigner) *Enforcer {
+	return &Enforcer{AppID: appID, Sender: sender, Signer: signer}
+}
+
+// Address returns the address of the enforcer app, which payments of
+// transfers are sent to.
+func (e *Enforcer) Address() types.Address {
+	return crypto.GetApplicationAddress(e.AppID)
+}
+
+// withInnerFees returns sp with the flat fee of a transaction paying for
+// innerTxns inner transactions.
+func withInnerFees(sp types.SuggestedParams, innerTxns uint64) types.SuggestedParams {
+	minFee := sp.MinFee
+	if minFee == 0 {
+		minFee = transaction.MinTxnFee
+	}
+	sp.FlatFee = true
+	sp.Fee = types.MicroAlgos(minFee * (1 + innerTxns))
+	return sp
+}
+
+// methodCallParams returns the parameters of a call to the method with the
+// signature signature.
+func (e *Enforcer) methodCallParams(signature string, args []interface{}, sp types.SuggestedParams) (transaction.AddMethodCallParams, error) {
+	method, err := abi.MethodFromSignature(signature)
+	if err != nil {
+		return transaction.AddMethodCallParams{}, err
+	}
+	return transaction.AddMethodCallParams{
+		AppID:           e.AppID,
+		Method:          method,
+		MethodArgs:      args,
+		Sender:          e.Sender,
+		SuggestedParams: sp,
+		OnComplete:      types.NoOpOC,
+		Signer:          e.Signer,
+	}, nil
+}
+
+// addMethodCall adds a call to the method with the signature signature to atc.
+func (e *Enforcer) addMethodCall(atc *transaction.AtomicTransactionComposer, signature string, args []interface{}, sp types.SuggestedParams) error {
+	params, err := e.methodCallParams(signature, args, sp)
+	if err != nil {
+		return err
+	}
+	return atc.AddMethodCall(params)
+}
+
+// AddSetAdministrator adds setting the administrator of the enforcer to
+// administrator to atc. Sender must be the current administrator.
+func (e *Enforcer) AddSetAdministrator(atc *transaction.AtomicTransactionComposer, administrator types.Address, sp types.SuggestedParams) error {
+	return e.addMethodCall(atc, SetAdministratorSignature, []interface{}{administrator}, sp)
+}
+
+// AddSetPolicy adds setting the royalty policy of the enforcer to atc. Sender
+// must be the administrator.
+func (e *Enforcer) AddSetPolicy(atc *transaction.AtomicTransactionComposer, policy Policy, sp types.SuggestedParams) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	return e.addMethodCall(atc, SetPolicySignature, []interface{}{policy.Basis, policy.Recipient}, sp)
+}
+
+// AddSetPaymentAsset adds allowing or disallowing payments of transfers in
+// the asset assetID to atc. Sender must be the administrator.
+func (e *Enforcer) AddSetPaymentAsset(atc *transaction.AtomicTransactionComposer, assetID uint64, allowed bool, sp types.SuggestedParams) error {
+	return e.addMethodCall(atc, SetPaymentAssetSignature, []interface{}{assetID, allowed}, withInnerFees(sp, setPaymentAssetInnerTxns))
+}
+
+// AddOffer adds the offer of Sender to sell amount of the asset assetID through
+// authAddress to atc, replacing its previous offer prev.
+func (e *Enforcer) AddOffer(atc *transaction.AtomicTransactionComposer, assetID, amount uint64, authAddress types.Address, prev Offer, sp types.SuggestedParams) error {
+	if authAddress.IsZero() {
+		return fmt.Errorf("the authorized address of an offer must not be the zero address")
+	}
+	return e.addMethodCall(atc, OfferSignature, []interface{}{assetID, amount, authAddress, prev.Amount, prev.AuthAddress}, sp)
+}
+
+// Transfer is a transfer of a royalty enforced asset paid to the enforcer.
+type Transfer struct {
+	// AssetID is the royalty enforced asset
+	AssetID uint64
+	// Amount is the amount of the asset transferred
+	Amount uint64
+	// From is the seller of the asset, and To its buyer
+	From types.Address
+	To   types.Address
+	// Policy is the royalty policy of the enforcer
+	Policy Policy
+	// Offer is the current offer of From, which Sender must be authorized by
+	Offer Offer
+	// Payment pays the enforcer for the asset, in Algos or an asset allowed
+	// for payments
+	Payment transaction.TransactionWithSigner
+}
+
+// AddTransfer adds the transfer t to atc: the payment of the enforcer along
+// with the call splitting it between the seller and the royalty recipient
+// and moving the asset. Sender must be the authorized address of the offer.
+func (e *Enforcer) AddTransfer(atc *transaction.AtomicTransactionComposer, t Transfer, sp types.SuggestedParams) error {
+	if err := t.Offer.ValidateTransfer(e.Sender, t.Amount); err != nil {
+		return err
+	}
+	if err := t.Policy.Validate(); err != nil {
+		return err
+	}
+
+	payment := t.Payment.Txn
+	var signature string
+	var args []interface{}
+	switch payment.Type {
+	case types.PaymentTx:
+		if payment.Receiver != e.Address() {
+			return fmt.Errorf("payment receiver %s is not the enforcer %s", payment.Receiver, e.Address())
+		}
+		if !payment.CloseRemainderTo.IsZero() {
+			return fmt.Errorf("payment must not close its sender")
+		}
+		signature = TransferAlgoPaymentSignature
+		args = []interface{}{t.AssetID, t.Amount, t.From, t.To, t.Policy.Recipient, t.Payment, t.Offer.Amount}
+	case types.AssetTransferTx:
+		if payment.AssetReceiver != e.Address() {
+			return fmt.Errorf("payment receiver %s is not the enforcer %s", payment.AssetReceiver, e.Address())
+		}
+		if !payment.AssetCloseTo.IsZero() || !payment.AssetSender.IsZero() {
+			return fmt.Errorf("payment must be a plain asset transfer")
+		}
+		signature = TransferAssetPaymentSignature
+		args = []interface{}{t.AssetID, t.Amount, t.From, t.To, t.Policy.Recipient, t.Payment, uint64(payment.XferAsset), t.Offer.Amount}
+	default:
+		return fmt.Errorf("payment of type %s is neither a payment nor an asset transfer", payment.Type)
+	}
+	return e.addMethodCall(atc, signature, args, withInnerFees(sp, transferInnerTxns))
+}
+
+// AddRoyaltyFreeMove adds moving amount of the asset assetID from from to to
+// without payment to atc, consuming the offer of offeredAmount of from. Sender
+// must be the administrator.
+func (e *Enforcer) AddRoyaltyFreeMove(atc *transaction.AtomicTransactionComposer, assetID, amount uint64, from, to types.Address, offeredAmount uint64, sp types.SuggestedParams) error {
+	if amount > offeredAmount {
+		return fmt.Errorf("move of %d exceeds the offered amount %d", amount, offeredAmount)
+	}
+	return e.addMethodCall(atc, RoyaltyFreeMoveSignature, []interface{}{assetID, amount, from, to, offeredAmount}, withInnerFees(sp, royaltyFreeMoveInnerTxns))
+}
+
+// simulate simulates the call of params without signatures, and decodes its
+// return value into v.
+func (e *Enforcer) simulate(ctx context.Context, client *algod.Client, params transaction.AddMethodCallParams, v interface{}) error {
+	params.Signer = transaction.EmptyTransactionSigner{}
+	var atc transaction.AtomicTransactionComposer
+	if err := atc.AddMethodCall(params); err != nil {
+		return err
+	}
+	response, err := atc.Simulate(client, ctx, models.SimulateRequest{AllowEmptySignatures: true})
+	if err != nil {
+		return err
+	}
+	result := response.MethodResults[0]
+	if result.DecodeError != nil {
+		return result.DecodeError
+	}
+	returnType, err := params.Method.Returns.GetTypeObject()
+	if err != nil {
+		return err
+	}
+	return abi.Unmarshal(returnType, result.RawReturnValue, v)
+}
+
+// GetPolicy returns the royalty policy of the enforcer by simulating a call to
+// get_policy.
+func (e *Enforcer) GetPolicy(ctx context.Context, client *algod.Client, sp types.SuggestedParams) (Policy, error) {
+	params, err := e.methodCallParams(GetPolicySignature, nil, sp)
+	if err != nil {
+		return Policy{}, err
+	}
+	var policy Policy
+	if err := e.simulate(ctx, client, params, &policy); err != nil {
+		return Policy{}, err
+	}
+	return policy, nil
+}
+
+// GetOffer returns the offer of from for the asset assetID by simulating a call
+// to get_offer.
+func (e *Enforcer) GetOffer(ctx context.Context, client *algod.Client, assetID uint64, from types.Address, sp types.SuggestedParams) (Offer, error) {
+	params, err := e.methodCallParams(GetOfferSignature, []interface{}{assetID, from}, sp)
+	if err != nil {
+		return Offer{}, err
+	}
+	var offer Offer
+	if err := e.simulate(ctx, client, params, &offer); err != nil {
+		return Offer{}, err
+	}
+	return offer, nil
+}
+
+// GetAdministrator returns the administrator of the enforcer by simulating a
+// call to get_administrator.
+func (e *Enforcer) GetAdministrator(ctx context.Context, client *algod.Client, sp types.SuggestedParams) (types.Address, error) {
+	params, err := e.methodCallParams(GetAdministratorSignature, nil, sp)
+	if err != nil {
+		return types.Address{}, err
+	}
+	var administrator types.Address
+	if err := e.simulate(ctx, client, params, &administrator); err != nil {
+		return types.Address{}, err
+	}
+	return administrator, nil
+}
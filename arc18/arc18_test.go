@@ -0,0 +1,162 @@
+package arc18
+
+import (
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+var sp = types.SuggestedParams{Fee: 1000, FirstRoundValid: 1, LastRoundValid: 1001, GenesisHash: make([]byte, 32), FlatFee: true, MinFee: 1000}
+
+func TestPolicySplit(t *testing.T) {
+	recipient := crypto.GenerateAccount().Address
+	royalty, seller, err := Policy{Recipient: recipient, Basis: 250}.Split(1000001)
+	require.NoError(t, err)
+	require.Equal(t, uint64(25000), royalty)
+	require.Equal(t, uint64(975001), seller)
+
+	royalty, seller, err = Policy{Basis: BasisPoints}.Split(^uint64(0))
+	require.NoError(t, err)
+	require.Equal(t, ^uint64(0), royalty)
+	require.Zero(t, seller)
+
+	royalty, _, err = Policy{Basis: 9999}.Split(^uint64(0))
+	require.NoError(t, err)
+	require.Equal(t, uint64(18444899399302180659), royalty)
+
+	_, _, err = Policy{Basis: BasisPoints + 1}.Split(1)
+	require.Error(t, err)
+}
+
+func TestValidateOffer(t *testing.T) {
+	marketplace := crypto.GenerateAccount().Address
+	require.NoError(t, ValidateOffer(marketplace, 1, 1))
+	require.Error(t, ValidateOffer(marketplace, 2, 1))
+	require.Error(t, ValidateOffer(types.Address{}, 1, 1))
+
+	offer := Offer{AuthAddress: marketplace, Amount: 3}
+	require.NoError(t, offer.ValidateTransfer(marketplace, 3))
+	require.Error(t, offer.ValidateTransfer(marketplace, 4))
+	require.Error(t, offer.ValidateTransfer(marketplace, 0))
+	require.Error(t, offer.ValidateTransfer(crypto.GenerateAccount().Address, 1))
+	require.Error(t, Offer{}.ValidateTransfer(marketplace, 1))
+}
+
+func TestAddTransfer(t *testing.T) {
+	marketplace := crypto.GenerateAccount()
+	seller := crypto.GenerateAccount().Address
+	buyer := crypto.GenerateAccount()
+	recipient := crypto.GenerateAccount().Address
+	enforcer := NewEnforcer(9, marketplace.Address, transaction.BasicAccountTransactionSigner{Account: marketplace})
+	buyerSigner := transaction.BasicAccountTransactionSigner{Account: buyer}
+
+	pay, err := transaction.MakePaymentTxn(buyer.Address.String(), enforcer.Address().String(), 1000000, nil, "", sp)
+	require.NoError(t, err)
+	transfer := Transfer{
+		AssetID: 12,
+		Amount:  1,
+		From:    seller,
+		To:      buyer.Address,
+		Policy:  Policy{Recipient: recipient, Basis: 500},
+		Offer:   Offer{AuthAddress: marketplace.Address, Amount: 1},
+		Payment: transaction.TransactionWithSigner{Txn: pay, Signer: buyerSigner},
+	}
+
+	var atc transaction.AtomicTransactionComposer
+	require.NoError(t, enforcer.AddTransfer(&atc, transfer, sp))
+
+	axfer, err := transaction.MakeAssetTransferTxn(buyer.Address.String(), enforcer.Address().String(), 50, nil, sp, "", 34)
+	require.NoError(t, err)
+	assetTransfer := transfer
+	assetTransfer.Payment = transaction.TransactionWithSigner{Txn: axfer, Signer: buyerSigner}
+	require.NoError(t, enforcer.AddTransfer(&atc, assetTransfer, sp))
+
+	group, err := atc.BuildGroup()
+	require.NoError(t, err)
+	require.Len(t, group, 4)
+
+	algoCall := group[1].Txn
+	method, err := abi.MethodFromSignature(TransferAlgoPaymentSignature)
+	require.NoError(t, err)
+	require.Equal(t, method.GetSelector(), algoCall.ApplicationArgs[0])
+	require.Equal(t, types.MicroAlgos(4000), algoCall.Fee)
+	require.Equal(t, []types.Address{seller, buyer.Address, recipient}, algoCall.Accounts)
+	require.Equal(t, []types.AssetIndex{12}, algoCall.ForeignAssets)
+	require.Equal(t, uint64(1), binary.BigEndian.Uint64(algoCall.ApplicationArgs[6]))
+
+	assetCall := group[3].Txn
+	method, err = abi.MethodFromSignature(TransferAssetPaymentSignature)
+	require.NoError(t, err)
+	require.Equal(t, method.GetSelector(), assetCall.ApplicationArgs[0])
+	require.Equal(t, []types.AssetIndex{12, 34}, assetCall.ForeignAssets)
+
+	invalid := transfer
+	invalid.Amount = 2
+	require.Error(t, enforcer.AddTransfer(&transaction.AtomicTransactionComposer{}, invalid, sp))
+	invalid = transfer
+	invalid.Payment.Txn.Receiver = seller
+	require.Error(t, enforcer.AddTransfer(&transaction.AtomicTransactionComposer{}, invalid, sp))
+	invalid = transfer
+	invalid.Offer.AuthAddress = seller
+	require.Error(t, enforcer.AddTransfer(&transaction.AtomicTransactionComposer{}, invalid, sp))
+}
+
+func TestAddAdministration(t *testing.T) {
+	admin := crypto.GenerateAccount()
+	enforcer := NewEnforcer(9, admin.Address, transaction.BasicAccountTransactionSigner{Account: admin})
+	recipient := crypto.GenerateAccount().Address
+	from := crypto.GenerateAccount().Address
+	to := crypto.GenerateAccount().Address
+
+	var atc transaction.AtomicTransactionComposer
+	require.Error(t, enforcer.AddSetPolicy(&atc, Policy{Recipient: recipient, Basis: BasisPoints + 1}, sp))
+	require.NoError(t, enforcer.AddSetPolicy(&atc, Policy{Recipient: recipient, Basis: 250}, sp))
+	require.NoError(t, enforcer.AddSetPaymentAsset(&atc, 34, true, sp))
+	require.NoError(t, enforcer.AddOffer(&atc, 12, 1, to, Offer{}, sp))
+	require.Error(t, enforcer.AddRoyaltyFreeMove(&atc, 12, 2, from, to, 1, sp))
+	require.NoError(t, enforcer.AddRoyaltyFreeMove(&atc, 12, 1, from, to, 1, sp))
+
+	group, err := atc.BuildGroup()
+	require.NoError(t, err)
+	require.Len(t, group, 4)
+	require.Equal(t, uint64(250), binary.BigEndian.Uint64(group[0].Txn.ApplicationArgs[1]))
+	require.Equal(t, []byte{1}, group[0].Txn.ApplicationArgs[2])
+	require.Equal(t, types.MicroAlgos(2000), group[1].Txn.Fee)
+	require.Equal(t, to[:], group[2].Txn.ApplicationArgs[3])
+	require.Equal(t, make([]byte, 32), group[2].Txn.ApplicationArgs[5])
+	require.Equal(t, []types.Address{from, to}, group[3].Txn.Accounts)
+}
+
+func TestGetPolicy(t *testing.T) {
+	recipient := crypto.GenerateAccount().Address
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/transactions/simulate", r.URL.Path)
+		returnType, err := abi.TypeOf("(address,uint64)")
+		require.NoError(t, err)
+		encoded, err := abi.Marshal(returnType, Policy{Recipient: recipient, Basis: 250})
+		require.NoError(t, err)
+		log := append(append([]byte(nil), abi.ReturnPrefix...), encoded...)
+		result := models.SimulateTransactionResult{TxnResult: models.PendingTransactionResponse{Logs: [][]byte{log}}}
+		w.Write(json.Encode(models.SimulateResponse{TxnGroups: []models.SimulateTransactionGroupResult{{TxnResults: []models.SimulateTransactionResult{result}}}}))
+	}))
+	defer server.Close()
+	client, err := algod.MakeClient(server.URL, "")
+	require.NoError(t, err)
+
+	enforcer := NewEnforcer(9, crypto.GenerateAccount().Address, nil)
+	policy, err := enforcer.GetPolicy(context.Background(), client, sp)
+	require.NoError(t, err)
+	require.Equal(t, Policy{Recipient: recipient, Basis: 250}, policy)
+}
@@ -0,0 +1,185 @@
+// Package arc19 resolves the template-ipfs:// URLs of ARC-19 assets, whose
+// IPFS content identifier is stored in the reserve address so that the
+// manager can update the metadata of the asset, and builds them for minting.
+package arc19
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+const (
+	// templateScheme is the scheme of ARC-19 URLs, and ipfsScheme that of the
+	// URLs they resolve to.
+	templateScheme = "template-ipfs://"
+	ipfsScheme     = "ipfs://"
+
+	// codecRaw and codecDagPb are the multicodecs ARC-19 supports, and
+	// hashSha256 its multihash.
+	codecRaw   = 0x55
+	codecDagPb = 0x70
+	hashSha256 = 0x12
+)
+
+var codecs = map[string]uint64{"raw": codecRaw, "dag-pb": codecDagPb}
+
+// templatePattern matches an ARC-19 URL:
+// template-ipfs://{ipfscid:<version>:<codec>:reserve:sha2-256} followed by an
+// optional path or fragment.
+var templatePattern = regexp.MustCompile(`^template-ipfs://\{ipfscid:(0|1):(raw|dag-pb):reserve:sha2-256\}(.*)$`)
+
+var (
+	base32Encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+// IsTemplateURL returns whether url is an ARC-19 template URL.
+func IsTemplateURL(url string) bool {
+	return strings.HasPrefix(url, templateScheme)
+}
+
+// ResolveURL returns the ipfs:// URL of the ARC-19 template URL templateURL of
+// an asset with the reserve address reserve, keeping the path or fragment
+// following the template, such as #arc3.
+func ResolveURL(templateURL string, reserve types.Address) (string, error) {
+	match := templatePattern.FindStringSubmatch(templateURL)
+	if match == nil {
+		return "", fmt.Errorf("invalid ARC-19 template URL %q", templateURL)
+	}
+	cid, err := makeCID(match[1] == "1", codecs[match[2]], reserve[:])
+	if err != nil {
+		return "", err
+	}
+	return ipfsScheme + cid + match[3], nil
+}
+
+// ResolveAssetURL returns the URL of an asset, resolved with its reserve
+// address when it is an ARC-19 template URL.
+func ResolveAssetURL(params types.AssetParams) (string, error) {
+	if !IsTemplateURL(params.URL) {
+		return params.URL, nil
+	}
+	return ResolveURL(params.URL, params.Reserve)
+}
+
+// makeCID returns the content identifier of a sha2-256 digest.
+func makeCID(v1 bool, codec uint64, digest []byte) (string, error) {
+	multihash := append([]byte{hashSha256, byte(len(digest))}, digest...)
+	if !v1 {
+		if codec != codecDagPb {
+			return "", fmt.Errorf("CIDv0 requires the dag-pb codec")
+		}
+		return base58Encode(multihash), nil
+	}
+	cid := make([]byte, 2*binary.MaxVarintLen64, 2*binary.MaxVarintLen64+len(multihash))
+	n := binary.PutUvarint(cid, 1)
+	n += binary.PutUvarint(cid[n:], codec)
+	cid = append(cid[:n], multihash...)
+	// the b prefix is the multibase of lowercase base32
+	return "b" + strings.ToLower(base32Encoding.EncodeToString(cid)), nil
+}
+
+// TemplateURL returns the ARC-19 template URL and reserve address of an asset
+// with the IPFS content identifier cid, a CIDv0 or a base32 CIDv1 of a
+// sha2-256 digest, for minting the asset or updating its metadata. suffix is
+// appended to the URL, such as "#arc3" for ARC-3 metadata.
+func TemplateURL(cid, suffix string) (string, types.Address, error) {
+	v1, codec, digest, err := parseCID(cid)
+	if err != nil {
+		return "", types.Address{}, err
+	}
+	version := 0
+	if v1 {
+		version = 1
+	}
+	codecName := "raw"
+	if codec == codecDagPb {
+		codecName = "dag-pb"
+	}
+	var reserve types.Address
+	copy(reserve[:], digest)
+	url := fmt.Sprintf("%s{ipfscid:%d:%s:reserve:sha2-256}%s", templateScheme, version, codecName, suffix)
+	return url, reserve, nil
+}
+
+// parseCID returns the version, codec and sha2-256 digest of a content
+// identifier.
+func parseCID(cid string) (v1 bool, codec uint64, digest []byte, err error) {
+	var multihash []byte
+	switch {
+	case strings.HasPrefix(cid, "Qm"):
+		if multihash, err = base58Decode(cid); err != nil {
+			return false, 0, nil, err
+		}
+		codec = codecDagPb
+	case strings.HasPrefix(cid, "b"):
+		raw, err := base32Encoding.DecodeString(strings.ToUpper(cid[1:]))
+		if err != nil {
+			return false, 0, nil, fmt.Errorf("invalid CID %q: %w", cid, err)
+		}
+		r := bytes.NewReader(raw)
+		version, err := binary.ReadUvarint(r)
+		if err != nil || version != 1 {
+			return false, 0, nil, fmt.Errorf("invalid CID %q: unsupported version", cid)
+		}
+		if codec, err = binary.ReadUvarint(r); err != nil {
+			return false, 0, nil, fmt.Errorf("invalid CID %q: %w", cid, err)
+		}
+		multihash = raw[len(raw)-r.Len():]
+		v1 = true
+	default:
+		return false, 0, nil, fmt.Errorf("unsupported CID %q: only CIDv0 and base32 CIDv1 are supported", cid)
+	}
+	if codec != codecRaw && codec != codecDagPb {
+		return false, 0, nil, fmt.Errorf("unsupported CID %q: codec %#x is neither raw nor dag-pb", cid, codec)
+	}
+	if len(multihash) != 2+len(types.Address{}) || multihash[0] != hashSha256 || int(multihash[1]) != len(types.Address{}) {
+		return false, 0, nil, fmt.Errorf("unsupported CID %q: the hash is not sha2-256", cid)
+	}
+	return v1, codec, multihash[2:], nil
+}
+
+func base58Encode(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	base := big.NewInt(58)
+	var out []byte
+	for n.Sign() > 0 {
+		mod := new(big.Int)
+		n.DivMod(n, base, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+func base58Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	base := big.NewInt(58)
+	zeros := 0
+	for i, c := range s {
+		digit := strings.IndexRune(base58Alphabet, c)
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+		if digit == 0 && i == zeros {
+			zeros++
+		}
+		n.Mul(n, base).Add(n, big.NewInt(int64(digit)))
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
@@ -0,0 +1,70 @@
+package arc19
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+const (
+	cidV0 = "QmPZ9gcCEpqKTo6aq61g2nXGUhM4iCL3ewB6LDXZCtioEB"
+	cidV1 = "bafybeiasb5vpmaounyilfuxbd3lryvosl4yefqrfahsb2esg46q6tu6y5q"
+)
+
+func TestTemplateURL(t *testing.T) {
+	url, reserve, err := TemplateURL(cidV0, "#arc3")
+	require.NoError(t, err)
+	require.Equal(t, "template-ipfs://{ipfscid:0:dag-pb:reserve:sha2-256}#arc3", url)
+	require.True(t, IsTemplateURL(url))
+
+	resolved, err := ResolveURL(url, reserve)
+	require.NoError(t, err)
+	require.Equal(t, "ipfs://"+cidV0+"#arc3", resolved)
+
+	resolved, err = ResolveAssetURL(types.AssetParams{URL: url, Reserve: reserve})
+	require.NoError(t, err)
+	require.Equal(t, "ipfs://"+cidV0+"#arc3", resolved)
+	resolved, err = ResolveAssetURL(types.AssetParams{URL: "https://example.com"})
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com", resolved)
+
+	// the CIDv1 of the same content has the same digest
+	url, reserveV1, err := TemplateURL(cidV1, "")
+	require.NoError(t, err)
+	require.Equal(t, "template-ipfs://{ipfscid:1:dag-pb:reserve:sha2-256}", url)
+	require.Equal(t, reserve, reserveV1)
+	resolved, err = ResolveURL(url, reserve)
+	require.NoError(t, err)
+	require.Equal(t, "ipfs://"+cidV1, resolved)
+
+	resolved, err = ResolveURL("template-ipfs://{ipfscid:1:raw:reserve:sha2-256}/metadata.json", reserve)
+	require.NoError(t, err)
+	require.Equal(t, "ipfs://bafkreiasb5vpmaounyilfuxbd3lryvosl4yefqrfahsb2esg46q6tu6y5q/metadata.json", resolved)
+	url, reserveRaw, err := TemplateURL(resolved[len("ipfs://"):len(resolved)-len("/metadata.json")], "/metadata.json")
+	require.NoError(t, err)
+	require.Equal(t, "template-ipfs://{ipfscid:1:raw:reserve:sha2-256}/metadata.json", url)
+	require.Equal(t, reserve, reserveRaw)
+}
+
+func TestErrors(t *testing.T) {
+	var reserve types.Address
+	for _, url := range []string{
+		"ipfs://" + cidV0,
+		"template-ipfs://{ipfscid:2:raw:reserve:sha2-256}",
+		"template-ipfs://{ipfscid:1:json:reserve:sha2-256}",
+		"template-ipfs://{ipfscid:1:raw:manager:sha2-256}",
+		"template-ipfs://{ipfscid:1:raw:reserve:sha3-256}",
+	} {
+		_, err := ResolveURL(url, reserve)
+		require.Error(t, err, url)
+	}
+	_, err := ResolveURL("template-ipfs://{ipfscid:0:raw:reserve:sha2-256}", reserve)
+	require.Error(t, err)
+
+	for _, cid := range []string{"", "zb2rhe5P4gXftAwvA4eXQ5HJwsER2owDyS9sKaQRRVQPn93bA", "Qm0", "bafy", "b" + cidV1[2:]} {
+		_, _, err := TemplateURL(cid, "")
+		require.Error(t, err, cid)
+	}
+}
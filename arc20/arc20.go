@@ -0,0 +1,152 @@
+// Package arc20 calls the controlling app of ARC-20 smart ASAs. A smart ASA is
+// an asset whose transfers, freezes and clawbacks are made through method calls
+// to an app holding its clawback, freeze and manager roles, instead of through
+// asset transactions.
+package arc20
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// The signatures of the methods of ARC-20 apps called by Client.
+const (
+	AssetTransferSignature        = "asset_transfer(asset,uint64,account,account)void"
+	AssetFreezeSignature          = "asset_freeze(asset,bool)void"
+	AccountFreezeSignature        = "account_freeze(asset,account,bool)void"
+	AssetAppOptInSignature        = "asset_app_optin(asset,axfer)void"
+	AssetAppCloseOutSignature     = "asset_app_closeout(asset,account)void"
+	AssetDestroySignature         = "asset_destroy(asset)void"
+	GetAssetIsFrozenSignature     = "get_asset_is_frozen(asset)bool"
+	GetAccountIsFrozenSignature   = "get_account_is_frozen(asset,account)bool"
+	GetCirculatingSupplySignature = "get_circulating_supply(asset)uint64"
+)
+
+// Client adds calls to the controlling app of an ARC-20 smart ASA to an
+// AtomicTransactionComposer. The results of the getters are the return values
+// of their method calls once the composer is executed or simulated.
+type Client struct {
+	// The ID of the controlling app
+	AppID uint64
+	// The ID of the smart ASA
+	AssetID uint64
+	// The sender of the method calls
+	Sender types.Address
+	// A transaction Signer that can authorize the method calls from Sender
+	Signer transaction.TransactionSigner
+}
+
+// NewClient returns a Client calling the app appID controlling the smart ASA
+// assetID from sender.
+func NewClient(appID, assetID uint64, sender types.Address, signer transaction.TransactionSigner) *Client {
+	return &Client{AppID: appID, AssetID: assetID, Sender: sender, Signer: signer}
+}
+
+// addMethodCall adds a call to the method with the signature signature to atc.
+func (c *Client) addMethodCall(atc *transaction.AtomicTransactionComposer, signature string, args []interface{}, onComplete types.OnCompletion, sp types.SuggestedParams) error {
+	method, err := abi.MethodFromSignature(signature)
+	if err != nil {
+		return err
+	}
+	return atc.AddMethodCall(transaction.AddMethodCallParams{
+		AppID:           c.AppID,
+		Method:          method,
+		MethodArgs:      args,
+		Sender:          c.Sender,
+		SuggestedParams: sp,
+		OnComplete:      onComplete,
+		Signer:          c.Signer,
+	})
+}
+
+// AddTransfer adds a transfer of amount units of the smart ASA from Sender to
+// receiver to atc.
+func (c *Client) AddTransfer(atc *transaction.AtomicTransactionComposer, receiver types.Address, amount uint64, sp types.SuggestedParams) error {
+	return c.addMethodCall(atc, AssetTransferSignature, []interface{}{c.AssetID, amount, c.Sender, receiver}, types.NoOpOC, sp)
+}
+
+// AddClawback adds a transfer of amount units of the smart ASA from holder to
+// receiver to atc. Sender must be the clawback address of the smart ASA.
+func (c *Client) AddClawback(atc *transaction.AtomicTransactionComposer, holder, receiver types.Address, amount uint64, sp types.SuggestedParams) error {
+	if holder == c.Sender {
+		return fmt.Errorf("the holder of a clawback must not be its sender %s", holder)
+	}
+	return c.addMethodCall(atc, AssetTransferSignature, []interface{}{c.AssetID, amount, holder, receiver}, types.NoOpOC, sp)
+}
+
+// AddMint adds a transfer of amount units of the smart ASA from the account
+// of the controlling app, which holds its uncirculated supply, to receiver to
+// atc. The app decides which senders can mint, such as the reserve address of
+// the smart ASA.
+func (c *Client) AddMint(atc *transaction.AtomicTransactionComposer, receiver types.Address, amount uint64, sp types.SuggestedParams) error {
+	return c.addMethodCall(atc, AssetTransferSignature, []interface{}{c.AssetID, amount, c.appAddress(), receiver}, types.NoOpOC, sp)
+}
+
+// AddBurn adds a transfer of amount units of the smart ASA from holder back to
+// the account of the controlling app to atc. The app decides which senders can
+// burn, such as the reserve or clawback address of the smart ASA.
+func (c *Client) AddBurn(atc *transaction.AtomicTransactionComposer, holder types.Address, amount uint64, sp types.SuggestedParams) error {
+	return c.addMethodCall(atc, AssetTransferSignature, []interface{}{c.AssetID, amount, holder, c.appAddress()}, types.NoOpOC, sp)
+}
+
+// AddAssetFreeze adds a freeze or unfreeze of every holding of the smart ASA
+// to atc. Sender must be the freeze address of the smart ASA.
+func (c *Client) AddAssetFreeze(atc *transaction.AtomicTransactionComposer, frozen bool, sp types.SuggestedParams) error {
+	return c.addMethodCall(atc, AssetFreezeSignature, []interface{}{c.AssetID, frozen}, types.NoOpOC, sp)
+}
+
+// AddAccountFreeze adds a freeze or unfreeze of the holding of account to atc.
+// Sender must be the freeze address of the smart ASA.
+func (c *Client) AddAccountFreeze(atc *transaction.AtomicTransactionComposer, account types.Address, frozen bool, sp types.SuggestedParams) error {
+	return c.addMethodCall(atc, AccountFreezeSignature, []interface{}{c.AssetID, account, frozen}, types.NoOpOC, sp)
+}
+
+// AddOptIn adds an opt in of Sender to the controlling app to atc, preceded by
+// the opt in of Sender to the smart ASA the app requires.
+func (c *Client) AddOptIn(atc *transaction.AtomicTransactionComposer, sp types.SuggestedParams) error {
+	optIn, err := transaction.MakeAssetAcceptanceTxn(c.Sender.String(), nil, sp, c.AssetID)
+	if err != nil {
+		return err
+	}
+	txn := transaction.TransactionWithSigner{Txn: optIn, Signer: c.Signer}
+	return c.addMethodCall(atc, AssetAppOptInSignature, []interface{}{c.AssetID, txn}, types.OptInOC, sp)
+}
+
+// AddCloseOut adds a close out of Sender from the controlling app to atc,
+// closing its holding of the smart ASA to closeTo.
+func (c *Client) AddCloseOut(atc *transaction.AtomicTransactionComposer, closeTo types.Address, sp types.SuggestedParams) error {
+	return c.addMethodCall(atc, AssetAppCloseOutSignature, []interface{}{c.AssetID, closeTo}, types.CloseOutOC, sp)
+}
+
+// AddDestroy adds the destruction of the smart ASA to atc. Sender must be the
+// manager address of the smart ASA.
+func (c *Client) AddDestroy(atc *transaction.AtomicTransactionComposer, sp types.SuggestedParams) error {
+	return c.addMethodCall(atc, AssetDestroySignature, []interface{}{c.AssetID}, types.NoOpOC, sp)
+}
+
+// AddGetAssetIsFrozen adds a call returning whether the smart ASA is frozen
+// to atc.
+func (c *Client) AddGetAssetIsFrozen(atc *transaction.AtomicTransactionComposer, sp types.SuggestedParams) error {
+	return c.addMethodCall(atc, GetAssetIsFrozenSignature, []interface{}{c.AssetID}, types.NoOpOC, sp)
+}
+
+// AddGetAccountIsFrozen adds a call returning whether the holding of account
+// is frozen to atc.
+func (c *Client) AddGetAccountIsFrozen(atc *transaction.AtomicTransactionComposer, account types.Address, sp types.SuggestedParams) error {
+	return c.addMethodCall(atc, GetAccountIsFrozenSignature, []interface{}{c.AssetID, account}, types.NoOpOC, sp)
+}
+
+// AddGetCirculatingSupply adds a call returning the circulating supply of the
+// smart ASA to atc.
+func (c *Client) AddGetCirculatingSupply(atc *transaction.AtomicTransactionComposer, sp types.SuggestedParams) error {
+	return c.addMethodCall(atc, GetCirculatingSupplySignature, []interface{}{c.AssetID}, types.NoOpOC, sp)
+}
+
+// appAddress returns the address of the controlling app.
+func (c *Client) appAddress() types.Address {
+	return crypto.GetApplicationAddress(c.AppID)
+}
@@ -0,0 +1,78 @@
+package arc20
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+func selector(t *testing.T, signature string) []byte {
+	method, err := abi.MethodFromSignature(signature)
+	require.NoError(t, err)
+	return method.GetSelector()
+}
+
+func TestClient(t *testing.T) {
+	account := crypto.GenerateAccount()
+	holder := crypto.GenerateAccount().Address
+	receiver := crypto.GenerateAccount().Address
+	sp := types.SuggestedParams{Fee: 1000, FirstRoundValid: 1, LastRoundValid: 1001, GenesisHash: make([]byte, 32), FlatFee: true}
+	client := NewClient(12, 34, account.Address, transaction.BasicAccountTransactionSigner{Account: account})
+
+	var atc transaction.AtomicTransactionComposer
+	require.NoError(t, client.AddOptIn(&atc, sp))
+	require.NoError(t, client.AddTransfer(&atc, receiver, 5, sp))
+	require.NoError(t, client.AddClawback(&atc, holder, receiver, 6, sp))
+	require.Error(t, client.AddClawback(&atc, account.Address, receiver, 6, sp))
+	require.NoError(t, client.AddAccountFreeze(&atc, holder, true, sp))
+	require.NoError(t, client.AddMint(&atc, receiver, 7, sp))
+	require.NoError(t, client.AddGetCirculatingSupply(&atc, sp))
+	require.NoError(t, client.AddCloseOut(&atc, holder, sp))
+
+	group, err := atc.BuildGroup()
+	require.NoError(t, err)
+	require.Len(t, group, 8)
+	for _, txn := range group[1:] {
+		require.Equal(t, types.AppIndex(12), txn.Txn.ApplicationID)
+		require.Equal(t, account.Address, txn.Txn.Sender)
+		require.Equal(t, []types.AssetIndex{34}, txn.Txn.ForeignAssets)
+	}
+
+	optIn := group[0].Txn
+	require.Equal(t, types.AssetTransferTx, optIn.Type)
+	require.Equal(t, types.AssetIndex(34), optIn.XferAsset)
+	require.Equal(t, account.Address, optIn.AssetReceiver)
+	require.Zero(t, optIn.AssetAmount)
+	require.Equal(t, types.OptInOC, group[1].Txn.OnCompletion)
+	require.Equal(t, [][]byte{selector(t, AssetAppOptInSignature), {0}}, group[1].Txn.ApplicationArgs)
+
+	transfer := group[2].Txn
+	require.Equal(t, selector(t, AssetTransferSignature), transfer.ApplicationArgs[0])
+	require.Equal(t, uint64(5), binary.BigEndian.Uint64(transfer.ApplicationArgs[2]))
+	require.Equal(t, []byte{0}, transfer.ApplicationArgs[3])
+	require.Equal(t, []byte{1}, transfer.ApplicationArgs[4])
+	require.Equal(t, []types.Address{receiver}, transfer.Accounts)
+
+	clawback := group[3].Txn
+	require.Equal(t, uint64(6), binary.BigEndian.Uint64(clawback.ApplicationArgs[2]))
+	require.Equal(t, []types.Address{holder, receiver}, clawback.Accounts)
+
+	freeze := group[4].Txn
+	require.Equal(t, [][]byte{selector(t, AccountFreezeSignature), {0}, {1}, {0x80}}, freeze.ApplicationArgs)
+	require.Equal(t, []types.Address{holder}, freeze.Accounts)
+
+	mint := group[5].Txn
+	require.Equal(t, []types.Address{crypto.GetApplicationAddress(12), receiver}, mint.Accounts)
+
+	require.Equal(t, [][]byte{selector(t, GetCirculatingSupplySignature), {0}}, group[6].Txn.ApplicationArgs)
+
+	closeOut := group[7].Txn
+	require.Equal(t, types.CloseOutOC, closeOut.OnCompletion)
+	require.Equal(t, []types.Address{holder}, closeOut.Accounts)
+}
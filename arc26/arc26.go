@@ -0,0 +1,171 @@
+// Package arc26 builds and parses the algorand:// payment URIs of ARC-26,
+// which wallets read from QR codes to prepare payments and asset transfers.
+package arc26
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// Scheme is the scheme of ARC-26 URIs.
+const Scheme = "algorand"
+
+// The query parameters of ARC-26 URIs, in the order Encode writes them.
+const (
+	paramLabel  = "label"
+	paramAmount = "amount"
+	paramAsset  = "asset"
+	paramNote   = "note"
+	paramXNote  = "xnote"
+)
+
+// URI is an ARC-26 payment URI, of the form
+// algorand://<address>?label=<label>&amount=<amount>&asset=<asset>&note=<note>.
+type URI struct {
+	// Address is the receiver of the payment
+	Address types.Address
+	// Label is a name for the receiver, for the wallet to show
+	Label string
+	// Amount is the amount in microAlgos, or in base units of the asset when
+	// Asset is set
+	Amount uint64
+	// Asset is the ID of the asset to transfer, or 0 for a payment in Algos
+	Asset uint64
+	// Note is a note the user can change in the wallet, and XNote a note
+	// the user cannot change. At most one of them is set.
+	Note  string
+	XNote string
+}
+
+func (u URI) validate() error {
+	if u.Note != "" && u.XNote != "" {
+		return fmt.Errorf("an ARC-26 URI cannot have both a note and an xnote")
+	}
+	maxLen := types.Consensus[types.ConsensusCurrentVersion].MaxTxnNoteBytes
+	if len(u.Note) > maxLen || len(u.XNote) > maxLen {
+		return fmt.Errorf("the note of an ARC-26 URI exceeds the maximum note length %d", maxLen)
+	}
+	return nil
+}
+
+// Encode returns the URI as a string. The amount is written when it is not
+// zero or the URI transfers an asset, so that an amount of 0 of an asset
+// requests opting in to it.
+func (u URI) Encode() (string, error) {
+	if err := u.validate(); err != nil {
+		return "", err
+	}
+	var params []string
+	add := func(name, value string) {
+		params = append(params, name+"="+escape(value))
+	}
+	if u.Label != "" {
+		add(paramLabel, u.Label)
+	}
+	if u.Amount != 0 || u.Asset != 0 {
+		add(paramAmount, strconv.FormatUint(u.Amount, 10))
+	}
+	if u.Asset != 0 {
+		add(paramAsset, strconv.FormatUint(u.Asset, 10))
+	}
+	if u.Note != "" {
+		add(paramNote, u.Note)
+	}
+	if u.XNote != "" {
+		add(paramXNote, u.XNote)
+	}
+
+	uri := Scheme + "://" + u.Address.String()
+	if len(params) > 0 {
+		uri += "?" + strings.Join(params, "&")
+	}
+	return uri, nil
+}
+
+// escape percent-encodes a query value, with spaces as %20 rather than +.
+func escape(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+// Parse parses an ARC-26 URI. It is strict: the address must be valid with
+// its checksum, numbers must be decimal without sign, and parameters unknown
+// to ARC-26 or given twice are errors.
+func Parse(uri string) (URI, error) {
+	scheme := Scheme + "://"
+	if len(uri) < len(scheme) || !strings.EqualFold(uri[:len(scheme)], scheme) {
+		return URI{}, fmt.Errorf("ARC-26 URI must start with %s", scheme)
+	}
+	rest := uri[len(scheme):]
+	query := ""
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		rest, query = rest[:i], rest[i+1:]
+	}
+	if strings.ContainsAny(rest, "/#") {
+		return URI{}, fmt.Errorf("ARC-26 URI must only have an address before its query")
+	}
+
+	var u URI
+	var err error
+	if u.Address, err = types.DecodeAddress(rest); err != nil {
+		return URI{}, fmt.Errorf("invalid ARC-26 address %q: %w", rest, err)
+	}
+	if query == "" {
+		return u, nil
+	}
+
+	seen := map[string]bool{}
+	for _, param := range strings.Split(query, "&") {
+		eq := strings.IndexByte(param, '=')
+		if eq < 0 {
+			return URI{}, fmt.Errorf("ARC-26 parameter %q has no value", param)
+		}
+		name := param[:eq]
+		value, err := url.QueryUnescape(param[eq+1:])
+		if err != nil {
+			return URI{}, fmt.Errorf("invalid ARC-26 %s: %w", name, err)
+		}
+		if seen[name] {
+			return URI{}, fmt.Errorf("ARC-26 parameter %s is given twice", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case paramLabel:
+			u.Label = value
+		case paramAmount:
+			if u.Amount, err = parseUint(name, value); err != nil {
+				return URI{}, err
+			}
+		case paramAsset:
+			if u.Asset, err = parseUint(name, value); err != nil {
+				return URI{}, err
+			}
+			if u.Asset == 0 {
+				return URI{}, fmt.Errorf("ARC-26 asset must be a positive asset ID")
+			}
+		case paramNote:
+			u.Note = value
+		case paramXNote:
+			u.XNote = value
+		default:
+			return URI{}, fmt.Errorf("unknown ARC-26 parameter %s", name)
+		}
+	}
+	if err := u.validate(); err != nil {
+		return URI{}, err
+	}
+	return u, nil
+}
+
+// parseUint parses a decimal number, rejecting signs and leading zeros.
+func parseUint(name, value string) (uint64, error) {
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || len(value) > 1 && value[0] == '0' {
+		return 0, fmt.Errorf("invalid ARC-26 %s %q", name, value)
+	}
+	return n, nil
+}
@@ -0,0 +1,74 @@
+package arc26
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testAddress = "TMTAD6N22HCS2LKH7677L2KFLT3PAQWY6M4JFQFXQS32ECBFC23F57RYX4"
+
+func TestEncode(t *testing.T) {
+	address, err := types.DecodeAddress(testAddress)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		uri  URI
+		want string
+	}{
+		{"address", URI{Address: address}, "algorand://" + testAddress},
+		{"payment", URI{Address: address, Label: "Silvio Micali", Amount: 150500000, Note: "hello world & more"},
+			"algorand://" + testAddress + "?label=Silvio%20Micali&amount=150500000&note=hello%20world%20%26%20more"},
+		{"asset", URI{Address: address, Amount: 150, Asset: 45, XNote: "1+1=2"},
+			"algorand://" + testAddress + "?amount=150&asset=45&xnote=1%2B1%3D2"},
+		{"opt in", URI{Address: address, Asset: 45},
+			"algorand://" + testAddress + "?amount=0&asset=45"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			uri, err := test.uri.Encode()
+			require.NoError(t, err)
+			require.Equal(t, test.want, uri)
+
+			parsed, err := Parse(uri)
+			require.NoError(t, err)
+			require.Equal(t, test.uri, parsed)
+		})
+	}
+
+	_, err = URI{Address: address, Note: "a", XNote: "b"}.Encode()
+	require.Error(t, err)
+	_, err = URI{Address: address, Note: strings.Repeat("a", 1025)}.Encode()
+	require.Error(t, err)
+}
+
+func TestParse(t *testing.T) {
+	uri, err := Parse("ALGORAND://" + testAddress + "?label=Silvio+Micali&note=a%2Fb")
+	require.NoError(t, err)
+	require.Equal(t, "Silvio Micali", uri.Label)
+	require.Equal(t, "a/b", uri.Note)
+
+	for _, bad := range []string{
+		"https://" + testAddress,
+		"algorand://",
+		"algorand://" + testAddress[:57] + "A",
+		"algorand://" + testAddress + "/path",
+		"algorand://" + testAddress + "?amount",
+		"algorand://" + testAddress + "?amount=-1",
+		"algorand://" + testAddress + "?amount=+1",
+		"algorand://" + testAddress + "?amount=01",
+		"algorand://" + testAddress + "?amount=1.5",
+		"algorand://" + testAddress + "?amount=18446744073709551616",
+		"algorand://" + testAddress + "?asset=0",
+		"algorand://" + testAddress + "?amount=1&amount=2",
+		"algorand://" + testAddress + "?note=a&xnote=b",
+		"algorand://" + testAddress + "?fee=1000",
+		"algorand://" + testAddress + "?note=%zz",
+	} {
+		_, err := Parse(bad)
+		require.Error(t, err, bad)
+	}
+}
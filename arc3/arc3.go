@@ -0,0 +1,243 @@
+// Package arc3 builds and validates the JSON metadata of ARC-3 assets, such as
+// NFTs, and computes the metadata hash committing the asset to it.
+package arc3
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+const (
+	// URLSuffix marks the URL of an ARC-3 asset when its name is not marked.
+	URLSuffix = "#arc3"
+	// NameSuffix marks the name of an ARC-3 asset.
+	NameSuffix = "@arc3"
+
+	// localePlaceholder is replaced by each locale in the URI of the
+	// localized metadata.
+	localePlaceholder = "{locale}"
+
+	// integrityPrefix is the prefix of the sha256 integrity fields, which
+	// ARC-3 takes from Subresource Integrity.
+	integrityPrefix = "sha256-"
+)
+
+var (
+	// extraMetadataPrefix and metadataJSONPrefix are the prefixes of the
+	// metadata hash of metadata with extra metadata.
+	extraMetadataPrefix = []byte("arc0003/am")
+	metadataJSONPrefix  = []byte("arc0003/amj")
+
+	backgroundColorPattern = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
+	mimeTypePattern        = regexp.MustCompile(`^[a-zA-Z0-9!#$&^_.+-]+/[a-zA-Z0-9!#$&^_.+-]+$`)
+)
+
+// Metadata is the JSON metadata of an ARC-3 asset, found at its URL.
+type Metadata struct {
+	// Name is the name of the asset, which should match the asset name
+	Name string `json:"name,omitempty"`
+	// Decimals is the number of decimals of the asset, and must match the
+	// asset when set
+	Decimals    *uint32 `json:"decimals,omitempty"`
+	Description string  `json:"description,omitempty"`
+
+	Image          string `json:"image,omitempty"`
+	ImageIntegrity string `json:"image_integrity,omitempty"`
+	ImageMimetype  string `json:"image_mimetype,omitempty"`
+	// BackgroundColor is a color as six hexadecimal digits, without #
+	BackgroundColor string `json:"background_color,omitempty"`
+
+	ExternalURL          string `json:"external_url,omitempty"`
+	ExternalURLIntegrity string `json:"external_url_integrity,omitempty"`
+	ExternalURLMimetype  string `json:"external_url_mimetype,omitempty"`
+
+	AnimationURL          string `json:"animation_url,omitempty"`
+	AnimationURLIntegrity string `json:"animation_url_integrity,omitempty"`
+	AnimationURLMimetype  string `json:"animation_url_mimetype,omitempty"`
+
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	// ExtraMetadata is base64 encoded extra metadata, committed to by the
+	// metadata hash, see MetadataHash
+	ExtraMetadata string        `json:"extra_metadata,omitempty"`
+	Localization  *Localization `json:"localization,omitempty"`
+}
+
+// Localization locates the localized versions of metadata.
+type Localization struct {
+	// URI is the URI of the localized metadata, with {locale} standing for
+	// the locale
+	URI     string   `json:"uri"`
+	Default string   `json:"default"`
+	Locales []string `json:"locales"`
+	// Integrity are the sha256 integrity fields of the localized metadata
+	// by locale
+	Integrity map[string]string `json:"integrity,omitempty"`
+}
+
+// Integrity returns the sha256 integrity field of content, such as of an image
+// for ImageIntegrity.
+func Integrity(content []byte) string {
+	sum := sha256.Sum256(content)
+	return integrityPrefix + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// VerifyIntegrity returns an error when content does not match the sha256
+// integrity field integrity.
+func VerifyIntegrity(integrity string, content []byte) error {
+	if err := validateIntegrity(integrity); err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(Integrity(content)), []byte(integrity)) != 1 {
+		return fmt.Errorf("content does not match integrity %s", integrity)
+	}
+	return nil
+}
+
+func validateIntegrity(integrity string) error {
+	if !strings.HasPrefix(integrity, integrityPrefix) {
+		return fmt.Errorf("integrity %q is not a sha256 integrity", integrity)
+	}
+	digest, err := base64.StdEncoding.DecodeString(integrity[len(integrityPrefix):])
+	if err != nil || len(digest) != sha256.Size {
+		return fmt.Errorf("integrity %q is not a base64 sha256 digest", integrity)
+	}
+	return nil
+}
+
+// Validate checks the format of the fields of the metadata: the integrity,
+// mime type and color fields, the extra metadata, and the localization.
+func (m Metadata) Validate() error {
+	if m.Decimals != nil && *m.Decimals > types.AssetMaxNumberOfDecimals {
+		return fmt.Errorf("decimals %d exceed the maximum %d", *m.Decimals, types.AssetMaxNumberOfDecimals)
+	}
+	for _, field := range []struct{ name, url, integrity, mimetype string }{
+		{"image", m.Image, m.ImageIntegrity, m.ImageMimetype},
+		{"external_url", m.ExternalURL, m.ExternalURLIntegrity, m.ExternalURLMimetype},
+		{"animation_url", m.AnimationURL, m.AnimationURLIntegrity, m.AnimationURLMimetype},
+	} {
+		if field.url == "" && (field.integrity != "" || field.mimetype != "") {
+			return fmt.Errorf("%s integrity or mime type without %s", field.name, field.name)
+		}
+		if field.integrity != "" {
+			if err := validateIntegrity(field.integrity); err != nil {
+				return fmt.Errorf("%s: %w", field.name, err)
+			}
+		}
+		if field.mimetype != "" && !mimeTypePattern.MatchString(field.mimetype) {
+			return fmt.Errorf("%s mime type %q is invalid", field.name, field.mimetype)
+		}
+	}
+	if m.BackgroundColor != "" && !backgroundColorPattern.MatchString(m.BackgroundColor) {
+		return fmt.Errorf("background color %q is not six hexadecimal digits", m.BackgroundColor)
+	}
+	if _, err := m.extraMetadata(); err != nil {
+		return err
+	}
+	if l := m.Localization; l != nil {
+		if !strings.Contains(l.URI, localePlaceholder) {
+			return fmt.Errorf("localization uri %q does not contain %s", l.URI, localePlaceholder)
+		}
+		found := false
+		for _, locale := range l.Locales {
+			found = found || locale == l.Default
+		}
+		if !found {
+			return fmt.Errorf("default locale %q is not one of the locales", l.Default)
+		}
+		for locale, integrity := range l.Integrity {
+			if err := validateIntegrity(integrity); err != nil {
+				return fmt.Errorf("localization %s: %w", locale, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (m Metadata) extraMetadata() ([]byte, error) {
+	extra, err := base64.StdEncoding.DecodeString(m.ExtraMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("extra_metadata is not base64: %w", err)
+	}
+	return extra, nil
+}
+
+// JSON validates the metadata and returns it as JSON, the content to publish
+// at the URL of the asset and to compute the metadata hash of.
+func (m Metadata) JSON() ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+// ParseMetadata parses and validates the JSON metadata of an asset.
+func ParseMetadata(data []byte) (Metadata, error) {
+	var m Metadata
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Metadata{}, fmt.Errorf("invalid ARC-3 metadata: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return Metadata{}, err
+	}
+	return m, nil
+}
+
+// MetadataHash returns the metadata hash of an asset with the JSON metadata
+// metadataJSON: its SHA-256 hash, or when it has extra metadata
+// SHA-512/256("arc0003/am" || SHA-512/256("arc0003/amj" || metadataJSON) ||
+// extra metadata). The hash is of the exact bytes published, so any change of
+// formatting changes it.
+func MetadataHash(metadataJSON []byte) ([types.AssetMetadataHashLen]byte, error) {
+	m, err := ParseMetadata(metadataJSON)
+	if err != nil {
+		return [types.AssetMetadataHashLen]byte{}, err
+	}
+	if m.ExtraMetadata == "" {
+		return sha256.Sum256(metadataJSON), nil
+	}
+	extra, err := m.extraMetadata()
+	if err != nil {
+		return [types.AssetMetadataHashLen]byte{}, err
+	}
+	jsonHash := sha512.Sum512_256(append(append([]byte(nil), metadataJSONPrefix...), metadataJSON...))
+	return sha512.Sum512_256(bytes.Join([][]byte{extraMetadataPrefix, jsonHash[:], extra}, nil)), nil
+}
+
+// IsARC3 returns whether an asset with the name and URL is an ARC-3 asset:
+// its name is "arc3" or ends with "@arc3", or its URL ends with "#arc3".
+func IsARC3(assetName, url string) bool {
+	return assetName == "arc3" || strings.HasSuffix(assetName, NameSuffix) || strings.HasSuffix(url, URLSuffix)
+}
+
+// ValidateAsset checks that an asset is an ARC-3 asset with the JSON metadata
+// metadataJSON: that it is marked as ARC-3, that its metadata hash, when set,
+// commits to the metadata, and that its decimals match the metadata.
+func ValidateAsset(params types.AssetParams, metadataJSON []byte) error {
+	if !IsARC3(params.AssetName, params.URL) {
+		return fmt.Errorf("asset is not marked as ARC-3 by its name or URL")
+	}
+	m, err := ParseMetadata(metadataJSON)
+	if err != nil {
+		return err
+	}
+	if m.Decimals != nil && *m.Decimals != params.Decimals {
+		return fmt.Errorf("metadata decimals %d do not match asset decimals %d", *m.Decimals, params.Decimals)
+	}
+	hash, err := MetadataHash(metadataJSON)
+	if err != nil {
+		return err
+	}
+	if params.MetadataHash != ([types.AssetMetadataHashLen]byte{}) && hash != params.MetadataHash {
+		return fmt.Errorf("asset metadata hash does not match the metadata")
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package arc3
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/stretchr/testify/require"
+)
+
+func testMetadata() Metadata {
+	decimals := uint32(0)
+	image := []byte("image content")
+	return Metadata{
+		Name:            "My NFT",
+		Decimals:        &decimals,
+		Description:     "An NFT",
+		Image:           "ipfs://QmImage",
+		ImageIntegrity:  Integrity(image),
+		ImageMimetype:   "image/png",
+		BackgroundColor: "00ff00",
+		Properties:      map[string]interface{}{"rarity": "rare"},
+		Localization: &Localization{
+			URI:       "ipfs://QmLocales/{locale}.json",
+			Default:   "en",
+			Locales:   []string{"en", "fr"},
+			Integrity: map[string]string{"fr": Integrity([]byte("fr"))},
+		},
+	}
+}
+
+func TestIntegrity(t *testing.T) {
+	require.Equal(t, "sha256-LCa0a2j/xo/5m0U8HTBBNBNCLXBkg7+g+YpeiGJm564=", Integrity([]byte("foo")))
+	require.NoError(t, VerifyIntegrity(Integrity([]byte("foo")), []byte("foo")))
+	require.Error(t, VerifyIntegrity(Integrity([]byte("foo")), []byte("bar")))
+	require.Error(t, VerifyIntegrity("sha384-abc", []byte("foo")))
+}
+
+func TestMetadataJSON(t *testing.T) {
+	m := testMetadata()
+	data, err := m.JSON()
+	require.NoError(t, err)
+	parsed, err := ParseMetadata(data)
+	require.NoError(t, err)
+	require.Equal(t, m, parsed)
+
+	hash, err := MetadataHash(data)
+	require.NoError(t, err)
+	require.Equal(t, sha256.Sum256(data), hash)
+}
+
+func TestMetadataHashExtraMetadata(t *testing.T) {
+	m := testMetadata()
+	extra := []byte("extra")
+	m.ExtraMetadata = base64.StdEncoding.EncodeToString(extra)
+	data, err := m.JSON()
+	require.NoError(t, err)
+
+	hash, err := MetadataHash(data)
+	require.NoError(t, err)
+	jsonHash := sha512.Sum512_256(append([]byte("arc0003/amj"), data...))
+	expected := sha512.Sum512_256(bytes.Join([][]byte{[]byte("arc0003/am"), jsonHash[:], extra}, nil))
+	require.Equal(t, expected, hash)
+}
+
+func TestMetadataValidate(t *testing.T) {
+	tests := map[string]func(m *Metadata){
+		"decimals":         func(m *Metadata) { d := uint32(20); m.Decimals = &d },
+		"integrity":        func(m *Metadata) { m.ImageIntegrity = "sha256-abc" },
+		"integrity no url": func(m *Metadata) { m.AnimationURLIntegrity = Integrity(nil) },
+		"mime type":        func(m *Metadata) { m.ImageMimetype = "png" },
+		"background color": func(m *Metadata) { m.BackgroundColor = "#00ff00" },
+		"extra metadata":   func(m *Metadata) { m.ExtraMetadata = "not base64!" },
+		"locale uri":       func(m *Metadata) { m.Localization.URI = "ipfs://QmLocales/en.json" },
+		"default locale":   func(m *Metadata) { m.Localization.Default = "de" },
+		"locale integrity": func(m *Metadata) { m.Localization.Integrity["fr"] = "fr" },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := testMetadata()
+			mutate(&m)
+			require.Error(t, m.Validate())
+			_, err := m.JSON()
+			require.Error(t, err)
+		})
+	}
+
+	_, err := ParseMetadata([]byte(`{"name": 1}`))
+	require.Error(t, err)
+}
+
+func TestValidateAsset(t *testing.T) {
+	data, err := testMetadata().JSON()
+	require.NoError(t, err)
+	hash, err := MetadataHash(data)
+	require.NoError(t, err)
+
+	params := types.AssetParams{AssetName: "My NFT", URL: "ipfs://QmMetadata#arc3", MetadataHash: hash}
+	require.NoError(t, ValidateAsset(params, data))
+
+	require.True(t, IsARC3("arc3", ""))
+	require.True(t, IsARC3("My NFT@arc3", "ipfs://QmMetadata"))
+	require.False(t, IsARC3("My NFT", "ipfs://QmMetadata"))
+
+	unmarked := params
+	unmarked.URL = "ipfs://QmMetadata"
+	require.Error(t, ValidateAsset(unmarked, data))
+
+	decimals := params
+	decimals.Decimals = 2
+	require.Error(t, ValidateAsset(decimals, data))
+
+	wrongHash := params
+	wrongHash.MetadataHash[0] ^= 1
+	require.Error(t, ValidateAsset(wrongHash, data))
+
+	noHash := params
+	noHash.MetadataHash = [32]byte{}
+	require.NoError(t, ValidateAsset(noHash, data))
+}
@@ -0,0 +1,219 @@
+// Package arc31 authenticates Algorand accounts in the style of ARC-31. A
+// service issues a Message binding a random challenge to its domain, network
+// and validity period, the wallet of the account signs it, and the service
+// verifies the signature against the authorizing address of the account,
+// which may be rekeyed to a single or multisig account.
+package arc31
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// ChallengeSize is the size of the random challenges of NewMessage.
+const ChallengeSize = 32
+
+// bytesPrefix is prepended to the digest of a message when signing, as done
+// by crypto.SignBytes.
+var bytesPrefix = []byte("MX")
+
+// Message is the authentication message an account signs.
+type Message struct {
+	// Domain is the domain of the service the account signs in to
+	Domain string `json:"domain"`
+	// AuthAcc is the address of the account signing in
+	AuthAcc string `json:"authAcc"`
+	// Challenge is the nonce of the message, only valid once
+	Challenge []byte `json:"challenge"`
+	// ChainID is the genesis hash of the network of the account
+	ChainID []byte `json:"chainId"`
+	// Description explains the message to the user
+	Description string    `json:"desc,omitempty"`
+	IssuedAt    time.Time `json:"issuedAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// NewMessage returns the message for account to sign in to domain on the
+// network with genesis hash genesisHash, with a random challenge and valid for
+// validity from now.
+func NewMessage(domain string, account types.Address, genesisHash []byte, validity time.Duration) (Message, error) {
+	challenge := make([]byte, ChallengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return Message{}, err
+	}
+	now := time.Now().UTC()
+	return Message{
+		Domain:    domain,
+		AuthAcc:   account.String(),
+		Challenge: challenge,
+		ChainID:   genesisHash,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(validity),
+	}, nil
+}
+
+// Encode returns the JSON encoding of the message, which is what is signed.
+func (m Message) Encode() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// ParseMessage decodes the JSON encoding of a message.
+func ParseMessage(data []byte) (Message, error) {
+	var m Message
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Message{}, fmt.Errorf("invalid ARC-31 message: %w", err)
+	}
+	return m, nil
+}
+
+// SignedMessage is an encoded message with the signature of the authorizing
+// address of its account, either a single or multisig account.
+type SignedMessage struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Message []byte            `codec:"msg"`
+	Sig     types.Signature   `codec:"sig"`
+	Msig    types.MultisigSig `codec:"msig"`
+}
+
+// digest returns the bytes signed for an encoded message.
+func digest(message []byte) []byte {
+	hash := sha256.Sum256(message)
+	return hash[:]
+}
+
+// Sign returns the message signed with sk.
+func (m Message) Sign(sk ed25519.PrivateKey) (SignedMessage, error) {
+	message, err := m.Encode()
+	if err != nil {
+		return SignedMessage{}, err
+	}
+	signature, err := crypto.SignBytes(sk, digest(message))
+	if err != nil {
+		return SignedMessage{}, err
+	}
+	signed := SignedMessage{Message: message}
+	copy(signed.Sig[:], signature)
+	return signed, nil
+}
+
+// SignMultisig returns the message signed with sk for the multisig account ma.
+// The other signers of ma add their signatures with AppendMultisigSignature.
+func (m Message) SignMultisig(sk ed25519.PrivateKey, ma crypto.MultisigAccount) (SignedMessage, error) {
+	if err := ma.Validate(); err != nil {
+		return SignedMessage{}, err
+	}
+	message, err := m.Encode()
+	if err != nil {
+		return SignedMessage{}, err
+	}
+	signed := SignedMessage{Message: message}
+	signed.Msig.Version = ma.Version
+	signed.Msig.Threshold = ma.Threshold
+	signed.Msig.Subsigs = make([]types.MultisigSubsig, len(ma.Pks))
+	for i, pk := range ma.Pks {
+		signed.Msig.Subsigs[i].Key = append(ed25519.PublicKey(nil), pk...)
+	}
+	if err := signed.AppendMultisigSignature(sk); err != nil {
+		return SignedMessage{}, err
+	}
+	return signed, nil
+}
+
+// AppendMultisigSignature adds the signature of sk to the multisig signature
+// of the message.
+func (s *SignedMessage) AppendMultisigSignature(sk ed25519.PrivateKey) error {
+	pk := sk.Public().(ed25519.PublicKey)
+	for i, subsig := range s.Msig.Subsigs {
+		if bytes.Equal(subsig.Key, pk) {
+			signature, err := crypto.SignBytes(sk, digest(s.Message))
+			if err != nil {
+				return err
+			}
+			copy(s.Msig.Subsigs[i].Sig[:], signature)
+			return nil
+		}
+	}
+	return fmt.Errorf("key %x is not a key of the multisig account", pk)
+}
+
+// Verifier verifies the signed messages of accounts signing in to a service.
+type Verifier struct {
+	// Domain is the domain of the service
+	Domain string
+	// GenesisHash is the genesis hash of the network of the service
+	GenesisHash []byte
+	// Now returns the current time, time.Now when nil
+	Now func() time.Time
+}
+
+// Verify verifies that signed is a valid signature of a message for the
+// domain and network of the verifier, naming the account account the
+// challenge challenge was issued to, at the current time. authAddr is the
+// address account is rekeyed to, or the zero address when it is not rekeyed,
+// see LookupAuthAddr. It returns the verified message.
+//
+// The service must ensure a challenge is only verified once.
+func (v Verifier) Verify(signed SignedMessage, challenge []byte, account types.Address, authAddr types.Address) (Message, error) {
+	m, err := ParseMessage(signed.Message)
+	if err != nil {
+		return Message{}, err
+	}
+	if m.Domain != v.Domain {
+		return Message{}, fmt.Errorf("message is for domain %s, not %s", m.Domain, v.Domain)
+	}
+	if !bytes.Equal(m.ChainID, v.GenesisHash) {
+		return Message{}, fmt.Errorf("message is for another network")
+	}
+	if len(challenge) == 0 || subtle.ConstantTimeCompare(m.Challenge, challenge) != 1 {
+		return Message{}, fmt.Errorf("message does not have the issued challenge")
+	}
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	if now.Before(m.IssuedAt) || !now.Before(m.ExpiresAt) {
+		return Message{}, fmt.Errorf("message is only valid from %s to %s", m.IssuedAt.Format(time.RFC3339), m.ExpiresAt.Format(time.RFC3339))
+	}
+
+	if m.AuthAcc != account.String() {
+		return Message{}, fmt.Errorf("message is for account %s, not %s", m.AuthAcc, account)
+	}
+	if authAddr.IsZero() {
+		authAddr = account
+	}
+	toBeVerified := digest(signed.Message)
+	if !signed.Msig.Blank() {
+		if !crypto.VerifyMultisig(authAddr, append(append([]byte(nil), bytesPrefix...), toBeVerified...), signed.Msig) {
+			return Message{}, fmt.Errorf("invalid multisig signature of %s", authAddr)
+		}
+	} else if !crypto.VerifyBytes(authAddr[:], toBeVerified, signed.Sig[:]) {
+		return Message{}, fmt.Errorf("invalid signature of %s", authAddr)
+	}
+	return m, nil
+}
+
+// LookupAuthAddr returns the address account is rekeyed to, or the zero
+// address when it is not rekeyed.
+func LookupAuthAddr(ctx context.Context, client *algod.Client, account types.Address) (types.Address, error) {
+	info, err := client.AccountInformation(account.String()).Do(ctx)
+	if err != nil {
+		return types.Address{}, err
+	}
+	if info.AuthAddr == "" {
+		return types.Address{}, nil
+	}
+	return types.DecodeAddress(info.AuthAddr)
+}
@@ -0,0 +1,127 @@
+package arc31
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+var genesisHash = []byte("genesis hash of the test network")
+
+func TestVerify(t *testing.T) {
+	account := crypto.GenerateAccount()
+	m, err := NewMessage("example.com", account.Address, genesisHash, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, m.Challenge, ChallengeSize)
+	signed, err := m.Sign(account.PrivateKey)
+	require.NoError(t, err)
+
+	var decoded SignedMessage
+	require.NoError(t, msgpack.Decode(msgpack.Encode(signed), &decoded))
+	v := Verifier{Domain: "example.com", GenesisHash: genesisHash}
+	verified, err := v.Verify(decoded, m.Challenge, account.Address, types.Address{})
+	require.NoError(t, err)
+	require.Equal(t, m.AuthAcc, verified.AuthAcc)
+	require.True(t, m.IssuedAt.Equal(verified.IssuedAt))
+
+	_, err = v.Verify(signed, []byte("other challenge"), account.Address, types.Address{})
+	require.Error(t, err)
+	_, err = v.Verify(signed, m.Challenge, account.Address, crypto.GenerateAccount().Address)
+	require.Error(t, err)
+	_, err = Verifier{Domain: "evil.com", GenesisHash: genesisHash}.Verify(signed, m.Challenge, account.Address, types.Address{})
+	require.EqualError(t, err, "message is for domain example.com, not evil.com")
+	_, err = Verifier{Domain: "example.com", GenesisHash: []byte("other")}.Verify(signed, m.Challenge, account.Address, types.Address{})
+	require.Error(t, err)
+	expired := Verifier{Domain: "example.com", GenesisHash: genesisHash, Now: func() time.Time { return m.ExpiresAt }}
+	_, err = expired.Verify(signed, m.Challenge, account.Address, types.Address{})
+	require.Error(t, err)
+
+	tampered := signed
+	tampered.Message = append([]byte(nil), signed.Message...)
+	tampered.Message[len(tampered.Message)-2]++
+	_, err = v.Verify(tampered, m.Challenge, account.Address, types.Address{})
+	require.Error(t, err)
+}
+
+func TestVerifyOtherAccount(t *testing.T) {
+	issuedTo := crypto.GenerateAccount()
+	attacker := crypto.GenerateAccount()
+	issued, err := NewMessage("example.com", issuedTo.Address, genesisHash, time.Minute)
+	require.NoError(t, err)
+
+	// The attacker signs the challenge issued to another account as itself.
+	m := issued
+	m.AuthAcc = attacker.Address.String()
+	signed, err := m.Sign(attacker.PrivateKey)
+	require.NoError(t, err)
+
+	v := Verifier{Domain: "example.com", GenesisHash: genesisHash}
+	_, err = v.Verify(signed, issued.Challenge, issuedTo.Address, types.Address{})
+	require.EqualError(t, err, "message is for account "+attacker.Address.String()+", not "+issuedTo.Address.String())
+	_, err = v.Verify(signed, issued.Challenge, issuedTo.Address, attacker.Address)
+	require.Error(t, err)
+}
+
+func TestVerifyRekeyed(t *testing.T) {
+	account := crypto.GenerateAccount().Address
+	auth := crypto.GenerateAccount()
+	m, err := NewMessage("example.com", account, genesisHash, time.Minute)
+	require.NoError(t, err)
+	signed, err := m.Sign(auth.PrivateKey)
+	require.NoError(t, err)
+
+	v := Verifier{Domain: "example.com", GenesisHash: genesisHash}
+	_, err = v.Verify(signed, m.Challenge, account, types.Address{})
+	require.Error(t, err)
+	_, err = v.Verify(signed, m.Challenge, account, auth.Address)
+	require.NoError(t, err)
+}
+
+func TestVerifyMultisig(t *testing.T) {
+	signers := []crypto.Account{crypto.GenerateAccount(), crypto.GenerateAccount(), crypto.GenerateAccount()}
+	ma, err := crypto.MultisigAccountWithParams(1, 2, []types.Address{signers[0].Address, signers[1].Address, signers[2].Address})
+	require.NoError(t, err)
+	address, err := ma.Address()
+	require.NoError(t, err)
+
+	m, err := NewMessage("example.com", address, genesisHash, time.Minute)
+	require.NoError(t, err)
+	signed, err := m.SignMultisig(signers[0].PrivateKey, ma)
+	require.NoError(t, err)
+
+	v := Verifier{Domain: "example.com", GenesisHash: genesisHash}
+	_, err = v.Verify(signed, m.Challenge, address, types.Address{})
+	require.Error(t, err)
+	require.NoError(t, signed.AppendMultisigSignature(signers[2].PrivateKey))
+	_, err = v.Verify(signed, m.Challenge, address, types.Address{})
+	require.NoError(t, err)
+
+	require.Error(t, signed.AppendMultisigSignature(crypto.GenerateAccount().PrivateKey))
+}
+
+func TestLookupAuthAddr(t *testing.T) {
+	account := crypto.GenerateAccount().Address
+	auth := crypto.GenerateAccount().Address
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/accounts/"+account.String(), r.URL.Path)
+		require.NoError(t, json.NewEncoder(w).Encode(models.Account{Address: account.String(), AuthAddr: auth.String()}))
+	}))
+	defer server.Close()
+	client, err := algod.MakeClient(server.URL, "")
+	require.NoError(t, err)
+
+	authAddr, err := LookupAuthAddr(context.Background(), client, account)
+	require.NoError(t, err)
+	require.Equal(t, auth, authAddr)
+}
@@ -0,0 +1,312 @@
+// Package arc59 sends assets through the ARC-59 asset inbox router, so that
+// assets can be sent to receivers which have not opted in to them. The router
+// holds the assets in an inbox account of the receiver, from which the
+// receiver claims or rejects them.
+package arc59
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// The signatures of the methods of the ARC-59 router called by Router.
+const (
+	OptRouterInSignature      = "arc59_optRouterIn(uint64)void"
+	GetInboxSignature         = "arc59_getInbox(address)address"
+	GetSendAssetInfoSignature = "arc59_getSendAssetInfo(address,uint64)(uint64,uint64,bool,bool,uint64)"
+	SendAssetSignature        = "arc59_sendAsset(axfer,address,uint64)address"
+	ClaimSignature            = "arc59_claim(uint64)void"
+	RejectSignature           = "arc59_reject(uint64)void"
+	ClaimAlgoSignature        = "arc59_claimAlgo()void"
+)
+
+// The number of inner transactions of the router methods with a fixed number
+// of them, whose fees are paid by their callers.
+const (
+	optRouterInInnerTxns = 1
+	claimInnerTxns       = 1
+	rejectInnerTxns      = 1
+	claimAlgoInnerTxns   = 1
+)
+
+// SendAssetInfo is what sending an asset to a receiver through the router
+// requires, as returned by arc59_getSendAssetInfo.
+type SendAssetInfo struct {
+	// InnerTxns is the number of inner transactions of arc59_sendAsset
+	InnerTxns uint64
+	// MBR is the minimum balance the router and the inbox need to receive
+	MBR uint64
+	// RouterOptedIn is whether the router has opted in to the asset
+	RouterOptedIn bool
+	// ReceiverOptedIn is whether the receiver has opted in to the asset, in
+	// which case the asset is sent to it directly
+	ReceiverOptedIn bool
+	// ReceiverAlgoNeededForClaim is the amount of microAlgos the receiver
+	// needs to claim the asset
+	ReceiverAlgoNeededForClaim uint64
+}
+
+// Router adds calls to an ARC-59 router app to an AtomicTransactionComposer.
+type Router struct {
+	// The ID of the router app
+	AppID uint64
+	// The sender of the transactions
+	Sender types.Address
+	// A transaction Signer that can authorize the transactions from Sender
+	Signer transaction.TransactionSigner
+}
+
+// NewRouter returns a Router calling the router app appID from sender.
+func NewRouter(appID uint64, sender types.Address, signer transaction.TransactionSigner) *Router {
+	return &Router{AppID: appID, Sender: sender, Signer: signer}
+}
+
+// Address returns the address of the router app.
+func (r *Router) Address() types.Address {
+	return crypto.GetApplicationAddress(r.AppID)
+}
+
+// withInnerFees returns sp with the flat fee of a transaction paying for
+// innerTxns inner transactions.
+func withInnerFees(sp types.SuggestedParams, innerTxns uint64) types.SuggestedParams {
+	minFee := sp.MinFee
+	if minFee == 0 {
+		minFee = transaction.MinTxnFee
+	}
+	sp.FlatFee = true
+	sp.Fee = types.MicroAlgos(minFee * (1 + innerTxns))
+	return sp
+}
+
+// methodCallParams returns the parameters of a call to the method with the
+// signature signature.
+func (r *Router) methodCallParams(signature string, args []interface{}, sp types.SuggestedParams) (transaction.AddMethodCallParams, error) {
+	method, err := abi.MethodFromSignature(signature)
+	if err != nil {
+		return transaction.AddMethodCallParams{}, err
+	}
+	return transaction.AddMethodCallParams{
+		AppID:           r.AppID,
+		Method:          method,
+		MethodArgs:      args,
+		Sender:          r.Sender,
+		SuggestedParams: sp,
+		OnComplete:      types.NoOpOC,
+		Signer:          r.Signer,
+	}, nil
+}
+
+// inboxBox returns the reference to the box of the router mapping receiver to
+// its inbox.
+func inboxBox(receiver types.Address) []types.AppBoxReference {
+	return []types.AppBoxReference{{AppID: 0, Name: receiver[:]}}
+}
+
+// AddSendAsset adds the transactions sending amount units of the asset assetID
+// from Sender to the inbox of receiver to atc, given the info of sending it
+// and the current inbox of receiver, the zero address if it has none:
+//   - a payment of the minimum balances and the microAlgos the receiver needs
+//     to claim the asset to the router, if any
+//   - the opt in of the router to the asset, if it has not opted in
+//   - the transfer of the asset to the router, along with the call to
+//     arc59_sendAsset moving it to the inbox
+//
+// When the receiver has opted in to the asset, a transfer of the asset to the
+// receiver is added instead.
+func (r *Router) AddSendAsset(atc *transaction.AtomicTransactionComposer, receiver types.Address, assetID, amount uint64, info SendAssetInfo, inbox types.Address, sp types.SuggestedParams) error {
+	if info.ReceiverOptedIn {
+		txn, err := transaction.MakeAssetTransferTxn(r.Sender.String(), receiver.String(), amount, nil, sp, "", assetID)
+		if err != nil {
+			return err
+		}
+		return atc.AddTransaction(transaction.TransactionWithSigner{Txn: txn, Signer: r.Signer})
+	}
+
+	if funds := info.MBR + info.ReceiverAlgoNeededForClaim; funds > 0 {
+		txn, err := transaction.MakePaymentTxn(r.Sender.String(), r.Address().String(), funds, nil, "", sp)
+		if err != nil {
+			return err
+		}
+		if err := atc.AddTransaction(transaction.TransactionWithSigner{Txn: txn, Signer: r.Signer}); err != nil {
+			return err
+		}
+	}
+
+	if !info.RouterOptedIn {
+		params, err := r.methodCallParams(OptRouterInSignature, []interface{}{assetID}, withInnerFees(sp, optRouterInInnerTxns))
+		if err != nil {
+			return err
+		}
+		params.ForeignAssets = []uint64{assetID}
+		if err := atc.AddMethodCall(params); err != nil {
+			return err
+		}
+	}
+
+	axfer, err := transaction.MakeAssetTransferTxn(r.Sender.String(), r.Address().String(), amount, nil, sp, "", assetID)
+	if err != nil {
+		return err
+	}
+	args := []interface{}{
+		transaction.TransactionWithSigner{Txn: axfer, Signer: r.Signer},
+		receiver,
+		info.ReceiverAlgoNeededForClaim,
+	}
+	params, err := r.methodCallParams(SendAssetSignature, args, withInnerFees(sp, info.InnerTxns))
+	if err != nil {
+		return err
+	}
+	params.ForeignAccounts = []string{receiver.String()}
+	if !inbox.IsZero() {
+		params.ForeignAccounts = append(params.ForeignAccounts, inbox.String())
+	}
+	params.ForeignAssets = []uint64{assetID}
+	params.BoxReferences = inboxBox(receiver)
+	return atc.AddMethodCall(params)
+}
+
+// SendAsset adds the transactions sending amount units of the asset assetID
+// from Sender to receiver to atc, directly when receiver has opted in to the
+// asset, and through the inbox of receiver otherwise, see AddSendAsset. The
+// opt in of receiver, its inbox and the info of sending the asset are looked
+// up with client.
+func (r *Router) SendAsset(ctx context.Context, client *algod.Client, atc *transaction.AtomicTransactionComposer, receiver types.Address, assetID, amount uint64, sp types.SuggestedParams) error {
+	optedIn, err := IsOptedIn(ctx, client, receiver, assetID)
+	if err != nil {
+		return err
+	}
+	if optedIn {
+		return r.AddSendAsset(atc, receiver, assetID, amount, SendAssetInfo{ReceiverOptedIn: true}, types.Address{}, sp)
+	}
+	inbox, err := r.GetInbox(ctx, client, receiver, sp)
+	if err != nil {
+		return err
+	}
+	info, err := r.GetSendAssetInfo(ctx, client, receiver, assetID, inbox, sp)
+	if err != nil {
+		return err
+	}
+	return r.AddSendAsset(atc, receiver, assetID, amount, info, inbox, sp)
+}
+
+// simulate simulates the call of params without signatures, and decodes its
+// return value into v.
+func (r *Router) simulate(ctx context.Context, client *algod.Client, params transaction.AddMethodCallParams, v interface{}) error {
+	params.Signer = transaction.EmptyTransactionSigner{}
+	var atc transaction.AtomicTransactionComposer
+	if err := atc.AddMethodCall(params); err != nil {
+		return err
+	}
+	response, err := atc.Simulate(client, ctx, models.SimulateRequest{AllowEmptySignatures: true})
+	if err != nil {
+		return err
+	}
+	result := response.MethodResults[0]
+	if result.DecodeError != nil {
+		return result.DecodeError
+	}
+	returnType, err := params.Method.Returns.GetTypeObject()
+	if err != nil {
+		return err
+	}
+	return abi.Unmarshal(returnType, result.RawReturnValue, v)
+}
+
+// GetInbox returns the inbox of receiver, or the zero address if it has none,
+// by simulating a call to arc59_getInbox.
+func (r *Router) GetInbox(ctx context.Context, client *algod.Client, receiver types.Address, sp types.SuggestedParams) (types.Address, error) {
+	params, err := r.methodCallParams(GetInboxSignature, []interface{}{receiver}, sp)
+	if err != nil {
+		return types.Address{}, err
+	}
+	params.BoxReferences = inboxBox(receiver)
+	var inbox types.Address
+	if err := r.simulate(ctx, client, params, &inbox); err != nil {
+		return types.Address{}, err
+	}
+	return inbox, nil
+}
+
+// GetSendAssetInfo returns what sending the asset assetID to receiver with
+// the inbox inbox requires, by simulating a call to arc59_getSendAssetInfo.
+func (r *Router) GetSendAssetInfo(ctx context.Context, client *algod.Client, receiver types.Address, assetID uint64, inbox types.Address, sp types.SuggestedParams) (SendAssetInfo, error) {
+	params, err := r.methodCallParams(GetSendAssetInfoSignature, []interface{}{receiver, assetID}, sp)
+	if err != nil {
+		return SendAssetInfo{}, err
+	}
+	params.ForeignAccounts = []string{receiver.String()}
+	if !inbox.IsZero() {
+		params.ForeignAccounts = append(params.ForeignAccounts, inbox.String())
+	}
+	params.ForeignAssets = []uint64{assetID}
+	params.BoxReferences = inboxBox(receiver)
+	var info SendAssetInfo
+	if err := r.simulate(ctx, client, params, &info); err != nil {
+		return SendAssetInfo{}, err
+	}
+	return info, nil
+}
+
+// AddClaim adds the claim of the asset assetID from the inbox of Sender to
+// atc, preceded by the opt in of Sender to the asset when optIn is set.
+func (r *Router) AddClaim(atc *transaction.AtomicTransactionComposer, inbox types.Address, assetID uint64, optIn bool, sp types.SuggestedParams) error {
+	if optIn {
+		txn, err := transaction.MakeAssetAcceptanceTxn(r.Sender.String(), nil, sp, assetID)
+		if err != nil {
+			return err
+		}
+		if err := atc.AddTransaction(transaction.TransactionWithSigner{Txn: txn, Signer: r.Signer}); err != nil {
+			return err
+		}
+	}
+	return r.addInboxCall(atc, ClaimSignature, inbox, assetID, claimInnerTxns, sp)
+}
+
+// AddReject adds the rejection of the asset assetID from the inbox of Sender
+// to atc, which closes the asset out of the inbox to its creator.
+func (r *Router) AddReject(atc *transaction.AtomicTransactionComposer, inbox types.Address, assetID uint64, sp types.SuggestedParams) error {
+	return r.addInboxCall(atc, RejectSignature, inbox, assetID, rejectInnerTxns, sp)
+}
+
+// addInboxCall adds a call of Sender on the asset assetID in its inbox.
+func (r *Router) addInboxCall(atc *transaction.AtomicTransactionComposer, signature string, inbox types.Address, assetID, innerTxns uint64, sp types.SuggestedParams) error {
+	params, err := r.methodCallParams(signature, []interface{}{assetID}, withInnerFees(sp, innerTxns))
+	if err != nil {
+		return err
+	}
+	params.ForeignAccounts = []string{inbox.String()}
+	params.ForeignAssets = []uint64{assetID}
+	params.BoxReferences = inboxBox(r.Sender)
+	return atc.AddMethodCall(params)
+}
+
+// AddClaimAlgo adds the claim of the microAlgos in the inbox of Sender above
+// its minimum balance to atc.
+func (r *Router) AddClaimAlgo(atc *transaction.AtomicTransactionComposer, inbox types.Address, sp types.SuggestedParams) error {
+	params, err := r.methodCallParams(ClaimAlgoSignature, nil, withInnerFees(sp, claimAlgoInnerTxns))
+	if err != nil {
+		return err
+	}
+	params.ForeignAccounts = []string{inbox.String()}
+	params.BoxReferences = inboxBox(r.Sender)
+	return atc.AddMethodCall(params)
+}
+
+// IsOptedIn returns whether account has opted in to the asset assetID.
+func IsOptedIn(ctx context.Context, client *algod.Client, account types.Address, assetID uint64) (bool, error) {
+	_, err := client.AccountAssetInformation(account.String(), assetID).Do(ctx)
+	if common.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
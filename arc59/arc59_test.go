@@ -0,0 +1,171 @@
+package arc59
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+var sp = types.SuggestedParams{Fee: 1000, FirstRoundValid: 1, LastRoundValid: 1001, GenesisHash: make([]byte, 32), FlatFee: true, MinFee: 1000}
+
+func selector(t *testing.T, signature string) []byte {
+	method, err := abi.MethodFromSignature(signature)
+	require.NoError(t, err)
+	return method.GetSelector()
+}
+
+func TestAddSendAsset(t *testing.T) {
+	sender := crypto.GenerateAccount()
+	receiver := crypto.GenerateAccount().Address
+	inbox := crypto.GenerateAccount().Address
+	router := NewRouter(5, sender.Address, transaction.BasicAccountTransactionSigner{Account: sender})
+
+	var atc transaction.AtomicTransactionComposer
+	info := SendAssetInfo{InnerTxns: 4, MBR: 228100, ReceiverAlgoNeededForClaim: 201000}
+	require.NoError(t, router.AddSendAsset(&atc, receiver, 7, 10, info, inbox, sp))
+	group, err := atc.BuildGroup()
+	require.NoError(t, err)
+	require.Len(t, group, 4)
+
+	pay := group[0].Txn
+	require.Equal(t, types.PaymentTx, pay.Type)
+	require.Equal(t, router.Address(), pay.Receiver)
+	require.Equal(t, types.MicroAlgos(429100), pay.Amount)
+
+	optIn := group[1].Txn
+	require.Equal(t, selector(t, OptRouterInSignature), optIn.ApplicationArgs[0])
+	require.Equal(t, types.MicroAlgos(2000), optIn.Fee)
+
+	axfer := group[2].Txn
+	require.Equal(t, types.AssetTransferTx, axfer.Type)
+	require.Equal(t, router.Address(), axfer.AssetReceiver)
+	require.Equal(t, uint64(10), axfer.AssetAmount)
+
+	send := group[3].Txn
+	require.Equal(t, types.AppIndex(5), send.ApplicationID)
+	require.Equal(t, selector(t, SendAssetSignature), send.ApplicationArgs[0])
+	require.Equal(t, receiver[:], send.ApplicationArgs[1])
+	require.Equal(t, types.MicroAlgos(5000), send.Fee)
+	require.Equal(t, []types.Address{receiver, inbox}, send.Accounts)
+	require.Equal(t, []types.AssetIndex{7}, send.ForeignAssets)
+	require.Equal(t, []types.BoxReference{{ForeignAppIdx: 0, Name: receiver[:]}}, send.BoxReferences)
+
+	atc = transaction.AtomicTransactionComposer{}
+	require.NoError(t, router.AddSendAsset(&atc, receiver, 7, 10, SendAssetInfo{InnerTxns: 2, RouterOptedIn: true}, types.Address{}, sp))
+	require.Equal(t, 2, atc.Count())
+
+	atc = transaction.AtomicTransactionComposer{}
+	require.NoError(t, router.AddSendAsset(&atc, receiver, 7, 10, SendAssetInfo{ReceiverOptedIn: true}, types.Address{}, sp))
+	group, err = atc.BuildGroup()
+	require.NoError(t, err)
+	require.Len(t, group, 1)
+	require.Equal(t, receiver, group[0].Txn.AssetReceiver)
+}
+
+func TestAddClaim(t *testing.T) {
+	receiver := crypto.GenerateAccount()
+	inbox := crypto.GenerateAccount().Address
+	router := NewRouter(5, receiver.Address, transaction.BasicAccountTransactionSigner{Account: receiver})
+
+	var atc transaction.AtomicTransactionComposer
+	require.NoError(t, router.AddClaim(&atc, inbox, 7, true, sp))
+	require.NoError(t, router.AddReject(&atc, inbox, 8, sp))
+	require.NoError(t, router.AddClaimAlgo(&atc, inbox, sp))
+	group, err := atc.BuildGroup()
+	require.NoError(t, err)
+	require.Len(t, group, 4)
+
+	require.Equal(t, types.AssetTransferTx, group[0].Txn.Type)
+	require.Equal(t, receiver.Address, group[0].Txn.AssetReceiver)
+	require.Equal(t, selector(t, ClaimSignature), group[1].Txn.ApplicationArgs[0])
+	require.Equal(t, []types.Address{inbox}, group[1].Txn.Accounts)
+	require.Equal(t, selector(t, RejectSignature), group[2].Txn.ApplicationArgs[0])
+	require.Equal(t, []types.AssetIndex{8}, group[2].Txn.ForeignAssets)
+	require.Equal(t, [][]byte{selector(t, ClaimAlgoSignature)}, group[3].Txn.ApplicationArgs)
+	for _, txn := range group[1:] {
+		require.Equal(t, types.MicroAlgos(2000), txn.Txn.Fee)
+		require.Equal(t, []types.BoxReference{{ForeignAppIdx: 0, Name: receiver.Address[:]}}, txn.Txn.BoxReferences)
+	}
+}
+
+func TestSendAsset(t *testing.T) {
+	sender := crypto.GenerateAccount()
+	receiver := crypto.GenerateAccount().Address
+	inbox := crypto.GenerateAccount().Address
+	router := NewRouter(5, sender.Address, transaction.BasicAccountTransactionSigner{Account: sender})
+
+	returnLog := func(signature string, value interface{}) []byte {
+		method, err := abi.MethodFromSignature(signature)
+		require.NoError(t, err)
+		returnType, err := method.Returns.GetTypeObject()
+		require.NoError(t, err)
+		encoded, err := abi.Marshal(returnType, value)
+		require.NoError(t, err)
+		return append(append([]byte(nil), abi.ReturnPrefix...), encoded...)
+	}
+	optedIn := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/accounts/"+receiver.String()+"/assets/7" {
+			if !optedIn {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message":"account asset info not found"}`))
+				return
+			}
+			w.Write(json.Encode(models.AccountAssetResponse{}))
+			return
+		}
+		require.Equal(t, "/v2/transactions/simulate", r.URL.Path)
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		var request models.SimulateRequest
+		require.NoError(t, msgpack.Decode(body, &request))
+		require.True(t, request.AllowEmptySignatures)
+		call := request.TxnGroups[0].Txns[0].Txn
+		var log []byte
+		switch {
+		case bytes.Equal(call.ApplicationArgs[0], selector(t, GetInboxSignature)):
+			log = returnLog(GetInboxSignature, inbox)
+		case bytes.Equal(call.ApplicationArgs[0], selector(t, GetSendAssetInfoSignature)):
+			require.Equal(t, []types.Address{receiver, inbox}, call.Accounts)
+			log = returnLog(GetSendAssetInfoSignature, []interface{}{uint64(1), uint64(100000), true, false, uint64(0)})
+		default:
+			t.Fatalf("unexpected simulated call %x", call.ApplicationArgs[0])
+		}
+		result := models.SimulateTransactionResult{TxnResult: models.PendingTransactionResponse{Logs: [][]byte{log}}}
+		w.Write(json.Encode(models.SimulateResponse{TxnGroups: []models.SimulateTransactionGroupResult{{TxnResults: []models.SimulateTransactionResult{result}}}}))
+	}))
+	defer server.Close()
+	client, err := algod.MakeClient(server.URL, "")
+	require.NoError(t, err)
+
+	var atc transaction.AtomicTransactionComposer
+	require.NoError(t, router.SendAsset(context.Background(), client, &atc, receiver, 7, 10, sp))
+	group, err := atc.BuildGroup()
+	require.NoError(t, err)
+	require.Len(t, group, 3)
+	require.Equal(t, types.MicroAlgos(100000), group[0].Txn.Amount)
+	require.Equal(t, router.Address(), group[1].Txn.AssetReceiver)
+	require.Equal(t, types.MicroAlgos(2000), group[2].Txn.Fee)
+
+	optedIn = true
+	atc = transaction.AtomicTransactionComposer{}
+	require.NoError(t, router.SendAsset(context.Background(), client, &atc, receiver, 7, 10, sp))
+	group, err = atc.BuildGroup()
+	require.NoError(t, err)
+	require.Len(t, group, 1)
+	require.Equal(t, receiver, group[0].Txn.AssetReceiver)
+}
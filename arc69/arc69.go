@@ -0,0 +1,130 @@
+// Package arc69 encodes and decodes the ARC-69 metadata of assets, which is
+// the JSON note of their latest asset configuration transaction, and resolves
+// the current metadata of an asset through the indexer.
+package arc69
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/indexer"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// Standard is the value of the standard field of ARC-69 metadata.
+const Standard = "arc69"
+
+// Metadata is the ARC-69 metadata of an asset.
+type Metadata struct {
+	// Standard is always "arc69", and set by Note when empty
+	Standard    string `json:"standard"`
+	Description string `json:"description,omitempty"`
+	ExternalURL string `json:"external_url,omitempty"`
+	// MediaURL is the media of the asset, when it is not its URL
+	MediaURL   string                 `json:"media_url,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	MimeType   string                 `json:"mime_type,omitempty"`
+	Attributes []Attribute            `json:"attributes,omitempty"`
+}
+
+// Attribute is a trait of an asset, in the format of marketplaces.
+type Attribute struct {
+	TraitType   string      `json:"trait_type"`
+	Value       interface{} `json:"value"`
+	DisplayType string      `json:"display_type,omitempty"`
+}
+
+// Note returns the metadata as the note of an asset configuration
+// transaction, to create the asset with or to update its metadata.
+func (m Metadata) Note() ([]byte, error) {
+	if m.Standard == "" {
+		m.Standard = Standard
+	}
+	if m.Standard != Standard {
+		return nil, fmt.Errorf("ARC-69 standard must be %q, not %q", Standard, m.Standard)
+	}
+	note, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	maxLen := types.Consensus[types.ConsensusCurrentVersion].MaxTxnNoteBytes
+	if len(note) > maxLen {
+		return nil, fmt.Errorf("ARC-69 metadata of %d bytes exceeds the maximum note length %d", len(note), maxLen)
+	}
+	return note, nil
+}
+
+// ParseNote parses the ARC-69 metadata in the note of an asset configuration
+// transaction. It returns an error when the note is not ARC-69 metadata.
+func ParseNote(note []byte) (Metadata, error) {
+	var m Metadata
+	if err := json.Unmarshal(note, &m); err != nil {
+		return Metadata{}, fmt.Errorf("note is not ARC-69 metadata: %w", err)
+	}
+	if m.Standard != Standard {
+		return Metadata{}, fmt.Errorf("note is not ARC-69 metadata: standard is %q", m.Standard)
+	}
+	return m, nil
+}
+
+// Resolved is the ARC-69 metadata of an asset, with the transaction it was
+// found in.
+type Resolved struct {
+	Metadata Metadata
+	// TxID is the ID of the top level transaction, Round its round and
+	// IntraRoundOffset its offset in the round
+	TxID             string
+	Round            uint64
+	IntraRoundOffset uint64
+}
+
+// LatestMetadata returns the current ARC-69 metadata of an asset: that of the
+// latest asset configuration transaction of the asset with an ARC-69 note,
+// including the inner transactions of applications managing the asset. It
+// returns an error when the asset has no ARC-69 metadata.
+func LatestMetadata(ctx context.Context, client *indexer.Client, assetID uint64) (Resolved, error) {
+	var latest Resolved
+	found := false
+	it := client.LookupAssetTransactions(assetID).TxType(string(types.AssetConfigTx)).Iterate(ctx)
+	for it.Next() {
+		txn := it.Transaction()
+		m, ok := latestNote(txn, assetID)
+		if !ok {
+			continue
+		}
+		if found && (txn.ConfirmedRound < latest.Round || txn.ConfirmedRound == latest.Round && txn.IntraRoundOffset < latest.IntraRoundOffset) {
+			continue
+		}
+		latest = Resolved{Metadata: m, TxID: txn.Id, Round: txn.ConfirmedRound, IntraRoundOffset: txn.IntraRoundOffset}
+		found = true
+	}
+	if err := it.Err(); err != nil {
+		return Resolved{}, err
+	}
+	if !found {
+		return Resolved{}, fmt.Errorf("asset %d has no ARC-69 metadata", assetID)
+	}
+	return latest, nil
+}
+
+// latestNote returns the metadata of the last asset configuration of the
+// asset in a transaction and its inner transactions.
+func latestNote(txn models.Transaction, assetID uint64) (Metadata, bool) {
+	var latest Metadata
+	found := false
+	if types.TxType(txn.Type) == types.AssetConfigTx &&
+		(txn.AssetConfigTransaction.AssetId == assetID || txn.CreatedAssetIndex == assetID) {
+		if m, err := ParseNote(txn.Note); err == nil {
+			latest, found = m, true
+		}
+	}
+	// inner transactions are confirmed after the application call issuing them
+	for _, inner := range txn.InnerTxns {
+		if m, ok := latestNote(inner, assetID); ok {
+			latest, found = m, true
+		}
+	}
+	return latest, found
+}
@@ -0,0 +1,100 @@
+package arc69
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/indexer"
+)
+
+func TestNote(t *testing.T) {
+	m := Metadata{
+		Description: "An NFT",
+		MediaURL:    "ipfs://QmMedia",
+		MimeType:    "image/png",
+		Attributes:  []Attribute{{TraitType: "rarity", Value: "rare"}},
+	}
+	note, err := m.Note()
+	require.NoError(t, err)
+	require.Equal(t, `{"standard":"arc69","description":"An NFT","media_url":"ipfs://QmMedia","mime_type":"image/png","attributes":[{"trait_type":"rarity","value":"rare"}]}`, string(note))
+
+	parsed, err := ParseNote(note)
+	require.NoError(t, err)
+	m.Standard = Standard
+	require.Equal(t, m, parsed)
+
+	_, err = Metadata{Standard: "arc3"}.Note()
+	require.Error(t, err)
+	_, err = Metadata{Description: strings.Repeat("a", 1024)}.Note()
+	require.Error(t, err)
+	_, err = ParseNote([]byte(`{"standard":"arc3"}`))
+	require.Error(t, err)
+	_, err = ParseNote([]byte("not json"))
+	require.Error(t, err)
+}
+
+func note(t *testing.T, description string) []byte {
+	n, err := Metadata{Description: description}.Note()
+	require.NoError(t, err)
+	return n
+}
+
+func TestLatestMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/assets/7/transactions", r.URL.Path)
+		require.Equal(t, "acfg", r.URL.Query().Get("tx-type"))
+		response := models.TransactionsResponse{}
+		switch r.URL.Query().Get("next") {
+		case "":
+			response.NextToken = "page2"
+			response.Transactions = []models.Transaction{
+				{Id: "CREATE", Type: "acfg", ConfirmedRound: 1, CreatedAssetIndex: 7, Note: note(t, "first")},
+				{Id: "UPDATE", Type: "acfg", ConfirmedRound: 5, IntraRoundOffset: 2,
+					AssetConfigTransaction: models.TransactionAssetConfig{AssetId: 7}, Note: note(t, "second")},
+			}
+		case "page2":
+			response.Transactions = []models.Transaction{
+				{Id: "PLAIN", Type: "acfg", ConfirmedRound: 6,
+					AssetConfigTransaction: models.TransactionAssetConfig{AssetId: 7}, Note: []byte("hello")},
+				{Id: "APPL", Type: "appl", ConfirmedRound: 6, IntraRoundOffset: 1, InnerTxns: []models.Transaction{
+					{Type: "acfg", AssetConfigTransaction: models.TransactionAssetConfig{AssetId: 8}, Note: note(t, "other asset")},
+					{Type: "acfg", AssetConfigTransaction: models.TransactionAssetConfig{AssetId: 7}, Note: note(t, "inner")},
+				}},
+				{Id: "OLD", Type: "acfg", ConfirmedRound: 5, IntraRoundOffset: 1,
+					AssetConfigTransaction: models.TransactionAssetConfig{AssetId: 7}, Note: note(t, "old")},
+			}
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, err := indexer.MakeClient(server.URL, "")
+	require.NoError(t, err)
+
+	resolved, err := LatestMetadata(context.Background(), client, 7)
+	require.NoError(t, err)
+	require.Equal(t, "inner", resolved.Metadata.Description)
+	require.Equal(t, "APPL", resolved.TxID)
+	require.Equal(t, uint64(6), resolved.Round)
+}
+
+func TestLatestMetadataNone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(models.TransactionsResponse{Transactions: []models.Transaction{
+			{Id: "CREATE", Type: "acfg", ConfirmedRound: 1, CreatedAssetIndex: 7},
+		}})
+	}))
+	defer server.Close()
+
+	client, err := indexer.MakeClient(server.URL, "")
+	require.NoError(t, err)
+	_, err = LatestMetadata(context.Background(), client, 7)
+	require.EqualError(t, err, "asset 7 has no ARC-69 metadata")
+}
@@ -0,0 +1,65 @@
+package blockfollower
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// Checkpointer persists the next round a Follower should process, so that it
+// can resume where it left off after a restart.
+type Checkpointer interface {
+	// Load returns the saved round, or ok == false if there is no checkpoint.
+	Load() (round uint64, ok bool, err error)
+
+	// Save records the next round to process.
+	Save(round uint64) error
+}
+
+type checkpoint struct {
+	NextRound uint64 `json:"next-round"`
+}
+
+// FileCheckpointer is a Checkpointer which stores the checkpoint as JSON in a
+// file.
+type FileCheckpointer struct {
+	Path string
+}
+
+// Load reads the checkpoint file, a missing file is not an error.
+func (f FileCheckpointer) Load() (round uint64, ok bool, err error) {
+	data, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	var cp checkpoint
+	if err = json.Decode(data, &cp); err != nil {
+		return 0, false, err
+	}
+	return cp.NextRound, true, nil
+}
+
+// Save writes the checkpoint to a temporary file and renames it over the
+// checkpoint file, so that a crash never leaves a partially written checkpoint.
+func (f FileCheckpointer) Save(round uint64) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(f.Path), filepath.Base(f.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(json.Encode(checkpoint{NextRound: round})); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
@@ -0,0 +1,196 @@
+// Package blockfollower follows the blocks committed by an algod node, passing
+// each round's typed block (and optionally its state delta) to a callback.
+package blockfollower
+
+import (
+	"context"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// DefaultRetryInterval is the time to wait before retrying a failed request
+// when Config.RetryInterval is not set.
+const DefaultRetryInterval = time.Second
+
+// BlockData is the data for a single round passed to a Handler.
+type BlockData struct {
+	Round       uint64
+	Block       types.Block
+	Certificate types.Certificate
+
+	// Transactions the payset of the block, see algod.DecodedBlock.
+	Transactions []types.SignedTxnWithAD
+
+	// Delta the ledger state delta of the round, only set when
+	// Config.FetchDeltas is enabled.
+	Delta *types.LedgerStateDelta
+}
+
+// Handler processes the data of a round. Rounds are delivered in order, and a
+// round is only checkpointed after its Handler returns without error. An error
+// returned by the Handler stops the Follower.
+type Handler func(ctx context.Context, data BlockData) error
+
+// Config configures a Follower.
+type Config struct {
+	// StartRound the first round to process when there is no checkpoint.
+	StartRound uint64
+
+	// FetchDeltas also fetch the ledger state delta of each round. This requires
+	// a node running in follower mode.
+	FetchDeltas bool
+
+	// AdvanceSyncRound set the node's sync round after each processed round, so
+	// that a follower mode node keeps the data the Follower has not yet processed.
+	AdvanceSyncRound bool
+
+	// Checkpointer persists the next round to process, may be nil.
+	Checkpointer Checkpointer
+
+	// RetryInterval the time to wait before retrying a failed request. Defaults
+	// to DefaultRetryInterval.
+	RetryInterval time.Duration
+
+	// MaxRetries the number of times a failed request is retried before Run
+	// returns the error. Zero retries forever.
+	MaxRetries int
+}
+
+// Follower fetches the blocks committed by an algod node in order.
+type Follower struct {
+	client  *algod.Client
+	handler Handler
+	config  Config
+}
+
+// New creates a Follower which passes each round to handler.
+func New(client *algod.Client, handler Handler, config Config) *Follower {
+	if config.RetryInterval == 0 {
+		config.RetryInterval = DefaultRetryInterval
+	}
+	return &Follower{client: client, handler: handler, config: config}
+}
+
+// Run follows the node until ctx is cancelled, a Handler returns an error, or
+// a request fails more than Config.MaxRetries times.
+func (f *Follower) Run(ctx context.Context) error {
+	round, err := f.startRound()
+	if err != nil {
+		return err
+	}
+
+	if f.config.AdvanceSyncRound {
+		if err = f.retry(ctx, func() error {
+			_, err := f.client.SetSyncRound(round).Do(ctx)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	var lastRound uint64
+	for {
+		for lastRound < round {
+			if err = f.retry(ctx, func() error {
+				status, err := f.client.StatusAfterBlock(lastRound).Do(ctx)
+				lastRound = status.LastRound
+				return err
+			}); err != nil {
+				return err
+			}
+		}
+
+		data, err := f.fetch(ctx, round)
+		if err != nil {
+			return err
+		}
+
+		if err = f.handler(ctx, data); err != nil {
+			return err
+		}
+
+		round++
+		if err = f.checkpoint(ctx, round); err != nil {
+			return err
+		}
+	}
+}
+
+func (f *Follower) startRound() (uint64, error) {
+	if f.config.Checkpointer == nil {
+		return f.config.StartRound, nil
+	}
+
+	round, ok, err := f.config.Checkpointer.Load()
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return f.config.StartRound, nil
+	}
+	return round, nil
+}
+
+func (f *Follower) fetch(ctx context.Context, round uint64) (data BlockData, err error) {
+	data.Round = round
+
+	err = f.retry(ctx, func() error {
+		block, err := f.client.BlockDecoded(round).Do(ctx)
+		data.Block = block.Block
+		data.Certificate = block.Certificate
+		data.Transactions = block.Transactions
+		return err
+	})
+	if err != nil || !f.config.FetchDeltas {
+		return
+	}
+
+	err = f.retry(ctx, func() error {
+		delta, err := f.client.GetLedgerStateDelta(round).Do(ctx)
+		data.Delta = &delta
+		return err
+	})
+	return
+}
+
+func (f *Follower) checkpoint(ctx context.Context, next uint64) error {
+	if f.config.Checkpointer != nil {
+		if err := f.config.Checkpointer.Save(next); err != nil {
+			return err
+		}
+	}
+
+	if !f.config.AdvanceSyncRound {
+		return nil
+	}
+	return f.retry(ctx, func() error {
+		_, err := f.client.SetSyncRound(next).Do(ctx)
+		return err
+	})
+}
+
+// retry calls fn until it succeeds, ctx is cancelled or it has been retried
+// MaxRetries times.
+func (f *Follower) retry(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if f.config.MaxRetries > 0 && attempt >= f.config.MaxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(f.config.RetryInterval):
+		}
+	}
+}
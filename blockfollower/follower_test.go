@@ -0,0 +1,168 @@
+package blockfollower
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// mockAlgod serves blocks up to lastRound, failing the first request for
+// each block when flaky is set.
+type mockAlgod struct {
+	mu        sync.Mutex
+	lastRound uint64
+	flaky     bool
+	failed    map[string]bool
+	syncRound uint64
+}
+
+func (m *mockAlgod) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.flaky && !m.failed[r.URL.Path] {
+		m.failed[r.URL.Path] = true
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var round uint64
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v2/status"):
+		fmt.Fprintf(w, `{"last-round":%d}`, m.lastRound)
+	case strings.HasPrefix(r.URL.Path, "/v2/blocks/"):
+		fmt.Sscanf(r.URL.Path, "/v2/blocks/%d", &round)
+		cert := types.EncodedBlockCert{
+			Block:       types.Block{BlockHeader: types.BlockHeader{Round: types.Round(round)}},
+			Certificate: types.Certificate{Round: types.Round(round)},
+		}
+		w.Write(msgpack.Encode(cert))
+	case strings.HasPrefix(r.URL.Path, "/v2/deltas/"):
+		fmt.Sscanf(r.URL.Path, "/v2/deltas/%d", &round)
+		w.Write(msgpack.Encode(types.LedgerStateDelta{Hdr: &types.BlockHeader{Round: types.Round(round)}}))
+	case strings.HasPrefix(r.URL.Path, "/v2/ledger/sync/"):
+		fmt.Sscanf(r.URL.Path, "/v2/ledger/sync/%d", &m.syncRound)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (m *mockAlgod) SyncRound() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.syncRound
+}
+
+var errStop = errors.New("stop")
+
+// runUntil runs a Follower, stopping it once round stop has been handled.
+func runUntil(t *testing.T, client *algod.Client, config Config, stop uint64) []BlockData {
+	var handled []BlockData
+	follower := New(client, func(ctx context.Context, data BlockData) error {
+		handled = append(handled, data)
+		if data.Round == stop {
+			return errStop
+		}
+		return nil
+	}, config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.Equal(t, errStop, follower.Run(ctx))
+	return handled
+}
+
+func TestFollowerRun(t *testing.T) {
+	mock := &mockAlgod{lastRound: 10, flaky: true, failed: map[string]bool{}}
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	client, err := algod.MakeClient(server.URL, "")
+	require.NoError(t, err)
+
+	config := Config{
+		StartRound:       3,
+		FetchDeltas:      true,
+		AdvanceSyncRound: true,
+		RetryInterval:    time.Millisecond,
+	}
+	handled := runUntil(t, client, config, 5)
+
+	require.Len(t, handled, 3)
+	for i, data := range handled {
+		round := uint64(3 + i)
+		require.Equal(t, round, data.Round)
+		require.Equal(t, types.Round(round), data.Block.Round)
+		require.Equal(t, types.Round(round), data.Certificate.Round)
+		require.NotNil(t, data.Delta)
+		require.Equal(t, types.Round(round), data.Delta.Hdr.Round)
+	}
+	// round 5 was not handled successfully, so the sync round stays at 5
+	require.Equal(t, uint64(5), mock.SyncRound())
+}
+
+func TestFollowerMaxRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, err := algod.MakeClient(server.URL, "")
+	require.NoError(t, err)
+
+	follower := New(client, func(ctx context.Context, data BlockData) error {
+		return nil
+	}, Config{RetryInterval: time.Millisecond, MaxRetries: 2})
+	require.Error(t, follower.Run(context.Background()))
+}
+
+func TestFollowerResumesFromCheckpoint(t *testing.T) {
+	server := httptest.NewServer(&mockAlgod{lastRound: 10})
+	defer server.Close()
+
+	client, err := algod.MakeClient(server.URL, "")
+	require.NoError(t, err)
+
+	checkpointer := FileCheckpointer{Path: filepath.Join(t.TempDir(), "checkpoint.json")}
+	config := Config{StartRound: 1, Checkpointer: checkpointer}
+
+	handled := runUntil(t, client, config, 2)
+	require.Len(t, handled, 2)
+
+	round, ok, err := checkpointer.Load()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(2), round)
+
+	// the failed round is delivered again after a restart
+	handled = runUntil(t, client, config, 4)
+	require.Len(t, handled, 3)
+	require.Equal(t, uint64(2), handled[0].Round)
+	require.Nil(t, handled[0].Delta)
+}
+
+func TestFileCheckpointerMissingFile(t *testing.T) {
+	checkpointer := FileCheckpointer{Path: filepath.Join(t.TempDir(), "checkpoint.json")}
+	_, ok, err := checkpointer.Load()
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, checkpointer.Save(42))
+	round, ok, err := checkpointer.Load()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(42), round)
+}
@@ -0,0 +1,77 @@
+package kmd
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors which kmd error responses are classified as, to be checked with
+// errors.Is.
+var (
+	// ErrWrongPassword the wallet password was incorrect.
+	ErrWrongPassword = errors.New("wrong password")
+
+	// ErrWalletLocked the wallet handle token is invalid or has expired, and a
+	// new one must be obtained with InitWalletHandle.
+	ErrWalletLocked = errors.New("wallet locked")
+
+	// ErrWalletNotFound no wallet exists with the given ID.
+	ErrWalletNotFound = errors.New("wallet not found")
+
+	// ErrKeyNotFound the wallet does not contain the key for the address.
+	ErrKeyNotFound = errors.New("key not found")
+
+	// ErrMultisigNotFound the wallet does not contain the preimage of the
+	// multisig address.
+	ErrMultisigNotFound = errors.New("multisig not found")
+)
+
+// errorKinds maps fragments of kmd error messages to the error they are
+// classified as, checked in order. Missing multisig information and keys are
+// reported as not existing "in this wallet", so they are checked before
+// missing wallets.
+var errorKinds = []struct {
+	fragments []string
+	kind      error
+}{
+	{[]string{"password"}, ErrWrongPassword},
+	{[]string{"decrypt"}, ErrWrongPassword},
+	{[]string{"handle"}, ErrWalletLocked},
+	{[]string{"multisig", "does not exist"}, ErrMultisigNotFound},
+	{[]string{"multisig", "not found"}, ErrMultisigNotFound},
+	{[]string{"key", "does not exist"}, ErrKeyNotFound},
+	{[]string{"key", "not found"}, ErrKeyNotFound},
+	{[]string{"wallet", "not found"}, ErrWalletNotFound},
+	{[]string{"wallet", "does not exist"}, ErrWalletNotFound},
+}
+
+// APIError is an error response returned by kmd. Use errors.Is with the Err*
+// variables to check for specific failures.
+type APIError struct {
+	Message string
+
+	kind error
+}
+
+func newAPIError(message string) *APIError {
+	lower := strings.ToLower(message)
+	for _, k := range errorKinds {
+		matched := true
+		for _, fragment := range k.fragments {
+			matched = matched && strings.Contains(lower, fragment)
+		}
+		if matched {
+			return &APIError{Message: message, kind: k.kind}
+		}
+	}
+	return &APIError{Message: message}
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error the response was classified as, if any.
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
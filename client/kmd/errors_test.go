@@ -0,0 +1,50 @@
+package kmd
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIErrorKinds(t *testing.T) {
+	// the messages of the errors kmd reports
+	tests := []struct {
+		message string
+		kind    error
+	}{
+		{"wrong password", ErrWrongPassword},
+		{"failed to decrypt data", ErrWrongPassword},
+		{"invalid wallet handle", ErrWalletLocked},
+		{"wallet handle expired", ErrWalletLocked},
+		{"wallet not found", ErrWalletNotFound},
+		{"key does not exist in this wallet", ErrKeyNotFound},
+		{"multisig information (pks, threshold) for address does not exist in this wallet", ErrMultisigNotFound},
+		{"key already exists in wallet", nil},
+		{"wallet with same name already exists", nil},
+	}
+	kinds := []error{ErrWrongPassword, ErrWalletLocked, ErrWalletNotFound, ErrKeyNotFound, ErrMultisigNotFound}
+	for _, test := range tests {
+		err := error(newAPIError(test.message))
+		require.EqualError(t, err, test.message)
+		require.Equal(t, test.kind, errors.Unwrap(err), test.message)
+		for _, kind := range kinds {
+			require.Equal(t, kind == test.kind, errors.Is(err, kind), "%s is %s", test.message, kind)
+		}
+	}
+}
+
+func TestAPIErrorResponse(t *testing.T) {
+	kcl := kmdServer(t, func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":true,"message":"key does not exist in this wallet"}`))
+	})
+
+	_, err := kcl.ExportKey("handle", "password", "ADDRESS")
+	require.True(t, errors.Is(err, ErrKeyNotFound))
+	require.False(t, errors.Is(err, ErrWalletNotFound))
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, "key does not exist in this wallet", apiErr.Message)
+}
@@ -2,6 +2,7 @@ package kmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -38,36 +39,34 @@ func MakeClient(address string, apiToken string) (Client, error) {
 	return kcl, nil
 }
 
+// MakeClientWithTransport instantiates a Client for the given address and
+// apiToken which sends requests with the given http.RoundTripper, e.g. one
+// wrapped with common.TracingMiddleware.
+func MakeClientWithTransport(address string, apiToken string, transport http.RoundTripper) (Client, error) {
+	kcl, err := MakeClient(address, apiToken)
+	kcl.httpClient.Transport = transport
+	return kcl, err
+}
+
 // DoV1Request accepts a request from kmdapi/requests and
 func (kcl Client) DoV1Request(req APIV1Request, resp APIV1Response) error {
-	var body []byte
-
-	// Get the path and method for this request type
-	reqPath, reqMethod, err := getPathAndMethod(req)
-	if err != nil {
-		return err
-	}
+	return kcl.DoV1RequestWithContext(context.Background(), req, resp)
+}
 
-	// Encode the request
-	body = json.Encode(req)
-	fullPath := fmt.Sprintf("%s/%s", kcl.address, reqPath)
-	hreq, err := http.NewRequest(reqMethod, fullPath, bytes.NewReader(body))
+// DoV1RequestWithContext is DoV1Request sending the request with the given
+// context.
+func (kcl Client) DoV1RequestWithContext(ctx context.Context, req APIV1Request, resp APIV1Response) error {
+	hresp, err := kcl.sendV1Request(ctx, req)
 	if err != nil {
 		return err
 	}
-
-	// Add the auth token
-	hreq.Header.Add(kmdTokenHeader, kcl.apiToken)
-
-	// Send the request
-	hresp, err := kcl.httpClient.Do(hreq)
-	if err != nil {
+	defer hresp.Body.Close()
+	if err := checkV1Status(hresp); err != nil {
 		return err
 	}
 
 	decoder := json.NewDecoder(hresp.Body)
 	err = decoder.Decode(resp)
-	hresp.Body.Close()
 	if err != nil {
 		return err
 	}
@@ -81,6 +80,46 @@ func (kcl Client) DoV1Request(req APIV1Request, resp APIV1Response) error {
 	return nil
 }
 
+// sendV1Request sends the request, returning the response for the caller to
+// decode and close.
+func (kcl Client) sendV1Request(ctx context.Context, req APIV1Request) (*http.Response, error) {
+	var body []byte
+
+	// Get the path and method for this request type
+	reqPath, reqMethod, err := getPathAndMethod(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Encode the request
+	body = json.Encode(req)
+	fullPath := fmt.Sprintf("%s/%s", kcl.address, reqPath)
+	hreq, err := http.NewRequestWithContext(ctx, reqMethod, fullPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	// Add the auth token
+	hreq.Header.Add(kmdTokenHeader, kcl.apiToken)
+
+	// Send the request
+	return kcl.httpClient.Do(hreq)
+}
+
+// checkV1Status returns the error of a response with an error status: the
+// error of its envelope, or else its status when it has no error envelope,
+// e.g. when the API token is rejected.
+func checkV1Status(hresp *http.Response) error {
+	if hresp.StatusCode >= 200 && hresp.StatusCode < 300 {
+		return nil
+	}
+	var envelope APIV1ResponseEnvelope
+	if err := json.NewDecoder(hresp.Body).Decode(&envelope); err == nil && envelope.Error {
+		return envelope.GetError()
+	}
+	return fmt.Errorf("kmd responded with status %s", hresp.Status)
+}
+
 // getPathAndMethod infers the request path and method from the request type
 func getPathAndMethod(req APIV1Request) (reqPath string, reqMethod string, err error) {
 	switch req.(type) {
@@ -146,6 +185,12 @@ func getPathAndMethod(req APIV1Request) (reqPath string, reqMethod string, err e
 	case SignMultisigTransactionRequest:
 		reqPath = "v1/multisig/sign"
 		reqMethod = "POST"
+	case SignProgramRequest:
+		reqPath = "v1/program/sign"
+		reqMethod = "POST"
+	case SignProgramMultisigRequest:
+		reqPath = "v1/multisig/signprogram"
+		reqMethod = "POST"
 	}
 	return
 }
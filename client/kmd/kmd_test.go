@@ -0,0 +1,63 @@
+package kmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// kmdServer returns a client of a kmd answering the requests with handle.
+func kmdServer(t *testing.T, handle func(w http.ResponseWriter, r *http.Request, body map[string]interface{})) Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "token", r.Header.Get(kmdTokenHeader))
+		body := map[string]interface{}{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		handle(w, r, body)
+	}))
+	t.Cleanup(server.Close)
+	kcl, err := MakeClient(server.URL, "token")
+	require.NoError(t, err)
+	return kcl
+}
+
+func TestListKeysWithContext(t *testing.T) {
+	kcl := kmdServer(t, func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/v1/key/list", r.URL.Path)
+		require.Equal(t, "handle", body["wallet_handle_token"])
+		w.Write([]byte(`{"addresses":["A","B"]}`))
+	})
+
+	resp, err := kcl.ListKeysWithContext(context.Background(), "handle")
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, resp.Addresses)
+	resp, err = kcl.ListKeys("handle")
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, resp.Addresses)
+}
+
+func TestRequestContextCancelled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	kcl := kmdServer(t, func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := kcl.GenerateKeyWithContext(ctx, "handle")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = kcl.ListKeysFuncWithContext(ctx, "handle", func(string) error { return nil })
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -161,3 +161,23 @@ type SignMultisigTransactionRequest struct {
 	PartialMsig       types.MultisigSig `json:"partial_multisig"`
 	WalletPassword    string            `json:"wallet_password"`
 }
+
+// SignProgramRequest is the request for `POST /v1/program/sign`
+type SignProgramRequest struct {
+	APIV1RequestEnvelope
+	WalletHandleToken string `json:"wallet_handle_token"`
+	Address           string `json:"address"`
+	Program           []byte `json:"data"`
+	WalletPassword    string `json:"wallet_password"`
+}
+
+// SignProgramMultisigRequest is the request for `POST /v1/multisig/signprogram`
+type SignProgramMultisigRequest struct {
+	APIV1RequestEnvelope
+	WalletHandleToken string            `json:"wallet_handle_token"`
+	Address           string            `json:"address"`
+	Program           []byte            `json:"data"`
+	PublicKey         ed25519.PublicKey `json:"public_key"`
+	PartialMsig       types.MultisigSig `json:"partial_multisig"`
+	WalletPassword    string            `json:"wallet_password"`
+}
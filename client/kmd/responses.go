@@ -1,8 +1,6 @@
 package kmd
 
 import (
-	"errors"
-
 	"golang.org/x/crypto/ed25519"
 
 	"github.com/algorand/go-algorand-sdk/v2/types"
@@ -27,10 +25,10 @@ func (r VersionsResponse) GetError() error {
 }
 
 // GetError allows responses that embed an APIV1ResponseEnvelope to satisfy the
-// APIV1Response interface
+// APIV1Response interface. Errors are returned as an *APIError.
 func (r APIV1ResponseEnvelope) GetError() error {
 	if r.Error {
-		return errors.New(r.Message)
+		return newAPIError(r.Message)
 	}
 	return nil
 }
@@ -153,3 +151,15 @@ type SignMultisigTransactionResponse struct {
 	APIV1ResponseEnvelope
 	Multisig []byte `json:"multisig"`
 }
+
+// SignProgramResponse is the response to `POST /v1/program/sign`
+type SignProgramResponse struct {
+	APIV1ResponseEnvelope
+	Signature []byte `json:"sig"`
+}
+
+// SignProgramMultisigResponse is the response to `POST /v1/multisig/signprogram`
+type SignProgramMultisigResponse struct {
+	APIV1ResponseEnvelope
+	Multisig []byte `json:"multisig"`
+}
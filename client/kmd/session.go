@@ -0,0 +1,128 @@
+package kmd
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// minRenewInterval bounds how often a WalletSession renews its handle, and is
+// the delay before retrying a failed renewal.
+var minRenewInterval = time.Second
+
+// WalletSession holds a wallet handle token which is renewed in the background
+// before it expires. If the handle is invalidated anyway, e.g. because kmd was
+// restarted, a new handle is initialized with the wallet password. Close
+// releases the handle.
+type WalletSession struct {
+	kcl            Client
+	walletID       string
+	walletPassword string
+
+	mu     sync.Mutex
+	handle string
+	err    error
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewWalletSession initializes a handle for the wallet and starts renewing it
+// in the background.
+func NewWalletSession(kcl Client, walletID, walletPassword string) (*WalletSession, error) {
+	s := &WalletSession{
+		kcl:            kcl,
+		walletID:       walletID,
+		walletPassword: walletPassword,
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+
+	interval, err := s.init()
+	if err != nil {
+		return nil, err
+	}
+	go s.renewLoop(interval)
+	return s, nil
+}
+
+// Handle returns the current wallet handle token.
+func (s *WalletSession) Handle() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.handle
+}
+
+// Err returns the error of the last renewal, nil if it succeeded.
+func (s *WalletSession) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close stops renewing the handle and releases it.
+func (s *WalletSession) Close() error {
+	select {
+	case <-s.stop:
+		return nil
+	default:
+		close(s.stop)
+	}
+	<-s.done
+
+	_, err := s.kcl.ReleaseWalletHandle(s.Handle())
+	return err
+}
+
+// init initializes a new handle, returning how long to wait before renewing it.
+func (s *WalletSession) init() (time.Duration, error) {
+	resp, err := s.kcl.InitWalletHandle(s.walletID, s.walletPassword)
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	s.handle = resp.WalletHandleToken
+	s.mu.Unlock()
+	return s.renew()
+}
+
+// renew renews the handle, returning how long to wait before renewing it
+// again: half of its remaining lifetime.
+func (s *WalletSession) renew() (time.Duration, error) {
+	resp, err := s.kcl.RenewWalletHandle(s.Handle())
+	if err != nil {
+		return 0, err
+	}
+	interval := time.Duration(resp.WalletHandle.ExpiresSeconds) * time.Second / 2
+	if interval < minRenewInterval {
+		interval = minRenewInterval
+	}
+	return interval, nil
+}
+
+func (s *WalletSession) renewLoop(interval time.Duration) {
+	defer close(s.done)
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-timer.C:
+		}
+
+		interval, err := s.renew()
+		if errors.Is(err, ErrWalletLocked) {
+			interval, err = s.init()
+		}
+		if err != nil {
+			interval = minRenewInterval
+		}
+
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+		timer.Reset(interval)
+	}
+}
@@ -0,0 +1,130 @@
+package kmd
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSessions is a kmd managing wallet handles, which never expire unless
+// expired is called.
+type fakeSessions struct {
+	mu       sync.Mutex
+	handles  int
+	renewals map[string]int
+	expired  map[string]bool
+	released []string
+	failing  bool
+}
+
+func (f *fakeSessions) handle(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	handle, _ := body["wallet_handle_token"].(string)
+	switch r.URL.Path {
+	case "/v1/wallet/init":
+		if body["wallet_password"] != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":true,"message":"wrong password"}`))
+			return
+		}
+		f.handles++
+		fmt.Fprintf(w, `{"wallet_handle_token":"h%d"}`, f.handles)
+	case "/v1/wallet/renew":
+		switch {
+		case f.failing:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":true,"message":"database error"}`))
+		case f.expired[handle]:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":true,"message":"wallet handle expired"}`))
+		default:
+			f.renewals[handle]++
+			w.Write([]byte(`{"wallet_handle":{"expires_seconds":0}}`))
+		}
+	case "/v1/wallet/release":
+		f.released = append(f.released, handle)
+		w.Write([]byte(`{}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeSessions) renewalsOf(handle string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.renewals[handle]
+}
+
+func newSessionServer(t *testing.T) (*fakeSessions, Client) {
+	interval := minRenewInterval
+	minRenewInterval = 5 * time.Millisecond
+	t.Cleanup(func() { minRenewInterval = interval })
+	fake := &fakeSessions{renewals: map[string]int{}, expired: map[string]bool{}}
+	return fake, kmdServer(t, fake.handle)
+}
+
+func TestWalletSessionRenewal(t *testing.T) {
+	fake, kcl := newSessionServer(t)
+	_, err := NewWalletSession(kcl, "wallet", "wrong")
+	require.ErrorIs(t, err, ErrWrongPassword)
+
+	s, err := NewWalletSession(kcl, "wallet", "password")
+	require.NoError(t, err)
+	defer s.Close()
+	require.Equal(t, "h1", s.Handle())
+	require.Eventually(t, func() bool { return fake.renewalsOf("h1") >= 3 }, 5*time.Second, time.Millisecond)
+	require.Equal(t, "h1", s.Handle())
+	require.NoError(t, s.Err())
+
+	fake.mu.Lock()
+	fake.failing = true
+	fake.mu.Unlock()
+	require.Eventually(t, func() bool { return s.Err() != nil }, 5*time.Second, time.Millisecond)
+
+	fake.mu.Lock()
+	fake.failing = false
+	fake.mu.Unlock()
+	require.Eventually(t, func() bool { return s.Err() == nil }, 5*time.Second, time.Millisecond)
+	require.Equal(t, "h1", s.Handle())
+}
+
+func TestWalletSessionExpiredHandle(t *testing.T) {
+	fake, kcl := newSessionServer(t)
+	s, err := NewWalletSession(kcl, "wallet", "password")
+	require.NoError(t, err)
+	defer s.Close()
+
+	fake.mu.Lock()
+	fake.expired["h1"] = true
+	fake.mu.Unlock()
+	require.Eventually(t, func() bool { return s.Handle() == "h2" }, 5*time.Second, time.Millisecond)
+	require.Eventually(t, func() bool { return fake.renewalsOf("h2") >= 2 }, 5*time.Second, time.Millisecond)
+	require.NoError(t, s.Err())
+}
+
+func TestWalletSessionClose(t *testing.T) {
+	fake, kcl := newSessionServer(t)
+	s, err := NewWalletSession(kcl, "wallet", "password")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return fake.renewalsOf("h1") >= 2 }, 5*time.Second, time.Millisecond)
+
+	require.NoError(t, s.Close())
+	select {
+	case <-s.done:
+	default:
+		t.Fatal("Close returned before the renewal goroutine stopped")
+	}
+	renewals := fake.renewalsOf("h1")
+	time.Sleep(10 * minRenewInterval)
+	require.Equal(t, renewals, fake.renewalsOf("h1"))
+
+	require.NoError(t, s.Close())
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	require.Equal(t, []string{"h1"}, fake.released)
+}
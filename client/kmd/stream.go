@@ -0,0 +1,90 @@
+package kmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ListKeysFunc is ListKeys calling fn with each address as the response is
+// read, instead of returning them all at once, so that wallets with very many
+// keys can be enumerated without holding every address in memory. It stops at
+// and returns the first error returned by fn.
+func (kcl Client) ListKeysFunc(walletHandle string, fn func(address string) error) error {
+	return kcl.ListKeysFuncWithContext(context.Background(), walletHandle, fn)
+}
+
+// ListKeysFuncWithContext is ListKeysFunc sending the request with the given
+// context.
+func (kcl Client) ListKeysFuncWithContext(ctx context.Context, walletHandle string, fn func(address string) error) error {
+	req := ListKeysRequest{
+		WalletHandleToken: walletHandle,
+	}
+	hresp, err := kcl.sendV1Request(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer hresp.Body.Close()
+	if err := checkV1Status(hresp); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(hresp.Body)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+	var envelope APIV1ResponseEnvelope
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case "error":
+			err = decoder.Decode(&envelope.Error)
+		case "message":
+			err = decoder.Decode(&envelope.Message)
+		case "addresses":
+			err = decodeAddresses(decoder, fn)
+		default:
+			var skip json.RawMessage
+			err = decoder.Decode(&skip)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return envelope.GetError()
+}
+
+// decodeAddresses calls fn with each address of a JSON array of addresses.
+func decodeAddresses(decoder *json.Decoder, fn func(address string) error) error {
+	token, err := decoder.Token()
+	if err != nil || token == nil {
+		return err
+	}
+	if token != json.Delim('[') {
+		return fmt.Errorf("unexpected %v in addresses", token)
+	}
+	for decoder.More() {
+		var address string
+		if err := decoder.Decode(&address); err != nil {
+			return err
+		}
+		if err := fn(address); err != nil {
+			return err
+		}
+	}
+	return expectDelim(decoder, ']')
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("unexpected %v in response, expected %v", token, delim)
+	}
+	return nil
+}
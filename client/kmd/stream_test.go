@@ -0,0 +1,78 @@
+package kmd
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func listKeysServer(t *testing.T, status int, response string) Client {
+	return kmdServer(t, func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		require.Equal(t, "/v1/key/list", r.URL.Path)
+		require.Equal(t, "handle", body["wallet_handle_token"])
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	})
+}
+
+func listAddresses(kcl Client) ([]string, error) {
+	var addresses []string
+	err := kcl.ListKeysFunc("handle", func(address string) error {
+		addresses = append(addresses, address)
+		return nil
+	})
+	return addresses, err
+}
+
+func TestListKeysFunc(t *testing.T) {
+	kcl := listKeysServer(t, http.StatusOK, `{"error":false,"extra":{"nested":[1,{"a":"b"}]},"addresses":["A","B","C"],"message":""}`)
+	addresses, err := listAddresses(kcl)
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B", "C"}, addresses)
+
+	for _, response := range []string{`{"addresses":null}`, `{"addresses":[]}`, `{}`} {
+		addresses, err = listAddresses(listKeysServer(t, http.StatusOK, response))
+		require.NoError(t, err, response)
+		require.Empty(t, addresses, response)
+	}
+
+	for _, response := range []string{`[]`, `{"addresses":{}}`, `{"addresses":["A"`, `{"addresses":[1]}`} {
+		_, err = listAddresses(listKeysServer(t, http.StatusOK, response))
+		require.Error(t, err, response)
+	}
+}
+
+func TestListKeysFuncStops(t *testing.T) {
+	kcl := listKeysServer(t, http.StatusOK, `{"addresses":["A","B","C"]}`)
+	stop := errors.New("stop")
+	var addresses []string
+	err := kcl.ListKeysFunc("handle", func(address string) error {
+		addresses = append(addresses, address)
+		if address == "B" {
+			return stop
+		}
+		return nil
+	})
+	require.Equal(t, stop, err)
+	require.Equal(t, []string{"A", "B"}, addresses)
+}
+
+func TestListKeysFuncErrors(t *testing.T) {
+	// the error envelope, whether it comes before or after the addresses
+	kcl := listKeysServer(t, http.StatusOK, `{"addresses":[],"error":true,"message":"invalid wallet handle"}`)
+	_, err := listAddresses(kcl)
+	require.ErrorIs(t, err, ErrWalletLocked)
+
+	kcl = listKeysServer(t, http.StatusBadRequest, `{"error":true,"message":"invalid wallet handle"}`)
+	_, err = listAddresses(kcl)
+	require.ErrorIs(t, err, ErrWalletLocked)
+
+	// error statuses without an error envelope
+	kcl = listKeysServer(t, http.StatusUnauthorized, "Invalid API Token\n")
+	_, err = listAddresses(kcl)
+	require.EqualError(t, err, "kmd responded with status 401 Unauthorized")
+	_, err = kcl.ListKeys("handle")
+	require.EqualError(t, err, "kmd responded with status 401 Unauthorized")
+}
@@ -0,0 +1,22 @@
+package algod
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+)
+
+// AbortCatchup given a catchpoint, it aborts catching up to this catchpoint
+type AbortCatchup struct {
+	c *Client
+
+	catchpoint string
+}
+
+// Do performs the HTTP request
+func (s *AbortCatchup) Do(ctx context.Context, headers ...*common.Header) (response models.CatchpointAbortResponse, err error) {
+	err = s.c.delete(ctx, &response, fmt.Sprintf("/v2/catchup/%s", common.EscapeParams(s.catchpoint)...), nil, headers)
+	return
+}
@@ -6,8 +6,76 @@ import (
 
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
 )
 
+const (
+	// AccountInformationExcludeAll excludes asset holdings, application local
+	// state, created asset parameters and created application parameters.
+	AccountInformationExcludeAll = "all"
+	// AccountInformationExcludeNone returns the complete account record.
+	AccountInformationExcludeNone = "none"
+)
+
+// resultLimitExceededMessage is the message algod returns when an account holds
+// more resources than the node is configured to return.
+const resultLimitExceededMessage = "Result limit exceeded"
+
+// AccountResultLimitExceededError is returned by AccountInformation when the
+// account holds more assets and applications than algod will return in a single
+// response. Use Exclude(AccountInformationExcludeAll) together with
+// AccountAssetInformation and AccountApplicationInformation to read such
+// accounts.
+type AccountResultLimitExceededError struct {
+	Address            string
+	MaxResults         uint64
+	TotalAssetsOptedIn uint64
+	TotalCreatedAssets uint64
+	TotalAppsOptedIn   uint64
+	TotalCreatedApps   uint64
+
+	err error
+}
+
+func (e *AccountResultLimitExceededError) Error() string {
+	return fmt.Sprintf("account %s has more resources than the result limit of %d: %v", e.Address, e.MaxResults, e.err)
+}
+
+func (e *AccountResultLimitExceededError) Unwrap() error {
+	return e.err
+}
+
+// resultLimitExceeded converts a result limit error returned by algod into an
+// AccountResultLimitExceededError, returning any other error unchanged.
+func resultLimitExceeded(err error) error {
+	apiErr, ok := common.AsAPIError(err)
+	if !ok || apiErr.StatusCode != 400 || apiErr.Message != resultLimitExceededMessage || apiErr.Data == nil {
+		return err
+	}
+
+	var details struct {
+		Address            string `json:"address"`
+		MaxResults         uint64 `json:"max-results"`
+		TotalAssetsOptedIn uint64 `json:"total-assets-opted-in"`
+		TotalCreatedAssets uint64 `json:"total-created-assets"`
+		TotalAppsOptedIn   uint64 `json:"total-apps-opted-in"`
+		TotalCreatedApps   uint64 `json:"total-created-apps"`
+	}
+	if json.LenientDecode(json.Encode(apiErr.Data), &details) != nil {
+		return err
+	}
+
+	return &AccountResultLimitExceededError{
+		Address:            details.Address,
+		MaxResults:         details.MaxResults,
+		TotalAssetsOptedIn: details.TotalAssetsOptedIn,
+		TotalCreatedAssets: details.TotalCreatedAssets,
+		TotalAppsOptedIn:   details.TotalAppsOptedIn,
+		TotalCreatedApps:   details.TotalCreatedApps,
+		err:                err,
+	}
+}
+
 // AccountInformationParams contains all of the query parameters for url serialization.
 type AccountInformationParams struct {
 
@@ -40,8 +108,12 @@ func (s *AccountInformation) Exclude(Exclude string) *AccountInformation {
 	return s
 }
 
-// Do performs the HTTP request
+// Do performs the HTTP request. If the account holds more resources than algod
+// will return, the error is an *AccountResultLimitExceededError.
 func (s *AccountInformation) Do(ctx context.Context, headers ...*common.Header) (response models.Account, err error) {
 	err = s.c.get(ctx, &response, fmt.Sprintf("/v2/accounts/%s", common.EscapeParams(s.address)...), s.p, headers)
+	if err != nil {
+		err = resultLimitExceeded(err)
+	}
 	return
 }
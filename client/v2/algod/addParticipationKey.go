@@ -0,0 +1,21 @@
+package algod
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+)
+
+// AddParticipationKey add a participation key to the node
+type AddParticipationKey struct {
+	c *Client
+
+	participationkey []byte
+}
+
+// Do performs the HTTP request
+func (s *AddParticipationKey) Do(ctx context.Context, headers ...*common.Header) (response models.PostParticipationResponse, err error) {
+	err = s.c.post(ctx, &response, "/v2/participation", nil, withContentType(headers, "application/msgpack"), s.participationkey)
+	return
+}
@@ -2,6 +2,8 @@ package algod
 
 import (
 	"context"
+	"net/http"
+	"strings"
 
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
@@ -38,6 +40,17 @@ func (c *Client) post(ctx context.Context, response interface{}, path string, pa
 	return (*common.Client)(c).Post(ctx, response, path, params, headers, body)
 }
 
+// withContentType appends a Content-Type header with the given value, unless the
+// caller already specified one.
+func withContentType(headers []*common.Header, contentType string) []*common.Header {
+	for _, header := range headers {
+		if strings.ToLower(header.Key) == "content-type" {
+			return headers
+		}
+	}
+	return append(headers, &common.Header{Key: "Content-Type", Value: contentType})
+}
+
 // MakeClient is the factory for constructing a ClientV2 for a given endpoint.
 func MakeClient(address string, apiToken string) (c *Client, err error) {
 	commonClient, err := common.MakeClient(address, authHeader, apiToken)
@@ -53,10 +66,45 @@ func MakeClientWithHeaders(address string, apiToken string, headers []*common.He
 	return
 }
 
+// MakeClientWithTransport is the factory for constructing a ClientV2 for a given
+// endpoint with a custom HTTP transport as well as optional additional user
+// defined headers.
+func MakeClientWithTransport(address string, apiToken string, headers []*common.Header, transport http.RoundTripper) (c *Client, err error) {
+	commonClient, err := common.MakeClientWithTransport(address, authHeader, apiToken, headers, transport)
+	c = (*Client)(commonClient)
+	return
+}
+
+// MakeClientWithOptions is the factory for constructing a ClientV2 for a given
+// endpoint with optional behavior such as retries.
+func MakeClientWithOptions(address string, apiToken string, opts ...common.ClientOption) (c *Client, err error) {
+	commonClient, err := common.MakeClientWithOptions(address, authHeader, apiToken, opts...)
+	c = (*Client)(commonClient)
+	return
+}
+
+// MakeFailoverClient is the factory for constructing a ClientV2 which fails over
+// between multiple endpoints, trying them in the order given by policy.
+func MakeFailoverClient(addresses []string, policy common.SelectionPolicy, apiToken string, opts ...common.ClientOption) (c *Client, err error) {
+	commonClient, err := common.MakeFailoverClient(addresses, policy, authHeader, apiToken, opts...)
+	c = (*Client)(commonClient)
+	return
+}
+
+// CheckEndpoints probes the health of every endpoint of a failover client, see
+// common.Client.CheckEndpoints.
+func (c *Client) CheckEndpoints(ctx context.Context) {
+	(*common.Client)(c).CheckEndpoints(ctx)
+}
+
 func (c *Client) HealthCheck() *HealthCheck {
 	return &HealthCheck{c: c}
 }
 
+func (c *Client) Ready() *Ready {
+	return &Ready{c: c}
+}
+
 func (c *Client) GetGenesis() *GetGenesis {
 	return &GetGenesis{c: c}
 }
@@ -85,6 +133,10 @@ func (c *Client) Block(round uint64) *Block {
 	return &Block{c: c, round: round}
 }
 
+func (c *Client) GetLedgerStateDelta(round uint64) *GetLedgerStateDelta {
+	return &GetLedgerStateDelta{c: c, round: round}
+}
+
 func (c *Client) GetBlockHash(round uint64) *GetBlockHash {
 	return &GetBlockHash{c: c, round: round}
 }
@@ -109,6 +161,30 @@ func (c *Client) SendRawTransaction(rawtxn []byte) *SendRawTransaction {
 	return &SendRawTransaction{c: c, rawtxn: rawtxn}
 }
 
+func (c *Client) GetParticipationKeys() *GetParticipationKeys {
+	return &GetParticipationKeys{c: c}
+}
+
+func (c *Client) AddParticipationKey(participationkey []byte) *AddParticipationKey {
+	return &AddParticipationKey{c: c, participationkey: participationkey}
+}
+
+func (c *Client) GetParticipationKeyByID(participationId string) *GetParticipationKeyByID {
+	return &GetParticipationKeyByID{c: c, participationId: participationId}
+}
+
+func (c *Client) AppendKeys(participationId string, keymap []byte) *AppendKeys {
+	return &AppendKeys{c: c, participationId: participationId, keymap: keymap}
+}
+
+func (c *Client) DeleteParticipationKeyByID(participationId string) *DeleteParticipationKeyByID {
+	return &DeleteParticipationKeyByID{c: c, participationId: participationId}
+}
+
+func (c *Client) SimulateTransaction(request models.SimulateRequest) *SimulateTransaction {
+	return &SimulateTransaction{c: c, request: request}
+}
+
 func (c *Client) SuggestedParams() *SuggestedParams {
 	return &SuggestedParams{c: c}
 }
@@ -157,6 +233,14 @@ func (c *Client) SetSyncRound(round uint64) *SetSyncRound {
 	return &SetSyncRound{c: c, round: round}
 }
 
+func (c *Client) StartCatchup(catchpoint string) *StartCatchup {
+	return &StartCatchup{c: c, catchpoint: catchpoint}
+}
+
+func (c *Client) AbortCatchup(catchpoint string) *AbortCatchup {
+	return &AbortCatchup{c: c, catchpoint: catchpoint}
+}
+
 func (c *Client) TealCompile(source []byte) *TealCompile {
 	return &TealCompile{c: c, source: source}
 }
@@ -169,6 +253,18 @@ func (c *Client) TealDryrun(request models.DryrunRequest) *TealDryrun {
 	return &TealDryrun{c: c, request: request}
 }
 
+func (c *Client) GetBlockTimeStampOffset() *GetBlockTimeStampOffset {
+	return &GetBlockTimeStampOffset{c: c}
+}
+
+func (c *Client) SetBlockTimeStampOffset(offset uint64) *SetBlockTimeStampOffset {
+	return &SetBlockTimeStampOffset{c: c, offset: offset}
+}
+
 func (c *Client) BlockRaw(round uint64) *BlockRaw {
 	return &BlockRaw{c: c, round: round}
 }
+
+func (c *Client) BlockDecoded(round uint64) *BlockDecoded {
+	return &BlockDecoded{c: c, round: round}
+}
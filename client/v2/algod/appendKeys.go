@@ -0,0 +1,24 @@
+package algod
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+)
+
+// AppendKeys given a participation ID, append state proof keys to a particular
+// set of participation keys
+type AppendKeys struct {
+	c *Client
+
+	participationId string
+	keymap          []byte
+}
+
+// Do performs the HTTP request
+func (s *AppendKeys) Do(ctx context.Context, headers ...*common.Header) (response models.ParticipationKey, err error) {
+	err = s.c.post(ctx, &response, fmt.Sprintf("/v2/participation/%s", common.EscapeParams(s.participationId)...), nil, withContentType(headers, "application/msgpack"), s.keymap)
+	return
+}
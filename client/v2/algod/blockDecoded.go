@@ -0,0 +1,39 @@
+package algod
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// DecodedBlock is a block along with its certificate and the complete
+// transactions of its payset.
+type DecodedBlock struct {
+	Block       types.Block
+	Certificate types.Certificate
+
+	// Transactions the payset of the block with the fields stripped when encoding
+	// them into the block restored, including ApplyData and inner transactions.
+	Transactions []types.SignedTxnWithAD
+}
+
+// BlockDecoded contains metadata required to execute a BlockDecoded query.
+type BlockDecoded struct {
+	c     *Client
+	round uint64
+}
+
+// Do executes the BlockDecoded query, fetching the msgpack encoded block and
+// decoding it into typed structures.
+func (s *BlockDecoded) Do(ctx context.Context, headers ...*common.Header) (result DecodedBlock, err error) {
+	response, err := s.c.Block(s.round).DoWithCertificate(ctx, headers...)
+	if err != nil {
+		return
+	}
+
+	result.Block = response.Block
+	result.Certificate = response.Certificate
+	result.Transactions = response.Block.DecodePayset()
+	return
+}
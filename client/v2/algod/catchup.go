@@ -0,0 +1,91 @@
+package algod
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+)
+
+// CatchupProgress is the progress of a fast catchup as reported by the node.
+type CatchupProgress struct {
+	Catchpoint        string
+	TotalAccounts     uint64
+	ProcessedAccounts uint64
+	VerifiedAccounts  uint64
+	TotalKvs          uint64
+	ProcessedKvs      uint64
+	VerifiedKvs       uint64
+	TotalBlocks       uint64
+	AcquiredBlocks    uint64
+}
+
+func catchupProgress(status models.NodeStatus) CatchupProgress {
+	return CatchupProgress{
+		Catchpoint:        status.Catchpoint,
+		TotalAccounts:     status.CatchpointTotalAccounts,
+		ProcessedAccounts: status.CatchpointProcessedAccounts,
+		VerifiedAccounts:  status.CatchpointVerifiedAccounts,
+		TotalKvs:          status.CatchpointTotalKvs,
+		ProcessedKvs:      status.CatchpointProcessedKvs,
+		VerifiedKvs:       status.CatchpointVerifiedKvs,
+		TotalBlocks:       status.CatchpointTotalBlocks,
+		AcquiredBlocks:    status.CatchpointAcquiredBlocks,
+	}
+}
+
+// catchpointRound returns the round of a catchpoint label of the form
+// 'round#hash'.
+func catchpointRound(catchpoint string) (uint64, error) {
+	parts := strings.SplitN(catchpoint, "#", 2)
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("catchpoint %q is not in the form 'round#hash'", catchpoint)
+	}
+	return strconv.ParseUint(parts[0], 10, 64)
+}
+
+// FastCatchup starts a fast catchup to the given catchpoint label and polls the
+// node status every pollInterval until the node has caught up to the catchpoint
+// round. onProgress, if not nil, is called with the progress reported by each
+// poll while the catchup is running. It returns an error if the node stops the
+// catchup, because it was aborted or failed, before reaching the catchpoint
+// round. If ctx is cancelled the catchup is left running on the node, use
+// AbortCatchup to stop it.
+func (c *Client) FastCatchup(ctx context.Context, catchpoint string, pollInterval time.Duration, onProgress func(CatchupProgress), headers ...*common.Header) error {
+	round, err := catchpointRound(catchpoint)
+	if err != nil {
+		return err
+	}
+
+	if _, err = c.StartCatchup(catchpoint).Do(ctx, headers...); err != nil {
+		return err
+	}
+
+	for {
+		status, err := c.Status().Do(ctx, headers...)
+		if err != nil {
+			return err
+		}
+
+		if status.Catchpoint == "" {
+			if status.LastRound >= round {
+				return nil
+			}
+			return fmt.Errorf("catchup to %s stopped at round %d", catchpoint, status.LastRound)
+		}
+
+		if onProgress != nil {
+			onProgress(catchupProgress(status))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
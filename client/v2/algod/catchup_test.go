@@ -0,0 +1,111 @@
+package algod
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+)
+
+const testCatchpoint = "1000#4RMIDMJ3ZYZHA7IBMBRSVE5GRYNOLKBMWR3VUDU7XXMZZX42A3PQ"
+
+func TestCatchpointRound(t *testing.T) {
+	round, err := catchpointRound(testCatchpoint)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000), round)
+
+	for _, catchpoint := range []string{"", "1000", "round#hash", "-1#hash"} {
+		_, err = catchpointRound(catchpoint)
+		require.Error(t, err, catchpoint)
+	}
+}
+
+// catchupServer serves StartCatchup and the statuses of statuses in turn,
+// repeating the last one.
+func catchupServer(t *testing.T, statuses []models.NodeStatus) (*Client, func() int) {
+	var mu sync.Mutex
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/v2/catchup/" + testCatchpoint:
+			require.Equal(t, http.MethodPost, r.Method)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"catchup-message":"` + testCatchpoint + `"}`))
+		case "/v2/status":
+			status := statuses[len(statuses)-1]
+			if polls < len(statuses) {
+				status = statuses[polls]
+			}
+			polls++
+			require.NoError(t, json.NewEncoder(w).Encode(status))
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	c, err := MakeClient(server.URL, "")
+	require.NoError(t, err)
+	return c, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return polls
+	}
+}
+
+func TestFastCatchup(t *testing.T) {
+	c, polls := catchupServer(t, []models.NodeStatus{
+		{Catchpoint: testCatchpoint, LastRound: 10, CatchpointTotalAccounts: 100},
+		{Catchpoint: testCatchpoint, LastRound: 10, CatchpointTotalAccounts: 100, CatchpointProcessedAccounts: 50},
+		{LastRound: 1000},
+	})
+
+	var progress []CatchupProgress
+	err := c.FastCatchup(context.Background(), testCatchpoint, time.Millisecond, func(p CatchupProgress) {
+		progress = append(progress, p)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, polls())
+	require.Equal(t, []CatchupProgress{
+		{Catchpoint: testCatchpoint, TotalAccounts: 100},
+		{Catchpoint: testCatchpoint, TotalAccounts: 100, ProcessedAccounts: 50},
+	}, progress)
+}
+
+func TestFastCatchupAborted(t *testing.T) {
+	c, polls := catchupServer(t, []models.NodeStatus{
+		{Catchpoint: testCatchpoint, LastRound: 10},
+		{LastRound: 10},
+	})
+
+	err := c.FastCatchup(context.Background(), testCatchpoint, time.Millisecond, nil)
+	require.EqualError(t, err, "catchup to "+testCatchpoint+" stopped at round 10")
+	require.Equal(t, 2, polls())
+}
+
+func TestFastCatchupCancelled(t *testing.T) {
+	c, polls := catchupServer(t, []models.NodeStatus{
+		{Catchpoint: testCatchpoint, LastRound: 10},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := c.FastCatchup(ctx, testCatchpoint, time.Millisecond, func(CatchupProgress) {
+		cancel()
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, polls())
+}
+
+func TestFastCatchupInvalidCatchpoint(t *testing.T) {
+	c, polls := catchupServer(t, []models.NodeStatus{{}})
+	require.Error(t, c.FastCatchup(context.Background(), "not a catchpoint", time.Millisecond, nil))
+	require.Zero(t, polls())
+}
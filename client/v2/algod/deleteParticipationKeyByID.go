@@ -0,0 +1,22 @@
+package algod
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+)
+
+// DeleteParticipationKeyByID delete a given participation key by ID
+type DeleteParticipationKeyByID struct {
+	c *Client
+
+	participationId string
+}
+
+// Do performs the HTTP request
+func (s *DeleteParticipationKeyByID) Do(ctx context.Context, headers ...*common.Header) (err error) {
+	var response string
+	err = s.c.delete(ctx, &response, fmt.Sprintf("/v2/participation/%s", common.EscapeParams(s.participationId)...), nil, headers)
+	return
+}
@@ -14,6 +14,15 @@ type GetApplicationBoxesParams struct {
 	// Max max number of box names to return. If max is not set, or max == 0, returns
 	// all box-names.
 	Max uint64 `url:"max,omitempty"`
+
+	// Next a box name, in the goal app call arg form 'encoding:value'. When provided,
+	// the returned box names begin (lexographically) with the supplied name. Used for
+	// pagination together with the next-token of a previous response.
+	Next string `url:"next,omitempty"`
+
+	// Prefix a box name prefix, in the goal app call arg form 'encoding:value'. Only
+	// box names beginning with the prefix are returned.
+	Prefix string `url:"prefix,omitempty"`
 }
 
 // GetApplicationBoxes given an application ID, return all Box names. No particular
@@ -35,6 +44,23 @@ func (s *GetApplicationBoxes) Max(Max uint64) *GetApplicationBoxes {
 	return s
 }
 
+// Next a box name, in the goal app call arg form 'encoding:value'. When provided,
+// the returned box names begin (lexographically) with the supplied name. Used for
+// pagination together with the next-token of a previous response.
+func (s *GetApplicationBoxes) Next(Next string) *GetApplicationBoxes {
+	s.p.Next = Next
+
+	return s
+}
+
+// Prefix a box name prefix, in the goal app call arg form 'encoding:value'. Only
+// box names beginning with the prefix are returned.
+func (s *GetApplicationBoxes) Prefix(Prefix string) *GetApplicationBoxes {
+	s.p.Prefix = Prefix
+
+	return s
+}
+
 // Do performs the HTTP request
 func (s *GetApplicationBoxes) Do(ctx context.Context, headers ...*common.Header) (response models.BoxesResponse, err error) {
 	err = s.c.get(ctx, &response, fmt.Sprintf("/v2/applications/%s/boxes", common.EscapeParams(s.applicationId)...), s.p, headers)
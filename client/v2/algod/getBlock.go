@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
-	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
 	"github.com/algorand/go-algorand-sdk/v2/types"
 )
 
@@ -15,6 +14,10 @@ type BlockParams struct {
 	// Format configures whether the response object is JSON or MessagePack encoded. If
 	// not provided, defaults to JSON.
 	Format string `url:"format,omitempty"`
+
+	// HeaderOnly if true, only the block header (exclusive of payset or certificate)
+	// may be included in response.
+	HeaderOnly bool `url:"header-only,omitempty"`
 }
 
 // Block get the block for the given round.
@@ -26,16 +29,42 @@ type Block struct {
 	p BlockParams
 }
 
+// HeaderOnly if true, only the block header (exclusive of payset or certificate)
+// may be included in response.
+func (s *Block) HeaderOnly(HeaderOnly bool) *Block {
+	s.p.HeaderOnly = HeaderOnly
+
+	return s
+}
+
 // Do performs the HTTP request
 func (s *Block) Do(ctx context.Context, headers ...*common.Header) (result types.Block, err error) {
-	var response models.BlockResponse
+	response, err := s.DoWithCertificate(ctx, headers...)
+	if err != nil {
+		return
+	}
 
+	result = response.Block
+	return
+}
+
+// DoWithCertificate performs the HTTP request and returns the block along with
+// the certificate which committed it.
+func (s *Block) DoWithCertificate(ctx context.Context, headers ...*common.Header) (response types.EncodedBlockCert, err error) {
 	s.p.Format = "msgpack"
 	err = s.c.getMsgpack(ctx, &response, fmt.Sprintf("/v2/blocks/%d", s.round), s.p, headers)
+	return
+}
+
+// DoHeader performs the HTTP request with the header-only option set and
+// returns the block header, skipping the download of the payset.
+func (s *Block) DoHeader(ctx context.Context, headers ...*common.Header) (result types.BlockHeader, err error) {
+	s.p.HeaderOnly = true
+	response, err := s.DoWithCertificate(ctx, headers...)
 	if err != nil {
 		return
 	}
 
-	result = response.Block
+	result = response.Block.BlockHeader
 	return
 }
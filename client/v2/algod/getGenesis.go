@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/types"
 )
 
 // GetGenesis returns the entire genesis file in json.
@@ -16,3 +17,12 @@ func (s *GetGenesis) Do(ctx context.Context, headers ...*common.Header) (respons
 	err = s.c.get(ctx, &response, "/genesis", nil, headers)
 	return
 }
+
+// DoTyped performs the HTTP request and decodes the genesis file.
+func (s *GetGenesis) DoTyped(ctx context.Context, headers ...*common.Header) (genesis types.Genesis, err error) {
+	response, err := s.Do(ctx, headers...)
+	if err != nil {
+		return
+	}
+	return types.DecodeGenesis([]byte(response))
+}
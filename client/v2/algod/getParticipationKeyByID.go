@@ -0,0 +1,23 @@
+package algod
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+)
+
+// GetParticipationKeyByID given a participation ID, return information about that
+// participation key
+type GetParticipationKeyByID struct {
+	c *Client
+
+	participationId string
+}
+
+// Do performs the HTTP request
+func (s *GetParticipationKeyByID) Do(ctx context.Context, headers ...*common.Header) (response models.ParticipationKey, err error) {
+	err = s.c.get(ctx, &response, fmt.Sprintf("/v2/participation/%s", common.EscapeParams(s.participationId)...), nil, headers)
+	return
+}
@@ -0,0 +1,19 @@
+package algod
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+)
+
+// GetParticipationKeys return a list of participation keys
+type GetParticipationKeys struct {
+	c *Client
+}
+
+// Do performs the HTTP request
+func (s *GetParticipationKeys) Do(ctx context.Context, headers ...*common.Header) (response []models.ParticipationKey, err error) {
+	err = s.c.get(ctx, &response, "/v2/participation", nil, headers)
+	return
+}
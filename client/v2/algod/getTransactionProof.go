@@ -40,8 +40,10 @@ func (s *GetTransactionProof) Hashtype(Hashtype string) *GetTransactionProof {
 	return s
 }
 
-// Do performs the HTTP request
+// Do performs the HTTP request. The response is requested as msgpack to avoid
+// base64 encoding the proof.
 func (s *GetTransactionProof) Do(ctx context.Context, headers ...*common.Header) (response models.TransactionProofResponse, err error) {
-	err = s.c.get(ctx, &response, fmt.Sprintf("/v2/blocks/%s/transactions/%s/proof", common.EscapeParams(s.round, s.txid)...), s.p, headers)
+	s.p.Format = "msgpack"
+	err = s.c.getMsgpack(ctx, &response, fmt.Sprintf("/v2/blocks/%s/transactions/%s/proof", common.EscapeParams(s.round, s.txid)...), s.p, headers)
 	return
 }
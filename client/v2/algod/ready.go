@@ -0,0 +1,21 @@
+package algod
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+)
+
+// Ready returns OK if healthy and fully caught up. Unlike HealthCheck, which
+// only reports liveness, a node which is still catching up or has not yet
+// started returns 503.
+type Ready struct {
+	c *Client
+}
+
+// Do performs the HTTP request
+func (s *Ready) Do(ctx context.Context, headers ...*common.Header) error {
+	// The response body is empty, read it as a string to skip decoding.
+	var response string
+	return s.c.get(ctx, &response, "/ready", nil, headers)
+}
@@ -0,0 +1,24 @@
+package algod
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+)
+
+// SimulateTransaction simulates a raw transaction or transaction group as it would
+// be evaluated on the network. The simulation will use blockchain state from the
+// latest committed round.
+type SimulateTransaction struct {
+	c *Client
+
+	request models.SimulateRequest
+}
+
+// Do performs the HTTP request
+func (s *SimulateTransaction) Do(ctx context.Context, headers ...*common.Header) (response models.SimulateResponse, err error) {
+	err = s.c.post(ctx, &response, "/v2/transactions/simulate", nil, withContentType(headers, "application/msgpack"), msgpack.Encode(&s.request))
+	return
+}
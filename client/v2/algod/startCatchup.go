@@ -0,0 +1,44 @@
+package algod
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+)
+
+// StartCatchupParams contains all of the query parameters for url serialization.
+type StartCatchupParams struct {
+
+	// Min specify the minimum number of blocks which the ledger must be advanced by
+	// in order to start the catchup. This is useful for simplifying tools which
+	// support fast catchup, they can run the catchup unconditionally and the node
+	// will skip the catchup if it is not needed.
+	Min uint64 `url:"min,omitempty"`
+}
+
+// StartCatchup given a catchpoint, it starts catching up to this catchpoint
+type StartCatchup struct {
+	c *Client
+
+	catchpoint string
+
+	p StartCatchupParams
+}
+
+// Min specify the minimum number of blocks which the ledger must be advanced by in
+// order to start the catchup. This is useful for simplifying tools which support
+// fast catchup, they can run the catchup unconditionally and the node will skip
+// the catchup if it is not needed.
+func (s *StartCatchup) Min(Min uint64) *StartCatchup {
+	s.p.Min = Min
+
+	return s
+}
+
+// Do performs the HTTP request
+func (s *StartCatchup) Do(ctx context.Context, headers ...*common.Header) (response models.CatchpointStartResponse, err error) {
+	err = s.c.post(ctx, &response, fmt.Sprintf("/v2/catchup/%s", common.EscapeParams(s.catchpoint)...), s.p, headers, nil)
+	return
+}
@@ -2,9 +2,12 @@ package algod
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
 
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/logic"
 )
 
 // TealCompileParams contains all of the query parameters for url serialization.
@@ -40,3 +43,38 @@ func (s *TealCompile) Do(ctx context.Context, headers ...*common.Header) (respon
 	err = s.c.post(ctx, &response, "/v2/teal/compile", s.p, headers, s.source)
 	return
 }
+
+// CompiledTeal is a compiled TEAL program together with its decoded source map.
+type CompiledTeal struct {
+	// Program the compiled program bytes.
+	Program []byte
+
+	// Hash base32 SHA512_256 of program bytes (Address style).
+	Hash string
+
+	// SourceMap maps program counters of Program to lines of the TEAL source.
+	SourceMap logic.SourceMap
+}
+
+// DoWithSourceMap requests the source map along with the compiled program and
+// returns the decoded program bytes and source map.
+func (s *TealCompile) DoWithSourceMap(ctx context.Context, headers ...*common.Header) (compiled CompiledTeal, err error) {
+	s.p.Sourcemap = true
+	response, err := s.Do(ctx, headers...)
+	if err != nil {
+		return
+	}
+
+	compiled.Hash = response.Hash
+	compiled.Program, err = base64.StdEncoding.DecodeString(response.Result)
+	if err != nil {
+		return
+	}
+
+	if response.Sourcemap == nil {
+		err = fmt.Errorf("algod did not return a source map")
+		return
+	}
+	compiled.SourceMap, err = logic.DecodeSourceMap(*response.Sourcemap)
+	return
+}
@@ -0,0 +1,152 @@
+package common
+
+import (
+	"bytes"
+	"container/list"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DefaultCacheSize is the number of responses held by the cache created by
+// WithCache when no Cache is given.
+const DefaultCacheSize = 1024
+
+// immutableEndpoints are the endpoints whose successful responses never change.
+var immutableEndpoints = []string{
+	"/genesis",
+	"/v2/blocks/{round}",
+	"/v2/blocks/{round}/hash",
+	"/v2/blocks/{round}/lightheader/proof",
+	"/v2/blocks/{round}/transactions/{txid}/proof",
+	"/v2/deltas/{round}",
+	"/v2/stateproofs/{round}",
+	"/v2/transactions/{txid}",
+}
+
+// Cache stores response bodies by key. Implementations must be safe for
+// concurrent use.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+}
+
+// WithCache caches the successful responses of endpoints returning immutable
+// data, such as finalized blocks, the genesis file, confirmed transactions and
+// transaction proofs. Responses are keyed by their URL, including the round or
+// transaction ID and any query parameters. If cache is nil an in-memory LRU
+// cache holding DefaultCacheSize responses is used.
+func WithCache(cache Cache) ClientOption {
+	if cache == nil {
+		cache = NewLRUCache(DefaultCacheSize)
+	}
+	return WithMiddleware(CacheMiddleware(cache))
+}
+
+// CacheMiddleware serves requests for immutable data from cache, storing
+// successful responses.
+func CacheMiddleware(cache Cache) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if !cacheable(req) {
+				return next.RoundTrip(req)
+			}
+
+			key := req.URL.String()
+			if body, ok := cache.Get(key); ok {
+				return &http.Response{
+					Status:        "200 OK",
+					StatusCode:    http.StatusOK,
+					Proto:         "HTTP/1.1",
+					ProtoMajor:    1,
+					ProtoMinor:    1,
+					Header:        http.Header{},
+					Body:          ioutil.NopCloser(bytes.NewReader(body)),
+					ContentLength: int64(len(body)),
+					Request:       req,
+				}, nil
+			}
+
+			resp, err := next.RoundTrip(req)
+			if err != nil || resp.StatusCode != http.StatusOK {
+				return resp, err
+			}
+
+			body, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			cache.Set(key, body)
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			return resp, nil
+		})
+	}
+}
+
+func cacheable(req *http.Request) bool {
+	if req.Method != "GET" {
+		return false
+	}
+	name, _ := spanName(req.Method, req.URL.Path)
+	for _, endpoint := range immutableEndpoints {
+		// the server address may include a base path before the endpoint
+		if strings.HasSuffix(name, endpoint) {
+			return true
+		}
+	}
+	return false
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+// LRUCache is an in-memory Cache evicting the least recently used entry once
+// it holds size entries.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// NewLRUCache creates an LRUCache holding at most size entries.
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Get returns the value stored for key.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).value, true
+}
+
+// Set stores value for key, evicting the least recently used entry if the
+// cache is full.
+func (c *LRUCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruEntry).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
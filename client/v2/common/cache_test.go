@@ -0,0 +1,65 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", []byte("1"))
+	cache.Set("b", []byte("2"))
+
+	// reading a makes b the least recently used entry
+	value, ok := cache.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	cache.Set("c", []byte("3"))
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	_, ok = cache.Get("a")
+	assert.True(t, ok)
+	_, ok = cache.Get("c")
+	assert.True(t, ok)
+
+	cache.Set("a", []byte("4"))
+	value, _ = cache.Get("a")
+	assert.Equal(t, []byte("4"), value)
+}
+
+func TestClient_Cache(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.String()]++
+		if r.URL.Path == "/algod/v2/blocks/100" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		fmt.Fprint(w, r.URL.Path)
+	}))
+	defer server.Close()
+
+	c, err := MakeClientWithOptions(server.URL+"/algod", "API-Header", "ASDF", WithCache(nil))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		var response string
+		require.NoError(t, c.Get(context.Background(), &response, "/v2/blocks/5", nil, nil))
+		assert.Equal(t, "/algod/v2/blocks/5", response)
+		require.NoError(t, c.Get(context.Background(), &response, "/genesis", nil, nil))
+		require.NoError(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+		require.Error(t, c.Get(context.Background(), &response, "/v2/blocks/100", nil, nil))
+	}
+
+	// only successful responses of immutable endpoints are cached
+	assert.Equal(t, 1, requests["/algod/v2/blocks/5"])
+	assert.Equal(t, 1, requests["/algod/genesis"])
+	assert.Equal(t, 2, requests["/algod/v2/status"])
+	assert.Equal(t, 2, requests["/algod/v2/blocks/100"])
+}
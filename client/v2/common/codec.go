@@ -0,0 +1,44 @@
+package common
+
+import (
+	"github.com/algorand/go-algorand-sdk/v2/encoding"
+)
+
+// WithJSONCodec replaces the codec used to encode JSON request bodies and to
+// decode JSON responses, by default encoding.LenientJSON.
+func WithJSONCodec(codec encoding.Codec) ClientOption {
+	return func(c *Client) {
+		c.jsonCodec = codec
+	}
+}
+
+// WithMsgpackCodec replaces the codec used to decode msgpack responses, by
+// default encoding.LenientMsgpack.
+func WithMsgpackCodec(codec encoding.Codec) ClientOption {
+	return func(c *Client) {
+		c.msgpackCodec = codec
+	}
+}
+
+// WithStrictDecoding makes msgpack responses, such as blocks, fail to decode
+// when they have fields unknown to the SDK's types, with an error wrapping
+// msgpack.ErrUnknownField, instead of dropping those fields. It is meant for
+// applications which must notice new consensus fields, e.g. because they
+// re-encode what they read. JSON responses are still decoded leniently.
+func WithStrictDecoding() ClientOption {
+	return WithMsgpackCodec(encoding.Msgpack)
+}
+
+func (client *Client) jsonEncoding() encoding.Codec {
+	if client.jsonCodec == nil {
+		return encoding.LenientJSON
+	}
+	return client.jsonCodec
+}
+
+func (client *Client) msgpackEncoding() encoding.Codec {
+	if client.msgpackCodec == nil {
+		return encoding.LenientMsgpack
+	}
+	return client.msgpackCodec
+}
@@ -0,0 +1,88 @@
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+)
+
+// countingCodec counts the objects encoded and decoded through it.
+type countingCodec struct {
+	encoding.Codec
+	encoded, decoded int
+}
+
+func (c *countingCodec) Encode(obj interface{}) []byte {
+	c.encoded++
+	return c.Codec.Encode(obj)
+}
+
+func (c *countingCodec) Decode(b []byte, objptr interface{}) error {
+	c.decoded++
+	return c.Codec.Decode(b, objptr)
+}
+
+func (c *countingCodec) NewDecoder(r io.Reader) encoding.Decoder {
+	c.decoded++
+	return c.Codec.NewDecoder(r)
+}
+
+func TestClient_Codecs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/msgpack" {
+			w.Write(encoding.Msgpack.Encode(map[string]string{"a": "b"}))
+			return
+		}
+		w.Write([]byte(`{"a": "b", "unknown": 1}`))
+	}))
+	defer server.Close()
+
+	jsonCodec := &countingCodec{Codec: encoding.LenientJSON}
+	msgpackCodec := &countingCodec{Codec: encoding.LenientMsgpack}
+	c, err := MakeClientWithOptions(server.URL, "API-Header", "ASDF", WithJSONCodec(jsonCodec), WithMsgpackCodec(msgpackCodec))
+	require.NoError(t, err)
+
+	var response map[string]interface{}
+	require.NoError(t, c.Post(context.Background(), &response, "/json", struct {
+		X string `url:"-" json:"x"`
+	}{X: "y"}, nil, nil))
+	assert.Equal(t, "b", response["a"])
+	assert.Equal(t, 1, jsonCodec.encoded)
+	assert.Equal(t, 1, jsonCodec.decoded)
+
+	var msgpackResponse map[string]string
+	require.NoError(t, c.GetRawMsgpack(context.Background(), &msgpackResponse, "/msgpack", nil, nil))
+	assert.Equal(t, "b", msgpackResponse["a"])
+	assert.Equal(t, 1, msgpackCodec.decoded)
+}
+
+func TestClient_StrictDecoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(encoding.Msgpack.Encode(map[string]string{"a": "b", "unknown": "c"}))
+	}))
+	defer server.Close()
+
+	type response struct {
+		A string `codec:"a"`
+	}
+
+	c, err := MakeClient(server.URL, "API-Header", "ASDF")
+	require.NoError(t, err)
+	var lenient response
+	require.NoError(t, c.GetRawMsgpack(context.Background(), &lenient, "/block", nil, nil))
+	assert.Equal(t, "b", lenient.A)
+
+	c, err = MakeClientWithOptions(server.URL, "API-Header", "ASDF", WithStrictDecoding())
+	require.NoError(t, err)
+	var strict response
+	err = c.GetRawMsgpack(context.Background(), &strict, "/block", nil, nil)
+	assert.ErrorIs(t, err, msgpack.ErrUnknownField)
+}
@@ -10,17 +10,17 @@ import (
 	"net/http"
 	"net/url"
 
-	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
-	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/encoding"
 	"github.com/google/go-querystring/query"
 )
 
 // rawRequestPaths is a set of paths where the body should not be urlencoded
 var rawRequestPaths = map[string]bool{
-	"/v2/transactions":     true,
-	"/v2/teal/compile":     true,
-	"/v2/teal/disassemble": true,
-	"/v2/teal/dryrun":      true,
+	"/v2/transactions":          true,
+	"/v2/transactions/simulate": true,
+	"/v2/teal/compile":          true,
+	"/v2/teal/disassemble":      true,
+	"/v2/teal/dryrun":           true,
 }
 
 // Header is a struct for custom headers.
@@ -35,6 +35,16 @@ type Client struct {
 	apiHeader string
 	apiToken  string
 	headers   []*Header
+
+	transport  http.RoundTripper
+	middleware []Middleware
+
+	retryPolicy *RetryPolicy
+	rateLimiter *rateLimiter
+	pacer       *pacer
+
+	jsonCodec    encoding.Codec
+	msgpackCodec encoding.Codec
 }
 
 // MakeClient is the factory for constructing a Client for a given endpoint.
@@ -64,6 +74,23 @@ func MakeClientWithHeaders(address string, apiHeader, apiToken string, headers [
 	return
 }
 
+// ClientOption configures optional Client behavior.
+type ClientOption func(*Client)
+
+// MakeClientWithOptions is the factory for constructing a Client for a given
+// endpoint with optional behavior.
+func MakeClientWithOptions(address string, apiHeader, apiToken string, opts ...ClientOption) (c *Client, err error) {
+	c, err = MakeClient(address, apiHeader, apiToken)
+	if err != nil {
+		return
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	return
+}
+
 type BadRequest error
 type InvalidToken error
 type NotFound error
@@ -73,11 +100,11 @@ type InternalError error
 // If so, it returns the error.
 // Otherwise, it returns nil.
 func extractError(code int, errorBuf []byte) error {
-	if code == 200 {
+	if code >= 200 && code < 300 {
 		return nil
 	}
 
-	wrappedError := fmt.Errorf("HTTP %v: %s", code, errorBuf)
+	wrappedError := newAPIError(code, errorBuf)
 	switch code {
 	case 400:
 		return BadRequest(wrappedError)
@@ -106,7 +133,14 @@ func mergeRawQueries(q1, q2 string) string {
 // submitFormRaw is a helper used for submitting (ex.) GETs and POSTs to the server
 func (client *Client) submitFormRaw(ctx context.Context, path string, params interface{}, requestMethod string, encodeJSON bool, headers []*Header, body interface{}) (resp *http.Response, err error) {
 	queryURL := client.serverURL
-	queryURL.Path += path
+	// Path parameters are already escaped by EscapeParams, so keep the escaped
+	// form as the raw path rather than escaping it a second time.
+	unescapedPath, err := url.PathUnescape(path)
+	if err != nil {
+		return nil, err
+	}
+	queryURL.RawPath = queryURL.EscapedPath() + path
+	queryURL.Path += unescapedPath
 
 	var req *http.Request
 	var bodyReader io.Reader
@@ -119,18 +153,19 @@ func (client *Client) submitFormRaw(ctx context.Context, path string, params int
 		}
 	}
 
-	if requestMethod == "POST" && rawRequestPaths[path] {
-		reqBytes, ok := body.([]byte)
-		if !ok {
-			return nil, fmt.Errorf("couldn't decode raw body as bytes")
-		}
+	if reqBytes, ok := body.([]byte); ok && (requestMethod == "POST" || requestMethod == "DELETE") {
+		// Raw bodies are sent as-is, e.g. for paths with a path parameter
+		// which cannot be listed in rawRequestPaths.
 		bodyReader = bytes.NewBuffer(reqBytes)
+	} else if requestMethod == "POST" && rawRequestPaths[path] {
+		return nil, fmt.Errorf("couldn't decode raw body as bytes")
 	} else if encodeJSON {
-		jsonValue := json.Encode(params)
+		jsonValue := client.jsonEncoding().Encode(params)
 		bodyReader = bytes.NewBuffer(jsonValue)
 	}
 
 	queryURL.RawQuery = mergeRawQueries(queryURL.RawQuery, v.Encode())
+	queryURL.RawQuery = mergeRawQueries(queryURL.RawQuery, queryFromContext(ctx))
 
 	req, err = http.NewRequest(requestMethod, queryURL.String(), bodyReader)
 	if err != nil {
@@ -144,13 +179,16 @@ func (client *Client) submitFormRaw(ctx context.Context, path string, params int
 		req.Header.Add(header.Key, header.Value)
 	}
 	// Add the request headers.
+	for _, header := range headersFromContext(ctx) {
+		req.Header.Add(header.Key, header.Value)
+	}
 	for _, header := range headers {
 		req.Header.Add(header.Key, header.Value)
 	}
 
-	httpClient := &http.Client{}
+	httpClient := &http.Client{Transport: client.roundTripper()}
 	req = req.WithContext(ctx)
-	resp, err = httpClient.Do(req)
+	resp, err = client.doWithRetries(httpClient, req, path)
 
 	if err != nil {
 		select {
@@ -163,6 +201,21 @@ func (client *Client) submitFormRaw(ctx context.Context, path string, params int
 	return resp, nil
 }
 
+// send sends a single request once the rate limiter, if any, allows it.
+func (client *Client) send(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	if client.pacer != nil {
+		if err := client.pacer.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	if client.rateLimiter != nil {
+		if err := client.rateLimiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	return httpClient.Do(req)
+}
+
 func (client *Client) submitForm(ctx context.Context, response interface{}, path string, params interface{}, requestMethod string, encodeJSON bool, headers []*Header, body interface{}) error {
 	resp, err := client.submitFormRaw(ctx, path, params, requestMethod, encodeJSON, headers, body)
 	if err != nil {
@@ -185,7 +238,7 @@ func (client *Client) submitForm(ctx context.Context, response interface{}, path
 	}
 
 	// Attempt to unmarshal a response regardless of whether or not there was an error.
-	err = json.LenientDecode(bodyBytes, response)
+	err = client.jsonEncoding().Decode(bodyBytes, response)
 	if responseErr != nil {
 		// Even if there was an unmarshalling error, return the HTTP error first if there was one.
 		return responseErr
@@ -198,6 +251,12 @@ func (client *Client) Delete(ctx context.Context, response interface{}, path str
 	return client.submitForm(ctx, response, path, params, "DELETE", false /* encodeJSON */, headers, nil)
 }
 
+// DeleteWithBody performs a DELETE request with the given raw body to the
+// specific path against the server
+func (client *Client) DeleteWithBody(ctx context.Context, response interface{}, path string, params interface{}, headers []*Header, body []byte) error {
+	return client.submitForm(ctx, response, path, params, "DELETE", false /* encodeJSON */, headers, body)
+}
+
 // Get performs a GET request to the specific path against the server
 func (client *Client) Get(ctx context.Context, response interface{}, path string, params interface{}, headers []*Header) error {
 	return client.submitForm(ctx, response, path, params, "GET", false /* encodeJSON */, headers, nil)
@@ -238,7 +297,7 @@ func (client *Client) GetRawMsgpack(ctx context.Context, response interface{}, p
 		return extractError(resp.StatusCode, bodyBytes)
 	}
 
-	dec := msgpack.NewLenientDecoder(resp.Body)
+	dec := client.msgpackEncoding().NewDecoder(resp.Body)
 	return dec.Decode(&response)
 }
 
@@ -2,6 +2,8 @@ package common
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -59,3 +61,51 @@ func TestClient_Verbs(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_RawPostBody(t *testing.T) {
+	body := []byte{0x81, 0xa1, 0x61, 0x01}
+
+	var receivedBody []byte
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedBody, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer mockServer.Close()
+
+	c, err := MakeClient(mockServer.URL, "API-Header", "ASDF")
+	require.NoError(t, err)
+
+	// Paths with path parameters cannot be listed in rawRequestPaths, raw bodies are sent as-is.
+	var response string
+	err = c.Post(context.Background(), &response, "/v2/participation/ABCD", nil, nil, body)
+	require.NoError(t, err)
+	assert.Equal(t, body, receivedBody)
+
+	// Raw paths must be given a raw body.
+	err = c.Post(context.Background(), &response, "/v2/transactions", nil, nil, "not bytes")
+	require.EqualError(t, err, "couldn't decode raw body as bytes")
+
+	// DELETE requests may also have a body.
+	receivedBody = nil
+	err = c.DeleteWithBody(context.Background(), &response, "/v1/key", nil, nil, body)
+	require.NoError(t, err)
+	assert.Equal(t, body, receivedBody)
+}
+
+func TestClient_EscapedPathParams(t *testing.T) {
+	var receivedPath string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer mockServer.Close()
+
+	c, err := MakeClient(mockServer.URL, "API-Header", "ASDF")
+	require.NoError(t, err)
+
+	// Escaped path parameters must not be escaped a second time, and any 2xx status is a success.
+	var response string
+	path := fmt.Sprintf("/v2/catchup/%s", EscapeParams("1234#ABCD")...)
+	err = c.Post(context.Background(), &response, path, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "/v2/catchup/1234#ABCD", receivedPath)
+}
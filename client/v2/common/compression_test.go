@@ -0,0 +1,74 @@
+package common
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compress(t *testing.T, encoding string, data []byte) []byte {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	var err error
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "zlib":
+		w = zlib.NewWriter(&buf)
+	case "flate":
+		w, err = flate.NewWriter(&buf, flate.DefaultCompression)
+		require.NoError(t, err)
+	}
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestClient_Decompression(t *testing.T) {
+	body := []byte(`{"last-round":1234}`)
+
+	for _, encoding := range []string{"gzip", "zlib", "flate", ""} {
+		encoding := encoding
+		t.Run(encoding, func(t *testing.T) {
+			var acceptEncoding string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				acceptEncoding = r.Header.Get("Accept-Encoding")
+				switch encoding {
+				case "gzip":
+					w.Header().Set("Content-Encoding", "gzip")
+				case "zlib", "flate":
+					w.Header().Set("Content-Encoding", "deflate")
+				default:
+					w.Write(body)
+					return
+				}
+				w.Write(compress(t, encoding, body))
+			}))
+			defer server.Close()
+
+			// a custom transport which does not decompress responses itself
+			transport := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				return (&http.Transport{DisableCompression: true}).RoundTrip(req)
+			})
+			c, err := MakeClientWithOptions(server.URL, "API-Header", "ASDF", WithTransport(transport))
+			require.NoError(t, err)
+
+			var response struct {
+				LastRound uint64 `json:"last-round"`
+			}
+			require.NoError(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+			assert.Equal(t, uint64(1234), response.LastRound)
+			assert.Equal(t, "gzip, deflate", acceptEncoding)
+		})
+	}
+}
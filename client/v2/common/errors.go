@@ -0,0 +1,91 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// APIError is the error returned for a response with a non-2xx status. The
+// message and data fields are parsed from the JSON error body returned by
+// algod and indexer, use errors.As to retrieve it from a returned error.
+type APIError struct {
+	// StatusCode the HTTP status code of the response.
+	StatusCode int
+
+	// Message the node's error message.
+	Message string
+
+	// Data additional error details returned by the node, if any.
+	Data map[string]interface{}
+
+	// Body the raw response body.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("HTTP %v: %s", e.StatusCode, e.Body)
+}
+
+// NotFound reports whether the requested resource, e.g. an account, block or
+// transaction, was not found.
+func (e *APIError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// RateLimited reports whether the request was rejected by a rate limit.
+func (e *APIError) RateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// Overspend reports whether a transaction was rejected because its sender
+// cannot cover the amount and fees.
+func (e *APIError) Overspend() bool {
+	return e.StatusCode == http.StatusBadRequest && strings.Contains(e.Message, "overspend")
+}
+
+func newAPIError(code int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: code, Body: body}
+
+	var response struct {
+		Message string                 `json:"message"`
+		Data    map[string]interface{} `json:"data,omitempty"`
+	}
+	if len(body) > 0 && json.LenientDecode(body, &response) == nil {
+		apiErr.Message = response.Message
+		apiErr.Data = response.Data
+	}
+	return apiErr
+}
+
+// AsAPIError returns the APIError in err's chain, if any.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsNotFound reports whether err is an APIError for a resource which was not
+// found.
+func IsNotFound(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.NotFound()
+}
+
+// IsRateLimited reports whether err is an APIError for a rate limited request.
+func IsRateLimited(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.RateLimited()
+}
+
+// IsOverspend reports whether err is an APIError for a transaction rejected
+// because of an overspend.
+func IsOverspend(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.Overspend()
+}
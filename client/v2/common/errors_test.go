@@ -0,0 +1,58 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_APIError(t *testing.T) {
+	responses := map[string]struct {
+		status int
+		body   string
+	}{
+		"/v2/accounts/missing": {http.StatusNotFound, `{"message":"account not found"}`},
+		"/v2/transactions":     {http.StatusBadRequest, `{"message":"TransactionPool.Remember: transaction ABC: overspend (account XYZ)","data":{"pool":"full"}}`},
+		"/v2/status":           {http.StatusTooManyRequests, `rate limit exceeded`},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := responses[r.URL.Path]
+		w.WriteHeader(response.status)
+		w.Write([]byte(response.body))
+	}))
+	defer server.Close()
+
+	c, err := MakeClient(server.URL, "API-Header", "ASDF")
+	require.NoError(t, err)
+
+	var response string
+	err = c.Get(context.Background(), &response, "/v2/accounts/missing", nil, nil)
+	apiErr, ok := AsAPIError(fmt.Errorf("wrapped: %w", err))
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "account not found", apiErr.Message)
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsOverspend(err))
+	// the error message is unchanged
+	assert.EqualError(t, err, `HTTP 404: {"message":"account not found"}`)
+	_, ok = err.(NotFound)
+	assert.True(t, ok)
+
+	err = c.Post(context.Background(), &response, "/v2/transactions", nil, nil, []byte{1})
+	assert.True(t, IsOverspend(err))
+	apiErr, _ = AsAPIError(err)
+	assert.Equal(t, map[string]interface{}{"pool": "full"}, apiErr.Data)
+
+	err = c.Get(context.Background(), &response, "/v2/status", nil, nil)
+	assert.True(t, IsRateLimited(err))
+	apiErr, _ = AsAPIError(err)
+	assert.Empty(t, apiErr.Message)
+	assert.Equal(t, []byte("rate limit exceeded"), apiErr.Body)
+
+	assert.False(t, IsNotFound(fmt.Errorf("not an api error")))
+}
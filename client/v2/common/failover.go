@@ -0,0 +1,240 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SelectionPolicy determines the order in which a failover client tries its
+// endpoints.
+type SelectionPolicy int
+
+const (
+	// PriorityPolicy tries the endpoints in the order they were given.
+	PriorityPolicy SelectionPolicy = iota
+	// RoundRobinPolicy spreads requests across the endpoints in turn.
+	RoundRobinPolicy
+	// LowestLatencyPolicy tries the endpoint with the lowest observed latency first.
+	LowestLatencyPolicy
+)
+
+// unhealthyCooldown is how long an endpoint which failed a request is skipped
+// before it is tried again, unless a health check marks it healthy sooner.
+const unhealthyCooldown = 30 * time.Second
+
+// healthPath is the health check path shared by algod and indexer.
+const healthPath = "/health"
+
+type endpoint struct {
+	url            url.URL
+	unhealthyUntil time.Time
+	latency        time.Duration
+}
+
+// failoverTransport sends each request to the first endpoint, in policy order,
+// which does not fail with a connection error or a 5xx response.
+type failoverTransport struct {
+	mu        sync.Mutex
+	endpoints []*endpoint
+	policy    SelectionPolicy
+	next      int
+	transport http.RoundTripper
+}
+
+// MakeFailoverClient is the factory for constructing a Client which fails over
+// between multiple endpoints. Requests are sent to the endpoints in the order
+// determined by policy, moving on to the next endpoint on connection errors and
+// 5xx responses. All endpoints must accept the same API token.
+func MakeFailoverClient(addresses []string, policy SelectionPolicy, apiHeader, apiToken string, opts ...ClientOption) (c *Client, err error) {
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("at least one address is required")
+	}
+
+	ft := &failoverTransport{policy: policy, transport: http.DefaultTransport}
+	for _, address := range addresses {
+		u, err := url.Parse(address)
+		if err != nil {
+			return nil, err
+		}
+		ft.endpoints = append(ft.endpoints, &endpoint{url: *u})
+	}
+
+	c, err = MakeClientWithOptions(addresses[0], apiHeader, apiToken, opts...)
+	if err != nil {
+		return
+	}
+	// endpoints are reached through any transport given in the options
+	if c.transport != nil {
+		ft.transport = c.transport
+	}
+	c.transport = ft
+	return
+}
+
+// CheckEndpoints probes the health endpoint of every endpoint of a failover
+// client, recording which endpoints are healthy and their latency. It does
+// nothing for a client with a single endpoint.
+func (client *Client) CheckEndpoints(ctx context.Context) {
+	ft, ok := client.transport.(*failoverTransport)
+	if !ok {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, ep := range ft.endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+			ft.check(ctx, client, ep)
+		}(ep)
+	}
+	wg.Wait()
+}
+
+func (ft *failoverTransport) check(ctx context.Context, client *Client, ep *endpoint) {
+	u := ep.url
+	u.Path = strings.TrimSuffix(u.Path, "/") + healthPath
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set(client.apiHeader, client.apiToken)
+
+	start := time.Now()
+	resp, err := ft.transport.RoundTrip(req.WithContext(ctx))
+	if err == nil {
+		ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("HTTP %v", resp.StatusCode)
+		}
+	}
+	ft.record(ep, time.Since(start), err)
+}
+
+// record updates the health and latency of an endpoint after a request.
+func (ft *failoverTransport) record(ep *endpoint, latency time.Duration, err error) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	if err != nil {
+		ep.unhealthyUntil = time.Now().Add(unhealthyCooldown)
+		return
+	}
+	ep.unhealthyUntil = time.Time{}
+	if ep.latency == 0 {
+		ep.latency = latency
+	} else {
+		// exponentially weighted moving average
+		ep.latency = (ep.latency*3 + latency) / 4
+	}
+}
+
+// order returns the endpoints in the order they should be tried. Unhealthy
+// endpoints are tried last, in case every endpoint is failing.
+func (ft *failoverTransport) order() []*endpoint {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	ordered := make([]*endpoint, len(ft.endpoints))
+	copy(ordered, ft.endpoints)
+
+	switch ft.policy {
+	case RoundRobinPolicy:
+		start := ft.next % len(ordered)
+		ft.next++
+		ordered = append(ordered[start:], ordered[:start]...)
+	case LowestLatencyPolicy:
+		// insertion sort keeps the priority order between equal latencies,
+		// endpoints without a measured latency go last
+		for i := 1; i < len(ordered); i++ {
+			for j := i; j > 0 && fasterThan(ordered[j], ordered[j-1]); j-- {
+				ordered[j], ordered[j-1] = ordered[j-1], ordered[j]
+			}
+		}
+	}
+
+	now := time.Now()
+	healthy := ordered[:0:0]
+	var unhealthy []*endpoint
+	for _, ep := range ordered {
+		if now.Before(ep.unhealthyUntil) {
+			unhealthy = append(unhealthy, ep)
+		} else {
+			healthy = append(healthy, ep)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func fasterThan(a, b *endpoint) bool {
+	if a.latency == 0 {
+		return false
+	}
+	return b.latency == 0 || a.latency < b.latency
+}
+
+// RoundTrip implements http.RoundTripper. The request URL is expected to be
+// relative to the first endpoint, and is rewritten for each endpoint tried.
+func (ft *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	primary := ft.endpoints[0].url
+	relative := strings.TrimPrefix(req.URL.EscapedPath(), strings.TrimSuffix(primary.EscapedPath(), "/"))
+
+	var lastErr error
+	for i, ep := range ft.order() {
+		attempt := req.Clone(req.Context())
+		if i > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				break
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attempt.Body = body
+		}
+
+		u := *req.URL
+		u.Scheme = ep.url.Scheme
+		u.Host = ep.url.Host
+		u.RawPath = strings.TrimSuffix(ep.url.EscapedPath(), "/") + relative
+		u.Path, _ = url.PathUnescape(u.RawPath)
+		attempt.URL = &u
+		attempt.Host = ""
+
+		start := time.Now()
+		resp, err := ft.transport.RoundTrip(attempt)
+		if err == nil && resp.StatusCode < 500 {
+			ft.record(ep, time.Since(start), nil)
+			return resp, nil
+		}
+
+		if err != nil {
+			lastErr = err
+		} else {
+			lastErr = fmt.Errorf("HTTP %v from %s", resp.StatusCode, ep.url.Host)
+		}
+		ft.record(ep, time.Since(start), lastErr)
+
+		if req.Context().Err() != nil {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			return nil, req.Context().Err()
+		}
+		if i == len(ft.endpoints)-1 && resp != nil {
+			// return the last 5xx response so its error body reaches the caller
+			return resp, nil
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,119 @@
+package common
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	status int
+	delay  time.Duration
+	paths  []string
+	bodies []string
+}
+
+func newRecordingServer(status int) *recordingServer {
+	rs := &recordingServer{status: status}
+	rs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		rs.mu.Lock()
+		rs.paths = append(rs.paths, r.URL.Path)
+		rs.bodies = append(rs.bodies, string(body))
+		status, delay := rs.status, rs.delay
+		rs.mu.Unlock()
+
+		time.Sleep(delay)
+		w.WriteHeader(status)
+		w.Write([]byte(`"ok"`))
+	}))
+	return rs
+}
+
+func (rs *recordingServer) requests() []string {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return append([]string{}, rs.paths...)
+}
+
+func TestFailoverClient_FailsOver(t *testing.T) {
+	failing := newRecordingServer(http.StatusInternalServerError)
+	defer failing.Close()
+	healthy := newRecordingServer(http.StatusOK)
+	defer healthy.Close()
+
+	c, err := MakeFailoverClient([]string{failing.URL + "/algod", healthy.URL}, PriorityPolicy, "API-Header", "ASDF")
+	require.NoError(t, err)
+
+	var response string
+	require.NoError(t, c.Post(context.Background(), &response, "/v2/teal/compile", nil, nil, []byte("int 1")))
+	assert.Equal(t, []string{"/algod/v2/teal/compile"}, failing.requests())
+	assert.Equal(t, []string{"/v2/teal/compile"}, healthy.requests())
+	assert.Equal(t, []string{"int 1"}, healthy.bodies)
+
+	// the failing endpoint is skipped until its cooldown expires
+	require.NoError(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+	assert.Len(t, failing.requests(), 1)
+	assert.Len(t, healthy.requests(), 2)
+}
+
+func TestFailoverClient_AllFailing(t *testing.T) {
+	first := newRecordingServer(http.StatusServiceUnavailable)
+	defer first.Close()
+	second := newRecordingServer(http.StatusInternalServerError)
+	defer second.Close()
+
+	c, err := MakeFailoverClient([]string{first.URL, second.URL}, PriorityPolicy, "API-Header", "ASDF")
+	require.NoError(t, err)
+
+	var response string
+	err = c.Get(context.Background(), &response, "/v2/status", nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 500")
+}
+
+func TestFailoverClient_RoundRobin(t *testing.T) {
+	first := newRecordingServer(http.StatusOK)
+	defer first.Close()
+	second := newRecordingServer(http.StatusOK)
+	defer second.Close()
+
+	c, err := MakeFailoverClient([]string{first.URL, second.URL}, RoundRobinPolicy, "API-Header", "ASDF")
+	require.NoError(t, err)
+
+	var response string
+	for i := 0; i < 4; i++ {
+		require.NoError(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+	}
+	assert.Len(t, first.requests(), 2)
+	assert.Len(t, second.requests(), 2)
+}
+
+func TestFailoverClient_LowestLatency(t *testing.T) {
+	slow := newRecordingServer(http.StatusOK)
+	slow.delay = 50 * time.Millisecond
+	defer slow.Close()
+	fast := newRecordingServer(http.StatusOK)
+	defer fast.Close()
+
+	c, err := MakeFailoverClient([]string{slow.URL, fast.URL}, LowestLatencyPolicy, "API-Header", "ASDF")
+	require.NoError(t, err)
+
+	c.CheckEndpoints(context.Background())
+	assert.Equal(t, []string{"/health"}, slow.requests())
+	assert.Equal(t, []string{"/health"}, fast.requests())
+
+	var response string
+	require.NoError(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+	assert.Len(t, slow.requests(), 1)
+	assert.Equal(t, []string{"/health", "/v2/status"}, fast.requests())
+}
@@ -0,0 +1,91 @@
+package common
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// RequestInfo describes a single HTTP request sent by a client.
+type RequestInfo struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Latency    time.Duration
+
+	// Err the transport error, if the request failed without a response.
+	Err error
+
+	// RequestBody and ResponseBody are only set when bodies are captured.
+	RequestBody  []byte
+	ResponseBody []byte
+}
+
+// RequestHook is called after every HTTP request sent by a client, including
+// retries.
+type RequestHook func(info RequestInfo)
+
+// RequestHookOptions configures what is passed to a RequestHook.
+type RequestHookOptions struct {
+	// CaptureBodies include the request and response bodies.
+	CaptureBodies bool
+
+	// Redact, if set, is applied to captured bodies before they are passed to
+	// the hook.
+	Redact func(body []byte) []byte
+}
+
+// WithRequestHook calls hook after every HTTP request sent by the client. The
+// API token header is never passed to the hook.
+func WithRequestHook(hook RequestHook, options RequestHookOptions) ClientOption {
+	return func(c *Client) {
+		// the hook is outermost so that it sees requests as the client built them
+		c.middleware = append([]Middleware{hookMiddleware(hook, options)}, c.middleware...)
+	}
+}
+
+func hookMiddleware(hook RequestHook, options RequestHookOptions) Middleware {
+	redact := func(body []byte) []byte {
+		if options.Redact == nil || body == nil {
+			return body
+		}
+		return options.Redact(body)
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			info := RequestInfo{Method: req.Method, URL: req.URL.String()}
+
+			if options.CaptureBodies && req.GetBody != nil {
+				if body, err := req.GetBody(); err == nil {
+					info.RequestBody, _ = ioutil.ReadAll(body)
+					body.Close()
+					info.RequestBody = redact(info.RequestBody)
+				}
+			}
+
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			info.Latency = time.Since(start)
+			info.Err = err
+
+			if resp != nil {
+				info.StatusCode = resp.StatusCode
+				if options.CaptureBodies {
+					body, readErr := ioutil.ReadAll(resp.Body)
+					resp.Body.Close()
+					resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+					if readErr != nil {
+						hook(info)
+						return nil, readErr
+					}
+					info.ResponseBody = redact(body)
+				}
+			}
+
+			hook(info)
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_RequestHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(`"secret response"`))
+	}))
+	defer server.Close()
+
+	var infos []RequestInfo
+	redact := func(body []byte) []byte {
+		return bytes.Replace(body, []byte("secret"), []byte("******"), -1)
+	}
+	c, err := MakeClientWithOptions(server.URL, "API-Header", "ASDF", WithRequestHook(func(info RequestInfo) {
+		infos = append(infos, info)
+	}, RequestHookOptions{CaptureBodies: true, Redact: redact}))
+	require.NoError(t, err)
+
+	var response string
+	require.NoError(t, c.Post(context.Background(), &response, "/v2/teal/compile", nil, nil, []byte("secret program")))
+	// the response body is still available to the caller
+	assert.Equal(t, `"secret response"`, response)
+
+	require.Error(t, c.Get(context.Background(), &response, "/missing", nil, nil))
+
+	require.Len(t, infos, 2)
+	assert.Equal(t, "POST", infos[0].Method)
+	assert.True(t, strings.HasSuffix(infos[0].URL, "/v2/teal/compile"))
+	assert.Equal(t, http.StatusOK, infos[0].StatusCode)
+	assert.Equal(t, "****** program", string(infos[0].RequestBody))
+	assert.Equal(t, `"****** response"`, string(infos[0].ResponseBody))
+	assert.True(t, infos[0].Latency > 0)
+
+	assert.Equal(t, "GET", infos[1].Method)
+	assert.Equal(t, http.StatusNotFound, infos[1].StatusCode)
+	assert.Nil(t, infos[1].RequestBody)
+}
+
+func TestClient_RequestHookWithoutBodies(t *testing.T) {
+	var info RequestInfo
+	c, err := MakeClientWithOptions("http://127.0.0.1:0", "API-Header", "ASDF", WithRequestHook(func(i RequestInfo) {
+		info = i
+	}, RequestHookOptions{}))
+	require.NoError(t, err)
+
+	var response string
+	require.Error(t, c.Post(context.Background(), &response, "/v2/teal/compile", nil, nil, []byte("int 1")))
+	assert.Error(t, info.Err)
+	assert.Equal(t, 0, info.StatusCode)
+	assert.Nil(t, info.RequestBody)
+}
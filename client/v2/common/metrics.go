@@ -0,0 +1,40 @@
+package common
+
+import (
+	"net/http"
+	"time"
+)
+
+// MetricsRecorder records the outcome of every API request. It is a minimal
+// interface so that the SDK does not depend on a metrics library; a Prometheus
+// implementation would increment a CounterVec and observe a HistogramVec, both
+// labelled by endpoint and status code.
+type MetricsRecorder interface {
+	// ObserveRequest is called once per HTTP request with the endpoint name,
+	// e.g. "GET /v2/blocks/{round}", the response status code, or 0 if the
+	// request failed without a response, and the request latency.
+	ObserveRequest(endpoint string, statusCode int, latency time.Duration)
+}
+
+// MetricsMiddleware reports every HTTP request to recorder.
+func MetricsMiddleware(recorder MetricsRecorder) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			endpoint, _ := spanName(req.Method, req.URL.Path)
+
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			statusCode := 0
+			if resp != nil {
+				statusCode = resp.StatusCode
+			}
+			recorder.ObserveRequest(endpoint, statusCode, time.Since(start))
+			return resp, err
+		})
+	}
+}
+
+// WithMetrics reports every request sent by the client to recorder.
+func WithMetrics(recorder MetricsRecorder) ClientOption {
+	return WithMiddleware(MetricsMiddleware(recorder))
+}
@@ -0,0 +1,56 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type observation struct {
+	endpoint   string
+	statusCode int
+	latency    time.Duration
+}
+
+type testRecorder struct {
+	observations []observation
+}
+
+func (r *testRecorder) ObserveRequest(endpoint string, statusCode int, latency time.Duration) {
+	r.observations = append(r.observations, observation{endpoint, statusCode, latency})
+}
+
+func TestClient_Metrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/blocks/8" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		w.Write([]byte(`"ok"`))
+	}))
+	defer server.Close()
+
+	recorder := &testRecorder{}
+	c, err := MakeClientWithOptions(server.URL, "API-Header", "ASDF", WithMetrics(recorder))
+	require.NoError(t, err)
+
+	var response string
+	require.NoError(t, c.Get(context.Background(), &response, "/v2/blocks/7", nil, nil))
+	require.Error(t, c.Get(context.Background(), &response, "/v2/blocks/8", nil, nil))
+
+	require.Len(t, recorder.observations, 2)
+	assert.Equal(t, "GET /v2/blocks/{round}", recorder.observations[0].endpoint)
+	assert.Equal(t, http.StatusOK, recorder.observations[0].statusCode)
+	assert.True(t, recorder.observations[0].latency > 0)
+	assert.Equal(t, http.StatusInternalServerError, recorder.observations[1].statusCode)
+
+	// requests which fail without a response are recorded with status code 0
+	c, err = MakeClientWithOptions("http://127.0.0.1:0", "API-Header", "ASDF", WithMetrics(recorder))
+	require.NoError(t, err)
+	require.Error(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+	assert.Equal(t, 0, recorder.observations[2].statusCode)
+}
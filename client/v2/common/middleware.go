@@ -0,0 +1,60 @@
+package common
+
+import (
+	"net/http"
+)
+
+// RoundTripperFunc adapts a function to an http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the http.RoundTripper used to send requests, e.g. to sign
+// requests, add authentication or record instrumentation.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// WithTransport sends requests with the given http.RoundTripper instead of
+// http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithMiddleware adds middleware around the client's transport. The first
+// middleware given is the outermost, seeing each request first and each
+// response last.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// MakeClientWithTransport is the factory for constructing a Client for a given
+// endpoint with additional user defined headers and a custom transport.
+func MakeClientWithTransport(address string, apiHeader, apiToken string, headers []*Header, transport http.RoundTripper) (c *Client, err error) {
+	c, err = MakeClientWithHeaders(address, apiHeader, apiToken, headers)
+	if err != nil {
+		return
+	}
+
+	c.transport = transport
+	return
+}
+
+// roundTripper returns the client's transport wrapped in its middleware, with
+// responses decompressed before they reach the middleware.
+func (client *Client) roundTripper() http.RoundTripper {
+	rt := client.transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	rt = decompressingTransport{next: rt}
+	for i := len(client.middleware) - 1; i >= 0; i-- {
+		rt = client.middleware[i](rt)
+	}
+	return rt
+}
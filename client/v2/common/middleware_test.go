@@ -0,0 +1,68 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Middleware(t *testing.T) {
+	var receivedHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeaders = r.Header
+		w.Write([]byte(`"ok"`))
+	}))
+	defer server.Close()
+
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				req.Header.Add("X-Middleware", name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	var transportCalls int
+	transport := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		transportCalls++
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	c, err := MakeClientWithOptions(server.URL, "API-Header", "ASDF",
+		WithTransport(transport), WithMiddleware(tag("outer"), tag("inner")))
+	require.NoError(t, err)
+
+	var response string
+	require.NoError(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+	assert.Equal(t, []string{"outer", "inner"}, order)
+	assert.Equal(t, []string{"outer", "inner"}, receivedHeaders.Values("X-Middleware"))
+	assert.Equal(t, 1, transportCalls)
+}
+
+func TestMakeClientWithTransport(t *testing.T) {
+	var called bool
+	transport := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		assert.Equal(t, "value", req.Header.Get("X-Custom"))
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"ok"`))
+	}))
+	defer server.Close()
+
+	c, err := MakeClientWithTransport(server.URL, "API-Header", "ASDF", []*Header{{Key: "X-Custom", Value: "value"}}, transport)
+	require.NoError(t, err)
+
+	var response string
+	require.NoError(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+	assert.True(t, called)
+}
@@ -0,0 +1,7 @@
+package models
+
+// CatchpointAbortResponse an catchpoint abort response.
+type CatchpointAbortResponse struct {
+	// CatchupMessage catchup abort response string
+	CatchupMessage string `json:"catchup-message"`
+}
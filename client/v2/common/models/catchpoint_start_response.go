@@ -0,0 +1,7 @@
+package models
+
+// CatchpointStartResponse an catchpoint start response.
+type CatchpointStartResponse struct {
+	// CatchupMessage catchup start response string
+	CatchupMessage string `json:"catchup-message"`
+}
@@ -0,0 +1,29 @@
+package models
+
+// ParticipationKey represents a participation key used by the node.
+type ParticipationKey struct {
+	// Address address the key was generated for.
+	Address string `json:"address"`
+
+	// EffectiveFirstValid when registered, this is the first round it may be used.
+	EffectiveFirstValid uint64 `json:"effective-first-valid,omitempty"`
+
+	// EffectiveLastValid when registered, this is the last round it may be used.
+	EffectiveLastValid uint64 `json:"effective-last-valid,omitempty"`
+
+	// Id the key's ParticipationID.
+	Id string `json:"id"`
+
+	// Key accountParticipation describes the parameters used by this account in
+	// consensus protocol.
+	Key AccountParticipation `json:"key"`
+
+	// LastBlockProposal round when this key was last used to propose a block.
+	LastBlockProposal uint64 `json:"last-block-proposal,omitempty"`
+
+	// LastStateProof round when this key was last used to generate a state proof.
+	LastStateProof uint64 `json:"last-state-proof,omitempty"`
+
+	// LastVote round when this key was last used to vote.
+	LastVote uint64 `json:"last-vote,omitempty"`
+}
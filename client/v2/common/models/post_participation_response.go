@@ -0,0 +1,7 @@
+package models
+
+// PostParticipationResponse participation ID of the submission
+type PostParticipationResponse struct {
+	// PartId encoding of the participation ID.
+	PartId string `json:"partId"`
+}
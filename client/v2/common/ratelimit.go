@@ -0,0 +1,73 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the rate of requests sent by the client with a token
+// bucket refilled at requestsPerSecond and holding at most burst tokens.
+// Requests, including retries, wait until a token is available. A
+// requestsPerSecond of zero or less disables the limit.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = newRateLimiter(requestsPerSecond, burst)
+	}
+}
+
+type rateLimiter struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastFill time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:     rate,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		lastFill: time.Now(),
+	}
+}
+
+// reserve takes a token, returning how long the caller must wait before the
+// token may be used.
+func (rl *rateLimiter) reserve(now time.Time) time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.tokens += now.Sub(rl.lastFill).Seconds() * rl.rate
+	if rl.tokens > rl.burst {
+		rl.tokens = rl.burst
+	}
+	rl.lastFill = now
+
+	rl.tokens--
+	if rl.tokens >= 0 || rl.rate <= 0 {
+		return 0
+	}
+	return time.Duration(-rl.tokens / rl.rate * float64(time.Second))
+}
+
+// wait blocks until a token is available or ctx is done.
+func (rl *rateLimiter) wait(ctx context.Context) error {
+	delay := rl.reserve(time.Now())
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	rl := newRateLimiter(10, 2)
+	now := rl.lastFill
+
+	// the burst is available immediately
+	assert.Equal(t, time.Duration(0), rl.reserve(now))
+	assert.Equal(t, time.Duration(0), rl.reserve(now))
+
+	// then tokens arrive every 100ms
+	assert.Equal(t, 100*time.Millisecond, rl.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, rl.reserve(now))
+
+	// the bucket refills over time, up to the burst
+	assert.Equal(t, time.Duration(0), rl.reserve(now.Add(time.Second)))
+	assert.Equal(t, time.Duration(0), rl.reserve(now.Add(time.Second)))
+	assert.Equal(t, 100*time.Millisecond, rl.reserve(now.Add(time.Second)))
+}
+
+func TestRateLimiter_WaitCancelled(t *testing.T) {
+	rl := newRateLimiter(0.001, 1)
+	require.NoError(t, rl.wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Equal(t, context.Canceled, rl.wait(ctx))
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"ok"`))
+	}))
+	defer server.Close()
+
+	c, err := MakeClientWithOptions(server.URL, "API-Header", "ASDF", WithRateLimit(50, 1))
+	require.NoError(t, err)
+
+	start := time.Now()
+	var response string
+	for i := 0; i < 3; i++ {
+		require.NoError(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+	}
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+}
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"context"
+	"net/url"
+)
+
+type requestHeadersKey struct{}
+type requestQueryKey struct{}
+
+// ContextWithHeaders returns a context which adds headers to every request made
+// with it, in addition to the client's headers and any headers passed to Do.
+// This allows per-request headers, such as gateway signatures or tenancy
+// headers, to be passed through helpers which do not take headers themselves.
+func ContextWithHeaders(ctx context.Context, headers ...*Header) context.Context {
+	existing, _ := ctx.Value(requestHeadersKey{}).([]*Header)
+	merged := append(append([]*Header{}, existing...), headers...)
+	return context.WithValue(ctx, requestHeadersKey{}, merged)
+}
+
+// ContextWithQuery returns a context which adds query parameters to every
+// request made with it.
+func ContextWithQuery(ctx context.Context, query url.Values) context.Context {
+	merged := url.Values{}
+	if existing, ok := ctx.Value(requestQueryKey{}).(url.Values); ok {
+		for key, values := range existing {
+			merged[key] = append(merged[key], values...)
+		}
+	}
+	for key, values := range query {
+		merged[key] = append(merged[key], values...)
+	}
+	return context.WithValue(ctx, requestQueryKey{}, merged)
+}
+
+func headersFromContext(ctx context.Context) []*Header {
+	headers, _ := ctx.Value(requestHeadersKey{}).([]*Header)
+	return headers
+}
+
+func queryFromContext(ctx context.Context) string {
+	query, _ := ctx.Value(requestQueryKey{}).(url.Values)
+	return query.Encode()
+}
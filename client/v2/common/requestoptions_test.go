@@ -0,0 +1,46 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ContextHeadersAndQuery(t *testing.T) {
+	var received *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		w.Write([]byte(`"ok"`))
+	}))
+	defer server.Close()
+
+	c, err := MakeClientWithHeaders(server.URL, "API-Header", "ASDF", []*Header{{Key: "X-Client", Value: "client"}})
+	require.NoError(t, err)
+
+	ctx := ContextWithHeaders(context.Background(), &Header{Key: "X-Tenant", Value: "a"})
+	ctx = ContextWithHeaders(ctx, &Header{Key: "X-Signature", Value: "sig"})
+	ctx = ContextWithQuery(ctx, url.Values{"tenant": {"a"}})
+
+	params := struct {
+		Max uint64 `url:"max,omitempty"`
+	}{Max: 5}
+	var response string
+	require.NoError(t, c.Get(ctx, &response, "/v2/status", params, []*Header{{Key: "X-Call", Value: "call"}}))
+
+	assert.Equal(t, "client", received.Header.Get("X-Client"))
+	assert.Equal(t, "a", received.Header.Get("X-Tenant"))
+	assert.Equal(t, "sig", received.Header.Get("X-Signature"))
+	assert.Equal(t, "call", received.Header.Get("X-Call"))
+	assert.Equal(t, "5", received.URL.Query().Get("max"))
+	assert.Equal(t, "a", received.URL.Query().Get("tenant"))
+
+	// contexts without options leave requests unchanged
+	require.NoError(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+	assert.Empty(t, received.Header.Get("X-Tenant"))
+	assert.Empty(t, received.URL.RawQuery)
+}
@@ -0,0 +1,218 @@
+package common
+
+import (
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultSafePostPaths are the POST paths which do not modify node state and
+// may be retried.
+var DefaultSafePostPaths = map[string]bool{
+	"/v2/teal/compile":          true,
+	"/v2/teal/disassemble":      true,
+	"/v2/teal/dryrun":           true,
+	"/v2/transactions/simulate": true,
+}
+
+// RetryPolicy configures automatic retries of failed requests. GET requests and
+// POST requests to SafePostPaths are retried after connection errors and 429,
+// 502, 503 and 504 responses.
+type RetryPolicy struct {
+	// MaxRetries the maximum number of retries of a single request.
+	MaxRetries int
+
+	// InitialBackoff the delay before the first retry, doubled for each
+	// subsequent retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff the maximum delay between retries.
+	MaxBackoff time.Duration
+
+	// Jitter the fraction of each delay which is randomized, between 0 and 1.
+	Jitter float64
+
+	// Budget the maximum total time spent waiting between retries of a single
+	// request, zero for no limit.
+	Budget time.Duration
+
+	// SafePostPaths the POST paths which may be retried.
+	SafePostPaths map[string]bool
+
+	// Pace when set, a 429 response pauses every request of the client until
+	// the delay before its retry has elapsed, so that concurrent and subsequent
+	// requests slow down to the server's pace instead of also being rejected.
+	Pace bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy retrying up to 3 times with
+// exponential backoff starting at 250ms.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Jitter:         0.2,
+		Budget:         15 * time.Second,
+		SafePostPaths:  DefaultSafePostPaths,
+	}
+}
+
+// WithRetryPolicy enables automatic retries of failed requests.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = &policy
+		c.pacer = nil
+		if policy.Pace {
+			c.pacer = &pacer{}
+		}
+	}
+}
+
+func (p *RetryPolicy) retryable(req *http.Request, path string) bool {
+	switch req.Method {
+	case "GET":
+		return true
+	case "POST":
+		return p.SafePostPaths[path] && (req.Body == nil || req.GetBody != nil)
+	default:
+		return false
+	}
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before the given retry, starting at 0.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 0; i < retry && (p.MaxBackoff == 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		spread := float64(delay) * p.Jitter
+		delay = time.Duration(float64(delay) - spread + rand.Float64()*2*spread)
+	}
+	return delay
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+// doWithRetries sends the request, retrying it according to the client's
+// retry policy.
+func (client *Client) doWithRetries(httpClient *http.Client, req *http.Request, path string) (*http.Response, error) {
+	policy := client.retryPolicy
+	if policy == nil || !policy.retryable(req, path) {
+		return client.send(httpClient, req)
+	}
+
+	var waited time.Duration
+	for retry := 0; ; retry++ {
+		resp, err := client.send(httpClient, req)
+		if req.Context().Err() != nil || retry >= policy.MaxRetries {
+			return resp, err
+		}
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		delay := policy.backoff(retry)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				delay = after
+			}
+		}
+		if delay < 0 {
+			delay = 0
+		}
+		if policy.Budget > 0 && waited+delay > policy.Budget {
+			return resp, err
+		}
+		if client.pacer != nil && resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			client.pacer.pause(delay)
+		}
+
+		if resp != nil {
+			ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		waited += delay
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// pacer pauses all the requests of a client after it was rate limited.
+type pacer struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// pause delays requests until at least delay from now.
+func (p *pacer) pause(delay time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if until := time.Now().Add(delay); until.After(p.until) {
+		p.until = until
+	}
+}
+
+// wait blocks until the pause is over or ctx is done.
+func (p *pacer) wait(ctx context.Context) error {
+	p.mu.Lock()
+	delay := time.Until(p.until)
+	p.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
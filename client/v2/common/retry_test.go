@@ -0,0 +1,167 @@
+package common
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.Jitter = 0
+	return policy
+}
+
+// flakyServer responds with the given statuses in turn, then with 200.
+func flakyServer(statuses ...int) (*httptest.Server, *[]string) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) <= len(statuses) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(statuses[len(bodies)-1])
+			return
+		}
+		w.Write([]byte(`"ok"`))
+	}))
+	return server, &bodies
+}
+
+func TestClient_RetriesGet(t *testing.T) {
+	server, requests := flakyServer(http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	defer server.Close()
+
+	c, err := MakeClientWithOptions(server.URL, "API-Header", "ASDF", WithRetryPolicy(testRetryPolicy()))
+	require.NoError(t, err)
+
+	var response string
+	require.NoError(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+	assert.Len(t, *requests, 3)
+}
+
+func TestClient_RetriesSafePost(t *testing.T) {
+	server, requests := flakyServer(http.StatusBadGateway)
+	defer server.Close()
+
+	c, err := MakeClientWithOptions(server.URL, "API-Header", "ASDF", WithRetryPolicy(testRetryPolicy()))
+	require.NoError(t, err)
+
+	var response string
+	require.NoError(t, c.Post(context.Background(), &response, "/v2/teal/compile", nil, nil, []byte("int 1")))
+	assert.Equal(t, []string{"int 1", "int 1"}, *requests)
+}
+
+func TestClient_DoesNotRetryUnsafePost(t *testing.T) {
+	server, requests := flakyServer(http.StatusServiceUnavailable)
+	defer server.Close()
+
+	c, err := MakeClientWithOptions(server.URL, "API-Header", "ASDF", WithRetryPolicy(testRetryPolicy()))
+	require.NoError(t, err)
+
+	var response string
+	require.Error(t, c.Post(context.Background(), &response, "/v2/transactions", nil, nil, []byte{1}))
+	assert.Len(t, *requests, 1)
+}
+
+func TestClient_RetryLimits(t *testing.T) {
+	server, requests := flakyServer(http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	policy := testRetryPolicy()
+	policy.MaxRetries = 1
+	c, err := MakeClientWithOptions(server.URL, "API-Header", "ASDF", WithRetryPolicy(policy))
+	require.NoError(t, err)
+
+	var response string
+	err = c.Get(context.Background(), &response, "/v2/status", nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 503")
+	assert.Len(t, *requests, 2)
+
+	// statuses which are not transient are returned immediately
+	server, requests = flakyServer(http.StatusNotFound)
+	defer server.Close()
+	c, err = MakeClientWithOptions(server.URL, "API-Header", "ASDF", WithRetryPolicy(testRetryPolicy()))
+	require.NoError(t, err)
+	require.Error(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+	assert.Len(t, *requests, 1)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(0))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 300*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 300*time.Millisecond, policy.backoff(10))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := policy.backoff(0)
+		assert.True(t, delay >= 50*time.Millisecond && delay <= 150*time.Millisecond, delay)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	_, ok := retryAfter(resp)
+	assert.False(t, ok)
+
+	resp.Header.Set("Retry-After", "3")
+	delay, ok := retryAfter(resp)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, delay)
+
+	resp.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	delay, ok = retryAfter(resp)
+	assert.True(t, ok)
+	assert.True(t, delay > 58*time.Second && delay <= time.Minute, delay)
+}
+
+func TestClient_RetryPacing(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		times = append(times, time.Now())
+		if len(times) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`"ok"`))
+	}))
+	defer server.Close()
+
+	policy := testRetryPolicy()
+	policy.InitialBackoff = 100 * time.Millisecond
+	policy.Pace = true
+	c, err := MakeClientWithOptions(server.URL, "API-Header", "ASDF", WithRetryPolicy(policy))
+	require.NoError(t, err)
+
+	var response string
+	require.NoError(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+
+	// every request waits while the client is paused
+	c.pacer.pause(100 * time.Millisecond)
+	start := time.Now()
+	require.NoError(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+	assert.True(t, time.Since(start) >= 90*time.Millisecond)
+
+	require.Len(t, times, 3)
+	assert.True(t, times[1].Sub(times[0]) >= 90*time.Millisecond)
+
+	// waiting for the pause stops when the context is done
+	c.pacer.pause(time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, c.pacer.wait(ctx))
+}
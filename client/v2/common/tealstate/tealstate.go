@@ -0,0 +1,60 @@
+// Package tealstate converts the application state returned by the REST APIs
+// to and from types.TealKeyValue. It is kept apart from the models package,
+// which is generated.
+package tealstate
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// DecodeKeyValues converts application state as returned by the REST APIs,
+// with keys and byte values in base64, into a types.TealKeyValue, whose Decode
+// method returns it as a map of Go values.
+func DecodeKeyValues(kvs []models.TealKeyValue) (types.TealKeyValue, error) {
+	state := make(types.TealKeyValue, len(kvs))
+	for _, kv := range kvs {
+		key, err := base64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", kv.Key, err)
+		}
+		value := types.TealValue{Type: types.TealType(kv.Value.Type), Uint: kv.Value.Uint}
+		if value.Type == types.TealBytesType {
+			bytes, err := base64.StdEncoding.DecodeString(kv.Value.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of key %q: %w", kv.Key, err)
+			}
+			value.Bytes = string(bytes)
+		}
+		state[string(key)] = value
+	}
+	return state, nil
+}
+
+// EncodeKeyValues is the inverse of DecodeKeyValues, with the keys in sorted
+// order.
+func EncodeKeyValues(state types.TealKeyValue) []models.TealKeyValue {
+	keys := make([]string, 0, len(state))
+	for key := range state {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]models.TealKeyValue, len(keys))
+	for i, key := range keys {
+		value := state[key]
+		kvs[i] = models.TealKeyValue{
+			Key: base64.StdEncoding.EncodeToString([]byte(key)),
+			Value: models.TealValue{
+				Type:  uint64(value.Type),
+				Bytes: base64.StdEncoding.EncodeToString([]byte(value.Bytes)),
+				Uint:  value.Uint,
+			},
+		}
+	}
+	return kvs
+}
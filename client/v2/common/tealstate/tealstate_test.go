@@ -0,0 +1,29 @@
+package tealstate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+func TestKeyValues(t *testing.T) {
+	kvs := []models.TealKeyValue{
+		{Key: "AAE=", Value: models.TealValue{Type: uint64(types.TealBytesType), Bytes: "cmF3IGtleQ=="}},
+		{Key: "Y291bnRlcg==", Value: models.TealValue{Type: uint64(types.TealUintType), Bytes: "", Uint: 7}},
+	}
+	state, err := DecodeKeyValues(kvs)
+	require.NoError(t, err)
+	require.Equal(t, types.TealKeyValue{
+		"counter":  {Type: types.TealUintType, Uint: 7},
+		"\x00\x01": {Type: types.TealBytesType, Bytes: "raw key"},
+	}, state)
+	require.Equal(t, kvs, EncodeKeyValues(state))
+
+	_, err = DecodeKeyValues([]models.TealKeyValue{{Key: "!"}})
+	require.Error(t, err)
+	_, err = DecodeKeyValues([]models.TealKeyValue{{Key: "YQ==", Value: models.TealValue{Type: uint64(types.TealBytesType), Bytes: "!"}}})
+	require.Error(t, err)
+}
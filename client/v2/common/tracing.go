@@ -0,0 +1,125 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Tracer starts a span for each API request. It is a minimal interface which
+// can be implemented by a thin adapter around an OpenTelemetry trace.Tracer,
+// so that the SDK does not depend on a tracing library.
+type Tracer interface {
+	// Start starts a span named name as a child of any span in ctx.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced API request.
+type Span interface {
+	// SetAttribute records an attribute of the request, value is a string or
+	// an int64.
+	SetAttribute(key string, value interface{})
+
+	// SetError marks the span as failed.
+	SetError(err error)
+
+	// End completes the span.
+	End()
+}
+
+// Span attribute keys set by TracingMiddleware.
+const (
+	AttributeHTTPMethod     = "http.method"
+	AttributeHTTPStatusCode = "http.status_code"
+	AttributeServerAddress  = "server.address"
+	AttributeRound          = "algorand.round"
+	AttributeTxID           = "algorand.txid"
+	AttributeAddress        = "algorand.address"
+	AttributeApplicationID  = "algorand.application_id"
+	AttributeAssetID        = "algorand.asset_id"
+)
+
+// pathParamAttributes maps a path segment to the attribute of the path
+// parameter which follows it.
+var pathParamAttributes = map[string]string{
+	"accounts":             AttributeAddress,
+	"applications":         AttributeApplicationID,
+	"assets":               AttributeAssetID,
+	"blocks":               AttributeRound,
+	"deltas":               AttributeRound,
+	"lightheader":          AttributeRound,
+	"pending":              AttributeTxID,
+	"stateproofs":          AttributeRound,
+	"sync":                 AttributeRound,
+	"transactions":         AttributeTxID,
+	"wait-for-block-after": AttributeRound,
+}
+
+// spanName returns the name of the endpoint for a request path, with path
+// parameters replaced by their names, along with the path parameter values.
+func spanName(method, path string) (string, map[string]string) {
+	segments := strings.Split(path, "/")
+	params := make(map[string]string)
+	for i := 1; i < len(segments); i++ {
+		attribute, ok := pathParamAttributes[segments[i-1]]
+		if !ok || !isPathParam(attribute, segments[i]) {
+			continue
+		}
+		params[attribute] = segments[i]
+		segments[i] = "{" + strings.TrimPrefix(attribute, "algorand.") + "}"
+	}
+	return method + " " + strings.Join(segments, "/"), params
+}
+
+func isPathParam(attribute, segment string) bool {
+	switch attribute {
+	case AttributeRound, AttributeApplicationID, AttributeAssetID:
+		_, err := strconv.ParseUint(segment, 10, 64)
+		return err == nil
+	case AttributeTxID:
+		return len(segment) == 52
+	case AttributeAddress:
+		return len(segment) == 58
+	}
+	return false
+}
+
+// TracingMiddleware starts a span for every HTTP request, named after the
+// endpoint, e.g. "GET /v2/blocks/{round}".
+func TracingMiddleware(tracer Tracer) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			name, params := spanName(req.Method, req.URL.Path)
+			ctx, span := tracer.Start(req.Context(), name)
+			defer span.End()
+
+			span.SetAttribute(AttributeHTTPMethod, req.Method)
+			span.SetAttribute(AttributeServerAddress, req.URL.Host)
+			for attribute, value := range params {
+				if attribute == AttributeTxID || attribute == AttributeAddress {
+					span.SetAttribute(attribute, value)
+				} else if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+					span.SetAttribute(attribute, n)
+				}
+			}
+
+			resp, err := next.RoundTrip(req.WithContext(ctx))
+			if err != nil {
+				span.SetError(err)
+				return resp, err
+			}
+
+			span.SetAttribute(AttributeHTTPStatusCode, int64(resp.StatusCode))
+			if resp.StatusCode >= 400 {
+				span.SetError(extractError(resp.StatusCode, nil))
+			}
+			return resp, nil
+		})
+	}
+}
+
+// WithTracer starts a span with tracer for every request sent by the client.
+func WithTracer(tracer Tracer) ClientOption {
+	return WithMiddleware(TracingMiddleware(tracer))
+}
@@ -0,0 +1,86 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSpan struct {
+	name       string
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+func (s *testSpan) SetError(err error)                         { s.err = err }
+func (s *testSpan) End()                                       { s.ended = true }
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &testSpan{name: name, attributes: map[string]interface{}{}}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func TestSpanName(t *testing.T) {
+	txid := "TXIDTXIDTXIDTXIDTXIDTXIDTXIDTXIDTXIDTXIDTXIDTXIDTXID"
+	addr := "DN7MBMCL5JQ3PFUQS7TMX5AH4EEKOBJVDUF4TCV6WERATKFLQF4MQUPZTA"
+
+	tests := []struct {
+		path   string
+		name   string
+		params map[string]string
+	}{
+		{"/v2/status", "GET /v2/status", map[string]string{}},
+		{"/v2/blocks/123", "GET /v2/blocks/{round}", map[string]string{AttributeRound: "123"}},
+		{"/v2/transactions/pending/" + txid, "GET /v2/transactions/pending/{txid}", map[string]string{AttributeTxID: txid}},
+		{"/v2/accounts/" + addr + "/assets/7", "GET /v2/accounts/{address}/assets/{asset_id}",
+			map[string]string{AttributeAddress: addr, AttributeAssetID: "7"}},
+		{"/v2/blocks/5/transactions/" + txid + "/proof", "GET /v2/blocks/{round}/transactions/{txid}/proof",
+			map[string]string{AttributeRound: "5", AttributeTxID: txid}},
+	}
+	for _, test := range tests {
+		name, params := spanName("GET", test.path)
+		assert.Equal(t, test.name, name)
+		assert.Equal(t, test.params, params)
+	}
+}
+
+func TestClient_Tracer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/blocks/99" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(`"ok"`))
+	}))
+	defer server.Close()
+
+	tracer := &testTracer{}
+	c, err := MakeClientWithOptions(server.URL, "API-Header", "ASDF", WithTracer(tracer))
+	require.NoError(t, err)
+
+	var response string
+	require.NoError(t, c.Get(context.Background(), &response, "/v2/status", nil, nil))
+	require.Error(t, c.Get(context.Background(), &response, "/v2/blocks/99", nil, nil))
+
+	require.Len(t, tracer.spans, 2)
+	assert.Equal(t, "GET /v2/status", tracer.spans[0].name)
+	assert.Equal(t, int64(http.StatusOK), tracer.spans[0].attributes[AttributeHTTPStatusCode])
+	assert.NoError(t, tracer.spans[0].err)
+	assert.True(t, tracer.spans[0].ended)
+
+	assert.Equal(t, "GET /v2/blocks/{round}", tracer.spans[1].name)
+	assert.Equal(t, int64(99), tracer.spans[1].attributes[AttributeRound])
+	assert.Equal(t, int64(http.StatusNotFound), tracer.spans[1].attributes[AttributeHTTPStatusCode])
+	assert.Error(t, tracer.spans[1].err)
+	assert.True(t, tracer.spans[1].ended)
+}
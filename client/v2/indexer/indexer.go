@@ -2,6 +2,7 @@ package indexer
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
 )
@@ -52,6 +53,37 @@ func MakeClientWithHeaders(address string, apiToken string, headers []*common.He
 	return
 }
 
+// MakeClientWithTransport is the factory for constructing a ClientV2 for a given
+// endpoint with a custom HTTP transport as well as optional additional user
+// defined headers.
+func MakeClientWithTransport(address string, apiToken string, headers []*common.Header, transport http.RoundTripper) (c *Client, err error) {
+	commonClient, err := common.MakeClientWithTransport(address, authHeader, apiToken, headers, transport)
+	c = (*Client)(commonClient)
+	return
+}
+
+// MakeClientWithOptions is the factory for constructing a ClientV2 for a given
+// endpoint with optional behavior such as retries.
+func MakeClientWithOptions(address string, apiToken string, opts ...common.ClientOption) (c *Client, err error) {
+	commonClient, err := common.MakeClientWithOptions(address, authHeader, apiToken, opts...)
+	c = (*Client)(commonClient)
+	return
+}
+
+// MakeFailoverClient is the factory for constructing a ClientV2 which fails over
+// between multiple endpoints, trying them in the order given by policy.
+func MakeFailoverClient(addresses []string, policy common.SelectionPolicy, apiToken string, opts ...common.ClientOption) (c *Client, err error) {
+	commonClient, err := common.MakeFailoverClient(addresses, policy, authHeader, apiToken, opts...)
+	c = (*Client)(commonClient)
+	return
+}
+
+// CheckEndpoints probes the health of every endpoint of a failover client, see
+// common.Client.CheckEndpoints.
+func (c *Client) CheckEndpoints(ctx context.Context) {
+	(*common.Client)(c).CheckEndpoints(ctx)
+}
+
 func (c *Client) HealthCheck() *HealthCheck {
 	return &HealthCheck{c: c}
 }
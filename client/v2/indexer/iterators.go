@@ -0,0 +1,322 @@
+package indexer
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+)
+
+// pageFetcher fetches the page of results starting at the given next token,
+// returning a function making the page the current page of the iterator, the
+// number of results and the token of the next page.
+type pageFetcher func(ctx context.Context, next string) (setPage func(), count int, nextToken string, err error)
+
+type pageResult struct {
+	setPage func()
+	count   int
+	next    string
+	err     error
+}
+
+// pager follows next-token across pages of search results. A page is only
+// fetched once every result of the previous page has been consumed, unless
+// prefetch is set in which case the next page is fetched in the background
+// while the current one is consumed. The requests are made with a context
+// cancelled once the iteration ends or is closed.
+type pager struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	fetch    pageFetcher
+	prefetch bool
+	pending  chan pageResult
+	next     string
+	index    int
+	count    int
+	started  bool
+	done     bool
+	err      error
+}
+
+func newPager(ctx context.Context, next string, fetch pageFetcher) pager {
+	ctx, cancel := context.WithCancel(ctx)
+	return pager{ctx: ctx, cancel: cancel, fetch: fetch, next: next}
+}
+
+func (p *pager) load(next string) pageResult {
+	setPage, count, next, err := p.fetch(p.ctx, next)
+	return pageResult{setPage: setPage, count: count, next: next, err: err}
+}
+
+// advance moves to the next result, fetching the next page when needed.
+func (p *pager) advance() bool {
+	if p.done {
+		return false
+	}
+	p.index++
+	if p.started && p.index < p.count {
+		return true
+	}
+	if p.err != nil || (p.started && p.next == "") {
+		p.Close()
+		return false
+	}
+
+	p.started = true
+	var result pageResult
+	if p.pending != nil {
+		result = <-p.pending
+		p.pending = nil
+	} else {
+		result = p.load(p.next)
+	}
+	if result.err != nil {
+		p.err = result.err
+		p.Close()
+		return false
+	}
+	result.setPage()
+	p.index, p.count, p.next = 0, result.count, result.next
+
+	if p.prefetch && p.count > 0 && p.next != "" {
+		pending := make(chan pageResult, 1)
+		go func(next string) {
+			pending <- p.load(next)
+		}(p.next)
+		p.pending = pending
+	}
+	// an empty page ends the iteration even if a next token was returned
+	if p.count == 0 {
+		p.Close()
+		return false
+	}
+	return true
+}
+
+// Close stops the iteration, cancelling the request of a page being fetched in
+// the background and waiting for it to return. Iterations which end, because
+// every result was consumed or an error occurred, are closed already.
+func (p *pager) Close() {
+	p.done = true
+	p.cancel()
+	if p.pending != nil {
+		<-p.pending
+		p.pending = nil
+	}
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (p *pager) Err() error {
+	return p.err
+}
+
+// NextToken returns the token of the page following the current one, which
+// can be used to resume the search later. It is empty on the last page.
+func (p *pager) NextToken() string {
+	return p.next
+}
+
+// TransactionIterator iterates over the results of a transaction search one
+// transaction at a time.
+type TransactionIterator struct {
+	pager
+	page []models.Transaction
+}
+
+// Next advances to the next transaction, returning false when there are no
+// more transactions or an error occurred.
+func (it *TransactionIterator) Next() bool {
+	return it.advance()
+}
+
+// Transaction returns the current transaction.
+func (it *TransactionIterator) Transaction() models.Transaction {
+	return it.page[it.index]
+}
+
+// Iterate returns an iterator over all the transactions matching the search,
+// following next-token across pages.
+func (s *SearchForTransactions) Iterate(ctx context.Context, headers ...*common.Header) *TransactionIterator {
+	it := &TransactionIterator{}
+	it.pager = newPager(ctx, s.p.NextToken, func(ctx context.Context, next string) (func(), int, string, error) {
+		response, err := s.NextToken(next).Do(ctx, headers...)
+		return func() { it.page = response.Transactions }, len(response.Transactions), response.NextToken, err
+	})
+	return it
+}
+
+// Iterate returns an iterator over all the transactions of the account
+// matching the search, following next-token across pages.
+func (s *LookupAccountTransactions) Iterate(ctx context.Context, headers ...*common.Header) *TransactionIterator {
+	it := &TransactionIterator{}
+	it.pager = newPager(ctx, s.p.NextToken, func(ctx context.Context, next string) (func(), int, string, error) {
+		response, err := s.NextToken(next).Do(ctx, headers...)
+		return func() { it.page = response.Transactions }, len(response.Transactions), response.NextToken, err
+	})
+	return it
+}
+
+// Iterate returns an iterator over all the transactions of the asset matching
+// the search, following next-token across pages.
+func (s *LookupAssetTransactions) Iterate(ctx context.Context, headers ...*common.Header) *TransactionIterator {
+	it := &TransactionIterator{}
+	it.pager = newPager(ctx, s.p.NextToken, func(ctx context.Context, next string) (func(), int, string, error) {
+		response, err := s.NextToken(next).Do(ctx, headers...)
+		return func() { it.page = response.Transactions }, len(response.Transactions), response.NextToken, err
+	})
+	return it
+}
+
+// AccountIterator iterates over the results of an account search one account
+// at a time.
+type AccountIterator struct {
+	pager
+	page []models.Account
+}
+
+// Next advances to the next account, returning false when there are no more
+// accounts or an error occurred.
+func (it *AccountIterator) Next() bool {
+	return it.advance()
+}
+
+// Account returns the current account.
+func (it *AccountIterator) Account() models.Account {
+	return it.page[it.index]
+}
+
+// Iterate returns an iterator over all the accounts matching the search,
+// following next-token across pages.
+func (s *SearchAccounts) Iterate(ctx context.Context, headers ...*common.Header) *AccountIterator {
+	it := &AccountIterator{}
+	it.pager = newPager(ctx, s.p.NextToken, func(ctx context.Context, next string) (func(), int, string, error) {
+		response, err := s.NextToken(next).Do(ctx, headers...)
+		return func() { it.page = response.Accounts }, len(response.Accounts), response.NextToken, err
+	})
+	return it
+}
+
+// AssetIterator iterates over the results of an asset search one asset at a
+// time.
+type AssetIterator struct {
+	pager
+	page []models.Asset
+}
+
+// Next advances to the next asset, returning false when there are no more
+// assets or an error occurred.
+func (it *AssetIterator) Next() bool {
+	return it.advance()
+}
+
+// Asset returns the current asset.
+func (it *AssetIterator) Asset() models.Asset {
+	return it.page[it.index]
+}
+
+// Iterate returns an iterator over all the assets matching the search,
+// following next-token across pages.
+func (s *SearchForAssets) Iterate(ctx context.Context, headers ...*common.Header) *AssetIterator {
+	it := &AssetIterator{}
+	it.pager = newPager(ctx, s.p.NextToken, func(ctx context.Context, next string) (func(), int, string, error) {
+		response, err := s.NextToken(next).Do(ctx, headers...)
+		return func() { it.page = response.Assets }, len(response.Assets), response.NextToken, err
+	})
+	return it
+}
+
+// ApplicationIterator iterates over the results of an application search one
+// application at a time.
+type ApplicationIterator struct {
+	pager
+	page []models.Application
+}
+
+// Next advances to the next application, returning false when there are no
+// more applications or an error occurred.
+func (it *ApplicationIterator) Next() bool {
+	return it.advance()
+}
+
+// Application returns the current application.
+func (it *ApplicationIterator) Application() models.Application {
+	return it.page[it.index]
+}
+
+// Iterate returns an iterator over all the applications matching the search,
+// following next-token across pages.
+func (s *SearchForApplications) Iterate(ctx context.Context, headers ...*common.Header) *ApplicationIterator {
+	it := &ApplicationIterator{}
+	it.pager = newPager(ctx, s.p.Next, func(ctx context.Context, next string) (func(), int, string, error) {
+		response, err := s.Next(next).Do(ctx, headers...)
+		return func() { it.page = response.Applications }, len(response.Applications), response.NextToken, err
+	})
+	return it
+}
+
+// ApplicationLogIterator iterates over the results of an application logs
+// lookup one transaction's logs at a time.
+type ApplicationLogIterator struct {
+	pager
+	page []models.ApplicationLogData
+}
+
+// Next advances to the next log data, returning false when there is no more
+// log data or an error occurred.
+func (it *ApplicationLogIterator) Next() bool {
+	return it.advance()
+}
+
+// LogData returns the current log data.
+func (it *ApplicationLogIterator) LogData() models.ApplicationLogData {
+	return it.page[it.index]
+}
+
+// Iterate returns an iterator over all the application logs matching the
+// lookup, following next-token across pages.
+func (s *LookupApplicationLogsByID) Iterate(ctx context.Context, headers ...*common.Header) *ApplicationLogIterator {
+	it := &ApplicationLogIterator{}
+	it.pager = newPager(ctx, s.p.Next, func(ctx context.Context, next string) (func(), int, string, error) {
+		response, err := s.Next(next).Do(ctx, headers...)
+		return func() { it.page = response.LogData }, len(response.LogData), response.NextToken, err
+	})
+	return it
+}
+
+// AssetBalanceIterator iterates over the holders of an asset one holding at a
+// time. The page size is set with the Limit option of the lookup.
+type AssetBalanceIterator struct {
+	pager
+	page []models.MiniAssetHolding
+}
+
+// Prefetch enables fetching the next page of holdings in the background while
+// the current page is consumed. It must be called before the first call to
+// Next. Close the iterator when stopping before its end to stop the
+// background fetch.
+func (it *AssetBalanceIterator) Prefetch(enabled bool) *AssetBalanceIterator {
+	it.prefetch = enabled
+	return it
+}
+
+// Next advances to the next holding, returning false when there are no more
+// holdings or an error occurred.
+func (it *AssetBalanceIterator) Next() bool {
+	return it.advance()
+}
+
+// Balance returns the current holding.
+func (it *AssetBalanceIterator) Balance() models.MiniAssetHolding {
+	return it.page[it.index]
+}
+
+// Iterate returns an iterator over all the holders of the asset, following
+// next-token across pages.
+func (s *LookupAssetBalances) Iterate(ctx context.Context, headers ...*common.Header) *AssetBalanceIterator {
+	it := &AssetBalanceIterator{}
+	it.pager = newPager(ctx, s.p.NextToken, func(ctx context.Context, next string) (func(), int, string, error) {
+		response, err := s.NextToken(next).Do(ctx, headers...)
+		return func() { it.page = response.Balances }, len(response.Balances), response.NextToken, err
+	})
+	return it
+}
@@ -0,0 +1,249 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+)
+
+// pagesServer returns a client of an indexer answering the requests for path
+// with the response of page for their next token, and the tokens requested.
+func pagesServer(t *testing.T, path string, page func(r *http.Request, next string) (int, interface{})) (*Client, func() []string) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, path, r.URL.Path)
+		next := r.URL.Query().Get("next")
+		mu.Lock()
+		requested = append(requested, next)
+		mu.Unlock()
+		status, response := page(r, next)
+		w.WriteHeader(status)
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	t.Cleanup(server.Close)
+	c, err := MakeClient(server.URL, "")
+	require.NoError(t, err)
+	return c, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requested...)
+	}
+}
+
+func TestAccountIterator(t *testing.T) {
+	pages := map[string]models.AccountsResponse{
+		"":   {Accounts: []models.Account{{Address: "A"}, {Address: "B"}}, NextToken: "t1"},
+		"t1": {Accounts: []models.Account{{Address: "C"}}, NextToken: "t2"},
+		// an empty page ends the iteration even with a next token
+		"t2": {NextToken: "t3"},
+	}
+	c, requested := pagesServer(t, "/v2/accounts", func(r *http.Request, next string) (int, interface{}) {
+		require.Equal(t, "10", r.URL.Query().Get("limit"))
+		return http.StatusOK, pages[next]
+	})
+
+	it := c.SearchAccounts().Limit(10).Iterate(context.Background())
+	var addresses, tokens []string
+	for it.Next() {
+		addresses = append(addresses, it.Account().Address)
+		tokens = append(tokens, it.NextToken())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"A", "B", "C"}, addresses)
+	require.Equal(t, []string{"t1", "t1", "t2"}, tokens)
+	require.Equal(t, []string{"", "t1", "t2"}, requested())
+	require.False(t, it.Next())
+	require.Equal(t, []string{"", "t1", "t2"}, requested())
+}
+
+func TestAccountIteratorLastPage(t *testing.T) {
+	c, requested := pagesServer(t, "/v2/accounts", func(r *http.Request, next string) (int, interface{}) {
+		if next == "" {
+			return http.StatusOK, models.AccountsResponse{Accounts: []models.Account{{Address: "A"}}, NextToken: "t1"}
+		}
+		return http.StatusOK, models.AccountsResponse{Accounts: []models.Account{{Address: "B"}}}
+	})
+
+	it := c.SearchAccounts().Iterate(context.Background())
+	var addresses []string
+	for it.Next() {
+		addresses = append(addresses, it.Account().Address)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"A", "B"}, addresses)
+	require.Empty(t, it.NextToken())
+	require.Equal(t, []string{"", "t1"}, requested())
+}
+
+func TestAccountIteratorError(t *testing.T) {
+	failing := true
+	c, _ := pagesServer(t, "/v2/accounts", func(r *http.Request, next string) (int, interface{}) {
+		switch {
+		case next == "":
+			return http.StatusOK, models.AccountsResponse{Accounts: []models.Account{{Address: "A"}}, NextToken: "t1"}
+		case failing:
+			return http.StatusBadRequest, map[string]string{"message": "invalid next token"}
+		}
+		return http.StatusOK, models.AccountsResponse{Accounts: []models.Account{{Address: "B"}}}
+	})
+
+	it := c.SearchAccounts().Iterate(context.Background())
+	require.True(t, it.Next())
+	require.Equal(t, "A", it.Account().Address)
+	require.False(t, it.Next())
+	require.Error(t, it.Err())
+	require.False(t, it.Next())
+
+	// the search resumes from the page which failed
+	failing = false
+	resumed := c.SearchAccounts().NextToken(it.NextToken()).Iterate(context.Background())
+	require.True(t, resumed.Next())
+	require.Equal(t, "B", resumed.Account().Address)
+	require.False(t, resumed.Next())
+	require.NoError(t, resumed.Err())
+}
+
+func TestTransactionIterator(t *testing.T) {
+	pages := map[string]models.TransactionsResponse{
+		"":   {Transactions: []models.Transaction{{Id: "T1"}, {Id: "T2"}}, NextToken: "t1"},
+		"t1": {Transactions: []models.Transaction{{Id: "T3"}}},
+	}
+	c, requested := pagesServer(t, "/v2/transactions", func(r *http.Request, next string) (int, interface{}) {
+		require.Equal(t, "pay", r.URL.Query().Get("tx-type"))
+		return http.StatusOK, pages[next]
+	})
+
+	it := c.SearchForTransactions().TxType("pay").Iterate(context.Background())
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Transaction().Id)
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"T1", "T2", "T3"}, ids)
+	require.Equal(t, []string{"", "t1"}, requested())
+}
+
+func TestApplicationLogIterator(t *testing.T) {
+	pages := map[string]models.ApplicationLogsResponse{
+		"":   {ApplicationId: 7, LogData: []models.ApplicationLogData{{Txid: "T1", Logs: [][]byte{[]byte("a")}}}, NextToken: "t1"},
+		"t1": {ApplicationId: 7, LogData: []models.ApplicationLogData{{Txid: "T2", Logs: [][]byte{[]byte("b"), []byte("c")}}}, NextToken: "t2"},
+		"t2": {ApplicationId: 7},
+	}
+	c, requested := pagesServer(t, "/v2/applications/7/logs", func(r *http.Request, next string) (int, interface{}) {
+		return http.StatusOK, pages[next]
+	})
+
+	it := c.LookupApplicationLogsByID(7).Next("t1").Iterate(context.Background())
+	require.True(t, it.Next())
+	require.Equal(t, models.ApplicationLogData{Txid: "T2", Logs: [][]byte{[]byte("b"), []byte("c")}}, it.LogData())
+	require.False(t, it.Next())
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"t1", "t2"}, requested())
+}
+
+func holdings(addresses ...string) []models.MiniAssetHolding {
+	var balances []models.MiniAssetHolding
+	for _, address := range addresses {
+		balances = append(balances, models.MiniAssetHolding{Address: address, Amount: 1})
+	}
+	return balances
+}
+
+func TestAssetBalanceIteratorPrefetch(t *testing.T) {
+	pages := map[string]models.AssetBalancesResponse{
+		"":   {Balances: holdings("A", "B"), NextToken: "t1"},
+		"t1": {Balances: holdings("C"), NextToken: "t2"},
+		"t2": {Balances: holdings("D", "E")},
+	}
+	fetched := make(chan string, len(pages))
+	c, requested := pagesServer(t, "/v2/assets/5/balances", func(r *http.Request, next string) (int, interface{}) {
+		fetched <- next
+		return http.StatusOK, pages[next]
+	})
+
+	it := c.LookupAssetBalances(5).Iterate(context.Background()).Prefetch(true)
+	var addresses []string
+	for it.Next() {
+		addresses = append(addresses, it.Balance().Address)
+		if it.Balance().Address == "A" {
+			require.Equal(t, "", <-fetched)
+			// the next page is fetched while the current one is consumed
+			select {
+			case next := <-fetched:
+				require.Equal(t, "t1", next)
+			case <-time.After(5 * time.Second):
+				t.Fatal("the next page was not prefetched")
+			}
+		}
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"A", "B", "C", "D", "E"}, addresses)
+	require.Equal(t, []string{"", "t1", "t2"}, requested())
+	require.Nil(t, it.pending)
+}
+
+func TestAssetBalanceIteratorPrefetchError(t *testing.T) {
+	c, _ := pagesServer(t, "/v2/assets/5/balances", func(r *http.Request, next string) (int, interface{}) {
+		if next == "" {
+			return http.StatusOK, models.AssetBalancesResponse{Balances: holdings("A", "B"), NextToken: "t1"}
+		}
+		return http.StatusInternalServerError, map[string]string{"message": "failed"}
+	})
+
+	it := c.LookupAssetBalances(5).Iterate(context.Background()).Prefetch(true)
+	var addresses []string
+	for it.Next() {
+		addresses = append(addresses, it.Balance().Address)
+	}
+	require.Equal(t, []string{"A", "B"}, addresses)
+	require.Error(t, it.Err())
+	require.Contains(t, it.Err().Error(), "failed")
+	require.Equal(t, "t1", it.NextToken())
+}
+
+func TestAssetBalanceIteratorPrefetchClose(t *testing.T) {
+	prefetching := make(chan struct{})
+	cancelled := make(chan struct{})
+	c, _ := pagesServer(t, "/v2/assets/5/balances", func(r *http.Request, next string) (int, interface{}) {
+		if next == "" {
+			return http.StatusOK, models.AssetBalancesResponse{Balances: holdings("A", "B"), NextToken: "t1"}
+		}
+		close(prefetching)
+		select {
+		case <-r.Context().Done():
+			close(cancelled)
+		case <-time.After(5 * time.Second):
+		}
+		return http.StatusOK, models.AssetBalancesResponse{Balances: holdings("C")}
+	})
+
+	it := c.LookupAssetBalances(5).Iterate(context.Background()).Prefetch(true)
+	require.True(t, it.Next())
+	require.Equal(t, "A", it.Balance().Address)
+	<-prefetching
+
+	closed := make(chan struct{})
+	go func() {
+		it.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close did not stop the background fetch")
+	}
+	<-cancelled
+	// Close returns once the background fetch has returned
+	require.Nil(t, it.pending)
+	require.False(t, it.Next())
+	require.NoError(t, it.Err())
+}
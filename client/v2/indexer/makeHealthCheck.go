@@ -2,6 +2,8 @@ package indexer
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
@@ -17,3 +19,34 @@ func (s *HealthCheck) Do(ctx context.Context, headers ...*common.Header) (respon
 	err = s.c.get(ctx, &response, "/health", nil, headers)
 	return
 }
+
+// NotReadyError is returned by DoReady when the indexer responded to the
+// health check but is not ready to serve traffic.
+type NotReadyError struct {
+	Health models.HealthCheckResponse
+}
+
+func (e NotReadyError) Error() string {
+	var reasons []string
+	if !e.Health.DbAvailable {
+		reasons = append(reasons, "database unavailable")
+	}
+	if e.Health.IsMigrating {
+		reasons = append(reasons, "migration in progress")
+	}
+	reasons = append(reasons, e.Health.Errors...)
+	return fmt.Sprintf("indexer not ready at round %d: %s", e.Health.Round, strings.Join(reasons, ", "))
+}
+
+// DoReady performs the HTTP request and returns a NotReadyError if the
+// database is unavailable, a migration is in progress or errors are reported.
+func (s *HealthCheck) DoReady(ctx context.Context, headers ...*common.Header) (response models.HealthCheckResponse, err error) {
+	response, err = s.Do(ctx, headers...)
+	if err != nil {
+		return
+	}
+	if !response.DbAvailable || response.IsMigrating || len(response.Errors) > 0 {
+		err = NotReadyError{Health: response}
+	}
+	return
+}
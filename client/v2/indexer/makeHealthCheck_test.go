@@ -0,0 +1,84 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheckDoReady(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		err      string
+	}{
+		{
+			name:     "healthy",
+			response: `{"db-available":true,"is-migrating":false,"message":"100","round":100,"version":"2.15.0"}`,
+		},
+		{
+			name:     "migrating",
+			response: `{"db-available":true,"is-migrating":true,"message":"100","round":100,"version":"2.15.0"}`,
+			err:      "indexer not ready at round 100: migration in progress",
+		},
+		{
+			name:     "database unavailable",
+			response: `{"db-available":false,"is-migrating":false,"message":"100","round":100,"version":"2.15.0"}`,
+			err:      "indexer not ready at round 100: database unavailable",
+		},
+		{
+			name:     "errors",
+			response: `{"db-available":true,"is-migrating":false,"errors":["importer stalled"],"message":"100","round":100,"version":"2.15.0"}`,
+			err:      "indexer not ready at round 100: importer stalled",
+		},
+		{
+			name:     "all",
+			response: `{"db-available":false,"is-migrating":true,"errors":["a","b"],"message":"100","round":100,"version":"2.15.0"}`,
+			err:      "indexer not ready at round 100: database unavailable, migration in progress, a, b",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var path string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				w.Write([]byte(test.response))
+			}))
+			defer server.Close()
+			c, err := MakeClient(server.URL, "")
+			require.NoError(t, err)
+
+			health, err := c.HealthCheck().DoReady(context.Background())
+			require.Equal(t, "/health", path)
+			require.Equal(t, uint64(100), health.Round)
+			require.Equal(t, "2.15.0", health.Version)
+			if test.err == "" {
+				require.NoError(t, err)
+				require.True(t, health.DbAvailable)
+				return
+			}
+			require.EqualError(t, err, test.err)
+			var notReady NotReadyError
+			require.True(t, errors.As(err, &notReady))
+			require.Equal(t, health, notReady.Health)
+		})
+	}
+}
+
+func TestHealthCheckDoReadyHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"message":"failed"}`))
+	}))
+	defer server.Close()
+	c, err := MakeClient(server.URL, "")
+	require.NoError(t, err)
+
+	_, err = c.HealthCheck().DoReady(context.Background())
+	require.Error(t, err)
+	require.False(t, errors.As(err, &NotReadyError{}))
+}
@@ -0,0 +1,21 @@
+package indexer
+
+import (
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+)
+
+// DefaultRetryPolicy returns a retry policy suited to indexer providers which
+// rate limit with 429 responses. Requests are retried for up to two minutes,
+// and a 429 pauses every request of the client for its Retry-After delay, so
+// that long pagination jobs slow down instead of failing part way through.
+// Use it with MakeClientWithOptions and common.WithRetryPolicy.
+func DefaultRetryPolicy() common.RetryPolicy {
+	policy := common.DefaultRetryPolicy()
+	policy.MaxRetries = 10
+	policy.MaxBackoff = 30 * time.Second
+	policy.Budget = 2 * time.Minute
+	policy.Pace = true
+	return policy
+}
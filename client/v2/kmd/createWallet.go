@@ -0,0 +1,38 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// CreateWallet creates a wallet with the given name and password.
+type CreateWallet struct {
+	c *Client
+
+	body models.CreateWalletRequest
+}
+
+// WalletDriverName the wallet driver to use, the default driver if empty.
+func (s *CreateWallet) WalletDriverName(WalletDriverName string) *CreateWallet {
+	s.body.WalletDriverName = WalletDriverName
+
+	return s
+}
+
+// MasterDerivationKey the master derivation key of the wallet, generated by kmd
+// if not set.
+func (s *CreateWallet) MasterDerivationKey(MasterDerivationKey types.MasterDerivationKey) *CreateWallet {
+	s.body.MasterDerivationKey = MasterDerivationKey
+
+	return s
+}
+
+// Do performs the HTTP request
+func (s *CreateWallet) Do(ctx context.Context, headers ...*common.Header) (response models.CreateWalletResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/wallet", nil, headers, json.Encode(s.body))
+	return
+}
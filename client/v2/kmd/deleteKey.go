@@ -0,0 +1,22 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// DeleteKey deletes the key of an address from a wallet.
+type DeleteKey struct {
+	c *Client
+
+	body models.AddressRequest
+}
+
+// Do performs the HTTP request
+func (s *DeleteKey) Do(ctx context.Context, headers ...*common.Header) (response models.EmptyResponse, err error) {
+	err = s.c.delete(ctx, &response, "/v1/key", headers, json.Encode(s.body))
+	return
+}
@@ -0,0 +1,22 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// DeleteMultisig deletes the preimage of a multisig address from a wallet.
+type DeleteMultisig struct {
+	c *Client
+
+	body models.AddressRequest
+}
+
+// Do performs the HTTP request
+func (s *DeleteMultisig) Do(ctx context.Context, headers ...*common.Header) (response models.EmptyResponse, err error) {
+	err = s.c.delete(ctx, &response, "/v1/multisig", headers, json.Encode(s.body))
+	return
+}
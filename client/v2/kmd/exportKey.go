@@ -0,0 +1,22 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// ExportKey exports the private key of an address in a wallet.
+type ExportKey struct {
+	c *Client
+
+	body models.AddressRequest
+}
+
+// Do performs the HTTP request
+func (s *ExportKey) Do(ctx context.Context, headers ...*common.Header) (response models.ExportKeyResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/key/export", nil, headers, json.Encode(s.body))
+	return
+}
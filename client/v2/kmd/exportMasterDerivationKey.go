@@ -0,0 +1,22 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// ExportMasterDerivationKey exports the master derivation key of a wallet.
+type ExportMasterDerivationKey struct {
+	c *Client
+
+	body models.WalletPasswordRequest
+}
+
+// Do performs the HTTP request
+func (s *ExportMasterDerivationKey) Do(ctx context.Context, headers ...*common.Header) (response models.ExportMasterKeyResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/master-key/export", nil, headers, json.Encode(s.body))
+	return
+}
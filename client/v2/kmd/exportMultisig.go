@@ -0,0 +1,22 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// ExportMultisig exports the preimage of a multisig address in a wallet.
+type ExportMultisig struct {
+	c *Client
+
+	body models.AddressRequest
+}
+
+// Do performs the HTTP request
+func (s *ExportMultisig) Do(ctx context.Context, headers ...*common.Header) (response models.ExportMultisigResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/multisig/export", nil, headers, json.Encode(s.body))
+	return
+}
@@ -0,0 +1,23 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// GenerateKey generates the next key of a wallet from its master derivation
+// key.
+type GenerateKey struct {
+	c *Client
+
+	body models.GenerateKeyRequest
+}
+
+// Do performs the HTTP request
+func (s *GenerateKey) Do(ctx context.Context, headers ...*common.Header) (response models.AddressResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/key", nil, headers, json.Encode(s.body))
+	return
+}
@@ -0,0 +1,19 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+)
+
+// GetVersion retrieves the kmd API versions supported.
+type GetVersion struct {
+	c *Client
+}
+
+// Do performs the HTTP request
+func (s *GetVersion) Do(ctx context.Context, headers ...*common.Header) (response models.VersionsResponse, err error) {
+	err = s.c.get(ctx, &response, "/versions", nil, headers)
+	return
+}
@@ -0,0 +1,22 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// ImportKey imports an ed25519 private key into a wallet.
+type ImportKey struct {
+	c *Client
+
+	body models.ImportKeyRequest
+}
+
+// Do performs the HTTP request
+func (s *ImportKey) Do(ctx context.Context, headers ...*common.Header) (response models.AddressResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/key/import", nil, headers, json.Encode(s.body))
+	return
+}
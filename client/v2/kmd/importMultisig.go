@@ -0,0 +1,22 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// ImportMultisig imports the preimage of a multisig address into a wallet.
+type ImportMultisig struct {
+	c *Client
+
+	body models.ImportMultisigRequest
+}
+
+// Do performs the HTTP request
+func (s *ImportMultisig) Do(ctx context.Context, headers ...*common.Header) (response models.AddressResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/multisig/import", nil, headers, json.Encode(s.body))
+	return
+}
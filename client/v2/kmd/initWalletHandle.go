@@ -0,0 +1,23 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// InitWalletHandle unlocks a wallet and returns a wallet handle token which
+// must be renewed before it expires.
+type InitWalletHandle struct {
+	c *Client
+
+	body models.InitWalletHandleTokenRequest
+}
+
+// Do performs the HTTP request
+func (s *InitWalletHandle) Do(ctx context.Context, headers ...*common.Header) (response models.InitWalletHandleTokenResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/wallet/init", nil, headers, json.Encode(s.body))
+	return
+}
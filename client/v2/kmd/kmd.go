@@ -0,0 +1,163 @@
+package kmd
+
+import (
+	"context"
+	"net/http"
+
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+const authHeader = "X-KMD-API-Token"
+
+type Client common.Client
+
+// delete performs a DELETE request with the given body to the specific path against the server, assumes JSON response
+func (c *Client) delete(ctx context.Context, response interface{}, path string, headers []*common.Header, body []byte) error {
+	return (*common.Client)(c).DeleteWithBody(ctx, response, path, nil, headers, body)
+}
+
+// get performs a GET request to the specific path against the server, assumes JSON response
+func (c *Client) get(ctx context.Context, response interface{}, path string, body interface{}, headers []*common.Header) error {
+	return (*common.Client)(c).Get(ctx, response, path, body, headers)
+}
+
+// post sends a POST request to the given path with the given request object.
+// No query parameters will be sent if request is nil.
+// response must be a pointer to an object as post writes the response there.
+func (c *Client) post(ctx context.Context, response interface{}, path string, params interface{}, headers []*common.Header, body interface{}) error {
+	return (*common.Client)(c).Post(ctx, response, path, params, headers, body)
+}
+
+// publicKeys converts public keys to their encoded form in requests.
+func publicKeys(pks []ed25519.PublicKey) [][]byte {
+	encoded := make([][]byte, len(pks))
+	for i, pk := range pks {
+		encoded[i] = pk
+	}
+	return encoded
+}
+
+// MakeClient is the factory for constructing a ClientV2 for a given endpoint.
+func MakeClient(address string, apiToken string) (c *Client, err error) {
+	commonClient, err := common.MakeClient(address, authHeader, apiToken)
+	c = (*Client)(commonClient)
+	return
+}
+
+// MakeClientWithHeaders is the factory for constructing a ClientV2 for a
+// given endpoint with custom headers.
+func MakeClientWithHeaders(address string, apiToken string, headers []*common.Header) (c *Client, err error) {
+	commonClientWithHeaders, err := common.MakeClientWithHeaders(address, authHeader, apiToken, headers)
+	c = (*Client)(commonClientWithHeaders)
+	return
+}
+
+// MakeClientWithTransport is the factory for constructing a ClientV2 for a given
+// endpoint with a custom HTTP transport as well as optional additional user
+// defined headers.
+func MakeClientWithTransport(address string, apiToken string, headers []*common.Header, transport http.RoundTripper) (c *Client, err error) {
+	commonClient, err := common.MakeClientWithTransport(address, authHeader, apiToken, headers, transport)
+	c = (*Client)(commonClient)
+	return
+}
+
+// MakeClientWithOptions is the factory for constructing a ClientV2 for a given
+// endpoint with optional behavior such as retries.
+func MakeClientWithOptions(address string, apiToken string, opts ...common.ClientOption) (c *Client, err error) {
+	commonClient, err := common.MakeClientWithOptions(address, authHeader, apiToken, opts...)
+	c = (*Client)(commonClient)
+	return
+}
+
+func (c *Client) GetVersion() *GetVersion {
+	return &GetVersion{c: c}
+}
+
+func (c *Client) ListWallets() *ListWallets {
+	return &ListWallets{c: c}
+}
+
+func (c *Client) CreateWallet(walletName string, walletPassword string) *CreateWallet {
+	return &CreateWallet{c: c, body: models.CreateWalletRequest{WalletName: walletName, WalletPassword: walletPassword}}
+}
+
+func (c *Client) InitWalletHandle(walletId string, walletPassword string) *InitWalletHandle {
+	return &InitWalletHandle{c: c, body: models.InitWalletHandleTokenRequest{WalletId: walletId, WalletPassword: walletPassword}}
+}
+
+func (c *Client) ReleaseWalletHandle(walletHandleToken string) *ReleaseWalletHandle {
+	return &ReleaseWalletHandle{c: c, body: models.WalletHandleTokenRequest{WalletHandleToken: walletHandleToken}}
+}
+
+func (c *Client) RenewWalletHandle(walletHandleToken string) *RenewWalletHandle {
+	return &RenewWalletHandle{c: c, body: models.WalletHandleTokenRequest{WalletHandleToken: walletHandleToken}}
+}
+
+func (c *Client) RenameWallet(walletId string, walletPassword string, newWalletName string) *RenameWallet {
+	return &RenameWallet{c: c, body: models.RenameWalletRequest{WalletId: walletId, WalletPassword: walletPassword, WalletName: newWalletName}}
+}
+
+func (c *Client) GetWallet(walletHandleToken string) *GetWallet {
+	return &GetWallet{c: c, body: models.WalletHandleTokenRequest{WalletHandleToken: walletHandleToken}}
+}
+
+func (c *Client) ExportMasterDerivationKey(walletHandleToken string, walletPassword string) *ExportMasterDerivationKey {
+	return &ExportMasterDerivationKey{c: c, body: models.WalletPasswordRequest{WalletHandleToken: walletHandleToken, WalletPassword: walletPassword}}
+}
+
+func (c *Client) ImportKey(walletHandleToken string, privateKey ed25519.PrivateKey) *ImportKey {
+	return &ImportKey{c: c, body: models.ImportKeyRequest{WalletHandleToken: walletHandleToken, PrivateKey: privateKey}}
+}
+
+func (c *Client) ExportKey(walletHandleToken string, walletPassword string, address string) *ExportKey {
+	return &ExportKey{c: c, body: models.AddressRequest{WalletHandleToken: walletHandleToken, WalletPassword: walletPassword, Address: address}}
+}
+
+func (c *Client) GenerateKey(walletHandleToken string) *GenerateKey {
+	return &GenerateKey{c: c, body: models.GenerateKeyRequest{WalletHandleToken: walletHandleToken}}
+}
+
+func (c *Client) DeleteKey(walletHandleToken string, walletPassword string, address string) *DeleteKey {
+	return &DeleteKey{c: c, body: models.AddressRequest{WalletHandleToken: walletHandleToken, WalletPassword: walletPassword, Address: address}}
+}
+
+func (c *Client) ListKeys(walletHandleToken string) *ListKeys {
+	return &ListKeys{c: c, body: models.WalletHandleTokenRequest{WalletHandleToken: walletHandleToken}}
+}
+
+func (c *Client) SignTransaction(walletHandleToken string, walletPassword string, txn types.Transaction) *SignTransaction {
+	return &SignTransaction{c: c, body: models.SignTransactionRequest{WalletHandleToken: walletHandleToken, WalletPassword: walletPassword, Transaction: msgpack.Encode(txn)}}
+}
+
+func (c *Client) ListMultisig(walletHandleToken string) *ListMultisig {
+	return &ListMultisig{c: c, body: models.WalletHandleTokenRequest{WalletHandleToken: walletHandleToken}}
+}
+
+func (c *Client) ImportMultisig(walletHandleToken string, version uint8, threshold uint8, pks []ed25519.PublicKey) *ImportMultisig {
+	return &ImportMultisig{c: c, body: models.ImportMultisigRequest{WalletHandleToken: walletHandleToken, MultisigVersion: version, Threshold: threshold, Pks: publicKeys(pks)}}
+}
+
+func (c *Client) ExportMultisig(walletHandleToken string, walletPassword string, address string) *ExportMultisig {
+	return &ExportMultisig{c: c, body: models.AddressRequest{WalletHandleToken: walletHandleToken, WalletPassword: walletPassword, Address: address}}
+}
+
+func (c *Client) DeleteMultisig(walletHandleToken string, walletPassword string, address string) *DeleteMultisig {
+	return &DeleteMultisig{c: c, body: models.AddressRequest{WalletHandleToken: walletHandleToken, WalletPassword: walletPassword, Address: address}}
+}
+
+func (c *Client) SignMultisigTransaction(walletHandleToken string, walletPassword string, txn types.Transaction, pk ed25519.PublicKey) *SignMultisigTransaction {
+	return &SignMultisigTransaction{c: c, body: models.SignMultisigRequest{WalletHandleToken: walletHandleToken, WalletPassword: walletPassword, Transaction: msgpack.Encode(txn), PublicKey: pk}}
+}
+
+func (c *Client) SignProgram(walletHandleToken string, walletPassword string, address string, program []byte) *SignProgram {
+	return &SignProgram{c: c, body: models.SignProgramRequest{WalletHandleToken: walletHandleToken, WalletPassword: walletPassword, Address: address, Data: program}}
+}
+
+func (c *Client) SignMultisigProgram(walletHandleToken string, walletPassword string, address string, program []byte, pk ed25519.PublicKey) *SignMultisigProgram {
+	return &SignMultisigProgram{c: c, body: models.SignProgramMultisigRequest{WalletHandleToken: walletHandleToken, WalletPassword: walletPassword, Address: address, Data: program, PublicKey: pk}}
+}
@@ -0,0 +1,261 @@
+package kmd
+
+import (
+	"context"
+	"encoding/base64"
+	stdjson "encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// request is a request received by a kmdServer.
+type request struct {
+	method string
+	path   string
+	token  string
+	body   map[string]interface{}
+}
+
+// kmdServer returns a client of a kmd answering every request with status and
+// response, and the last request received.
+func kmdServer(t *testing.T, status int, response string) (*Client, func() request) {
+	var last request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		last = request{method: r.Method, path: r.URL.Path, token: r.Header.Get(authHeader)}
+		if len(body) > 0 {
+			require.NoError(t, stdjson.Unmarshal(body, &last.body))
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	c, err := MakeClient(server.URL, "token")
+	require.NoError(t, err)
+	return c, func() request { return last }
+}
+
+func b64(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func TestRequests(t *testing.T) {
+	pk := ed25519.PublicKey(make([]byte, ed25519.PublicKeySize))
+	pk[0] = 1
+	sk := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	var mdk types.MasterDerivationKey
+	mdk[0] = 2
+
+	tests := []struct {
+		name   string
+		do     func(c *Client) error
+		method string
+		path   string
+		body   map[string]interface{}
+	}{
+		{"GetVersion", func(c *Client) error {
+			_, err := c.GetVersion().Do(context.Background())
+			return err
+		}, "GET", "/versions", nil},
+		{"ListWallets", func(c *Client) error {
+			_, err := c.ListWallets().Do(context.Background())
+			return err
+		}, "GET", "/v1/wallets", nil},
+		{"CreateWallet", func(c *Client) error {
+			_, err := c.CreateWallet("name", "pw").WalletDriverName("sqlite").MasterDerivationKey(mdk).Do(context.Background())
+			return err
+		}, "POST", "/v1/wallet", map[string]interface{}{
+			"wallet_name": "name", "wallet_password": "pw", "wallet_driver_name": "sqlite", "master_derivation_key": b64(mdk[:]),
+		}},
+		{"InitWalletHandle", func(c *Client) error {
+			_, err := c.InitWalletHandle("id", "pw").Do(context.Background())
+			return err
+		}, "POST", "/v1/wallet/init", map[string]interface{}{"wallet_id": "id", "wallet_password": "pw"}},
+		{"ReleaseWalletHandle", func(c *Client) error {
+			_, err := c.ReleaseWalletHandle("handle").Do(context.Background())
+			return err
+		}, "POST", "/v1/wallet/release", map[string]interface{}{"wallet_handle_token": "handle"}},
+		{"RenewWalletHandle", func(c *Client) error {
+			_, err := c.RenewWalletHandle("handle").Do(context.Background())
+			return err
+		}, "POST", "/v1/wallet/renew", map[string]interface{}{"wallet_handle_token": "handle"}},
+		{"RenameWallet", func(c *Client) error {
+			_, err := c.RenameWallet("id", "pw", "new").Do(context.Background())
+			return err
+		}, "POST", "/v1/wallet/rename", map[string]interface{}{"wallet_id": "id", "wallet_password": "pw", "wallet_name": "new"}},
+		{"GetWallet", func(c *Client) error {
+			_, err := c.GetWallet("handle").Do(context.Background())
+			return err
+		}, "POST", "/v1/wallet/info", map[string]interface{}{"wallet_handle_token": "handle"}},
+		{"ExportMasterDerivationKey", func(c *Client) error {
+			_, err := c.ExportMasterDerivationKey("handle", "pw").Do(context.Background())
+			return err
+		}, "POST", "/v1/master-key/export", map[string]interface{}{"wallet_handle_token": "handle", "wallet_password": "pw"}},
+		{"ImportKey", func(c *Client) error {
+			_, err := c.ImportKey("handle", sk).Do(context.Background())
+			return err
+		}, "POST", "/v1/key/import", map[string]interface{}{"wallet_handle_token": "handle", "private_key": b64(sk)}},
+		{"ExportKey", func(c *Client) error {
+			_, err := c.ExportKey("handle", "pw", "ADDR").Do(context.Background())
+			return err
+		}, "POST", "/v1/key/export", map[string]interface{}{"wallet_handle_token": "handle", "wallet_password": "pw", "address": "ADDR"}},
+		{"GenerateKey", func(c *Client) error {
+			_, err := c.GenerateKey("handle").Do(context.Background())
+			return err
+		}, "POST", "/v1/key", map[string]interface{}{"wallet_handle_token": "handle"}},
+		{"DeleteKey", func(c *Client) error {
+			_, err := c.DeleteKey("handle", "pw", "ADDR").Do(context.Background())
+			return err
+		}, "DELETE", "/v1/key", map[string]interface{}{"wallet_handle_token": "handle", "wallet_password": "pw", "address": "ADDR"}},
+		{"ListKeys", func(c *Client) error {
+			_, err := c.ListKeys("handle").Do(context.Background())
+			return err
+		}, "POST", "/v1/key/list", map[string]interface{}{"wallet_handle_token": "handle"}},
+		{"ListMultisig", func(c *Client) error {
+			_, err := c.ListMultisig("handle").Do(context.Background())
+			return err
+		}, "POST", "/v1/multisig/list", map[string]interface{}{"wallet_handle_token": "handle"}},
+		{"ImportMultisig", func(c *Client) error {
+			_, err := c.ImportMultisig("handle", 1, 2, []ed25519.PublicKey{pk, pk}).Do(context.Background())
+			return err
+		}, "POST", "/v1/multisig/import", map[string]interface{}{
+			"wallet_handle_token": "handle", "multisig_version": float64(1), "threshold": float64(2), "pks": []interface{}{b64(pk), b64(pk)},
+		}},
+		{"ExportMultisig", func(c *Client) error {
+			_, err := c.ExportMultisig("handle", "pw", "ADDR").Do(context.Background())
+			return err
+		}, "POST", "/v1/multisig/export", map[string]interface{}{"wallet_handle_token": "handle", "wallet_password": "pw", "address": "ADDR"}},
+		{"DeleteMultisig", func(c *Client) error {
+			_, err := c.DeleteMultisig("handle", "pw", "ADDR").Do(context.Background())
+			return err
+		}, "DELETE", "/v1/multisig", map[string]interface{}{"wallet_handle_token": "handle", "wallet_password": "pw", "address": "ADDR"}},
+		{"SignProgram", func(c *Client) error {
+			_, err := c.SignProgram("handle", "pw", "ADDR", []byte{1, 2}).Do(context.Background())
+			return err
+		}, "POST", "/v1/program/sign", map[string]interface{}{
+			"wallet_handle_token": "handle", "wallet_password": "pw", "address": "ADDR", "data": b64([]byte{1, 2}),
+		}},
+		{"SignMultisigProgram", func(c *Client) error {
+			_, err := c.SignMultisigProgram("handle", "pw", "ADDR", []byte{1, 2}, pk).Do(context.Background())
+			return err
+		}, "POST", "/v1/multisig/signprogram", map[string]interface{}{
+			"wallet_handle_token": "handle", "wallet_password": "pw", "address": "ADDR", "data": b64([]byte{1, 2}), "public_key": b64(pk),
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, last := kmdServer(t, http.StatusOK, `{}`)
+			require.NoError(t, test.do(c))
+			req := last()
+			require.Equal(t, test.method, req.method)
+			require.Equal(t, test.path, req.path)
+			require.Equal(t, "token", req.token)
+			require.Equal(t, test.body, req.body)
+		})
+	}
+}
+
+func TestWallets(t *testing.T) {
+	c, last := kmdServer(t, http.StatusOK, `{"wallets":[{"id":"id","name":"name","driver_name":"sqlite","driver_version":1,"supported_txs":["pay"]}]}`)
+	wallets, err := c.ListWallets().Do(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []models.Wallet{{Id: "id", Name: "name", DriverName: "sqlite", DriverVersion: 1, SupportedTxs: []string{"pay"}}}, wallets.Wallets)
+	require.Nil(t, last().body)
+
+	c, _ = kmdServer(t, http.StatusOK, `{"wallet_handle":{"expires_seconds":60,"wallet":{"id":"id","name":"name"}}}`)
+	handle, err := c.RenewWalletHandle("handle").Do(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, models.WalletHandle{ExpiresSeconds: 60, Wallet: models.Wallet{Id: "id", Name: "name"}}, handle.WalletHandle)
+}
+
+func TestKeys(t *testing.T) {
+	c, _ := kmdServer(t, http.StatusOK, `{"addresses":["A","B"]}`)
+	keys, err := c.ListKeys("handle").Do(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, keys.Addresses)
+
+	sk := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	c, _ = kmdServer(t, http.StatusOK, `{"private_key":"`+b64(sk)+`"}`)
+	exported, err := c.ExportKey("handle", "pw", "A").Do(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []byte(sk), exported.PrivateKey)
+}
+
+func TestMultisig(t *testing.T) {
+	pk := make([]byte, ed25519.PublicKeySize)
+	c, _ := kmdServer(t, http.StatusOK, `{"multisig_version":1,"threshold":2,"pks":["`+b64(pk)+`"]}`)
+	exported, err := c.ExportMultisig("handle", "pw", "ADDR").Do(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, models.ExportMultisigResponse{MultisigVersion: 1, Threshold: 2, Pks: [][]byte{pk}}, exported)
+}
+
+func TestSignTransaction(t *testing.T) {
+	txn := types.Transaction{Type: types.PaymentTx, Header: types.Header{Fee: 1000, FirstValid: 1, LastValid: 10}}
+	stx := msgpack.Encode(types.SignedTxn{Txn: txn})
+	c, last := kmdServer(t, http.StatusOK, `{"signed_transaction":"`+b64(stx)+`"}`)
+
+	pk := make([]byte, ed25519.PublicKeySize)
+	signed, err := c.SignTransaction("handle", "pw", txn).PublicKey(pk).Do(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, stx, signed.SignedTransaction)
+
+	// the transaction is sent msgpack encoded
+	req := last()
+	require.Equal(t, "/v1/transaction/sign", req.path)
+	require.Equal(t, b64(pk), req.body["public_key"])
+	encoded, err := base64.StdEncoding.DecodeString(req.body["transaction"].(string))
+	require.NoError(t, err)
+	var decoded types.Transaction
+	require.NoError(t, msgpack.Decode(encoded, &decoded))
+	require.Equal(t, txn, decoded)
+}
+
+func TestSignMultisigTransaction(t *testing.T) {
+	txn := types.Transaction{Type: types.PaymentTx, Header: types.Header{Fee: 1000, FirstValid: 1, LastValid: 10}}
+	pk := make([]byte, ed25519.PublicKeySize)
+	partial := types.MultisigSig{Version: 1, Threshold: 1, Subsigs: []types.MultisigSubsig{{Key: pk}}}
+	c, last := kmdServer(t, http.StatusOK, `{"multisig":"`+b64(msgpack.Encode(partial))+`"}`)
+
+	signed, err := c.SignMultisigTransaction("handle", "pw", txn, pk).PartialMultisig(partial).Do(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, msgpack.Encode(partial), signed.Multisig)
+
+	req := last()
+	require.Equal(t, "POST", req.method)
+	require.Equal(t, "/v1/multisig/sign", req.path)
+	require.Equal(t, b64(pk), req.body["public_key"])
+	encoded, err := base64.StdEncoding.DecodeString(req.body["transaction"].(string))
+	require.NoError(t, err)
+	var decoded types.Transaction
+	require.NoError(t, msgpack.Decode(encoded, &decoded))
+	require.Equal(t, txn, decoded)
+	require.Contains(t, req.body, "partial_multisig")
+}
+
+func TestAPIError(t *testing.T) {
+	c, _ := kmdServer(t, http.StatusBadRequest, `{"error":true,"message":"wrong password"}`)
+	_, err := c.ExportMasterDerivationKey("handle", "wrong").Do(context.Background())
+	require.Error(t, err)
+	apiErr, ok := common.AsAPIError(err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	require.Equal(t, "wrong password", apiErr.Message)
+
+	c, _ = kmdServer(t, http.StatusUnauthorized, `{"error":true,"message":"invalid API token"}`)
+	_, err = c.GetVersion().Do(context.Background())
+	apiErr, ok = common.AsAPIError(err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+}
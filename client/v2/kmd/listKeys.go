@@ -0,0 +1,22 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// ListKeys lists the addresses whose keys are stored in a wallet.
+type ListKeys struct {
+	c *Client
+
+	body models.WalletHandleTokenRequest
+}
+
+// Do performs the HTTP request
+func (s *ListKeys) Do(ctx context.Context, headers ...*common.Header) (response models.AddressesResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/key/list", nil, headers, json.Encode(s.body))
+	return
+}
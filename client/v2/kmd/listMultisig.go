@@ -0,0 +1,23 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// ListMultisig lists the multisig addresses whose preimages are stored in a
+// wallet.
+type ListMultisig struct {
+	c *Client
+
+	body models.WalletHandleTokenRequest
+}
+
+// Do performs the HTTP request
+func (s *ListMultisig) Do(ctx context.Context, headers ...*common.Header) (response models.AddressesResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/multisig/list", nil, headers, json.Encode(s.body))
+	return
+}
@@ -0,0 +1,19 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+)
+
+// ListWallets lists the wallets known to kmd.
+type ListWallets struct {
+	c *Client
+}
+
+// Do performs the HTTP request
+func (s *ListWallets) Do(ctx context.Context, headers ...*common.Header) (response models.ListWalletsResponse, err error) {
+	err = s.c.get(ctx, &response, "/v1/wallets", nil, headers)
+	return
+}
@@ -0,0 +1,14 @@
+package models
+
+// AddressRequest a request for an address in a wallet, authorized with its
+// password.
+type AddressRequest struct {
+	// Address the address.
+	Address string `json:"address"`
+
+	// WalletHandleToken the wallet handle token.
+	WalletHandleToken string `json:"wallet_handle_token"`
+
+	// WalletPassword the password of the wallet.
+	WalletPassword string `json:"wallet_password"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// AddressResponse the address of a key or multisig preimage.
+type AddressResponse struct {
+	// Address the address.
+	Address string `json:"address"`
+}
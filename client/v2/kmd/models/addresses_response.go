@@ -0,0 +1,7 @@
+package models
+
+// AddressesResponse the addresses stored in a wallet.
+type AddressesResponse struct {
+	// Addresses the addresses.
+	Addresses []string `json:"addresses"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "github.com/algorand/go-algorand-sdk/v2/types"
+
+// CreateWalletRequest the request to create a wallet.
+type CreateWalletRequest struct {
+	// MasterDerivationKey the master derivation key of the wallet, generated by
+	// kmd if zero.
+	MasterDerivationKey types.MasterDerivationKey `json:"master_derivation_key,omitempty"`
+
+	// WalletDriverName the wallet driver to use, the default driver if empty.
+	WalletDriverName string `json:"wallet_driver_name"`
+
+	// WalletName the name of the wallet.
+	WalletName string `json:"wallet_name"`
+
+	// WalletPassword the password of the wallet.
+	WalletPassword string `json:"wallet_password"`
+}
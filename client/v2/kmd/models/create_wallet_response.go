@@ -0,0 +1,7 @@
+package models
+
+// CreateWalletResponse the created wallet.
+type CreateWalletResponse struct {
+	// Wallet the created wallet.
+	Wallet Wallet `json:"wallet"`
+}
@@ -0,0 +1,5 @@
+package models
+
+// EmptyResponse the response of requests which return no data.
+type EmptyResponse struct {
+}
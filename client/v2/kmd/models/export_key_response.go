@@ -0,0 +1,7 @@
+package models
+
+// ExportKeyResponse the private key of an address.
+type ExportKeyResponse struct {
+	// PrivateKey the ed25519 private key.
+	PrivateKey []byte `json:"private_key,omitempty"`
+}
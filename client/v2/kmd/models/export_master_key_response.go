@@ -0,0 +1,9 @@
+package models
+
+import "github.com/algorand/go-algorand-sdk/v2/types"
+
+// ExportMasterKeyResponse the master derivation key of a wallet.
+type ExportMasterKeyResponse struct {
+	// MasterDerivationKey the master derivation key.
+	MasterDerivationKey types.MasterDerivationKey `json:"master_derivation_key,omitempty"`
+}
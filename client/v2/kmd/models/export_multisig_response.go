@@ -0,0 +1,13 @@
+package models
+
+// ExportMultisigResponse the preimage of a multisig address.
+type ExportMultisigResponse struct {
+	// MultisigVersion the multisig version.
+	MultisigVersion uint8 `json:"multisig_version"`
+
+	// Pks the public keys of the multisig account.
+	Pks [][]byte `json:"pks"`
+
+	// Threshold the number of signatures required.
+	Threshold uint8 `json:"threshold"`
+}
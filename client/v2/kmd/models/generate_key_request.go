@@ -0,0 +1,10 @@
+package models
+
+// GenerateKeyRequest the request to generate the next key of a wallet.
+type GenerateKeyRequest struct {
+	// DisplayMnemonic unused.
+	DisplayMnemonic bool `json:"display_mnemonic,omitempty"`
+
+	// WalletHandleToken the wallet handle token.
+	WalletHandleToken string `json:"wallet_handle_token"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// ImportKeyRequest the request to import a key into a wallet.
+type ImportKeyRequest struct {
+	// PrivateKey the ed25519 private key to import.
+	PrivateKey []byte `json:"private_key,omitempty"`
+
+	// WalletHandleToken the wallet handle token.
+	WalletHandleToken string `json:"wallet_handle_token"`
+}
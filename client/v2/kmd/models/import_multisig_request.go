@@ -0,0 +1,16 @@
+package models
+
+// ImportMultisigRequest the request to import a multisig preimage.
+type ImportMultisigRequest struct {
+	// MultisigVersion the multisig version.
+	MultisigVersion uint8 `json:"multisig_version"`
+
+	// Pks the public keys of the multisig account.
+	Pks [][]byte `json:"pks"`
+
+	// Threshold the number of signatures required.
+	Threshold uint8 `json:"threshold"`
+
+	// WalletHandleToken the wallet handle token.
+	WalletHandleToken string `json:"wallet_handle_token"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// InitWalletHandleTokenRequest the request to unlock a wallet.
+type InitWalletHandleTokenRequest struct {
+	// WalletId the ID of the wallet.
+	WalletId string `json:"wallet_id"`
+
+	// WalletPassword the password of the wallet.
+	WalletPassword string `json:"wallet_password"`
+}
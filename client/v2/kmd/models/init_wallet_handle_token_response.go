@@ -0,0 +1,7 @@
+package models
+
+// InitWalletHandleTokenResponse the wallet handle token of an unlocked wallet.
+type InitWalletHandleTokenResponse struct {
+	// WalletHandleToken the wallet handle token.
+	WalletHandleToken string `json:"wallet_handle_token"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// ListWalletsResponse the wallets known to kmd.
+type ListWalletsResponse struct {
+	// Wallets the wallets.
+	Wallets []Wallet `json:"wallets"`
+}
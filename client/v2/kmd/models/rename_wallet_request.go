@@ -0,0 +1,13 @@
+package models
+
+// RenameWalletRequest the request to rename a wallet.
+type RenameWalletRequest struct {
+	// WalletId the ID of the wallet.
+	WalletId string `json:"wallet_id"`
+
+	// WalletName the new name of the wallet.
+	WalletName string `json:"wallet_name"`
+
+	// WalletPassword the password of the wallet.
+	WalletPassword string `json:"wallet_password"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// RenameWalletResponse the renamed wallet.
+type RenameWalletResponse struct {
+	// Wallet the renamed wallet.
+	Wallet Wallet `json:"wallet"`
+}
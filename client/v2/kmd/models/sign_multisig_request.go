@@ -0,0 +1,21 @@
+package models
+
+import "github.com/algorand/go-algorand-sdk/v2/types"
+
+// SignMultisigRequest the request to add a signature to a multisig transaction.
+type SignMultisigRequest struct {
+	// PartialMultisig the signatures collected so far, may be empty.
+	PartialMultisig types.MultisigSig `json:"partial_multisig,omitempty"`
+
+	// PublicKey the public key to sign with.
+	PublicKey []byte `json:"public_key,omitempty"`
+
+	// Transaction the msgpack encoded transaction.
+	Transaction []byte `json:"transaction,omitempty"`
+
+	// WalletHandleToken the wallet handle token.
+	WalletHandleToken string `json:"wallet_handle_token"`
+
+	// WalletPassword the password of the wallet.
+	WalletPassword string `json:"wallet_password"`
+}
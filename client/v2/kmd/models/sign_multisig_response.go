@@ -0,0 +1,7 @@
+package models
+
+// SignMultisigResponse a multisig signature.
+type SignMultisigResponse struct {
+	// Multisig the msgpack encoded multisig signature.
+	Multisig []byte `json:"multisig,omitempty"`
+}
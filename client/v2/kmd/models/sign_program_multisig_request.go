@@ -0,0 +1,25 @@
+package models
+
+import "github.com/algorand/go-algorand-sdk/v2/types"
+
+// SignProgramMultisigRequest the request to add a signature to a multisig
+// program signature.
+type SignProgramMultisigRequest struct {
+	// Address the multisig address.
+	Address string `json:"address"`
+
+	// Data the program.
+	Data []byte `json:"data,omitempty"`
+
+	// PartialMultisig the signatures collected so far, may be empty.
+	PartialMultisig types.MultisigSig `json:"partial_multisig,omitempty"`
+
+	// PublicKey the public key to sign with.
+	PublicKey []byte `json:"public_key,omitempty"`
+
+	// WalletHandleToken the wallet handle token.
+	WalletHandleToken string `json:"wallet_handle_token"`
+
+	// WalletPassword the password of the wallet.
+	WalletPassword string `json:"wallet_password"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// SignProgramRequest the request to sign a program.
+type SignProgramRequest struct {
+	// Address the address whose key signs the program.
+	Address string `json:"address"`
+
+	// Data the program.
+	Data []byte `json:"data,omitempty"`
+
+	// WalletHandleToken the wallet handle token.
+	WalletHandleToken string `json:"wallet_handle_token"`
+
+	// WalletPassword the password of the wallet.
+	WalletPassword string `json:"wallet_password"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// SignProgramResponse a program signature.
+type SignProgramResponse struct {
+	// Sig the signature of the program.
+	Sig []byte `json:"sig,omitempty"`
+}
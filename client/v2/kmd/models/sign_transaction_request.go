@@ -0,0 +1,16 @@
+package models
+
+// SignTransactionRequest the request to sign a transaction.
+type SignTransactionRequest struct {
+	// PublicKey the public key to sign with, the key of the sender if empty.
+	PublicKey []byte `json:"public_key,omitempty"`
+
+	// Transaction the msgpack encoded transaction.
+	Transaction []byte `json:"transaction,omitempty"`
+
+	// WalletHandleToken the wallet handle token.
+	WalletHandleToken string `json:"wallet_handle_token"`
+
+	// WalletPassword the password of the wallet.
+	WalletPassword string `json:"wallet_password"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// SignTransactionResponse a signed transaction.
+type SignTransactionResponse struct {
+	// SignedTransaction the msgpack encoded signed transaction.
+	SignedTransaction []byte `json:"signed_transaction,omitempty"`
+}
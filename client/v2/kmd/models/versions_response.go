@@ -0,0 +1,7 @@
+package models
+
+// VersionsResponse the kmd API versions supported.
+type VersionsResponse struct {
+	// Versions the supported API versions.
+	Versions []string `json:"versions"`
+}
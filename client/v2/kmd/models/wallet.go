@@ -0,0 +1,22 @@
+package models
+
+// Wallet a kmd wallet.
+type Wallet struct {
+	// DriverName the name of the wallet driver backing the wallet.
+	DriverName string `json:"driver_name"`
+
+	// DriverVersion the version of the wallet driver.
+	DriverVersion uint32 `json:"driver_version"`
+
+	// Id the wallet ID.
+	Id string `json:"id"`
+
+	// MnemonicUx whether the wallet supports mnemonic display.
+	MnemonicUx bool `json:"mnemonic_ux"`
+
+	// Name the wallet name.
+	Name string `json:"name"`
+
+	// SupportedTxs the transaction types the wallet can sign.
+	SupportedTxs []string `json:"supported_txs"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// WalletHandle a wallet handle and the wallet it belongs to.
+type WalletHandle struct {
+	// ExpiresSeconds the number of seconds until the handle expires.
+	ExpiresSeconds int64 `json:"expires_seconds"`
+
+	// Wallet the wallet the handle belongs to.
+	Wallet Wallet `json:"wallet"`
+}
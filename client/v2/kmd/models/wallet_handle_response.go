@@ -0,0 +1,7 @@
+package models
+
+// WalletHandleResponse a wallet handle and the wallet it belongs to.
+type WalletHandleResponse struct {
+	// WalletHandle the wallet handle.
+	WalletHandle WalletHandle `json:"wallet_handle"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// WalletHandleTokenRequest a request identifying a wallet by its handle token.
+type WalletHandleTokenRequest struct {
+	// WalletHandleToken the wallet handle token.
+	WalletHandleToken string `json:"wallet_handle_token"`
+}
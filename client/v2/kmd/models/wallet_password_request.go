@@ -0,0 +1,11 @@
+package models
+
+// WalletPasswordRequest a request identifying a wallet by its handle token,
+// authorized with its password.
+type WalletPasswordRequest struct {
+	// WalletHandleToken the wallet handle token.
+	WalletHandleToken string `json:"wallet_handle_token"`
+
+	// WalletPassword the password of the wallet.
+	WalletPassword string `json:"wallet_password"`
+}
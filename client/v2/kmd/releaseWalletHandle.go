@@ -0,0 +1,22 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// ReleaseWalletHandle invalidates a wallet handle token.
+type ReleaseWalletHandle struct {
+	c *Client
+
+	body models.WalletHandleTokenRequest
+}
+
+// Do performs the HTTP request
+func (s *ReleaseWalletHandle) Do(ctx context.Context, headers ...*common.Header) (response models.EmptyResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/wallet/release", nil, headers, json.Encode(s.body))
+	return
+}
@@ -0,0 +1,22 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// RenameWallet renames a wallet.
+type RenameWallet struct {
+	c *Client
+
+	body models.RenameWalletRequest
+}
+
+// Do performs the HTTP request
+func (s *RenameWallet) Do(ctx context.Context, headers ...*common.Header) (response models.RenameWalletResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/wallet/rename", nil, headers, json.Encode(s.body))
+	return
+}
@@ -0,0 +1,31 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// SignMultisigTransaction adds the signature of a key in a wallet to a multisig
+// transaction.
+type SignMultisigTransaction struct {
+	c *Client
+
+	body models.SignMultisigRequest
+}
+
+// PartialMultisig the signatures collected so far.
+func (s *SignMultisigTransaction) PartialMultisig(PartialMultisig types.MultisigSig) *SignMultisigTransaction {
+	s.body.PartialMultisig = PartialMultisig
+
+	return s
+}
+
+// Do performs the HTTP request
+func (s *SignMultisigTransaction) Do(ctx context.Context, headers ...*common.Header) (response models.SignMultisigResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/multisig/sign", nil, headers, json.Encode(s.body))
+	return
+}
@@ -0,0 +1,23 @@
+package kmd
+
+import (
+	"context"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// SignProgram signs a program with the key of an address, for a delegated logic
+// signature.
+type SignProgram struct {
+	c *Client
+
+	body models.SignProgramRequest
+}
+
+// Do performs the HTTP request
+func (s *SignProgram) Do(ctx context.Context, headers ...*common.Header) (response models.SignProgramResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/program/sign", nil, headers, json.Encode(s.body))
+	return
+}
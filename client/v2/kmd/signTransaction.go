@@ -0,0 +1,32 @@
+package kmd
+
+import (
+	"context"
+
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/kmd/models"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// SignTransaction signs a transaction with the key of its sender.
+type SignTransaction struct {
+	c *Client
+
+	body models.SignTransactionRequest
+}
+
+// PublicKey the public key to sign with, for transactions whose sender has been
+// rekeyed.
+func (s *SignTransaction) PublicKey(PublicKey ed25519.PublicKey) *SignTransaction {
+	s.body.PublicKey = PublicKey
+
+	return s
+}
+
+// Do performs the HTTP request
+func (s *SignTransaction) Do(ctx context.Context, headers ...*common.Header) (response models.SignTransactionResponse, err error) {
+	err = s.c.post(ctx, &response, "/v1/transaction/sign", nil, headers, json.Encode(s.body))
+	return
+}
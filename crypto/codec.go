@@ -0,0 +1,110 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// ErrNonCanonicalCodec is wrapped by the errors of the Helpers whose codec
+// does not produce the canonical msgpack encoding.
+var ErrNonCanonicalCodec = errors.New("codec does not produce the canonical msgpack encoding")
+
+// Helpers are the signing and hashing functions of the package, encoding and
+// decoding with a codec supplied by the application, e.g. one instrumented to
+// record what is signed. Since signatures and IDs are computed over the
+// canonical msgpack encoding, the codec can only observe it: each encoding
+// and decoding of the codec is checked against the msgpack package, and an
+// error wrapping ErrNonCanonicalCodec is returned when they differ.
+//
+// The zero Helpers encode and decode with the msgpack package, as the
+// functions of the package do.
+type Helpers struct {
+	codec encoding.Codec
+}
+
+// codecSample is encoded and decoded by WithCodec to check a codec.
+var codecSample = types.SignedTxn{
+	Txn: types.Transaction{
+		Type: types.PaymentTx,
+		Header: types.Header{
+			Sender:     types.Address{1},
+			Fee:        1000,
+			FirstValid: 1,
+			LastValid:  1001,
+			Note:       []byte("note"),
+		},
+		PaymentTxnFields: types.PaymentTxnFields{
+			Receiver: types.Address{2},
+			Amount:   1,
+		},
+	},
+	Msig: types.MultisigSig{
+		Version:   1,
+		Threshold: 1,
+		Subsigs:   []types.MultisigSubsig{{Key: make([]byte, 32), Sig: types.Signature{3}}},
+	},
+	AuthAddr: types.Address{4},
+}
+
+// WithCodec returns the Helpers encoding and decoding with codec. It returns
+// an error wrapping ErrNonCanonicalCodec when codec does not encode and decode
+// a sample signed transaction as the msgpack package does.
+func WithCodec(codec encoding.Codec) (Helpers, error) {
+	if codec == nil {
+		return Helpers{}, fmt.Errorf("nil codec")
+	}
+	h := Helpers{codec: codec}
+	encoded, err := h.encode(codecSample)
+	if err != nil {
+		return Helpers{}, err
+	}
+	var decoded types.SignedTxn
+	if err := h.decode(encoded, &decoded); err != nil {
+		return Helpers{}, err
+	}
+	return h, nil
+}
+
+// encode returns the encoding of obj.
+func (h Helpers) encode(obj interface{}) ([]byte, error) {
+	return h.encodeTo(nil, obj)
+}
+
+// encodeTo appends the encoding of obj to buf, checking that the codec of h
+// appends the canonical msgpack encoding.
+func (h Helpers) encodeTo(buf []byte, obj interface{}) ([]byte, error) {
+	if h.codec == nil {
+		return msgpack.EncodeTo(buf, obj), nil
+	}
+	n := len(buf)
+	buf = h.codec.EncodeTo(buf, obj)
+	if len(buf) < n || !bytes.Equal(buf[n:], msgpack.Encode(obj)) {
+		return nil, fmt.Errorf("%w: encoding %T", ErrNonCanonicalCodec, obj)
+	}
+	return buf, nil
+}
+
+// decode decodes b into the object pointed to by objptr, checking that the
+// codec of h decodes it as the msgpack package does.
+func (h Helpers) decode(b []byte, objptr interface{}) error {
+	if h.codec == nil {
+		return msgpack.Decode(b, objptr)
+	}
+	if err := h.codec.Decode(b, objptr); err != nil {
+		return err
+	}
+	canonical := reflect.New(reflect.TypeOf(objptr).Elem()).Interface()
+	if err := msgpack.Decode(b, canonical); err != nil {
+		return fmt.Errorf("%w: %v", ErrNonCanonicalCodec, err)
+	}
+	if !bytes.Equal(msgpack.Encode(objptr), msgpack.Encode(canonical)) {
+		return fmt.Errorf("%w: decoding %T", ErrNonCanonicalCodec, objptr)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package crypto
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// recordingCodec records the types of the objects encoded and decoded by
+// codec, and appends extra to the encodings of the objects of type extraFor.
+type recordingCodec struct {
+	codec    encoding.Codec
+	encoded  []string
+	decoded  []string
+	extraFor interface{}
+}
+
+func (c *recordingCodec) Encode(obj interface{}) []byte {
+	return c.EncodeTo(nil, obj)
+}
+
+func (c *recordingCodec) EncodeTo(buf []byte, obj interface{}) []byte {
+	c.encoded = append(c.encoded, typeName(obj))
+	buf = c.codec.EncodeTo(buf, obj)
+	if c.extraFor != nil && typeName(obj) == typeName(c.extraFor) {
+		buf = append(buf, 0xc0)
+	}
+	return buf
+}
+
+func (c *recordingCodec) Decode(b []byte, objptr interface{}) error {
+	c.decoded = append(c.decoded, typeName(objptr))
+	return c.codec.Decode(b, objptr)
+}
+
+func (c *recordingCodec) NewDecoder(r io.Reader) encoding.Decoder {
+	return c.codec.NewDecoder(r)
+}
+
+func typeName(obj interface{}) string {
+	switch obj.(type) {
+	case types.Transaction:
+		return "Transaction"
+	case types.SignedTxn:
+		return "SignedTxn"
+	case *types.SignedTxn:
+		return "*SignedTxn"
+	case types.TxGroup:
+		return "TxGroup"
+	case types.Bid:
+		return "Bid"
+	}
+	return "other"
+}
+
+func TestWithCodec(t *testing.T) {
+	ma, sk1, sk2, _ := makeTestMultisigAccount(t)
+	fromAddr, err := ma.Address()
+	require.NoError(t, err)
+	tx := types.Transaction{
+		Type: types.PaymentTx,
+		Header: types.Header{
+			Sender:     fromAddr,
+			Fee:        1000,
+			FirstValid: 1,
+			LastValid:  1001,
+		},
+		PaymentTxnFields: types.PaymentTxnFields{
+			Receiver: fromAddr,
+			Amount:   5000,
+		},
+	}
+
+	codec := &recordingCodec{codec: encoding.Msgpack}
+	h, err := WithCodec(codec)
+	require.NoError(t, err)
+	codec.encoded, codec.decoded = nil, nil
+
+	txid, err := h.TransactionIDString(tx)
+	require.NoError(t, err)
+	require.Equal(t, TransactionIDString(tx), txid)
+	require.Equal(t, []string{"Transaction"}, codec.encoded)
+
+	txid, stxBytes, err := h.SignMultisigTransaction(sk1, ma, tx)
+	require.NoError(t, err)
+	expectedTxid, expectedBytes, err := SignMultisigTransaction(sk1, ma, tx)
+	require.NoError(t, err)
+	require.Equal(t, expectedTxid, txid)
+	require.Equal(t, expectedBytes, stxBytes)
+
+	codec.encoded = nil
+	txid, stxBytes, err = h.AppendMultisigTransaction(sk2, ma, stxBytes)
+	require.NoError(t, err)
+	expectedTxid, expectedBytes, err = AppendMultisigTransaction(sk2, ma, expectedBytes)
+	require.NoError(t, err)
+	require.Equal(t, expectedTxid, txid)
+	require.Equal(t, expectedBytes, stxBytes)
+	require.Contains(t, codec.encoded, "SignedTxn")
+	require.Contains(t, codec.decoded, "*SignedTxn")
+
+	gid, err := h.ComputeGroupID([]types.Transaction{tx, tx})
+	require.NoError(t, err)
+	expectedGid, err := ComputeGroupID([]types.Transaction{tx, tx})
+	require.NoError(t, err)
+	require.Equal(t, expectedGid, gid)
+	require.Contains(t, codec.encoded, "TxGroup")
+
+	header := types.LightBlockHeader{RoundNumber: 1}
+	digest, err := h.HashLightBlockHeader(header)
+	require.NoError(t, err)
+	require.Equal(t, HashLightBlockHeader(header), digest)
+
+	_, err = WithCodec(nil)
+	require.Error(t, err)
+}
+
+func TestWithCodecNonCanonical(t *testing.T) {
+	_, err := WithCodec(encoding.JSON)
+	require.True(t, errors.Is(err, ErrNonCanonicalCodec), err)
+
+	_, err = WithCodec(&recordingCodec{codec: encoding.Msgpack, extraFor: types.SignedTxn{}})
+	require.True(t, errors.Is(err, ErrNonCanonicalCodec), err)
+
+	// a codec which is canonical for signed transactions only
+	h, err := WithCodec(&recordingCodec{codec: encoding.Msgpack, extraFor: types.Transaction{}})
+	require.NoError(t, err)
+	account := GenerateAccount()
+	tx := types.Transaction{
+		Type:   types.PaymentTx,
+		Header: types.Header{Sender: account.Address, FirstValid: 1, LastValid: 1001},
+	}
+	_, _, err = h.SignTransaction(account.PrivateKey, tx)
+	require.True(t, errors.Is(err, ErrNonCanonicalCodec), err)
+	_, err = h.TransactionID(tx)
+	require.True(t, errors.Is(err, ErrNonCanonicalCodec), err)
+	_, err = h.ComputeGroupID([]types.Transaction{tx})
+	require.True(t, errors.Is(err, ErrNonCanonicalCodec), err)
+
+	_, err = h.SignBid(account.PrivateKey, types.Bid{})
+	require.NoError(t, err)
+}
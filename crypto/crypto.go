@@ -12,7 +12,6 @@ import (
 
 	"golang.org/x/crypto/ed25519"
 
-	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
 	"github.com/algorand/go-algorand-sdk/v2/types"
 )
 
@@ -66,8 +65,17 @@ func GenerateAddressFromSK(sk []byte) (types.Address, error) {
 }
 
 func GetTxID(tx types.Transaction) string {
-	rawTx := rawTransactionBytesToSign(tx)
-	return txIDFromRawTxnBytesToSign(rawTx)
+	txid, _ := Helpers{}.GetTxID(tx)
+	return txid
+}
+
+// GetTxID is GetTxID with the codec of h.
+func (h Helpers) GetTxID(tx types.Transaction) (string, error) {
+	rawTx, err := h.rawTransactionBytesToSign(tx)
+	if err != nil {
+		return "", err
+	}
+	return txIDFromRawTxnBytesToSign(rawTx), nil
 }
 
 // SignTransaction accepts a private key and a transaction, and returns the
@@ -75,7 +83,12 @@ func GetTxID(tx types.Transaction) string {
 // If the SK's corresponding address is different than the txn sender's, the SK's
 // corresponding address will be assigned as AuthAddr
 func SignTransaction(sk ed25519.PrivateKey, tx types.Transaction) (txid string, stxBytes []byte, err error) {
-	s, txid, err := rawSignTransaction(sk, tx)
+	return Helpers{}.SignTransaction(sk, tx)
+}
+
+// SignTransaction is SignTransaction with the codec of h.
+func (h Helpers) SignTransaction(sk ed25519.PrivateKey, tx types.Transaction) (txid string, stxBytes []byte, err error) {
+	s, txid, err := h.rawSignTransaction(sk, tx)
 	if err != nil {
 		return
 	}
@@ -95,19 +108,21 @@ func SignTransaction(sk ed25519.PrivateKey, tx types.Transaction) (txid string,
 	}
 
 	// Encode the SignedTxn
-	stxBytes = msgpack.Encode(stx)
+	stxBytes, err = h.encode(stx)
 	return
 }
 
 // rawTransactionBytesToSign returns the byte form of the tx that we actually sign
 // and compute txID from.
-func rawTransactionBytesToSign(tx types.Transaction) []byte {
-	// Encode the transaction as msgpack
-	encodedTx := msgpack.Encode(tx)
+func (h Helpers) rawTransactionBytesToSign(tx types.Transaction) ([]byte, error) {
+	return h.appendTransactionBytesToSign(nil, tx)
+}
 
-	// Prepend the hashable prefix
-	msgParts := [][]byte{txidPrefix, encodedTx}
-	return bytes.Join(msgParts, nil)
+// appendTransactionBytesToSign appends the hashable prefix and the msgpack
+// encoding of the transaction to buf, so that a buffer can be reused across
+// transactions.
+func (h Helpers) appendTransactionBytesToSign(buf []byte, tx types.Transaction) ([]byte, error) {
+	return h.encodeTo(append(buf, txidPrefix...), tx)
 }
 
 // txID computes a transaction id base32 string from raw transaction bytes
@@ -117,16 +132,18 @@ func txIDFromRawTxnBytesToSign(toBeSigned []byte) (txid string) {
 	return
 }
 
-// txIDFromTransaction is a convenience function for generating txID from txn
-func txIDFromTransaction(tx types.Transaction) (txid string) {
-	txidBytes := TransactionID(tx)
-	txid = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(txidBytes[:])
+// TransactionID is the unique identifier for a Transaction in progress
+func TransactionID(tx types.Transaction) (txid []byte) {
+	txid, _ = Helpers{}.TransactionID(tx)
 	return
 }
 
-// TransactionID is the unique identifier for a Transaction in progress
-func TransactionID(tx types.Transaction) (txid []byte) {
-	toBeSigned := rawTransactionBytesToSign(tx)
+// TransactionID is TransactionID with the codec of h.
+func (h Helpers) TransactionID(tx types.Transaction) (txid []byte, err error) {
+	toBeSigned, err := h.rawTransactionBytesToSign(tx)
+	if err != nil {
+		return
+	}
 	txid32 := sha512.Sum512_256(toBeSigned)
 	txid = txid32[:]
 	return
@@ -134,13 +151,26 @@ func TransactionID(tx types.Transaction) (txid []byte) {
 
 // TransactionIDString is a base32 representation of a TransactionID
 func TransactionIDString(tx types.Transaction) (txid string) {
-	txid = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(TransactionID(tx))
+	txid, _ = Helpers{}.TransactionIDString(tx)
+	return
+}
+
+// TransactionIDString is TransactionIDString with the codec of h.
+func (h Helpers) TransactionIDString(tx types.Transaction) (txid string, err error) {
+	txidBytes, err := h.TransactionID(tx)
+	if err != nil {
+		return
+	}
+	txid = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(txidBytes)
 	return
 }
 
 // rawSignTransaction signs the msgpack-encoded tx (with prepended "TX" prefix), and returns the sig and txid
-func rawSignTransaction(sk ed25519.PrivateKey, tx types.Transaction) (s types.Signature, txid string, err error) {
-	toBeSigned := rawTransactionBytesToSign(tx)
+func (h Helpers) rawSignTransaction(sk ed25519.PrivateKey, tx types.Transaction) (s types.Signature, txid string, err error) {
+	toBeSigned, err := h.rawTransactionBytesToSign(tx)
+	if err != nil {
+		return
+	}
 
 	// Sign the encoded transaction
 	signature := ed25519.Sign(sk, toBeSigned)
@@ -177,8 +207,16 @@ func VerifyBytes(pk ed25519.PublicKey, message, signature []byte) bool {
 // SignBid accepts a private key and a bid, and returns the signature of the
 // bid under that key
 func SignBid(sk ed25519.PrivateKey, bid types.Bid) (signedBid []byte, err error) {
+	return Helpers{}.SignBid(sk, bid)
+}
+
+// SignBid is SignBid with the codec of h.
+func (h Helpers) SignBid(sk ed25519.PrivateKey, bid types.Bid) (signedBid []byte, err error) {
 	// Encode the bid as msgpack
-	encodedBid := msgpack.Encode(bid)
+	encodedBid, err := h.encode(bid)
+	if err != nil {
+		return
+	}
 
 	// Prepend the hashable prefix
 	msgParts := [][]byte{bidPrefix, encodedBid}
@@ -204,7 +242,7 @@ func SignBid(sk ed25519.PrivateKey, bid types.Bid) (signedBid []byte, err error)
 		SignedBid: sb,
 	}
 
-	signedBid = msgpack.Encode(nf)
+	signedBid, err = h.encode(nf)
 	return
 }
 
@@ -248,6 +286,11 @@ func multisigSingle(sk ed25519.PrivateKey, ma MultisigAccount, customSigner sign
 // private key, returning the bytes of a signed transaction with the multisig field
 // partially populated, ready to be passed to other multisig signers to sign or broadcast.
 func SignMultisigTransaction(sk ed25519.PrivateKey, ma MultisigAccount, tx types.Transaction) (txid string, stxBytes []byte, err error) {
+	return Helpers{}.SignMultisigTransaction(sk, ma, tx)
+}
+
+// SignMultisigTransaction is SignMultisigTransaction with the codec of h.
+func (h Helpers) SignMultisigTransaction(sk ed25519.PrivateKey, ma MultisigAccount, tx types.Transaction) (txid string, stxBytes []byte, err error) {
 	err = ma.Validate()
 	if err != nil {
 		return
@@ -255,7 +298,7 @@ func SignMultisigTransaction(sk ed25519.PrivateKey, ma MultisigAccount, tx types
 
 	// this signer signs a transaction and sets txid from the closure
 	customSigner := func() (rawSig types.Signature, err error) {
-		rawSig, txid, err = rawSignTransaction(sk, tx)
+		rawSig, txid, err = h.rawSignTransaction(sk, tx)
 		return rawSig, err
 	}
 
@@ -279,13 +322,18 @@ func SignMultisigTransaction(sk ed25519.PrivateKey, ma MultisigAccount, tx types
 		stx.AuthAddr = maAddress
 	}
 
-	stxBytes = msgpack.Encode(stx)
+	stxBytes, err = h.encode(stx)
 	return
 }
 
 // MergeMultisigTransactions merges the given (partially) signed multisig transactions, and
 // returns an encoded signed multisig transaction with the component signatures.
 func MergeMultisigTransactions(stxsBytes ...[]byte) (txid string, stxBytes []byte, err error) {
+	return Helpers{}.MergeMultisigTransactions(stxsBytes...)
+}
+
+// MergeMultisigTransactions is MergeMultisigTransactions with the codec of h.
+func (h Helpers) MergeMultisigTransactions(stxsBytes ...[]byte) (txid string, stxBytes []byte, err error) {
 	if len(stxsBytes) < 2 {
 		err = errMsigMergeLessThanTwo
 		return
@@ -296,7 +344,7 @@ func MergeMultisigTransactions(stxsBytes ...[]byte) (txid string, stxBytes []byt
 	var refAuthAddr types.Address
 	for _, partStxBytes := range stxsBytes {
 		partStx := types.SignedTxn{}
-		err = msgpack.Decode(partStxBytes, &partStx)
+		err = h.decode(partStxBytes, &partStx)
 		if err != nil {
 			return
 		}
@@ -356,9 +404,12 @@ func MergeMultisigTransactions(stxsBytes ...[]byte) (txid string, stxBytes []byt
 		Txn:      refTx,
 		AuthAddr: refAuthAddr,
 	}
-	stxBytes = msgpack.Encode(stx)
+	stxBytes, err = h.encode(stx)
+	if err != nil {
+		return
+	}
 	// let's also compute the txid.
-	txid = txIDFromTransaction(refTx)
+	txid, err = h.TransactionIDString(refTx)
 	return
 }
 
@@ -367,16 +418,21 @@ func MergeMultisigTransactions(stxsBytes ...[]byte) (txid string, stxBytes []byt
 // While we could compute the multisig preimage from the multisig blob, we ask the caller
 // to pass it back in, to explicitly check that they know who they are signing as.
 func AppendMultisigTransaction(sk ed25519.PrivateKey, ma MultisigAccount, preStxBytes []byte) (txid string, stxBytes []byte, err error) {
+	return Helpers{}.AppendMultisigTransaction(sk, ma, preStxBytes)
+}
+
+// AppendMultisigTransaction is AppendMultisigTransaction with the codec of h.
+func (h Helpers) AppendMultisigTransaction(sk ed25519.PrivateKey, ma MultisigAccount, preStxBytes []byte) (txid string, stxBytes []byte, err error) {
 	preStx := types.SignedTxn{}
-	err = msgpack.Decode(preStxBytes, &preStx)
+	err = h.decode(preStxBytes, &preStx)
 	if err != nil {
 		return
 	}
-	_, partStxBytes, err := SignMultisigTransaction(sk, ma, preStx.Txn)
+	_, partStxBytes, err := h.SignMultisigTransaction(sk, ma, preStx.Txn)
 	if err != nil {
 		return
 	}
-	txid, stxBytes, err = MergeMultisigTransactions(partStxBytes, preStxBytes)
+	txid, stxBytes, err = h.MergeMultisigTransactions(partStxBytes, preStxBytes)
 	return
 }
 
@@ -436,11 +492,17 @@ func VerifyMultisig(addr types.Address, message []byte, msig types.MultisigSig)
 
 // ComputeGroupID returns group ID for a group of transactions
 func ComputeGroupID(txgroup []types.Transaction) (gid types.Digest, err error) {
+	return Helpers{}.ComputeGroupID(txgroup)
+}
+
+// ComputeGroupID is ComputeGroupID with the codec of h.
+func (h Helpers) ComputeGroupID(txgroup []types.Transaction) (gid types.Digest, err error) {
 	if len(txgroup) > types.MaxTxGroupSize {
 		err = fmt.Errorf("txgroup too large, %v > max size %v", len(txgroup), types.MaxTxGroupSize)
 		return
 	}
 	var group types.TxGroup
+	var buf []byte
 	empty := types.Digest{}
 	for _, tx := range txgroup {
 		if tx.Group != empty {
@@ -448,11 +510,18 @@ func ComputeGroupID(txgroup []types.Transaction) (gid types.Digest, err error) {
 			return
 		}
 
-		txID := sha512.Sum512_256(rawTransactionBytesToSign(tx))
+		buf, err = h.appendTransactionBytesToSign(buf[:0], tx)
+		if err != nil {
+			return
+		}
+		txID := sha512.Sum512_256(buf)
 		group.TxGroupHashes = append(group.TxGroupHashes, txID)
 	}
 
-	encoded := msgpack.Encode(group)
+	encoded, err := h.encode(group)
+	if err != nil {
+		return
+	}
 
 	// Prepend the hashable prefix and hash it
 	msgParts := [][]byte{tgidPrefix, encoded}
@@ -539,14 +608,17 @@ func VerifyLogicSig(lsig types.LogicSig, singleSigner types.Address) (result boo
 // signLogicSigTransactionWithAddress signs a transaction with a LogicSig.
 //
 // lsigAddress is the address of the account that the LogicSig represents.
-func signLogicSigTransactionWithAddress(lsig types.LogicSig, lsigAddress types.Address, tx types.Transaction) (txid string, stxBytes []byte, err error) {
+func (h Helpers) signLogicSigTransactionWithAddress(lsig types.LogicSig, lsigAddress types.Address, tx types.Transaction) (txid string, stxBytes []byte, err error) {
 
 	if !VerifyLogicSig(lsig, lsigAddress) {
 		err = errLsigInvalidSignature
 		return
 	}
 
-	txid = txIDFromTransaction(tx)
+	txid, err = h.TransactionIDString(tx)
+	if err != nil {
+		return
+	}
 	// Construct the SignedTxn
 	stx := types.SignedTxn{
 		Lsig: lsig,
@@ -558,7 +630,7 @@ func signLogicSigTransactionWithAddress(lsig types.LogicSig, lsigAddress types.A
 	}
 
 	// Encode the SignedTxn
-	stxBytes = msgpack.Encode(stx)
+	stxBytes, err = h.encode(stx)
 	return
 }
 
@@ -568,12 +640,18 @@ func signLogicSigTransactionWithAddress(lsig types.LogicSig, lsigAddress types.A
 // LogicSig, but the network will reject the transaction if the LogicSig's
 // program declines the transaction.
 func SignLogicSigAccountTransaction(logicSigAccount LogicSigAccount, tx types.Transaction) (txid string, stxBytes []byte, err error) {
+	return Helpers{}.SignLogicSigAccountTransaction(logicSigAccount, tx)
+}
+
+// SignLogicSigAccountTransaction is SignLogicSigAccountTransaction with the
+// codec of h.
+func (h Helpers) SignLogicSigAccountTransaction(logicSigAccount LogicSigAccount, tx types.Transaction) (txid string, stxBytes []byte, err error) {
 	addr, err := logicSigAccount.Address()
 	if err != nil {
 		return
 	}
 
-	txid, stxBytes, err = signLogicSigTransactionWithAddress(logicSigAccount.Lsig, addr, tx)
+	txid, stxBytes, err = h.signLogicSigTransactionWithAddress(logicSigAccount.Lsig, addr, tx)
 	return
 }
 
@@ -587,6 +665,11 @@ func SignLogicSigAccountTransaction(logicSigAccount LogicSigAccount, tx types.Tr
 // account. In order to properly handle that case, create a LogicSigAccount and
 // use SignLogicSigAccountTransaction instead.
 func SignLogicSigTransaction(lsig types.LogicSig, tx types.Transaction) (txid string, stxBytes []byte, err error) {
+	return Helpers{}.SignLogicSigTransaction(lsig, tx)
+}
+
+// SignLogicSigTransaction is SignLogicSigTransaction with the codec of h.
+func (h Helpers) SignLogicSigTransaction(lsig types.LogicSig, tx types.Transaction) (txid string, stxBytes []byte, err error) {
 	hasSig := lsig.Sig != (types.Signature{})
 	hasMsig := !lsig.Msig.Blank()
 
@@ -612,7 +695,7 @@ func SignLogicSigTransaction(lsig types.LogicSig, tx types.Transaction) (txid st
 		lsigAddress = LogicSigAddress(lsig)
 	}
 
-	txid, stxBytes, err = signLogicSigTransactionWithAddress(lsig, lsigAddress, tx)
+	txid, stxBytes, err = h.signLogicSigTransactionWithAddress(lsig, lsigAddress, tx)
 	return
 }
 
@@ -760,21 +843,37 @@ func GetApplicationAddress(appID uint64) types.Address {
 }
 
 func HashStateProofMessage(stateProofMessage *types.Message) types.MessageHash {
-	msgPackedStateProofMessage := msgpack.Encode(stateProofMessage)
+	hash, _ := Helpers{}.HashStateProofMessage(stateProofMessage)
+	return hash
+}
+
+// HashStateProofMessage is HashStateProofMessage with the codec of h.
+func (h Helpers) HashStateProofMessage(stateProofMessage *types.Message) (types.MessageHash, error) {
+	msgPackedStateProofMessage, err := h.encode(stateProofMessage)
+	if err != nil {
+		return types.MessageHash{}, err
+	}
 
 	stateProofMessageData := make([]byte, 0, len(StateProofMessagePrefix)+len(msgPackedStateProofMessage))
 	stateProofMessageData = append(stateProofMessageData, StateProofMessagePrefix...)
 	stateProofMessageData = append(stateProofMessageData, msgPackedStateProofMessage...)
 
-	return sha256.Sum256(stateProofMessageData)
+	return sha256.Sum256(stateProofMessageData), nil
 }
 
 func HashLightBlockHeader(lightBlockHeader types.LightBlockHeader) types.Digest {
-	msgPackedLightBlockHeader := msgpack.Encode(lightBlockHeader)
+	hash, _ := Helpers{}.HashLightBlockHeader(lightBlockHeader)
+	return hash
+}
 
-	lightBlockHeaderData := make([]byte, 0, len(LightBlockHeaderPrefix)+len(msgPackedLightBlockHeader))
+// HashLightBlockHeader is HashLightBlockHeader with the codec of h.
+func (h Helpers) HashLightBlockHeader(lightBlockHeader types.LightBlockHeader) (types.Digest, error) {
+	lightBlockHeaderData := make([]byte, 0, len(LightBlockHeaderPrefix))
 	lightBlockHeaderData = append(lightBlockHeaderData, LightBlockHeaderPrefix...)
-	lightBlockHeaderData = append(lightBlockHeaderData, msgpack.Encode(lightBlockHeader)...)
+	lightBlockHeaderData, err := h.encodeTo(lightBlockHeaderData, lightBlockHeader)
+	if err != nil {
+		return types.Digest{}, err
+	}
 
-	return sha256.Sum256(lightBlockHeaderData)
+	return sha256.Sum256(lightBlockHeaderData), nil
 }
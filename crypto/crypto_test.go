@@ -74,7 +74,8 @@ func TestSignMultisigTransaction(t *testing.T) {
 	require.Equal(t, types.Address{}, stx.AuthAddr)
 	require.Equal(t, tx, stx.Txn)
 
-	bytesToSign := rawTransactionBytesToSign(stx.Txn)
+	bytesToSign, err := Helpers{}.rawTransactionBytesToSign(stx.Txn)
+	require.NoError(t, err)
 	verified := VerifyMultisig(fromAddr, bytesToSign, stx.Msig)
 	require.False(t, verified) // not enough signatures
 }
@@ -117,7 +118,8 @@ func TestSignMultisigTransactionWithAuthAddr(t *testing.T) {
 	require.Equal(t, multisigAddr, stx.AuthAddr)
 	require.Equal(t, tx, stx.Txn)
 
-	bytesToSign := rawTransactionBytesToSign(stx.Txn)
+	bytesToSign, err := Helpers{}.rawTransactionBytesToSign(stx.Txn)
+	require.NoError(t, err)
 	verified := VerifyMultisig(multisigAddr, bytesToSign, stx.Msig)
 	require.False(t, verified) // not enough signatures
 }
@@ -136,7 +138,8 @@ func TestAppendMultisigTransaction(t *testing.T) {
 	var stx types.SignedTxn
 	err = msgpack.Decode(txBytes, &stx)
 	require.NoError(t, err)
-	bytesToSign := rawTransactionBytesToSign(stx.Txn)
+	bytesToSign, err := Helpers{}.rawTransactionBytesToSign(stx.Txn)
+	require.NoError(t, err)
 
 	fromAddr, err := ma.Address()
 	require.NoError(t, err)
@@ -168,7 +171,8 @@ func TestAppendMultisigTransactionWithAuthAddr(t *testing.T) {
 	var stx types.SignedTxn
 	err = msgpack.Decode(txBytes, &stx)
 	require.NoError(t, err)
-	bytesToSign := rawTransactionBytesToSign(stx.Txn)
+	bytesToSign, err := Helpers{}.rawTransactionBytesToSign(stx.Txn)
+	require.NoError(t, err)
 
 	multisigAddr, err := ma.Address()
 	require.NoError(t, err)
@@ -0,0 +1,110 @@
+// Package encoding defines the Codec interface through which the REST clients
+// encode and decode objects, so that an embedding application can supply its
+// own codec, e.g. one instrumented to record what is encoded, or an
+// alternative implementation for fuzzing or schema auditing. The signing and
+// hashing helpers of the crypto package take a codec through crypto.WithCodec,
+// which only accepts codecs producing the canonical msgpack encoding. The
+// implementations in the msgpack and json packages are available as Msgpack,
+// LenientMsgpack, JSON and LenientJSON.
+package encoding
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+)
+
+// Codec encodes and decodes objects in one format.
+type Codec interface {
+	// Encode returns the encoding of obj. Like msgpack.Encode, it panics if
+	// obj cannot be encoded.
+	Encode(obj interface{}) []byte
+
+	// EncodeTo appends the encoding of obj to buf and returns the extended
+	// buffer.
+	EncodeTo(buf []byte, obj interface{}) []byte
+
+	// Decode decodes b into the object pointed to by objptr.
+	Decode(b []byte, objptr interface{}) error
+
+	// NewDecoder returns a Decoder reading successive objects from r.
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Decoder decodes objects from a stream.
+type Decoder interface {
+	Decode(objptr interface{}) error
+}
+
+var (
+	// Msgpack is the canonical msgpack codec of the msgpack package, which
+	// rejects unknown fields when decoding with an error wrapping
+	// msgpack.ErrUnknownField.
+	Msgpack Codec = msgpackCodec{}
+
+	// LenientMsgpack is Msgpack ignoring unknown fields when decoding, as
+	// used for the responses of the REST APIs.
+	LenientMsgpack Codec = msgpackCodec{lenient: true}
+
+	// JSON is the JSON codec of the json package, which rejects unknown
+	// fields when decoding.
+	JSON Codec = jsonCodec{}
+
+	// LenientJSON is JSON ignoring unknown fields when decoding, as used for
+	// the responses of the REST APIs.
+	LenientJSON Codec = jsonCodec{lenient: true}
+)
+
+type msgpackCodec struct {
+	lenient bool
+}
+
+func (msgpackCodec) Encode(obj interface{}) []byte {
+	return msgpack.Encode(obj)
+}
+
+func (msgpackCodec) EncodeTo(buf []byte, obj interface{}) []byte {
+	return msgpack.EncodeTo(buf, obj)
+}
+
+func (c msgpackCodec) Decode(b []byte, objptr interface{}) error {
+	if c.lenient {
+		return msgpack.NewLenientDecoder(bytes.NewReader(b)).Decode(objptr)
+	}
+	return msgpack.Decode(b, objptr)
+}
+
+func (c msgpackCodec) NewDecoder(r io.Reader) Decoder {
+	if c.lenient {
+		return msgpack.NewLenientDecoder(r)
+	}
+	return msgpack.NewStreamDecoder(r)
+}
+
+type jsonCodec struct {
+	lenient bool
+}
+
+func (jsonCodec) Encode(obj interface{}) []byte {
+	return json.Encode(obj)
+}
+
+func (jsonCodec) EncodeTo(buf []byte, obj interface{}) []byte {
+	return append(buf, json.Encode(obj)...)
+}
+
+func (c jsonCodec) Decode(b []byte, objptr interface{}) error {
+	if c.lenient {
+		return json.LenientDecode(b, objptr)
+	}
+	return json.Decode(b, objptr)
+}
+
+func (c jsonCodec) NewDecoder(r io.Reader) Decoder {
+	if c.lenient {
+		return json.NewLenientDecoder(r)
+	}
+	return json.NewDecoder(r)
+}
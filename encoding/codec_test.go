@@ -0,0 +1,53 @@
+package encoding
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type object struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+	A       uint64   `codec:"a"`
+	B       string   `codec:"b"`
+}
+
+type unknownField struct {
+	object
+	C uint64 `codec:"c"`
+}
+
+func TestCodecs(t *testing.T) {
+	obj := object{A: 1, B: "b"}
+	for name, pair := range map[string][2]Codec{
+		"msgpack": {Msgpack, LenientMsgpack},
+		"json":    {JSON, LenientJSON},
+	} {
+		strict, lenient := pair[0], pair[1]
+		t.Run(name, func(t *testing.T) {
+			encoded := strict.Encode(obj)
+			require.Equal(t, encoded, lenient.Encode(obj))
+			require.Equal(t, append([]byte("x"), encoded...), strict.EncodeTo([]byte("x"), obj))
+
+			var decoded object
+			require.NoError(t, strict.Decode(encoded, &decoded))
+			require.Equal(t, obj, decoded)
+
+			decoded = object{}
+			require.NoError(t, strict.NewDecoder(bytes.NewReader(encoded)).Decode(&decoded))
+			require.Equal(t, obj, decoded)
+
+			unknown := strict.Encode(unknownField{object: obj, C: 3})
+			require.Error(t, strict.Decode(unknown, &decoded))
+			require.Error(t, strict.NewDecoder(bytes.NewReader(unknown)).Decode(&decoded))
+
+			decoded = object{}
+			require.NoError(t, lenient.Decode(unknown, &decoded))
+			require.Equal(t, obj, decoded)
+			decoded = object{}
+			require.NoError(t, lenient.NewDecoder(bytes.NewReader(unknown)).Decode(&decoded))
+			require.Equal(t, obj, decoded)
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package json
+
+import (
+	"bytes"
+	"encoding/base64"
+	stdjson "encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EncodeDisplay returns the display JSON of obj, e.g. a transaction or the
+// transactions of a group: a deterministic JSON encoding meant for audit logs
+// and human review, where the same object must always produce the same bytes.
+//
+// Every value is annotated with its Go type, as an object of the form
+// {"type": "types.MicroAlgos", "value": 1000}. Struct fields are listed under
+// their msgpack names in sorted order, with embedded structs flattened, and
+// are included even when zero, so that a reviewer sees every field which was
+// not set. Map keys are sorted too. Values with a String method, such as
+// addresses, are shown as that string, and other byte slices and arrays in
+// base64.
+//
+// An error is returned if obj holds a value with no JSON representation, such
+// as a channel, a function, a complex number or a NaN or infinite float.
+func EncodeDisplay(obj interface{}) ([]byte, error) {
+	var compact bytes.Buffer
+	if err := writeDisplayValue(&compact, reflect.ValueOf(obj)); err != nil {
+		return nil, err
+	}
+
+	var indented bytes.Buffer
+	if err := stdjson.Indent(&indented, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	return indented.Bytes(), nil
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// writeDisplayValue writes v along with its type.
+func writeDisplayValue(buf *bytes.Buffer, v reflect.Value) error {
+	buf.WriteString(`{"type":`)
+	if !v.IsValid() {
+		buf.WriteString(`"nil","value":null}`)
+		return nil
+	}
+	writeDisplayString(buf, v.Type().String())
+	buf.WriteString(`,"value":`)
+	if err := writeDisplayData(buf, v); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// writeDisplayData writes v without its type. Only struct fields are
+// annotated with their types, so that slices and maps of plain values stay
+// readable.
+func writeDisplayData(buf *bytes.Buffer, v reflect.Value) error {
+	if v.CanInterface() && v.Type().Implements(stringerType) && (v.Kind() != reflect.Ptr || !v.IsNil()) {
+		writeDisplayString(buf, v.Interface().(fmt.Stringer).String())
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeDisplayData(buf, v.Elem())
+	case reflect.Bool:
+		buf.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("cannot encode %s value %v as display JSON", v.Type(), f)
+		}
+		buf.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()))
+	case reflect.String:
+		writeDisplayString(buf, v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			writeDisplayString(buf, base64.StdEncoding.EncodeToString(b))
+			return nil
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeDisplayData(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]reflect.Value, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := displayMapKey(iter.Key())
+			keys = append(keys, key)
+			values[key] = iter.Value()
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeDisplayString(buf, key)
+			buf.WriteByte(':')
+			if err := writeDisplayData(buf, values[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case reflect.Struct:
+		fields := make(map[string]reflect.Value)
+		collectDisplayFields(v, fields)
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		buf.WriteByte('{')
+		for i, name := range names {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeDisplayString(buf, name)
+			buf.WriteByte(':')
+			if err := writeDisplayValue(buf, fields[name]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("cannot encode %s as display JSON", v.Type())
+	}
+	return nil
+}
+
+// collectDisplayFields adds the exported fields of the struct v to fields
+// under their msgpack names, flattening embedded structs without a name.
+func collectDisplayFields(v reflect.Value, fields map[string]reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("codec"), ",")[0]
+		if name == "-" || f.Name == "_struct" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			collectDisplayFields(v.Field(i), fields)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = v.Field(i)
+	}
+}
+
+func displayMapKey(k reflect.Value) string {
+	if k.CanInterface() && k.Type().Implements(stringerType) {
+		return k.Interface().(fmt.Stringer).String()
+	}
+	return fmt.Sprint(k)
+}
+
+func writeDisplayString(buf *bytes.Buffer, s string) {
+	// marshalling a string cannot fail
+	b, _ := stdjson.Marshal(s)
+	buf.Write(b)
+}
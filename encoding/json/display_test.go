@@ -0,0 +1,110 @@
+package json
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type displayName [4]byte
+
+func (n displayName) String() string {
+	return "name:" + string(n[:])
+}
+
+type displayHeader struct {
+	Fee  uint64      `codec:"fee"`
+	Note []byte      `codec:"note"`
+	Name displayName `codec:"name"`
+}
+
+type displayObject struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+	displayHeader
+	Amount   uint64            `codec:"amt"`
+	Accounts []displayName     `codec:"apat"`
+	Boxes    map[string]uint64 `codec:"box"`
+	Ignored  uint64            `codec:"-"`
+	private  uint64
+}
+
+func TestEncodeDisplay(t *testing.T) {
+	obj := displayObject{
+		displayHeader: displayHeader{Fee: 1000, Note: []byte("hi"), Name: displayName{'a', 'b', 'c', 'd'}},
+		Accounts:      []displayName{{'e', 'f', 'g', 'h'}},
+		Boxes:         map[string]uint64{"z": 1, "a": 2},
+		Ignored:       3,
+		private:       4,
+	}
+
+	expected := `{
+  "type": "json.displayObject",
+  "value": {
+    "amt": {
+      "type": "uint64",
+      "value": 0
+    },
+    "apat": {
+      "type": "[]json.displayName",
+      "value": [
+        "name:efgh"
+      ]
+    },
+    "box": {
+      "type": "map[string]uint64",
+      "value": {
+        "a": 2,
+        "z": 1
+      }
+    },
+    "fee": {
+      "type": "uint64",
+      "value": 1000
+    },
+    "name": {
+      "type": "json.displayName",
+      "value": "name:abcd"
+    },
+    "note": {
+      "type": "[]uint8",
+      "value": "aGk="
+    }
+  }
+}`
+	encoded, err := EncodeDisplay(obj)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(encoded))
+
+	group, err := EncodeDisplay([]displayObject{obj, {}})
+	require.NoError(t, err)
+	require.Contains(t, string(group), `"type": "[]json.displayObject"`)
+	require.Contains(t, string(group), `"value": []`)
+	again, err := EncodeDisplay([]displayObject{obj, {}})
+	require.NoError(t, err)
+	require.Equal(t, group, again)
+}
+
+func TestEncodeDisplayUnsupported(t *testing.T) {
+	tests := []interface{}{
+		make(chan int),
+		func() {},
+		complex(1, 2),
+		math.NaN(),
+		math.Inf(1),
+		[]float32{float32(math.Inf(-1))},
+		map[string]interface{}{"f": func() {}},
+		struct {
+			F chan int `codec:"f"`
+		}{},
+	}
+	for _, obj := range tests {
+		encoded, err := EncodeDisplay(obj)
+		require.Error(t, err, "%T", obj)
+		require.Nil(t, encoded)
+	}
+
+	encoded, err := EncodeDisplay(1.5)
+	require.NoError(t, err)
+	require.Contains(t, string(encoded), `"value": 1.5`)
+}
@@ -0,0 +1,45 @@
+package msgpack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// ErrNotCanonical is returned by VerifyCanonical for encodings which decode
+// successfully but are not in canonical form.
+var ErrNotCanonical = errors.New("msgpack encoding is not canonical")
+
+// VerifyCanonical checks that b is the canonical encoding of an object of the
+// type objptr points to, as produced by Encode: map keys sorted, zero values
+// omitted, integers and lengths in their shortest form and no trailing data.
+// Only canonical encodings hash to the IDs the network computes, so externally
+// produced transactions should be verified before they are hashed. b is
+// decoded into objptr, and ErrNotCanonical is returned if it decodes but is
+// not canonical.
+func VerifyCanonical(b []byte, objptr interface{}) error {
+	if err := Decode(b, objptr); err != nil {
+		return err
+	}
+	canonical := Encode(objptr)
+	if !bytes.Equal(b, canonical) {
+		return fmt.Errorf("%w: differs from canonical encoding at byte %d", ErrNotCanonical, firstDifference(b, canonical))
+	}
+	return nil
+}
+
+// Normalize decodes b into objptr and returns its canonical encoding.
+func Normalize(b []byte, objptr interface{}) ([]byte, error) {
+	if err := Decode(b, objptr); err != nil {
+		return nil, err
+	}
+	return Encode(objptr), nil
+}
+
+func firstDifference(a, b []byte) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,49 @@
+package msgpack
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type canonicalObject struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+	Data    string   `codec:"data"`
+	Name    string   `codec:"name"`
+}
+
+func TestVerifyCanonical(t *testing.T) {
+	obj := canonicalObject{Data: "data", Name: "name"}
+	canonical := Encode(obj)
+
+	var decoded canonicalObject
+	require.NoError(t, VerifyCanonical(canonical, &decoded))
+	require.Equal(t, obj, decoded)
+
+	nonCanonical := map[string][]byte{
+		// keys out of order
+		"unsorted": {0x82, 0xa4, 'n', 'a', 'm', 'e', 0xa1, 'n', 0xa4, 'd', 'a', 't', 'a', 0xa1, 'd'},
+		// zero value not omitted
+		"zero value": {0x82, 0xa4, 'd', 'a', 't', 'a', 0xa1, 'd', 0xa4, 'n', 'a', 'm', 'e', 0xa0},
+		// string length not in its shortest form
+		"long length": {0x81, 0xa4, 'd', 'a', 't', 'a', 0xd9, 0x01, 'd'},
+		"trailing":    append(Encode(obj), 0x00),
+	}
+	for name, b := range nonCanonical {
+		t.Run(name, func(t *testing.T) {
+			var decoded canonicalObject
+			err := VerifyCanonical(b, &decoded)
+			require.True(t, errors.Is(err, ErrNotCanonical), err)
+
+			normalized, err := Normalize(b, &decoded)
+			require.NoError(t, err)
+			require.NoError(t, VerifyCanonical(normalized, &decoded))
+		})
+	}
+
+	// invalid encodings are reported as decoding errors
+	err := VerifyCanonical([]byte{0x81, 0xa7, 'u', 'n', 'k', 'n', 'o', 'w', 'n', 0x01}, &decoded)
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrNotCanonical))
+}
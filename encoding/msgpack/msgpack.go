@@ -1,7 +1,11 @@
 package msgpack
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"strings"
+	"sync"
 
 	"github.com/algorand/go-codec/codec"
 )
@@ -33,25 +37,74 @@ func init() {
 	LenientCodecHandle.PositiveIntUnsigned = true
 }
 
+// maxPooledBufferSize bounds the buffers kept by pooled encoders, so that
+// encoding an occasional large object does not pin its memory.
+const maxPooledBufferSize = 64 * 1024
+
+// pooledEncoder is an encoder along with the buffer it encodes into.
+type pooledEncoder struct {
+	enc *codec.Encoder
+	buf []byte
+}
+
+var encoderPool = sync.Pool{
+	New: func() interface{} {
+		e := &pooledEncoder{}
+		e.enc = codec.NewEncoderBytes(&e.buf, CodecHandle)
+		return e
+	},
+}
+
 // Encode returns a msgpack-encoded byte buffer for a given object
 func Encode(obj interface{}) []byte {
-	var b []byte
-	enc := codec.NewEncoderBytes(&b, CodecHandle)
-	enc.MustEncode(obj)
-	return b
+	return EncodeTo(nil, obj)
 }
 
+// EncodeTo appends the msgpack encoding of obj to buf and returns the extended
+// buffer, like the strconv Append functions. Encoders are pooled, so encoding
+// into a buffer with enough capacity, e.g. one reused across calls, does not
+// allocate.
+func EncodeTo(buf []byte, obj interface{}) []byte {
+	e := encoderPool.Get().(*pooledEncoder)
+	e.enc.ResetBytes(&e.buf)
+	e.enc.MustEncode(obj)
+	buf = append(buf, e.buf...)
+
+	if cap(e.buf) <= maxPooledBufferSize {
+		encoderPool.Put(e)
+	}
+	return buf
+}
+
+// ErrUnknownField is wrapped by the errors of Decode and of the decoders of
+// NewStreamDecoder when the encoding has a field the decoded type does not
+// have, e.g. a consensus field added after this version of the SDK. Decoding
+// such an object leniently and re-encoding it would silently drop the field.
+var ErrUnknownField = errors.New("msgpack: unknown field")
+
 // Decode attempts to decode a msgpack-encoded byte buffer into an
-// object instance pointed to by objptr
+// object instance pointed to by objptr. Unlike the lenient decoders used for
+// the REST APIs, it fails on fields unknown to the type of objptr, with an
+// error wrapping ErrUnknownField.
 func Decode(b []byte, objptr interface{}) error {
 	dec := codec.NewDecoderBytes(b, CodecHandle)
 	err := dec.Decode(objptr)
 	if err != nil {
-		return err
+		return wrapUnknownField(err)
 	}
 	return nil
 }
 
+// wrapUnknownField wraps ErrUnknownField around the errors go-codec returns
+// for unknown fields when ErrorIfNoField is set, which have no type of their
+// own.
+func wrapUnknownField(err error) error {
+	if err != nil && strings.Contains(err.Error(), "no matching struct field found") {
+		return fmt.Errorf("%w: %v", ErrUnknownField, err)
+	}
+	return err
+}
+
 // NewDecoder returns a msgpack decoder
 func NewDecoder(r io.Reader) *codec.Decoder {
 	return codec.NewDecoder(r, CodecHandle)
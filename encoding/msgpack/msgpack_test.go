@@ -49,6 +49,18 @@ func TestDecode(t *testing.T) {
 		assert.Contains(t, err.Error(), "no matching struct field found when decoding stream map with key name")
 	})
 
+	t.Run("strict decode subset, unknown field error", func(t *testing.T) {
+		var decoded subsetObject
+		err := Decode(encodedOb, &decoded)
+		assert.ErrorIs(t, err, ErrUnknownField)
+
+		err = NewStreamDecoder(bytes.NewReader(encodedOb)).Decode(&decoded)
+		assert.ErrorIs(t, err, ErrUnknownField)
+
+		err = NewLenientStreamDecoder(bytes.NewReader(encodedOb)).Decode(&decoded)
+		assert.NoError(t, err)
+	})
+
 	t.Run("lenient decode subset, pass", func(t *testing.T) {
 		// strict decode test
 		decoder := NewLenientDecoder(bytes.NewReader(encodedOb))
@@ -58,3 +70,18 @@ func TestDecode(t *testing.T) {
 		assert.Equal(t, obj.subsetObject, decoded)
 	})
 }
+
+func TestEncodeTo(t *testing.T) {
+	obj := object{subsetObject: subsetObject{Data: "data"}, Name: "name"}
+	encoded := Encode(obj)
+
+	prefix := []byte("prefix")
+	assert.Equal(t, append(append([]byte{}, prefix...), encoded...), EncodeTo(prefix, obj))
+	assert.Equal(t, "prefix", string(prefix))
+
+	// a buffer with enough capacity is reused
+	buf := make([]byte, 0, 1024)
+	reused := EncodeTo(buf, &obj)
+	assert.Equal(t, encoded, reused)
+	assert.Equal(t, &buf[:1][0], &reused[0])
+}
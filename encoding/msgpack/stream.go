@@ -0,0 +1,241 @@
+package msgpack
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/algorand/go-codec/codec"
+)
+
+// StreamDecoder reads msgpack from an io.Reader one element at a time, so that
+// large payloads such as blocks can be processed without holding the whole
+// encoding or its decoded form in memory. Containers are entered with
+// ReadMapHeader and ReadArrayHeader, and their elements are decoded with
+// Decode or skipped with Skip.
+type StreamDecoder struct {
+	r      *bufio.Reader
+	handle *codec.MsgpackHandle
+	raw    []byte
+}
+
+// NewStreamDecoder returns a StreamDecoder decoding elements with our settings.
+func NewStreamDecoder(r io.Reader) *StreamDecoder {
+	return &StreamDecoder{r: bufio.NewReader(r), handle: CodecHandle}
+}
+
+// NewLenientStreamDecoder returns a StreamDecoder which ignores unknown fields
+// when decoding elements, like NewLenientDecoder.
+func NewLenientStreamDecoder(r io.Reader) *StreamDecoder {
+	return &StreamDecoder{r: bufio.NewReader(r), handle: LenientCodecHandle}
+}
+
+// ReadMapHeader reads the header of a map, returning its number of key/value
+// pairs. A nil is read as an empty map.
+func (d *StreamDecoder) ReadMapHeader() (int, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case b >= 0x80 && b <= 0x8f:
+		return int(b & 0x0f), nil
+	case b == 0xde:
+		return d.readLength(2)
+	case b == 0xdf:
+		return d.readLength(4)
+	case b == 0xc0:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("msgpack: expected map, found 0x%02x", b)
+	}
+}
+
+// ReadArrayHeader reads the header of an array, returning its number of
+// elements. A nil is read as an empty array.
+func (d *StreamDecoder) ReadArrayHeader() (int, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case b >= 0x90 && b <= 0x9f:
+		return int(b & 0x0f), nil
+	case b == 0xdc:
+		return d.readLength(2)
+	case b == 0xdd:
+		return d.readLength(4)
+	case b == 0xc0:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("msgpack: expected array, found 0x%02x", b)
+	}
+}
+
+// ReadString reads a string, e.g. a map key.
+func (d *StreamDecoder) ReadString() (string, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	var n int
+	switch {
+	case b >= 0xa0 && b <= 0xbf:
+		n = int(b & 0x1f)
+	case b == 0xd9:
+		n, err = d.readLength(1)
+	case b == 0xda:
+		n, err = d.readLength(2)
+	case b == 0xdb:
+		n, err = d.readLength(4)
+	default:
+		return "", fmt.Errorf("msgpack: expected string, found 0x%02x", b)
+	}
+	if err != nil {
+		return "", err
+	}
+	s := make([]byte, n)
+	_, err = io.ReadFull(d.r, s)
+	return string(s), err
+}
+
+// ReadRaw returns the encoding of the next element. The returned slice is
+// only valid until the next call on the decoder.
+func (d *StreamDecoder) ReadRaw() ([]byte, error) {
+	d.raw = d.raw[:0]
+	err := d.next(func(p []byte) { d.raw = append(d.raw, p...) })
+	return d.raw, err
+}
+
+// Skip skips the next element.
+func (d *StreamDecoder) Skip() error {
+	return d.next(nil)
+}
+
+// Decode decodes the next element into the object pointed to by objptr. The
+// decoders of NewStreamDecoder fail on unknown fields like Decode.
+func (d *StreamDecoder) Decode(objptr interface{}) error {
+	raw, err := d.ReadRaw()
+	if err != nil {
+		return err
+	}
+	return wrapUnknownField(codec.NewDecoderBytes(raw, d.handle).Decode(objptr))
+}
+
+func (d *StreamDecoder) readLength(size int) (int, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(d.r, buf[:size]); err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return int(buf[0]), nil
+	case 2:
+		return int(binary.BigEndian.Uint16(buf[:2])), nil
+	default:
+		return int(binary.BigEndian.Uint32(buf[:4])), nil
+	}
+}
+
+// next reads the next element, passing its bytes to emit if not nil.
+// Containers are read by counting the elements left to read rather than by
+// recursion, so that deeply nested input cannot exhaust the stack.
+func (d *StreamDecoder) next(emit func(p []byte)) error {
+	var head [9]byte
+	for remaining := 1; remaining > 0; remaining-- {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		head[0] = b
+
+		// size of the header after the first byte, the length of any payload
+		// and the number of elements the header adds
+		var extra, payload, elements int
+		switch {
+		case b <= 0x7f || b >= 0xe0 || b == 0xc0 || b == 0xc2 || b == 0xc3:
+		case b <= 0x8f:
+			elements = 2 * int(b&0x0f)
+		case b <= 0x9f:
+			elements = int(b & 0x0f)
+		case b <= 0xbf:
+			payload = int(b & 0x1f)
+		case b == 0xc4 || b == 0xd9:
+			extra = 1
+		case b == 0xc5 || b == 0xda || b == 0xdc || b == 0xde:
+			extra = 2
+		case b == 0xc6 || b == 0xdb || b == 0xdd || b == 0xdf:
+			extra = 4
+		case b == 0xc7:
+			extra = 2
+		case b == 0xc8:
+			extra = 3
+		case b == 0xc9:
+			extra = 5
+		case b == 0xca:
+			payload = 4
+		case b == 0xcb:
+			payload = 8
+		case b >= 0xcc && b <= 0xcf:
+			payload = 1 << (b - 0xcc)
+		case b >= 0xd0 && b <= 0xd3:
+			payload = 1 << (b - 0xd0)
+		case b >= 0xd4 && b <= 0xd8:
+			payload = 1 + 1<<(b-0xd4)
+		default:
+			return fmt.Errorf("msgpack: invalid byte 0x%02x", b)
+		}
+
+		if extra > 0 {
+			if _, err := io.ReadFull(d.r, head[1:1+extra]); err != nil {
+				return err
+			}
+			// the length precedes the type byte of ext formats
+			lengthSize := extra
+			if b >= 0xc7 && b <= 0xc9 {
+				lengthSize--
+			}
+			var length int
+			for _, lb := range head[1 : 1+lengthSize] {
+				length = length<<8 | int(lb)
+			}
+			switch b {
+			case 0xdc, 0xdd:
+				elements = length
+			case 0xde, 0xdf:
+				elements = 2 * length
+			default:
+				payload = length
+			}
+		}
+		if emit != nil {
+			emit(head[:1+extra])
+		}
+		if err := d.copyPayload(payload, emit); err != nil {
+			return err
+		}
+		remaining += elements
+	}
+	return nil
+}
+
+func (d *StreamDecoder) copyPayload(n int, emit func(p []byte)) error {
+	if emit == nil {
+		_, err := d.r.Discard(n)
+		return err
+	}
+	var buf [512]byte
+	for n > 0 {
+		chunk := buf[:]
+		if n < len(chunk) {
+			chunk = chunk[:n]
+		}
+		if _, err := io.ReadFull(d.r, chunk); err != nil {
+			return err
+		}
+		emit(chunk)
+		n -= len(chunk)
+	}
+	return nil
+}
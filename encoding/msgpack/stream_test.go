@@ -0,0 +1,88 @@
+package msgpack
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamDecoder(t *testing.T) {
+	type element struct {
+		Name   string            `codec:"name"`
+		Values []int64           `codec:"values"`
+		Data   []byte            `codec:"data"`
+		Nested map[string]uint64 `codec:"nested"`
+		Float  float64           `codec:"f"`
+	}
+	elements := []element{
+		{Name: "small", Values: []int64{-1, 1}},
+		{Name: strings.Repeat("long", 100), Values: []int64{-1 << 40, 1 << 40, 300}, Data: make([]byte, 70000)},
+		{Nested: map[string]uint64{"a": 1, "b": 1 << 63}, Float: 1.5},
+	}
+	payload := map[string]interface{}{
+		"elements": elements,
+		"skipped":  map[string]interface{}{"deep": [][]string{{"x"}, {"y", "z"}}},
+	}
+	dec := NewStreamDecoder(bytes.NewReader(Encode(payload)))
+
+	n, err := dec.ReadMapHeader()
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	key, err := dec.ReadString()
+	require.NoError(t, err)
+	require.Equal(t, "elements", key)
+	n, err = dec.ReadArrayHeader()
+	require.NoError(t, err)
+	require.Equal(t, len(elements), n)
+	for i := 0; i < n; i++ {
+		var decoded element
+		require.NoError(t, dec.Decode(&decoded))
+		require.Equal(t, elements[i], decoded)
+	}
+
+	key, err = dec.ReadString()
+	require.NoError(t, err)
+	require.Equal(t, "skipped", key)
+	require.NoError(t, dec.Skip())
+
+	_, err = dec.ReadRaw()
+	require.Error(t, err)
+}
+
+func TestStreamDecoderRaw(t *testing.T) {
+	values := []interface{}{nil, true, 1, -100, uint64(1) << 60, "str", []byte{1, 2, 3}, []interface{}{1, "a"}, 2.5}
+	var encoded []byte
+	for _, v := range values {
+		encoded = append(encoded, Encode(v)...)
+	}
+
+	dec := NewStreamDecoder(bytes.NewReader(encoded))
+	for _, v := range values {
+		raw, err := dec.ReadRaw()
+		require.NoError(t, err)
+		require.Equal(t, Encode(v), raw)
+	}
+}
+
+func TestStreamDecoderStrict(t *testing.T) {
+	encoded := Encode(object{Name: "name"})
+
+	var decoded subsetObject
+	require.Error(t, NewStreamDecoder(bytes.NewReader(encoded)).Decode(&decoded))
+	require.NoError(t, NewLenientStreamDecoder(bytes.NewReader(encoded)).Decode(&decoded))
+}
+
+func TestStreamDecoderInvalid(t *testing.T) {
+	_, err := NewStreamDecoder(bytes.NewReader([]byte{0xc1})).ReadRaw()
+	require.Error(t, err)
+
+	_, err = NewStreamDecoder(bytes.NewReader(Encode("str"))).ReadMapHeader()
+	require.Error(t, err)
+
+	// truncated array
+	_, err = NewStreamDecoder(bytes.NewReader([]byte{0x92, 0x01})).ReadRaw()
+	require.Error(t, err)
+}
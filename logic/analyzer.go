@@ -0,0 +1,249 @@
+package logic
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// ExpensiveOpCost is the cost from which Analyze reports the use of an opcode.
+const ExpensiveOpCost = 100
+
+// opCosts are the costs of the opcodes which do not cost 1, from version 2.
+var opCosts = map[string]int{
+	"sha256":             35,
+	"keccak256":          130,
+	"sha512_256":         45,
+	"sha3_256":           130,
+	"ed25519verify":      1900,
+	"ed25519verify_bare": 1900,
+	"ecdsa_pk_recover":   2000,
+	"vrf_verify":         5700,
+	"divmodw":            20,
+	"expw":               10,
+	"bsqrt":              40,
+	"b+":                 10,
+	"b-":                 10,
+	"b/":                 20,
+	"b*":                 20,
+	"b%":                 20,
+	"b|":                 6,
+	"b&":                 6,
+	"b^":                 6,
+	"b~":                 4,
+	"base64_decode":      1,
+	"json_ref":           25,
+}
+
+// v1OpCosts are the costs of the hash opcodes in version 1.
+var v1OpCosts = map[string]int{
+	"sha256":     7,
+	"keccak256":  26,
+	"sha512_256": 9,
+}
+
+// fieldOpCosts are the costs of the opcodes whose cost depends on their field.
+var fieldOpCosts = map[string]map[string]int{
+	"ecdsa_verify":        {"Secp256k1": 1700, "Secp256r1": 2500},
+	"ecdsa_pk_decompress": {"Secp256k1": 650, "Secp256r1": 2400},
+	"ec_add":              {"BN254g1": 125, "BN254g2": 170, "BLS12_381g1": 205, "BLS12_381g2": 290},
+	"ec_scalar_mul":       {"BN254g1": 1810, "BN254g2": 3430, "BLS12_381g1": 2950, "BLS12_381g2": 6530},
+	"ec_pairing_check":    {"BN254g1": 8000, "BN254g2": 8000, "BLS12_381g1": 13000, "BLS12_381g2": 13000},
+	"ec_multi_scalar_mul": {"BN254g1": 3600, "BN254g2": 7200, "BLS12_381g1": 6500, "BLS12_381g2": 14850},
+	"ec_subgroup_check":   {"BN254g1": 20, "BN254g2": 3100, "BLS12_381g1": 1850, "BLS12_381g2": 2340},
+	"ec_map_to":           {"BN254g1": 630, "BN254g2": 3300, "BLS12_381g1": 1950, "BLS12_381g2": 8150},
+	"mimc":                {"BN254Mp110": 560, "BLS12_381Mp111": 560},
+}
+
+// dynamicCostOps are the opcodes costing more with larger arguments, whose
+// costs above are their lowest.
+var dynamicCostOps = map[string]bool{
+	"base64_decode":       true,
+	"json_ref":            true,
+	"ec_pairing_check":    true,
+	"ec_multi_scalar_mul": true,
+	"mimc":                true,
+}
+
+// opCost returns the cost of an instruction in a program of version version,
+// and whether it is the lowest of a cost depending on the arguments.
+func opCost(inst disassembled, version int) (int, bool) {
+	name := inst.spec.name
+	dynamic := dynamicCostOps[name]
+	if costs, ok := fieldOpCosts[name]; ok && len(inst.fields) > 0 {
+		return costs[inst.fields[0]], dynamic
+	}
+	if cost, ok := v1OpCosts[name]; ok && version == 1 {
+		return cost, dynamic
+	}
+	if cost, ok := opCosts[name]; ok {
+		return cost, dynamic
+	}
+	return 1, dynamic
+}
+
+// ExpensiveOp is a use of an opcode costing at least ExpensiveOpCost, or
+// whose cost depends on its arguments.
+type ExpensiveOp struct {
+	Pc   int
+	Name string
+	Cost int
+	// Dynamic whether the opcode costs more with larger arguments, Cost
+	// being its lowest cost
+	Dynamic bool
+}
+
+// Analysis is the static analysis of a program, made without running it.
+type Analysis struct {
+	Version int
+	Size    int
+
+	// MaxCost is the highest opcode cost of the execution paths of the
+	// program, following both sides of each branch and counting the cost of
+	// each subroutine call. It is a lower bound of the worst case when
+	// Bounded is false or DynamicCost is true.
+	MaxCost int
+	// Bounded is false when the program can loop or recurse, so that its
+	// cost depends on its inputs.
+	Bounded bool
+	// DynamicCost whether MaxCost counts opcodes whose cost depends on
+	// their arguments at their lowest cost.
+	DynamicCost bool
+
+	ExpensiveOps []ExpensiveOp
+}
+
+// Analyze walks the bytecode of a program and reports its worst-case opcode
+// cost, size and use of expensive opcodes, so that budget overruns can be
+// caught before deploying it. See Analysis.CheckLogicSig and
+// Analysis.CheckApp to compare it to the limits of the consensus protocol.
+func Analyze(program []byte) (Analysis, error) {
+	version, instructions, err := decodeProgram(program)
+	if err != nil {
+		return Analysis{}, err
+	}
+	a := Analysis{Version: version, Size: len(program), Bounded: true}
+
+	w := costWalker{
+		instructions: instructions,
+		byPc:         map[int]int{},
+		end:          len(program),
+		version:      version,
+		costs:        map[int]int{},
+		visiting:     map[int]bool{},
+	}
+	for i, inst := range instructions {
+		w.byPc[inst.pc] = i
+		cost, dynamic := opCost(inst, version)
+		if cost >= ExpensiveOpCost || dynamic {
+			a.ExpensiveOps = append(a.ExpensiveOps, ExpensiveOp{Pc: inst.pc, Name: inst.spec.name, Cost: cost, Dynamic: dynamic})
+		}
+		a.DynamicCost = a.DynamicCost || dynamic
+	}
+	for _, inst := range instructions {
+		for _, target := range inst.targets {
+			if _, ok := w.byPc[target]; !ok && target != w.end {
+				return Analysis{}, fmt.Errorf("%d: branch target %d is not the start of an instruction", inst.pc, target)
+			}
+		}
+	}
+
+	if len(instructions) > 0 {
+		a.MaxCost = w.cost(instructions[0].pc)
+	}
+	a.Bounded = !w.unbounded
+	return a, nil
+}
+
+// costWalker computes the highest cost of the paths from each instruction to
+// the end of the program or of its subroutine.
+type costWalker struct {
+	instructions []disassembled
+	// byPc are the indexes of the instructions by program counter
+	byPc      map[int]int
+	end       int
+	version   int
+	costs     map[int]int
+	visiting  map[int]bool
+	unbounded bool
+}
+
+func (w *costWalker) cost(pc int) int {
+	if pc == w.end {
+		return 0
+	}
+	if cost, ok := w.costs[pc]; ok {
+		return cost
+	}
+	if w.visiting[pc] {
+		// a loop, or a recursive subroutine
+		w.unbounded = true
+		return 0
+	}
+	w.visiting[pc] = true
+	defer delete(w.visiting, pc)
+
+	i := w.byPc[pc]
+	inst := w.instructions[i]
+	next := w.end
+	if i+1 < len(w.instructions) {
+		next = w.instructions[i+1].pc
+	}
+
+	cost, _ := opCost(inst, w.version)
+	switch inst.spec.name {
+	case "return", "err", "retsub":
+	case "b":
+		cost += w.cost(inst.targets[0])
+	case "callsub":
+		cost += w.cost(inst.targets[0]) + w.cost(next)
+	default:
+		highest := w.cost(next)
+		for _, target := range inst.targets {
+			if c := w.cost(target); c > highest {
+				highest = c
+			}
+		}
+		cost += highest
+	}
+	w.costs[pc] = cost
+	return cost
+}
+
+// CheckLogicSig returns the limits of params that the program exceeds as a
+// logic signature.
+func (a Analysis) CheckLogicSig(params types.ConsensusParams) []error {
+	var errs []error
+	if a.Size > params.LogicSigMaxSize {
+		errs = append(errs, fmt.Errorf("program size %d exceeds the logic signature limit of %d bytes", a.Size, params.LogicSigMaxSize))
+	}
+	if !a.Bounded {
+		errs = append(errs, fmt.Errorf("program cost is unbounded as it can loop or recurse"))
+	}
+	if uint64(a.MaxCost) > params.LogicSigMaxCost {
+		errs = append(errs, fmt.Errorf("program cost %d exceeds the logic signature budget of %d", a.MaxCost, params.LogicSigMaxCost))
+	}
+	return errs
+}
+
+// CheckApp returns the limits of params that the program exceeds as the
+// approval or clear state program of an app with extraPages extra program
+// pages. A cost above the budget of one app call is reported along with the
+// number of app calls of a group needed to pool enough budget.
+func (a Analysis) CheckApp(params types.ConsensusParams, extraPages int) []error {
+	var errs []error
+	if extraPages > params.MaxExtraAppProgramPages {
+		errs = append(errs, fmt.Errorf("%d extra program pages exceed the limit of %d", extraPages, params.MaxExtraAppProgramPages))
+	}
+	if limit := (1 + extraPages) * params.MaxAppProgramLen; a.Size > limit {
+		errs = append(errs, fmt.Errorf("program size %d exceeds the limit of %d bytes with %d extra pages", a.Size, limit, extraPages))
+	}
+	if !a.Bounded {
+		errs = append(errs, fmt.Errorf("program cost is unbounded as it can loop or recurse"))
+	}
+	if a.MaxCost > params.MaxAppProgramCost {
+		calls := (a.MaxCost + params.MaxAppProgramCost - 1) / params.MaxAppProgramCost
+		errs = append(errs, fmt.Errorf("program cost %d exceeds the app call budget of %d, pooling budget needs %d app calls", a.MaxCost, params.MaxAppProgramCost, calls))
+	}
+	return errs
+}
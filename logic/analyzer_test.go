@@ -0,0 +1,89 @@
+package logic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/stretchr/testify/require"
+)
+
+func analyzeSource(t *testing.T, source string) Analysis {
+	program, err := Assemble(source)
+	require.NoError(t, err)
+	analysis, err := Analyze(program)
+	require.NoError(t, err)
+	require.Equal(t, len(program), analysis.Size)
+	return analysis
+}
+
+func TestAnalyzeCost(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		cost   int
+	}{
+		{"straight", "#pragma version 6\nint 1\nreturn", 2},
+		{"branches", "#pragma version 6\nint 1\nbnz a\nbyte 0x00\nsha256\npop\na: int 1", 41},
+		{"v1 hash cost", "#pragma version 1\narg 0\nsha256", 8},
+		{"subroutine", "#pragma version 8\ncallsub f\ncallsub f\nint 1\nreturn\nf: sha256\nretsub", 76},
+		{"field cost", "#pragma version 7\necdsa_verify Secp256r1", 2500},
+		{"switch", "#pragma version 8\nint 0\nswitch a b\nerr\na: sha3_256\nb: int 1", 133},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			analysis := analyzeSource(t, test.source)
+			require.True(t, analysis.Bounded)
+			require.Equal(t, test.cost, analysis.MaxCost)
+		})
+	}
+}
+
+func TestAnalyzeExpensiveOps(t *testing.T) {
+	analysis := analyzeSource(t, "#pragma version 7\narg 0\narg 1\narg 2\ned25519verify\nbyte \"{}\"\njson_ref JSONObject")
+	require.Equal(t, []ExpensiveOp{
+		{Pc: 4, Name: "ed25519verify", Cost: 1900},
+		{Pc: 9, Name: "json_ref", Cost: 25, Dynamic: true},
+	}, analysis.ExpensiveOps)
+	require.True(t, analysis.DynamicCost)
+	require.Equal(t, 1929, analysis.MaxCost)
+
+	params := types.Consensus[types.ConsensusCurrentVersion]
+	require.Empty(t, analysis.CheckLogicSig(params))
+	errs := analysis.CheckApp(params, 0)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), "pooling budget needs 3 app calls")
+}
+
+func TestAnalyzeUnbounded(t *testing.T) {
+	analysis := analyzeSource(t, "#pragma version 4\nloop: int 1\nbnz loop\nint 1")
+	require.False(t, analysis.Bounded)
+
+	params := types.Consensus[types.ConsensusCurrentVersion]
+	errs := analysis.CheckLogicSig(params)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), "unbounded")
+
+	analysis = analyzeSource(t, "#pragma version 4\nf: callsub f")
+	require.False(t, analysis.Bounded)
+}
+
+func TestAnalyzeSize(t *testing.T) {
+	source := "#pragma version 8\nbyte 0x" + strings.Repeat("00", 2100) + "\npop\nint 1"
+	analysis := analyzeSource(t, source)
+
+	params := types.Consensus[types.ConsensusCurrentVersion]
+	errs := analysis.CheckLogicSig(params)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), "exceeds the logic signature limit of 1000 bytes")
+	require.Len(t, analysis.CheckApp(params, 0), 1)
+	require.Empty(t, analysis.CheckApp(params, 1))
+	require.Len(t, analysis.CheckApp(params, 4), 1)
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	_, err := Analyze(nil)
+	require.Error(t, err)
+	_, err = Analyze([]byte{0x04, 0x42, 0x00, 0x01, 0x81, 0x01})
+	require.Error(t, err)
+}
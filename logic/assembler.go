@@ -0,0 +1,792 @@
+package logic
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// assemblerDefaultVersion is the version of programs without a
+// "#pragma version" line.
+const assemblerDefaultVersion = 1
+
+// optimizeConstantsVersion is the first version where constants used once
+// are pushed with pushint and pushbytes, and the others are ordered by
+// frequency in the constant blocks.
+const optimizeConstantsVersion = 4
+
+// backBranchVersion is the first version allowing backward branches.
+const backBranchVersion = 4
+
+// Assemble assembles TEAL source into a program, producing the same bytes as
+// algod's /v2/teal/compile endpoint, so that programs can be compiled without
+// a node. Programs up to MaxVersion are supported, without macros. Errors are
+// prefixed with the line they occurred on.
+func Assemble(source string) ([]byte, error) {
+	a := assembler{version: assemblerDefaultVersion, labels: map[string]int{}}
+	if err := a.parse(source); err != nil {
+		return nil, err
+	}
+	a.assignConstants()
+	return a.emit()
+}
+
+// statement is an instruction or pseudo-op with its arguments.
+type statement struct {
+	line int
+	args []string
+}
+
+// constRef is an int or byte pseudo-op, whose encoding depends on the other
+// constants of the program.
+type constRef struct {
+	isBytes bool
+	value   uint64
+	bytes   []byte
+	// template is the name of the template variable giving the value
+	template string
+}
+
+func (r constRef) key() string {
+	if r.template != "" {
+		return "t" + r.template
+	}
+	if r.isBytes {
+		return "b" + string(r.bytes)
+	}
+	return "i" + strconv.FormatUint(r.value, 10)
+}
+
+// instruction is an assembled instruction, whose label offsets are resolved
+// once the position of every label is known.
+type instruction struct {
+	line   int
+	spec   *opSpec
+	imm    []byte
+	labels []string
+	ref    *constRef
+	// size is the size of the instruction, known once constants are assigned
+	size int
+	// labelDefs are the labels pointing at the instruction
+	labelDefs []string
+}
+
+type assembler struct {
+	version       int
+	versionSet    bool
+	instructions  []instruction
+	pendingLabels []string
+	labels        map[string]int
+
+	// manualIntc and manualBytec are the values of the intcblock and
+	// bytecblock of the source, if it has them.
+	manualIntc, manualBytec       []interface{}
+	hasManualIntc, hasManualBytec bool
+
+	// intc and bytec are the constant blocks prepended to the program.
+	intc  []uint64
+	bytec [][]byte
+	// refEncodings are how each constant is referenced.
+	refEncodings map[string]refEncoding
+
+	// templates are the kinds of the template variables of the source, or
+	// nil when the source is not a template.
+	templates map[string]templateKind
+	// intcTemplates and bytecTemplates are the template variables of the
+	// constant blocks, by index.
+	intcTemplates, bytecTemplates map[int]string
+	// codeStart is the offset of the code following the constant blocks.
+	codeStart int
+}
+
+type refEncoding struct {
+	push  bool
+	index int
+}
+
+func (a *assembler) parse(source string) error {
+	for i, line := range strings.Split(source, "\n") {
+		lineNum := i + 1
+		statements, err := tokenize(line)
+		if err != nil {
+			return fmt.Errorf("%d: %w", lineNum, err)
+		}
+		for _, args := range statements {
+			if err := a.statement(statement{line: lineNum, args: args}); err != nil {
+				return fmt.Errorf("%d: %w", lineNum, err)
+			}
+		}
+	}
+	if len(a.templates) > 0 && (a.hasManualIntc || a.hasManualBytec) {
+		return fmt.Errorf("template variables cannot be used with intcblock or bytecblock")
+	}
+	if len(a.pendingLabels) > 0 {
+		// labels at the end of the program point past its last instruction
+		a.instructions = append(a.instructions, instruction{labelDefs: a.pendingLabels})
+		a.pendingLabels = nil
+	}
+	return nil
+}
+
+// tokenize splits a line into statements separated by semicolons, without
+// comments. Quoted strings are kept as single tokens with their quotes.
+func tokenize(line string) ([][]string, error) {
+	spans, err := tokenizeSpans(line)
+	if err != nil {
+		return nil, err
+	}
+	statements := make([][]string, len(spans))
+	for i, statement := range spans {
+		for _, span := range statement {
+			statements[i] = append(statements[i], line[span.start:span.end])
+		}
+	}
+	return statements, nil
+}
+
+// tokenSpan is the position of a token in its line.
+type tokenSpan struct {
+	start, end int
+}
+
+// tokenizeSpans returns the positions of the tokens tokenize returns.
+func tokenizeSpans(line string) ([][]tokenSpan, error) {
+	var statements [][]tokenSpan
+	var tokens []tokenSpan
+	start := -1
+	endToken := func(i int) {
+		if start >= 0 {
+			tokens = append(tokens, tokenSpan{start, i})
+			start = -1
+		}
+	}
+	endStatement := func() {
+		if len(tokens) > 0 {
+			statements = append(statements, tokens)
+			tokens = nil
+		}
+	}
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '"':
+			if start < 0 {
+				start = i
+			}
+			i++
+			for ; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(line) {
+				return nil, fmt.Errorf("unterminated string %s", line[start:])
+			}
+		case c == '/' && i+1 < len(line) && line[i+1] == '/':
+			endToken(i)
+			endStatement()
+			return statements, nil
+		case c == ';':
+			endToken(i)
+			endStatement()
+		case c == ' ' || c == '\t' || c == '\r':
+			endToken(i)
+		default:
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+	endToken(len(line))
+	endStatement()
+	return statements, nil
+}
+
+func (a *assembler) statement(s statement) error {
+	name := s.args[0]
+	if strings.HasPrefix(name, "#pragma") {
+		return a.pragma(s.args)
+	}
+	if strings.HasPrefix(name, "#") {
+		return fmt.Errorf("%s is not supported", name)
+	}
+	if strings.HasSuffix(name, ":") && !strings.HasPrefix(name, "\"") {
+		label := strings.TrimSuffix(name, ":")
+		if _, ok := a.labels[label]; ok {
+			return fmt.Errorf("duplicate label %s", label)
+		}
+		a.labels[label] = -1
+		a.pendingLabels = append(a.pendingLabels, label)
+		if len(s.args) == 1 {
+			return nil
+		}
+		s.args = s.args[1:]
+		name = s.args[0]
+	}
+
+	inst, err := a.instruction(name, s.args[1:])
+	if err != nil {
+		return err
+	}
+	if inst.spec != nil && inst.spec.version > a.version {
+		return fmt.Errorf("%s opcode was introduced in v%d", inst.spec.name, inst.spec.version)
+	}
+	inst.line = s.line
+	inst.labelDefs = a.pendingLabels
+	a.pendingLabels = nil
+	a.instructions = append(a.instructions, inst)
+	return nil
+}
+
+func (a *assembler) pragma(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("empty pragma")
+	}
+	switch args[1] {
+	case "version":
+		if len(args) != 3 {
+			return fmt.Errorf("no version value")
+		}
+		if len(a.instructions) > 0 || a.versionSet {
+			return fmt.Errorf("#pragma version is only allowed before instructions")
+		}
+		version, err := strconv.ParseUint(args[2], 0, 64)
+		if err != nil {
+			return err
+		}
+		if version < 1 || version > MaxVersion {
+			return fmt.Errorf("unsupported version: %d", version)
+		}
+		a.version = int(version)
+		a.versionSet = true
+		return nil
+	case "typetrack":
+		return nil
+	default:
+		return fmt.Errorf("unsupported pragma directive: %s", args[1])
+	}
+}
+
+// instruction assembles a statement, resolving pseudo-ops.
+func (a *assembler) instruction(name string, args []string) (instruction, error) {
+	if a.templates != nil && len(args) == 1 && strings.HasPrefix(args[0], templatePrefix) {
+		return a.templateInstruction(name, args[0])
+	}
+	switch name {
+	case "int":
+		if len(args) != 1 {
+			return instruction{}, fmt.Errorf("int needs one argument")
+		}
+		value, err := parseInt(args[0])
+		if err != nil {
+			return instruction{}, err
+		}
+		return instruction{ref: &constRef{value: value}}, nil
+	case "byte":
+		value, err := parseBytesArg(args)
+		if err != nil {
+			return instruction{}, err
+		}
+		return instruction{ref: &constRef{isBytes: true, bytes: value}}, nil
+	case "addr":
+		if len(args) != 1 {
+			return instruction{}, fmt.Errorf("addr needs one argument")
+		}
+		addr, err := types.DecodeAddress(args[0])
+		if err != nil {
+			return instruction{}, err
+		}
+		return instruction{ref: &constRef{isBytes: true, bytes: addr[:]}}, nil
+	case "method":
+		if len(args) != 1 {
+			return instruction{}, fmt.Errorf("method needs one argument")
+		}
+		sig, err := parseStringLiteral(args[0])
+		if err != nil {
+			return instruction{}, err
+		}
+		hash := sha512.Sum512_256(sig)
+		return instruction{ref: &constRef{isBytes: true, bytes: hash[:4]}}, nil
+	case "arg":
+		if len(args) == 1 {
+			if n, err := strconv.ParseUint(args[0], 0, 64); err == nil && n < 4 {
+				return instruction{spec: opsByName[fmt.Sprintf("arg_%d", n)]}, nil
+			}
+		}
+	case "txn", "gtxns", "itxn":
+		if len(args) == 2 {
+			name += "a"
+		}
+	case "gtxn", "gitxn":
+		if len(args) == 3 {
+			name += "a"
+		}
+	case "extract", "substring":
+		if len(args) == 0 {
+			name += "3"
+		}
+	case "replace":
+		switch len(args) {
+		case 0:
+			name = "replace3"
+		case 1:
+			name = "replace2"
+		}
+	}
+
+	spec, ok := opsByName[name]
+	if !ok {
+		return instruction{}, fmt.Errorf("unknown opcode: %s", name)
+	}
+	inst := instruction{spec: spec}
+	var err error
+	switch spec.name {
+	case "intcblock":
+		a.hasManualIntc = true
+		for _, arg := range args {
+			value, err := parseInt(arg)
+			if err != nil {
+				return instruction{}, err
+			}
+			a.manualIntc = append(a.manualIntc, value)
+		}
+	case "bytecblock":
+		a.hasManualBytec = true
+		values, err := parseBytesList(args)
+		if err != nil {
+			return instruction{}, err
+		}
+		for _, value := range values {
+			a.manualBytec = append(a.manualBytec, value)
+		}
+	}
+
+	for i, imm := range spec.imms {
+		if imm.kind == immLabels || imm.kind == immInts || imm.kind == immBytess || imm.kind == immBytes {
+			// these take the remaining arguments
+			inst.imm, inst.labels, err = a.variableImmediate(imm, args[i:], inst.imm)
+			if err != nil {
+				return instruction{}, fmt.Errorf("%s: %w", spec.name, err)
+			}
+			args = args[:i]
+			break
+		}
+		if i >= len(args) {
+			return instruction{}, fmt.Errorf("%s expects %d immediate arguments", spec.name, len(spec.imms))
+		}
+		switch imm.kind {
+		case immByte:
+			n, err := strconv.ParseUint(args[i], 0, 64)
+			if err != nil || n > math.MaxUint8 {
+				return instruction{}, fmt.Errorf("%s: invalid immediate %s", spec.name, args[i])
+			}
+			inst.imm = append(inst.imm, byte(n))
+		case immInt:
+			value, err := parseInt(args[i])
+			if err != nil {
+				return instruction{}, fmt.Errorf("%s: %w", spec.name, err)
+			}
+			inst.imm = appendUvarint(inst.imm, value)
+		case immInt8:
+			n, err := strconv.ParseInt(args[i], 0, 64)
+			if err != nil || n < math.MinInt8 || n > math.MaxInt8 {
+				return instruction{}, fmt.Errorf("%s: invalid immediate %s", spec.name, args[i])
+			}
+			inst.imm = append(inst.imm, byte(int8(n)))
+		case immField:
+			field, ok := imm.group.byName(args[i])
+			if !ok {
+				return instruction{}, fmt.Errorf("%s unknown field: %s", spec.name, args[i])
+			}
+			if field.version > a.version {
+				return instruction{}, fmt.Errorf("%s %s field was introduced in v%d", spec.name, field.name, field.version)
+			}
+			if imm.group == txnFields {
+				indexed := strings.HasSuffix(spec.name, "a") || strings.HasSuffix(spec.name, "as")
+				if field.array && !indexed && spec.name != "itxn_field" {
+					return instruction{}, fmt.Errorf("%s %s field needs an index", spec.name, field.name)
+				}
+				if !field.array && indexed {
+					return instruction{}, fmt.Errorf("%s %s field is not an array", spec.name, field.name)
+				}
+			}
+			inst.imm = append(inst.imm, field.value)
+		case immLabel:
+			inst.labels = append(inst.labels, args[i])
+		}
+	}
+	if len(args) > len(spec.imms) {
+		return instruction{}, fmt.Errorf("%s expects %d immediate arguments", spec.name, len(spec.imms))
+	}
+	return inst, nil
+}
+
+// variableImmediate encodes the immediates taking the remaining arguments.
+func (a *assembler) variableImmediate(imm immediate, args []string, buf []byte) ([]byte, []string, error) {
+	switch imm.kind {
+	case immLabels:
+		if len(args) > math.MaxUint8 {
+			return nil, nil, fmt.Errorf("too many labels")
+		}
+		return append(buf, byte(len(args))), args, nil
+	case immInts:
+		buf = appendUvarint(buf, uint64(len(args)))
+		for _, arg := range args {
+			value, err := parseInt(arg)
+			if err != nil {
+				return nil, nil, err
+			}
+			buf = appendUvarint(buf, value)
+		}
+		return buf, nil, nil
+	case immBytes:
+		value, err := parseBytesArg(args)
+		if err != nil {
+			return nil, nil, err
+		}
+		buf = appendUvarint(buf, uint64(len(value)))
+		return append(buf, value...), nil, nil
+	case immBytess:
+		values, err := parseBytesList(args)
+		if err != nil {
+			return nil, nil, err
+		}
+		buf = appendUvarint(buf, uint64(len(values)))
+		for _, value := range values {
+			buf = appendUvarint(buf, uint64(len(value)))
+			buf = append(buf, value...)
+		}
+		return buf, nil, nil
+	}
+	return nil, nil, fmt.Errorf("unexpected immediate")
+}
+
+// assignConstants decides which int and byte constants go into the constant
+// blocks and which are pushed, as algod does.
+func (a *assembler) assignConstants() {
+	a.refEncodings = map[string]refEncoding{}
+	a.intcTemplates = map[int]string{}
+	a.bytecTemplates = map[int]string{}
+	a.assignKind(false)
+	a.assignKind(true)
+}
+
+func (a *assembler) assignKind(isBytes bool) {
+	type constFreq struct {
+		ref  constRef
+		freq int
+	}
+	var freqs []constFreq
+	index := map[string]int{}
+	for _, inst := range a.instructions {
+		if inst.ref == nil || inst.ref.isBytes != isBytes {
+			continue
+		}
+		key := inst.ref.key()
+		if i, ok := index[key]; ok {
+			freqs[i].freq++
+			continue
+		}
+		index[key] = len(freqs)
+		freqs = append(freqs, constFreq{ref: *inst.ref, freq: 1})
+	}
+
+	manual, hasManual := a.manualIntc, a.hasManualIntc
+	if isBytes {
+		manual, hasManual = a.manualBytec, a.hasManualBytec
+	}
+	if hasManual {
+		for _, f := range freqs {
+			enc := refEncoding{push: true}
+			if a.version < optimizeConstantsVersion {
+				enc = refEncoding{index: -1}
+				for i, value := range manual {
+					if f.ref.matches(value) {
+						enc.index = i
+						break
+					}
+				}
+			}
+			a.refEncodings[f.ref.key()] = enc
+		}
+		return
+	}
+
+	if a.version >= optimizeConstantsVersion {
+		// most frequent constants first, in order of first use for equal
+		// frequencies
+		sort.SliceStable(freqs, func(i, j int) bool { return freqs[i].freq > freqs[j].freq })
+	}
+	for _, f := range freqs {
+		// template variables always go in the blocks, so that their values
+		// can be substituted without moving the code
+		if a.version >= optimizeConstantsVersion && f.freq == 1 && f.ref.template == "" {
+			a.refEncodings[f.ref.key()] = refEncoding{push: true}
+			continue
+		}
+		if isBytes {
+			if f.ref.template != "" {
+				a.bytecTemplates[len(a.bytec)] = f.ref.template
+			}
+			a.refEncodings[f.ref.key()] = refEncoding{index: len(a.bytec)}
+			a.bytec = append(a.bytec, f.ref.bytes)
+		} else {
+			if f.ref.template != "" {
+				a.intcTemplates[len(a.intc)] = f.ref.template
+			}
+			a.refEncodings[f.ref.key()] = refEncoding{index: len(a.intc)}
+			a.intc = append(a.intc, f.ref.value)
+		}
+	}
+}
+
+func (r constRef) matches(value interface{}) bool {
+	switch v := value.(type) {
+	case uint64:
+		return !r.isBytes && r.value == v
+	case []byte:
+		return r.isBytes && bytes.Equal(r.bytes, v)
+	}
+	return false
+}
+
+// resolveRef returns the instruction referencing a constant.
+func (a *assembler) resolveRef(inst *instruction) error {
+	ref := inst.ref
+	enc := a.refEncodings[ref.key()]
+	prefix := "intc"
+	if ref.isBytes {
+		prefix = "bytec"
+	}
+	switch {
+	case enc.push && ref.isBytes:
+		inst.spec = opsByName["pushbytes"]
+		inst.imm = append(appendUvarint(nil, uint64(len(ref.bytes))), ref.bytes...)
+	case enc.push:
+		inst.spec = opsByName["pushint"]
+		inst.imm = appendUvarint(nil, ref.value)
+	case enc.index < 0:
+		return fmt.Errorf("%s not in %sblock", ref.key()[1:], prefix)
+	case enc.index < 4:
+		inst.spec = opsByName[fmt.Sprintf("%s_%d", prefix, enc.index)]
+	case enc.index <= math.MaxUint8:
+		inst.spec = opsByName[prefix]
+		inst.imm = []byte{byte(enc.index)}
+	default:
+		return fmt.Errorf("too many constants for %sblock", prefix)
+	}
+	return nil
+}
+
+func (a *assembler) emit() ([]byte, error) {
+	program := appendUvarint(nil, uint64(a.version))
+	if len(a.intc) > 0 {
+		program = append(program, opsByName["intcblock"].opcode)
+		program = appendUvarint(program, uint64(len(a.intc)))
+		for _, value := range a.intc {
+			program = appendUvarint(program, value)
+		}
+	}
+	if len(a.bytec) > 0 {
+		program = append(program, opsByName["bytecblock"].opcode)
+		program = appendUvarint(program, uint64(len(a.bytec)))
+		for _, value := range a.bytec {
+			program = appendUvarint(program, uint64(len(value)))
+			program = append(program, value...)
+		}
+	}
+	a.codeStart = len(program)
+
+	// size instructions and place labels
+	pc := len(program)
+	for i := range a.instructions {
+		inst := &a.instructions[i]
+		for _, label := range inst.labelDefs {
+			a.labels[label] = pc
+		}
+		if inst.ref != nil {
+			if err := a.resolveRef(inst); err != nil {
+				return nil, fmt.Errorf("%d: %w", inst.line, err)
+			}
+		}
+		if inst.spec == nil {
+			continue
+		}
+		inst.size = 1 + len(inst.imm) + 2*len(inst.labels)
+		pc += inst.size
+	}
+
+	for _, inst := range a.instructions {
+		if inst.spec == nil {
+			continue
+		}
+		program = append(program, inst.spec.opcode)
+		program = append(program, inst.imm...)
+		end := len(program) + 2*len(inst.labels)
+		for _, label := range inst.labels {
+			target, ok := a.labels[label]
+			if !ok {
+				return nil, fmt.Errorf("%d: reference to undefined label %#v", inst.line, label)
+			}
+			offset := target - end
+			if offset < 0 && a.version < backBranchVersion {
+				return nil, fmt.Errorf("%d: label %#v is a back reference, back jump support was introduced in v%d", inst.line, label, backBranchVersion)
+			}
+			if offset < math.MinInt16 || offset > math.MaxInt16 {
+				return nil, fmt.Errorf("%d: label %#v is too far away", inst.line, label)
+			}
+			program = append(program, byte(uint16(offset)>>8), byte(uint16(offset)))
+		}
+	}
+	return program, nil
+}
+
+func appendUvarint(buf []byte, value uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], value)
+	return append(buf, tmp[:n]...)
+}
+
+// parseInt parses the argument of int, pushint and intcblock: a number in
+// decimal, hexadecimal, octal or binary, or the name of a transaction type or
+// OnCompletion value.
+func parseInt(arg string) (uint64, error) {
+	if value, ok := namedInts[arg]; ok {
+		return value, nil
+	}
+	value, err := strconv.ParseUint(arg, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse %#v as integer", arg)
+	}
+	return value, nil
+}
+
+// parseBytesArg parses the arguments of byte and pushbytes, which must form a
+// single value.
+func parseBytesArg(args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing byte argument")
+	}
+	value, consumed, err := parseBytes(args)
+	if err != nil {
+		return nil, err
+	}
+	if consumed != len(args) {
+		return nil, fmt.Errorf("extra arguments after byte value: %s", strings.Join(args[consumed:], " "))
+	}
+	return value, nil
+}
+
+// parseBytesList parses a list of byte values, as in bytecblock.
+func parseBytesList(args []string) ([][]byte, error) {
+	var values [][]byte
+	for len(args) > 0 {
+		value, consumed, err := parseBytes(args)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		args = args[consumed:]
+	}
+	return values, nil
+}
+
+// parseBytes parses a byte value from the start of args and returns the
+// number of arguments it took: base64 or b64 followed by base64, base32 or
+// b32 followed by base32, base64(...), b64(...), base32(...) or b32(...),
+// 0x followed by hex, or a quoted string.
+func parseBytes(args []string) ([]byte, int, error) {
+	arg := args[0]
+	switch arg {
+	case "base64", "b64", "base32", "b32":
+		if len(args) < 2 {
+			return nil, 0, fmt.Errorf("%s needs an argument", arg)
+		}
+		value, err := decodeBase(arg, args[1])
+		return value, 2, err
+	}
+	for _, prefix := range []string{"base64(", "b64(", "base32(", "b32("} {
+		if strings.HasPrefix(arg, prefix) {
+			if !strings.HasSuffix(arg, ")") {
+				return nil, 0, fmt.Errorf("%s has no closing parenthesis", arg)
+			}
+			value, err := decodeBase(prefix[:len(prefix)-1], arg[len(prefix):len(arg)-1])
+			return value, 1, err
+		}
+	}
+	if strings.HasPrefix(arg, "0x") {
+		value, err := hex.DecodeString(arg[2:])
+		return value, 1, err
+	}
+	if strings.HasPrefix(arg, "\"") {
+		value, err := parseStringLiteral(arg)
+		return value, 1, err
+	}
+	return nil, 0, fmt.Errorf("byte arg did not parse: %s", arg)
+}
+
+func decodeBase(encoding string, s string) ([]byte, error) {
+	if encoding == "base64" || encoding == "b64" {
+		return base64.StdEncoding.DecodeString(s)
+	}
+	if strings.HasSuffix(s, "=") {
+		return base32.StdEncoding.DecodeString(s)
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+}
+
+// parseStringLiteral parses a quoted string with the escapes \n, \r, \t,
+// \\, \" and \xHH.
+func parseStringLiteral(arg string) ([]byte, error) {
+	if len(arg) < 2 || arg[0] != '"' || arg[len(arg)-1] != '"' {
+		return nil, fmt.Errorf("%s is not a quoted string", arg)
+	}
+	s := arg[1 : len(arg)-1]
+	var out []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			out = append(out, c)
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return nil, fmt.Errorf("escape at end of string %s", arg)
+		}
+		switch s[i] {
+		case 'n':
+			out = append(out, '\n')
+		case 'r':
+			out = append(out, '\r')
+		case 't':
+			out = append(out, '\t')
+		case '\\':
+			out = append(out, '\\')
+		case '"':
+			out = append(out, '"')
+		case 'x':
+			if i+2 >= len(s) {
+				return nil, fmt.Errorf("invalid \\x escape in %s", arg)
+			}
+			b, err := hex.DecodeString(s[i+1 : i+3])
+			if err != nil {
+				return nil, fmt.Errorf("invalid \\x escape in %s", arg)
+			}
+			out = append(out, b[0])
+			i += 2
+		default:
+			return nil, fmt.Errorf("invalid escape \\%c in %s", s[i], arg)
+		}
+	}
+	return out, nil
+}
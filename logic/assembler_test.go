@@ -0,0 +1,75 @@
+package logic
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxVersion(t *testing.T) {
+	require.GreaterOrEqual(t, MaxVersion, types.Consensus[types.ConsensusCurrentVersion].LogicSigVersion)
+}
+
+func TestAssemble(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{"default version", "int 1", "0120010122"},
+		{"constant block", "#pragma version 2\nint 1", "0220010122"},
+		{"push", "#pragma version 5\nint 1\nreturn", "05810143"},
+		{"statements", "#pragma version 10\nint 1; return // done", "0a810143"},
+		{"frequency order", "#pragma version 4\nint 5\nint 7\nint 7\n+", "042001078105222208"},
+		{
+			"byte forms",
+			"byte 0x6869\nbyte \"hi\"\nbyte base64 aGk=\nbyte b32(NBUQ)\nbyte base32 NBUQ====",
+			"0126010268692828282828",
+		},
+		{"escapes", "#pragma version 5\nbyte \"a\\x00\\n\\\"\"", "05800461000a22"},
+		{"named int", "#pragma version 5\nint appl\nint OptIn", "0581068101"},
+		{"method", "#pragma version 6\nmethod \"add(uint64,uint64)uint128\"", "0680048aa3b61f"},
+		{"arg", "#pragma version 5\narg 1\narg 4", "052e2c04"},
+		{"txna", "#pragma version 2\ntxn Accounts 1\ngtxn 0 ApplicationArgs 2", "02361c0137001a02"},
+		{"extract", "#pragma version 5\nextract\nextract 1 2", "0558570102"},
+		{"forward branch", "#pragma version 2\nb end\nend:", "02420000"},
+		{"backward branch", "#pragma version 4\nloop: int 1\nbnz loop", "04810140fffb"},
+		{"switch", "#pragma version 8\nint 0\nswitch a b\na:\nb: int 1", "0881008d02000000008101"},
+		{"manual block", "#pragma version 4\nintcblock 1 2\nint 2", "04200201028102"},
+		{"manual block before v4", "#pragma version 3\nintcblock 1 2\nint 2", "032002010223"},
+		{"global", "#pragma version 6\nglobal CallerApplicationID", "06320d"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			program, err := Assemble(test.source)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, hex.EncodeToString(program))
+		})
+	}
+}
+
+func TestAssembleErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		err    string
+	}{
+		{"#pragma version 5\nfoo", "2: unknown opcode: foo"},
+		{"#pragma version 3\ncallsub f\nf:", "2: callsub opcode was introduced in v4"},
+		{"#pragma version 5\nglobal CallerApplicationID", "2: global CallerApplicationID field was introduced in v6"},
+		{"#pragma version 2\ntxn Accounts", "2: txn Accounts field needs an index"},
+		{"#pragma version 2\nloop: int 1\nbnz loop", "3: label \"loop\" is a back reference"},
+		{"#pragma version 4\nb nowhere", "2: reference to undefined label \"nowhere\""},
+		{"#pragma version 4\na:\na:", "3: duplicate label a"},
+		{"#pragma version 12", "1: unsupported version: 12"},
+		{"int 1\n#pragma version 2", "2: #pragma version is only allowed before instructions"},
+		{"byte \"abc", "1: unterminated string"},
+		{"#pragma version 3\nintcblock 1\nint 2", "3: 2 not in intcblock"},
+	}
+	for _, test := range tests {
+		_, err := Assemble(test.source)
+		require.Error(t, err, test.source)
+		require.Contains(t, err.Error(), test.err)
+	}
+}
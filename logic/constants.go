@@ -0,0 +1,128 @@
+package logic
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// ProgramAddress returns the escrow address of a compiled program, the
+// address of the contract account its logic signatures sign for, without
+// calling a node.
+func ProgramAddress(program []byte) types.Address {
+	return crypto.AddressFromProgram(program)
+}
+
+// ProgramConstant is a constant of an intcblock or bytecblock of a program.
+type ProgramConstant struct {
+	// Offset and Length locate the encoding of the constant in the program:
+	// the varuint of an int, or the varuint length and content of bytes
+	Offset int
+	Length int
+	// Index is the index of the constant in its block
+	Index   int
+	IsBytes bool
+	Int     uint64
+	Bytes   []byte
+}
+
+// ProgramConstants returns the constants of the intcblock and bytecblock
+// instructions of a program, in program order, e.g. to read the parameters a
+// contract account factory stamped into it.
+func ProgramConstants(program []byte) ([]ProgramConstant, error) {
+	_, instructions, err := decodeProgram(program)
+	if err != nil {
+		return nil, err
+	}
+	var constants []ProgramConstant
+	for _, inst := range instructions {
+		isBytes := inst.spec.name == "bytecblock"
+		if !isBytes && inst.spec.name != "intcblock" {
+			continue
+		}
+		d := disassembler{program: program, pc: inst.pc + 1}
+		count, err := d.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < int(count); i++ {
+			c := ProgramConstant{Offset: d.pc, Index: i, IsBytes: isBytes}
+			if isBytes {
+				c.Bytes, err = d.readBytes()
+			} else {
+				c.Int, err = d.readUvarint()
+			}
+			if err != nil {
+				return nil, err
+			}
+			c.Length = d.pc - c.Offset
+			constants = append(constants, c)
+		}
+	}
+	return constants, nil
+}
+
+// PatchIntConstant returns a copy of program with the intcblock constant at
+// offset, the Offset of a ProgramConstant, set to value.
+func PatchIntConstant(program []byte, offset int, value uint64) ([]byte, error) {
+	return patchConstant(program, offset, false, appendUvarint(nil, value))
+}
+
+// PatchBytesConstant returns a copy of program with the bytecblock constant
+// at offset, the Offset of a ProgramConstant, set to value.
+//
+// As with PatchIntConstant, the program changes size when the encoding of
+// the new value has a different length than the old one. This is only
+// allowed when no branch of the program jumps over the constant, as is the
+// case for the constant blocks starting programs.
+func PatchBytesConstant(program []byte, offset int, value []byte) ([]byte, error) {
+	return patchConstant(program, offset, true, append(appendUvarint(nil, uint64(len(value))), value...))
+}
+
+func patchConstant(program []byte, offset int, isBytes bool, encoded []byte) ([]byte, error) {
+	constants, err := ProgramConstants(program)
+	if err != nil {
+		return nil, err
+	}
+	var constant *ProgramConstant
+	for i := range constants {
+		if constants[i].Offset == offset && constants[i].IsBytes == isBytes {
+			constant = &constants[i]
+		}
+	}
+	if constant == nil {
+		kind := "intcblock"
+		if isBytes {
+			kind = "bytecblock"
+		}
+		return nil, fmt.Errorf("no %s constant at offset %d", kind, offset)
+	}
+
+	if len(encoded) != constant.Length {
+		_, instructions, err := decodeProgram(program)
+		if err != nil {
+			return nil, err
+		}
+		for i, inst := range instructions {
+			end := len(program)
+			if i+1 < len(instructions) {
+				end = instructions[i+1].pc
+			}
+			for _, target := range inst.targets {
+				low, high := end, target
+				if target < end {
+					low, high = target, end
+				}
+				if offset >= low && offset < high {
+					return nil, fmt.Errorf("the branch at %d jumps over the constant at offset %d, whose length would change", inst.pc, offset)
+				}
+			}
+		}
+	}
+
+	patched := make([]byte, 0, len(program)-constant.Length+len(encoded))
+	patched = append(patched, program[:offset]...)
+	patched = append(patched, encoded...)
+	return append(patched, program[offset+constant.Length:]...), nil
+}
@@ -0,0 +1,58 @@
+package logic
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgramAddress(t *testing.T) {
+	program := []byte{0x05, 0x81, 0x01, 0x43}
+	require.Equal(t, crypto.AddressFromProgram(program), ProgramAddress(program))
+}
+
+func TestProgramConstants(t *testing.T) {
+	program, err := Assemble("#pragma version 3\nint 1000\nint 5\nbyte \"hi\"\nbyte 0x\nb end\nend:")
+	require.NoError(t, err)
+	require.Equal(t, "032002e8070526020268690022232829420000", hex.EncodeToString(program))
+
+	constants, err := ProgramConstants(program)
+	require.NoError(t, err)
+	require.Equal(t, []ProgramConstant{
+		{Offset: 3, Length: 2, Index: 0, Int: 1000},
+		{Offset: 5, Length: 1, Index: 1, Int: 5},
+		{Offset: 8, Length: 3, Index: 0, IsBytes: true, Bytes: []byte("hi")},
+		{Offset: 11, Length: 1, Index: 1, IsBytes: true, Bytes: []byte{}},
+	}, constants)
+
+	patched, err := PatchIntConstant(program, 3, 1<<20)
+	require.NoError(t, err)
+	expected, err := Assemble("#pragma version 3\nint 1048576\nint 5\nbyte \"hi\"\nbyte 0x\nb end\nend:")
+	require.NoError(t, err)
+	require.Equal(t, expected, patched)
+
+	patched, err = PatchBytesConstant(program, 8, []byte("hello"))
+	require.NoError(t, err)
+	expected, err = Assemble("#pragma version 3\nint 1000\nint 5\nbyte \"hello\"\nbyte 0x\nb end\nend:")
+	require.NoError(t, err)
+	require.Equal(t, expected, patched)
+
+	_, err = PatchBytesConstant(program, 3, []byte("hello"))
+	require.EqualError(t, err, "no bytecblock constant at offset 3")
+}
+
+func TestPatchConstantOverBranch(t *testing.T) {
+	program, err := Assemble("#pragma version 4\nb skip\nintcblock 7\nskip:\nintc_0")
+	require.NoError(t, err)
+	constants, err := ProgramConstants(program)
+	require.NoError(t, err)
+	require.Len(t, constants, 1)
+
+	_, err = PatchIntConstant(program, constants[0].Offset, 1000)
+	require.Error(t, err)
+	patched, err := PatchIntConstant(program, constants[0].Offset, 8)
+	require.NoError(t, err)
+	require.Equal(t, byte(8), patched[constants[0].Offset])
+}
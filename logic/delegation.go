@@ -0,0 +1,166 @@
+package logic
+
+import "fmt"
+
+// Severity is how dangerous a DelegationFinding is.
+type Severity int
+
+const (
+	// SeverityWarning findings let the signature be used in ways the account
+	// may not intend, such as paying high fees.
+	SeverityWarning Severity = iota
+	// SeverityCritical findings let the signature be used to take over or
+	// empty the account.
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	if s == SeverityCritical {
+		return "critical"
+	}
+	return "warning"
+}
+
+// The checks of CheckDelegation.
+const (
+	DelegationCheckFee            = "fee"
+	DelegationCheckRekeyTo        = "rekey-to"
+	DelegationCheckCloseRemainder = "close-remainder-to"
+	DelegationCheckAssetCloseTo   = "asset-close-to"
+	DelegationCheckUnconditional  = "unconditional"
+)
+
+// DelegationFinding is a dangerous pattern of a program used as a delegated
+// logic signature, which anyone holding the signature can exploit.
+type DelegationFinding struct {
+	// Check is the pattern, one of the DelegationCheck constants
+	Check    string
+	Severity Severity
+	Message  string
+}
+
+// delegationFieldChecks are the transaction fields a delegated logic signature
+// must constrain.
+var delegationFieldChecks = []struct {
+	field    string
+	check    string
+	severity Severity
+	message  string
+}{
+	{"Fee", DelegationCheckFee, SeverityWarning,
+		"the fee is not bounded, so the account can be drained through transaction fees"},
+	{"RekeyTo", DelegationCheckRekeyTo, SeverityCritical,
+		"RekeyTo is not checked, so the account can be rekeyed to another account"},
+	{"CloseRemainderTo", DelegationCheckCloseRemainder, SeverityCritical,
+		"CloseRemainderTo is not checked, so the account can be closed and its balance sent away"},
+	{"AssetCloseTo", DelegationCheckAssetCloseTo, SeverityCritical,
+		"AssetCloseTo is not checked, so asset holdings can be closed out and sent away"},
+}
+
+// CheckDelegation inspects a program to be used as a delegated logic
+// signature and reports the dangerous patterns it finds, for wallets to warn
+// about before signing it: an unbounded fee, and RekeyTo, CloseRemainderTo and
+// AssetCloseTo not being checked. A field counts as checked when the program
+// compares it with ==, or bounds the fee from above with <, <=, > or >=, using
+// the value of txn next to the comparison and a constant of the program: the
+// zero address or an address constant for the address fields, and an integer
+// constant or the minimum fee for the fee. The checks are heuristics, which
+// do not follow the branches of the program.
+func CheckDelegation(program []byte) ([]DelegationFinding, error) {
+	_, instructions, err := decodeProgram(program)
+	if err != nil {
+		return nil, err
+	}
+
+	readsTxn := false
+	for _, inst := range instructions {
+		if isTxnRead(inst, "") {
+			readsTxn = true
+			break
+		}
+	}
+	if !readsTxn {
+		return []DelegationFinding{{
+			Check:    DelegationCheckUnconditional,
+			Severity: SeverityCritical,
+			Message:  "the program does not read the transaction, so it approves any transaction from the account it is run for",
+		}}, nil
+	}
+
+	var findings []DelegationFinding
+	for _, check := range delegationFieldChecks {
+		if !constrainsField(instructions, check.field) {
+			findings = append(findings, DelegationFinding{Check: check.check, Severity: check.severity, Message: check.message})
+		}
+	}
+	return findings, nil
+}
+
+// isTxnRead returns whether inst reads the field of the current transaction,
+// or any field when field is empty.
+func isTxnRead(inst disassembled, field string) bool {
+	if inst.spec.name != "txn" || len(inst.fields) == 0 {
+		return false
+	}
+	return field == "" || inst.fields[0] == field
+}
+
+// constrainsField returns whether the program compares the field of the
+// current transaction in a way that constrains it.
+func constrainsField(instructions []disassembled, field string) bool {
+	for i := 2; i < len(instructions); i++ {
+		inst := instructions[i]
+		first, second := instructions[i-2], instructions[i-1]
+		fieldFirst := isTxnRead(first, field)
+		other := first
+		if fieldFirst {
+			other = second
+		} else if !isTxnRead(second, field) {
+			continue
+		}
+		// comparing with a value the sender of the transaction controls, as
+		// txn RekeyTo; arg 0; == does, constrains nothing
+		if !isConstantFor(other, field) {
+			continue
+		}
+		switch inst.spec.name {
+		case "==":
+			return true
+		case "<", "<=":
+			// txn Fee; x; < bounds the fee from above
+			if field == "Fee" && fieldFirst {
+				return true
+			}
+		case ">", ">=":
+			if field == "Fee" && !fieldFirst {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isConstantFor returns whether inst pushes a value fixed by the program to
+// compare the field with: an integer constant or the minimum fee for Fee, and
+// a bytes constant or the zero address for the address fields.
+func isConstantFor(inst disassembled, field string) bool {
+	switch inst.spec.name {
+	case "intc", "intc_0", "intc_1", "intc_2", "intc_3", "pushint":
+		return field == "Fee"
+	case "bytec", "bytec_0", "bytec_1", "bytec_2", "bytec_3", "pushbytes":
+		return field != "Fee"
+	case "global":
+		if len(inst.fields) == 0 {
+			return false
+		}
+		if field == "Fee" {
+			return inst.fields[0] == "MinTxnFee"
+		}
+		return inst.fields[0] == "ZeroAddress"
+	}
+	return false
+}
+
+func (f DelegationFinding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.Check, f.Message)
+}
@@ -0,0 +1,67 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func delegationFindings(t *testing.T, source string) []string {
+	program, err := Assemble(source)
+	require.NoError(t, err)
+	findings, err := CheckDelegation(program)
+	require.NoError(t, err)
+	var checks []string
+	for _, finding := range findings {
+		checks = append(checks, finding.Check)
+	}
+	return checks
+}
+
+func TestCheckDelegation(t *testing.T) {
+	safe := `#pragma version 5
+txn Fee
+int 1000
+<=
+global ZeroAddress
+txn RekeyTo
+==
+&&
+txn CloseRemainderTo
+global ZeroAddress
+==
+&&
+txn AssetCloseTo
+global ZeroAddress
+==
+&&`
+	require.Empty(t, delegationFindings(t, safe))
+
+	require.Equal(t, []string{DelegationCheckFee, DelegationCheckRekeyTo, DelegationCheckCloseRemainder, DelegationCheckAssetCloseTo},
+		delegationFindings(t, "#pragma version 5\ntxn Amount\nint 10\n<="))
+	require.Equal(t, []string{DelegationCheckUnconditional}, delegationFindings(t, "#pragma version 5\nint 1"))
+
+	// a lower bound does not constrain the fee, nor != the rekey address
+	require.Equal(t, []string{DelegationCheckFee, DelegationCheckRekeyTo, DelegationCheckCloseRemainder, DelegationCheckAssetCloseTo},
+		delegationFindings(t, "#pragma version 5\ntxn Fee\nint 1000\n>=\ntxn RekeyTo\nglobal ZeroAddress\n!=\n&&"))
+	require.Equal(t, []string{DelegationCheckRekeyTo, DelegationCheckCloseRemainder, DelegationCheckAssetCloseTo},
+		delegationFindings(t, "#pragma version 5\nint 1000\ntxn Fee\n>="))
+
+	// comparisons with values the sender of the transaction controls
+	require.Equal(t, []string{DelegationCheckFee, DelegationCheckRekeyTo, DelegationCheckCloseRemainder, DelegationCheckAssetCloseTo},
+		delegationFindings(t, "#pragma version 5\ntxn RekeyTo\narg 0\n==\ntxn CloseRemainderTo\ntxn Receiver\n==\n&&\ntxn Fee\ntxn Amount\n<=\n&&"))
+	require.Equal(t, []string{DelegationCheckFee, DelegationCheckRekeyTo, DelegationCheckAssetCloseTo},
+		delegationFindings(t, "#pragma version 5\ntxn Receiver\ntxn CloseRemainderTo\n==\ntxn CloseRemainderTo\naddr AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAY5HFKQ\n==\n&&"))
+	require.Equal(t, []string{DelegationCheckRekeyTo, DelegationCheckCloseRemainder, DelegationCheckAssetCloseTo},
+		delegationFindings(t, "#pragma version 5\ntxn Fee\nglobal MinTxnFee\n=="))
+
+	program, err := Assemble("#pragma version 5\nint 1")
+	require.NoError(t, err)
+	findings, err := CheckDelegation(program)
+	require.NoError(t, err)
+	require.Equal(t, SeverityCritical, findings[0].Severity)
+	require.Contains(t, findings[0].String(), "critical: unconditional: ")
+
+	_, err = CheckDelegation(nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,334 @@
+package logic
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Disassemble converts a program back to TEAL source, one instruction per
+// line after its "#pragma version" line, without calling a node. Branch
+// targets are given labels, and references to the intcblock and bytecblock
+// are followed by a comment with the value they load. Assembling the result
+// gives back the program.
+func Disassemble(program []byte) (string, error) {
+	version, instructions, err := decodeProgram(program)
+	if err != nil {
+		return "", err
+	}
+
+	// name labels in program order
+	boundaries := map[int]bool{len(program): true}
+	targets := map[int]bool{}
+	for _, inst := range instructions {
+		boundaries[inst.pc] = true
+		for _, target := range inst.targets {
+			targets[target] = true
+		}
+	}
+	var sorted []int
+	for target := range targets {
+		if !boundaries[target] {
+			return "", fmt.Errorf("branch target %d is not the start of an instruction", target)
+		}
+		sorted = append(sorted, target)
+	}
+	sort.Ints(sorted)
+	labels := map[int]string{}
+	for i, target := range sorted {
+		labels[target] = "label" + strconv.Itoa(i+1)
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "#pragma version %d\n", version)
+	for _, inst := range instructions {
+		if label, ok := labels[inst.pc]; ok {
+			fmt.Fprintf(&out, "%s:\n", label)
+		}
+		out.WriteString(inst.text)
+		for _, target := range inst.targets {
+			out.WriteString(" " + labels[target])
+		}
+		if inst.comment != "" {
+			out.WriteString(" // " + inst.comment)
+		}
+		out.WriteString("\n")
+	}
+	if label, ok := labels[len(program)]; ok {
+		fmt.Fprintf(&out, "%s:\n", label)
+	}
+	return out.String(), nil
+}
+
+// decodeProgram returns the version and instructions of a program.
+func decodeProgram(program []byte) (int, []disassembled, error) {
+	version, n := binary.Uvarint(program)
+	if n <= 0 {
+		return 0, nil, fmt.Errorf("invalid version")
+	}
+	if version < 1 || version > MaxVersion {
+		return 0, nil, fmt.Errorf("unsupported version: %d", version)
+	}
+	d := disassembler{program: program, version: int(version), pc: n}
+
+	var instructions []disassembled
+	for d.pc < len(program) {
+		inst, err := d.next()
+		if err != nil {
+			return 0, nil, fmt.Errorf("%d: %w", d.pc, err)
+		}
+		instructions = append(instructions, inst)
+	}
+	return int(version), instructions, nil
+}
+
+// disassembled is a decoded instruction.
+type disassembled struct {
+	pc   int
+	spec *opSpec
+	// fields are the names of the field immediates
+	fields []string
+	// ints are the values of the other immediates but labels, int8 being
+	// kept as their byte, and bytes the values of the bytes immediates
+	ints  []uint64
+	bytes [][]byte
+	// text is the instruction without its branch targets
+	text    string
+	targets []int
+	comment string
+}
+
+type disassembler struct {
+	program []byte
+	version int
+	pc      int
+	intc    []uint64
+	bytec   [][]byte
+}
+
+func (d *disassembler) next() (disassembled, error) {
+	inst := disassembled{pc: d.pc}
+	spec := opsByOpcode[d.program[d.pc]]
+	if spec == nil {
+		return inst, fmt.Errorf("invalid opcode %#x", d.program[d.pc])
+	}
+	if spec.version > d.version {
+		return inst, fmt.Errorf("%s opcode was introduced in v%d", spec.name, spec.version)
+	}
+	d.pc++
+	inst.spec = spec
+
+	parts := []string{spec.name}
+	for _, imm := range spec.imms {
+		switch imm.kind {
+		case immByte:
+			b, err := d.readByte()
+			if err != nil {
+				return inst, err
+			}
+			parts = append(parts, strconv.Itoa(int(b)))
+			inst.ints = append(inst.ints, uint64(b))
+		case immInt8:
+			b, err := d.readByte()
+			if err != nil {
+				return inst, err
+			}
+			parts = append(parts, strconv.Itoa(int(int8(b))))
+			inst.ints = append(inst.ints, uint64(b))
+		case immField:
+			b, err := d.readByte()
+			if err != nil {
+				return inst, err
+			}
+			field, ok := imm.group.byValue(b)
+			if !ok {
+				return inst, fmt.Errorf("%s: invalid %s field %d", spec.name, imm.group.name, b)
+			}
+			if field.version > d.version {
+				return inst, fmt.Errorf("%s %s field was introduced in v%d", spec.name, field.name, field.version)
+			}
+			parts = append(parts, field.name)
+			inst.fields = append(inst.fields, field.name)
+		case immLabel:
+			target, err := d.readTarget()
+			if err != nil {
+				return inst, err
+			}
+			inst.targets = append(inst.targets, target)
+		case immLabels:
+			count, err := d.readByte()
+			if err != nil {
+				return inst, err
+			}
+			end := d.pc + 2*int(count)
+			for i := 0; i < int(count); i++ {
+				target, err := d.readTargetFrom(end)
+				if err != nil {
+					return inst, err
+				}
+				inst.targets = append(inst.targets, target)
+			}
+		case immInt:
+			value, err := d.readUvarint()
+			if err != nil {
+				return inst, err
+			}
+			parts = append(parts, strconv.FormatUint(value, 10))
+			inst.ints = append(inst.ints, value)
+		case immInts:
+			values, err := d.readInts()
+			if err != nil {
+				return inst, err
+			}
+			for _, value := range values {
+				parts = append(parts, strconv.FormatUint(value, 10))
+			}
+			inst.ints = append(inst.ints, values...)
+			if spec.name == "intcblock" {
+				d.intc = values
+			}
+		case immBytes:
+			value, err := d.readBytes()
+			if err != nil {
+				return inst, err
+			}
+			parts = append(parts, "0x"+hex.EncodeToString(value))
+			inst.bytes = append(inst.bytes, value)
+			inst.comment = bytesComment(value)
+		case immBytess:
+			count, err := d.readUvarint()
+			if err != nil {
+				return inst, err
+			}
+			var values [][]byte
+			for i := uint64(0); i < count; i++ {
+				value, err := d.readBytes()
+				if err != nil {
+					return inst, err
+				}
+				values = append(values, value)
+				parts = append(parts, "0x"+hex.EncodeToString(value))
+			}
+			inst.bytes = append(inst.bytes, values...)
+			if spec.name == "bytecblock" {
+				d.bytec = values
+			}
+		}
+	}
+	inst.text = strings.Join(parts, " ")
+
+	switch spec.name {
+	case "intc", "intc_0", "intc_1", "intc_2", "intc_3":
+		if i := d.constIndex(spec, parts); i < len(d.intc) {
+			inst.comment = strconv.FormatUint(d.intc[i], 10)
+		}
+	case "bytec", "bytec_0", "bytec_1", "bytec_2", "bytec_3":
+		if i := d.constIndex(spec, parts); i < len(d.bytec) {
+			inst.comment = bytesComment(d.bytec[i])
+			if inst.comment == "" {
+				inst.comment = "0x" + hex.EncodeToString(d.bytec[i])
+			}
+		}
+	}
+	return inst, nil
+}
+
+// constIndex returns the index of the constant loaded by an intc or bytec
+// instruction.
+func (d *disassembler) constIndex(spec *opSpec, parts []string) int {
+	if i := strings.IndexByte(spec.name, '_'); i >= 0 {
+		index, _ := strconv.Atoi(spec.name[i+1:])
+		return index
+	}
+	index, _ := strconv.Atoi(parts[1])
+	return index
+}
+
+// bytesComment returns a byte constant as a quoted string when it is
+// printable, and "" otherwise.
+func bytesComment(value []byte) string {
+	if len(value) == 0 {
+		return ""
+	}
+	for _, r := range string(value) {
+		if r == unicode.ReplacementChar || !unicode.IsPrint(r) {
+			return ""
+		}
+	}
+	return strconv.Quote(string(value))
+}
+
+func (d *disassembler) readByte() (byte, error) {
+	if d.pc >= len(d.program) {
+		return 0, fmt.Errorf("unexpected end of program")
+	}
+	b := d.program[d.pc]
+	d.pc++
+	return b, nil
+}
+
+func (d *disassembler) readUvarint() (uint64, error) {
+	value, n := binary.Uvarint(d.program[d.pc:])
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid varuint")
+	}
+	d.pc += n
+	return value, nil
+}
+
+func (d *disassembler) readInts() ([]uint64, error) {
+	count, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	var values []uint64
+	for i := uint64(0); i < count; i++ {
+		value, err := d.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func (d *disassembler) readBytes() ([]byte, error) {
+	length, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if length > uint64(len(d.program)-d.pc) {
+		return nil, fmt.Errorf("unexpected end of program")
+	}
+	value := d.program[d.pc : d.pc+int(length)]
+	d.pc += int(length)
+	return value, nil
+}
+
+// readTarget reads the offset of a branch and returns its target.
+func (d *disassembler) readTarget() (int, error) {
+	return d.readTargetFrom(d.pc + 2)
+}
+
+// readTargetFrom reads a branch offset relative to end, the end of the
+// instruction.
+func (d *disassembler) readTargetFrom(end int) (int, error) {
+	if d.pc+2 > len(d.program) {
+		return 0, fmt.Errorf("unexpected end of program")
+	}
+	raw := binary.BigEndian.Uint16(d.program[d.pc:])
+	d.pc += 2
+	offset := int(raw)
+	if d.version >= backBranchVersion {
+		offset = int(int16(raw))
+	}
+	target := end + offset
+	if target < 0 || target > len(d.program) {
+		return 0, fmt.Errorf("branch target %d is outside the program", target)
+	}
+	return target, nil
+}
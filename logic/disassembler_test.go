@@ -0,0 +1,86 @@
+package logic
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisassemble(t *testing.T) {
+	program, err := hex.DecodeString("0420020701260102686931182212400005282381054389")
+	require.NoError(t, err)
+
+	source, err := Disassemble(program)
+	require.NoError(t, err)
+	require.Equal(t, `#pragma version 4
+intcblock 7 1
+bytecblock 0x6869
+txn ApplicationID
+intc_0 // 7
+==
+bnz label1
+bytec_0 // "hi"
+intc_1 // 1
+pushint 5
+return
+label1:
+retsub
+`, source)
+
+	reassembled, err := Assemble(source)
+	require.NoError(t, err)
+	require.Equal(t, program, reassembled)
+}
+
+func TestDisassembleRoundTrip(t *testing.T) {
+	sources := []string{
+		"#pragma version 8\nint 0\nswitch a b\na: frame_dig -1\nb: byte \"\\x00\\x01\"\nreturn",
+		"#pragma version 2\ntxn Accounts 1\nb end\nend:",
+		"#pragma version 10\nglobal GenesisHash\npushbytess \"a\" \"b\"\nec_add BN254g1",
+		"#pragma version 11\nonline_stake\nglobal PayoutsEnabled\nbyte 0x01\nmimc BN254Mp110\ntxn Sender\nvoter_params_get VoterBalance",
+	}
+	files, err := filepath.Glob("../_examples/*/*.teal")
+	require.NoError(t, err)
+	for _, file := range files {
+		source, err := os.ReadFile(file)
+		require.NoError(t, err)
+		sources = append(sources, string(source))
+	}
+
+	for _, source := range sources {
+		program, err := Assemble(source)
+		require.NoError(t, err, source)
+		disassembled, err := Disassemble(program)
+		require.NoError(t, err, base64.StdEncoding.EncodeToString(program))
+		reassembled, err := Assemble(disassembled)
+		require.NoError(t, err, disassembled)
+		require.Equal(t, program, reassembled, disassembled)
+	}
+}
+
+func TestDisassembleErrors(t *testing.T) {
+	tests := []struct {
+		program string
+		err     string
+	}{
+		{"", "invalid version"},
+		{"0c", "unsupported version: 12"},
+		{"02ff", "invalid opcode 0xff"},
+		{"0281", "pushint opcode was introduced in v3"},
+		{"0231", "unexpected end of program"},
+		{"0231c8", "invalid txn field 200"},
+		{"04420005", "outside the program"},
+		{"044200018101", "not the start of an instruction"},
+	}
+	for _, test := range tests {
+		program, err := hex.DecodeString(test.program)
+		require.NoError(t, err)
+		_, err = Disassemble(program)
+		require.Error(t, err, test.program)
+		require.Contains(t, err.Error(), test.err)
+	}
+}
@@ -0,0 +1,1419 @@
+package logic
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+const (
+	// maxStackDepth is the maximum height of the stack.
+	maxStackDepth = 1000
+	// maxStringSize is the maximum length of a bytes value.
+	maxStringSize = 4096
+	// maxByteMathSize is the maximum length of the arguments of byte math.
+	maxByteMathSize = 64
+	// maxCallDepth is the maximum depth of subroutine calls.
+	maxCallDepth = 8
+	// programPageSize is the size of the pages of ApprovalProgramPages and
+	// ClearStateProgramPages.
+	programPageSize = 4096
+)
+
+// StackValue is a value of the stack or scratch space of the AVM: bytes when
+// IsBytes, and a uint64 otherwise.
+type StackValue struct {
+	IsBytes bool
+	Uint    uint64
+	Bytes   []byte
+}
+
+func (v StackValue) String() string {
+	if v.IsBytes {
+		return "0x" + hex.EncodeToString(v.Bytes)
+	}
+	return fmt.Sprint(v.Uint)
+}
+
+// EvalParams are the inputs of EvalLogicSig.
+type EvalParams struct {
+	// TxnGroup is the group of the transaction approved by the logic
+	// signature, which is TxnGroup[GroupIndex]. A transaction alone is a
+	// group of one.
+	TxnGroup   []types.Transaction
+	GroupIndex int
+	// Args are the arguments of the logic signature
+	Args [][]byte
+	// Proto are the consensus parameters to evaluate with, those of
+	// types.ConsensusCurrentVersion when nil
+	Proto *types.ConsensusParams
+	// Trace whether to record the instructions evaluated in EvalResult.Trace
+	Trace bool
+}
+
+// EvalStep is an instruction evaluated by EvalLogicSig, with the stack after
+// it.
+type EvalStep struct {
+	Pc    int
+	Text  string
+	Stack []StackValue
+}
+
+// EvalResult is the result of evaluating a logic signature.
+type EvalResult struct {
+	// Pass whether the program approved the transaction
+	Pass bool
+	Cost int
+	// Stack and Scratch are the stack and scratch space at the end of the
+	// evaluation, or where it failed
+	Stack   []StackValue
+	Scratch [256]StackValue
+	Trace   []EvalStep
+}
+
+// EvalError is an error of a program during evaluation. As the logic errors of
+// algod, its message ends with the program counter, so that it can be located
+// with SourceMap.MapError.
+type EvalError struct {
+	Pc  int
+	Err error
+}
+
+func (e *EvalError) Error() string {
+	return fmt.Sprintf("%v pc=%d", e.Err, e.Pc)
+}
+
+func (e *EvalError) Unwrap() error {
+	return e.Err
+}
+
+// EvalLogicSig runs a program as the logic signature of a transaction, without
+// calling a node, so that smart signatures can be unit tested in Go. The
+// program does not pass when it ends with 0 on the stack, and fails with an
+// *EvalError when it errs, such as with the err opcode, a failed assert, or
+// exceeding the cost budget of a single logic signature.
+//
+// The evaluator follows the rules of the AVM for logic signatures, with the
+// exception of the opcodes needing the ledger or a cryptographic primitive
+// the SDK lacks: ecdsa, vrf_verify, elliptic curves, mimc, json_ref, block and
+// the payouts globals, which fail as unsupported.
+func EvalLogicSig(program []byte, params EvalParams) (EvalResult, error) {
+	proto := types.Consensus[types.ConsensusCurrentVersion]
+	if params.Proto != nil {
+		proto = *params.Proto
+	}
+	if params.GroupIndex < 0 || params.GroupIndex >= len(params.TxnGroup) {
+		return EvalResult{}, fmt.Errorf("group index %d is outside the group of %d transactions", params.GroupIndex, len(params.TxnGroup))
+	}
+	size := len(program)
+	for _, arg := range params.Args {
+		size += len(arg)
+	}
+	if size > proto.LogicSigMaxSize {
+		return EvalResult{}, fmt.Errorf("logic signature of %d bytes exceeds the maximum size %d", size, proto.LogicSigMaxSize)
+	}
+	version, instructions, err := decodeProgram(program)
+	if err != nil {
+		return EvalResult{}, err
+	}
+	if version > proto.LogicSigVersion {
+		return EvalResult{}, fmt.Errorf("program version %d is higher than the highest version %d of the consensus protocol", version, proto.LogicSigVersion)
+	}
+
+	e := &evaluator{
+		params:       params,
+		proto:        proto,
+		program:      program,
+		version:      version,
+		instructions: instructions,
+		index:        map[int]int{len(program): len(instructions)},
+	}
+	for i, inst := range instructions {
+		e.index[inst.pc] = i
+	}
+	err = e.run()
+	result := EvalResult{Cost: e.cost, Stack: e.stack, Scratch: e.scratch, Trace: e.trace}
+	if err != nil {
+		return result, err
+	}
+	if len(e.stack) != 1 {
+		return result, &EvalError{Pc: len(program), Err: fmt.Errorf("stack len is %d instead of 1", len(e.stack))}
+	}
+	if e.stack[0].IsBytes {
+		return result, &EvalError{Pc: len(program), Err: fmt.Errorf("stack finished with bytes not int")}
+	}
+	result.Pass = e.stack[0].Uint != 0
+	return result, nil
+}
+
+// evalFrame is a subroutine call.
+type evalFrame struct {
+	// next is the index of the instruction following the callsub
+	next   int
+	height int
+	// proto whether the subroutine declared its arguments and return values
+	proto   bool
+	args    int
+	returns int
+}
+
+type evaluator struct {
+	params       EvalParams
+	proto        types.ConsensusParams
+	program      []byte
+	version      int
+	instructions []disassembled
+	// index is the index of the instruction at each pc
+	index map[int]int
+
+	stack   []StackValue
+	scratch [256]StackValue
+	intc    []uint64
+	bytec   [][]byte
+	frames  []evalFrame
+	cost    int
+	trace   []EvalStep
+}
+
+func (e *evaluator) run() error {
+	maxCost := int(e.proto.LogicSigMaxCost)
+	for i := 0; i < len(e.instructions); {
+		inst := e.instructions[i]
+		cost, _ := opCost(inst, e.version)
+		e.cost += cost
+		if e.cost > maxCost {
+			return &EvalError{Pc: inst.pc, Err: fmt.Errorf("cost budget %d exceeded", maxCost)}
+		}
+		next, err := e.step(inst, i)
+		if err == nil && len(e.stack) > maxStackDepth {
+			err = fmt.Errorf("stack overflow")
+		}
+		if err != nil {
+			return &EvalError{Pc: inst.pc, Err: err}
+		}
+		if e.params.Trace {
+			e.trace = append(e.trace, EvalStep{Pc: inst.pc, Text: inst.text, Stack: append([]StackValue(nil), e.stack...)})
+		}
+		i = next
+	}
+	return nil
+}
+
+// branch returns the index of the instruction at target.
+func (e *evaluator) branch(target int) (int, error) {
+	i, ok := e.index[target]
+	if !ok {
+		return 0, fmt.Errorf("branch target %d is not the start of an instruction", target)
+	}
+	return i, nil
+}
+
+func (e *evaluator) push(v StackValue) {
+	e.stack = append(e.stack, v)
+}
+
+func (e *evaluator) pushUint(u uint64) {
+	e.push(StackValue{Uint: u})
+}
+
+func (e *evaluator) pushBool(b bool) {
+	if b {
+		e.pushUint(1)
+	} else {
+		e.pushUint(0)
+	}
+}
+
+func (e *evaluator) pushBytes(b []byte) error {
+	if len(b) > maxStringSize {
+		return fmt.Errorf("byte array of %d bytes exceeds %d", len(b), maxStringSize)
+	}
+	e.push(StackValue{IsBytes: true, Bytes: b})
+	return nil
+}
+
+func (e *evaluator) pop() (StackValue, error) {
+	if len(e.stack) == 0 {
+		return StackValue{}, fmt.Errorf("stack underflow")
+	}
+	v := e.stack[len(e.stack)-1]
+	e.stack = e.stack[:len(e.stack)-1]
+	return v, nil
+}
+
+func (e *evaluator) popUint() (uint64, error) {
+	v, err := e.pop()
+	if err != nil {
+		return 0, err
+	}
+	if v.IsBytes {
+		return 0, fmt.Errorf("expected uint64 but got []byte")
+	}
+	return v.Uint, nil
+}
+
+func (e *evaluator) popBytes() ([]byte, error) {
+	v, err := e.pop()
+	if err != nil {
+		return nil, err
+	}
+	if !v.IsBytes {
+		return nil, fmt.Errorf("expected []byte but got uint64")
+	}
+	return v.Bytes, nil
+}
+
+// popUints pops two uint64 values, a being the deeper one.
+func (e *evaluator) popUints() (a, b uint64, err error) {
+	if b, err = e.popUint(); err != nil {
+		return
+	}
+	a, err = e.popUint()
+	return
+}
+
+// popBytess pops two bytes values, a being the deeper one.
+func (e *evaluator) popBytess() (a, b []byte, err error) {
+	if b, err = e.popBytes(); err != nil {
+		return
+	}
+	a, err = e.popBytes()
+	return
+}
+
+// need checks that the stack has at least n values.
+func (e *evaluator) need(n int) error {
+	if len(e.stack) < n {
+		return fmt.Errorf("stack underflow")
+	}
+	return nil
+}
+
+// step evaluates the instruction inst at index i, and returns the index of the
+// next instruction to evaluate.
+func (e *evaluator) step(inst disassembled, i int) (int, error) {
+	next := i + 1
+	name := inst.spec.name
+	var err error
+	switch name {
+	case "err":
+		return 0, fmt.Errorf("err opcode executed")
+	case "sha256", "keccak256", "sha512_256", "sha3_256":
+		var data []byte
+		if data, err = e.popBytes(); err != nil {
+			return 0, err
+		}
+		err = e.pushBytes(hash(name, data))
+	case "ed25519verify", "ed25519verify_bare":
+		var data, sig, pk []byte
+		if pk, err = e.popBytes(); err != nil {
+			return 0, err
+		}
+		if data, sig, err = e.popBytess(); err != nil {
+			return 0, err
+		}
+		if len(pk) != ed25519.PublicKeySize || len(sig) != ed25519.SignatureSize {
+			return 0, fmt.Errorf("invalid public key or signature length")
+		}
+		if name == "ed25519verify" {
+			var signature types.Signature
+			copy(signature[:], sig)
+			e.pushBool(crypto.TealVerify(pk, data, crypto.AddressFromProgram(e.program), signature))
+		} else {
+			e.pushBool(ed25519.Verify(pk, data, sig))
+		}
+
+	case "+", "-", "/", "*", "%", "|", "&", "^", "shl", "shr", "exp":
+		var a, b, c uint64
+		if a, b, err = e.popUints(); err != nil {
+			return 0, err
+		}
+		if c, err = arithmetic(name, a, b); err != nil {
+			return 0, err
+		}
+		e.pushUint(c)
+	case "<", ">", "<=", ">=", "&&", "||":
+		var a, b uint64
+		if a, b, err = e.popUints(); err != nil {
+			return 0, err
+		}
+		e.pushBool(compare(name, a, b))
+	case "==", "!=":
+		var a, b StackValue
+		if b, err = e.pop(); err != nil {
+			return 0, err
+		}
+		if a, err = e.pop(); err != nil {
+			return 0, err
+		}
+		if a.IsBytes != b.IsBytes {
+			return 0, fmt.Errorf("cannot compare uint64 to []byte")
+		}
+		equal := a.Uint == b.Uint && bytes.Equal(a.Bytes, b.Bytes)
+		e.pushBool(equal == (name == "=="))
+	case "!", "~", "sqrt", "bitlen":
+		var v StackValue
+		if v, err = e.pop(); err != nil {
+			return 0, err
+		}
+		switch {
+		case name == "bitlen" && v.IsBytes:
+			e.pushUint(uint64(new(big.Int).SetBytes(v.Bytes).BitLen()))
+		case v.IsBytes:
+			return 0, fmt.Errorf("expected uint64 but got []byte")
+		case name == "!":
+			e.pushBool(v.Uint == 0)
+		case name == "~":
+			e.pushUint(^v.Uint)
+		case name == "sqrt":
+			e.pushUint(new(big.Int).Sqrt(new(big.Int).SetUint64(v.Uint)).Uint64())
+		default:
+			e.pushUint(uint64(bits.Len64(v.Uint)))
+		}
+	case "mulw", "addw", "expw":
+		var a, b, high, low uint64
+		if a, b, err = e.popUints(); err != nil {
+			return 0, err
+		}
+		switch name {
+		case "mulw":
+			high, low = bits.Mul64(a, b)
+		case "addw":
+			low, high = bits.Add64(a, b, 0)
+		default:
+			if a == 0 && b == 0 {
+				return 0, fmt.Errorf("0^0 is undefined")
+			}
+			r := new(big.Int).Exp(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b), nil)
+			if r.BitLen() > 128 {
+				return 0, fmt.Errorf("%d^%d overflow", a, b)
+			}
+			low = new(big.Int).And(r, new(big.Int).SetUint64(math.MaxUint64)).Uint64()
+			high = new(big.Int).Rsh(r, 64).Uint64()
+		}
+		e.pushUint(high)
+		e.pushUint(low)
+	case "divmodw":
+		var ah, al, bh, bl uint64
+		if bh, bl, err = e.popUints(); err != nil {
+			return 0, err
+		}
+		if ah, al, err = e.popUints(); err != nil {
+			return 0, err
+		}
+		divisor := new(big.Int).SetBytes(append(itob(bh), itob(bl)...))
+		if divisor.Sign() == 0 {
+			return 0, fmt.Errorf("/ 0")
+		}
+		q, r := new(big.Int).QuoRem(new(big.Int).SetBytes(append(itob(ah), itob(al)...)), divisor, new(big.Int))
+		mask := new(big.Int).SetUint64(math.MaxUint64)
+		e.pushUint(new(big.Int).Rsh(q, 64).Uint64())
+		e.pushUint(new(big.Int).And(q, mask).Uint64())
+		e.pushUint(new(big.Int).Rsh(r, 64).Uint64())
+		e.pushUint(new(big.Int).And(r, mask).Uint64())
+	case "divw":
+		var high, low, divisor uint64
+		if divisor, err = e.popUint(); err != nil {
+			return 0, err
+		}
+		if high, low, err = e.popUints(); err != nil {
+			return 0, err
+		}
+		if divisor == 0 {
+			return 0, fmt.Errorf("/ 0")
+		}
+		if high >= divisor {
+			return 0, fmt.Errorf("divw overflow")
+		}
+		q, _ := bits.Div64(high, low, divisor)
+		e.pushUint(q)
+
+	case "len":
+		var b []byte
+		if b, err = e.popBytes(); err != nil {
+			return 0, err
+		}
+		e.pushUint(uint64(len(b)))
+	case "itob":
+		var u uint64
+		if u, err = e.popUint(); err != nil {
+			return 0, err
+		}
+		err = e.pushBytes(itob(u))
+	case "btoi":
+		var b []byte
+		if b, err = e.popBytes(); err != nil {
+			return 0, err
+		}
+		if len(b) > 8 {
+			return 0, fmt.Errorf("btoi arg too long, got %d bytes", len(b))
+		}
+		var u uint64
+		for _, c := range b {
+			u = u<<8 | uint64(c)
+		}
+		e.pushUint(u)
+
+	case "intcblock":
+		e.intc = inst.ints
+	case "intc", "intc_0", "intc_1", "intc_2", "intc_3":
+		index := immIndex(inst)
+		if index >= len(e.intc) {
+			return 0, fmt.Errorf("intc %d beyond %d constants", index, len(e.intc))
+		}
+		e.pushUint(e.intc[index])
+	case "bytecblock":
+		e.bytec = inst.bytes
+	case "bytec", "bytec_0", "bytec_1", "bytec_2", "bytec_3":
+		index := immIndex(inst)
+		if index >= len(e.bytec) {
+			return 0, fmt.Errorf("bytec %d beyond %d constants", index, len(e.bytec))
+		}
+		err = e.pushBytes(e.bytec[index])
+	case "pushint", "pushints":
+		for _, u := range inst.ints {
+			e.pushUint(u)
+		}
+	case "pushbytes", "pushbytess":
+		for _, b := range inst.bytes {
+			if err = e.pushBytes(b); err != nil {
+				return 0, err
+			}
+		}
+	case "arg", "arg_0", "arg_1", "arg_2", "arg_3", "args":
+		var index uint64
+		if name == "args" {
+			if index, err = e.popUint(); err != nil {
+				return 0, err
+			}
+		} else {
+			index = uint64(immIndex(inst))
+		}
+		if index >= uint64(len(e.params.Args)) {
+			return 0, fmt.Errorf("cannot load arg[%d] of %d", index, len(e.params.Args))
+		}
+		err = e.pushBytes(e.params.Args[index])
+
+	case "txn", "gtxn", "txna", "gtxna", "gtxns", "gtxnsa", "txnas", "gtxnas", "gtxnsas":
+		err = e.txnOp(inst)
+	case "global":
+		var v StackValue
+		if v, err = e.global(inst.fields[0]); err != nil {
+			return 0, err
+		}
+		e.push(v)
+	case "load", "loads":
+		var index uint64
+		if name == "load" {
+			index = inst.ints[0]
+		} else if index, err = e.popUint(); err != nil {
+			return 0, err
+		}
+		if index >= uint64(len(e.scratch)) {
+			return 0, fmt.Errorf("invalid scratch space index %d", index)
+		}
+		e.push(e.scratch[index])
+	case "store", "stores":
+		var v StackValue
+		var index uint64
+		if v, err = e.pop(); err != nil {
+			return 0, err
+		}
+		if name == "store" {
+			index = inst.ints[0]
+		} else if index, err = e.popUint(); err != nil {
+			return 0, err
+		}
+		if index >= uint64(len(e.scratch)) {
+			return 0, fmt.Errorf("invalid scratch space index %d", index)
+		}
+		e.scratch[index] = v
+
+	case "bnz", "bz", "b":
+		taken := true
+		if name != "b" {
+			var u uint64
+			if u, err = e.popUint(); err != nil {
+				return 0, err
+			}
+			taken = (u != 0) == (name == "bnz")
+		}
+		if taken {
+			return e.branch(inst.targets[0])
+		}
+	case "switch", "match":
+		var taken = -1
+		if name == "switch" {
+			var u uint64
+			if u, err = e.popUint(); err != nil {
+				return 0, err
+			}
+			if u < uint64(len(inst.targets)) {
+				taken = int(u)
+			}
+		} else {
+			n := len(inst.targets)
+			if err = e.need(n + 1); err != nil {
+				return 0, err
+			}
+			value := e.stack[len(e.stack)-1]
+			cases := e.stack[len(e.stack)-1-n : len(e.stack)-1]
+			for j, c := range cases {
+				if c.IsBytes == value.IsBytes && c.Uint == value.Uint && bytes.Equal(c.Bytes, value.Bytes) {
+					taken = j
+					break
+				}
+			}
+			e.stack = e.stack[:len(e.stack)-1-n]
+		}
+		if taken >= 0 {
+			return e.branch(inst.targets[taken])
+		}
+	case "return":
+		var v StackValue
+		if v, err = e.pop(); err != nil {
+			return 0, err
+		}
+		e.stack = []StackValue{v}
+		return len(e.instructions), nil
+	case "assert":
+		var u uint64
+		if u, err = e.popUint(); err != nil {
+			return 0, err
+		}
+		if u == 0 {
+			return 0, fmt.Errorf("assert failed")
+		}
+	case "callsub":
+		if len(e.frames) >= maxCallDepth {
+			return 0, fmt.Errorf("callsub exceeds the maximum call depth %d", maxCallDepth)
+		}
+		e.frames = append(e.frames, evalFrame{next: next, height: len(e.stack)})
+		return e.branch(inst.targets[0])
+	case "retsub":
+		return e.retsub()
+	case "proto":
+		if len(e.frames) == 0 {
+			return 0, fmt.Errorf("proto was executed without a callsub")
+		}
+		frame := &e.frames[len(e.frames)-1]
+		frame.proto, frame.args, frame.returns = true, int(inst.ints[0]), int(inst.ints[1])
+		if frame.height < frame.args {
+			return 0, fmt.Errorf("callsub to proto with %d arguments and a stack of %d", frame.args, frame.height)
+		}
+	case "frame_dig", "frame_bury":
+		var index int
+		if index, err = e.frameIndex(int(int8(inst.ints[0]))); err != nil {
+			return 0, err
+		}
+		if name == "frame_dig" {
+			e.push(e.stack[index])
+			break
+		}
+		var v StackValue
+		if v, err = e.pop(); err != nil {
+			return 0, err
+		}
+		if index >= len(e.stack) {
+			return 0, fmt.Errorf("frame_bury above the stack")
+		}
+		e.stack[index] = v
+
+	case "pop":
+		_, err = e.pop()
+	case "popn":
+		n := int(inst.ints[0])
+		if err = e.need(n); err != nil {
+			return 0, err
+		}
+		e.stack = e.stack[:len(e.stack)-n]
+	case "dup", "dupn":
+		n := 1
+		if name == "dupn" {
+			n = int(inst.ints[0])
+		}
+		if err = e.need(1); err != nil {
+			return 0, err
+		}
+		for j := 0; j < n; j++ {
+			e.push(e.stack[len(e.stack)-1])
+		}
+	case "dup2":
+		if err = e.need(2); err != nil {
+			return 0, err
+		}
+		e.stack = append(e.stack, e.stack[len(e.stack)-2:]...)
+	case "dig":
+		n := int(inst.ints[0])
+		if err = e.need(n + 1); err != nil {
+			return 0, err
+		}
+		e.push(e.stack[len(e.stack)-1-n])
+	case "bury":
+		n := int(inst.ints[0])
+		if n == 0 {
+			return 0, fmt.Errorf("bury 0 is invalid")
+		}
+		if err = e.need(n + 1); err != nil {
+			return 0, err
+		}
+		top := len(e.stack) - 1
+		e.stack[top-n] = e.stack[top]
+		e.stack = e.stack[:top]
+	case "swap":
+		if err = e.need(2); err != nil {
+			return 0, err
+		}
+		top := len(e.stack) - 1
+		e.stack[top], e.stack[top-1] = e.stack[top-1], e.stack[top]
+	case "select":
+		var c uint64
+		if c, err = e.popUint(); err != nil {
+			return 0, err
+		}
+		if err = e.need(2); err != nil {
+			return 0, err
+		}
+		top := len(e.stack) - 1
+		if c != 0 {
+			e.stack[top-1] = e.stack[top]
+		}
+		e.stack = e.stack[:top]
+	case "cover", "uncover":
+		n := int(inst.ints[0])
+		if err = e.need(n + 1); err != nil {
+			return 0, err
+		}
+		top := len(e.stack) - 1
+		if name == "cover" {
+			v := e.stack[top]
+			copy(e.stack[top-n+1:], e.stack[top-n:top])
+			e.stack[top-n] = v
+		} else {
+			v := e.stack[top-n]
+			copy(e.stack[top-n:], e.stack[top-n+1:])
+			e.stack[top] = v
+		}
+
+	case "concat":
+		var a, b []byte
+		if a, b, err = e.popBytess(); err != nil {
+			return 0, err
+		}
+		err = e.pushBytes(append(append([]byte(nil), a...), b...))
+	case "substring", "substring3", "extract", "extract3":
+		var start, end uint64
+		switch name {
+		case "substring3":
+			if start, end, err = e.popUints(); err != nil {
+				return 0, err
+			}
+		case "extract3":
+			if start, end, err = e.popUints(); err != nil {
+				return 0, err
+			}
+			end += start
+			if end < start {
+				return 0, fmt.Errorf("extract range overflows")
+			}
+		default:
+			start, end = inst.ints[0], inst.ints[1]
+			if name == "extract" {
+				end += start
+			}
+		}
+		var b []byte
+		if b, err = e.popBytes(); err != nil {
+			return 0, err
+		}
+		if name == "extract" && inst.ints[1] == 0 {
+			end = uint64(len(b))
+		}
+		if start > end || end > uint64(len(b)) {
+			return 0, fmt.Errorf("%s range %d-%d is beyond the length %d", name, start, end, len(b))
+		}
+		err = e.pushBytes(b[start:end])
+	case "extract_uint16", "extract_uint32", "extract_uint64":
+		var b []byte
+		var start uint64
+		if start, err = e.popUint(); err != nil {
+			return 0, err
+		}
+		if b, err = e.popBytes(); err != nil {
+			return 0, err
+		}
+		size := map[string]uint64{"extract_uint16": 2, "extract_uint32": 4, "extract_uint64": 8}[name]
+		if start+size < start || start+size > uint64(len(b)) {
+			return 0, fmt.Errorf("%s range %d-%d is beyond the length %d", name, start, start+size, len(b))
+		}
+		var u uint64
+		for _, c := range b[start : start+size] {
+			u = u<<8 | uint64(c)
+		}
+		e.pushUint(u)
+	case "replace2", "replace3":
+		var b, replacement []byte
+		var start uint64
+		if replacement, err = e.popBytes(); err != nil {
+			return 0, err
+		}
+		if name == "replace2" {
+			start = inst.ints[0]
+		} else if start, err = e.popUint(); err != nil {
+			return 0, err
+		}
+		if b, err = e.popBytes(); err != nil {
+			return 0, err
+		}
+		end := start + uint64(len(replacement))
+		if end < start || end > uint64(len(b)) {
+			return 0, fmt.Errorf("%s range %d-%d is beyond the length %d", name, start, end, len(b))
+		}
+		replaced := append([]byte(nil), b...)
+		copy(replaced[start:], replacement)
+		err = e.pushBytes(replaced)
+	case "getbit", "getbyte":
+		var index uint64
+		var v StackValue
+		if index, err = e.popUint(); err != nil {
+			return 0, err
+		}
+		if v, err = e.pop(); err != nil {
+			return 0, err
+		}
+		if name == "getbyte" {
+			if !v.IsBytes {
+				return 0, fmt.Errorf("expected []byte but got uint64")
+			}
+			if index >= uint64(len(v.Bytes)) {
+				return 0, fmt.Errorf("getbyte index %d is beyond the length %d", index, len(v.Bytes))
+			}
+			e.pushUint(uint64(v.Bytes[index]))
+			break
+		}
+		var bit uint64
+		if bit, err = getBit(v, index); err != nil {
+			return 0, err
+		}
+		e.pushUint(bit)
+	case "setbit", "setbyte":
+		var index, value uint64
+		var v StackValue
+		if index, value, err = e.popUints(); err != nil {
+			return 0, err
+		}
+		if v, err = e.pop(); err != nil {
+			return 0, err
+		}
+		if name == "setbyte" {
+			if !v.IsBytes {
+				return 0, fmt.Errorf("expected []byte but got uint64")
+			}
+			if index >= uint64(len(v.Bytes)) || value > math.MaxUint8 {
+				return 0, fmt.Errorf("setbyte index %d or value %d out of range", index, value)
+			}
+			set := append([]byte(nil), v.Bytes...)
+			set[index] = byte(value)
+			err = e.pushBytes(set)
+			break
+		}
+		if v, err = setBit(v, index, value); err != nil {
+			return 0, err
+		}
+		e.push(v)
+	case "bzero":
+		var n uint64
+		if n, err = e.popUint(); err != nil {
+			return 0, err
+		}
+		if n > maxStringSize {
+			return 0, fmt.Errorf("bzero of %d bytes exceeds %d", n, maxStringSize)
+		}
+		err = e.pushBytes(make([]byte, n))
+	case "base64_decode":
+		var b []byte
+		if b, err = e.popBytes(); err != nil {
+			return 0, err
+		}
+		encoding := base64.URLEncoding
+		if inst.fields[0] == "StdEncoding" {
+			encoding = base64.StdEncoding
+		}
+		var decoded []byte
+		if decoded, err = encoding.Strict().DecodeString(string(b)); err != nil {
+			return 0, err
+		}
+		err = e.pushBytes(decoded)
+
+	case "b+", "b-", "b/", "b*", "b%", "b|", "b&", "b^", "b<", "b>", "b<=", "b>=", "b==", "b!=":
+		var a, b []byte
+		if a, b, err = e.popBytess(); err != nil {
+			return 0, err
+		}
+		if len(a) > maxByteMathSize || len(b) > maxByteMathSize {
+			return 0, fmt.Errorf("math attempted on large byte-array")
+		}
+		var v StackValue
+		if v, err = byteMath(name, a, b); err != nil {
+			return 0, err
+		}
+		e.push(v)
+	case "b~", "bsqrt":
+		var a []byte
+		if a, err = e.popBytes(); err != nil {
+			return 0, err
+		}
+		if len(a) > maxByteMathSize {
+			return 0, fmt.Errorf("math attempted on large byte-array")
+		}
+		if name == "bsqrt" {
+			err = e.pushBytes(new(big.Int).Sqrt(new(big.Int).SetBytes(a)).Bytes())
+			break
+		}
+		inverted := make([]byte, len(a))
+		for j, c := range a {
+			inverted[j] = ^c
+		}
+		err = e.pushBytes(inverted)
+
+	default:
+		if feature, ok := opFeature(name); ok && appOnlyFeatures[feature] || appOnlyOps[name] {
+			return 0, fmt.Errorf("%s is only available in applications", name)
+		}
+		return 0, fmt.Errorf("%s is not supported by the evaluator", name)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+// appOnlyFeatures are the features of the opcodes only available in
+// applications, with appOnlyOps.
+var appOnlyFeatures = map[string]bool{
+	FeatureAppState:          true,
+	FeatureInnerTransactions: true,
+	FeatureLogs:              true,
+	FeatureBoxes:             true,
+	FeatureGroupAccess:       true,
+	FeatureOnlineStake:       true,
+}
+
+var appOnlyOps = map[string]bool{
+	"balance":     true,
+	"min_balance": true,
+}
+
+// immIndex returns the index of the constant or argument loaded by inst.
+func immIndex(inst disassembled) int {
+	if len(inst.ints) > 0 {
+		return int(inst.ints[0])
+	}
+	name := inst.spec.name
+	return int(name[len(name)-1] - '0')
+}
+
+func (e *evaluator) retsub() (int, error) {
+	if len(e.frames) == 0 {
+		return 0, fmt.Errorf("retsub with empty callstack")
+	}
+	frame := e.frames[len(e.frames)-1]
+	e.frames = e.frames[:len(e.frames)-1]
+	if frame.proto {
+		if len(e.stack) < frame.height+frame.returns {
+			return 0, fmt.Errorf("retsub executed with stack below frame, %d values for %d return values", len(e.stack)-frame.height, frame.returns)
+		}
+		returns := e.stack[len(e.stack)-frame.returns:]
+		base := frame.height - frame.args
+		e.stack = append(e.stack[:base], returns...)
+	}
+	return frame.next, nil
+}
+
+// frameIndex returns the index in the stack of the frame_dig or frame_bury
+// offset of the current subroutine.
+func (e *evaluator) frameIndex(offset int) (int, error) {
+	if len(e.frames) == 0 || !e.frames[len(e.frames)-1].proto {
+		return 0, fmt.Errorf("frame access without a proto")
+	}
+	frame := e.frames[len(e.frames)-1]
+	if offset < 0 && -offset > frame.args {
+		return 0, fmt.Errorf("frame offset %d is below the %d arguments", offset, frame.args)
+	}
+	index := frame.height + offset
+	if index >= len(e.stack) {
+		return 0, fmt.Errorf("frame offset %d is above the stack", offset)
+	}
+	return index, nil
+}
+
+func (e *evaluator) txnOp(inst disassembled) error {
+	name := inst.spec.name
+	groupIndex := uint64(e.params.GroupIndex)
+	var arrayIndex uint64
+	var err error
+	ints := inst.ints
+
+	// the group index is an immediate of the gtxn opcodes
+	if name == "gtxn" || name == "gtxna" || name == "gtxnas" {
+		groupIndex, ints = ints[0], ints[1:]
+	}
+	if len(ints) > 0 {
+		arrayIndex = ints[0]
+	}
+	if name == "txnas" || name == "gtxnas" || name == "gtxnsas" {
+		if arrayIndex, err = e.popUint(); err != nil {
+			return err
+		}
+	}
+	if name == "gtxns" || name == "gtxnsa" || name == "gtxnsas" {
+		if groupIndex, err = e.popUint(); err != nil {
+			return err
+		}
+	}
+	if groupIndex >= uint64(len(e.params.TxnGroup)) {
+		return fmt.Errorf("txn index %d is outside the group of %d transactions", groupIndex, len(e.params.TxnGroup))
+	}
+	v, err := txnField(e.params.TxnGroup[groupIndex], int(groupIndex), inst.fields[0], arrayIndex)
+	if err != nil {
+		return err
+	}
+	if v.IsBytes {
+		return e.pushBytes(v.Bytes)
+	}
+	e.push(v)
+	return nil
+}
+
+func (e *evaluator) global(field string) (StackValue, error) {
+	txn := e.params.TxnGroup[e.params.GroupIndex]
+	switch field {
+	case "MinTxnFee":
+		return StackValue{Uint: uint64(e.proto.MinTxnFee)}, nil
+	case "MinBalance", "AssetCreateMinBalance", "AssetOptInMinBalance":
+		return StackValue{Uint: uint64(e.proto.MinBalance)}, nil
+	case "MaxTxnLife":
+		return StackValue{Uint: e.proto.MaxTxnLife}, nil
+	case "ZeroAddress":
+		return StackValue{IsBytes: true, Bytes: make([]byte, len(types.ZeroAddress))}, nil
+	case "GroupSize":
+		return StackValue{Uint: uint64(len(e.params.TxnGroup))}, nil
+	case "LogicSigVersion":
+		return StackValue{Uint: uint64(e.proto.LogicSigVersion)}, nil
+	case "GroupID":
+		return StackValue{IsBytes: true, Bytes: append([]byte(nil), txn.Group[:]...)}, nil
+	case "OpcodeBudget":
+		return StackValue{Uint: e.proto.LogicSigMaxCost - uint64(e.cost)}, nil
+	case "GenesisHash":
+		return StackValue{IsBytes: true, Bytes: append([]byte(nil), txn.GenesisHash[:]...)}, nil
+	case "PayoutsEnabled", "PayoutsGoOnlineFee", "PayoutsPercent", "PayoutsMinBalance", "PayoutsMaxBalance":
+		return StackValue{}, fmt.Errorf("global %s is not supported by the evaluator", field)
+	}
+	return StackValue{}, fmt.Errorf("global %s is only available in applications", field)
+}
+
+// txnField returns the field of the transaction txn, at index groupIndex of
+// the group, and the element arrayIndex of array fields.
+func txnField(txn types.Transaction, groupIndex int, field string, arrayIndex uint64) (StackValue, error) {
+	uintValue := func(u uint64) (StackValue, error) {
+		return StackValue{Uint: u}, nil
+	}
+	bytesValue := func(b []byte) (StackValue, error) {
+		return StackValue{IsBytes: true, Bytes: append([]byte(nil), b...)}, nil
+	}
+	boolValue := func(b bool) (StackValue, error) {
+		if b {
+			return uintValue(1)
+		}
+		return uintValue(0)
+	}
+	element := func(length int) error {
+		if arrayIndex >= uint64(length) {
+			return fmt.Errorf("invalid %s index %d of %d", field, arrayIndex, length)
+		}
+		return nil
+	}
+	page := func(program []byte) (StackValue, error) {
+		pages := (len(program) + programPageSize - 1) / programPageSize
+		if err := element(pages); err != nil {
+			return StackValue{}, err
+		}
+		start := int(arrayIndex) * programPageSize
+		end := start + programPageSize
+		if end > len(program) {
+			end = len(program)
+		}
+		return bytesValue(program[start:end])
+	}
+	app := txn.ApplicationCallTxnFields
+	params := txn.AssetParams
+
+	switch field {
+	case "Sender":
+		return bytesValue(txn.Sender[:])
+	case "Fee":
+		return uintValue(uint64(txn.Fee))
+	case "FirstValid":
+		return uintValue(uint64(txn.FirstValid))
+	case "LastValid":
+		return uintValue(uint64(txn.LastValid))
+	case "Note":
+		return bytesValue(txn.Note)
+	case "Lease":
+		return bytesValue(txn.Lease[:])
+	case "Receiver":
+		return bytesValue(txn.Receiver[:])
+	case "Amount":
+		return uintValue(uint64(txn.Amount))
+	case "CloseRemainderTo":
+		return bytesValue(txn.CloseRemainderTo[:])
+	case "VotePK":
+		return bytesValue(txn.VotePK[:])
+	case "SelectionPK":
+		return bytesValue(txn.SelectionPK[:])
+	case "VoteFirst":
+		return uintValue(uint64(txn.VoteFirst))
+	case "VoteLast":
+		return uintValue(uint64(txn.VoteLast))
+	case "VoteKeyDilution":
+		return uintValue(txn.VoteKeyDilution)
+	case "Nonparticipation":
+		return boolValue(txn.Nonparticipation)
+	case "StateProofPK":
+		return bytesValue(txn.StateProofPK[:])
+	case "Type":
+		return bytesValue([]byte(txn.Type))
+	case "TypeEnum":
+		return uintValue(namedInts[string(txn.Type)])
+	case "XferAsset":
+		return uintValue(uint64(txn.XferAsset))
+	case "AssetAmount":
+		return uintValue(txn.AssetAmount)
+	case "AssetSender":
+		return bytesValue(txn.AssetSender[:])
+	case "AssetReceiver":
+		return bytesValue(txn.AssetReceiver[:])
+	case "AssetCloseTo":
+		return bytesValue(txn.AssetCloseTo[:])
+	case "GroupIndex":
+		return uintValue(uint64(groupIndex))
+	case "TxID":
+		return bytesValue(crypto.TransactionID(txn))
+	case "RekeyTo":
+		return bytesValue(txn.RekeyTo[:])
+
+	case "ApplicationID":
+		return uintValue(uint64(app.ApplicationID))
+	case "OnCompletion":
+		return uintValue(uint64(app.OnCompletion))
+	case "ApplicationArgs":
+		if err := element(len(app.ApplicationArgs)); err != nil {
+			return StackValue{}, err
+		}
+		return bytesValue(app.ApplicationArgs[arrayIndex])
+	case "NumAppArgs":
+		return uintValue(uint64(len(app.ApplicationArgs)))
+	case "Accounts":
+		// the sender is the account 0
+		if arrayIndex == 0 {
+			return bytesValue(txn.Sender[:])
+		}
+		if err := element(len(app.Accounts) + 1); err != nil {
+			return StackValue{}, err
+		}
+		return bytesValue(app.Accounts[arrayIndex-1][:])
+	case "NumAccounts":
+		return uintValue(uint64(len(app.Accounts)))
+	case "Assets":
+		if err := element(len(app.ForeignAssets)); err != nil {
+			return StackValue{}, err
+		}
+		return uintValue(uint64(app.ForeignAssets[arrayIndex]))
+	case "NumAssets":
+		return uintValue(uint64(len(app.ForeignAssets)))
+	case "Applications":
+		// the called application is the application 0
+		if arrayIndex == 0 {
+			return uintValue(uint64(app.ApplicationID))
+		}
+		if err := element(len(app.ForeignApps) + 1); err != nil {
+			return StackValue{}, err
+		}
+		return uintValue(uint64(app.ForeignApps[arrayIndex-1]))
+	case "NumApplications":
+		return uintValue(uint64(len(app.ForeignApps)))
+	case "ApprovalProgram":
+		return bytesValue(app.ApprovalProgram)
+	case "ClearStateProgram":
+		return bytesValue(app.ClearStateProgram)
+	case "ApprovalProgramPages":
+		return page(app.ApprovalProgram)
+	case "NumApprovalProgramPages":
+		return uintValue(uint64((len(app.ApprovalProgram) + programPageSize - 1) / programPageSize))
+	case "ClearStateProgramPages":
+		return page(app.ClearStateProgram)
+	case "NumClearStateProgramPages":
+		return uintValue(uint64((len(app.ClearStateProgram) + programPageSize - 1) / programPageSize))
+	case "GlobalNumUint":
+		return uintValue(app.GlobalStateSchema.NumUint)
+	case "GlobalNumByteSlice":
+		return uintValue(app.GlobalStateSchema.NumByteSlice)
+	case "LocalNumUint":
+		return uintValue(app.LocalStateSchema.NumUint)
+	case "LocalNumByteSlice":
+		return uintValue(app.LocalStateSchema.NumByteSlice)
+	case "ExtraProgramPages":
+		return uintValue(uint64(app.ExtraProgramPages))
+
+	case "ConfigAsset":
+		return uintValue(uint64(txn.ConfigAsset))
+	case "ConfigAssetTotal":
+		return uintValue(params.Total)
+	case "ConfigAssetDecimals":
+		return uintValue(uint64(params.Decimals))
+	case "ConfigAssetDefaultFrozen":
+		return boolValue(params.DefaultFrozen)
+	case "ConfigAssetUnitName":
+		return bytesValue([]byte(params.UnitName))
+	case "ConfigAssetName":
+		return bytesValue([]byte(params.AssetName))
+	case "ConfigAssetURL":
+		return bytesValue([]byte(params.URL))
+	case "ConfigAssetMetadataHash":
+		return bytesValue(params.MetadataHash[:])
+	case "ConfigAssetManager":
+		return bytesValue(params.Manager[:])
+	case "ConfigAssetReserve":
+		return bytesValue(params.Reserve[:])
+	case "ConfigAssetFreeze":
+		return bytesValue(params.Freeze[:])
+	case "ConfigAssetClawback":
+		return bytesValue(params.Clawback[:])
+	case "FreezeAsset":
+		return uintValue(uint64(txn.FreezeAsset))
+	case "FreezeAssetAccount":
+		return bytesValue(txn.FreezeAccount[:])
+	case "FreezeAssetFrozen":
+		return boolValue(txn.AssetFrozen)
+	}
+	return StackValue{}, fmt.Errorf("txn field %s is not available in logic signatures", field)
+}
+
+func hash(name string, data []byte) []byte {
+	switch name {
+	case "sha256":
+		sum := sha256.Sum256(data)
+		return sum[:]
+	case "keccak256":
+		h := sha3.NewLegacyKeccak256()
+		h.Write(data)
+		return h.Sum(nil)
+	case "sha512_256":
+		sum := sha512.Sum512_256(data)
+		return sum[:]
+	default:
+		sum := sha3.Sum256(data)
+		return sum[:]
+	}
+}
+
+func itob(u uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, u)
+	return b
+}
+
+// arithmetic evaluates the integer operator name on a and b.
+func arithmetic(name string, a, b uint64) (uint64, error) {
+	switch name {
+	case "+":
+		sum, carry := bits.Add64(a, b, 0)
+		if carry != 0 {
+			return 0, fmt.Errorf("+ overflowed")
+		}
+		return sum, nil
+	case "-":
+		if b > a {
+			return 0, fmt.Errorf("- would result negative")
+		}
+		return a - b, nil
+	case "*":
+		high, low := bits.Mul64(a, b)
+		if high != 0 {
+			return 0, fmt.Errorf("* overflowed")
+		}
+		return low, nil
+	case "/", "%":
+		if b == 0 {
+			return 0, fmt.Errorf("%s 0", name)
+		}
+		if name == "/" {
+			return a / b, nil
+		}
+		return a % b, nil
+	case "|":
+		return a | b, nil
+	case "&":
+		return a & b, nil
+	case "^":
+		return a ^ b, nil
+	case "shl", "shr":
+		if b >= 64 {
+			return 0, fmt.Errorf("%s arg too big, (%d)", name, b)
+		}
+		if name == "shl" {
+			return a << b, nil
+		}
+		return a >> b, nil
+	default:
+		if a == 0 && b == 0 {
+			return 0, fmt.Errorf("0^0 is undefined")
+		}
+		r := new(big.Int).Exp(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b), nil)
+		if !r.IsUint64() {
+			return 0, fmt.Errorf("%d^%d overflow", a, b)
+		}
+		return r.Uint64(), nil
+	}
+}
+
+// compare evaluates the comparison or logical operator name on a and b.
+func compare(name string, a, b uint64) bool {
+	switch name {
+	case "<":
+		return a < b
+	case ">":
+		return a > b
+	case "<=":
+		return a <= b
+	case ">=":
+		return a >= b
+	case "&&":
+		return a != 0 && b != 0
+	default:
+		return a != 0 || b != 0
+	}
+}
+
+// byteMath evaluates the byte math operator name on a and b, big-endian
+// unsigned integers.
+func byteMath(name string, a, b []byte) (StackValue, error) {
+	x, y := new(big.Int).SetBytes(a), new(big.Int).SetBytes(b)
+	result := func(r *big.Int) (StackValue, error) {
+		return StackValue{IsBytes: true, Bytes: r.Bytes()}, nil
+	}
+	switch name {
+	case "b+":
+		return result(x.Add(x, y))
+	case "b-":
+		if x.Cmp(y) < 0 {
+			return StackValue{}, fmt.Errorf("byte math would have negative result")
+		}
+		return result(x.Sub(x, y))
+	case "b*":
+		return result(x.Mul(x, y))
+	case "b/", "b%":
+		if y.Sign() == 0 {
+			return StackValue{}, fmt.Errorf("division by zero")
+		}
+		if name == "b/" {
+			return result(x.Quo(x, y))
+		}
+		return result(x.Rem(x, y))
+	case "b|", "b&", "b^":
+		// bitwise operators keep the length of the longer argument
+		if len(a) < len(b) {
+			a, b = b, a
+		}
+		out := append([]byte(nil), a...)
+		offset := len(a) - len(b)
+		for i := range out {
+			var c byte
+			if i >= offset {
+				c = b[i-offset]
+			}
+			switch name {
+			case "b|":
+				out[i] |= c
+			case "b&":
+				out[i] &= c
+			default:
+				out[i] ^= c
+			}
+		}
+		return StackValue{IsBytes: true, Bytes: out}, nil
+	}
+	cmp := x.Cmp(y)
+	var ok bool
+	switch name {
+	case "b<":
+		ok = cmp < 0
+	case "b>":
+		ok = cmp > 0
+	case "b<=":
+		ok = cmp <= 0
+	case "b>=":
+		ok = cmp >= 0
+	case "b==":
+		ok = cmp == 0
+	default:
+		ok = cmp != 0
+	}
+	if ok {
+		return StackValue{Uint: 1}, nil
+	}
+	return StackValue{}, nil
+}
+
+// getBit returns the bit index of v, counting from the lowest bit of an
+// integer and from the highest bit of the first byte of bytes.
+func getBit(v StackValue, index uint64) (uint64, error) {
+	if !v.IsBytes {
+		if index >= 64 {
+			return 0, fmt.Errorf("getbit index %d is beyond 63", index)
+		}
+		return v.Uint >> index & 1, nil
+	}
+	if index >= uint64(len(v.Bytes))*8 {
+		return 0, fmt.Errorf("getbit index %d is beyond the length %d", index, len(v.Bytes)*8)
+	}
+	return uint64(v.Bytes[index/8] >> (7 - index%8) & 1), nil
+}
+
+// setBit returns v with the bit index set to bit, as numbered by getBit.
+func setBit(v StackValue, index, bit uint64) (StackValue, error) {
+	if bit > 1 {
+		return StackValue{}, fmt.Errorf("setbit value > 1")
+	}
+	if !v.IsBytes {
+		if index >= 64 {
+			return StackValue{}, fmt.Errorf("setbit index %d is beyond 63", index)
+		}
+		v.Uint = v.Uint&^(1<<index) | bit<<index
+		return v, nil
+	}
+	if index >= uint64(len(v.Bytes))*8 {
+		return StackValue{}, fmt.Errorf("setbit index %d is beyond the length %d", index, len(v.Bytes)*8)
+	}
+	set := append([]byte(nil), v.Bytes...)
+	mask := byte(1) << (7 - index%8)
+	if bit == 1 {
+		set[index/8] |= mask
+	} else {
+		set[index/8] &^= mask
+	}
+	return StackValue{IsBytes: true, Bytes: set}, nil
+}
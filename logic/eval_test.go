@@ -0,0 +1,165 @@
+package logic
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/stretchr/testify/require"
+)
+
+func evalSource(t *testing.T, source string, params EvalParams) (EvalResult, error) {
+	program, err := Assemble(source)
+	require.NoError(t, err)
+	if params.TxnGroup == nil {
+		params.TxnGroup = []types.Transaction{{Type: types.PaymentTx}}
+	}
+	return EvalLogicSig(program, params)
+}
+
+func TestEvalLogicSigOps(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{"arithmetic", "int 7; int 3; -; int 2; *; int 8; ==; int 17; int 5; %; int 2; ==; &&"},
+		{"bitwise", "int 12; int 10; &; int 8; ==; int 1; int 4; shl; int 16; ==; &&"},
+		{"wide math", "int 18446744073709551615; int 2; mulw; int 18446744073709551614; ==; swap; int 1; ==; &&"},
+		{"divmodw", "int 1; int 0; int 0; int 2; divmodw; int 0; ==; assert; int 0; ==; assert; int 9223372036854775808; ==; assert; int 0; =="},
+		{"exp", "int 2; int 10; exp; int 1024; =="},
+		{"bytes", `byte "hello"; byte " world"; concat; extract 6 5; byte "world"; ==`},
+		{"substring", `byte "abcdef"; int 1; int 3; substring3; byte "bc"; ==; byte "abcdef"; substring 4 6; len; int 2; ==; &&`},
+		{"itob btoi", "int 258; itob; dup; int 6; extract_uint16; int 258; ==; swap; btoi; int 258; ==; &&"},
+		{"bits", "int 5; int 1; int 1; setbit; int 7; ==; byte 0x80; int 0; getbit; &&"},
+		{"byte math", "byte 0xff; byte 0x01; b+; byte 0x0100; b==; byte 0x0f; byte 0xf0; b|; byte 0xff; ==; &&"},
+		{"hash", `byte "abc"; sha256; byte 0xba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad; ==`},
+		{"stack", "int 1; int 2; int 3; uncover 2; int 1; ==; assert; cover 1; pop; int 3; ==; dupn 2; popn 2"},
+		{"scratch", "int 5; store 3; int 3; loads; int 6; int 2; stores; load 6; +; int 7; =="},
+		{"select", "int 1; int 2; int 0; select; int 1; =="},
+		{"loop", "int 0; loop: int 1; +; dup; int 10; <; bnz loop; int 10; =="},
+		{"subroutine", "int 3; int 4; callsub add; int 7; ==; return; add: proto 2 1; frame_dig -2; frame_dig -1; +; retsub"},
+		{"switch", "int 1; switch a b; err; a: err; b: byte 0x01; byte 0x02; byte 0x02; match c d; err; c: err; d: int 1"},
+		{"globals", "global GroupSize; int 1; ==; global MinTxnFee; int 1000; ==; &&; global ZeroAddress; len; int 32; ==; &&"},
+		{"base64", `byte "aGk="; base64_decode StdEncoding; byte "hi"; ==`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := evalSource(t, "#pragma version 10\n"+test.source, EvalParams{})
+			require.NoError(t, err)
+			require.True(t, result.Pass, "stack %v", result.Stack)
+		})
+	}
+}
+
+func TestEvalLogicSigTransaction(t *testing.T) {
+	owner := crypto.GenerateAccount().Address
+	source := `#pragma version 8
+txn TypeEnum
+int pay
+==
+txn Fee
+int 2000
+<=
+&&
+txn Receiver
+addr ` + owner.String() + `
+==
+&&
+arg 0
+sha256
+byte 0x` + "2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b" + `
+==
+&&
+gtxn 1 Amount
+int 5
+>=
+&&`
+	pay := types.Transaction{Type: types.PaymentTx}
+	pay.Fee = 1000
+	pay.Receiver = owner
+	group := []types.Transaction{pay, {Type: types.PaymentTx, PaymentTxnFields: types.PaymentTxnFields{Amount: 5}}}
+
+	result, err := evalSource(t, source, EvalParams{TxnGroup: group, Args: [][]byte{[]byte("secret")}, Trace: true})
+	require.NoError(t, err)
+	require.True(t, result.Pass)
+	require.Equal(t, 54, result.Cost)
+	require.Equal(t, "txn TypeEnum", result.Trace[0].Text)
+	require.Equal(t, []StackValue{{Uint: 1}}, result.Trace[0].Stack)
+
+	result, err = evalSource(t, source, EvalParams{TxnGroup: group, Args: [][]byte{[]byte("guess")}})
+	require.NoError(t, err)
+	require.False(t, result.Pass)
+
+	group[0].Fee = 5000
+	result, err = evalSource(t, source, EvalParams{TxnGroup: group, Args: [][]byte{[]byte("secret")}})
+	require.NoError(t, err)
+	require.False(t, result.Pass)
+
+	_, err = evalSource(t, source, EvalParams{TxnGroup: group})
+	require.EqualError(t, err, "cannot load arg[0] of 0 pc=51")
+	_, err = evalSource(t, source, EvalParams{TxnGroup: group[:1], Args: [][]byte{[]byte("secret")}})
+	require.EqualError(t, err, "txn index 1 is outside the group of 1 transactions pc=89")
+}
+
+func TestEvalLogicSigSignature(t *testing.T) {
+	account := crypto.GenerateAccount()
+	program, err := Assemble("#pragma version 5\narg 0\narg 1\naddr " + account.Address.String() + "\ned25519verify")
+	require.NoError(t, err)
+	data := sha256.Sum256([]byte("data"))
+	sig, err := crypto.TealSignFromProgram(account.PrivateKey, data[:], program)
+	require.NoError(t, err)
+
+	params := EvalParams{TxnGroup: []types.Transaction{{}}, Args: [][]byte{data[:], sig[:]}}
+	result, err := EvalLogicSig(program, params)
+	require.NoError(t, err)
+	require.True(t, result.Pass)
+	require.Equal(t, 1903, result.Cost)
+
+	params.Args[0] = []byte("other")
+	result, err = EvalLogicSig(program, params)
+	require.NoError(t, err)
+	require.False(t, result.Pass)
+}
+
+func TestEvalLogicSigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		err    string
+	}{
+		{"err", "#pragma version 8\nint 1\nerr", "err opcode executed pc=3"},
+		{"assert", "#pragma version 8\nint 0\nassert\nint 1", "assert failed pc=3"},
+		{"overflow", "#pragma version 8\nint 18446744073709551615\nint 1\n+", "+ overflowed pc=14"},
+		{"types", "#pragma version 8\nbyte 0x01\nint 1\n==", "cannot compare uint64 to []byte pc=6"},
+		{"underflow", "#pragma version 8\npop\nint 1", "stack underflow pc=1"},
+		{"final stack", "#pragma version 8\nint 1\nint 1", "stack len is 2 instead of 1 pc=6"},
+		{"final bytes", "#pragma version 8\nbyte 0x01", "stack finished with bytes not int pc=4"},
+		{"extract", "#pragma version 8\nbyte 0x0102\nextract 1 2\nlen", "extract range 1-3 is beyond the length 2 pc=5"},
+		{"app only", "#pragma version 8\nbyte \"k\"\napp_global_get", "app_global_get is only available in applications pc=4"},
+		{"app global", "#pragma version 8\nglobal Round", "global Round is only available in applications pc=1"},
+		{"unsupported", "#pragma version 8\nbyte 0x01\njson_ref JSONString", "json_ref is not supported by the evaluator pc=4"},
+		{"app only v11", "#pragma version 11\nonline_stake", "online_stake is only available in applications pc=1"},
+		{"unsupported v11", "#pragma version 11\nbyte 0x01\nmimc BN254Mp110\nlen", "mimc is not supported by the evaluator pc=4"},
+		{"budget", "#pragma version 8\nloop: b loop", "cost budget 20000 exceeded pc=1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := evalSource(t, test.source, EvalParams{})
+			require.EqualError(t, err, test.err)
+			var evalErr *EvalError
+			require.True(t, errors.As(err, &evalErr))
+			pc, ok := ErrorPc(err.Error())
+			require.True(t, ok)
+			require.Equal(t, evalErr.Pc, pc)
+		})
+	}
+
+	params := types.Consensus[types.ConsensusCurrentVersion]
+	params.LogicSigVersion = 9
+	_, err := evalSource(t, "#pragma version 10\nint 1", EvalParams{Proto: &params})
+	require.Error(t, err)
+	_, err = EvalLogicSig([]byte{0x08, 0x81, 0x01}, EvalParams{})
+	require.Error(t, err)
+}
@@ -0,0 +1,183 @@
+package logic
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// The AVM features DetectFeatures reports.
+const (
+	FeatureBackwardBranches  = "backward branches"
+	FeatureSubroutines       = "subroutines"
+	FeatureFrames            = "frames"
+	FeatureSwitch            = "switch"
+	FeatureBigMath           = "byte math"
+	FeatureAppState          = "app state"
+	FeatureInnerTransactions = "inner transactions"
+	FeatureLogs              = "logs"
+	FeatureBoxes             = "boxes"
+	FeatureECDSA             = "ecdsa"
+	FeatureEllipticCurves    = "elliptic curves"
+	FeatureVRF               = "vrf"
+	FeatureBlock             = "block"
+	FeatureJSON              = "json"
+	FeatureBase64            = "base64"
+	FeatureGroupAccess       = "group access"
+	FeatureOnlineStake       = "online stake"
+	FeatureMiMC              = "mimc"
+)
+
+// opFeatures are the features of the opcodes which are not given by their
+// prefix in opPrefixFeatures.
+var opFeatures = map[string]string{
+	"callsub":           FeatureSubroutines,
+	"retsub":            FeatureSubroutines,
+	"proto":             FeatureFrames,
+	"frame_dig":         FeatureFrames,
+	"frame_bury":        FeatureFrames,
+	"switch":            FeatureSwitch,
+	"match":             FeatureSwitch,
+	"log":               FeatureLogs,
+	"vrf_verify":        FeatureVRF,
+	"block":             FeatureBlock,
+	"json_ref":          FeatureJSON,
+	"base64_decode":     FeatureBase64,
+	"bsqrt":             FeatureBigMath,
+	"bzero":             FeatureBigMath,
+	"gload":             FeatureGroupAccess,
+	"gloads":            FeatureGroupAccess,
+	"gloadss":           FeatureGroupAccess,
+	"gaid":              FeatureGroupAccess,
+	"gaids":             FeatureGroupAccess,
+	"app_opted_in":      FeatureAppState,
+	"app_params_get":    FeatureAppState,
+	"asset_holding_get": FeatureAppState,
+	"asset_params_get":  FeatureAppState,
+	"acct_params_get":   FeatureAppState,
+	"voter_params_get":  FeatureOnlineStake,
+	"online_stake":      FeatureOnlineStake,
+	"mimc":              FeatureMiMC,
+}
+
+// opPrefixFeatures are the features of the opcodes by prefix.
+var opPrefixFeatures = []struct {
+	prefix  string
+	feature string
+}{
+	{"itxn", FeatureInnerTransactions},
+	{"gitxn", FeatureInnerTransactions},
+	{"box_", FeatureBoxes},
+	{"ecdsa_", FeatureECDSA},
+	{"ec_", FeatureEllipticCurves},
+	{"app_global_", FeatureAppState},
+	{"app_local_", FeatureAppState},
+	{"b", FeatureBigMath},
+}
+
+func opFeature(name string) (string, bool) {
+	if feature, ok := opFeatures[name]; ok {
+		return feature, true
+	}
+	for _, p := range opPrefixFeatures {
+		if !strings.HasPrefix(name, p.prefix) {
+			continue
+		}
+		// the byte math opcodes are b followed by an operator
+		if p.prefix == "b" && (len(name) < 2 || name[1] >= 'a' && name[1] <= 'z') {
+			continue
+		}
+		return p.feature, true
+	}
+	return "", false
+}
+
+// ProgramVersion returns the AVM version of a program, read from its prefix.
+// Unlike the other functions of the package, it takes programs of versions
+// above MaxVersion.
+func ProgramVersion(program []byte) (int, error) {
+	version, n := binary.Uvarint(program)
+	if n <= 0 || version == 0 || version > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid version")
+	}
+	return int(version), nil
+}
+
+// Features are the AVM features a program uses.
+type Features struct {
+	// Version is the version of the program
+	Version int
+	// MinVersion is the lowest version with every opcode, field and
+	// feature the program uses
+	MinVersion int
+	// Opcodes are the opcodes the program uses, sorted
+	Opcodes []string
+	// Features are the features the program uses, as the Feature constants,
+	// sorted
+	Features []string
+}
+
+// DetectFeatures reads the version of a program and reports the opcodes and
+// features it uses, and the lowest version it could have, so that tooling
+// can confirm it runs with a consensus version before submitting it.
+func DetectFeatures(program []byte) (Features, error) {
+	version, instructions, err := decodeProgram(program)
+	if err != nil {
+		return Features{}, err
+	}
+	f := Features{Version: version, MinVersion: assemblerDefaultVersion}
+	opcodes := map[string]bool{}
+	features := map[string]bool{}
+	needs := func(v int) {
+		if v > f.MinVersion {
+			f.MinVersion = v
+		}
+	}
+
+	for _, inst := range instructions {
+		opcodes[inst.spec.name] = true
+		needs(inst.spec.version)
+		if feature, ok := opFeature(inst.spec.name); ok {
+			features[feature] = true
+		}
+		fields := inst.fields
+		for _, imm := range inst.spec.imms {
+			if imm.kind != immField || len(fields) == 0 {
+				continue
+			}
+			if field, ok := imm.group.byName(fields[0]); ok {
+				needs(field.version)
+			}
+			fields = fields[1:]
+		}
+		for _, target := range inst.targets {
+			if target <= inst.pc {
+				features[FeatureBackwardBranches] = true
+				needs(backBranchVersion)
+			}
+		}
+	}
+
+	for name := range opcodes {
+		f.Opcodes = append(f.Opcodes, name)
+	}
+	sort.Strings(f.Opcodes)
+	for name := range features {
+		f.Features = append(f.Features, name)
+	}
+	sort.Strings(f.Features)
+	return f, nil
+}
+
+// CheckConsensus returns an error when the program cannot run with the
+// consensus parameters params, because its version is too recent.
+func (f Features) CheckConsensus(params types.ConsensusParams) error {
+	if f.Version > params.LogicSigVersion {
+		return fmt.Errorf("program version %d is higher than the highest version %d of the consensus protocol", f.Version, params.LogicSigVersion)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgramVersion(t *testing.T) {
+	version, err := ProgramVersion([]byte{0x0b, 0x81, 0x01})
+	require.NoError(t, err)
+	require.Equal(t, 11, version)
+
+	_, err = ProgramVersion(nil)
+	require.Error(t, err)
+	_, err = ProgramVersion([]byte{0x00})
+	require.Error(t, err)
+}
+
+func TestDetectFeatures(t *testing.T) {
+	program, err := Assemble(`#pragma version 8
+loop:
+txn Fee
+global CallerApplicationID
+b+
+box_get
+callsub f
+bnz loop
+f:
+proto 0 0
+itxn_begin
+retsub`)
+	require.NoError(t, err)
+
+	features, err := DetectFeatures(program)
+	require.NoError(t, err)
+	require.Equal(t, 8, features.Version)
+	require.Equal(t, 8, features.MinVersion)
+	require.Equal(t, []string{"b+", "bnz", "box_get", "callsub", "global", "itxn_begin", "proto", "retsub", "txn"}, features.Opcodes)
+	require.Equal(t, []string{FeatureBackwardBranches, FeatureBoxes, FeatureBigMath, FeatureFrames, FeatureInnerTransactions, FeatureSubroutines}, features.Features)
+
+	program, err = Assemble("#pragma version 8\nglobal CallerApplicationID\nbtoi")
+	require.NoError(t, err)
+	features, err = DetectFeatures(program)
+	require.NoError(t, err)
+	require.Equal(t, 6, features.MinVersion)
+	require.Empty(t, features.Features)
+
+	v38 := types.Consensus[types.ConsensusV38]
+	require.NoError(t, features.CheckConsensus(v38))
+	program, err = Assemble("#pragma version 10\nint 1")
+	require.NoError(t, err)
+	features, err = DetectFeatures(program)
+	require.NoError(t, err)
+	require.Error(t, features.CheckConsensus(v38))
+	require.NoError(t, features.CheckConsensus(types.Consensus[types.ConsensusV39]))
+
+	program, err = Assemble(`#pragma version 11
+online_stake
+global PayoutsEnabled
++
+txn Sender
+acct_params_get AcctLastProposed
+pop
++
+txn Sender
+voter_params_get VoterBalance
+pop
++
+byte 0x01
+mimc BN254Mp110
+len
++
+int 1
+block BlkProposer
+len
++`)
+	require.NoError(t, err)
+	features, err = DetectFeatures(program)
+	require.NoError(t, err)
+	require.Equal(t, 11, features.Version)
+	require.Equal(t, 11, features.MinVersion)
+	require.Equal(t, []string{"+", "acct_params_get", "block", "global", "len", "mimc", "online_stake", "pop", "pushbytes", "pushint", "txn", "voter_params_get"}, features.Opcodes)
+	require.Equal(t, []string{FeatureAppState, FeatureBlock, FeatureMiMC, FeatureOnlineStake}, features.Features)
+	require.Error(t, features.CheckConsensus(types.Consensus[types.ConsensusV39]))
+	require.NoError(t, features.CheckConsensus(types.Consensus[types.ConsensusCurrentVersion]))
+
+	// the v11 fields alone need v11
+	program, err = Assemble("#pragma version 11\nint 1\nblock BlkProposer\nlen")
+	require.NoError(t, err)
+	features, err = DetectFeatures(program)
+	require.NoError(t, err)
+	require.Equal(t, 11, features.MinVersion)
+}
@@ -0,0 +1,418 @@
+package logic
+
+// MaxVersion is the latest AVM version the assembler and disassembler
+// support.
+const MaxVersion = 11
+
+// immKind is the kind of an immediate argument of an opcode.
+type immKind int
+
+const (
+	// immByte is a uint8
+	immByte immKind = iota
+	// immInt8 is an int8, such as the frame offsets of frame_dig
+	immInt8
+	// immField is a uint8 naming a field of a fieldGroup
+	immField
+	// immLabel is an int16 offset from the end of the instruction
+	immLabel
+	// immLabels is a uint8 count followed by int16 offsets
+	immLabels
+	// immInt is a varuint
+	immInt
+	// immBytes is a varuint length followed by bytes
+	immBytes
+	// immInts is a varuint count followed by varuints
+	immInts
+	// immBytess is a varuint count followed by varuint length prefixed bytes
+	immBytess
+)
+
+type immediate struct {
+	kind  immKind
+	group *fieldGroup
+}
+
+// opSpec describes an opcode of the AVM.
+type opSpec struct {
+	name    string
+	opcode  byte
+	version int
+	imms    []immediate
+}
+
+// fieldSpec is a named value of an immediate argument, such as a transaction
+// field.
+type fieldSpec struct {
+	name    string
+	value   byte
+	version int
+	// array whether the transaction field is an array, accessed with txna
+	array bool
+}
+
+// fieldGroup is the set of names an immediate argument takes.
+type fieldGroup struct {
+	name   string
+	fields []fieldSpec
+}
+
+func (g *fieldGroup) byName(name string) (fieldSpec, bool) {
+	for _, f := range g.fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return fieldSpec{}, false
+}
+
+func (g *fieldGroup) byValue(value byte) (fieldSpec, bool) {
+	for _, f := range g.fields {
+		if f.value == value {
+			return f, true
+		}
+	}
+	return fieldSpec{}, false
+}
+
+func makeFields(name string, version int, names ...string) *fieldGroup {
+	g := &fieldGroup{name: name}
+	for i, n := range names {
+		g.fields = append(g.fields, fieldSpec{name: n, value: byte(i), version: version})
+	}
+	return g
+}
+
+// withVersion sets the version of the fields of g from the field named first.
+func (g *fieldGroup) withVersion(first string, version int) *fieldGroup {
+	found := false
+	for i := range g.fields {
+		if g.fields[i].name == first {
+			found = true
+		}
+		if found {
+			g.fields[i].version = version
+		}
+	}
+	return g
+}
+
+var txnFields = func() *fieldGroup {
+	g := makeFields("txn", 1,
+		"Sender", "Fee", "FirstValid", "FirstValidTime", "LastValid", "Note", "Lease",
+		"Receiver", "Amount", "CloseRemainderTo", "VotePK", "SelectionPK", "VoteFirst",
+		"VoteLast", "VoteKeyDilution", "Type", "TypeEnum", "XferAsset", "AssetAmount",
+		"AssetSender", "AssetReceiver", "AssetCloseTo", "GroupIndex", "TxID",
+		"ApplicationID", "OnCompletion", "ApplicationArgs", "NumAppArgs", "Accounts",
+		"NumAccounts", "ApprovalProgram", "ClearStateProgram", "RekeyTo", "ConfigAsset",
+		"ConfigAssetTotal", "ConfigAssetDecimals", "ConfigAssetDefaultFrozen",
+		"ConfigAssetUnitName", "ConfigAssetName", "ConfigAssetURL",
+		"ConfigAssetMetadataHash", "ConfigAssetManager", "ConfigAssetReserve",
+		"ConfigAssetFreeze", "ConfigAssetClawback", "FreezeAsset", "FreezeAssetAccount",
+		"FreezeAssetFrozen", "Assets", "NumAssets", "Applications", "NumApplications",
+		"GlobalNumUint", "GlobalNumByteSlice", "LocalNumUint", "LocalNumByteSlice",
+		"ExtraProgramPages", "Nonparticipation", "Logs", "NumLogs", "CreatedAssetID",
+		"CreatedApplicationID", "LastLog", "StateProofPK", "ApprovalProgramPages",
+		"NumApprovalProgramPages", "ClearStateProgramPages", "NumClearStateProgramPages")
+	g.withVersion("ApplicationID", 2).withVersion("Assets", 3).withVersion("ExtraProgramPages", 4).
+		withVersion("Nonparticipation", 5).withVersion("LastLog", 6).withVersion("ApprovalProgramPages", 7)
+	for i, f := range g.fields {
+		switch f.name {
+		case "FirstValidTime":
+			g.fields[i].version = 7
+		case "ApplicationArgs", "Accounts", "Assets", "Applications", "Logs",
+			"ApprovalProgramPages", "ClearStateProgramPages":
+			g.fields[i].array = true
+		}
+	}
+	return g
+}()
+
+var globalFields = makeFields("global", 1,
+	"MinTxnFee", "MinBalance", "MaxTxnLife", "ZeroAddress", "GroupSize", "LogicSigVersion",
+	"Round", "LatestTimestamp", "CurrentApplicationID", "CreatorAddress",
+	"CurrentApplicationAddress", "GroupID", "OpcodeBudget", "CallerApplicationID",
+	"CallerApplicationAddress", "AssetCreateMinBalance", "AssetOptInMinBalance", "GenesisHash",
+	"PayoutsEnabled", "PayoutsGoOnlineFee", "PayoutsPercent", "PayoutsMinBalance",
+	"PayoutsMaxBalance",
+).withVersion("LogicSigVersion", 2).withVersion("CreatorAddress", 3).
+	withVersion("CurrentApplicationAddress", 5).withVersion("OpcodeBudget", 6).
+	withVersion("AssetCreateMinBalance", 10).withVersion("PayoutsEnabled", 11)
+
+var assetHoldingFields = makeFields("asset_holding", 2, "AssetBalance", "AssetFrozen")
+
+var assetParamsFields = makeFields("asset_params", 2,
+	"AssetTotal", "AssetDecimals", "AssetDefaultFrozen", "AssetUnitName", "AssetName",
+	"AssetURL", "AssetMetadataHash", "AssetManager", "AssetReserve", "AssetFreeze",
+	"AssetClawback", "AssetCreator",
+).withVersion("AssetCreator", 5)
+
+var appParamsFields = makeFields("app_params", 5,
+	"AppApprovalProgram", "AppClearStateProgram", "AppGlobalNumUint", "AppGlobalNumByteSlice",
+	"AppLocalNumUint", "AppLocalNumByteSlice", "AppExtraProgramPages", "AppCreator", "AppAddress")
+
+var acctParamsFields = makeFields("acct_params", 6,
+	"AcctBalance", "AcctMinBalance", "AcctAuthAddr", "AcctTotalNumUint",
+	"AcctTotalNumByteSlice", "AcctTotalExtraAppPages", "AcctTotalAppsCreated",
+	"AcctTotalAppsOptedIn", "AcctTotalAssetsCreated", "AcctTotalAssets", "AcctTotalBoxes",
+	"AcctTotalBoxBytes", "AcctIncentiveEligible", "AcctLastProposed", "AcctLastHeartbeat",
+).withVersion("AcctTotalNumUint", 8).withVersion("AcctIncentiveEligible", 11)
+
+var voterParamsFields = makeFields("voter_params", 11, "VoterBalance", "VoterIncentiveEligible")
+
+var blockFields = makeFields("block", 7,
+	"BlkSeed", "BlkTimestamp", "BlkProposer", "BlkFeesCollected", "BlkBonus", "BlkBranch",
+	"BlkFeeSink", "BlkProtocol", "BlkTxnCounter", "BlkProposerPayout",
+).withVersion("BlkProposer", 11)
+
+var ecdsaCurves = makeFields("ECDSA", 5, "Secp256k1", "Secp256r1").withVersion("Secp256r1", 7)
+
+var base64Encodings = makeFields("base64", 7, "URLEncoding", "StdEncoding")
+
+var jsonRefTypes = makeFields("json_ref", 7, "JSONString", "JSONUint64", "JSONObject")
+
+var vrfStandards = makeFields("vrf_verify", 7, "VrfAlgorand")
+
+var ecGroups = makeFields("EC", 10, "BN254g1", "BN254g2", "BLS12_381g1", "BLS12_381g2")
+
+var mimcConfigs = makeFields("mimc", 11, "BN254Mp110", "BLS12_381Mp111")
+
+var (
+	imByte   = immediate{kind: immByte}
+	imInt8   = immediate{kind: immInt8}
+	imLabel  = immediate{kind: immLabel}
+	imLabels = immediate{kind: immLabels}
+	imInt    = immediate{kind: immInt}
+	imBytes  = immediate{kind: immBytes}
+	imInts   = immediate{kind: immInts}
+	imBytess = immediate{kind: immBytess}
+)
+
+func imField(g *fieldGroup) immediate {
+	return immediate{kind: immField, group: g}
+}
+
+func op(name string, opcode byte, version int, imms ...immediate) opSpec {
+	return opSpec{name: name, opcode: opcode, version: version, imms: imms}
+}
+
+// opSpecs are the opcodes of the AVM up to MaxVersion.
+var opSpecs = []opSpec{
+	op("err", 0x00, 1),
+	op("sha256", 0x01, 1),
+	op("keccak256", 0x02, 1),
+	op("sha512_256", 0x03, 1),
+	op("ed25519verify", 0x04, 1),
+	op("ecdsa_verify", 0x05, 5, imField(ecdsaCurves)),
+	op("ecdsa_pk_decompress", 0x06, 5, imField(ecdsaCurves)),
+	op("ecdsa_pk_recover", 0x07, 5, imField(ecdsaCurves)),
+	op("+", 0x08, 1),
+	op("-", 0x09, 1),
+	op("/", 0x0a, 1),
+	op("*", 0x0b, 1),
+	op("<", 0x0c, 1),
+	op(">", 0x0d, 1),
+	op("<=", 0x0e, 1),
+	op(">=", 0x0f, 1),
+	op("&&", 0x10, 1),
+	op("||", 0x11, 1),
+	op("==", 0x12, 1),
+	op("!=", 0x13, 1),
+	op("!", 0x14, 1),
+	op("len", 0x15, 1),
+	op("itob", 0x16, 1),
+	op("btoi", 0x17, 1),
+	op("%", 0x18, 1),
+	op("|", 0x19, 1),
+	op("&", 0x1a, 1),
+	op("^", 0x1b, 1),
+	op("~", 0x1c, 1),
+	op("mulw", 0x1d, 1),
+	op("addw", 0x1e, 2),
+	op("divmodw", 0x1f, 4),
+	op("intcblock", 0x20, 1, imInts),
+	op("intc", 0x21, 1, imByte),
+	op("intc_0", 0x22, 1),
+	op("intc_1", 0x23, 1),
+	op("intc_2", 0x24, 1),
+	op("intc_3", 0x25, 1),
+	op("bytecblock", 0x26, 1, imBytess),
+	op("bytec", 0x27, 1, imByte),
+	op("bytec_0", 0x28, 1),
+	op("bytec_1", 0x29, 1),
+	op("bytec_2", 0x2a, 1),
+	op("bytec_3", 0x2b, 1),
+	op("arg", 0x2c, 1, imByte),
+	op("arg_0", 0x2d, 1),
+	op("arg_1", 0x2e, 1),
+	op("arg_2", 0x2f, 1),
+	op("arg_3", 0x30, 1),
+	op("txn", 0x31, 1, imField(txnFields)),
+	op("global", 0x32, 1, imField(globalFields)),
+	op("gtxn", 0x33, 1, imByte, imField(txnFields)),
+	op("load", 0x34, 1, imByte),
+	op("store", 0x35, 1, imByte),
+	op("txna", 0x36, 2, imField(txnFields), imByte),
+	op("gtxna", 0x37, 2, imByte, imField(txnFields), imByte),
+	op("gtxns", 0x38, 3, imField(txnFields)),
+	op("gtxnsa", 0x39, 3, imField(txnFields), imByte),
+	op("gload", 0x3a, 4, imByte, imByte),
+	op("gloads", 0x3b, 4, imByte),
+	op("gaid", 0x3c, 4, imByte),
+	op("gaids", 0x3d, 4),
+	op("loads", 0x3e, 5),
+	op("stores", 0x3f, 5),
+	op("bnz", 0x40, 1, imLabel),
+	op("bz", 0x41, 2, imLabel),
+	op("b", 0x42, 2, imLabel),
+	op("return", 0x43, 2),
+	op("assert", 0x44, 3),
+	op("bury", 0x45, 8, imByte),
+	op("popn", 0x46, 8, imByte),
+	op("dupn", 0x47, 8, imByte),
+	op("pop", 0x48, 1),
+	op("dup", 0x49, 1),
+	op("dup2", 0x4a, 2),
+	op("dig", 0x4b, 3, imByte),
+	op("swap", 0x4c, 3),
+	op("select", 0x4d, 3),
+	op("cover", 0x4e, 5, imByte),
+	op("uncover", 0x4f, 5, imByte),
+	op("concat", 0x50, 2),
+	op("substring", 0x51, 2, imByte, imByte),
+	op("substring3", 0x52, 2),
+	op("getbit", 0x53, 3),
+	op("setbit", 0x54, 3),
+	op("getbyte", 0x55, 3),
+	op("setbyte", 0x56, 3),
+	op("extract", 0x57, 5, imByte, imByte),
+	op("extract3", 0x58, 5),
+	op("extract_uint16", 0x59, 5),
+	op("extract_uint32", 0x5a, 5),
+	op("extract_uint64", 0x5b, 5),
+	op("replace2", 0x5c, 7, imByte),
+	op("replace3", 0x5d, 7),
+	op("base64_decode", 0x5e, 7, imField(base64Encodings)),
+	op("json_ref", 0x5f, 7, imField(jsonRefTypes)),
+	op("balance", 0x60, 2),
+	op("app_opted_in", 0x61, 2),
+	op("app_local_get", 0x62, 2),
+	op("app_local_get_ex", 0x63, 2),
+	op("app_global_get", 0x64, 2),
+	op("app_global_get_ex", 0x65, 2),
+	op("app_local_put", 0x66, 2),
+	op("app_global_put", 0x67, 2),
+	op("app_local_del", 0x68, 2),
+	op("app_global_del", 0x69, 2),
+	op("asset_holding_get", 0x70, 2, imField(assetHoldingFields)),
+	op("asset_params_get", 0x71, 2, imField(assetParamsFields)),
+	op("app_params_get", 0x72, 5, imField(appParamsFields)),
+	op("acct_params_get", 0x73, 6, imField(acctParamsFields)),
+	op("voter_params_get", 0x74, 11, imField(voterParamsFields)),
+	op("online_stake", 0x75, 11),
+	op("min_balance", 0x78, 3),
+	op("pushbytes", 0x80, 3, imBytes),
+	op("pushint", 0x81, 3, imInt),
+	op("pushbytess", 0x82, 8, imBytess),
+	op("pushints", 0x83, 8, imInts),
+	op("ed25519verify_bare", 0x84, 7),
+	op("callsub", 0x88, 4, imLabel),
+	op("retsub", 0x89, 4),
+	op("proto", 0x8a, 8, imByte, imByte),
+	op("frame_dig", 0x8b, 8, imInt8),
+	op("frame_bury", 0x8c, 8, imInt8),
+	op("switch", 0x8d, 8, imLabels),
+	op("match", 0x8e, 8, imLabels),
+	op("shl", 0x90, 4),
+	op("shr", 0x91, 4),
+	op("sqrt", 0x92, 4),
+	op("bitlen", 0x93, 4),
+	op("exp", 0x94, 4),
+	op("expw", 0x95, 4),
+	op("bsqrt", 0x96, 6),
+	op("divw", 0x97, 6),
+	op("sha3_256", 0x98, 7),
+	op("b+", 0xa0, 4),
+	op("b-", 0xa1, 4),
+	op("b/", 0xa2, 4),
+	op("b*", 0xa3, 4),
+	op("b<", 0xa4, 4),
+	op("b>", 0xa5, 4),
+	op("b<=", 0xa6, 4),
+	op("b>=", 0xa7, 4),
+	op("b==", 0xa8, 4),
+	op("b!=", 0xa9, 4),
+	op("b%", 0xaa, 4),
+	op("b|", 0xab, 4),
+	op("b&", 0xac, 4),
+	op("b^", 0xad, 4),
+	op("b~", 0xae, 4),
+	op("bzero", 0xaf, 4),
+	op("log", 0xb0, 5),
+	op("itxn_begin", 0xb1, 5),
+	op("itxn_field", 0xb2, 5, imField(txnFields)),
+	op("itxn_submit", 0xb3, 5),
+	op("itxn", 0xb4, 5, imField(txnFields)),
+	op("itxna", 0xb5, 5, imField(txnFields), imByte),
+	op("itxn_next", 0xb6, 6),
+	op("gitxn", 0xb7, 6, imByte, imField(txnFields)),
+	op("gitxna", 0xb8, 6, imByte, imField(txnFields), imByte),
+	op("box_create", 0xb9, 8),
+	op("box_extract", 0xba, 8),
+	op("box_replace", 0xbb, 8),
+	op("box_del", 0xbc, 8),
+	op("box_len", 0xbd, 8),
+	op("box_get", 0xbe, 8),
+	op("box_put", 0xbf, 8),
+	op("txnas", 0xc0, 5, imField(txnFields)),
+	op("gtxnas", 0xc1, 5, imByte, imField(txnFields)),
+	op("gtxnsas", 0xc2, 5, imField(txnFields)),
+	op("args", 0xc3, 5),
+	op("gloadss", 0xc4, 6),
+	op("itxnas", 0xc5, 6, imField(txnFields)),
+	op("gitxnas", 0xc6, 6, imByte, imField(txnFields)),
+	op("vrf_verify", 0xd0, 7, imField(vrfStandards)),
+	op("block", 0xd1, 7, imField(blockFields)),
+	op("box_splice", 0xd2, 10),
+	op("box_resize", 0xd3, 10),
+	op("ec_add", 0xe0, 10, imField(ecGroups)),
+	op("ec_scalar_mul", 0xe1, 10, imField(ecGroups)),
+	op("ec_pairing_check", 0xe2, 10, imField(ecGroups)),
+	op("ec_multi_scalar_mul", 0xe3, 10, imField(ecGroups)),
+	op("ec_subgroup_check", 0xe4, 10, imField(ecGroups)),
+	op("ec_map_to", 0xe5, 10, imField(ecGroups)),
+	op("mimc", 0xe6, 11, imField(mimcConfigs)),
+}
+
+var (
+	opsByName   = map[string]*opSpec{}
+	opsByOpcode [256]*opSpec
+)
+
+func init() {
+	for i := range opSpecs {
+		spec := &opSpecs[i]
+		opsByName[spec.name] = spec
+		opsByOpcode[spec.opcode] = spec
+	}
+}
+
+// namedInts are the names the int pseudo-op takes for transaction types and
+// OnCompletion values.
+var namedInts = map[string]uint64{
+	"unknown": 0,
+	"pay":     1,
+	"keyreg":  2,
+	"acfg":    3,
+	"axfer":   4,
+	"afrz":    5,
+	"appl":    6,
+	"stpf":    7,
+
+	"NoOp":              0,
+	"OptIn":             1,
+	"CloseOut":          2,
+	"ClearState":        3,
+	"UpdateApplication": 4,
+	"DeleteApplication": 5,
+}
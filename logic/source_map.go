@@ -2,7 +2,10 @@ package logic
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -27,7 +30,15 @@ func DecodeSourceMap(ism map[string]interface{}) (SourceMap, error) {
 		return sm, err
 	}
 
-	err = json.Unmarshal(buff, &sm)
+	return ParseSourceMap(buff)
+}
+
+// ParseSourceMap parses the JSON of a version 3 source map, such as one
+// returned by compile or saved next to a program.
+func ParseSourceMap(data []byte) (SourceMap, error) {
+	var sm SourceMap
+
+	err := json.Unmarshal(data, &sm)
 	if err != nil {
 		return sm, err
 	}
@@ -71,6 +82,73 @@ func (s *SourceMap) GetPcsForLine(line int) []int {
 	return s.LineToPc[line]
 }
 
+// errorPcPattern matches the program counter in the logic errors of algod.
+var errorPcPattern = regexp.MustCompile(`pc=(\d+)`)
+
+// ErrorPc returns the program counter a program failed at from the message of
+// a logic error returned by algod, such as when sending or simulating a
+// transaction.
+func ErrorPc(message string) (int, bool) {
+	match := errorPcPattern.FindStringSubmatch(message)
+	if match == nil {
+		return 0, false
+	}
+	pc, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return pc, true
+}
+
+// LogicError is a logic error of algod located in the TEAL source of the
+// program that failed.
+type LogicError struct {
+	Err error
+	Pc  int
+	// Line is the line of the source, starting at 0 as in the source map
+	Line int
+	// Source is the content of the line, if the source is known
+	Source string
+}
+
+func (e *LogicError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("%v: at line %d", e.Err, e.Line+1)
+	}
+	return fmt.Sprintf("%v: at line %d: %s", e.Err, e.Line+1, e.Source)
+}
+
+func (e *LogicError) Unwrap() error {
+	return e.Err
+}
+
+// MapError locates a logic error of algod in the program of the source map
+// with TEAL source source, which may be empty. Errors without a program
+// counter in the source map are returned unchanged, and others are returned as
+// a *LogicError.
+func (s *SourceMap) MapError(err error, source string) error {
+	if err == nil {
+		return nil
+	}
+	var logicErr *LogicError
+	if errors.As(err, &logicErr) {
+		return err
+	}
+	pc, ok := ErrorPc(err.Error())
+	if !ok {
+		return err
+	}
+	line, ok := s.GetLineForPc(pc)
+	if !ok {
+		return err
+	}
+	mapped := &LogicError{Err: err, Pc: pc, Line: line}
+	if lines := strings.Split(source, "\n"); line < len(lines) {
+		mapped.Source = strings.TrimSpace(lines[line])
+	}
+	return mapped
+}
+
 const (
 	// consts used for vlq encoding/decoding
 	b64table     string = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
@@ -0,0 +1,64 @@
+package logic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSourceMap(t *testing.T) {
+	sm, err := ParseSourceMap([]byte(`{"version": 3, "sources": ["approval.teal"], "names": [], "mappings": ";AAAA;;AAEA;AACA"}`))
+	require.NoError(t, err)
+
+	line, ok := sm.GetLineForPc(1)
+	require.True(t, ok)
+	require.Equal(t, 0, line)
+	line, ok = sm.GetLineForPc(4)
+	require.True(t, ok)
+	require.Equal(t, 3, line)
+	_, ok = sm.GetLineForPc(5)
+	require.False(t, ok)
+	require.Equal(t, []int{0, 1, 2}, sm.GetPcsForLine(0))
+	require.Equal(t, []int{3}, sm.GetPcsForLine(2))
+
+	decoded, err := DecodeSourceMap(map[string]interface{}{"version": 3, "sources": []string{}, "names": []string{}, "mappings": ";AAAA;;AAEA;AACA"})
+	require.NoError(t, err)
+	require.Equal(t, sm.PcToLine, decoded.PcToLine)
+
+	_, err = ParseSourceMap([]byte(`{"version": 2, "mappings": "AAAA"}`))
+	require.Error(t, err)
+}
+
+func TestErrorPc(t *testing.T) {
+	pc, ok := ErrorPc("TransactionPool.Remember: transaction ABC: logic eval error: assert failed pc=29. Details: app=1, pc=29, opcodes=int 1; assert")
+	require.True(t, ok)
+	require.Equal(t, 29, pc)
+
+	_, ok = ErrorPc("overspend")
+	require.False(t, ok)
+}
+
+func TestSourceMapMapError(t *testing.T) {
+	sm, err := ParseSourceMap([]byte(`{"version": 3, "sources": [], "names": [], "mappings": ";AAAA;;AAEA;AACA"}`))
+	require.NoError(t, err)
+	source := "#pragma version 10\nint 1\nbnz done\n  err"
+
+	evalErr := errors.New("logic eval error: err opcode executed pc=4")
+	mapped := sm.MapError(evalErr, source)
+	var logicErr *LogicError
+	require.True(t, errors.As(mapped, &logicErr))
+	require.ErrorIs(t, mapped, evalErr)
+	require.Equal(t, 4, logicErr.Pc)
+	require.Equal(t, 3, logicErr.Line)
+	require.Equal(t, "logic eval error: err opcode executed pc=4: at line 4: err", mapped.Error())
+	require.Equal(t, mapped, sm.MapError(mapped, source))
+
+	require.Equal(t, "logic eval error: err opcode executed pc=4: at line 4", sm.MapError(evalErr, "").Error())
+
+	other := errors.New("overspend")
+	require.Equal(t, other, sm.MapError(other, source))
+	outside := errors.New("logic eval error: pc=40")
+	require.Equal(t, outside, sm.MapError(outside, source))
+	require.NoError(t, sm.MapError(nil, source))
+}
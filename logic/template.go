@@ -0,0 +1,300 @@
+package logic
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// templatePrefix is the prefix of the template variables of TEAL source.
+const templatePrefix = "TMPL_"
+
+// templateKind is the type of value a template variable takes.
+type templateKind int
+
+const (
+	templateInt templateKind = iota
+	templateBytes
+	templateAddr
+)
+
+func (k templateKind) String() string {
+	switch k {
+	case templateInt:
+		return "an integer"
+	case templateBytes:
+		return "bytes"
+	default:
+		return "an address"
+	}
+}
+
+// templateOpKinds are the kinds of the template variables each op takes as an
+// argument.
+var templateOpKinds = map[string]templateKind{
+	"int":        templateInt,
+	"pushint":    templateInt,
+	"pushints":   templateInt,
+	"intcblock":  templateInt,
+	"byte":       templateBytes,
+	"pushbytes":  templateBytes,
+	"pushbytess": templateBytes,
+	"bytecblock": templateBytes,
+	"addr":       templateAddr,
+}
+
+// Template is a program assembled from TEAL source with template variables,
+// written TMPL_<name> as the argument of int, pushint, byte, pushbytes or addr.
+// The variables are placed in the constant blocks of the program, so that
+// Program can substitute their values into the bytecode without assembling
+// the program again, e.g. to parameterize a smart signature.
+type Template struct {
+	version                       int
+	intc                          []uint64
+	bytec                         [][]byte
+	intcTemplates, bytecTemplates map[int]string
+	kinds                         map[string]templateKind
+	code                          []byte
+}
+
+// AssembleTemplate assembles TEAL source with template variables. Sources
+// with an intcblock or bytecblock are not supported.
+func AssembleTemplate(source string) (*Template, error) {
+	a := assembler{version: assemblerDefaultVersion, labels: map[string]int{}, templates: map[string]templateKind{}}
+	if err := a.parse(source); err != nil {
+		return nil, err
+	}
+	a.assignConstants()
+	program, err := a.emit()
+	if err != nil {
+		return nil, err
+	}
+	return &Template{
+		version:        a.version,
+		intc:           a.intc,
+		bytec:          a.bytec,
+		intcTemplates:  a.intcTemplates,
+		bytecTemplates: a.bytecTemplates,
+		kinds:          a.templates,
+		code:           program[a.codeStart:],
+	}, nil
+}
+
+// templateInstruction returns the constant reference of a template variable.
+func (a *assembler) templateInstruction(op string, variable string) (instruction, error) {
+	kind, ok := templateOpKinds[op]
+	if !ok || op == "pushints" || op == "pushbytess" || op == "intcblock" || op == "bytecblock" {
+		return instruction{}, fmt.Errorf("%s is only supported as the argument of int, pushint, byte, pushbytes and addr", variable)
+	}
+	if err := checkTemplateName(variable); err != nil {
+		return instruction{}, err
+	}
+	if previous, ok := a.templates[variable]; ok && previous != kind {
+		return instruction{}, fmt.Errorf("%s is used as both %s and %s", variable, previous, kind)
+	}
+	a.templates[variable] = kind
+	return instruction{ref: &constRef{isBytes: kind != templateInt, template: variable}}, nil
+}
+
+func checkTemplateName(variable string) error {
+	name := strings.TrimPrefix(variable, templatePrefix)
+	if name == "" {
+		return fmt.Errorf("empty template variable name")
+	}
+	for _, c := range name {
+		if !(c == '_' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z') {
+			return fmt.Errorf("invalid template variable %s", variable)
+		}
+	}
+	return nil
+}
+
+// Variables returns the names of the template variables, with their TMPL_
+// prefix, in sorted order.
+func (t *Template) Variables() []string {
+	var names []string
+	for name := range t.kinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Program returns the program with the values of vars substituted for the
+// template variables, as taken by SubstituteTemplate.
+func (t *Template) Program(vars map[string]interface{}) ([]byte, error) {
+	if err := checkTemplateVars(vars, t.kinds); err != nil {
+		return nil, err
+	}
+	program := appendUvarint(nil, uint64(t.version))
+	if len(t.intc) > 0 {
+		program = append(program, opsByName["intcblock"].opcode)
+		program = appendUvarint(program, uint64(len(t.intc)))
+		for i, value := range t.intc {
+			if name, ok := t.intcTemplates[i]; ok {
+				v, _, err := templateValue(vars, name, templateInt)
+				if err != nil {
+					return nil, err
+				}
+				value = v
+			}
+			program = appendUvarint(program, value)
+		}
+	}
+	if len(t.bytec) > 0 {
+		program = append(program, opsByName["bytecblock"].opcode)
+		program = appendUvarint(program, uint64(len(t.bytec)))
+		for i, value := range t.bytec {
+			if name, ok := t.bytecTemplates[i]; ok {
+				_, v, err := templateValue(vars, name, t.kinds[name])
+				if err != nil {
+					return nil, err
+				}
+				value = v
+			}
+			program = appendUvarint(program, uint64(len(value)))
+			program = append(program, value...)
+		}
+	}
+	return append(program, t.code...), nil
+}
+
+// SubstituteTemplate substitutes the values of vars for the template variables
+// of TEAL source, written TMPL_<name> as the argument of int, pushint,
+// intcblock, pushints, byte, pushbytes, bytecblock, pushbytess or addr. The
+// values are keyed by name, with or without the TMPL_ prefix. Integer
+// variables take unsigned or non-negative integers, byte variables take []byte
+// or string, and addr variables take a types.Address or its string form.
+// Missing values, values of the wrong type and values of unknown variables
+// are errors.
+func SubstituteTemplate(source string, vars map[string]interface{}) (string, error) {
+	lines := strings.Split(source, "\n")
+	kinds := map[string]templateKind{}
+	for i, line := range lines {
+		statements, err := tokenizeSpans(line)
+		if err != nil {
+			return "", fmt.Errorf("%d: %w", i+1, err)
+		}
+		var replaced strings.Builder
+		last := 0
+		for _, statement := range statements {
+			op := line[statement[0].start:statement[0].end]
+			if strings.HasSuffix(op, ":") && len(statement) > 1 {
+				statement = statement[1:]
+				op = line[statement[0].start:statement[0].end]
+			}
+			for _, span := range statement[1:] {
+				variable := line[span.start:span.end]
+				if !strings.HasPrefix(variable, templatePrefix) {
+					continue
+				}
+				if err := checkTemplateName(variable); err != nil {
+					return "", fmt.Errorf("%d: %w", i+1, err)
+				}
+				kind, ok := templateOpKinds[op]
+				if !ok {
+					return "", fmt.Errorf("%d: %s cannot be used as an argument of %s", i+1, variable, op)
+				}
+				if previous, ok := kinds[variable]; ok && previous != kind {
+					return "", fmt.Errorf("%d: %s is used as both %s and %s", i+1, variable, previous, kind)
+				}
+				kinds[variable] = kind
+
+				intValue, bytesValue, err := templateValue(vars, variable, kind)
+				if err != nil {
+					return "", fmt.Errorf("%d: %w", i+1, err)
+				}
+				replaced.WriteString(line[last:span.start])
+				switch kind {
+				case templateInt:
+					replaced.WriteString(strconv.FormatUint(intValue, 10))
+				case templateBytes:
+					replaced.WriteString("0x" + hex.EncodeToString(bytesValue))
+				case templateAddr:
+					var addr types.Address
+					copy(addr[:], bytesValue)
+					replaced.WriteString(addr.String())
+				}
+				last = span.end
+			}
+		}
+		if last > 0 {
+			replaced.WriteString(line[last:])
+			lines[i] = replaced.String()
+		}
+	}
+	if err := checkTemplateVars(vars, kinds); err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// checkTemplateVars checks that vars only has values of the template
+// variables kinds.
+func checkTemplateVars(vars map[string]interface{}, kinds map[string]templateKind) error {
+	for name := range vars {
+		if _, ok := kinds[name]; ok {
+			continue
+		}
+		if _, ok := kinds[templatePrefix+name]; ok {
+			continue
+		}
+		return fmt.Errorf("unknown template variable %s", name)
+	}
+	return nil
+}
+
+// templateValue returns the value of the template variable named variable,
+// with its TMPL_ prefix, as an integer or bytes depending on kind.
+func templateValue(vars map[string]interface{}, variable string, kind templateKind) (uint64, []byte, error) {
+	value, ok := vars[variable]
+	if !ok {
+		value, ok = vars[strings.TrimPrefix(variable, templatePrefix)]
+	}
+	if !ok {
+		return 0, nil, fmt.Errorf("no value for template variable %s", variable)
+	}
+
+	switch kind {
+	case templateInt:
+		switch v := value.(type) {
+		case uint64:
+			return v, nil, nil
+		case uint:
+			return uint64(v), nil, nil
+		case uint32:
+			return uint64(v), nil, nil
+		case int:
+			if v >= 0 {
+				return uint64(v), nil, nil
+			}
+		case int64:
+			if v >= 0 {
+				return uint64(v), nil, nil
+			}
+		}
+	case templateBytes:
+		switch v := value.(type) {
+		case []byte:
+			return 0, v, nil
+		case string:
+			return 0, []byte(v), nil
+		}
+	case templateAddr:
+		switch v := value.(type) {
+		case types.Address:
+			return 0, v[:], nil
+		case string:
+			addr, err := types.DecodeAddress(v)
+			if err != nil {
+				return 0, nil, fmt.Errorf("template variable %s: %w", variable, err)
+			}
+			return 0, addr[:], nil
+		}
+	}
+	return 0, nil, fmt.Errorf("template variable %s takes %s, not %T %v", variable, kind, value, value)
+}
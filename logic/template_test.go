@@ -0,0 +1,92 @@
+package logic
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/stretchr/testify/require"
+)
+
+const templateTestSource = `#pragma version 2
+txn Fee
+int TMPL_FEE // the highest fee
+<=
+txn Receiver
+addr TMPL_RECEIVER
+==
+&&
+txn Note; byte TMPL_NOTE
+==
+&&`
+
+func TestSubstituteTemplate(t *testing.T) {
+	var receiver types.Address
+	receiver[0] = 1
+	vars := map[string]interface{}{
+		"TMPL_FEE":  uint64(1000),
+		"RECEIVER":  receiver,
+		"TMPL_NOTE": "hi",
+	}
+
+	source, err := SubstituteTemplate(templateTestSource, vars)
+	require.NoError(t, err)
+	require.Equal(t, `#pragma version 2
+txn Fee
+int 1000 // the highest fee
+<=
+txn Receiver
+addr `+receiver.String()+`
+==
+&&
+txn Note; byte 0x6869
+==
+&&`, source)
+
+	template, err := AssembleTemplate(templateTestSource)
+	require.NoError(t, err)
+	require.Equal(t, []string{"TMPL_FEE", "TMPL_NOTE", "TMPL_RECEIVER"}, template.Variables())
+	program, err := template.Program(vars)
+	require.NoError(t, err)
+	expected, err := Assemble(source)
+	require.NoError(t, err)
+	require.Equal(t, expected, program)
+}
+
+func TestTemplateProgram(t *testing.T) {
+	template, err := AssembleTemplate("#pragma version 6\nint TMPL_FEE\nint 1\n+\nb end\nend:")
+	require.NoError(t, err)
+
+	program, err := template.Program(map[string]interface{}{"FEE": 1000})
+	require.NoError(t, err)
+	require.Equal(t, "062001e80722810108420000", hex.EncodeToString(program))
+	program, err = template.Program(map[string]interface{}{"FEE": uint64(1) << 40})
+	require.NoError(t, err)
+	require.Equal(t, "06200180808080802022810108420000", hex.EncodeToString(program))
+}
+
+func TestTemplateErrors(t *testing.T) {
+	_, err := SubstituteTemplate("int TMPL_FEE", map[string]interface{}{})
+	require.EqualError(t, err, "1: no value for template variable TMPL_FEE")
+	_, err = SubstituteTemplate("int TMPL_FEE", map[string]interface{}{"FEE": "1000"})
+	require.EqualError(t, err, "1: template variable TMPL_FEE takes an integer, not string 1000")
+	_, err = SubstituteTemplate("int TMPL_FEE", map[string]interface{}{"FEE": -1})
+	require.Error(t, err)
+	_, err = SubstituteTemplate("int TMPL_FEE", map[string]interface{}{"FEE": 1, "FE": 2})
+	require.EqualError(t, err, "unknown template variable FE")
+	_, err = SubstituteTemplate("int 1\ntxn TMPL_FIELD", map[string]interface{}{"FIELD": 1})
+	require.EqualError(t, err, "2: TMPL_FIELD cannot be used as an argument of txn")
+	_, err = SubstituteTemplate("int TMPL_X\nbyte TMPL_X", map[string]interface{}{"X": 1})
+	require.EqualError(t, err, "2: TMPL_X is used as both an integer and bytes")
+	_, err = SubstituteTemplate("addr TMPL_A", map[string]interface{}{"A": "not an address"})
+	require.Error(t, err)
+
+	_, err = AssembleTemplate("#pragma version 3\nintcblock 1\nint TMPL_FEE")
+	require.Error(t, err)
+	_, err = AssembleTemplate("#pragma version 3\nintcblock TMPL_FEE")
+	require.Error(t, err)
+	template, err := AssembleTemplate("int TMPL_FEE")
+	require.NoError(t, err)
+	_, err = template.Program(map[string]interface{}{"FEE": []byte{1}})
+	require.Error(t, err)
+}
@@ -0,0 +1,328 @@
+// Package templates provides smart signature templates for common contract
+// accounts, assembled offline with the logic package. Each template is given as
+// TEAL source with TMPL_ variables, and a constructor substituting the
+// parameters of the contract and returning its escrow LogicSigAccount.
+//
+// Every template requires the fee of the transactions it approves to be at
+// most a maximum fee and their RekeyTo to be the zero address.
+package templates
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/logic"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// HTLCSource is the TEAL source of a hash time lock contract. The receiver can
+// close the account to itself with the preimage of the hash image as the first
+// argument. After the expiry round, the owner can close the account to itself.
+const HTLCSource = `#pragma version 8
+txn TypeEnum
+int pay
+==
+txn Fee
+int TMPL_MAX_FEE
+<=
+&&
+txn RekeyTo
+global ZeroAddress
+==
+&&
+txn Amount
+int 0
+==
+&&
+txn Receiver
+global ZeroAddress
+==
+&&
+txn CloseRemainderTo
+addr TMPL_OWNER
+==
+bnz refund
+// the receiver claims the funds with the preimage
+txn CloseRemainderTo
+addr TMPL_RECEIVER
+==
+&&
+arg 0
+sha256
+byte TMPL_HASH_IMAGE
+==
+&&
+return
+// the owner takes the funds back after the expiry round
+refund:
+txn FirstValid
+int TMPL_EXPIRY_ROUND
+>
+&&
+`
+
+// TimeLockSource is the TEAL source of a time-locked escrow. From the unlock
+// round, the receiver can withdraw from the account or close it to itself.
+const TimeLockSource = `#pragma version 8
+txn TypeEnum
+int pay
+==
+txn Fee
+int TMPL_MAX_FEE
+<=
+&&
+txn RekeyTo
+global ZeroAddress
+==
+&&
+txn FirstValid
+int TMPL_UNLOCK_ROUND
+>=
+&&
+txn Receiver
+addr TMPL_RECEIVER
+==
+&&
+txn CloseRemainderTo
+global ZeroAddress
+==
+txn CloseRemainderTo
+addr TMPL_RECEIVER
+==
+||
+&&
+`
+
+// LimitOrderSource is the TEAL source of a limit order selling Algos for an
+// asset. Anyone can buy from the account with a group of two transactions: a
+// payment of at least the minimum trade from the account, and a transfer of
+// the asset to the owner, of at least the payment amount times the ratio
+// TMPL_ASSET_AMOUNT / TMPL_MICROALGO_AMOUNT. After the expiry round, the
+// owner can close the account to itself.
+const LimitOrderSource = `#pragma version 8
+global GroupSize
+int 2
+==
+bnz trade
+// the owner closes the order after the expiry round
+txn TypeEnum
+int pay
+==
+txn Fee
+int TMPL_MAX_FEE
+<=
+&&
+txn RekeyTo
+global ZeroAddress
+==
+&&
+txn Receiver
+global ZeroAddress
+==
+&&
+txn Amount
+int 0
+==
+&&
+txn CloseRemainderTo
+addr TMPL_OWNER
+==
+&&
+txn FirstValid
+int TMPL_EXPIRY_ROUND
+>
+&&
+return
+trade:
+txn GroupIndex
+int 0
+==
+txn TypeEnum
+int pay
+==
+&&
+txn Fee
+int TMPL_MAX_FEE
+<=
+&&
+txn RekeyTo
+global ZeroAddress
+==
+&&
+txn CloseRemainderTo
+global ZeroAddress
+==
+&&
+txn Amount
+int TMPL_MIN_TRADE
+>=
+&&
+gtxn 1 TypeEnum
+int axfer
+==
+&&
+gtxn 1 XferAsset
+int TMPL_ASSET
+==
+&&
+gtxn 1 AssetReceiver
+addr TMPL_OWNER
+==
+&&
+gtxn 1 AssetCloseTo
+global ZeroAddress
+==
+&&
+gtxn 1 AssetAmount
+int TMPL_MICROALGO_AMOUNT
+*
+txn Amount
+int TMPL_ASSET_AMOUNT
+*
+>=
+&&
+`
+
+// RecurringPaymentSource is the TEAL source of a recurring payment. The
+// receiver can withdraw the amount once per period, in a transaction valid
+// from a round multiple of the period for the duration, with the lease
+// preventing withdrawing twice. After the expiry round, the receiver can close
+// the account to itself.
+const RecurringPaymentSource = `#pragma version 8
+txn TypeEnum
+int pay
+==
+txn Fee
+int TMPL_MAX_FEE
+<=
+&&
+txn RekeyTo
+global ZeroAddress
+==
+&&
+txn FirstValid
+int TMPL_PERIOD
+%
+int 0
+==
+&&
+txn LastValid
+txn FirstValid
+int TMPL_DURATION
++
+==
+&&
+txn Lease
+byte TMPL_LEASE
+==
+&&
+// withdraw the amount
+txn CloseRemainderTo
+global ZeroAddress
+==
+txn Receiver
+addr TMPL_RECEIVER
+==
+&&
+txn Amount
+int TMPL_AMOUNT
+==
+&&
+// close to the receiver after the expiry round
+txn CloseRemainderTo
+addr TMPL_RECEIVER
+==
+txn Receiver
+global ZeroAddress
+==
+&&
+txn Amount
+int 0
+==
+&&
+txn FirstValid
+int TMPL_EXPIRY_ROUND
+>
+&&
+||
+&&
+`
+
+// makeAccount returns the escrow account of a template with its variables
+// set to vars.
+func makeAccount(source string, vars map[string]interface{}) (crypto.LogicSigAccount, error) {
+	substituted, err := logic.SubstituteTemplate(source, vars)
+	if err != nil {
+		return crypto.LogicSigAccount{}, err
+	}
+	program, err := logic.Assemble(substituted)
+	if err != nil {
+		return crypto.LogicSigAccount{}, err
+	}
+	return crypto.MakeLogicSigAccountEscrowChecked(program, nil)
+}
+
+// MakeHTLC returns the escrow account of a hash time lock contract, see
+// HTLCSource. hashImage is the SHA-256 hash of the preimage the receiver
+// claims the funds with, by setting the arguments of the account to
+// [][]byte{preimage}.
+func MakeHTLC(owner, receiver types.Address, hashImage []byte, expiryRound, maxFee uint64) (crypto.LogicSigAccount, error) {
+	if len(hashImage) != sha256.Size {
+		return crypto.LogicSigAccount{}, fmt.Errorf("hash image must be %d bytes, not %d", sha256.Size, len(hashImage))
+	}
+	return makeAccount(HTLCSource, map[string]interface{}{
+		"OWNER":        owner,
+		"RECEIVER":     receiver,
+		"HASH_IMAGE":   hashImage,
+		"EXPIRY_ROUND": expiryRound,
+		"MAX_FEE":      maxFee,
+	})
+}
+
+// MakeTimeLock returns the escrow account of a time-locked escrow, see
+// TimeLockSource.
+func MakeTimeLock(receiver types.Address, unlockRound, maxFee uint64) (crypto.LogicSigAccount, error) {
+	return makeAccount(TimeLockSource, map[string]interface{}{
+		"RECEIVER":     receiver,
+		"UNLOCK_ROUND": unlockRound,
+		"MAX_FEE":      maxFee,
+	})
+}
+
+// MakeLimitOrder returns the escrow account of a limit order selling Algos for
+// the asset assetID at a ratio of assetAmount units of the asset for
+// microAlgoAmount microAlgos, see LimitOrderSource.
+func MakeLimitOrder(owner types.Address, assetID, assetAmount, microAlgoAmount, minTrade, expiryRound, maxFee uint64) (crypto.LogicSigAccount, error) {
+	if assetAmount == 0 || microAlgoAmount == 0 {
+		return crypto.LogicSigAccount{}, fmt.Errorf("the ratio of the limit order must be positive")
+	}
+	return makeAccount(LimitOrderSource, map[string]interface{}{
+		"OWNER":            owner,
+		"ASSET":            assetID,
+		"ASSET_AMOUNT":     assetAmount,
+		"MICROALGO_AMOUNT": microAlgoAmount,
+		"MIN_TRADE":        minTrade,
+		"EXPIRY_ROUND":     expiryRound,
+		"MAX_FEE":          maxFee,
+	})
+}
+
+// MakeRecurringPayment returns the escrow account of a recurring payment of
+// amount microAlgos to receiver every period rounds, see
+// RecurringPaymentSource. Withdrawals must be valid for duration rounds from a
+// multiple of period, with the lease lease.
+func MakeRecurringPayment(receiver types.Address, amount, period, duration uint64, lease [32]byte, expiryRound, maxFee uint64) (crypto.LogicSigAccount, error) {
+	if period == 0 {
+		return crypto.LogicSigAccount{}, fmt.Errorf("the period must be positive")
+	}
+	return makeAccount(RecurringPaymentSource, map[string]interface{}{
+		"RECEIVER":     receiver,
+		"AMOUNT":       amount,
+		"PERIOD":       period,
+		"DURATION":     duration,
+		"LEASE":        lease[:],
+		"EXPIRY_ROUND": expiryRound,
+		"MAX_FEE":      maxFee,
+	})
+}
@@ -0,0 +1,207 @@
+package templates
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/logic"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/stretchr/testify/require"
+)
+
+// checkTemplate checks that the program of account fits in a logic signature
+// and returns its disassembly.
+func checkTemplate(t *testing.T, account crypto.LogicSigAccount) string {
+	program := account.Lsig.Logic
+	address, err := account.Address()
+	require.NoError(t, err)
+	require.Equal(t, logic.ProgramAddress(program), address)
+
+	analysis, err := logic.Analyze(program)
+	require.NoError(t, err)
+	require.True(t, analysis.Bounded)
+	require.Empty(t, analysis.CheckLogicSig(types.Consensus[types.ConsensusCurrentVersion]))
+
+	source, err := logic.Disassemble(program)
+	require.NoError(t, err)
+	return source
+}
+
+// evalTemplate evaluates the program of account as the logic signature of
+// group[index] with the arguments args, and returns whether it passes.
+func evalTemplate(t *testing.T, account crypto.LogicSigAccount, group []types.Transaction, index int, args ...[]byte) bool {
+	result, err := logic.EvalLogicSig(account.Lsig.Logic, logic.EvalParams{TxnGroup: group, GroupIndex: index, Args: args})
+	require.NoError(t, err)
+	return result.Pass
+}
+
+func TestMakeHTLC(t *testing.T) {
+	owner, receiver := crypto.GenerateAccount().Address, crypto.GenerateAccount().Address
+	hashImage := sha256.Sum256([]byte("secret"))
+
+	account, err := MakeHTLC(owner, receiver, hashImage[:], 1000, 2000)
+	require.NoError(t, err)
+	checkTemplate(t, account)
+
+	source, err := logic.SubstituteTemplate(HTLCSource, map[string]interface{}{
+		"OWNER": owner, "RECEIVER": receiver, "HASH_IMAGE": hashImage[:], "EXPIRY_ROUND": 1000, "MAX_FEE": 2000,
+	})
+	require.NoError(t, err)
+	program, err := logic.Assemble(source)
+	require.NoError(t, err)
+	require.Equal(t, program, account.Lsig.Logic)
+
+	other, err := MakeHTLC(owner, receiver, hashImage[:], 1001, 2000)
+	require.NoError(t, err)
+	require.NotEqual(t, account.Lsig.Logic, other.Lsig.Logic)
+
+	_, err = MakeHTLC(owner, receiver, []byte("short"), 1000, 2000)
+	require.Error(t, err)
+}
+
+func TestHTLCEval(t *testing.T) {
+	owner, receiver := crypto.GenerateAccount().Address, crypto.GenerateAccount().Address
+	hashImage := sha256.Sum256([]byte("secret"))
+	account, err := MakeHTLC(owner, receiver, hashImage[:], 1000, 2000)
+	require.NoError(t, err)
+	address, err := account.Address()
+	require.NoError(t, err)
+
+	txn := types.Transaction{
+		Type: types.PaymentTx,
+		Header: types.Header{
+			Sender:     address,
+			Fee:        1000,
+			FirstValid: 500,
+			LastValid:  1500,
+		},
+		PaymentTxnFields: types.PaymentTxnFields{CloseRemainderTo: receiver},
+	}
+	group := []types.Transaction{txn}
+	require.True(t, evalTemplate(t, account, group, 0, []byte("secret")))
+	// claiming with the wrong preimage
+	require.False(t, evalTemplate(t, account, group, 0, []byte("guess")))
+	// claiming to another account
+	group[0].CloseRemainderTo = owner
+	require.False(t, evalTemplate(t, account, group, 0, []byte("secret")))
+
+	// refunding before and at the expiry round
+	require.False(t, evalTemplate(t, account, group, 0))
+	group[0].FirstValid = 1000
+	require.False(t, evalTemplate(t, account, group, 0))
+	group[0].FirstValid = 1001
+	require.True(t, evalTemplate(t, account, group, 0))
+	// refunding with a fee above the maximum
+	group[0].Fee = 2001
+	require.False(t, evalTemplate(t, account, group, 0))
+}
+
+func TestMakeTimeLock(t *testing.T) {
+	receiver := crypto.GenerateAccount().Address
+	account, err := MakeTimeLock(receiver, 5000, 1000)
+	require.NoError(t, err)
+	require.Contains(t, checkTemplate(t, account), "5000")
+}
+
+func TestMakeLimitOrder(t *testing.T) {
+	owner := crypto.GenerateAccount().Address
+	account, err := MakeLimitOrder(owner, 12, 3, 1000000, 100000, 5000, 1000)
+	require.NoError(t, err)
+	checkTemplate(t, account)
+
+	_, err = MakeLimitOrder(owner, 12, 0, 1000000, 100000, 5000, 1000)
+	require.Error(t, err)
+}
+
+func TestLimitOrderEval(t *testing.T) {
+	owner, buyer := crypto.GenerateAccount().Address, crypto.GenerateAccount().Address
+	// 3 units of the asset 12 per Algo
+	account, err := MakeLimitOrder(owner, 12, 3, 1000000, 100000, 5000, 1000)
+	require.NoError(t, err)
+	address, err := account.Address()
+	require.NoError(t, err)
+
+	group := []types.Transaction{
+		{
+			Type:             types.PaymentTx,
+			Header:           types.Header{Sender: address, Fee: 1000, FirstValid: 100, LastValid: 1100},
+			PaymentTxnFields: types.PaymentTxnFields{Receiver: buyer, Amount: 2000000},
+		},
+		{
+			Type:   types.AssetTransferTx,
+			Header: types.Header{Sender: buyer, Fee: 1000, FirstValid: 100, LastValid: 1100},
+			AssetTransferTxnFields: types.AssetTransferTxnFields{
+				XferAsset:     12,
+				AssetAmount:   6,
+				AssetReceiver: owner,
+			},
+		},
+	}
+	require.True(t, evalTemplate(t, account, group, 0))
+	// filling below the ratio
+	group[1].AssetAmount = 5
+	require.False(t, evalTemplate(t, account, group, 0))
+	// filling at the ratio below the minimum trade
+	group[0].Amount, group[1].AssetAmount = 10000, 1
+	require.False(t, evalTemplate(t, account, group, 0))
+	// paying with another asset
+	group[0].Amount, group[1].AssetAmount, group[1].XferAsset = 2000000, 6, 13
+	require.False(t, evalTemplate(t, account, group, 0))
+
+	// closing the order before and after the expiry round
+	closing := []types.Transaction{{
+		Type:             types.PaymentTx,
+		Header:           types.Header{Sender: address, Fee: 1000, FirstValid: 4000, LastValid: 5000},
+		PaymentTxnFields: types.PaymentTxnFields{CloseRemainderTo: owner},
+	}}
+	require.False(t, evalTemplate(t, account, closing, 0))
+	closing[0].FirstValid = 5001
+	require.True(t, evalTemplate(t, account, closing, 0))
+}
+
+func TestMakeRecurringPayment(t *testing.T) {
+	receiver := crypto.GenerateAccount().Address
+	lease := [32]byte{1, 2, 3}
+	account, err := MakeRecurringPayment(receiver, 500000, 100, 10, lease, 100000, 1000)
+	require.NoError(t, err)
+	require.Contains(t, checkTemplate(t, account), "500000")
+
+	_, err = MakeRecurringPayment(receiver, 500000, 0, 10, lease, 100000, 1000)
+	require.Error(t, err)
+}
+
+func TestRecurringPaymentEval(t *testing.T) {
+	receiver := crypto.GenerateAccount().Address
+	lease := [32]byte{1, 2, 3}
+	account, err := MakeRecurringPayment(receiver, 500000, 100, 10, lease, 100000, 1000)
+	require.NoError(t, err)
+	address, err := account.Address()
+	require.NoError(t, err)
+
+	group := []types.Transaction{{
+		Type: types.PaymentTx,
+		Header: types.Header{
+			Sender:     address,
+			Fee:        1000,
+			FirstValid: 200,
+			LastValid:  210,
+			Lease:      lease,
+		},
+		PaymentTxnFields: types.PaymentTxnFields{Receiver: receiver, Amount: 500000},
+	}}
+	require.True(t, evalTemplate(t, account, group, 0))
+	// withdrawing with the wrong lease
+	group[0].Lease = [32]byte{4}
+	require.False(t, evalTemplate(t, account, group, 0))
+	group[0].Lease = lease
+	// withdrawing the wrong amount
+	group[0].Amount = 500001
+	require.False(t, evalTemplate(t, account, group, 0))
+	group[0].Amount = 500000
+	// withdrawing outside of the period
+	group[0].FirstValid, group[0].LastValid = 250, 260
+	require.False(t, evalTemplate(t, account, group, 0))
+	group[0].FirstValid, group[0].LastValid = 200, 300
+	require.False(t, evalTemplate(t, account, group, 0))
+}
@@ -0,0 +1,212 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+	"github.com/algorand/go-algorand-sdk/v2/logic"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// arc56Actions maps the OnComplete actions of ARC-56 specifications to their
+// values.
+var arc56Actions = map[string]types.OnCompletion{
+	"NoOp":              types.NoOpOC,
+	"OptIn":             types.OptInOC,
+	"CloseOut":          types.CloseOutOC,
+	"UpdateApplication": types.UpdateApplicationOC,
+	"DeleteApplication": types.DeleteApplicationOC,
+}
+
+// AppClient adds calls to the methods of an app described by an ARC-56
+// specification to an AtomicTransactionComposer. Compared to AddMethodCall, it
+// looks methods up by name or signature, fills in the programs and schema
+// when creating the app, uses literal default values for omitted arguments,
+// and encodes and decodes struct arguments and return values from Go structs.
+type AppClient struct {
+	// The specification of the app
+	Spec abi.Arc56Contract
+	// The ID of the app, or 0 to create it
+	AppID uint64
+	// The sender of the method calls
+	Sender types.Address
+	// A transaction Signer that can authorize the method calls from Sender
+	Signer TransactionSigner
+	// The source map of the approval program, used by MapError if set
+	SourceMap *logic.SourceMap
+}
+
+// NewAppClient returns an AppClient calling the app appID described by spec
+// from sender.
+func NewAppClient(spec abi.Arc56Contract, appID uint64, sender types.Address, signer TransactionSigner) *AppClient {
+	return &AppClient{Spec: spec, AppID: appID, Sender: sender, Signer: signer}
+}
+
+// AddMethodCall adds a call to the method with the name or signature method to
+// atc. A nil argument takes its literal default value. Arguments of struct
+// types may be given as Go structs, as taken by abi.Marshal.
+//
+// The call is made with OnComplete onComplete, which must be one of the actions
+// of the method. When AppID is 0, the call creates the app with the programs
+// and schema of the specification, and with UpdateApplication, it updates the
+// app to the programs of the specification.
+func (c *AppClient) AddMethodCall(atc *AtomicTransactionComposer, method string, args []interface{}, onComplete types.OnCompletion, sp types.SuggestedParams) error {
+	spec, err := c.Spec.GetMethodByNameOrSignature(method)
+	if err != nil {
+		return err
+	}
+	if len(args) != len(spec.Args) {
+		return fmt.Errorf("the incorrect number of arguments were provided: %d != %d", len(args), len(spec.Args))
+	}
+
+	actions := spec.Actions.Call
+	if c.AppID == 0 {
+		actions = spec.Actions.Create
+	}
+	allowed := false
+	for _, action := range actions {
+		if oc, ok := arc56Actions[action]; ok && oc == onComplete {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("method %s does not allow OnComplete %d", spec.Name, onComplete)
+	}
+
+	params := AddMethodCallParams{
+		AppID:           c.AppID,
+		Method:          spec.Method(),
+		MethodArgs:      make([]interface{}, len(args)),
+		Sender:          c.Sender,
+		SuggestedParams: sp,
+		OnComplete:      onComplete,
+		Signer:          c.Signer,
+	}
+	for i, arg := range spec.Args {
+		if params.MethodArgs[i], err = c.methodArg(arg, args[i]); err != nil {
+			return fmt.Errorf("argument %d of method %s: %w", i, spec.Name, err)
+		}
+	}
+
+	if c.AppID == 0 || onComplete == types.UpdateApplicationOC {
+		if c.Spec.ByteCode == nil {
+			return fmt.Errorf("the specification of %s has no bytecode to create or update the app with", c.Spec.Name)
+		}
+		if params.ApprovalProgram, params.ClearProgram, err = c.Spec.ByteCode.Decode(); err != nil {
+			return err
+		}
+	}
+	if c.AppID == 0 {
+		schema := c.Spec.State.Schema
+		params.GlobalSchema = types.StateSchema{NumUint: schema.Global.Ints, NumByteSlice: schema.Global.Bytes}
+		params.LocalSchema = types.StateSchema{NumUint: schema.Local.Ints, NumByteSlice: schema.Local.Bytes}
+		params.ExtraPages = ExtraProgramPages(params.ApprovalProgram, params.ClearProgram)
+	}
+
+	return atc.AddMethodCall(params)
+}
+
+// ExtraProgramPages returns the number of extra pages an app with the approval
+// and clear programs approval and clear must be created with.
+func ExtraProgramPages(approval, clear []byte) uint32 {
+	pageLen := types.Consensus[types.ConsensusCurrentVersion].MaxAppProgramLen
+	total := len(approval) + len(clear)
+	if total <= pageLen {
+		return 0
+	}
+	return uint32((total - 1) / pageLen)
+}
+
+// methodArg returns the value of an argument as taken by AddMethodCall.
+func (c *AppClient) methodArg(arg abi.Arc56MethodArg, value interface{}) (interface{}, error) {
+	if abi.IsTransactionType(arg.Type) || abi.IsReferenceType(arg.Type) {
+		return value, nil
+	}
+	abiType, err := abi.TypeOf(arg.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		if arg.DefaultValue == nil || arg.DefaultValue.Source != "literal" {
+			return nil, fmt.Errorf("no value and no literal default value")
+		}
+		encoded, err := base64.StdEncoding.DecodeString(arg.DefaultValue.Data)
+		if err != nil {
+			return nil, err
+		}
+		defaultType := abiType
+		if arg.DefaultValue.Type != "" {
+			if defaultType, err = abi.TypeOf(arg.DefaultValue.Type); err != nil {
+				return nil, err
+			}
+		}
+		return defaultType.Decode(encoded)
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || v.Type().PkgPath() == "math/big" {
+		return value, nil
+	}
+	encoded, err := abi.Marshal(abiType, value)
+	if err != nil {
+		return nil, err
+	}
+	return abiType.Decode(encoded)
+}
+
+// DecodeReturnValue decodes the return value of a method call made by the
+// client into the value pointed to by v, as abi.Unmarshal does, e.g. into a Go
+// struct for methods returning a struct.
+func (c *AppClient) DecodeReturnValue(result ABIMethodResult, v interface{}) error {
+	if result.DecodeError != nil {
+		return result.DecodeError
+	}
+	abiType, err := result.Method.Returns.GetTypeObject()
+	if err != nil {
+		return err
+	}
+	return abi.Unmarshal(abiType, result.RawReturnValue, v)
+}
+
+// ErrorMessage returns the error message the specification gives for the
+// approval program failing at the program counter pc.
+func (c *AppClient) ErrorMessage(pc uint64) (string, bool) {
+	if c.Spec.SourceInfo == nil {
+		return "", false
+	}
+	return c.Spec.SourceInfo.Approval.ErrorMessage(pc)
+}
+
+// MapError locates a logic error of the approval program, such as one returned
+// by Execute or found in the DecodeError of a simulated method call. With a
+// SourceMap, the error becomes a *logic.LogicError giving the line of the TEAL
+// source of the specification that failed, and the error message the
+// specification gives for the failing program counter is appended.
+func (c *AppClient) MapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if c.SourceMap != nil {
+		var source string
+		if c.Spec.Source != nil {
+			if approval, _, decodeErr := c.Spec.Source.Decode(); decodeErr == nil {
+				source = string(approval)
+			}
+		}
+		err = c.SourceMap.MapError(err, source)
+	}
+	pc, ok := logic.ErrorPc(err.Error())
+	if !ok {
+		return err
+	}
+	if message, ok := c.ErrorMessage(uint64(pc)); ok {
+		return fmt.Errorf("%w: %s", err, message)
+	}
+	return err
+}
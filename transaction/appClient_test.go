@@ -0,0 +1,127 @@
+package transaction
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/abi"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/logic"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/stretchr/testify/require"
+)
+
+const appClientTestSpec = `{
+  "arcs": [4, 56],
+  "name": "Orders",
+  "structs": {"Price": [{"name": "amount", "type": "ufixed64x2"}, {"name": "unit", "type": "string"}]},
+  "methods": [
+    {"name": "create", "args": [], "returns": {"type": "void"}, "actions": {"create": ["NoOp"], "call": []}},
+    {"name": "quote", "args": [{"type": "(ufixed64x2,string)", "struct": "Price", "name": "price"},
+                               {"type": "uint64", "name": "qty", "defaultValue": {"data": "AAAAAAAAAAM=", "source": "literal"}}],
+     "returns": {"type": "(ufixed64x2,string)", "struct": "Price"}, "actions": {"create": [], "call": ["NoOp"]}},
+    {"name": "update", "args": [], "returns": {"type": "void"}, "actions": {"create": [], "call": ["UpdateApplication"]}}
+  ],
+  "state": {"schema": {"global": {"ints": 1, "bytes": 2}, "local": {"ints": 3, "bytes": 4}},
+            "keys": {"global": {}, "local": {}, "box": {}}, "maps": {"global": {}, "local": {}, "box": {}}},
+  "bareActions": {"create": [], "call": []},
+  "sourceInfo": {"approval": {"pcOffsetMethod": "none", "sourceInfo": [{"pc": [9], "errorMessage": "bad unit"}]},
+                 "clear": {"pcOffsetMethod": "none", "sourceInfo": []}},
+  "byteCode": {"approval": "CoEBQw==", "clear": "CoEBQw=="}
+}`
+
+type appClientTestPrice struct {
+	Amount *big.Rat
+	Unit   string
+}
+
+func TestAppClient(t *testing.T) {
+	spec, err := abi.ParseArc56([]byte(appClientTestSpec))
+	require.NoError(t, err)
+	account := crypto.GenerateAccount()
+	signer := BasicAccountTransactionSigner{Account: account}
+	sp := types.SuggestedParams{Fee: 1000, FirstRoundValid: 1, LastRoundValid: 1001, FlatFee: true}
+
+	var atc AtomicTransactionComposer
+	creator := NewAppClient(spec, 0, account.Address, signer)
+	require.Error(t, creator.AddMethodCall(&atc, "quote", []interface{}{nil, nil}, types.NoOpOC, sp))
+	require.Error(t, creator.AddMethodCall(&atc, "create", nil, types.OptInOC, sp))
+	require.NoError(t, creator.AddMethodCall(&atc, "create", nil, types.NoOpOC, sp))
+
+	client := NewAppClient(spec, 12, account.Address, signer)
+	price := appClientTestPrice{Amount: big.NewRat(5, 4), Unit: "usd"}
+	require.NoError(t, client.AddMethodCall(&atc, "quote((ufixed64x2,string),uint64)(ufixed64x2,string)", []interface{}{price, nil}, types.NoOpOC, sp))
+	require.Error(t, client.AddMethodCall(&atc, "quote", []interface{}{nil, nil}, types.NoOpOC, sp))
+
+	group, err := atc.BuildGroup()
+	require.NoError(t, err)
+	require.Len(t, group, 2)
+
+	create := group[0].Txn
+	require.Equal(t, types.AppIndex(0), create.ApplicationID)
+	require.Equal(t, []byte{0x0a, 0x81, 0x01, 0x43}, create.ApprovalProgram)
+	require.Equal(t, types.StateSchema{NumUint: 1, NumByteSlice: 2}, create.GlobalStateSchema)
+	require.Equal(t, types.StateSchema{NumUint: 3, NumByteSlice: 4}, create.LocalStateSchema)
+
+	call := group[1].Txn
+	require.Equal(t, types.AppIndex(12), call.ApplicationID)
+	require.Len(t, call.ApplicationArgs, 3)
+	priceType, err := spec.StructType("Price")
+	require.NoError(t, err)
+	var encodedPrice appClientTestPrice
+	require.NoError(t, abi.Unmarshal(priceType, call.ApplicationArgs[1], &encodedPrice))
+	require.Zero(t, price.Amount.Cmp(encodedPrice.Amount))
+	require.Equal(t, "usd", encodedPrice.Unit)
+	require.Equal(t, uint64(3), binary.BigEndian.Uint64(call.ApplicationArgs[2]))
+
+	rawReturn, err := abi.Marshal(priceType, price)
+	require.NoError(t, err)
+	result := ABIMethodResult{Method: spec.Methods[1].Method(), RawReturnValue: rawReturn}
+	var returned appClientTestPrice
+	require.NoError(t, client.DecodeReturnValue(result, &returned))
+	require.Zero(t, price.Amount.Cmp(returned.Amount))
+	require.Equal(t, "usd", returned.Unit)
+
+	message, ok := client.ErrorMessage(9)
+	require.True(t, ok)
+	require.Equal(t, "bad unit", message)
+
+	sourceMap, err := logic.ParseSourceMap([]byte(`{"version": 3, "sources": [], "names": [], "mappings": ";AAAA;;AACA;;;;;;AACA"}`))
+	require.NoError(t, err)
+	client.SourceMap = &sourceMap
+	client.Spec.Source = &abi.Arc56Programs{Approval: "I3ByYWdtYSB2ZXJzaW9uIDEwCmludCAxCmFzc2VydA==", Clear: "I3ByYWdtYSB2ZXJzaW9uIDEw"}
+	sendErr := errors.New("logic eval error: assert failed pc=9")
+	mapped := client.MapError(sendErr)
+	require.ErrorIs(t, mapped, sendErr)
+	var logicErr *logic.LogicError
+	require.True(t, errors.As(mapped, &logicErr))
+	require.Equal(t, 2, logicErr.Line)
+	require.Equal(t, "logic eval error: assert failed pc=9: at line 3: assert: bad unit", mapped.Error())
+}
+
+func TestAppClientUpdate(t *testing.T) {
+	spec, err := abi.ParseArc56([]byte(appClientTestSpec))
+	require.NoError(t, err)
+	account := crypto.GenerateAccount()
+	sp := types.SuggestedParams{Fee: 1000, FirstRoundValid: 1, LastRoundValid: 1001, FlatFee: true}
+
+	var atc AtomicTransactionComposer
+	client := NewAppClient(spec, 12, account.Address, BasicAccountTransactionSigner{Account: account})
+	require.Error(t, client.AddMethodCall(&atc, "update", nil, types.NoOpOC, sp))
+	require.NoError(t, client.AddMethodCall(&atc, "update", nil, types.UpdateApplicationOC, sp))
+	group, err := atc.BuildGroup()
+	require.NoError(t, err)
+	update := group[0].Txn
+	require.Equal(t, types.UpdateApplicationOC, update.OnCompletion)
+	require.Equal(t, []byte{0x0a, 0x81, 0x01, 0x43}, update.ApprovalProgram)
+	require.Equal(t, []byte{0x0a, 0x81, 0x01, 0x43}, update.ClearStateProgram)
+	require.Equal(t, types.StateSchema{}, update.GlobalStateSchema)
+}
+
+func TestExtraProgramPages(t *testing.T) {
+	require.Equal(t, uint32(0), ExtraProgramPages(make([]byte, 2000), make([]byte, 48)))
+	require.Equal(t, uint32(1), ExtraProgramPages(make([]byte, 2000), make([]byte, 49)))
+	require.Equal(t, uint32(3), ExtraProgramPages(make([]byte, 8000), make([]byte, 192)))
+}
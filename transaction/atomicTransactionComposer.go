@@ -1,7 +1,6 @@
 package transaction
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,12 +9,10 @@ import (
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
 	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
 	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
 	"github.com/algorand/go-algorand-sdk/v2/types"
 )
 
-// abiReturnHash is the 4-byte prefix for logged return values, from https://github.com/algorandfoundation/ARCs/blob/main/ARCs/arc-0004.md#standard-format
-var abiReturnHash = []byte{0x15, 0x1f, 0x7c, 0x75}
-
 // maxAppArgs is the maximum number of arguments for an application call transaction at the time
 // ARC-4 was created
 const maxAppArgs = 16
@@ -114,6 +111,35 @@ type ExecuteResult struct {
 	MethodResults []ABIMethodResult
 }
 
+// MethodArgError is returned by AtomicTransactionComposer.AddMethodCall for an argument which
+// cannot be passed to the method, e.g. a value out of the bounds of its type or a reference
+// beyond the limits of an application call.
+type MethodArgError struct {
+	// The signature of the method
+	Method string
+	// The index of the argument
+	Index int
+	// The name of the argument, if it has one
+	Name string
+	// The type of the argument
+	Type string
+	// The reason the argument is invalid
+	Err error
+}
+
+func (e *MethodArgError) Error() string {
+	name := ""
+	if e.Name != "" {
+		name = fmt.Sprintf(" %q", e.Name)
+	}
+	return fmt.Sprintf("argument %d%s of method %s, of type %s: %v", e.Index, name, e.Method, e.Type, e.Err)
+}
+
+// Unwrap returns the reason the argument is invalid.
+func (e *MethodArgError) Unwrap() error {
+	return e.Err
+}
+
 // AtomicTransactionComposerStatus represents the status of an AtomicTransactionComposer
 type AtomicTransactionComposerStatus = int
 
@@ -254,7 +280,7 @@ func (atc *AtomicTransactionComposer) AddMethodCall(params AddMethodCallParams)
 	}
 
 	if len(params.MethodArgs) != len(params.Method.Args) {
-		return fmt.Errorf("the incorrect number of arguments were provided: %d != %d", len(params.MethodArgs), len(params.Method.Args))
+		return fmt.Errorf("the incorrect number of arguments were provided: %d != %d for method %s", len(params.MethodArgs), len(params.Method.Args), params.Method.GetSignature())
 	}
 
 	if atc.Count()+params.Method.GetTxCount() > MaxAtomicGroupSize {
@@ -276,24 +302,41 @@ func (atc *AtomicTransactionComposer) AddMethodCall(params AddMethodCallParams)
 		return fmt.Errorf("ApprovalProgram, ClearProgram, GlobalSchema, and LocalSchema must not be provided for a non-creation call")
 	}
 
+	// copy foreign arrays before resolving reference arguments into them
+	foreign := abi.ForeignReferences{
+		Accounts: make([]types.Address, len(params.ForeignAccounts)),
+		Apps:     make([]uint64, len(params.ForeignApps)),
+		Assets:   make([]uint64, len(params.ForeignAssets)),
+	}
+	for i, account := range params.ForeignAccounts {
+		address, err := types.DecodeAddress(account)
+		if err != nil {
+			return fmt.Errorf("invalid foreign account %s: %w", account, err)
+		}
+		foreign.Accounts[i] = address
+	}
+	copy(foreign.Apps, params.ForeignApps)
+	copy(foreign.Assets, params.ForeignAssets)
+
+	limits := types.Consensus[types.ConsensusCurrentVersion]
 	var txsToAdd []TransactionWithSigner
 	var basicArgValues []interface{}
 	var basicArgTypes []abi.Type
-	var refArgValues []interface{}
-	var refArgTypes []string
-	refArgIndexToBasicArgIndex := make(map[int]int)
 	for i, arg := range params.Method.Args {
 		argValue := params.MethodArgs[i]
+		argError := func(err error) error {
+			return &MethodArgError{Method: params.Method.GetSignature(), Index: i, Name: arg.Name, Type: arg.Type, Err: err}
+		}
 
 		if arg.IsTransactionArg() {
 			txnAndSigner, ok := argValue.(TransactionWithSigner)
 			if !ok {
-				return fmt.Errorf("invalid arg type, expected transaction")
+				return argError(fmt.Errorf("invalid arg type, expected transaction, got %T", argValue))
 			}
 
 			err := atc.validateTransaction(txnAndSigner.Txn, arg.Type)
 			if err != nil {
-				return err
+				return argError(err)
 			}
 			txsToAdd = append(txsToAdd, txnAndSigner)
 		} else {
@@ -301,17 +344,27 @@ func (atc *AtomicTransactionComposer) AddMethodCall(params AddMethodCallParams)
 			var err error
 
 			if arg.IsReferenceArg() {
-				refArgIndexToBasicArgIndex[len(refArgTypes)] = len(basicArgTypes)
-				refArgValues = append(refArgValues, argValue)
-				refArgTypes = append(refArgTypes, arg.Type)
+				// use the foreign array index as the encoded argument value
+				argValue, err = foreign.Resolve(arg.Type, argValue, params.Sender, params.AppID)
+				if err != nil {
+					return argError(err)
+				}
+				if arg.Type == abi.AccountReferenceType && argValue.(int) > limits.MaxAppTxnAccounts {
+					return argError(fmt.Errorf("an application call can reference at most %d accounts", limits.MaxAppTxnAccounts))
+				}
 
 				// treat the reference as a uint8 for encoding purposes
 				abiType, err = abi.TypeOf("uint8")
 			} else {
 				abiType, err = arg.GetTypeObject()
+				if err == nil {
+					// encode the value now to report out of bounds values
+					// along with the argument
+					_, err = abi.AppendEncode(nil, abiType, argValue)
+				}
 			}
 			if err != nil {
-				return err
+				return argError(err)
 			}
 
 			basicArgValues = append(basicArgValues, argValue)
@@ -319,30 +372,16 @@ func (atc *AtomicTransactionComposer) AddMethodCall(params AddMethodCallParams)
 		}
 	}
 
-	// copy foreign arrays before modifying in populateMethodCallReferenceArgs
-	foreignAccounts := make([]string, len(params.ForeignAccounts))
-	copy(foreignAccounts, params.ForeignAccounts)
-	foreignApps := make([]uint64, len(params.ForeignApps))
-	copy(foreignApps, params.ForeignApps)
-	foreignAssets := make([]uint64, len(params.ForeignAssets))
-	copy(foreignAssets, params.ForeignAssets)
-
-	refArgsResolved, err := populateMethodCallReferenceArgs(
-		params.Sender.String(),
-		params.AppID,
-		refArgTypes,
-		refArgValues,
-		&foreignAccounts,
-		&foreignApps,
-		&foreignAssets,
-	)
-	if err != nil {
-		return err
+	foreignAccounts := make([]string, len(foreign.Accounts))
+	for i, account := range foreign.Accounts {
+		foreignAccounts[i] = account.String()
 	}
-	for i, resolved := range refArgsResolved {
-		basicArgIndex := refArgIndexToBasicArgIndex[i]
-		// use the foreign array index as the encoded argument value
-		basicArgValues[basicArgIndex] = resolved
+	foreignApps, foreignAssets := foreign.Apps, foreign.Assets
+
+	totalRefs := len(foreignAccounts) + len(foreignApps) + len(foreignAssets) + len(params.BoxReferences)
+	if totalRefs > limits.MaxAppTotalTxnReferences {
+		return fmt.Errorf("method %s references %d accounts, %d apps, %d assets and %d boxes, more than the %d references an application call can have",
+			params.Method.GetSignature(), len(foreignAccounts), len(foreignApps), len(foreignAssets), len(params.BoxReferences), limits.MaxAppTotalTxnReferences)
 	}
 
 	// Up to 16 app arguments can be passed to app call. First is reserved for method selector,
@@ -368,7 +407,7 @@ func (atc *AtomicTransactionComposer) AddMethodCall(params AddMethodCallParams)
 	encodedAbiArgs := [][]byte{params.Method.GetSelector()}
 
 	for i, abiArg := range basicArgValues {
-		encodedArg, err := basicArgTypes[i].Encode(abiArg)
+		encodedArg, err := abi.AppendEncode(nil, basicArgTypes[i], abiArg)
 		if err != nil {
 			return err
 		}
@@ -642,163 +681,82 @@ func (atc *AtomicTransactionComposer) Execute(client *algod.Client, ctx context.
 			result.TransactionInfo = methodCallInfo
 		}
 
-		if txContext.method.Returns.IsVoid() {
-			result.RawReturnValue = []byte{}
-			executeResponse.MethodResults = append(executeResponse.MethodResults, result)
-			continue
-		}
-
-		if len(result.TransactionInfo.Logs) == 0 {
-			result.DecodeError = errors.New("method call did not log a return value")
-			executeResponse.MethodResults = append(executeResponse.MethodResults, result)
-			continue
-		}
-
-		lastLog := result.TransactionInfo.Logs[len(result.TransactionInfo.Logs)-1]
-		if !bytes.HasPrefix(lastLog, abiReturnHash) {
-			result.DecodeError = errors.New("method call did not log a return value")
-			executeResponse.MethodResults = append(executeResponse.MethodResults, result)
-			continue
-		}
-
-		result.RawReturnValue = lastLog[len(abiReturnHash):]
-
-		abiType, err := txContext.method.Returns.GetTypeObject()
-		if err != nil {
-			result.DecodeError = err
-			executeResponse.MethodResults = append(executeResponse.MethodResults, result)
-			break
-		}
-
-		result.ReturnValue, result.DecodeError = abiType.Decode(result.RawReturnValue)
+		result.decodeReturnValue()
 		executeResponse.MethodResults = append(executeResponse.MethodResults, result)
 	}
 
 	return executeResponse, nil
 }
 
-// marshallAbiUint64 converts any value used to represent an ABI "uint64" into
-// a golang uint64
-func marshallAbiUint64(value interface{}) (uint64, error) {
-	abiType, err := abi.TypeOf("uint64")
-	if err != nil {
-		return 0, err
-	}
-	encoded, err := abiType.Encode(value)
-	if err != nil {
-		return 0, err
-	}
-	decoded, err := abiType.Decode(encoded)
-	if err != nil {
-		return 0, err
-	}
-	marshalledValue, ok := decoded.(uint64)
-	if !ok {
-		err = fmt.Errorf("Decoded value is not a uint64")
-	}
-	return marshalledValue, err
+// SimulateAtomicTransactionResponse contains the results of successfully calling the Simulate
+// method on an AtomicTransactionComposer object.
+type SimulateAtomicTransactionResponse struct {
+	// The full response from the simulate endpoint
+	SimulateResponse models.SimulateResponse
+	// For each ABI method call in the simulated group (created by the AddMethodCall method), this
+	// slice contains information about the method call's return value
+	MethodResults []ABIMethodResult
 }
 
-// marshallAbiAddress converts any value used to represent an ABI "address" into
-// a golang address string
-func marshallAbiAddress(value interface{}) (string, error) {
-	abiType, err := abi.TypeOf("address")
+// Simulate simulates the transaction group against the network without submitting it. The
+// transactions are signed with each transaction's signer before simulating, so pair this with
+// EmptyTransactionSigner and request.AllowEmptySignatures to simulate without real signatures.
+//
+// Any TxnGroups set on the request are replaced with this composer's group.
+//
+// The composer's status will be at least SIGNED after executing this method, and does not change
+// as a result of the simulation.
+func (atc *AtomicTransactionComposer) Simulate(client *algod.Client, ctx context.Context, request models.SimulateRequest) (SimulateAtomicTransactionResponse, error) {
+	stxs, err := atc.GatherSignatures()
 	if err != nil {
-		return "", err
+		return SimulateAtomicTransactionResponse{}, err
 	}
-	encoded, err := abiType.Encode(value)
-	if err != nil {
-		return "", err
+
+	txnObjects := make([]types.SignedTxn, len(stxs))
+	for i, stx := range stxs {
+		err = msgpack.Decode(stx, &txnObjects[i])
+		if err != nil {
+			return SimulateAtomicTransactionResponse{}, err
+		}
 	}
-	decoded, err := abiType.Decode(encoded)
+
+	request.TxnGroups = []models.SimulateRequestTransactionGroup{{Txns: txnObjects}}
+	simulateResponse, err := client.SimulateTransaction(request).Do(ctx)
 	if err != nil {
-		return "", err
+		return SimulateAtomicTransactionResponse{}, err
 	}
-	marshalledValue, ok := decoded.([]byte)
-	if !ok || len(marshalledValue) != len(types.ZeroAddress) {
-		err = fmt.Errorf("Decoded value is not a 32 length byte slice")
+	if len(simulateResponse.TxnGroups) != 1 {
+		return SimulateAtomicTransactionResponse{}, fmt.Errorf("expected 1 simulated transaction group, got %d", len(simulateResponse.TxnGroups))
+	}
+	groupResult := simulateResponse.TxnGroups[0]
+	if len(groupResult.TxnResults) != len(atc.txContexts) {
+		return SimulateAtomicTransactionResponse{}, fmt.Errorf("expected %d simulated transaction results, got %d", len(atc.txContexts), len(groupResult.TxnResults))
 	}
-	var addressValue types.Address
-	copy(addressValue[:], marshalledValue)
-	return addressValue.String(), err
-}
-
-// populateMethodCallReferenceArgs parses reference argument types and resolves them to an index
-// into the appropriate foreign array. Their placement will be as compact as possible, which means
-// values will be deduplicated and any value that is the sender or the current app will not be added
-// to the foreign array.
-func populateMethodCallReferenceArgs(sender string, currentApp uint64, types []string, values []interface{}, accounts *[]string, apps *[]uint64, assets *[]uint64) ([]int, error) {
-	resolvedIndexes := make([]int, len(types))
-
-	for i, value := range values {
-		var resolved int
 
-		switch types[i] {
-		case abi.AccountReferenceType:
-			address, err := marshallAbiAddress(value)
-			if err != nil {
-				return nil, err
-			}
-			if address == sender {
-				resolved = 0
-			} else {
-				duplicate := false
-				for j, account := range *accounts {
-					if address == account {
-						resolved = j + 1 // + 1 because 0 is the sender
-						duplicate = true
-						break
-					}
-				}
-				if !duplicate {
-					resolved = len(*accounts) + 1
-					*accounts = append(*accounts, address)
-				}
-			}
-		case abi.ApplicationReferenceType:
-			appID, err := marshallAbiUint64(value)
-			if err != nil {
-				return nil, err
-			}
-			if appID == currentApp {
-				resolved = 0
-			} else {
-				duplicate := false
-				for j, app := range *apps {
-					if appID == app {
-						resolved = j + 1 // + 1 because 0 is the current app
-						duplicate = true
-						break
-					}
-				}
-				if !duplicate {
-					resolved = len(*apps) + 1
-					*apps = append(*apps, appID)
-				}
-			}
-		case abi.AssetReferenceType:
-			assetID, err := marshallAbiUint64(value)
-			if err != nil {
-				return nil, err
-			}
-			duplicate := false
-			for j, asset := range *assets {
-				if assetID == asset {
-					resolved = j
-					duplicate = true
-					break
-				}
-			}
-			if !duplicate {
-				resolved = len(*assets)
-				*assets = append(*assets, assetID)
-			}
-		default:
-			return nil, fmt.Errorf("Unknown reference type: %s", types[i])
+	simulateATCResponse := SimulateAtomicTransactionResponse{SimulateResponse: simulateResponse}
+	for i, txContext := range atc.txContexts {
+		if !txContext.isMethodCallTx() {
+			continue
 		}
 
-		resolvedIndexes[i] = resolved
+		result := ABIMethodResult{
+			TxID:            txContext.txID(),
+			TransactionInfo: models.PendingTransactionInfoResponse(groupResult.TxnResults[i].TxnResult),
+			Method:          *txContext.method,
+		}
+		if groupResult.FailureMessage == "" {
+			result.decodeReturnValue()
+		} else {
+			result.DecodeError = fmt.Errorf("simulation failed: %s", groupResult.FailureMessage)
+		}
+		simulateATCResponse.MethodResults = append(simulateATCResponse.MethodResults, result)
 	}
 
-	return resolvedIndexes, nil
+	return simulateATCResponse, nil
+}
+
+// decodeReturnValue populates the raw and decoded return values of the method result from the
+// logs in its TransactionInfo, recording any failure in DecodeError.
+func (result *ABIMethodResult) decodeReturnValue() {
+	result.RawReturnValue, result.ReturnValue, result.DecodeError = result.Method.DecodeReturnValue(result.TransactionInfo.Logs)
 }
@@ -1,10 +1,18 @@
 package transaction
 
 import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/algorand/go-algorand-sdk/v2/abi"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
 	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
 	"github.com/algorand/go-algorand-sdk/v2/types"
 	"github.com/stretchr/testify/require"
 )
@@ -204,6 +212,73 @@ func TestAddMethodCallWithManualForeignArgs(t *testing.T) {
 	require.Equal(t, txns[0].Txn.Accounts[0], arg_addr)
 }
 
+func TestAddMethodCallInvalidArgs(t *testing.T) {
+	account := crypto.GenerateAccount()
+	txSigner := BasicAccountTransactionSigner{Account: account}
+
+	method, err := abi.MethodFromSignature("pay(uint8,account,pay)void")
+	require.NoError(t, err)
+	method.Args[0].Name = "amount"
+
+	payment := TransactionWithSigner{
+		Txn:    types.Transaction{Type: types.PaymentTx},
+		Signer: txSigner,
+	}
+	other := crypto.GenerateAccount().Address
+
+	tests := []struct {
+		args  []interface{}
+		index int
+	}{
+		{[]interface{}{256, other, payment}, 0},
+		{[]interface{}{-1, other, payment}, 0},
+		{[]interface{}{1, "not an address", payment}, 1},
+		{[]interface{}{1, other, 3}, 2},
+	}
+	for _, test := range tests {
+		var atc AtomicTransactionComposer
+		err = atc.AddMethodCall(AddMethodCallParams{
+			AppID:      4,
+			Method:     method,
+			MethodArgs: test.args,
+			Sender:     account.Address,
+			Signer:     txSigner,
+		})
+		var argErr *MethodArgError
+		require.ErrorAs(t, err, &argErr)
+		require.Equal(t, test.index, argErr.Index)
+		require.Equal(t, method.Args[test.index].Type, argErr.Type)
+		require.Contains(t, err.Error(), "pay(uint8,account,pay)void")
+	}
+	require.Contains(t, err.Error(), "invalid arg type, expected transaction")
+
+	var atc AtomicTransactionComposer
+	err = atc.AddMethodCall(AddMethodCallParams{
+		AppID:           4,
+		Method:          method,
+		MethodArgs:      []interface{}{1, other, payment},
+		Sender:          account.Address,
+		Signer:          txSigner,
+		ForeignAccounts: []string{crypto.GenerateAccount().Address.String(), crypto.GenerateAccount().Address.String(), crypto.GenerateAccount().Address.String(), crypto.GenerateAccount().Address.String()},
+	})
+	var argErr *MethodArgError
+	require.ErrorAs(t, err, &argErr)
+	require.Equal(t, 1, argErr.Index)
+
+	err = atc.AddMethodCall(AddMethodCallParams{
+		AppID:         4,
+		Method:        method,
+		MethodArgs:    []interface{}{1, other, payment},
+		Sender:        account.Address,
+		Signer:        txSigner,
+		ForeignApps:   []uint64{1, 2, 3, 4},
+		ForeignAssets: []uint64{5, 6, 7, 8},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "more than the 8 references")
+	require.Equal(t, 0, atc.Count())
+}
+
 func TestGatherSignatures(t *testing.T) {
 	var atc AtomicTransactionComposer
 	account := crypto.GenerateAccount()
@@ -251,3 +326,110 @@ func TestGatherSignatures(t *testing.T) {
 	require.Equal(t, len(sigs[0]), len(expectedSig))
 	require.Equal(t, sigs[0], expectedSig)
 }
+
+func TestSimulate(t *testing.T) {
+	method, err := abi.MethodFromSignature("add(uint64,uint64)uint64")
+	require.NoError(t, err)
+	sender, err := types.DecodeAddress("DN7MBMCL5JQ3PFUQS7TMX5AH4EEKOBJVDUF4TCV6WERATKFLQF4MQUPZTA")
+	require.NoError(t, err)
+	params := types.SuggestedParams{
+		Fee:             1000,
+		FlatFee:         true,
+		FirstRoundValid: 1,
+		LastRoundValid:  1001,
+		GenesisHash:     make([]byte, 32),
+	}
+
+	makeATC := func() *AtomicTransactionComposer {
+		var atc AtomicTransactionComposer
+		err := atc.AddTransaction(TransactionWithSigner{
+			Txn: types.Transaction{
+				Type: types.PaymentTx,
+				Header: types.Header{
+					Sender:     sender,
+					Fee:        1000,
+					FirstValid: 1,
+					LastValid:  1001,
+				},
+				PaymentTxnFields: types.PaymentTxnFields{Receiver: sender, Amount: 1},
+			},
+			Signer: EmptyTransactionSigner{},
+		})
+		require.NoError(t, err)
+		err = atc.AddMethodCall(AddMethodCallParams{
+			AppID:           4,
+			Method:          method,
+			MethodArgs:      []interface{}{2, 3},
+			Sender:          sender,
+			SuggestedParams: params,
+			Signer:          EmptyTransactionSigner{},
+		})
+		require.NoError(t, err)
+		return &atc
+	}
+
+	returnLog := []byte{0x15, 0x1f, 0x7c, 0x75, 0, 0, 0, 0, 0, 0, 0, 5}
+	var requests []string
+	var bodies [][]byte
+	var response models.SimulateResponse
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, body)
+		w.Write(json.Encode(response))
+	}))
+	defer server.Close()
+	client, err := algod.MakeClient(server.URL, "")
+	require.NoError(t, err)
+
+	response = models.SimulateResponse{
+		LastRound: 10,
+		TxnGroups: []models.SimulateTransactionGroupResult{{
+			TxnResults: []models.SimulateTransactionResult{
+				{},
+				{TxnResult: models.PendingTransactionResponse{Logs: [][]byte{returnLog}}},
+			},
+		}},
+	}
+	atc := makeATC()
+	request := models.SimulateRequest{
+		AllowEmptySignatures: true,
+		TxnGroups:            []models.SimulateRequestTransactionGroup{{}, {}},
+	}
+	result, err := atc.Simulate(client, context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, SIGNED, atc.GetStatus())
+
+	require.Equal(t, []string{"POST /v2/transactions/simulate"}, requests)
+	var sent models.SimulateRequest
+	require.NoError(t, msgpack.Decode(bodies[0], &sent))
+	require.True(t, sent.AllowEmptySignatures)
+	require.Len(t, sent.TxnGroups, 1)
+	txns := sent.TxnGroups[0].Txns
+	require.Len(t, txns, 2)
+	require.Equal(t, types.PaymentTx, txns[0].Txn.Type)
+	require.Equal(t, types.ApplicationCallTx, txns[1].Txn.Type)
+	require.Equal(t, types.Signature{}, txns[1].Sig)
+	require.Equal(t, txns[0].Txn.Group, txns[1].Txn.Group)
+
+	require.Equal(t, uint64(10), result.SimulateResponse.LastRound)
+	require.Len(t, result.MethodResults, 1)
+	methodResult := result.MethodResults[0]
+	require.NoError(t, methodResult.DecodeError)
+	require.Equal(t, crypto.TransactionIDString(txns[1].Txn), methodResult.TxID)
+	require.Equal(t, "add", methodResult.Method.Name)
+	require.Equal(t, returnLog[4:], methodResult.RawReturnValue)
+	require.Equal(t, uint64(5), methodResult.ReturnValue)
+
+	response.TxnGroups[0].FailureMessage = "transaction rejected by ApprovalProgram"
+	response.TxnGroups[0].FailedAt = []uint64{1}
+	result, err = makeATC().Simulate(client, context.Background(), request)
+	require.NoError(t, err)
+	require.Len(t, result.MethodResults, 1)
+	require.EqualError(t, result.MethodResults[0].DecodeError, "simulation failed: transaction rejected by ApprovalProgram")
+	require.Nil(t, result.MethodResults[0].ReturnValue)
+
+	response.TxnGroups[0].TxnResults = response.TxnGroups[0].TxnResults[:1]
+	_, err = makeATC().Simulate(client, context.Background(), request)
+	require.EqualError(t, err, "expected 2 simulated transaction results, got 1")
+}
@@ -0,0 +1,145 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// ARC2Format is the format of the data of an ARC-2 note.
+type ARC2Format byte
+
+// The formats of ARC-2 notes.
+const (
+	ARC2MsgPack ARC2Format = 'm'
+	ARC2JSON    ARC2Format = 'j'
+	ARC2Bytes   ARC2Format = 'b'
+	ARC2UTF8    ARC2Format = 'u'
+)
+
+// arc2DappNamePattern matches the dapp names of ARC-2 notes: 5 to 32
+// characters, starting with a letter or digit.
+var arc2DappNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_/@.-]{4,31}$`)
+
+// ARC2Note is a transaction note following ARC-2, of the form
+// <dapp-name>:<format><data>, so that explorers and indexers can attribute
+// and decode it.
+type ARC2Note struct {
+	DappName string
+	Format   ARC2Format
+	Data     []byte
+}
+
+// ValidateARC2DappName returns an error when name is not a valid ARC-2 dapp
+// name: 5 to 32 letters, digits and any of _/@.- characters, starting with a
+// letter or digit.
+func ValidateARC2DappName(name string) error {
+	if !arc2DappNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid ARC-2 dapp name %q: it must be 5 to 32 letters, digits or _/@.- characters, starting with a letter or digit", name)
+	}
+	return nil
+}
+
+func (f ARC2Format) validate(data []byte) error {
+	switch f {
+	case ARC2MsgPack:
+		var v interface{}
+		if err := msgpack.Decode(data, &v); err != nil {
+			return fmt.Errorf("invalid ARC-2 msgpack data: %w", err)
+		}
+	case ARC2JSON:
+		if !json.Valid(data) {
+			return fmt.Errorf("invalid ARC-2 JSON data")
+		}
+	case ARC2UTF8:
+		if !utf8.Valid(data) {
+			return fmt.Errorf("invalid ARC-2 UTF-8 data")
+		}
+	case ARC2Bytes:
+	default:
+		return fmt.Errorf("unknown ARC-2 format %q", byte(f))
+	}
+	return nil
+}
+
+// Encode returns the note as the Note of a transaction, validating the dapp
+// name, that the data is in the format of the note, and that the note fits in
+// the note of a transaction.
+func (n ARC2Note) Encode() ([]byte, error) {
+	if err := ValidateARC2DappName(n.DappName); err != nil {
+		return nil, err
+	}
+	if err := n.Format.validate(n.Data); err != nil {
+		return nil, err
+	}
+	note := make([]byte, 0, len(n.DappName)+2+len(n.Data))
+	note = append(note, n.DappName...)
+	note = append(note, ':', byte(n.Format))
+	note = append(note, n.Data...)
+	maxLen := types.Consensus[types.ConsensusCurrentVersion].MaxTxnNoteBytes
+	if len(note) > maxLen {
+		return nil, fmt.Errorf("ARC-2 note of %d bytes exceeds the maximum note length %d", len(note), maxLen)
+	}
+	return note, nil
+}
+
+// Decode decodes the data of a note in the JSON or msgpack format into the
+// value pointed to by v.
+func (n ARC2Note) Decode(v interface{}) error {
+	switch n.Format {
+	case ARC2JSON:
+		return json.Unmarshal(n.Data, v)
+	case ARC2MsgPack:
+		return msgpack.Decode(n.Data, v)
+	}
+	return fmt.Errorf("cannot decode ARC-2 format %q", byte(n.Format))
+}
+
+// MakeARC2Note returns the note with dapp name dappName of data in the format
+// format.
+func MakeARC2Note(dappName string, format ARC2Format, data []byte) ([]byte, error) {
+	return ARC2Note{DappName: dappName, Format: format, Data: data}.Encode()
+}
+
+// MakeARC2JSONNote returns the note with dapp name dappName of v encoded to
+// JSON.
+func MakeARC2JSONNote(dappName string, v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return MakeARC2Note(dappName, ARC2JSON, data)
+}
+
+// MakeARC2MsgPackNote returns the note with dapp name dappName of v encoded to
+// msgpack.
+func MakeARC2MsgPackNote(dappName string, v interface{}) ([]byte, error) {
+	return MakeARC2Note(dappName, ARC2MsgPack, msgpack.Encode(v))
+}
+
+// ParseARC2Note parses the Note of a transaction following ARC-2. It returns
+// an error when the note does not follow ARC-2, such as when its dapp name is
+// invalid or its data is not in its format.
+func ParseARC2Note(note []byte) (ARC2Note, error) {
+	colon := bytes.IndexByte(note, ':')
+	if colon < 0 || colon+1 >= len(note) {
+		return ARC2Note{}, fmt.Errorf("note is not an ARC-2 note")
+	}
+	n := ARC2Note{
+		DappName: string(note[:colon]),
+		Format:   ARC2Format(note[colon+1]),
+		Data:     note[colon+2:],
+	}
+	if err := ValidateARC2DappName(n.DappName); err != nil {
+		return ARC2Note{}, err
+	}
+	if err := n.Format.validate(n.Data); err != nil {
+		return ARC2Note{}, err
+	}
+	return n, nil
+}
@@ -0,0 +1,71 @@
+package transaction
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestARC2Note(t *testing.T) {
+	note, err := MakeARC2Note("my-dapp", ARC2UTF8, []byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, "my-dapp:uhello", string(note))
+
+	parsed, err := ParseARC2Note(note)
+	require.NoError(t, err)
+	require.Equal(t, ARC2Note{DappName: "my-dapp", Format: ARC2UTF8, Data: []byte("hello")}, parsed)
+
+	type payload struct {
+		Kind  string `json:"kind" codec:"kind"`
+		Count int    `json:"count" codec:"count"`
+	}
+	note, err = MakeARC2JSONNote("algo.example", payload{Kind: "vote", Count: 3})
+	require.NoError(t, err)
+	require.Equal(t, `algo.example:j{"kind":"vote","count":3}`, string(note))
+	parsed, err = ParseARC2Note(note)
+	require.NoError(t, err)
+	var decoded payload
+	require.NoError(t, parsed.Decode(&decoded))
+	require.Equal(t, payload{Kind: "vote", Count: 3}, decoded)
+
+	note, err = MakeARC2MsgPackNote("dapp_1", payload{Kind: "vote", Count: 3})
+	require.NoError(t, err)
+	require.Equal(t, "dapp_1:m", string(note[:8]))
+	require.Equal(t, msgpack.Encode(payload{Kind: "vote", Count: 3}), note[8:])
+	parsed, err = ParseARC2Note(note)
+	require.NoError(t, err)
+	decoded = payload{}
+	require.NoError(t, parsed.Decode(&decoded))
+	require.Equal(t, payload{Kind: "vote", Count: 3}, decoded)
+
+	note, err = MakeARC2Note("dapp_1", ARC2Bytes, []byte{0xff, 0x00})
+	require.NoError(t, err)
+	parsed, err = ParseARC2Note(note)
+	require.NoError(t, err)
+	require.Error(t, parsed.Decode(&decoded))
+}
+
+func TestARC2NoteErrors(t *testing.T) {
+	for _, name := range []string{"", "dapp", "-dapp", "dapp name", "dapp:x", strings.Repeat("a", 33)} {
+		require.Error(t, ValidateARC2DappName(name), name)
+	}
+	for _, name := range []string{"dapp1", "a/b@c.d-e_f", strings.Repeat("a", 32)} {
+		require.NoError(t, ValidateARC2DappName(name), name)
+	}
+
+	_, err := MakeARC2Note("my-dapp", ARC2Format('x'), nil)
+	require.Error(t, err)
+	_, err = MakeARC2Note("my-dapp", ARC2JSON, []byte("{"))
+	require.Error(t, err)
+	_, err = MakeARC2Note("my-dapp", ARC2UTF8, []byte{0xff})
+	require.Error(t, err)
+	_, err = MakeARC2Note("my-dapp", ARC2Bytes, make([]byte, 1024))
+	require.Error(t, err)
+
+	for _, note := range []string{"no colon", "my-dapp:", "dap:uhello", "my-dapp:j{", "my-dapp:xdata"} {
+		_, err = ParseARC2Note([]byte(note))
+		require.Error(t, err, note)
+	}
+}
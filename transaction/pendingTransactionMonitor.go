@@ -0,0 +1,122 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+)
+
+// PendingTransactionEventType is the kind of change observed for a monitored
+// transaction.
+type PendingTransactionEventType int
+
+const (
+	// PendingTransactionSeen the transaction appeared in the node's transaction pool.
+	PendingTransactionSeen PendingTransactionEventType = iota
+	// PendingTransactionConfirmed the transaction was committed in a block.
+	PendingTransactionConfirmed
+	// PendingTransactionRejected the transaction was removed from the pool with a pool error.
+	PendingTransactionRejected
+)
+
+// PendingTransactionEvent is passed to the callback of
+// MonitorPendingTransactions when a monitored transaction changes state.
+type PendingTransactionEvent struct {
+	Type PendingTransactionEventType
+	TxID string
+
+	// Info the pending transaction information, set for confirmed and rejected
+	// transactions.
+	Info models.PendingTransactionInfoResponse
+}
+
+// `MonitorPendingTransactions` polls the pending transactions of a sender once per round and
+// calls `notify` when any of the given transactions appears in the pool, is confirmed or is rejected.
+// `sender`: The address whose pending transactions are polled
+// `txids`: The IDs of the transactions to monitor
+// `notify`: Called with each event, in the order they are observed
+// `waitRounds`: The number of rounds to block before exiting with an error.
+func MonitorPendingTransactions(c *algod.Client, sender string, txids []string, notify func(PendingTransactionEvent), waitRounds uint64, ctx context.Context, headers ...*common.Header) error {
+	response, err := c.Status().Do(ctx, headers...)
+	if err != nil {
+		return err
+	}
+
+	lastRound := response.LastRound
+	currentRound := lastRound + 1
+
+	seen := make(map[string]bool)
+	remaining := make(map[string]bool)
+	for _, txid := range txids {
+		remaining[txid] = true
+	}
+
+	for {
+		_, pending, err := c.PendingTransactionsByAddress(sender).Do(ctx, headers...)
+		if err != nil {
+			return err
+		}
+
+		inPool := make(map[string]bool)
+		for _, stxn := range pending {
+			inPool[crypto.TransactionIDString(stxn.Txn)] = true
+		}
+
+		// Preserve the order of txids when reporting events
+		for _, txid := range txids {
+			if !remaining[txid] {
+				continue
+			}
+
+			if inPool[txid] {
+				if !seen[txid] {
+					seen[txid] = true
+					notify(PendingTransactionEvent{Type: PendingTransactionSeen, TxID: txid})
+				}
+				continue
+			}
+
+			// ignore errors from PendingTransactionInformation, the transaction may not have
+			// reached this node yet
+			txInfo, _, err := c.PendingTransactionInformation(txid).Do(ctx, headers...)
+			if err != nil {
+				continue
+			}
+
+			if len(txInfo.PoolError) != 0 {
+				delete(remaining, txid)
+				notify(PendingTransactionEvent{Type: PendingTransactionRejected, TxID: txid, Info: txInfo})
+			} else if txInfo.ConfirmedRound > 0 {
+				delete(remaining, txid)
+				notify(PendingTransactionEvent{Type: PendingTransactionConfirmed, TxID: txid, Info: txInfo})
+			}
+		}
+
+		if len(remaining) == 0 {
+			return nil
+		}
+
+		// Check that the `waitRounds` has not passed
+		if currentRound > lastRound+waitRounds {
+			var ids []string
+			for _, txid := range txids {
+				if remaining[txid] {
+					ids = append(ids, txid)
+				}
+			}
+			return fmt.Errorf("Monitoring transaction ids %s timed out", strings.Join(ids, ", "))
+		}
+
+		// Wait until the block for the `currentRound` is confirmed
+		if _, err = c.StatusAfterBlock(currentRound).Do(ctx, headers...); err != nil {
+			return err
+		}
+
+		currentRound += 1
+	}
+}
@@ -0,0 +1,108 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+func TestMonitorPendingTransactions(t *testing.T) {
+	txns := proofTestTxns()[:3]
+	var ids []string
+	for _, txn := range txns {
+		ids = append(ids, crypto.TransactionIDString(txn))
+	}
+
+	// pool contents and transaction info by round, starting at round 100
+	pools := [][]int{{0, 1}, {1}, {}}
+	infos := []map[int]models.PendingTransactionInfoResponse{
+		{},
+		{0: {ConfirmedRound: 101}, 2: {PoolError: "overspend"}},
+		{0: {ConfirmedRound: 101}, 1: {ConfirmedRound: 102}, 2: {PoolError: "overspend"}},
+	}
+
+	round := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/status/wait-for-block-after/"):
+			round++
+			fmt.Fprintf(w, `{"last-round":%d}`, 100+round)
+		case r.URL.Path == "/v2/status":
+			fmt.Fprintf(w, `{"last-round":%d}`, 100+round)
+		case strings.HasPrefix(r.URL.Path, "/v2/accounts/"):
+			var response models.PendingTransactionsResponse
+			for _, i := range pools[round] {
+				response.TopTransactions = append(response.TopTransactions, types.SignedTxn{Txn: txns[i]})
+			}
+			w.Write(msgpack.Encode(response))
+		case strings.HasPrefix(r.URL.Path, "/v2/transactions/pending/"):
+			txid := strings.TrimPrefix(r.URL.Path, "/v2/transactions/pending/")
+			for i, id := range ids {
+				if info, ok := infos[round][i]; ok && id == txid {
+					w.Write(msgpack.Encode(info))
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := algod.MakeClient(server.URL, "")
+	require.NoError(t, err)
+
+	var events []PendingTransactionEvent
+	err = MonitorPendingTransactions(client, "sender", ids, func(event PendingTransactionEvent) {
+		events = append(events, event)
+	}, 5, context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, events, 5)
+	expected := []struct {
+		eventType PendingTransactionEventType
+		txid      string
+	}{
+		{PendingTransactionSeen, ids[0]},
+		{PendingTransactionSeen, ids[1]},
+		{PendingTransactionConfirmed, ids[0]},
+		{PendingTransactionRejected, ids[2]},
+		{PendingTransactionConfirmed, ids[1]},
+	}
+	for i, e := range expected {
+		require.Equal(t, e.eventType, events[i].Type)
+		require.Equal(t, e.txid, events[i].TxID)
+	}
+	require.Equal(t, "overspend", events[3].Info.PoolError)
+	require.Equal(t, uint64(102), events[4].Info.ConfirmedRound)
+}
+
+func TestMonitorPendingTransactionsTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/status"):
+			fmt.Fprint(w, `{"last-round":100}`)
+		case strings.HasPrefix(r.URL.Path, "/v2/accounts/"):
+			w.Write(msgpack.Encode(models.PendingTransactionsResponse{}))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := algod.MakeClient(server.URL, "")
+	require.NoError(t, err)
+
+	err = MonitorPendingTransactions(client, "sender", []string{"TXID"}, func(PendingTransactionEvent) {}, 2, context.Background())
+	require.EqualError(t, err, "Monitoring transaction ids TXID timed out")
+}
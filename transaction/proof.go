@@ -0,0 +1,108 @@
+package transaction
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+const (
+	// ProofHashTypeSha512_256 is the hash type of the block's native transaction commitment
+	ProofHashTypeSha512_256 = "sha512_256"
+	// ProofHashTypeSha256 is the hash type of the block's sha256 vector commitment
+	ProofHashTypeSha256 = "sha256"
+)
+
+var (
+	txnMerkleLeafPrefix = []byte("TL")
+	merkleNodePrefix    = []byte("MA")
+)
+
+// VerifyTransactionProof checks that a proof returned by algod's
+// GetTransactionProof endpoint proves the membership of txn in the block
+// with the given header. The proof is verified against the header's native
+// sha512_256 or sha256 transaction commitment depending on proof.Hashtype.
+func VerifyTransactionProof(txn types.Transaction, proof models.TransactionProofResponse, header types.BlockHeader) error {
+	var newHash func() hash.Hash
+	var root types.Digest
+	var txid []byte
+	position := proof.Idx
+
+	switch proof.Hashtype {
+	case "", ProofHashTypeSha512_256:
+		newHash = sha512.New512_256
+		root = header.TxnCommitments.NativeSha512_256Commitment
+		txid = crypto.TransactionID(txn)
+	case ProofHashTypeSha256:
+		newHash = sha256.New
+		root = header.TxnCommitments.Sha256Commitment
+		h := sha256.New()
+		h.Write([]byte("TX"))
+		h.Write(msgpack.Encode(txn))
+		txid = h.Sum(nil)
+		// leaves of the sha256 vector commitment are stored in bit-reversed order
+		position = reverseBits(proof.Idx, proof.Treedepth)
+	default:
+		return fmt.Errorf("unsupported proof hash type: %s", proof.Hashtype)
+	}
+
+	size := newHash().Size()
+	if uint64(len(proof.Proof)) != proof.Treedepth*uint64(size) {
+		return fmt.Errorf("proof length %d does not match tree depth %d", len(proof.Proof), proof.Treedepth)
+	}
+	if len(proof.Stibhash) != size {
+		return fmt.Errorf("stibhash length %d does not match hash size %d", len(proof.Stibhash), size)
+	}
+
+	node := hashParts(newHash, txnMerkleLeafPrefix, txid, proof.Stibhash)
+	zero := make([]byte, size)
+	for i := uint64(0); i < proof.Treedepth; i++ {
+		sibling := proof.Proof[i*uint64(size) : (i+1)*uint64(size)]
+		// an all-zero sibling stands in for a missing right child
+		if bytes.Equal(sibling, zero) {
+			sibling = nil
+		}
+		if position%2 == 0 {
+			node = hashParts(newHash, merkleNodePrefix, node, sibling)
+		} else {
+			node = hashParts(newHash, merkleNodePrefix, sibling, node)
+		}
+		position >>= 1
+	}
+
+	if !bytes.Equal(node, root[:]) {
+		return fmt.Errorf("transaction proof does not match the block's %s transaction commitment", hashTypeName(proof.Hashtype))
+	}
+	return nil
+}
+
+func hashParts(newHash func() hash.Hash, parts ...[]byte) []byte {
+	h := newHash()
+	for _, part := range parts {
+		h.Write(part)
+	}
+	return h.Sum(nil)
+}
+
+func reverseBits(value, bits uint64) uint64 {
+	var result uint64
+	for i := uint64(0); i < bits; i++ {
+		result = (result << 1) | (value & 1)
+		value >>= 1
+	}
+	return result
+}
+
+func hashTypeName(hashType string) string {
+	if hashType == "" {
+		return ProofHashTypeSha512_256
+	}
+	return hashType
+}
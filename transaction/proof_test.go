@@ -0,0 +1,112 @@
+package transaction
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+	"github.com/stretchr/testify/require"
+)
+
+func proofTestTxns() []types.Transaction {
+	var txns []types.Transaction
+	for i := uint64(0); i < 4; i++ {
+		txns = append(txns, types.Transaction{
+			Type: types.PaymentTx,
+			Header: types.Header{
+				Fee:        1000,
+				FirstValid: types.Round(i + 1),
+				LastValid:  types.Round(i + 1001),
+			},
+			PaymentTxnFields: types.PaymentTxnFields{Amount: types.MicroAlgos(i)},
+		})
+	}
+	return txns
+}
+
+func proofTestLeaf(newHash func() hash.Hash, txid []byte, stib []byte) []byte {
+	return hashParts(newHash, []byte("TL"), txid, stib)
+}
+
+func proofTestNode(newHash func() hash.Hash, left, right []byte) []byte {
+	return hashParts(newHash, []byte("MA"), left, right)
+}
+
+func TestVerifyTransactionProofSha512_256(t *testing.T) {
+	newHash := sha512.New512_256
+	txns := proofTestTxns()[:3]
+	var leaves, stibs [][]byte
+	for i, txn := range txns {
+		stib := hashParts(newHash, []byte{byte(i)})
+		stibs = append(stibs, stib)
+		leaves = append(leaves, proofTestLeaf(newHash, crypto.TransactionID(txn), stib))
+	}
+	n0 := proofTestNode(newHash, leaves[0], leaves[1])
+	n1 := proofTestNode(newHash, leaves[2], nil)
+	var header types.BlockHeader
+	copy(header.TxnCommitments.NativeSha512_256Commitment[:], proofTestNode(newHash, n0, n1))
+
+	proof := models.TransactionProofResponse{
+		Hashtype:  ProofHashTypeSha512_256,
+		Idx:       1,
+		Proof:     append(append([]byte{}, leaves[0]...), n1...),
+		Stibhash:  stibs[1],
+		Treedepth: 2,
+	}
+	require.NoError(t, VerifyTransactionProof(txns[1], proof, header))
+
+	// a missing sibling is encoded as a zero digest
+	proof = models.TransactionProofResponse{
+		Idx:       2,
+		Proof:     append(make([]byte, 32), n0...),
+		Stibhash:  stibs[2],
+		Treedepth: 2,
+	}
+	require.NoError(t, VerifyTransactionProof(txns[2], proof, header))
+
+	require.Error(t, VerifyTransactionProof(txns[0], proof, header))
+
+	proof.Stibhash = stibs[0]
+	require.Error(t, VerifyTransactionProof(txns[2], proof, header))
+
+	proof.Stibhash = stibs[2]
+	proof.Treedepth = 1
+	require.Error(t, VerifyTransactionProof(txns[2], proof, header))
+}
+
+func TestVerifyTransactionProofSha256(t *testing.T) {
+	newHash := sha256.New
+	txns := proofTestTxns()
+	var leaves, stibs [][]byte
+	for i, txn := range txns {
+		stib := hashParts(newHash, []byte{byte(i)})
+		stibs = append(stibs, stib)
+		txid := hashParts(newHash, []byte("TX"), msgpack.Encode(txn))
+		leaves = append(leaves, proofTestLeaf(newHash, txid, stib))
+	}
+	// leaves of a vector commitment are placed at their bit-reversed index
+	n0 := proofTestNode(newHash, leaves[0], leaves[2])
+	n1 := proofTestNode(newHash, leaves[1], leaves[3])
+	var header types.BlockHeader
+	copy(header.TxnCommitments.Sha256Commitment[:], proofTestNode(newHash, n0, n1))
+
+	proof := models.TransactionProofResponse{
+		Hashtype:  ProofHashTypeSha256,
+		Idx:       1,
+		Proof:     append(append([]byte{}, leaves[3]...), n0...),
+		Stibhash:  stibs[1],
+		Treedepth: 2,
+	}
+	require.NoError(t, VerifyTransactionProof(txns[1], proof, header))
+
+	proof.Hashtype = ProofHashTypeSha512_256
+	require.Error(t, VerifyTransactionProof(txns[1], proof, header))
+
+	proof.Hashtype = "md5"
+	require.Error(t, VerifyTransactionProof(txns[1], proof, header))
+}
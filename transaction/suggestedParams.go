@@ -0,0 +1,83 @@
+package transaction
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// SuggestedParamsSource caches the suggested parameters returned by algod, so
+// that services building many transactions do not request
+// /v2/transactions/params for each of them.
+//
+// Cached parameters are returned for up to the TTL after they were fetched.
+// Once half the TTL has passed, they are refreshed in the background while
+// the cached parameters are still returned, so that callers rarely wait for
+// algod. Since FirstRoundValid is the last round when the parameters were
+// fetched, the TTL should be short compared to the validity window of the
+// transactions, typically a few seconds.
+type SuggestedParamsSource struct {
+	fetch func(ctx context.Context) (types.SuggestedParams, error)
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu         sync.Mutex
+	params     types.SuggestedParams
+	fetchedAt  time.Time
+	valid      bool
+	refreshing bool
+}
+
+// NewSuggestedParamsSource returns a SuggestedParamsSource fetching the
+// suggested parameters from c and caching them for ttl.
+func NewSuggestedParamsSource(c *algod.Client, ttl time.Duration) *SuggestedParamsSource {
+	fetch := func(ctx context.Context) (types.SuggestedParams, error) {
+		return c.SuggestedParams().Do(ctx)
+	}
+	return &SuggestedParamsSource{fetch: fetch, ttl: ttl, now: time.Now}
+}
+
+// SuggestedParams returns the cached suggested parameters, fetching them if
+// there are none or they are older than the TTL.
+func (s *SuggestedParamsSource) SuggestedParams(ctx context.Context) (types.SuggestedParams, error) {
+	s.mu.Lock()
+	age := s.now().Sub(s.fetchedAt)
+	if s.valid && age < s.ttl {
+		params := s.params
+		if age >= s.ttl/2 && !s.refreshing {
+			s.refreshing = true
+			go s.refresh(context.Background())
+		}
+		s.mu.Unlock()
+		return params, nil
+	}
+	s.mu.Unlock()
+
+	return s.refresh(ctx)
+}
+
+// Invalidate drops the cached parameters, e.g. after algod rejected a
+// transaction built with them, so that the next call fetches new ones.
+func (s *SuggestedParamsSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.valid = false
+}
+
+func (s *SuggestedParamsSource) refresh(ctx context.Context) (types.SuggestedParams, error) {
+	params, err := s.fetch(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshing = false
+	if err != nil {
+		return types.SuggestedParams{}, err
+	}
+	s.params = params
+	s.fetchedAt = s.now()
+	s.valid = true
+	return params, nil
+}
@@ -0,0 +1,68 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+func TestSuggestedParamsSource(t *testing.T) {
+	var requests int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		round := atomic.AddInt64(&requests, 1) * 100
+		fmt.Fprintf(w, `{"consensus-version":"future","fee":0,"genesis-hash":"","genesis-id":"test","last-round":%d,"min-fee":1000}`, round)
+	}))
+	defer server.Close()
+	c, err := algod.MakeClient(server.URL, "")
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	now := time.Unix(1000, 0)
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+	source := NewSuggestedParamsSource(c, 10*time.Second)
+	source.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+
+	round := func() types.Round {
+		params, err := source.SuggestedParams(context.Background())
+		require.NoError(t, err)
+		return params.FirstRoundValid
+	}
+
+	// fetched once, then cached
+	require.Equal(t, types.Round(100), round())
+	advance(4 * time.Second)
+	require.Equal(t, types.Round(100), round())
+	require.Equal(t, int64(1), atomic.LoadInt64(&requests))
+
+	// past half the TTL, the cached params are returned while refreshed
+	advance(2 * time.Second)
+	require.Equal(t, types.Round(100), round())
+	require.Eventually(t, func() bool { return round() == 200 }, time.Second, time.Millisecond)
+	require.Equal(t, int64(2), atomic.LoadInt64(&requests))
+
+	// past the TTL, the params are fetched before returning
+	advance(20 * time.Second)
+	require.Equal(t, types.Round(300), round())
+
+	source.Invalidate()
+	require.Equal(t, types.Round(400), round())
+	require.Equal(t, int64(4), atomic.LoadInt64(&requests))
+}
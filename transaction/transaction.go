@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 
+	"github.com/algorand/go-algorand-sdk/v2/arc3"
 	"github.com/algorand/go-algorand-sdk/v2/crypto"
 	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
 	"github.com/algorand/go-algorand-sdk/v2/types"
@@ -314,6 +315,31 @@ func MakeAssetCreateTxn(account string, note []byte, params types.SuggestedParam
 	return setFee(tx, params)
 }
 
+// MakeARC3AssetCreateTxn constructs the creation transaction of an ARC-3 asset, such as an NFT,
+// with the JSON metadata metadataJSON published at url.
+// - the decimals of the asset are those of the metadata, 0 if it has none
+// - url is marked with "#arc3" unless assetName ends with "@arc3"
+// - the metadata hash of the asset commits to metadataJSON, see arc3.MetadataHash
+// For the other parameters, see MakeAssetCreateTxn.
+func MakeARC3AssetCreateTxn(account string, note []byte, params types.SuggestedParams, total uint64, defaultFrozen bool, manager, reserve, freeze, clawback string, unitName, assetName, url string, metadataJSON []byte) (types.Transaction, error) {
+	metadata, err := arc3.ParseMetadata(metadataJSON)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	hash, err := arc3.MetadataHash(metadataJSON)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	var decimals uint32
+	if metadata.Decimals != nil {
+		decimals = *metadata.Decimals
+	}
+	if !arc3.IsARC3(assetName, url) {
+		url += arc3.URLSuffix
+	}
+	return MakeAssetCreateTxn(account, note, params, total, decimals, defaultFrozen, manager, reserve, freeze, clawback, unitName, assetName, url, string(hash[:]))
+}
+
 // MakeAssetConfigTxn creates a tx template for changing the
 // key configuration of an existing asset.
 // Important notes -
@@ -1334,37 +1360,10 @@ func parseTxnForeignAssets(foreignAssets []uint64) (parsed []types.AssetIndex) {
 
 func parseBoxReferences(abrs []types.AppBoxReference, foreignApps []uint64, curAppID uint64) (parsed []types.BoxReference, err error) {
 	for _, abr := range abrs {
-		// there are a few unintuitive details to the parsing:
-		//     1. the AppID of the box must either be in the foreign apps array or
-		//        equal to 0, which references the current app.
-		//     2. if the box references the current app by its appID rather than 0 AND
-		//        the current appID is explicitly provided in the foreign apps array
-		//        then ForeignAppIdx should be set to its index in the array.
-		br := types.BoxReference{Name: abr.Name}
-		found := false
-
-		if abr.AppID == 0 {
-			found = true
-			br.ForeignAppIdx = 0
-		} else {
-			for idx, appID := range foreignApps {
-				if appID == abr.AppID {
-					found = true
-					br.ForeignAppIdx = uint64(idx + 1)
-					break
-				}
-			}
-		}
-
-		if !found && abr.AppID == curAppID {
-			found = true
-			br.ForeignAppIdx = 0
-		}
-
-		if !found {
-			return nil, fmt.Errorf("the app id %d provided for this box is not in the foreignApps array", abr.AppID)
+		br, err := abr.Resolve(foreignApps, curAppID)
+		if err != nil {
+			return nil, err
 		}
-
 		parsed = append(parsed, br)
 	}
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
 	"github.com/algorand/go-algorand-sdk/v2/types"
 )
 
@@ -148,3 +149,23 @@ func (txSigner MultiSigAccountTransactionSigner) Equals(other TransactionSigner)
 	}
 	return false
 }
+
+/**
+ * TransactionSigner that produces unsigned transactions. Intended for use with
+ * the simulate endpoint when allow-empty-signatures is set.
+ */
+type EmptyTransactionSigner struct{}
+
+func (txSigner EmptyTransactionSigner) SignTransactions(txGroup []types.Transaction, indexesToSign []int) ([][]byte, error) {
+	stxs := make([][]byte, len(indexesToSign))
+	for i, pos := range indexesToSign {
+		stxs[i] = msgpack.Encode(types.SignedTxn{Txn: txGroup[pos]})
+	}
+
+	return stxs, nil
+}
+
+func (txSigner EmptyTransactionSigner) Equals(other TransactionSigner) bool {
+	_, ok := other.(EmptyTransactionSigner)
+	return ok
+}
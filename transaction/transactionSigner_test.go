@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
 	"github.com/algorand/go-algorand-sdk/v2/mnemonic"
 	"github.com/algorand/go-algorand-sdk/v2/types"
 	"github.com/stretchr/testify/require"
@@ -137,3 +138,36 @@ func TestMakeMultiSigAccountTransactionSigner(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, sigs[0], expectedSig)
 }
+
+func TestEmptyTransactionSigner(t *testing.T) {
+	addr, err := types.DecodeAddress("DN7MBMCL5JQ3PFUQS7TMX5AH4EEKOBJVDUF4TCV6WERATKFLQF4MQUPZTA")
+	require.NoError(t, err)
+
+	txSigner := EmptyTransactionSigner{}
+	tx := types.Transaction{
+		Type: types.PaymentTx,
+		Header: types.Header{
+			Sender:     addr,
+			Fee:        1000,
+			FirstValid: 972508,
+			LastValid:  973508,
+			GenesisID:  "testnet-v31.0",
+		},
+		PaymentTxnFields: types.PaymentTxnFields{
+			Receiver: addr,
+			Amount:   5000,
+		},
+	}
+
+	sigs, err := txSigner.SignTransactions([]types.Transaction{tx, tx}, []int{1})
+	require.NoError(t, err)
+	require.Len(t, sigs, 1)
+
+	var stx types.SignedTxn
+	require.NoError(t, msgpack.Decode(sigs[0], &stx))
+	require.Equal(t, tx, stx.Txn)
+	require.Equal(t, types.Signature{}, stx.Sig)
+
+	require.True(t, txSigner.Equals(EmptyTransactionSigner{}))
+	require.False(t, txSigner.Equals(BasicAccountTransactionSigner{}))
+}
@@ -1,9 +1,11 @@
 package transaction
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
 	"testing"
 
+	"github.com/algorand/go-algorand-sdk/v2/arc3"
 	"github.com/algorand/go-algorand-sdk/v2/crypto"
 	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
 	"github.com/algorand/go-algorand-sdk/v2/mnemonic"
@@ -299,6 +301,34 @@ func TestMakeAssetCreateTxn(t *testing.T) {
 	require.EqualValues(t, newStxBytes, byteFromBase64(signedGolden))
 }
 
+func TestMakeARC3AssetCreateTxn(t *testing.T) {
+	const addr = "BH55E5RMBD4GYWXGX5W5PJ5JAHPGM5OXKDQH5DC4O2MGI7NW4H6VOE4CP4"
+	const genesisHash = "SGO1GKSzyE7IEPItTxCByw9x8FmnrCDexi9/cOUJOiI="
+	ghAsArray := byte32ArrayFromBase64(genesisHash)
+	params := types.SuggestedParams{
+		Fee:             1000,
+		FlatFee:         true,
+		FirstRoundValid: 322575,
+		LastRoundValid:  323575,
+		GenesisHash:     ghAsArray[:],
+	}
+	metadata := []byte(`{"name":"My NFT","decimals":2,"image":"ipfs://QmImage"}`)
+
+	tx, err := MakeARC3AssetCreateTxn(addr, nil, params, 100, false, addr, "", "", "", "NFT", "My NFT", "ipfs://QmMetadata", metadata)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), tx.AssetParams.Decimals)
+	require.Equal(t, "ipfs://QmMetadata#arc3", tx.AssetParams.URL)
+	require.Equal(t, sha256.Sum256(metadata), tx.AssetParams.MetadataHash)
+	require.NoError(t, arc3.ValidateAsset(tx.AssetParams, metadata))
+
+	tx, err = MakeARC3AssetCreateTxn(addr, nil, params, 100, false, addr, "", "", "", "NFT", "My NFT@arc3", "ipfs://QmMetadata", metadata)
+	require.NoError(t, err)
+	require.Equal(t, "ipfs://QmMetadata", tx.AssetParams.URL)
+
+	_, err = MakeARC3AssetCreateTxn(addr, nil, params, 100, false, addr, "", "", "", "NFT", "My NFT", "ipfs://QmMetadata", []byte(`{"background_color":"red"}`))
+	require.Error(t, err)
+}
+
 func TestMakeAssetCreateTxnWithDecimals(t *testing.T) {
 	const addr = "BH55E5RMBD4GYWXGX5W5PJ5JAHPGM5OXKDQH5DC4O2MGI7NW4H6VOE4CP4"
 	const defaultFrozen = false
@@ -3,8 +3,10 @@ package types
 import (
 	"bytes"
 	"crypto/sha512"
+	"database/sql/driver"
 	"encoding/base32"
 	"encoding/base64"
+	"fmt"
 )
 
 const (
@@ -35,10 +37,10 @@ func (a Address) IsZero() bool {
 	return a == ZeroAddress
 }
 
-// MarshalText returns the address string as an array of bytes
-func (addr *Address) MarshalText() ([]byte, error) {
-	result := base64.StdEncoding.EncodeToString(addr[:])
-	return []byte(result), nil
+// MarshalText returns the address string, in the base32 checksum format of
+// String, as an array of bytes
+func (addr Address) MarshalText() ([]byte, error) {
+	return []byte(addr.String()), nil
 }
 
 // UnmarshalText initializes the Address from an array of bytes.
@@ -63,6 +65,29 @@ func (addr *Address) UnmarshalText(text []byte) error {
 	return err
 }
 
+// Value implements driver.Valuer, storing the address in SQL as its base32
+// checksum string.
+func (addr Address) Value() (driver.Value, error) {
+	return addr.String(), nil
+}
+
+// Scan implements sql.Scanner. The address may be stored as a string in any
+// format accepted by UnmarshalText, or as its 32 raw bytes.
+func (addr *Address) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return addr.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == len(addr) {
+			copy(addr[:], v)
+			return nil
+		}
+		return addr.UnmarshalText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into an address", src)
+	}
+}
+
 // DecodeAddress turns a checksum address string into an Address object. It
 // checks that the checksum is correct, and returns an error if it's not.
 func DecodeAddress(addr string) (a Address, err error) {
@@ -2,6 +2,10 @@ package types
 
 import (
 	"crypto/rand"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -53,12 +57,12 @@ func TestUnmarshalAddress(t *testing.T) {
 			name:   "B32+Checksum",
 			input:  "7HJBGRIWI7GDL42SOJNIAZ7LJ7EBEGKGE5S52QZXAWDXOHDKMDFR6AUXDE",
 			str:    "7HJBGRIWI7GDL42SOJNIAZ7LJ7EBEGKGE5S52QZXAWDXOHDKMDFR6AUXDE",
-			output: "+dITRRZHzDXzUnJagGfrT8gSGUYnZd1DNwWHdxxqYMs=",
+			output: "7HJBGRIWI7GDL42SOJNIAZ7LJ7EBEGKGE5S52QZXAWDXOHDKMDFR6AUXDE",
 		}, {
 			name:   "B64",
 			input:  "+dITRRZHzDXzUnJagGfrT8gSGUYnZd1DNwWHdxxqYMs=",
 			str:    "7HJBGRIWI7GDL42SOJNIAZ7LJ7EBEGKGE5S52QZXAWDXOHDKMDFR6AUXDE",
-			output: "+dITRRZHzDXzUnJagGfrT8gSGUYnZd1DNwWHdxxqYMs=",
+			output: "7HJBGRIWI7GDL42SOJNIAZ7LJ7EBEGKGE5S52QZXAWDXOHDKMDFR6AUXDE",
 		}, {
 			name:  "B64-err-length",
 			input: "AAE=",
@@ -92,3 +96,72 @@ func TestUnmarshalAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestAddressText(t *testing.T) {
+	addr, err := DecodeAddress("7HJBGRIWI7GDL42SOJNIAZ7LJ7EBEGKGE5S52QZXAWDXOHDKMDFR6AUXDE")
+	require.NoError(t, err)
+
+	// Address values, not only pointers, are TextMarshalers, so that they
+	// encode as strings with encoding/json wherever they appear.
+	var marshaler encoding.TextMarshaler = addr
+	text, err := marshaler.MarshalText()
+	require.NoError(t, err)
+
+	var decoded Address
+	var unmarshaler encoding.TextUnmarshaler = &decoded
+	require.NoError(t, unmarshaler.UnmarshalText(text))
+	require.Equal(t, addr, decoded)
+
+	config := struct {
+		Addresses map[string]Address
+	}{Addresses: map[string]Address{"a": addr}}
+	b, err := json.Marshal(config)
+	require.NoError(t, err)
+	require.Equal(t, `{"Addresses":{"a":"`+string(text)+`"}}`, string(b))
+
+	config.Addresses = nil
+	require.NoError(t, json.Unmarshal(b, &config))
+	require.Equal(t, addr, config.Addresses["a"])
+}
+
+func TestAddressJSONField(t *testing.T) {
+	addr, err := DecodeAddress("7HJBGRIWI7GDL42SOJNIAZ7LJ7EBEGKGE5S52QZXAWDXOHDKMDFR6AUXDE")
+	require.NoError(t, err)
+
+	type account struct {
+		Address Address `json:"address"`
+	}
+	b, err := json.Marshal(account{Address: addr})
+	require.NoError(t, err)
+	require.Equal(t, `{"address":"7HJBGRIWI7GDL42SOJNIAZ7LJ7EBEGKGE5S52QZXAWDXOHDKMDFR6AUXDE"}`, string(b))
+
+	var decoded account
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	require.Equal(t, addr, decoded.Address)
+
+	// the base64 form of the raw bytes is still accepted
+	require.NoError(t, json.Unmarshal([]byte(`{"address":"+dITRRZHzDXzUnJagGfrT8gSGUYnZd1DNwWHdxxqYMs="}`), &decoded))
+	require.Equal(t, addr, decoded.Address)
+}
+
+func TestAddressSQL(t *testing.T) {
+	addr, err := DecodeAddress("7HJBGRIWI7GDL42SOJNIAZ7LJ7EBEGKGE5S52QZXAWDXOHDKMDFR6AUXDE")
+	require.NoError(t, err)
+
+	var valuer driver.Valuer = addr
+	value, err := valuer.Value()
+	require.NoError(t, err)
+	require.Equal(t, addr.String(), value)
+
+	for _, src := range []interface{}{value, []byte(addr.String()), addr[:], "+dITRRZHzDXzUnJagGfrT8gSGUYnZd1DNwWHdxxqYMs="} {
+		var scanned Address
+		var scanner sql.Scanner = &scanned
+		require.NoError(t, scanner.Scan(src))
+		require.Equal(t, addr, scanned)
+	}
+
+	var scanned Address
+	require.Error(t, scanned.Scan(nil))
+	require.Error(t, scanned.Scan(int64(1)))
+	require.Error(t, scanned.Scan("bogus"))
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrInvalidAssetAmount is returned when an amount in display units cannot be
+// converted to base units: it is not a non-negative decimal number, it has
+// more decimal places than the asset, or it does not fit in a uint64.
+var ErrInvalidAssetAmount = errors.New("invalid asset amount")
+
+var maxAssetAmount = new(big.Int).SetUint64(^uint64(0))
+
+// assetUnit returns 10^decimals, the number of base units in a display unit.
+func assetUnit(decimals uint32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+}
+
+// AssetAmountToRat converts an amount in base units of an asset with the
+// given decimals to display units, e.g. 150 with 2 decimals is 1.5.
+func AssetAmountToRat(amount uint64, decimals uint32) *big.Rat {
+	return new(big.Rat).SetFrac(new(big.Int).SetUint64(amount), assetUnit(decimals))
+}
+
+// AssetAmountFromRat converts an amount in display units of an asset with the
+// given decimals to base units. The amount must be a whole number of base
+// units which fits in a uint64.
+func AssetAmountFromRat(amount *big.Rat, decimals uint32) (uint64, error) {
+	if decimals > AssetMaxNumberOfDecimals {
+		return 0, fmt.Errorf("%w: asset decimals %d exceed %d", ErrInvalidAssetAmount, decimals, AssetMaxNumberOfDecimals)
+	}
+	base := new(big.Rat).Mul(amount, new(big.Rat).SetInt(assetUnit(decimals)))
+	if !base.IsInt() {
+		return 0, fmt.Errorf("%w: %s has more than %d decimal places", ErrInvalidAssetAmount, amount.RatString(), decimals)
+	}
+	if base.Sign() < 0 || base.Num().Cmp(maxAssetAmount) > 0 {
+		return 0, fmt.Errorf("%w: %s is out of range", ErrInvalidAssetAmount, amount.RatString())
+	}
+	return base.Num().Uint64(), nil
+}
+
+// FormatAssetAmount formats an amount in base units of an asset with the
+// given decimals in display units, with all the decimal places of the asset,
+// e.g. "1.50" for 150 with 2 decimals.
+func FormatAssetAmount(amount uint64, decimals uint32) string {
+	return AssetAmountToRat(amount, decimals).FloatString(int(decimals))
+}
+
+// ParseAssetAmount parses a user-entered amount in display units of an asset
+// with the given decimals, such as "1.5" or "1,000.25", into base units.
+// Commas are ignored as thousands separators, and the amount may have at most
+// the number of decimal places of the asset.
+func ParseAssetAmount(s string, decimals uint32) (uint64, error) {
+	text := strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	whole, frac := text, ""
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		whole, frac = text[:i], text[i+1:]
+	}
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidAssetAmount, s)
+	}
+
+	amount, ok := new(big.Rat).SetString(whole + "." + frac + "0")
+	if !ok {
+		return 0, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidAssetAmount, s)
+	}
+	return AssetAmountFromRat(amount, decimals)
+}
+
+// FormatAmount formats an amount in base units of the asset, see
+// FormatAssetAmount.
+func (ap AssetParams) FormatAmount(amount uint64) string {
+	return FormatAssetAmount(amount, ap.Decimals)
+}
+
+// ParseAmount parses an amount in display units of the asset, see
+// ParseAssetAmount.
+func (ap AssetParams) ParseAmount(s string) (uint64, error) {
+	return ParseAssetAmount(s, ap.Decimals)
+}
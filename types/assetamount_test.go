@@ -0,0 +1,57 @@
+package types
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAssetAmount(t *testing.T) {
+	require.Equal(t, "1.50", FormatAssetAmount(150, 2))
+	require.Equal(t, "150", FormatAssetAmount(150, 0))
+	require.Equal(t, "0.0000000000000000001", FormatAssetAmount(1, 19))
+	require.Equal(t, "1.8446744073709551615", FormatAssetAmount(math.MaxUint64, 19))
+	require.Equal(t, "0.000150", AssetParams{Decimals: 6}.FormatAmount(150))
+	require.Equal(t, big.NewRat(3, 2), AssetAmountToRat(150, 2))
+}
+
+func TestParseAssetAmount(t *testing.T) {
+	testcases := []struct {
+		input    string
+		decimals uint32
+		expected uint64
+		err      bool
+	}{
+		{input: "1.5", decimals: 2, expected: 150},
+		{input: "1.50", decimals: 2, expected: 150},
+		{input: "1,000.25", decimals: 2, expected: 100025},
+		{input: " .5 ", decimals: 1, expected: 5},
+		{input: "7", decimals: 0, expected: 7},
+		{input: "1.8446744073709551615", decimals: 19, expected: math.MaxUint64},
+		{input: "1.8446744073709551616", decimals: 19, err: true},
+		{input: "1.555", decimals: 2, err: true},
+		{input: "-1", decimals: 2, err: true},
+		{input: "1e3", decimals: 2, err: true},
+		{input: "1/2", decimals: 2, err: true},
+		{input: "", decimals: 2, err: true},
+		{input: "1", decimals: 20, err: true},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			amount, err := ParseAssetAmount(tc.input, tc.decimals)
+			if tc.err {
+				require.ErrorIs(t, err, ErrInvalidAssetAmount)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, amount)
+
+			parsed, err := AssetParams{Decimals: tc.decimals}.ParseAmount(FormatAssetAmount(amount, tc.decimals))
+			require.NoError(t, err)
+			require.Equal(t, amount, parsed)
+		})
+	}
+}
@@ -1,9 +1,14 @@
 package types
 
+import "crypto/sha512"
+
 type (
 	// BlockHash represents the hash of a block
 	BlockHash Digest
 
+	// Sha512Digest is a SHA-512 hash
+	Sha512Digest [sha512.Size]byte
+
 	// A BlockHeader represents the metadata and commitments to the state of a Block.
 	// The Algorand Ledger may be defined minimally as a cryptographically authenticated series of BlockHeader objects.
 	BlockHeader struct {
@@ -14,6 +19,10 @@ type (
 		// The hash of the previous block
 		Branch BlockHash `codec:"prev"`
 
+		// The SHA-512 hash of the previous block header, set by consensus
+		// versions which commit to block headers with SHA-512.
+		Branch512 Sha512Digest `codec:"prev512"`
+
 		// Sortition seed
 		Seed [32]byte `codec:"seed"`
 
@@ -89,6 +98,20 @@ type (
 		// started being supported).
 		TxnCounter uint64 `codec:"tc"`
 
+		// Proposer is the account which proposed this block.
+		Proposer Address `codec:"prp"`
+
+		// FeesCollected is the sum of the fees paid by the transactions in
+		// this block.
+		FeesCollected MicroAlgos `codec:"fc"`
+
+		// Bonus is the bonus incentive paid for proposing this block.
+		Bonus MicroAlgos `codec:"bi"`
+
+		// ProposerPayout is the amount moved from the FeeSink to the
+		// Proposer at the start of the next block.
+		ProposerPayout MicroAlgos `codec:"pp"`
+
 		// StateProofTracking tracks the status of the state proofs, potentially
 		// for multiple types of ASPs (Algorand's State Proofs).
 		//msgp:sort protocol.StateProofType protocol.SortStateProofType
@@ -109,6 +132,9 @@ type (
 
 		// Root of transaction vector commitment merkle tree using SHA256 hash function
 		Sha256Commitment Digest `codec:"txn256"`
+
+		// Root of transaction vector commitment merkle tree using SHA512 hash function
+		Sha512Commitment Sha512Digest `codec:"txn512"`
 	}
 
 	// ParticipationUpdates represents participation account data that
@@ -120,6 +146,11 @@ type (
 		// that needs to be converted to offline since their
 		// participation key expired.
 		ExpiredParticipationAccounts []Address `codec:"partupdrmv"`
+
+		// AbsentParticipationAccounts contains a list of online accounts
+		// that needs to be converted to offline since they are not
+		// proposing.
+		AbsentParticipationAccounts []Address `codec:"partupdabs"`
 	}
 
 	// RewardsState represents the global parameters controlling the rate
@@ -259,6 +290,11 @@ type EvalDelta struct {
 	// [txn.Sender, txn.Accounts[0], txn.Accounts[1], ...]
 	LocalDeltas map[uint64]StateDelta `codec:"ld,allocbound=config.MaxEvalDeltaAccounts"`
 
+	// SharedAccts are the accounts referenced by LocalDeltas which are
+	// not in the transaction's Accounts, in the order of their offsets
+	// after them.
+	SharedAccts []Address `codec:"sa,allocbound=config.MaxEvalDeltaAccounts"`
+
 	Logs []string `codec:"lg"`
 
 	InnerTxns []SignedTxnWithAD `codec:"itx"`
@@ -292,3 +328,50 @@ const (
 	// DeleteAction indicates that the value for a particular key should be deleted
 	DeleteAction DeltaAction = 3
 )
+
+// DecodeSignedTxn restores the fields that were stripped from a transaction
+// when it was encoded into a block with the given header, returning the
+// complete SignedTxn along with its ApplyData.
+func (bh BlockHeader) DecodeSignedTxn(stib SignedTxnInBlock) SignedTxnWithAD {
+	stxn := stib.SignedTxnWithAD
+	if stib.HasGenesisID {
+		stxn.Txn.GenesisID = bh.GenesisID
+	}
+	// The genesis hash is required by all current protocols, so it is always
+	// stripped from transactions in a block.
+	if stib.HasGenesisHash || stxn.Txn.GenesisHash == (Digest{}) {
+		stxn.Txn.GenesisHash = bh.GenesisHash
+	}
+	return stxn
+}
+
+// Walk calls fn with the transaction and then, depth first, with each of its
+// inner transactions, along with their depth: 0 for the transaction itself, 1
+// for its inner transactions and so on. It stops at and returns the first
+// error returned by fn.
+func (stxn SignedTxnWithAD) Walk(fn func(stxn SignedTxnWithAD, depth int) error) error {
+	return stxn.walk(fn, 0)
+}
+
+func (stxn SignedTxnWithAD) walk(fn func(stxn SignedTxnWithAD, depth int) error, depth int) error {
+	if err := fn(stxn, depth); err != nil {
+		return err
+	}
+	for _, inner := range stxn.EvalDelta.InnerTxns {
+		if err := inner.walk(fn, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodePayset returns the transactions in the block with the fields that were
+// stripped when encoding them into the block restored, so that their IDs and
+// signatures can be verified.
+func (b Block) DecodePayset() []SignedTxnWithAD {
+	stxns := make([]SignedTxnWithAD, len(b.Payset))
+	for i, stib := range b.Payset {
+		stxns[i] = b.BlockHeader.DecodeSignedTxn(stib)
+	}
+	return stxns
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+)
+
+func TestDecodePayset(t *testing.T) {
+	header := BlockHeader{GenesisID: "testnet-v1.0", GenesisHash: Digest{1, 2, 3}}
+	withGenesisID := SignedTxnInBlock{HasGenesisID: true}
+	withGenesisID.Txn.Type = PaymentTx
+	withoutGenesisID := SignedTxnInBlock{}
+	withoutGenesisID.Txn.Type = AssetTransferTx
+
+	block := Block{BlockHeader: header, Payset: Payset{withGenesisID, withoutGenesisID}}
+	stxns := block.DecodePayset()
+	require.Len(t, stxns, 2)
+
+	require.Equal(t, PaymentTx, stxns[0].Txn.Type)
+	require.Equal(t, "testnet-v1.0", stxns[0].Txn.GenesisID)
+	require.Equal(t, header.GenesisHash, stxns[0].Txn.GenesisHash)
+
+	require.Equal(t, AssetTransferTx, stxns[1].Txn.Type)
+	require.Empty(t, stxns[1].Txn.GenesisID)
+	require.Equal(t, header.GenesisHash, stxns[1].Txn.GenesisHash)
+
+	// the payset itself is left untouched
+	require.Empty(t, block.Payset[0].Txn.GenesisID)
+}
+
+func TestBlockApplyDataRoundTrip(t *testing.T) {
+	inner := SignedTxnWithAD{}
+	inner.Txn.Type = AssetTransferTx
+	inner.AssetClosingAmount = 3
+	inner.EvalDelta.Logs = []string{"inner log"}
+
+	stib := SignedTxnInBlock{HasGenesisID: true}
+	stib.Txn.Type = ApplicationCallTx
+	stib.ApplicationID = 5
+	stib.EvalDelta = EvalDelta{
+		GlobalDelta: StateDelta{"g": {Action: SetUintAction, Uint: 1}},
+		LocalDeltas: map[uint64]StateDelta{1: {"l": {Action: SetBytesAction, Bytes: "v"}}},
+		SharedAccts: []Address{{9}},
+		Logs:        []string{"log"},
+		InnerTxns:   []SignedTxnWithAD{inner, {ApplyData: ApplyData{EvalDelta: EvalDelta{InnerTxns: []SignedTxnWithAD{inner}}}}},
+	}
+
+	block := Block{
+		BlockHeader: BlockHeader{
+			Round:          7,
+			Branch512:      Sha512Digest{4},
+			TxnCommitments: TxnCommitments{Sha256Commitment: Digest{5}, Sha512Commitment: Sha512Digest{6}},
+			Proposer:       Address{1},
+			FeesCollected:  2000,
+			Bonus:          10,
+			ProposerPayout: 1000,
+			StateProofTracking: map[StateProofType]StateProofTrackingData{
+				StateProofBasic: {StateProofOnlineTotalWeight: 100, StateProofNextRound: 256},
+			},
+			ParticipationUpdates: ParticipationUpdates{
+				ExpiredParticipationAccounts: []Address{{2}},
+				AbsentParticipationAccounts:  []Address{{3}},
+			},
+		},
+		Payset: Payset{stib},
+	}
+
+	var decoded Block
+	require.NoError(t, msgpack.Decode(msgpack.Encode(block), &decoded))
+	require.Equal(t, block, decoded)
+}
+
+func TestSignedTxnWithADWalk(t *testing.T) {
+	var root SignedTxnWithAD
+	root.Txn.Note = []byte("root")
+	var child, grandchild SignedTxnWithAD
+	child.Txn.Note = []byte("child")
+	grandchild.Txn.Note = []byte("grandchild")
+	child.EvalDelta.InnerTxns = []SignedTxnWithAD{grandchild}
+	var sibling SignedTxnWithAD
+	sibling.Txn.Note = []byte("sibling")
+	root.EvalDelta.InnerTxns = []SignedTxnWithAD{child, sibling}
+
+	var visited []string
+	require.NoError(t, root.Walk(func(stxn SignedTxnWithAD, depth int) error {
+		visited = append(visited, fmt.Sprintf("%s:%d", stxn.Txn.Note, depth))
+		return nil
+	}))
+	require.Equal(t, []string{"root:0", "child:1", "grandchild:2", "sibling:1"}, visited)
+
+	stop := errors.New("stop")
+	count := 0
+	err := root.Walk(func(stxn SignedTxnWithAD, depth int) error {
+		count++
+		if depth == 2 {
+			return stop
+		}
+		return nil
+	})
+	require.Equal(t, stop, err)
+	require.Equal(t, 3, count)
+}
@@ -0,0 +1,155 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+)
+
+// paysetKey is the codec name of Block.Payset.
+const paysetKey = "txns"
+
+// DecodeBlockStream decodes a msgpack-encoded Block from r, calling fn with
+// each transaction of its payset as it is read instead of holding the whole
+// payset in memory. The transactions have the fields which were stripped when
+// they were encoded into the block restored, as by DecodePayset. The block
+// header is returned once the whole block has been read. Unknown fields are
+// ignored, like in the responses of the REST clients.
+func DecodeBlockStream(r io.Reader, fn func(stxn SignedTxnWithAD) error) (BlockHeader, error) {
+	return decodeBlockStream(msgpack.NewLenientStreamDecoder(r), false, fn)
+}
+
+// DecodeBlockStreamStrict is DecodeBlockStream failing on fields unknown to
+// the SDK's types, with an error wrapping msgpack.ErrUnknownField, so that
+// fields added by newer consensus versions are detected instead of dropped.
+func DecodeBlockStreamStrict(r io.Reader, fn func(stxn SignedTxnWithAD) error) (BlockHeader, error) {
+	return decodeBlockStream(msgpack.NewStreamDecoder(r), true, fn)
+}
+
+// DecodeEncodedBlockCertStream is DecodeBlockStream for a msgpack-encoded
+// EncodedBlockCert, as returned by algod, also returning the certificate.
+func DecodeEncodedBlockCertStream(r io.Reader, fn func(stxn SignedTxnWithAD) error) (header BlockHeader, cert Certificate, err error) {
+	return decodeEncodedBlockCertStream(msgpack.NewLenientStreamDecoder(r), false, fn)
+}
+
+// DecodeEncodedBlockCertStreamStrict is DecodeEncodedBlockCertStream failing on
+// unknown fields, like DecodeBlockStreamStrict.
+func DecodeEncodedBlockCertStreamStrict(r io.Reader, fn func(stxn SignedTxnWithAD) error) (header BlockHeader, cert Certificate, err error) {
+	return decodeEncodedBlockCertStream(msgpack.NewStreamDecoder(r), true, fn)
+}
+
+func decodeEncodedBlockCertStream(dec *msgpack.StreamDecoder, strict bool, fn func(stxn SignedTxnWithAD) error) (header BlockHeader, cert Certificate, err error) {
+	n, err := dec.ReadMapHeader()
+	if err != nil {
+		return
+	}
+	for i := 0; i < n; i++ {
+		var key string
+		if key, err = dec.ReadString(); err != nil {
+			return
+		}
+		switch key {
+		case "block":
+			header, err = decodeBlockStream(dec, strict, fn)
+		case "cert":
+			err = dec.Decode(&cert)
+		default:
+			if strict {
+				err = fmt.Errorf("%w: %s", msgpack.ErrUnknownField, key)
+			} else {
+				err = dec.Skip()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// decodeBlockStream decodes a block from dec, which decodes strictly if strict
+// is set.
+func decodeBlockStream(dec *msgpack.StreamDecoder, strict bool, fn func(stxn SignedTxnWithAD) error) (BlockHeader, error) {
+	n, err := dec.ReadMapHeader()
+	if err != nil {
+		return BlockHeader{}, err
+	}
+
+	// The header fields are collected into a map encoding which is decoded
+	// at the end. Fields needed to restore the transactions, such as the
+	// genesis hash, precede the payset in the canonical encoding, so the
+	// header decoded from the fields read so far is used for those.
+	var fields bytes.Buffer
+	count := 0
+	header := func() (BlockHeader, error) {
+		var bh BlockHeader
+		encoded := append(mapHeader(count), fields.Bytes()...)
+		decode := msgpack.Decode
+		if !strict {
+			decode = func(b []byte, objptr interface{}) error {
+				return msgpack.NewLenientDecoder(bytes.NewReader(b)).Decode(objptr)
+			}
+		}
+		if err := decode(encoded, &bh); err != nil {
+			return BlockHeader{}, fmt.Errorf("invalid block header: %w", err)
+		}
+		return bh, nil
+	}
+
+	for i := 0; i < n; i++ {
+		key, err := dec.ReadString()
+		if err != nil {
+			return BlockHeader{}, err
+		}
+		if key == paysetKey {
+			bh, err := header()
+			if err != nil {
+				return BlockHeader{}, err
+			}
+			if err := decodePaysetStream(dec, bh, fn); err != nil {
+				return BlockHeader{}, err
+			}
+			continue
+		}
+
+		raw, err := dec.ReadRaw()
+		if err != nil {
+			return BlockHeader{}, err
+		}
+		fields.Write(msgpack.Encode(key))
+		fields.Write(raw)
+		count++
+	}
+	return header()
+}
+
+func decodePaysetStream(dec *msgpack.StreamDecoder, bh BlockHeader, fn func(stxn SignedTxnWithAD) error) error {
+	n, err := dec.ReadArrayHeader()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		var stib SignedTxnInBlock
+		if err := dec.Decode(&stib); err != nil {
+			return fmt.Errorf("invalid transaction %d of payset: %w", i, err)
+		}
+		if err := fn(bh.DecodeSignedTxn(stib)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapHeader returns the msgpack header of a map with n entries.
+func mapHeader(n int) []byte {
+	switch {
+	case n < 16:
+		return []byte{0x80 | byte(n)}
+	case n < 1<<16:
+		return []byte{0xde, byte(n >> 8), byte(n)}
+	default:
+		return []byte{0xdf, byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+	}
+}
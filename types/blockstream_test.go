@@ -0,0 +1,124 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+)
+
+func testBlock() Block {
+	header := BlockHeader{
+		Round:       10,
+		GenesisID:   "testnet-v1.0",
+		GenesisHash: Digest{1, 2, 3},
+		TimeStamp:   1234,
+	}
+	header.UpgradeVote.UpgradeApprove = true
+
+	var payset Payset
+	for i := 0; i < 3; i++ {
+		stib := SignedTxnInBlock{HasGenesisID: i%2 == 0}
+		stib.Txn.Type = PaymentTx
+		stib.Txn.Amount = MicroAlgos(i + 1)
+		stib.ApplyData.SenderRewards = MicroAlgos(i)
+		payset = append(payset, stib)
+	}
+	return Block{BlockHeader: header, Payset: payset}
+}
+
+func TestDecodeBlockStream(t *testing.T) {
+	block := testBlock()
+
+	var stxns []SignedTxnWithAD
+	header, err := DecodeBlockStream(bytes.NewReader(msgpack.Encode(block)), func(stxn SignedTxnWithAD) error {
+		stxns = append(stxns, stxn)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, block.BlockHeader, header)
+	require.Equal(t, block.DecodePayset(), stxns)
+
+	stop := errors.New("stop")
+	_, err = DecodeBlockStream(bytes.NewReader(msgpack.Encode(block)), func(stxn SignedTxnWithAD) error {
+		return stop
+	})
+	require.Equal(t, stop, err)
+}
+
+func TestDecodeBlockStreamEmptyPayset(t *testing.T) {
+	block := testBlock()
+	block.Payset = nil
+
+	header, err := DecodeBlockStream(bytes.NewReader(msgpack.Encode(block)), func(stxn SignedTxnWithAD) error {
+		t.Fatal("unexpected transaction")
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, block.BlockHeader, header)
+}
+
+func TestDecodeEncodedBlockCertStream(t *testing.T) {
+	ebc := EncodedBlockCert{Block: testBlock()}
+	ebc.Certificate.Round = 10
+	ebc.Certificate.Step = 2
+
+	count := 0
+	header, cert, err := DecodeEncodedBlockCertStream(bytes.NewReader(msgpack.Encode(ebc)), func(stxn SignedTxnWithAD) error {
+		count++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, ebc.Block.BlockHeader, header)
+	require.Equal(t, ebc.Certificate, cert)
+	require.Equal(t, len(ebc.Block.Payset), count)
+}
+
+func TestDecodeBlockStreamStrict(t *testing.T) {
+	block := testBlock()
+	noop := func(stxn SignedTxnWithAD) error { return nil }
+
+	header, err := DecodeBlockStreamStrict(bytes.NewReader(msgpack.Encode(block)), noop)
+	require.NoError(t, err)
+	require.Equal(t, block.BlockHeader, header)
+
+	// a header field added by a newer consensus version
+	withHeaderField := struct {
+		Block
+		NewField uint64 `codec:"newfield"`
+	}{Block: block, NewField: 1}
+	encoded := msgpack.Encode(withHeaderField)
+	_, err = DecodeBlockStreamStrict(bytes.NewReader(encoded), noop)
+	require.ErrorIs(t, err, msgpack.ErrUnknownField)
+	header, err = DecodeBlockStream(bytes.NewReader(encoded), noop)
+	require.NoError(t, err)
+	require.Equal(t, block.BlockHeader, header)
+
+	// a transaction field added by a newer consensus version
+	type newStib struct {
+		SignedTxnInBlock
+		NewField uint64 `codec:"newfield"`
+	}
+	withTxnField := struct {
+		BlockHeader
+		Payset []newStib `codec:"txns"`
+	}{BlockHeader: block.BlockHeader, Payset: []newStib{{SignedTxnInBlock: block.Payset[0], NewField: 1}}}
+	encoded = msgpack.Encode(withTxnField)
+	_, err = DecodeBlockStreamStrict(bytes.NewReader(encoded), noop)
+	require.ErrorIs(t, err, msgpack.ErrUnknownField)
+	_, err = DecodeBlockStream(bytes.NewReader(encoded), noop)
+	require.NoError(t, err)
+
+	ebc := struct {
+		EncodedBlockCert
+		NewField uint64 `codec:"newfield"`
+	}{NewField: 1}
+	ebc.Block = block
+	_, _, err = DecodeEncodedBlockCertStreamStrict(bytes.NewReader(msgpack.Encode(ebc)), noop)
+	require.ErrorIs(t, err, msgpack.ErrUnknownField)
+	_, _, err = DecodeEncodedBlockCertStream(bytes.NewReader(msgpack.Encode(ebc)), noop)
+	require.NoError(t, err)
+}
@@ -0,0 +1,149 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// BoxFlatMinBalance is the minimum balance an application account needs
+	// for each box it holds, regardless of its size.
+	BoxFlatMinBalance MicroAlgos = 2500
+
+	// BoxByteMinBalance is the minimum balance an application account needs
+	// for each byte of the names and values of its boxes.
+	BoxByteMinBalance MicroAlgos = 400
+
+	// MaxBoxNameLen is the maximum length of a box name.
+	MaxBoxNameLen = 64
+
+	// MaxBoxSize is the maximum size of the value of a box.
+	MaxBoxSize = 32768
+)
+
+// Box is a box of an application: a name unique to the application and its
+// value.
+type Box struct {
+	App   AppIndex
+	Name  []byte
+	Value []byte
+}
+
+// MinBalance returns the minimum balance the account of the application needs
+// for the box, see BoxMinBalance.
+func (b Box) MinBalance() (MicroAlgos, error) {
+	return BoxMinBalance(len(b.Name), len(b.Value))
+}
+
+// BoxMinBalance returns the increase in the minimum balance of an
+// application account for creating a box with a name of nameLen bytes and a
+// value of size bytes: BoxFlatMinBalance plus BoxByteMinBalance for each byte
+// of the name and value. An error is returned if the name or the size exceed
+// MaxBoxNameLen or MaxBoxSize.
+func BoxMinBalance(nameLen, size int) (MicroAlgos, error) {
+	if nameLen < 1 || nameLen > MaxBoxNameLen {
+		return 0, fmt.Errorf("box name length %d is not between 1 and %d", nameLen, MaxBoxNameLen)
+	}
+	if size < 0 || size > MaxBoxSize {
+		return 0, fmt.Errorf("box size %d is not between 0 and %d", size, MaxBoxSize)
+	}
+	return BoxFlatMinBalance + BoxByteMinBalance*MicroAlgos(nameLen+size), nil
+}
+
+// Resolve returns the BoxReference of the box as encoded in an application
+// call to curAppID with the given foreign apps. The AppID of the box must be 0,
+// which references the called app, curAppID, or one of the foreign apps.
+// When curAppID is also a foreign app, the box references it by its index in
+// the foreign apps rather than by 0.
+func (abr AppBoxReference) Resolve(foreignApps []uint64, curAppID uint64) (BoxReference, error) {
+	br := BoxReference{Name: abr.Name}
+	if abr.AppID == 0 {
+		return br, nil
+	}
+	for idx, appID := range foreignApps {
+		if appID == abr.AppID {
+			br.ForeignAppIdx = uint64(idx + 1)
+			return br, nil
+		}
+	}
+	if abr.AppID == curAppID {
+		return br, nil
+	}
+	return BoxReference{}, fmt.Errorf("the app id %d provided for this box is not in the foreignApps array", abr.AppID)
+}
+
+// AppBoxReference returns the reference of the box with the ID of its app, as
+// encoded in an application call to curAppID with the given foreign apps. It
+// is the inverse of AppBoxReference.Resolve. curAppID is 0 for application
+// creation calls, whose boxes are those of the created app.
+func (br BoxReference) AppBoxReference(foreignApps []AppIndex, curAppID AppIndex) (AppBoxReference, error) {
+	abr := AppBoxReference{AppID: uint64(curAppID), Name: br.Name}
+	if br.ForeignAppIdx == 0 {
+		return abr, nil
+	}
+	if br.ForeignAppIdx > uint64(len(foreignApps)) {
+		return AppBoxReference{}, fmt.Errorf("box foreign app index %d is out of range of the %d foreign apps", br.ForeignAppIdx, len(foreignApps))
+	}
+	abr.AppID = uint64(foreignApps[br.ForeignAppIdx-1])
+	return abr, nil
+}
+
+// BoxNameFromString returns the box name for a printable string, equivalent
+// to the goal app call arg form 'str:value'.
+func BoxNameFromString(name string) []byte {
+	return []byte(name)
+}
+
+// BoxNameFromUint64 returns the box name for an integer, encoded as 8 big-endian
+// bytes, equivalent to the goal app call arg form 'int:value'.
+func BoxNameFromUint64(name uint64) []byte {
+	encoded := make([]byte, 8)
+	binary.BigEndian.PutUint64(encoded, name)
+	return encoded
+}
+
+// BoxNameFromAddress returns the box name for an address, equivalent to the
+// goal app call arg form 'addr:value'.
+func BoxNameFromAddress(name Address) []byte {
+	return name[:]
+}
+
+// EncodeBoxName returns the box name in the goal app call arg form
+// 'b64:value', which is accepted by the algod and indexer box endpoints.
+func EncodeBoxName(name []byte) string {
+	return "b64:" + base64.StdEncoding.EncodeToString(name)
+}
+
+// ParseBoxName decodes a box name given in the goal app call arg form
+// 'encoding:value'. Supported encodings are 'str', 'b64', 'int' and 'addr'.
+func ParseBoxName(encoded string) ([]byte, error) {
+	parts := strings.SplitN(encoded, ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("box name %q is not in the form 'encoding:value'", encoded)
+	}
+
+	encoding, value := parts[0], parts[1]
+	switch encoding {
+	case "str", "string":
+		return BoxNameFromString(value), nil
+	case "b64", "base64":
+		return base64.StdEncoding.DecodeString(value)
+	case "int", "integer":
+		name, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse box name %q as an integer: %w", value, err)
+		}
+		return BoxNameFromUint64(name), nil
+	case "addr", "address":
+		addr, err := DecodeAddress(value)
+		if err != nil {
+			return nil, err
+		}
+		return BoxNameFromAddress(addr), nil
+	default:
+		return nil, fmt.Errorf("unknown box name encoding %q", encoding)
+	}
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBoxName(t *testing.T) {
+	addr, err := DecodeAddress("DN7MBMCL5JQ3PFUQS7TMX5AH4EEKOBJVDUF4TCV6WERATKFLQF4MQUPZTA")
+	require.NoError(t, err)
+
+	tests := []struct {
+		encoded  string
+		expected []byte
+	}{
+		{"str:hello", []byte("hello")},
+		{"str:", []byte{}},
+		{"b64:AQID", []byte{1, 2, 3}},
+		{"int:1234", []byte{0, 0, 0, 0, 0, 0, 4, 210}},
+		{"addr:DN7MBMCL5JQ3PFUQS7TMX5AH4EEKOBJVDUF4TCV6WERATKFLQF4MQUPZTA", addr[:]},
+	}
+	for _, test := range tests {
+		t.Run(test.encoded, func(t *testing.T) {
+			name, err := ParseBoxName(test.encoded)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, name)
+		})
+	}
+
+	for _, encoded := range []string{"hello", "int:abc", "b64:!!", "hex:00", "addr:XYZ"} {
+		t.Run(encoded, func(t *testing.T) {
+			_, err := ParseBoxName(encoded)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestEncodeBoxName(t *testing.T) {
+	require.Equal(t, "b64:AQID", EncodeBoxName([]byte{1, 2, 3}))
+
+	name, err := ParseBoxName(EncodeBoxName(BoxNameFromUint64(42)))
+	require.NoError(t, err)
+	require.Equal(t, BoxNameFromUint64(42), name)
+}
+
+func TestBoxMinBalance(t *testing.T) {
+	mbr, err := BoxMinBalance(4, 1024)
+	require.NoError(t, err)
+	require.Equal(t, MicroAlgos(2500+400*(4+1024)), mbr)
+
+	mbr, err = Box{Name: []byte("name"), Value: make([]byte, 1024)}.MinBalance()
+	require.NoError(t, err)
+	require.Equal(t, MicroAlgos(2500+400*(4+1024)), mbr)
+
+	_, err = BoxMinBalance(0, 1)
+	require.Error(t, err)
+	_, err = BoxMinBalance(MaxBoxNameLen+1, 1)
+	require.Error(t, err)
+	_, err = BoxMinBalance(1, MaxBoxSize+1)
+	require.Error(t, err)
+}
+
+func TestBoxReferenceResolution(t *testing.T) {
+	name := []byte("box")
+	foreignApps := []uint64{10, 20}
+	foreignAppIndexes := []AppIndex{10, 20}
+
+	testcases := []struct {
+		name     string
+		appID    uint64
+		curAppID uint64
+		idx      uint64
+		err      bool
+	}{
+		{name: "current app by zero", appID: 0, curAppID: 5, idx: 0},
+		{name: "current app by id", appID: 5, curAppID: 5, idx: 0},
+		{name: "foreign app", appID: 20, curAppID: 5, idx: 2},
+		{name: "current app also foreign", appID: 10, curAppID: 10, idx: 1},
+		{name: "unknown app", appID: 30, curAppID: 5, err: true},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			br, err := AppBoxReference{AppID: tc.appID, Name: name}.Resolve(foreignApps, tc.curAppID)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, BoxReference{ForeignAppIdx: tc.idx, Name: name}, br)
+
+			abr, err := br.AppBoxReference(foreignAppIndexes, AppIndex(tc.curAppID))
+			require.NoError(t, err)
+			require.Equal(t, name, abr.Name)
+			if tc.appID == 0 {
+				require.Equal(t, tc.curAppID, abr.AppID)
+			} else {
+				require.Equal(t, tc.appID, abr.AppID)
+			}
+		})
+	}
+
+	_, err := BoxReference{ForeignAppIdx: 3, Name: name}.AppBoxReference(foreignAppIndexes, 5)
+	require.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package types
+
+// VrfProof is a VRF proof of a committee member's selection.
+type VrfProof [80]byte
+
+// UnauthenticatedCredential is the credential a committee member attaches to
+// its vote. It has not been verified against the member's selection key.
+type UnauthenticatedCredential struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Proof VrfProof `codec:"pf"`
+}
+
+// OneTimeSignature is a two-level ephemeral signature produced with a
+// participation key.
+type OneTimeSignature struct {
+	// This struct was never marked omitempty in go-algorand, so its zero
+	// fields are part of the certificate encoding.
+	_struct struct{} `codec:""`
+
+	// Sig is a signature of msg under the key PK.
+	Sig Signature `codec:"s"`
+	PK  [32]byte  `codec:"p"`
+
+	// PKSigOld is unused, but appears (with zero value) in certificates.
+	PKSigOld Signature `codec:"ps"`
+
+	// PK1Sig is a signature of the (PK, batch, offset) under the key PK2.
+	// PK2Sig is a signature of the (PK2, batch) under the voting key.
+	PK2    [32]byte  `codec:"p2"`
+	PK1Sig Signature `codec:"p1s"`
+	PK2Sig Signature `codec:"p2s"`
+}
+
+// ProposalValue identifies a proposed block.
+type ProposalValue struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	// OriginalPeriod is the period in which the block was first proposed.
+	OriginalPeriod uint64 `codec:"oper"`
+
+	// OriginalProposer is the account which first proposed the block.
+	OriginalProposer Address `codec:"oprop"`
+
+	// BlockDigest is the hash of the proposed block header.
+	BlockDigest Digest `codec:"dig"`
+
+	// EncodingDigest is the hash of the proposal's encoding.
+	EncodingDigest Digest `codec:"encdig"`
+}
+
+// VoteAuthenticator is a single committee member's vote for the certified
+// proposal.
+type VoteAuthenticator struct {
+	_struct struct{} `codec:""`
+
+	Sender Address                   `codec:"snd"`
+	Cred   UnauthenticatedCredential `codec:"cred"`
+	Sig    OneTimeSignature          `codec:"sig,omitempty,omitemptycheckstruct"`
+}
+
+// EquivocationVoteAuthenticator is a pair of votes for distinct proposals by
+// the same committee member, which count towards the certificate threshold.
+type EquivocationVoteAuthenticator struct {
+	_struct struct{} `codec:","`
+
+	Sender    Address                   `codec:"snd"`
+	Cred      UnauthenticatedCredential `codec:"cred"`
+	Sigs      [2]OneTimeSignature       `codec:"sig,allocbound=2"`
+	Proposals [2]ProposalValue          `codec:"props,allocbound=2"`
+}
+
+// Certificate is a bundle of votes proving that the agreement protocol
+// committed a block.
+type Certificate struct {
+	_struct struct{} `codec:","`
+
+	Round    Round         `codec:"rnd"`
+	Period   uint64        `codec:"per"`
+	Step     uint64        `codec:"step"`
+	Proposal ProposalValue `codec:"prop"`
+
+	Votes             []VoteAuthenticator             `codec:"vote,allocbound=config.MaxVoteThreshold"`
+	EquivocationVotes []EquivocationVoteAuthenticator `codec:"eqv,allocbound=config.MaxVoteThreshold"`
+}
+
+// EncodedBlockCert is a block along with the certificate that committed it.
+type EncodedBlockCert struct {
+	_struct struct{} `codec:""`
+
+	Block       Block       `codec:"block"`
+	Certificate Certificate `codec:"cert"`
+}
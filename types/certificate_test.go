@@ -0,0 +1,35 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+)
+
+func TestEncodedBlockCertRoundTrip(t *testing.T) {
+	cert := EncodedBlockCert{
+		Block: Block{BlockHeader: BlockHeader{Round: 10, GenesisID: "test-v1"}},
+		Certificate: Certificate{
+			Round:    10,
+			Step:     2,
+			Proposal: ProposalValue{OriginalProposer: Address{1}, BlockDigest: Digest{2}},
+			Votes: []VoteAuthenticator{
+				{Sender: Address{3}, Cred: UnauthenticatedCredential{Proof: VrfProof{4}}},
+			},
+		},
+	}
+
+	var decoded EncodedBlockCert
+	require.NoError(t, msgpack.Decode(msgpack.Encode(cert), &decoded))
+	require.Equal(t, cert, decoded)
+}
+
+func TestOneTimeSignatureEncodesZeroFields(t *testing.T) {
+	// go-algorand never marked OneTimeSignature as omitempty, so zero fields
+	// must be encoded to preserve certificate encodings.
+	var decoded map[string]interface{}
+	require.NoError(t, msgpack.Decode(msgpack.Encode(OneTimeSignature{}), &decoded))
+	require.Len(t, decoded, 6)
+}
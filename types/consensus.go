@@ -0,0 +1,180 @@
+package types
+
+// ConsensusVersion is the identifier of a version of the consensus protocol,
+// as found in BlockHeader.CurrentProtocol and in the status and suggested
+// parameters returned by algod.
+type ConsensusVersion string
+
+const (
+	// ConsensusV38 is version 38 of the consensus protocol.
+	ConsensusV38 = ConsensusVersion("https://github.com/algorandfoundation/specs/tree/abd3d4823c6f77349fc04c3af7b1e99fe4df699f")
+
+	// ConsensusV39 is version 39 of the consensus protocol.
+	ConsensusV39 = ConsensusVersion("https://github.com/algorandfoundation/specs/tree/925a46433742afb0b51bb939354bd907fa88bf95")
+
+	// ConsensusV40 is version 40 of the consensus protocol, which pays
+	// incentives to block proposers.
+	ConsensusV40 = ConsensusVersion("https://github.com/algorandfoundation/specs/tree/236dcc18c9c507d794813ab768e467ea42d1b4d9")
+
+	// ConsensusFuture is the version of development networks running
+	// unreleased protocol changes.
+	ConsensusFuture = ConsensusVersion("future")
+
+	// ConsensusCurrentVersion is the latest released version in the table.
+	ConsensusCurrentVersion = ConsensusV40
+)
+
+// ConsensusParams are the consensus parameters which constrain transactions
+// and applications, as defined by go-algorand's config.ConsensusParams.
+type ConsensusParams struct {
+	// MinTxnFee is the minimum fee of a transaction, or of each transaction
+	// of a group when fees are pooled.
+	MinTxnFee MicroAlgos
+
+	// MinBalance is the minimum balance of an account.
+	MinBalance MicroAlgos
+
+	// MaxTxnLife is the maximum number of rounds between the first and last
+	// valid rounds of a transaction.
+	MaxTxnLife uint64
+
+	// MaxTxnNoteBytes is the maximum length of a transaction note.
+	MaxTxnNoteBytes int
+
+	// MaxTxGroupSize is the maximum number of transactions in a group.
+	MaxTxGroupSize int
+
+	// LogicSigMaxSize is the maximum size of a LogicSig program and its
+	// arguments.
+	LogicSigMaxSize int
+
+	// LogicSigMaxCost is the maximum cost of evaluating a LogicSig.
+	LogicSigMaxCost uint64
+
+	// MaxAppArgs is the maximum number of arguments of an application call.
+	MaxAppArgs int
+
+	// MaxAppTotalArgLen is the maximum total length of the arguments of an
+	// application call.
+	MaxAppTotalArgLen int
+
+	// MaxAppTxnAccounts is the maximum number of accounts an application
+	// call can reference.
+	MaxAppTxnAccounts int
+
+	// MaxAppTotalTxnReferences is the maximum number of accounts, apps,
+	// assets and boxes an application call can reference in total.
+	MaxAppTotalTxnReferences int
+
+	// MaxAppBoxReferences is the maximum number of boxes an application
+	// call can reference.
+	MaxAppBoxReferences int
+
+	// MaxAppProgramLen is the maximum length of an approval or clear state
+	// program, not counting extra pages.
+	MaxAppProgramLen int
+
+	// MaxExtraAppProgramPages is the maximum number of extra pages of
+	// MaxAppProgramLen bytes an application can have.
+	MaxExtraAppProgramPages int
+
+	// MaxAppProgramCost is the budget of an application call.
+	MaxAppProgramCost int
+
+	// MaxInnerTransactions is the number of inner transactions an
+	// application call can issue.
+	MaxInnerTransactions int
+
+	// MaxAppKeyLen is the maximum length of a key of application state.
+	MaxAppKeyLen int
+
+	// MaxAppBytesValueLen is the maximum length of a bytes value of
+	// application state.
+	MaxAppBytesValueLen int
+
+	// MaxAppSumKeyValueLens is the maximum total length of a key and its
+	// value in application state.
+	MaxAppSumKeyValueLens int
+
+	// MaxBoxSize is the maximum size of a box.
+	MaxBoxSize int
+
+	// BoxFlatMinBalance and BoxByteMinBalance are the minimum balance
+	// requirement of a box, see BoxMinBalance.
+	BoxFlatMinBalance MicroAlgos
+	BoxByteMinBalance MicroAlgos
+
+	// BytesPerBoxReference is the number of box bytes each box reference
+	// of a group makes available to read and write.
+	BytesPerBoxReference int
+
+	// LogicSigVersion is the highest AVM version of logic signatures and
+	// application programs.
+	LogicSigVersion int
+}
+
+// v38Params are the parameters of ConsensusV38, which later versions left
+// unchanged except for the AVM version.
+var v38Params = ConsensusParams{
+	MinTxnFee:                1000,
+	MinBalance:               100000,
+	MaxTxnLife:               1000,
+	MaxTxnNoteBytes:          1024,
+	MaxTxGroupSize:           MaxTxGroupSize,
+	LogicSigMaxSize:          LogicSigMaxSize,
+	LogicSigMaxCost:          LogicSigMaxCost,
+	MaxAppArgs:               16,
+	MaxAppTotalArgLen:        2048,
+	MaxAppTxnAccounts:        4,
+	MaxAppTotalTxnReferences: 8,
+	MaxAppBoxReferences:      8,
+	MaxAppProgramLen:         2048,
+	MaxExtraAppProgramPages:  3,
+	MaxAppProgramCost:        700,
+	MaxInnerTransactions:     16,
+	MaxAppKeyLen:             64,
+	MaxAppBytesValueLen:      128,
+	MaxAppSumKeyValueLens:    128,
+	MaxBoxSize:               MaxBoxSize,
+	BoxFlatMinBalance:        BoxFlatMinBalance,
+	BoxByteMinBalance:        BoxByteMinBalance,
+	BytesPerBoxReference:     1024,
+	LogicSigVersion:          9,
+}
+
+func withLogicSigVersion(params ConsensusParams, version int) ConsensusParams {
+	params.LogicSigVersion = version
+	return params
+}
+
+// Consensus is the table of the consensus parameters of each version.
+var Consensus = map[ConsensusVersion]ConsensusParams{
+	ConsensusV38:    v38Params,
+	ConsensusV39:    withLogicSigVersion(v38Params, 10),
+	ConsensusV40:    withLogicSigVersion(v38Params, 11),
+	ConsensusFuture: withLogicSigVersion(v38Params, 11),
+}
+
+// ConsensusParamsFor returns the consensus parameters of version, and whether
+// it is in the table. The parameters of ConsensusCurrentVersion are returned
+// for unknown versions, so that callers which do not need to be exact can
+// ignore the second result.
+func ConsensusParamsFor(version ConsensusVersion) (ConsensusParams, bool) {
+	params, ok := Consensus[version]
+	if !ok {
+		return Consensus[ConsensusCurrentVersion], false
+	}
+	return params, true
+}
+
+// MaxAppTotalProgramLen is the maximum total length of the approval and clear
+// state programs of an application with extraPages extra pages.
+func (params ConsensusParams) MaxAppTotalProgramLen(extraPages int) int {
+	return params.MaxAppProgramLen * (1 + extraPages)
+}
+
+// MinFee returns the minimum total fee of a group of n transactions, with
+// fees pooled.
+func (params ConsensusParams) MinFee(n int) MicroAlgos {
+	return params.MinTxnFee * MicroAlgos(n)
+}
@@ -0,0 +1,25 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsensusParamsFor(t *testing.T) {
+	params, ok := ConsensusParamsFor(ConsensusV39)
+	require.True(t, ok)
+	require.Equal(t, MicroAlgos(1000), params.MinTxnFee)
+	require.Equal(t, MicroAlgos(4000), params.MinFee(4))
+	require.Equal(t, 8192, params.MaxAppTotalProgramLen(params.MaxExtraAppProgramPages))
+	require.Equal(t, 10, params.LogicSigVersion)
+
+	// the box parameters agree with the box minimum balance calculation
+	mbr, err := BoxMinBalance(1, params.MaxBoxSize)
+	require.NoError(t, err)
+	require.Equal(t, params.BoxFlatMinBalance+params.BoxByteMinBalance*MicroAlgos(1+params.MaxBoxSize), mbr)
+
+	unknown, ok := ConsensusParamsFor("unknown")
+	require.False(t, ok)
+	require.Equal(t, Consensus[ConsensusCurrentVersion], unknown)
+}
@@ -3,7 +3,10 @@ package types
 import (
 	"crypto/sha512"
 	"fmt"
+	"io/ioutil"
+	"sort"
 
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
 	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
 )
 
@@ -64,10 +67,58 @@ type Account struct {
 	VoteID          [32]byte `codec:"vote"`
 	SelectionID     [32]byte `codec:"sel"`
 	StateProofID    [64]byte `codec:"stprf"`
+	VoteFirstValid  uint64   `codec:"voteFst"`
 	VoteLastValid   uint64   `codec:"voteLst"`
 	VoteKeyDilution uint64   `codec:"voteKD"`
 }
 
+// DecodeGenesis decodes a genesis.json file, as returned by algod's /genesis
+// endpoint.
+func DecodeGenesis(data []byte) (genesis Genesis, err error) {
+	err = json.Decode(data, &genesis)
+	return
+}
+
+// EncodeGenesis encodes a genesis.json file, in the format of the genesis files
+// of the public networks. Nodes of a private network must all be given the
+// same encoding, since it determines the genesis hash.
+func EncodeGenesis(genesis Genesis) []byte {
+	return json.Encode(genesis)
+}
+
+// MakeGenesis returns the Genesis of a new network with the given initial
+// balances, e.g. to bootstrap a private network. It is the inverse of
+// Genesis.Balances, with the allocations sorted by address so that the same
+// balances always give the same genesis hash.
+func MakeGenesis(network, schemaID string, proto ConsensusVersion, balances GenesisBalances) Genesis {
+	allocation := make([]GenesisAllocation, 0, len(balances.Balances))
+	for addr, state := range balances.Balances {
+		allocation = append(allocation, GenesisAllocation{Address: addr.String(), State: state})
+	}
+	sort.Slice(allocation, func(i, j int) bool {
+		return allocation[i].Address < allocation[j].Address
+	})
+
+	return Genesis{
+		SchemaID:    schemaID,
+		Network:     network,
+		Proto:       string(proto),
+		Allocation:  allocation,
+		RewardsPool: balances.RewardsPool.String(),
+		FeeSink:     balances.FeeSink.String(),
+		Timestamp:   balances.Timestamp,
+	}
+}
+
+// LoadGenesisFromFile reads and decodes a genesis.json file.
+func LoadGenesisFromFile(path string) (Genesis, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Genesis{}, err
+	}
+	return DecodeGenesis(data)
+}
+
 // ID is the effective Genesis identifier - the combination
 // of the network and the ledger schema version
 func (genesis Genesis) ID() string {
@@ -153,3 +153,55 @@ func TestGenesis_Balances(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadGenesisFromFile(t *testing.T) {
+	genesis, err := LoadGenesisFromFile("test_resource/mainnet_genesis.json")
+	require.NoError(t, err)
+	assert.Equal(t, "mainnet-v1.0", genesis.ID())
+	assert.Equal(t, "mainnet", genesis.Network)
+	assert.NotEmpty(t, genesis.Allocation)
+
+	_, err = LoadGenesisFromFile("test_resource/missing.json")
+	require.Error(t, err)
+
+	_, err = DecodeGenesis([]byte("not json"))
+	require.Error(t, err)
+}
+
+func TestMakeGenesis(t *testing.T) {
+	var feeSink, rewardsPool, online, offline Address
+	feeSink[0], rewardsPool[0], online[0], offline[0] = 1, 2, 3, 4
+
+	balances := MakeTimestampedGenesisBalances(map[Address]Account{
+		feeSink:     {MicroAlgos: 100000, Status: byte(NotParticipating)},
+		rewardsPool: {MicroAlgos: 100000, Status: byte(NotParticipating)},
+		offline:     {MicroAlgos: 1000000},
+		online: {
+			MicroAlgos:      5000000,
+			Status:          byte(Online),
+			VoteID:          [32]byte{5},
+			SelectionID:     [32]byte{6},
+			VoteFirstValid:  1,
+			VoteLastValid:   1000000,
+			VoteKeyDilution: 1000,
+		},
+	}, feeSink, rewardsPool, 1700000000)
+
+	genesis := MakeGenesis("privnet", "v1", ConsensusFuture, balances)
+	require.Equal(t, "privnet-v1", genesis.ID())
+	require.Len(t, genesis.Allocation, 4)
+	for i := 1; i < len(genesis.Allocation); i++ {
+		require.Less(t, genesis.Allocation[i-1].Address, genesis.Allocation[i].Address)
+	}
+
+	decodedBalances, err := genesis.Balances()
+	require.NoError(t, err)
+	require.Equal(t, balances, decodedBalances)
+
+	// the genesis hash is preserved by the genesis.json encoding
+	decoded, err := DecodeGenesis(EncodeGenesis(genesis))
+	require.NoError(t, err)
+	require.Equal(t, genesis, decoded)
+	require.Equal(t, genesis.Hash(), decoded.Hash())
+	require.Equal(t, genesis.Hash(), MakeGenesis("privnet", "v1", ConsensusFuture, balances).Hash())
+}
@@ -0,0 +1,143 @@
+package types
+
+import (
+	"reflect"
+
+	"github.com/algorand/go-codec/codec"
+)
+
+// algodJSONHandle encodes JSON the way algod and goal do: short field names,
+// byte fields in base64 and addresses in their base32 checksum form. Unknown
+// fields are ignored when decoding, so that JSON produced by newer versions
+// can still be read.
+var algodJSONHandle *codec.JsonHandle
+
+func init() {
+	algodJSONHandle = new(codec.JsonHandle)
+	algodJSONHandle.ErrorIfNoField = false
+	algodJSONHandle.ErrorIfNoArrayExpand = true
+	algodJSONHandle.Canonical = true
+	algodJSONHandle.RecursiveEmptyCheck = true
+	algodJSONHandle.HTMLCharsAsIs = true
+	if err := algodJSONHandle.SetInterfaceExt(reflect.TypeOf(Address{}), 1, addressJSONExt{}); err != nil {
+		panic(err)
+	}
+}
+
+// addressJSONExt encodes addresses as base32 checksum strings. Base64 is also
+// accepted when decoding, as produced by Address.MarshalText.
+type addressJSONExt struct{}
+
+func (addressJSONExt) ConvertExt(v interface{}) interface{} {
+	return v.(*Address).String()
+}
+
+func (addressJSONExt) UpdateExt(dst interface{}, src interface{}) {
+	text, ok := src.(string)
+	if !ok {
+		panic(errWrongAddressLen)
+	}
+	if err := dst.(*Address).UnmarshalText([]byte(text)); err != nil {
+		panic(err)
+	}
+}
+
+func encodeAlgodJSON(obj interface{}) (b []byte, err error) {
+	err = codec.NewEncoderBytes(&b, algodJSONHandle).Encode(obj)
+	return
+}
+
+func decodeAlgodJSON(b []byte, objptr interface{}) error {
+	return codec.NewDecoderBytes(b, algodJSONHandle).Decode(objptr)
+}
+
+// The JSON methods encode through types without them, to avoid recursing.
+// Since SignedTxn is embedded in SignedTxnWithAD, which is embedded in
+// SignedTxnInBlock, those types also have JSON methods so that the ones of
+// SignedTxn are not promoted to them, and their JSON types flatten SignedTxn
+// through a type without methods.
+type (
+	transactionJSON Transaction
+	signedTxnJSON   SignedTxn
+
+	signedTxnWithADJSON struct {
+		_struct struct{} `codec:",omitempty,omitemptyarray"`
+		signedTxnJSON
+		ApplyData
+	}
+
+	signedTxnInBlockJSON struct {
+		_struct struct{} `codec:",omitempty,omitemptyarray"`
+		signedTxnJSON
+		ApplyData
+		HasGenesisID   bool `codec:"hgi"`
+		HasGenesisHash bool `codec:"hgh"`
+	}
+)
+
+// MarshalJSON encodes the transaction the way algod and goal do, e.g. in the
+// output of `goal clerk inspect`: short field names, byte fields in base64
+// and addresses in base32.
+func (tx Transaction) MarshalJSON() ([]byte, error) {
+	return encodeAlgodJSON(transactionJSON(tx))
+}
+
+// UnmarshalJSON decodes a transaction encoded by MarshalJSON, algod or goal.
+func (tx *Transaction) UnmarshalJSON(b []byte) error {
+	return decodeAlgodJSON(b, (*transactionJSON)(tx))
+}
+
+// MarshalJSON encodes the signed transaction the way algod and goal do, see
+// Transaction.MarshalJSON.
+func (stx SignedTxn) MarshalJSON() ([]byte, error) {
+	return encodeAlgodJSON(signedTxnJSON(stx))
+}
+
+// UnmarshalJSON decodes a signed transaction encoded by MarshalJSON, algod or
+// goal.
+func (stx *SignedTxn) UnmarshalJSON(b []byte) error {
+	return decodeAlgodJSON(b, (*signedTxnJSON)(stx))
+}
+
+// MarshalJSON encodes the signed transaction and its ApplyData the way algod
+// does, see Transaction.MarshalJSON.
+func (stx SignedTxnWithAD) MarshalJSON() ([]byte, error) {
+	return encodeAlgodJSON(signedTxnWithADJSON{signedTxnJSON: signedTxnJSON(stx.SignedTxn), ApplyData: stx.ApplyData})
+}
+
+// UnmarshalJSON decodes a signed transaction and its ApplyData encoded by
+// MarshalJSON or algod.
+func (stx *SignedTxnWithAD) UnmarshalJSON(b []byte) error {
+	var decoded signedTxnWithADJSON
+	if err := decodeAlgodJSON(b, &decoded); err != nil {
+		return err
+	}
+	*stx = SignedTxnWithAD{SignedTxn: SignedTxn(decoded.signedTxnJSON), ApplyData: decoded.ApplyData}
+	return nil
+}
+
+// MarshalJSON encodes the transaction the way it is encoded in algod's JSON
+// blocks, see Transaction.MarshalJSON.
+func (stib SignedTxnInBlock) MarshalJSON() ([]byte, error) {
+	return encodeAlgodJSON(signedTxnInBlockJSON{
+		signedTxnJSON:  signedTxnJSON(stib.SignedTxn),
+		ApplyData:      stib.ApplyData,
+		HasGenesisID:   stib.HasGenesisID,
+		HasGenesisHash: stib.HasGenesisHash,
+	})
+}
+
+// UnmarshalJSON decodes a transaction encoded by MarshalJSON or in algod's
+// JSON blocks.
+func (stib *SignedTxnInBlock) UnmarshalJSON(b []byte) error {
+	var decoded signedTxnInBlockJSON
+	if err := decodeAlgodJSON(b, &decoded); err != nil {
+		return err
+	}
+	*stib = SignedTxnInBlock{
+		SignedTxnWithAD: SignedTxnWithAD{SignedTxn: SignedTxn(decoded.signedTxnJSON), ApplyData: decoded.ApplyData},
+		HasGenesisID:    decoded.HasGenesisID,
+		HasGenesisHash:  decoded.HasGenesisHash,
+	}
+	return nil
+}
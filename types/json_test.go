@@ -0,0 +1,82 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkjson "github.com/algorand/go-algorand-sdk/v2/encoding/json"
+)
+
+// goalSignedTxn is a signed transaction as printed by `goal clerk inspect`.
+const goalSignedTxn = `{
+  "sig": "AQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA==",
+  "txn": {
+    "amt": 5,
+    "fee": 1000,
+    "fv": 10,
+    "gen": "testnet-v1.0",
+    "gh": "SGO1GKSzyE7IEPItTxCByw9x8FmnrCDexi9/cOUJOiI=",
+    "lv": 1010,
+    "note": "aGk=",
+    "rcv": "GD64YIY3TWGDMCNPP553DZPPR6LDUSFQOIJVFDPPXWEG3FVOJCCDBBHU5A",
+    "snd": "7ZUECA7HFLZTXENRV24SHLU4AVPUTMTTDUFUBNBD64C73F3UHRTHAIOF6Q",
+    "type": "pay"
+  }
+}`
+
+func TestSignedTxnJSON(t *testing.T) {
+	var stx SignedTxn
+	require.NoError(t, json.Unmarshal([]byte(goalSignedTxn), &stx))
+
+	sender, err := DecodeAddress("7ZUECA7HFLZTXENRV24SHLU4AVPUTMTTDUFUBNBD64C73F3UHRTHAIOF6Q")
+	require.NoError(t, err)
+	require.Equal(t, sender, stx.Txn.Sender)
+	require.Equal(t, MicroAlgos(5), stx.Txn.Amount)
+	require.Equal(t, "testnet-v1.0", stx.Txn.GenesisID)
+	require.Equal(t, []byte("hi"), stx.Txn.Note)
+	require.Equal(t, byte(1), stx.Sig[0])
+
+	encoded, err := json.MarshalIndent(stx, "", "  ")
+	require.NoError(t, err)
+	require.JSONEq(t, goalSignedTxn, string(encoded))
+	require.Equal(t, goalSignedTxn, string(encoded))
+}
+
+func TestTransactionJSONBase64Address(t *testing.T) {
+	// addresses encoded in base64, as by Address.MarshalText, are accepted
+	var tx Transaction
+	require.NoError(t, json.Unmarshal([]byte(`{"snd": "AQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=", "type": "pay"}`), &tx))
+	require.Equal(t, Address{1}, tx.Sender)
+
+	require.Error(t, json.Unmarshal([]byte(`{"snd": "not an address"}`), &tx))
+}
+
+func TestSignedTxnInBlockJSON(t *testing.T) {
+	stib := SignedTxnInBlock{HasGenesisID: true}
+	stib.Txn.Type = PaymentTx
+	stib.Txn.Sender = Address{1}
+	stib.AuthAddr = Address{2}
+	stib.ApplyData.SenderRewards = 7
+	stib.EvalDelta.InnerTxns = []SignedTxnWithAD{{SignedTxn: SignedTxn{Txn: Transaction{Type: PaymentTx, Header: Header{Sender: Address{3}}}}}}
+
+	encoded, err := json.Marshal(stib)
+	require.NoError(t, err)
+	require.Contains(t, string(encoded), `"sgnr":"`+Address{2}.String()+`"`)
+	require.Contains(t, string(encoded), `"snd":"`+Address{3}.String()+`"`)
+	require.Contains(t, string(encoded), `"hgi":true`)
+	require.Contains(t, string(encoded), `"rs":7`)
+
+	var decoded SignedTxnInBlock
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Equal(t, stib, decoded)
+
+	// the SDK's codec uses the methods for nested transactions
+	block := Block{Payset: Payset{stib}}
+	encoded = sdkjson.Encode(block)
+	require.Contains(t, string(encoded), Address{3}.String())
+	var decodedBlock Block
+	require.NoError(t, sdkjson.Decode(encoded, &decodedBlock))
+	require.Equal(t, block, decodedBlock)
+}
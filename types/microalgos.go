@@ -0,0 +1,106 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// ErrMicroAlgosOverflow is returned by the checked MicroAlgos operations when
+// the result does not fit in a MicroAlgos, or would be negative.
+var ErrMicroAlgosOverflow = errors.New("microalgos overflow")
+
+// ErrInvalidAlgos is returned by ParseAlgos for strings which are not a
+// decimal amount of Algos.
+var ErrInvalidAlgos = errors.New("invalid amount of algos")
+
+// microAlgoDecimals is the number of decimal places of an amount in Algos
+// which MicroAlgos represent exactly.
+const microAlgoDecimals = 6
+
+// Add returns microalgos + other, or ErrMicroAlgosOverflow.
+func (microalgos MicroAlgos) Add(other MicroAlgos) (MicroAlgos, error) {
+	res, overflowed := OAdd(uint64(microalgos), uint64(other))
+	if overflowed {
+		return 0, fmt.Errorf("%w: %d + %d", ErrMicroAlgosOverflow, microalgos, other)
+	}
+	return MicroAlgos(res), nil
+}
+
+// Sub returns microalgos - other, or ErrMicroAlgosOverflow if other is
+// larger.
+func (microalgos MicroAlgos) Sub(other MicroAlgos) (MicroAlgos, error) {
+	res, overflowed := OSub(uint64(microalgos), uint64(other))
+	if overflowed {
+		return 0, fmt.Errorf("%w: %d - %d", ErrMicroAlgosOverflow, microalgos, other)
+	}
+	return MicroAlgos(res), nil
+}
+
+// MulDiv returns microalgos * numerator / denominator rounded down, e.g. to
+// take a percentage of an amount. The product is computed in 128 bits, so
+// only the result needs to fit in a MicroAlgos.
+func (microalgos MicroAlgos) MulDiv(numerator, denominator uint64) (MicroAlgos, error) {
+	if denominator == 0 {
+		return 0, errors.New("microalgos division by zero")
+	}
+	hi, lo := bits.Mul64(uint64(microalgos), numerator)
+	if hi >= denominator {
+		return 0, fmt.Errorf("%w: %d * %d / %d", ErrMicroAlgosOverflow, microalgos, numerator, denominator)
+	}
+	quo, _ := bits.Div64(hi, lo, denominator)
+	return MicroAlgos(quo), nil
+}
+
+// AlgosString returns the amount in Algos as an exact decimal string with
+// all six decimal places, e.g. "1.500000".
+func (microalgos MicroAlgos) AlgosString() string {
+	return fmt.Sprintf("%d.%06d", uint64(microalgos)/microAlgoConversionFactor, uint64(microalgos)%microAlgoConversionFactor)
+}
+
+// ParseAlgos parses a decimal amount of Algos, such as "1.5" or "0.000001",
+// into MicroAlgos exactly. Unlike ToMicroAlgos it does not go through a
+// float64, and it returns an error for amounts with more than six decimal
+// places, negative amounts and amounts which do not fit in a MicroAlgos.
+func ParseAlgos(s string) (MicroAlgos, error) {
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if whole == "" && frac == "" || len(frac) > microAlgoDecimals || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAlgos, s)
+	}
+
+	var algos uint64
+	if whole != "" {
+		var err error
+		algos, err = strconv.ParseUint(whole, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrMicroAlgosOverflow, s)
+		}
+	}
+	var fraction uint64
+	if frac != "" {
+		fraction, _ = strconv.ParseUint(frac+strings.Repeat("0", microAlgoDecimals-len(frac)), 10, 64)
+	}
+
+	amount, overflowed := OMul(algos, microAlgoConversionFactor)
+	if !overflowed {
+		amount, overflowed = OAdd(amount, fraction)
+	}
+	if overflowed {
+		return 0, fmt.Errorf("%w: %q", ErrMicroAlgosOverflow, s)
+	}
+	return MicroAlgos(amount), nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMicroAlgosArithmetic(t *testing.T) {
+	max := MicroAlgos(math.MaxUint64)
+
+	sum, err := MicroAlgos(1).Add(2)
+	require.NoError(t, err)
+	require.Equal(t, MicroAlgos(3), sum)
+	_, err = max.Add(1)
+	require.ErrorIs(t, err, ErrMicroAlgosOverflow)
+
+	diff, err := MicroAlgos(3).Sub(2)
+	require.NoError(t, err)
+	require.Equal(t, MicroAlgos(1), diff)
+	_, err = MicroAlgos(2).Sub(3)
+	require.ErrorIs(t, err, ErrMicroAlgosOverflow)
+
+	// 2.5% of the maximum amount, whose product with the numerator overflows
+	// 64 bits
+	share, err := max.MulDiv(25, 1000)
+	require.NoError(t, err)
+	require.Equal(t, MicroAlgos(uint64(math.MaxUint64)/40), share)
+	share, err = MicroAlgos(10).MulDiv(1, 3)
+	require.NoError(t, err)
+	require.Equal(t, MicroAlgos(3), share)
+	_, err = max.MulDiv(2, 1)
+	require.ErrorIs(t, err, ErrMicroAlgosOverflow)
+	_, err = MicroAlgos(1).MulDiv(1, 0)
+	require.Error(t, err)
+}
+
+func TestParseAlgos(t *testing.T) {
+	testcases := []struct {
+		input    string
+		expected MicroAlgos
+		err      error
+	}{
+		{input: "1", expected: 1000000},
+		{input: "1.5", expected: 1500000},
+		{input: "0.000001", expected: 1},
+		{input: ".25", expected: 250000},
+		{input: "2.", expected: 2000000},
+		{input: "18446744073709.551615", expected: math.MaxUint64},
+		{input: "18446744073709.551616", err: ErrMicroAlgosOverflow},
+		{input: "100000000000000000000", err: ErrMicroAlgosOverflow},
+		{input: "0.0000001", err: ErrInvalidAlgos},
+		{input: "-1", err: ErrInvalidAlgos},
+		{input: "1e6", err: ErrInvalidAlgos},
+		{input: ".", err: ErrInvalidAlgos},
+		{input: "", err: ErrInvalidAlgos},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			amount, err := ParseAlgos(tc.input)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, amount)
+
+			parsed, err := ParseAlgos(amount.AlgosString())
+			require.NoError(t, err)
+			require.Equal(t, amount, parsed)
+		})
+	}
+
+	require.Equal(t, "1.500000", MicroAlgos(1500000).AlgosString())
+	require.Equal(t, "0.000001", MicroAlgos(1).AlgosString())
+}
@@ -1,5 +1,11 @@
 package types
 
+import (
+	"bytes"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+)
+
 // TealType is an enum of the types in a TEAL program: Bytes and Uint
 type TealType uint64
 
@@ -74,22 +80,28 @@ type AppLocalState struct {
 
 // AppLocalStateDelta tracks a changed AppLocalState, and whether it was deleted
 type AppLocalStateDelta struct {
-	LocalState *AppLocalState
-	Deleted    bool
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	LocalState *AppLocalState `codec:"LocalState"`
+	Deleted    bool           `codec:"Deleted"`
 }
 
 // AppParamsDelta tracks a changed AppParams, and whether it was deleted
 type AppParamsDelta struct {
-	Params  *AppParams
-	Deleted bool
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Params  *AppParams `codec:"Params"`
+	Deleted bool       `codec:"Deleted"`
 }
 
 // AppResourceRecord represents AppParams and AppLocalState in deltas
 type AppResourceRecord struct {
-	Aidx   AppIndex
-	Addr   Address
-	Params AppParamsDelta
-	State  AppLocalStateDelta
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Aidx   AppIndex           `codec:"Aidx"`
+	Addr   Address            `codec:"Addr"`
+	Params AppParamsDelta     `codec:"Params"`
+	State  AppLocalStateDelta `codec:"State"`
 }
 
 // AssetHolding describes an asset held by an account.
@@ -102,22 +114,28 @@ type AssetHolding struct {
 
 // AssetHoldingDelta records a changed AssetHolding, and whether it was deleted
 type AssetHoldingDelta struct {
-	Holding *AssetHolding
-	Deleted bool
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Holding *AssetHolding `codec:"Holding"`
+	Deleted bool          `codec:"Deleted"`
 }
 
 // AssetParamsDelta tracks a changed AssetParams, and whether it was deleted
 type AssetParamsDelta struct {
-	Params  *AssetParams
-	Deleted bool
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Params  *AssetParams `codec:"Params"`
+	Deleted bool         `codec:"Deleted"`
 }
 
 // AssetResourceRecord represents AssetParams and AssetHolding in deltas
 type AssetResourceRecord struct {
-	Aidx    AssetIndex
-	Addr    Address
-	Params  AssetParamsDelta
-	Holding AssetHoldingDelta
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Aidx    AssetIndex        `codec:"Aidx"`
+	Addr    Address           `codec:"Addr"`
+	Params  AssetParamsDelta  `codec:"Params"`
+	Holding AssetHoldingDelta `codec:"Holding"`
 }
 
 // AccountAsset is used as a map key.
@@ -142,31 +160,39 @@ type VRFVerifier [32]byte
 
 // VotingData holds participation information
 type VotingData struct {
-	VoteID       OneTimeSignatureVerifier
-	SelectionID  VRFVerifier
-	StateProofID Commitment
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
 
-	VoteFirstValid  Round
-	VoteLastValid   Round
-	VoteKeyDilution uint64
+	VoteID       OneTimeSignatureVerifier `codec:"VoteID"`
+	SelectionID  VRFVerifier              `codec:"SelectionID"`
+	StateProofID Commitment               `codec:"StateProofID"`
+
+	VoteFirstValid  Round  `codec:"VoteFirstValid"`
+	VoteLastValid   Round  `codec:"VoteLastValid"`
+	VoteKeyDilution uint64 `codec:"VoteKeyDilution"`
 }
 
 // AccountBaseData contains base account info like balance, status and total number of resources
 type AccountBaseData struct {
-	Status             Status
-	MicroAlgos         MicroAlgos
-	RewardsBase        uint64
-	RewardedMicroAlgos MicroAlgos
-	AuthAddr           Address
-
-	TotalAppSchema      StateSchema // Totals across created globals, and opted in locals.
-	TotalExtraAppPages  uint32      // Total number of extra pages across all created apps
-	TotalAppParams      uint64      // Total number of apps this account has created
-	TotalAppLocalStates uint64      // Total number of apps this account is opted into.
-	TotalAssetParams    uint64      // Total number of assets created by this account
-	TotalAssets         uint64      // Total of asset creations and optins (i.e. number of holdings)
-	TotalBoxes          uint64      // Total number of boxes associated to this account
-	TotalBoxBytes       uint64      // Total bytes for this account's boxes. keys _and_ values count
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Status             Status     `codec:"Status"`
+	MicroAlgos         MicroAlgos `codec:"MicroAlgos"`
+	RewardsBase        uint64     `codec:"RewardsBase"`
+	RewardedMicroAlgos MicroAlgos `codec:"RewardedMicroAlgos"`
+	AuthAddr           Address    `codec:"AuthAddr"`
+
+	TotalAppSchema      StateSchema `codec:"TotalAppSchema"`      // Totals across created globals, and opted in locals.
+	TotalExtraAppPages  uint32      `codec:"TotalExtraAppPages"`  // Total number of extra pages across all created apps
+	TotalAppParams      uint64      `codec:"TotalAppParams"`      // Total number of apps this account has created
+	TotalAppLocalStates uint64      `codec:"TotalAppLocalStates"` // Total number of apps this account is opted into.
+	TotalAssetParams    uint64      `codec:"TotalAssetParams"`    // Total number of assets created by this account
+	TotalAssets         uint64      `codec:"TotalAssets"`         // Total of asset creations and optins (i.e. number of holdings)
+	TotalBoxes          uint64      `codec:"TotalBoxes"`          // Total number of boxes associated to this account
+	TotalBoxBytes       uint64      `codec:"TotalBoxBytes"`       // Total bytes for this account's boxes. keys _and_ values count
+
+	IncentiveEligible bool  `codec:"IncentiveEligible"` // Whether the account is eligible for block proposer incentives
+	LastProposed      Round `codec:"LastProposed"`      // The last round this account proposed a block
+	LastHeartbeat     Round `codec:"LastHeartbeat"`     // The last round this account sent a heartbeat
 }
 
 // AccountData provides users of the Balances interface per-account data (like basics.AccountData)
@@ -174,13 +200,46 @@ type AccountBaseData struct {
 // ensures that transaction evaluation must retrieve and mutate account, asset, and application data
 // separately, to better support on-disk and in-memory schemas that do not store them together.
 type AccountData struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
 	AccountBaseData
 	VotingData
 }
 
+// OnlineAccountData is the data of an online account which determines its
+// participation in consensus, like basics.OnlineAccountData.
+type OnlineAccountData struct {
+	MicroAlgosWithRewards MicroAlgos
+	VotingData
+	IncentiveEligible bool
+}
+
+// rewardUnit is the number of MicroAlgos earning rewards as one unit.
+const rewardUnit = 1000000
+
+// OnlineAccountData returns the participation data of the account at a round
+// with the given rewards level, as in BlockHeader.RewardsLevel. It is zero
+// unless the account is online.
+func (ad AccountData) OnlineAccountData(rewardsLevel uint64) OnlineAccountData {
+	if ad.Status != Online {
+		return OnlineAccountData{}
+	}
+	balance := ad.MicroAlgos
+	if rewardsLevel > ad.RewardsBase {
+		balance += MicroAlgos(uint64(ad.MicroAlgos) / rewardUnit * (rewardsLevel - ad.RewardsBase))
+	}
+	return OnlineAccountData{
+		MicroAlgosWithRewards: balance,
+		VotingData:            ad.VotingData,
+		IncentiveEligible:     ad.IncentiveEligible,
+	}
+}
+
 // BalanceRecord is similar to basics.BalanceRecord but with decoupled base and voting data
 type BalanceRecord struct {
-	Addr Address
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Addr Address `codec:"Addr"`
 	AccountData
 }
 
@@ -192,19 +251,21 @@ type BalanceRecord struct {
 // element within the slice.
 // If adding fields make sure to add them to the .reset() method to avoid dirty state
 type AccountDeltas struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
 	// Actual data. If an account is deleted, `Accts` contains the BalanceRecord
 	// with an empty `AccountData` and a populated `Addr`.
-	Accts []BalanceRecord
+	Accts []BalanceRecord `codec:"Accts"`
 	// cache for addr to deltas index resolution
 	acctsCache map[Address]int
 
 	// AppResources deltas. If app params or local state is deleted, there is a nil value in AppResources.Params or AppResources.State and Deleted flag set
-	AppResources []AppResourceRecord
+	AppResources []AppResourceRecord `codec:"AppResources"`
 	// caches for {addr, app id} to app params delta resolution
 	// not preallocated - use UpsertAppResource instead of inserting directly
 	appResourcesCache map[AccountApp]int
 
-	AssetResources []AssetResourceRecord
+	AssetResources []AssetResourceRecord `codec:"AssetResources"`
 	// not preallocated - use UpsertAssertResource instead of inserting directly
 	assetResourcesCache map[AccountAsset]int
 }
@@ -213,17 +274,21 @@ type AccountDeltas struct {
 // changed.  However, OldData is elided during evaluation, and only filled in at
 // the conclusion of a block during the called to roundCowState.deltas()
 type KvValueDelta struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
 	// Data stores the most recent value (nil == deleted)
-	Data []byte
+	Data []byte `codec:"Data"`
 
 	// OldData stores the previous vlaue (nil == didn't exist)
-	OldData []byte
+	OldData []byte `codec:"OldData"`
 }
 
 // IncludedTransactions defines the transactions included in a block, their index and last valid round.
 type IncludedTransactions struct {
-	LastValid Round
-	Intra     uint64 // the index of the transaction in the block
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	LastValid Round  `codec:"LastValid"`
+	Intra     uint64 `codec:"Intra"` // the index of the transaction in the block
 }
 
 // Txid is a hash used to uniquely identify individual transactions
@@ -232,8 +297,10 @@ type Txid Digest
 // A Txlease is a transaction (sender, lease) pair which uniquely specifies a
 // transaction lease.
 type Txlease struct {
-	Sender Address
-	Lease  [32]byte
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Sender Address  `codec:"Sender"`
+	Lease  [32]byte `codec:"Lease"`
 }
 
 // CreatableIndex represents either an AssetIndex or AppIndex, which come from
@@ -247,18 +314,20 @@ type CreatableType uint64
 
 // ModifiedCreatable defines the changes to a single single creatable state
 type ModifiedCreatable struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
 	// Type of the creatable: app or asset
-	Ctype CreatableType
+	Ctype CreatableType `codec:"Ctype"`
 
 	// Created if true, deleted if false
-	Created bool
+	Created bool `codec:"Created"`
 
 	// creator of the app/asset
-	Creator Address
+	Creator Address `codec:"Creator"`
 
 	// Keeps track of how many times this app/asset appears in
 	// accountUpdates.creatableDeltas
-	Ndeltas int
+	Ndeltas int `codec:"Ndeltas"`
 }
 
 // AlgoCount represents a total of algos of a certain class
@@ -291,37 +360,123 @@ type AccountTotals struct {
 // it in .ReuseStateDelta to avoid dirty memory errors.
 // If adding fields make sure to add them to the .Reset() method to avoid dirty state
 type LedgerStateDelta struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
 	// modified new accounts
-	Accts AccountDeltas
+	Accts AccountDeltas `codec:"Accts"`
 
 	// modified kv pairs (nil == delete)
 	// not preallocated use .AddKvMod to insert instead of direct assignment
-	KvMods map[string]KvValueDelta
+	KvMods map[string]KvValueDelta `codec:"KvMods"`
 
 	// new Txids for the txtail and TxnCounter, mapped to txn.LastValid
-	Txids map[Txid]IncludedTransactions
+	Txids map[Txid]IncludedTransactions `codec:"Txids"`
 
 	// new txleases for the txtail mapped to expiration
 	// not pre-allocated so use .AddTxLease to insert instead of direct assignment
-	Txleases map[Txlease]Round
+	Txleases map[Txlease]Round `codec:"Txleases"`
 
 	// new creatables creator lookup table
 	// not pre-allocated so use .AddCreatable to insert instead of direct assignment
-	Creatables map[CreatableIndex]ModifiedCreatable
+	Creatables map[CreatableIndex]ModifiedCreatable `codec:"Creatables"`
 
 	// new block header; read-only
-	Hdr *BlockHeader
+	Hdr *BlockHeader `codec:"Hdr"`
 
 	// next round for which we expect a state proof.
 	// zero if no state proof is expected.
-	StateProofNext Round
+	StateProofNext Round `codec:"StateProofNext"`
 
 	// previous block timestamp
-	PrevTimestamp int64
+	PrevTimestamp int64 `codec:"PrevTimestamp"`
 
 	// initial hint for allocating data structures for StateDelta
 	initialHint int
 
 	// The account totals reflecting the changes in this StateDelta object.
-	Totals AccountTotals
+	Totals AccountTotals `codec:"Totals"`
+}
+
+// DecodeLedgerStateDelta decodes a msgpack-encoded LedgerStateDelta, as
+// returned by algod, and indexes its account deltas for lookups. Unknown
+// fields are ignored, like in the responses of the REST clients.
+func DecodeLedgerStateDelta(b []byte) (LedgerStateDelta, error) {
+	var delta LedgerStateDelta
+	if err := msgpack.NewLenientDecoder(bytes.NewReader(b)).Decode(&delta); err != nil {
+		return LedgerStateDelta{}, err
+	}
+	delta.Accts.Hydrate()
+	return delta, nil
+}
+
+// Hydrate indexes the account, app and asset deltas by address, so that
+// lookups do not scan them. It must be called again if the deltas are
+// modified, and not concurrently with lookups.
+func (ad *AccountDeltas) Hydrate() {
+	ad.acctsCache = make(map[Address]int, len(ad.Accts))
+	for i, record := range ad.Accts {
+		ad.acctsCache[record.Addr] = i
+	}
+	ad.appResourcesCache = make(map[AccountApp]int, len(ad.AppResources))
+	for i, record := range ad.AppResources {
+		ad.appResourcesCache[AccountApp{Address: record.Addr, App: record.Aidx}] = i
+	}
+	ad.assetResourcesCache = make(map[AccountAsset]int, len(ad.AssetResources))
+	for i, record := range ad.AssetResources {
+		ad.assetResourcesCache[AccountAsset{Address: record.Addr, Asset: record.Aidx}] = i
+	}
+}
+
+// GetData returns the new data of an account modified in the round. A
+// deleted account has empty AccountData.
+func (ad *AccountDeltas) GetData(addr Address) (AccountData, bool) {
+	if ad.acctsCache != nil {
+		i, ok := ad.acctsCache[addr]
+		if !ok {
+			return AccountData{}, false
+		}
+		return ad.Accts[i].AccountData, true
+	}
+	for _, record := range ad.Accts {
+		if record.Addr == addr {
+			return record.AccountData, true
+		}
+	}
+	return AccountData{}, false
+}
+
+// GetAppResource returns the changes to the params and local state of an app
+// for an account modified in the round.
+func (ad *AccountDeltas) GetAppResource(addr Address, aidx AppIndex) (AppResourceRecord, bool) {
+	if ad.appResourcesCache != nil {
+		i, ok := ad.appResourcesCache[AccountApp{Address: addr, App: aidx}]
+		if !ok {
+			return AppResourceRecord{}, false
+		}
+		return ad.AppResources[i], true
+	}
+	for _, record := range ad.AppResources {
+		if record.Addr == addr && record.Aidx == aidx {
+			return record, true
+		}
+	}
+	return AppResourceRecord{}, false
+}
+
+// GetAssetResource returns the changes to the params and holding of an asset
+// for an account modified in the round.
+func (ad *AccountDeltas) GetAssetResource(addr Address, aidx AssetIndex) (AssetResourceRecord, bool) {
+	if ad.assetResourcesCache != nil {
+		i, ok := ad.assetResourcesCache[AccountAsset{Address: addr, Asset: aidx}]
+		if !ok {
+			return AssetResourceRecord{}, false
+		}
+		return ad.AssetResources[i], true
+	}
+	for _, record := range ad.AssetResources {
+		if record.Addr == addr && record.Aidx == aidx {
+			return record, true
+		}
+	}
+	return AssetResourceRecord{}, false
 }
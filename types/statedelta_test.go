@@ -0,0 +1,216 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+)
+
+func TestDecodeLedgerStateDelta(t *testing.T) {
+	addr := Address{1}
+	other := Address{2}
+	delta := LedgerStateDelta{
+		Accts: AccountDeltas{
+			Accts: []BalanceRecord{
+				{Addr: addr, AccountData: AccountData{AccountBaseData: AccountBaseData{MicroAlgos: 100, TotalAssets: 1}}},
+				{Addr: other},
+			},
+			AppResources: []AppResourceRecord{
+				{Aidx: 5, Addr: addr, State: AppLocalStateDelta{LocalState: &AppLocalState{KeyValue: TealKeyValue{"k": {Type: TealUintType, Uint: 1}}}}},
+			},
+			AssetResources: []AssetResourceRecord{
+				{Aidx: 7, Addr: addr, Holding: AssetHoldingDelta{Holding: &AssetHolding{Amount: 10}}},
+				{Aidx: 8, Addr: other, Holding: AssetHoldingDelta{Deleted: true}},
+			},
+		},
+		KvMods:         map[string]KvValueDelta{"bx:key": {Data: []byte("value")}},
+		Txids:          map[Txid]IncludedTransactions{{1}: {LastValid: 1000, Intra: 2}},
+		Txleases:       map[Txlease]Round{{Sender: addr, Lease: [32]byte{1}}: 1000},
+		Creatables:     map[CreatableIndex]ModifiedCreatable{7: {Ctype: 0, Created: true, Creator: addr}},
+		Hdr:            &BlockHeader{Round: 10},
+		StateProofNext: 256,
+		PrevTimestamp:  1234,
+		Totals:         AccountTotals{Online: AlgoCount{Money: 1000}},
+	}
+
+	decoded, err := DecodeLedgerStateDelta(msgpack.Encode(delta))
+	require.NoError(t, err)
+	require.Equal(t, delta.KvMods, decoded.KvMods)
+	require.Equal(t, delta.Txids, decoded.Txids)
+	require.Equal(t, delta.Txleases, decoded.Txleases)
+	require.Equal(t, delta.Creatables, decoded.Creatables)
+	require.Equal(t, delta.Hdr, decoded.Hdr)
+	require.Equal(t, delta.StateProofNext, decoded.StateProofNext)
+	require.Equal(t, delta.PrevTimestamp, decoded.PrevTimestamp)
+	require.Equal(t, delta.Totals, decoded.Totals)
+
+	for _, accts := range []*AccountDeltas{&delta.Accts, &decoded.Accts} {
+		data, ok := accts.GetData(addr)
+		require.True(t, ok)
+		require.Equal(t, MicroAlgos(100), data.MicroAlgos)
+		data, ok = accts.GetData(other)
+		require.True(t, ok)
+		require.Equal(t, AccountData{}, data)
+		_, ok = accts.GetData(Address{3})
+		require.False(t, ok)
+
+		app, ok := accts.GetAppResource(addr, 5)
+		require.True(t, ok)
+		require.Equal(t, uint64(1), app.State.LocalState.KeyValue["k"].Uint)
+		_, ok = accts.GetAppResource(other, 5)
+		require.False(t, ok)
+
+		asset, ok := accts.GetAssetResource(addr, 7)
+		require.True(t, ok)
+		require.Equal(t, uint64(10), asset.Holding.Holding.Amount)
+		asset, ok = accts.GetAssetResource(other, 8)
+		require.True(t, ok)
+		require.True(t, asset.Holding.Deleted)
+		_, ok = accts.GetAssetResource(addr, 8)
+		require.False(t, ok)
+	}
+
+	_, err = DecodeLedgerStateDelta([]byte{0xc1})
+	require.Error(t, err)
+}
+
+func TestDecodeLedgerStateDeltaFixture(t *testing.T) {
+	// a delta as algod encodes it, spelled out with the field names on the
+	// wire rather than encoded from the types under test
+	addr := Address{1}
+	type obj = map[string]interface{}
+	fixture := msgpack.Encode(obj{
+		"Accts": obj{
+			"Accts": []interface{}{
+				obj{
+					"Addr":           addr[:],
+					"Status":         1,
+					"MicroAlgos":     100,
+					"RewardsBase":    2,
+					"TotalAppSchema": obj{"nui": 1, "nbs": 2},
+					"TotalAssets":    1,
+					"VoteFirstValid": 10,
+					"VoteLastValid":  20,
+				},
+			},
+			"AppResources": []interface{}{
+				obj{
+					"Aidx": 5,
+					"Addr": addr[:],
+					"Params": obj{
+						"Params": obj{
+							"approv": []byte{6, 0x81, 1},
+							"gs":     obj{"g": obj{"tt": 1, "tb": "v"}},
+							"lsch":   obj{"nui": 1},
+						},
+					},
+					"State": obj{
+						"LocalState": obj{"hsch": obj{"nui": 1}, "tkv": obj{"k": obj{"tt": 2, "ui": 3}}},
+					},
+				},
+			},
+			"AssetResources": []interface{}{
+				obj{
+					"Aidx":    7,
+					"Addr":    addr[:],
+					"Params":  obj{"Params": obj{"t": 1000, "dc": 2, "un": "TOK"}},
+					"Holding": obj{"Holding": obj{"a": 10, "f": true}},
+				},
+				obj{"Aidx": 8, "Addr": addr[:], "Holding": obj{"Deleted": true}},
+			},
+		},
+		"KvMods":         obj{"bx:key": obj{"Data": []byte("new"), "OldData": []byte("old")}},
+		"Creatables":     map[uint64]interface{}{7: obj{"Created": true, "Creator": addr[:], "Ndeltas": 1}},
+		"Hdr":            obj{"rnd": 10, "ts": 1234},
+		"StateProofNext": 256,
+		"PrevTimestamp":  1230,
+		"Totals":         obj{"online": obj{"mon": 1000, "rwd": 1}, "rwdlvl": 5},
+	})
+
+	expected := LedgerStateDelta{
+		Accts: AccountDeltas{
+			Accts: []BalanceRecord{{
+				Addr: addr,
+				AccountData: AccountData{
+					AccountBaseData: AccountBaseData{
+						Status:         Online,
+						MicroAlgos:     100,
+						RewardsBase:    2,
+						TotalAppSchema: StateSchema{NumUint: 1, NumByteSlice: 2},
+						TotalAssets:    1,
+					},
+					VotingData: VotingData{VoteFirstValid: 10, VoteLastValid: 20},
+				},
+			}},
+			AppResources: []AppResourceRecord{{
+				Aidx: 5,
+				Addr: addr,
+				Params: AppParamsDelta{Params: &AppParams{
+					ApprovalProgram: []byte{6, 0x81, 1},
+					GlobalState:     TealKeyValue{"g": {Type: TealBytesType, Bytes: "v"}},
+					StateSchemas:    StateSchemas{LocalStateSchema: StateSchema{NumUint: 1}},
+				}},
+				State: AppLocalStateDelta{LocalState: &AppLocalState{
+					Schema:   StateSchema{NumUint: 1},
+					KeyValue: TealKeyValue{"k": {Type: TealUintType, Uint: 3}},
+				}},
+			}},
+			AssetResources: []AssetResourceRecord{
+				{
+					Aidx:    7,
+					Addr:    addr,
+					Params:  AssetParamsDelta{Params: &AssetParams{Total: 1000, Decimals: 2, UnitName: "TOK"}},
+					Holding: AssetHoldingDelta{Holding: &AssetHolding{Amount: 10, Frozen: true}},
+				},
+				{Aidx: 8, Addr: addr, Holding: AssetHoldingDelta{Deleted: true}},
+			},
+		},
+		KvMods:         map[string]KvValueDelta{"bx:key": {Data: []byte("new"), OldData: []byte("old")}},
+		Creatables:     map[CreatableIndex]ModifiedCreatable{7: {Created: true, Creator: addr, Ndeltas: 1}},
+		Hdr:            &BlockHeader{Round: 10, TimeStamp: 1234},
+		StateProofNext: 256,
+		PrevTimestamp:  1230,
+		Totals:         AccountTotals{Online: AlgoCount{Money: 1000, RewardUnits: 1}, RewardsLevel: 5},
+	}
+
+	// every field of the fixture is known
+	var strict LedgerStateDelta
+	require.NoError(t, msgpack.Decode(fixture, &strict))
+	require.Equal(t, expected, strict)
+
+	decoded, err := DecodeLedgerStateDelta(fixture)
+	require.NoError(t, err)
+	expected.Accts.Hydrate()
+	require.Equal(t, expected, decoded)
+
+	// and the types encode back to the same bytes
+	require.Equal(t, fixture, msgpack.Encode(decoded))
+}
+
+func TestOnlineAccountData(t *testing.T) {
+	ad := AccountData{
+		AccountBaseData: AccountBaseData{
+			Status:            Online,
+			MicroAlgos:        5000000,
+			RewardsBase:       10,
+			IncentiveEligible: true,
+			LastProposed:      100,
+			LastHeartbeat:     90,
+		},
+		VotingData: VotingData{VoteFirstValid: 1, VoteLastValid: 1000, VoteKeyDilution: 100},
+	}
+
+	var decoded AccountData
+	require.NoError(t, msgpack.Decode(msgpack.Encode(ad), &decoded))
+	require.Equal(t, ad, decoded)
+
+	online := ad.OnlineAccountData(12)
+	require.Equal(t, MicroAlgos(5000010), online.MicroAlgosWithRewards)
+	require.Equal(t, ad.VotingData, online.VotingData)
+	require.True(t, online.IncentiveEligible)
+
+	ad.Status = Offline
+	require.Equal(t, OnlineAccountData{}, ad.OnlineAccountData(12))
+}
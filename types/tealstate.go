@@ -0,0 +1,116 @@
+package types
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tealKeyBase64Prefix marks the keys of decoded state which are not printable
+// UTF-8, like the goal app call arg form 'b64:value'.
+const tealKeyBase64Prefix = "b64:"
+
+// Value returns the value as a Go value: a uint64 for TealUintType and a
+// []byte for TealBytesType.
+func (tv TealValue) Value() interface{} {
+	if tv.Type == TealUintType {
+		return tv.Uint
+	}
+	return []byte(tv.Bytes)
+}
+
+// TealValueOf returns the TealValue of a Go value: unsigned and non-negative
+// integers are uints, and strings and byte slices are bytes.
+func TealValueOf(v interface{}) (TealValue, error) {
+	var i int64
+	switch v := v.(type) {
+	case []byte:
+		return TealValue{Type: TealBytesType, Bytes: string(v)}, nil
+	case string:
+		return TealValue{Type: TealBytesType, Bytes: v}, nil
+	case uint64:
+		return TealValue{Type: TealUintType, Uint: v}, nil
+	case uint:
+		return TealValue{Type: TealUintType, Uint: uint64(v)}, nil
+	case uint32:
+		return TealValue{Type: TealUintType, Uint: uint64(v)}, nil
+	case int:
+		i = int64(v)
+	case int64:
+		i = v
+	case int32:
+		i = int64(v)
+	default:
+		return TealValue{}, fmt.Errorf("cannot convert %T to a TEAL value", v)
+	}
+	if i < 0 {
+		return TealValue{}, fmt.Errorf("cannot convert negative %d to a TEAL value", i)
+	}
+	return TealValue{Type: TealUintType, Uint: uint64(i)}, nil
+}
+
+// Decode returns the state as a map of Go values, as returned by
+// TealValue.Value. Keys which are printable UTF-8 are kept as they are, and
+// other keys are given in base64 with the prefix "b64:", e.g. "b64:AAE=", as
+// are printable keys which already have that prefix.
+func (kv TealKeyValue) Decode() map[string]interface{} {
+	state := make(map[string]interface{}, len(kv))
+	for key, value := range kv {
+		state[DecodeTealKey(key)] = value.Value()
+	}
+	return state
+}
+
+// EncodeTealKeyValue returns the TealKeyValue of state in the form returned
+// by TealKeyValue.Decode, with values converted by TealValueOf. It is meant
+// e.g. for constructing the state expected by tests.
+func EncodeTealKeyValue(state map[string]interface{}) (TealKeyValue, error) {
+	kv := make(TealKeyValue, len(state))
+	for key, value := range state {
+		encodedKey, err := EncodeTealKey(key)
+		if err != nil {
+			return nil, err
+		}
+		tv, err := TealValueOf(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of key %q: %w", key, err)
+		}
+		kv[encodedKey] = tv
+	}
+	return kv, nil
+}
+
+// DecodeTealKey returns the raw key of a TEAL key/value store as it appears in
+// TealKeyValue.Decode.
+func DecodeTealKey(key string) string {
+	if isPrintable(key) && !strings.HasPrefix(key, tealKeyBase64Prefix) {
+		return key
+	}
+	return tealKeyBase64Prefix + base64.StdEncoding.EncodeToString([]byte(key))
+}
+
+// EncodeTealKey is the inverse of DecodeTealKey.
+func EncodeTealKey(key string) (string, error) {
+	if !strings.HasPrefix(key, tealKeyBase64Prefix) {
+		return key, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(key[len(tealKeyBase64Prefix):])
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 key %q: %w", key, err)
+	}
+	return string(raw), nil
+}
+
+func isPrintable(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTealKeyValueDecode(t *testing.T) {
+	kv := TealKeyValue{
+		"counter":       {Type: TealUintType, Uint: 7},
+		"owner":         {Type: TealBytesType, Bytes: "\x01\x02"},
+		"\x00\x01":      {Type: TealBytesType, Bytes: "raw key"},
+		"b64:ambiguous": {Type: TealUintType},
+	}
+
+	state := kv.Decode()
+	require.Equal(t, map[string]interface{}{
+		"counter":                  uint64(7),
+		"owner":                    []byte{1, 2},
+		"b64:AAE=":                 []byte("raw key"),
+		"b64:YjY0OmFtYmlndW91cw==": uint64(0),
+	}, state)
+
+	encoded, err := EncodeTealKeyValue(state)
+	require.NoError(t, err)
+	require.Equal(t, kv, encoded)
+}
+
+func TestEncodeTealKeyValue(t *testing.T) {
+	kv, err := EncodeTealKeyValue(map[string]interface{}{
+		"int":    1,
+		"uint":   uint64(2),
+		"string": "s",
+		"bytes":  []byte{3},
+	})
+	require.NoError(t, err)
+	require.Equal(t, TealKeyValue{
+		"int":    {Type: TealUintType, Uint: 1},
+		"uint":   {Type: TealUintType, Uint: 2},
+		"string": {Type: TealBytesType, Bytes: "s"},
+		"bytes":  {Type: TealBytesType, Bytes: "\x03"},
+	}, kv)
+
+	_, err = EncodeTealKeyValue(map[string]interface{}{"negative": -1})
+	require.Error(t, err)
+	_, err = EncodeTealKeyValue(map[string]interface{}{"float": 1.5})
+	require.Error(t, err)
+	_, err = EncodeTealKeyValue(map[string]interface{}{"b64:!": 1})
+	require.Error(t, err)
+}
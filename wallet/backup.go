@@ -0,0 +1,229 @@
+package wallet
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/kmd"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// archiveType is the walletFile type of backup archives.
+const archiveType = "backup"
+
+// kmdDriver is the kmd wallet driver wallets are restored into.
+const kmdDriver = "sqlite"
+
+// Archive is the content of a wallet backup: everything needed to recreate the
+// wallet's keys.
+type Archive struct {
+	// Name the name of the backed up wallet.
+	Name string
+
+	// MasterDerivationKey the key from which the wallet generated its keys.
+	MasterDerivationKey types.MasterDerivationKey
+
+	// Keys the private keys in the wallet, both generated and imported.
+	Keys []ed25519.PrivateKey
+
+	// WatchOnly the watch-only addresses of the wallet.
+	WatchOnly []types.Address
+}
+
+type archiveData struct {
+	_struct             struct{}                  `codec:",omitempty,omitemptyarray"`
+	Name                string                    `codec:"name"`
+	MasterDerivationKey types.MasterDerivationKey `codec:"mdk"`
+	Keys                []storedKey               `codec:"keys"`
+	WatchOnly           []types.Address           `codec:"watch"`
+}
+
+// EncryptArchive encodes the archive encrypted with passphrase.
+func EncryptArchive(archive Archive, passphrase string) ([]byte, error) {
+	data := archiveData{Name: archive.Name, MasterDerivationKey: archive.MasterDerivationKey, WatchOnly: archive.WatchOnly}
+	for _, sk := range archive.Keys {
+		data.Keys = append(data.Keys, storedKey{SecretKey: sk})
+	}
+
+	salt := make([]byte, 32)
+	crypto.RandomBytes(salt)
+	key, err := deriveEncryptionKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	return sealFile(msgpack.Encode(data), archiveType, salt, key), nil
+}
+
+// DecryptArchive decrypts an archive encoded by EncryptArchive. It returns
+// kmd.ErrWrongPassword if the passphrase is incorrect.
+func DecryptArchive(raw []byte, passphrase string) (Archive, error) {
+	plaintext, _, _, err := openFile(raw, archiveType, passphrase)
+	if err != nil {
+		return Archive{}, err
+	}
+	var data archiveData
+	if err := msgpack.Decode(plaintext, &data); err != nil {
+		return Archive{}, fmt.Errorf("invalid archive data: %w", err)
+	}
+
+	archive := Archive{Name: data.Name, MasterDerivationKey: data.MasterDerivationKey, WatchOnly: data.WatchOnly}
+	for _, key := range data.Keys {
+		if len(key.SecretKey) != ed25519.PrivateKeySize {
+			return Archive{}, fmt.Errorf("invalid archive data: bad private key length %d", len(key.SecretKey))
+		}
+		archive.Keys = append(archive.Keys, key.SecretKey)
+	}
+	return archive, nil
+}
+
+// Backup returns an archive of the wallet encrypted with passphrase.
+func (w *FileWallet) Backup(password, passphrase string) ([]byte, error) {
+	w.mu.Lock()
+	if err := w.checkPassword(password); err != nil {
+		w.mu.Unlock()
+		return nil, err
+	}
+	archive := Archive{
+		Name:                w.data.Name,
+		MasterDerivationKey: w.data.MasterDerivationKey,
+		WatchOnly:           append([]types.Address{}, w.data.WatchOnly...),
+	}
+	for _, key := range w.data.Keys {
+		archive.Keys = append(archive.Keys, append(ed25519.PrivateKey{}, key.SecretKey...))
+	}
+	w.mu.Unlock()
+
+	return EncryptArchive(archive, passphrase)
+}
+
+// Restore creates a wallet file at path, encrypted with password, from an
+// archive encrypted with passphrase.
+func Restore(path, password string, raw []byte, passphrase string) (*FileWallet, error) {
+	archive, err := DecryptArchive(raw, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if archive.MasterDerivationKey == (types.MasterDerivationKey{}) {
+		return nil, fmt.Errorf("invalid archive: missing master derivation key")
+	}
+
+	w, err := Create(path, archive.Name, password, archive.MasterDerivationKey)
+	if err != nil {
+		return nil, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.data.MaxIndex = restoredMaxIndex(archive)
+	w.data.WatchOnly = archive.WatchOnly
+	for _, sk := range archive.Keys {
+		if _, ok := w.find(publicKey(sk)); !ok {
+			w.data.Keys = append(w.data.Keys, storedKey{SecretKey: sk})
+		}
+	}
+	return w, w.save()
+}
+
+// restoredMaxIndex returns the index of the last key a wallet restored from
+// the archive should consider generated: past the keys generated by the backed
+// up wallet, up to and including the first one missing from the archive, which
+// was either deleted or not generated yet. This matches the keys RestoreKmd
+// has kmd generate.
+func restoredMaxIndex(archive Archive) uint64 {
+	remaining := make(map[types.Address]bool, len(archive.Keys))
+	for _, sk := range archive.Keys {
+		remaining[addressOf(sk)] = true
+	}
+	var index uint64
+	for len(remaining) > 0 {
+		index++
+		addr := addressOf(deriveKey(archive.MasterDerivationKey, index))
+		if !remaining[addr] {
+			break
+		}
+		delete(remaining, addr)
+	}
+	return index
+}
+
+// BackupKmd returns an archive of a kmd wallet encrypted with passphrase.
+func BackupKmd(kcl kmd.Client, walletHandle, walletPassword, passphrase string) ([]byte, error) {
+	wallet, err := kcl.GetWallet(walletHandle)
+	if err != nil {
+		return nil, err
+	}
+	mdk, err := kcl.ExportMasterDerivationKey(walletHandle, walletPassword)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := kcl.ListKeys(walletHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := Archive{Name: wallet.WalletHandle.Wallet.Name, MasterDerivationKey: mdk.MasterDerivationKey}
+	for _, addr := range keys.Addresses {
+		key, err := kcl.ExportKey(walletHandle, walletPassword, addr)
+		if err != nil {
+			return nil, err
+		}
+		archive.Keys = append(archive.Keys, key.PrivateKey)
+	}
+	return EncryptArchive(archive, passphrase)
+}
+
+// RestoreKmd creates a kmd wallet, protected by walletPassword, from an
+// archive encrypted with passphrase. Keys generated by the backed up wallet
+// are generated again, so that the restored wallet does not generate them a
+// second time, and the other keys are imported. kmd wallets have no watch-only
+// addresses, so those of the archive are not restored.
+func RestoreKmd(kcl kmd.Client, raw []byte, passphrase, walletPassword string) (kmd.APIV1Wallet, error) {
+	archive, err := DecryptArchive(raw, passphrase)
+	if err != nil {
+		return kmd.APIV1Wallet{}, err
+	}
+	if archive.MasterDerivationKey == (types.MasterDerivationKey{}) {
+		return kmd.APIV1Wallet{}, fmt.Errorf("invalid archive: missing master derivation key")
+	}
+
+	created, err := kcl.CreateWallet(archive.Name, walletPassword, kmdDriver, archive.MasterDerivationKey)
+	if err != nil {
+		return kmd.APIV1Wallet{}, err
+	}
+	handle, err := kcl.InitWalletHandle(created.Wallet.ID, walletPassword)
+	if err != nil {
+		return kmd.APIV1Wallet{}, err
+	}
+	defer kcl.ReleaseWalletHandle(handle.WalletHandleToken)
+
+	remaining := make(map[string]ed25519.PrivateKey, len(archive.Keys))
+	for _, sk := range archive.Keys {
+		remaining[addressOf(sk).String()] = sk
+	}
+	for len(remaining) > 0 {
+		generated, err := kcl.GenerateKey(handle.WalletHandleToken)
+		if err != nil {
+			return kmd.APIV1Wallet{}, err
+		}
+		if _, ok := remaining[generated.Address]; !ok {
+			// the key was deleted from the backed up wallet, or never
+			// generated by it
+			if _, err := kcl.DeleteKey(handle.WalletHandleToken, walletPassword, generated.Address); err != nil {
+				return kmd.APIV1Wallet{}, err
+			}
+			break
+		}
+		delete(remaining, generated.Address)
+	}
+	for _, sk := range archive.Keys {
+		if _, ok := remaining[addressOf(sk).String()]; !ok {
+			continue
+		}
+		if _, err := kcl.ImportKey(handle.WalletHandleToken, sk); err != nil {
+			return kmd.APIV1Wallet{}, err
+		}
+	}
+	return created.Wallet, nil
+}
@@ -0,0 +1,219 @@
+package wallet
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/kmd"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/json"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+const passphrase = "battery staple"
+
+// fakeKmd serves the kmd endpoints used by the backup helpers and KmdDriver
+// from a single FileWallet.
+type fakeKmd struct {
+	t      *testing.T
+	dir    string
+	wallet *FileWallet
+}
+
+func (f *fakeKmd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	require.NoError(f.t, err)
+	var req struct {
+		WalletHandleToken   string                    `json:"wallet_handle_token"`
+		WalletID            string                    `json:"wallet_id"`
+		WalletDriverName    string                    `json:"wallet_driver_name"`
+		DisplayMnemonic     bool                      `json:"display_mnemonic"`
+		WalletName          string                    `json:"wallet_name"`
+		WalletPassword      string                    `json:"wallet_password"`
+		MasterDerivationKey types.MasterDerivationKey `json:"master_derivation_key"`
+		PrivateKey          ed25519.PrivateKey        `json:"private_key"`
+		Address             string                    `json:"address"`
+		Transaction         []byte                    `json:"transaction"`
+		PublicKey           ed25519.PublicKey         `json:"public_key"`
+	}
+	require.NoError(f.t, json.Decode(body, &req))
+	wallet := kmd.APIV1Wallet{ID: "id", Name: "restored"}
+	if f.wallet != nil {
+		wallet = kmd.APIV1Wallet{ID: f.wallet.ID(), Name: f.wallet.Name()}
+	}
+
+	addr, _ := types.DecodeAddress(req.Address)
+
+	var resp interface{}
+	switch r.Method + " " + r.URL.Path {
+	case "POST /v1/wallet":
+		f.wallet, err = Create(filepath.Join(f.dir, "kmd.wallet"), req.WalletName, req.WalletPassword, req.MasterDerivationKey)
+		wallet = kmd.APIV1Wallet{ID: f.wallet.ID(), Name: f.wallet.Name()}
+		resp = kmd.CreateWalletResponse{Wallet: wallet}
+	case "POST /v1/wallet/init":
+		resp = kmd.InitWalletHandleResponse{WalletHandleToken: "handle"}
+	case "POST /v1/wallet/renew":
+		resp = kmd.RenewWalletHandleResponse{WalletHandle: kmd.APIV1WalletHandle{Wallet: wallet, ExpiresSeconds: 60}}
+	case "POST /v1/wallet/release":
+		resp = kmd.ReleaseWalletHandleResponse{}
+	case "POST /v1/wallet/info":
+		resp = kmd.GetWalletResponse{WalletHandle: kmd.APIV1WalletHandle{Wallet: wallet}}
+	case "POST /v1/master-key/export":
+		var mdk types.MasterDerivationKey
+		mdk, err = f.wallet.ExportMasterDerivationKey(req.WalletPassword)
+		resp = kmd.ExportMasterDerivationKeyResponse{MasterDerivationKey: mdk}
+	case "POST /v1/key":
+		addr, err = f.wallet.GenerateKey()
+		resp = kmd.GenerateKeyResponse{Address: addr.String()}
+	case "POST /v1/key/import":
+		addr, err = f.wallet.ImportKey(req.PrivateKey)
+		resp = kmd.ImportKeyResponse{Address: addr.String()}
+	case "POST /v1/key/export":
+		var sk ed25519.PrivateKey
+		sk, err = f.wallet.ExportKey(req.WalletPassword, addr)
+		resp = kmd.ExportKeyResponse{PrivateKey: sk}
+	case "DELETE /v1/key":
+		err = f.wallet.DeleteKey(req.WalletPassword, addr)
+		resp = kmd.DeleteKeyResponse{}
+	case "POST /v1/transaction/sign":
+		var tx types.Transaction
+		require.NoError(f.t, msgpack.Decode(req.Transaction, &tx))
+		var stx []byte
+		stx, err = f.wallet.SignTransactionWithPublicKey(req.WalletPassword, tx, req.PublicKey)
+		resp = kmd.SignTransactionResponse{SignedTransaction: stx}
+	case "POST /v1/key/list":
+		var addrs []string
+		for _, addr := range f.wallet.ListKeys() {
+			addrs = append(addrs, addr.String())
+		}
+		resp = kmd.ListKeysResponse{Addresses: addrs}
+	default:
+		f.t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+	}
+	require.NoError(f.t, err)
+	w.Write(json.Encode(resp))
+}
+
+func newFakeKmd(t *testing.T, wallet *FileWallet) (kmd.Client, *fakeKmd) {
+	fake := &fakeKmd{t: t, dir: t.TempDir(), wallet: wallet}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	kcl, err := kmd.MakeClient(server.URL, "token")
+	require.NoError(t, err)
+	return kcl, fake
+}
+
+// populate generates three keys, deletes the second one and imports a key,
+// returning the addresses left in the wallet.
+func populate(t *testing.T, w *FileWallet) []types.Address {
+	var generated []types.Address
+	for i := 0; i < 3; i++ {
+		addr, err := w.GenerateKey()
+		require.NoError(t, err)
+		generated = append(generated, addr)
+	}
+	require.NoError(t, w.DeleteKey(password, generated[1]))
+	imported, err := w.ImportKey(crypto.GenerateAccount().PrivateKey)
+	require.NoError(t, err)
+	return []types.Address{generated[0], generated[2], imported}
+}
+
+func TestArchive(t *testing.T) {
+	archive := Archive{Name: "test", Keys: []ed25519.PrivateKey{crypto.GenerateAccount().PrivateKey}}
+	archive.MasterDerivationKey[0] = 1
+
+	raw, err := EncryptArchive(archive, passphrase)
+	require.NoError(t, err)
+	_, err = DecryptArchive(raw, "wrong")
+	require.ErrorIs(t, err, kmd.ErrWrongPassword)
+	decrypted, err := DecryptArchive(raw, passphrase)
+	require.NoError(t, err)
+	require.Equal(t, archive, decrypted)
+
+	// an archive is not a wallet file and vice versa
+	path := filepath.Join(t.TempDir(), "archive")
+	require.NoError(t, ioutil.WriteFile(path, raw, 0600))
+	_, err = Open(path, passphrase)
+	require.Error(t, err)
+	w, walletPath := createWallet(t, types.MasterDerivationKey{})
+	require.NotNil(t, w)
+	walletRaw, err := ioutil.ReadFile(walletPath)
+	require.NoError(t, err)
+	_, err = DecryptArchive(walletRaw, password)
+	require.Error(t, err)
+}
+
+func TestBackupRestore(t *testing.T) {
+	w, _ := createWallet(t, types.MasterDerivationKey{})
+	addrs := populate(t, w)
+
+	_, err := w.Backup("wrong", passphrase)
+	require.ErrorIs(t, err, kmd.ErrWrongPassword)
+	raw, err := w.Backup(password, passphrase)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "restored.wallet")
+	_, err = Restore(path, "new password", raw, "wrong")
+	require.ErrorIs(t, err, kmd.ErrWrongPassword)
+	restored, err := Restore(path, "new password", raw, passphrase)
+	require.NoError(t, err)
+	require.Equal(t, "test", restored.Name())
+	require.Equal(t, addrs, restored.ListKeys())
+
+	// both wallets generate the same next key
+	expected, err := w.GenerateKey()
+	require.NoError(t, err)
+	actual, err := restored.GenerateKey()
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestRestoreKmdArchive(t *testing.T) {
+	// the archive of a kmd wallet which generated the keys with index 1 to 3,
+	// deleted the second one and imported another key
+	var mdk types.MasterDerivationKey
+	mdk[0] = 1
+	imported := crypto.GenerateAccount().PrivateKey
+	archive := Archive{Name: "kmd", MasterDerivationKey: mdk, Keys: []ed25519.PrivateKey{deriveKey(mdk, 1), deriveKey(mdk, 3), imported}}
+	require.Equal(t, uint64(2), restoredMaxIndex(archive))
+	raw, err := EncryptArchive(archive, passphrase)
+	require.NoError(t, err)
+
+	restored, err := Restore(filepath.Join(t.TempDir(), "restored.wallet"), password, raw, passphrase)
+	require.NoError(t, err)
+	require.Equal(t, []types.Address{addressOf(archive.Keys[0]), addressOf(archive.Keys[1]), addressOf(imported)}, restored.ListKeys())
+
+	// the restored wallet generates the key kmd would generate next
+	addr, err := restored.GenerateKey()
+	require.NoError(t, err)
+	require.Equal(t, addressOf(deriveKey(mdk, 4)), addr)
+}
+
+func TestBackupRestoreKmd(t *testing.T) {
+	w, _ := createWallet(t, types.MasterDerivationKey{})
+	addrs := populate(t, w)
+
+	source, _ := newFakeKmd(t, w)
+	raw, err := BackupKmd(source, "handle", password, passphrase)
+	require.NoError(t, err)
+
+	target, fake := newFakeKmd(t, nil)
+	wallet, err := RestoreKmd(target, raw, passphrase, "new password")
+	require.NoError(t, err)
+	require.Equal(t, "test", wallet.Name)
+	require.ElementsMatch(t, addrs, fake.wallet.ListKeys())
+
+	// the deleted and remaining generated keys are not generated again
+	expected, err := w.GenerateKey()
+	require.NoError(t, err)
+	actual, err := fake.wallet.GenerateKey()
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
@@ -0,0 +1,129 @@
+package wallet
+
+import (
+	"reflect"
+
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/kmd"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// Driver is the kmd-style wallet API which code signing with a wallet depends
+// on, so that the backend holding the keys can be swapped: a FileWallet, a kmd
+// wallet through KmdDriver, or e.g. a hardware device or a remote signing
+// service. Backends which do not need the wallet password ignore it.
+type Driver interface {
+	// ListKeysFunc calls fn with the address of each key the wallet can sign
+	// with, stopping at and returning the first error returned by fn.
+	ListKeysFunc(fn func(addr types.Address) error) error
+
+	// SignTransactionWithPublicKey signs a transaction with the key for pk
+	// and returns the encoded signed transaction.
+	SignTransactionWithPublicKey(password string, tx types.Transaction, pk ed25519.PublicKey) ([]byte, error)
+
+	// SignProgram signs a program with the key of addr, as used in a
+	// delegated LogicSig.
+	SignProgram(password string, addr types.Address, program []byte) (types.Signature, error)
+
+	// MultisigSignTransaction adds the signature of the key for pk to a
+	// partially signed multisig transaction.
+	MultisigSignTransaction(password string, tx types.Transaction, pk ed25519.PublicKey, partial types.MultisigSig) (types.MultisigSig, error)
+}
+
+// KmdDriver is the Driver of a kmd wallet, using the handle of a
+// kmd.WalletSession.
+type KmdDriver struct {
+	kcl     kmd.Client
+	session *kmd.WalletSession
+}
+
+// NewKmdDriver returns the Driver of the kmd wallet the session holds a handle
+// to.
+func NewKmdDriver(kcl kmd.Client, session *kmd.WalletSession) *KmdDriver {
+	return &KmdDriver{kcl: kcl, session: session}
+}
+
+// ListKeysFunc implements Driver.
+func (d *KmdDriver) ListKeysFunc(fn func(addr types.Address) error) error {
+	return d.kcl.ListKeysFunc(d.session.Handle(), func(address string) error {
+		addr, err := types.DecodeAddress(address)
+		if err != nil {
+			return err
+		}
+		return fn(addr)
+	})
+}
+
+// SignTransactionWithPublicKey implements Driver.
+func (d *KmdDriver) SignTransactionWithPublicKey(password string, tx types.Transaction, pk ed25519.PublicKey) ([]byte, error) {
+	resp, err := d.kcl.SignTransactionWithSpecificPublicKey(d.session.Handle(), password, tx, pk)
+	if err != nil {
+		return nil, err
+	}
+	return resp.SignedTransaction, nil
+}
+
+// SignProgram implements Driver.
+func (d *KmdDriver) SignProgram(password string, addr types.Address, program []byte) (sig types.Signature, err error) {
+	resp, err := d.kcl.SignProgram(d.session.Handle(), password, addr.String(), program)
+	if err != nil {
+		return
+	}
+	copy(sig[:], resp.Signature)
+	return
+}
+
+// MultisigSignTransaction implements Driver.
+func (d *KmdDriver) MultisigSignTransaction(password string, tx types.Transaction, pk ed25519.PublicKey, partial types.MultisigSig) (msig types.MultisigSig, err error) {
+	resp, err := d.kcl.MultisigSignTransaction(d.session.Handle(), password, tx, pk, partial)
+	if err != nil {
+		return
+	}
+	err = msgpack.Decode(resp.Multisig, &msig)
+	return
+}
+
+// Signer is a transaction.TransactionSigner signing with a key of a wallet,
+// so that a wallet can sign transactions added to an AtomicTransactionComposer.
+type Signer struct {
+	Driver   Driver
+	Password string
+
+	// AuthAddr the address whose key signs, if the sender was rekeyed. When
+	// zero, transactions are signed with the key of their sender.
+	AuthAddr types.Address
+}
+
+// SignTransactions implements transaction.TransactionSigner.
+func (s Signer) SignTransactions(txGroup []types.Transaction, indexesToSign []int) ([][]byte, error) {
+	stxs := make([][]byte, len(indexesToSign))
+	for i, pos := range indexesToSign {
+		authAddr := s.AuthAddr
+		if authAddr.IsZero() {
+			authAddr = txGroup[pos].Sender
+		}
+		stx, err := s.Driver.SignTransactionWithPublicKey(s.Password, txGroup[pos], authAddr[:])
+		if err != nil {
+			return nil, err
+		}
+		stxs[i] = stx
+	}
+	return stxs, nil
+}
+
+// Equals implements transaction.TransactionSigner. Drivers are compared by
+// identity, and drivers of types which are not comparable never are equal.
+func (s Signer) Equals(other transaction.TransactionSigner) bool {
+	castedSigner, ok := other.(Signer)
+	if !ok || castedSigner.Password != s.Password || castedSigner.AuthAddr != s.AuthAddr {
+		return false
+	}
+	if s.Driver == nil || castedSigner.Driver == nil {
+		return s.Driver == nil && castedSigner.Driver == nil
+	}
+	driverType := reflect.TypeOf(s.Driver)
+	return driverType == reflect.TypeOf(castedSigner.Driver) && driverType.Comparable() && s.Driver == castedSigner.Driver
+}
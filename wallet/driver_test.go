@@ -0,0 +1,91 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/kmd"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/transaction"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+func testDriver(t *testing.T, driver Driver, accounts []crypto.Account) {
+	var addrs []types.Address
+	require.NoError(t, driver.ListKeysFunc(func(addr types.Address) error {
+		addrs = append(addrs, addr)
+		return nil
+	}))
+	require.Equal(t, []types.Address{accounts[0].Address, accounts[1].Address}, addrs)
+
+	tx := types.Transaction{Type: types.PaymentTx, Header: types.Header{Sender: accounts[0].Address, FirstValid: 1, LastValid: 2}}
+	signer := Signer{Driver: driver, Password: password}
+	var _ transaction.TransactionSigner = signer
+	require.True(t, signer.Equals(Signer{Driver: driver, Password: password}))
+	require.False(t, signer.Equals(Signer{Driver: driver, Password: "other"}))
+
+	stxs, err := signer.SignTransactions([]types.Transaction{tx, tx}, []int{1})
+	require.NoError(t, err)
+	_, expected, err := crypto.SignTransaction(accounts[0].PrivateKey, tx)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{expected}, stxs)
+
+	// a rekeyed sender is signed for by its auth address
+	signer.AuthAddr = accounts[1].Address
+	stxs, err = signer.SignTransactions([]types.Transaction{tx}, []int{0})
+	require.NoError(t, err)
+	_, expected, err = crypto.SignTransaction(accounts[1].PrivateKey, tx)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{expected}, stxs)
+}
+
+func TestFileWalletDriver(t *testing.T) {
+	w, _ := createWallet(t, types.MasterDerivationKey{})
+	accounts := []crypto.Account{crypto.GenerateAccount(), crypto.GenerateAccount()}
+	for _, account := range accounts {
+		_, err := w.ImportKey(account.PrivateKey)
+		require.NoError(t, err)
+	}
+	testDriver(t, w, accounts)
+}
+
+func TestKmdDriver(t *testing.T) {
+	w, _ := createWallet(t, types.MasterDerivationKey{})
+	accounts := []crypto.Account{crypto.GenerateAccount(), crypto.GenerateAccount()}
+	for _, account := range accounts {
+		_, err := w.ImportKey(account.PrivateKey)
+		require.NoError(t, err)
+	}
+
+	kcl, _ := newFakeKmd(t, w)
+	session, err := kmd.NewWalletSession(kcl, w.ID(), password)
+	require.NoError(t, err)
+	defer session.Close()
+	testDriver(t, NewKmdDriver(kcl, session), accounts)
+}
+
+// sliceDriver is a Driver whose type is not comparable.
+type sliceDriver struct {
+	Driver
+	keys []types.Address
+}
+
+func TestSignerEquals(t *testing.T) {
+	w, _ := createWallet(t, types.MasterDerivationKey{})
+	other, _ := createWallet(t, types.MasterDerivationKey{})
+	authAddr := crypto.GenerateAccount().Address
+	signer := Signer{Driver: w, Password: password, AuthAddr: authAddr}
+
+	require.True(t, signer.Equals(Signer{Driver: w, Password: password, AuthAddr: authAddr}))
+	require.False(t, signer.Equals(Signer{Driver: other, Password: password, AuthAddr: authAddr}))
+	require.False(t, signer.Equals(Signer{Driver: w, Password: password}))
+	require.False(t, signer.Equals(transaction.EmptyTransactionSigner{}))
+	require.True(t, Signer{}.Equals(Signer{}))
+	require.False(t, signer.Equals(Signer{Password: password, AuthAddr: authAddr}))
+
+	uncomparable := Signer{Driver: sliceDriver{Driver: w}, Password: password}
+	require.False(t, uncomparable.Equals(uncomparable))
+	require.False(t, uncomparable.Equals(Signer{Driver: w, Password: password}))
+	require.False(t, Signer{Driver: w, Password: password}.Equals(uncomparable))
+}
@@ -0,0 +1,633 @@
+// Package wallet implements kmd's wallet semantics in process, so that
+// applications can generate, import and sign with keys without running the kmd
+// daemon. A FileWallet keeps its master derivation key, keys, multisig
+// preimages and watch-only addresses in a single file encrypted with the
+// wallet password.
+//
+// Errors are the ones of the kmd client, e.g. kmd.ErrWrongPassword, so code can
+// handle both the same way with errors.Is.
+package wallet
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/kmd"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// fileVersion is the version of the wallet file format.
+const fileVersion = 1
+
+// scrypt parameters used to derive the encryption key from the password.
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// hkdfInfoFormat is the HKDF info with which kmd expands the master derivation
+// key into the key with an index.
+const hkdfInfoFormat = "AlgorandDeterministicKey-%d"
+
+var (
+	// ErrKeyExists the key is already stored in the wallet.
+	ErrKeyExists = errors.New("key already exists in wallet")
+
+	// ErrWalletExists a wallet file already exists at the path.
+	ErrWalletExists = errors.New("wallet already exists")
+
+	// ErrWatchOnly the wallet only holds the public key of the address, so it
+	// cannot sign for it.
+	ErrWatchOnly = errors.New("address is watch-only")
+)
+
+// walletFile is the encoding of a wallet file. The wallet data is encrypted
+// with a key derived from the password and the salt. Backup archives share the
+// encoding, with Type set to archiveType.
+type walletFile struct {
+	_struct    struct{} `codec:",omitempty,omitemptyarray"`
+	Version    uint8    `codec:"v"`
+	Type       string   `codec:"type"`
+	Salt       []byte   `codec:"salt"`
+	Nonce      []byte   `codec:"nonce"`
+	Ciphertext []byte   `codec:"ct"`
+}
+
+// walletData is the content of a wallet.
+type walletData struct {
+	_struct             struct{}                  `codec:",omitempty,omitemptyarray"`
+	ID                  string                    `codec:"id"`
+	Name                string                    `codec:"name"`
+	MasterDerivationKey types.MasterDerivationKey `codec:"mdk"`
+	MaxIndex            uint64                    `codec:"idx"`
+	Keys                []storedKey               `codec:"keys"`
+	Multisigs           []storedMultisig          `codec:"msigs"`
+	WatchOnly           []types.Address           `codec:"watch"`
+}
+
+type storedKey struct {
+	_struct   struct{} `codec:",omitempty,omitemptyarray"`
+	SecretKey []byte   `codec:"sk"`
+}
+
+type storedMultisig struct {
+	_struct   struct{} `codec:",omitempty,omitemptyarray"`
+	Version   uint8    `codec:"v"`
+	Threshold uint8    `codec:"thr"`
+	Pks       [][]byte `codec:"pks"`
+}
+
+func (m storedMultisig) account() crypto.MultisigAccount {
+	ma := crypto.MultisigAccount{Version: m.Version, Threshold: m.Threshold}
+	for _, pk := range m.Pks {
+		ma.Pks = append(ma.Pks, pk)
+	}
+	return ma
+}
+
+// FileWallet is a wallet stored in an encrypted file. It is safe for
+// concurrent use.
+type FileWallet struct {
+	path string
+
+	mu   sync.Mutex
+	salt []byte
+	key  [32]byte
+	data walletData
+}
+
+// Create creates a wallet file at path, encrypted with password. If mdk is
+// zero, a random master derivation key is generated.
+func Create(path, name, password string, mdk types.MasterDerivationKey) (*FileWallet, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, ErrWalletExists
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var id [16]byte
+	crypto.RandomBytes(id[:])
+	if mdk == (types.MasterDerivationKey{}) {
+		crypto.RandomBytes(mdk[:])
+	}
+
+	w := &FileWallet{
+		path: path,
+		salt: make([]byte, 32),
+		data: walletData{
+			ID:                  hex.EncodeToString(id[:]),
+			Name:                name,
+			MasterDerivationKey: mdk,
+		},
+	}
+	crypto.RandomBytes(w.salt)
+	key, err := deriveEncryptionKey(password, w.salt)
+	if err != nil {
+		return nil, err
+	}
+	w.key = key
+
+	return w, w.save()
+}
+
+// Open decrypts the wallet file at path with password. It returns
+// kmd.ErrWrongPassword if the password is incorrect.
+func Open(path, password string) (*FileWallet, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, salt, key, err := openFile(raw, "", password)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &FileWallet{path: path, salt: salt, key: key}
+	if err := msgpack.Decode(plaintext, &w.data); err != nil {
+		return nil, fmt.Errorf("invalid wallet data: %w", err)
+	}
+	return w, nil
+}
+
+// openFile decrypts an encoded walletFile of the given type, returning its
+// plaintext along with the salt and key it was encrypted with.
+func openFile(raw []byte, fileType, password string) (plaintext, salt []byte, key [32]byte, err error) {
+	var file walletFile
+	if err = msgpack.Decode(raw, &file); err != nil {
+		err = fmt.Errorf("invalid wallet file: %w", err)
+		return
+	}
+	if file.Version != fileVersion {
+		err = fmt.Errorf("unsupported wallet file version %d", file.Version)
+		return
+	}
+	if file.Type != fileType {
+		err = fmt.Errorf("invalid wallet file: unexpected type %q", file.Type)
+		return
+	}
+	if len(file.Nonce) != 24 {
+		err = fmt.Errorf("invalid wallet file: bad nonce")
+		return
+	}
+
+	key, err = deriveEncryptionKey(password, file.Salt)
+	if err != nil {
+		return
+	}
+	var nonce [24]byte
+	copy(nonce[:], file.Nonce)
+	plaintext, ok := secretbox.Open(nil, file.Ciphertext, &nonce, &key)
+	if !ok {
+		err = kmd.ErrWrongPassword
+		return
+	}
+	return plaintext, file.Salt, key, nil
+}
+
+// sealFile encrypts plaintext into an encoded walletFile of the given type.
+func sealFile(plaintext []byte, fileType string, salt []byte, key [32]byte) []byte {
+	var nonce [24]byte
+	crypto.RandomBytes(nonce[:])
+	return msgpack.Encode(walletFile{
+		Version:    fileVersion,
+		Type:       fileType,
+		Salt:       salt,
+		Nonce:      nonce[:],
+		Ciphertext: secretbox.Seal(nil, plaintext, &nonce, &key),
+	})
+}
+
+func deriveEncryptionKey(password string, salt []byte) (key [32]byte, err error) {
+	derived, err := scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, len(key))
+	if err != nil {
+		return
+	}
+	copy(key[:], derived)
+	return
+}
+
+// save encrypts the wallet and atomically replaces its file.
+func (w *FileWallet) save() error {
+	tmp, err := ioutil.TempFile(filepath.Dir(w.path), filepath.Base(w.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(sealFile(msgpack.Encode(w.data), "", w.salt, w.key)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), w.path)
+}
+
+// checkPassword returns kmd.ErrWrongPassword unless password is the wallet's.
+func (w *FileWallet) checkPassword(password string) error {
+	key, err := deriveEncryptionKey(password, w.salt)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(key[:], w.key[:]) != 1 {
+		return kmd.ErrWrongPassword
+	}
+	return nil
+}
+
+// ID returns the randomly generated ID of the wallet.
+func (w *FileWallet) ID() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.data.ID
+}
+
+// Name returns the name of the wallet.
+func (w *FileWallet) Name() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.data.Name
+}
+
+// Rename changes the name of the wallet.
+func (w *FileWallet) Rename(password, name string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkPassword(password); err != nil {
+		return err
+	}
+	w.data.Name = name
+	return w.save()
+}
+
+// ExportMasterDerivationKey returns the master derivation key of the wallet,
+// from which the keys generated by GenerateKey can be recovered.
+func (w *FileWallet) ExportMasterDerivationKey(password string) (types.MasterDerivationKey, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkPassword(password); err != nil {
+		return types.MasterDerivationKey{}, err
+	}
+	return w.data.MasterDerivationKey, nil
+}
+
+// deriveKey derives the key with the given index from the master derivation
+// key as kmd does, so that a FileWallet and a kmd wallet with the same master
+// derivation key generate the same sequence of keys. Like kmd, wallets
+// generate their first key at index 1.
+func deriveKey(mdk types.MasterDerivationKey, index uint64) ed25519.PrivateKey {
+	keystream := hkdf.Expand(sha512.New512_256, mdk[:], []byte(fmt.Sprintf(hkdfInfoFormat, index)))
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := io.ReadFull(keystream, seed); err != nil {
+		// HKDF expands a key into much more than a seed
+		panic(err)
+	}
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+// GenerateKey derives the next key of the wallet from its master derivation
+// key and returns its address.
+func (w *FileWallet) GenerateKey() (types.Address, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for {
+		w.data.MaxIndex++
+		sk := deriveKey(w.data.MasterDerivationKey, w.data.MaxIndex)
+		// skip keys which were imported before being generated
+		if _, ok := w.find(publicKey(sk)); ok {
+			continue
+		}
+		w.data.Keys = append(w.data.Keys, storedKey{SecretKey: sk})
+		return addressOf(sk), w.save()
+	}
+}
+
+// ImportKey imports a private key into the wallet and returns its address.
+func (w *FileWallet) ImportKey(sk ed25519.PrivateKey) (types.Address, error) {
+	if len(sk) != ed25519.PrivateKeySize {
+		return types.Address{}, fmt.Errorf("invalid private key length %d", len(sk))
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.find(publicKey(sk)); ok {
+		return types.Address{}, ErrKeyExists
+	}
+	// the address is no longer watch-only once its key is imported
+	if i, ok := w.findWatchOnly(addressOf(sk)); ok {
+		w.data.WatchOnly = append(w.data.WatchOnly[:i], w.data.WatchOnly[i+1:]...)
+	}
+	w.data.Keys = append(w.data.Keys, storedKey{SecretKey: append([]byte{}, sk...)})
+	return addressOf(sk), w.save()
+}
+
+// ExportKey returns the private key of an address in the wallet.
+func (w *FileWallet) ExportKey(password string, addr types.Address) (ed25519.PrivateKey, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkPassword(password); err != nil {
+		return nil, err
+	}
+	i, ok := w.find(addr[:])
+	if !ok {
+		return nil, kmd.ErrKeyNotFound
+	}
+	return append(ed25519.PrivateKey{}, w.data.Keys[i].SecretKey...), nil
+}
+
+// DeleteKey removes the key of an address from the wallet. A deleted
+// generated key is not generated again, but can be recovered from the master
+// derivation key.
+func (w *FileWallet) DeleteKey(password string, addr types.Address) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkPassword(password); err != nil {
+		return err
+	}
+	i, ok := w.find(addr[:])
+	if !ok {
+		return kmd.ErrKeyNotFound
+	}
+	w.data.Keys = append(w.data.Keys[:i], w.data.Keys[i+1:]...)
+	return w.save()
+}
+
+// ListKeys returns the addresses whose keys are stored in the wallet, in the
+// order they were added.
+func (w *FileWallet) ListKeys() []types.Address {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	addrs := make([]types.Address, len(w.data.Keys))
+	for i, key := range w.data.Keys {
+		addrs[i] = addressOf(key.SecretKey)
+	}
+	return addrs
+}
+
+// ListKeysFunc calls fn with the address of each key in the wallet, in the
+// order they were added, without building the whole list. It stops at and
+// returns the first error returned by fn. Keys added or deleted during the
+// enumeration may be skipped.
+func (w *FileWallet) ListKeysFunc(fn func(addr types.Address) error) error {
+	for i := 0; ; i++ {
+		w.mu.Lock()
+		if i >= len(w.data.Keys) {
+			w.mu.Unlock()
+			return nil
+		}
+		addr := addressOf(w.data.Keys[i].SecretKey)
+		w.mu.Unlock()
+
+		if err := fn(addr); err != nil {
+			return err
+		}
+	}
+}
+
+// find returns the index of the key with the given public key.
+func (w *FileWallet) find(pk []byte) (int, bool) {
+	for i, key := range w.data.Keys {
+		if bytes.Equal(publicKey(key.SecretKey), pk) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// secretKey returns the secret key for a public key.
+func (w *FileWallet) secretKey(pk []byte) (ed25519.PrivateKey, error) {
+	i, ok := w.find(pk)
+	if !ok {
+		var addr types.Address
+		copy(addr[:], pk)
+		if _, watched := w.findWatchOnly(addr); watched {
+			return nil, ErrWatchOnly
+		}
+		return nil, kmd.ErrKeyNotFound
+	}
+	return w.data.Keys[i].SecretKey, nil
+}
+
+// SignTransaction signs a transaction with the key of its sender and returns
+// the encoded signed transaction.
+func (w *FileWallet) SignTransaction(password string, tx types.Transaction) ([]byte, error) {
+	return w.SignTransactionWithPublicKey(password, tx, tx.Sender[:])
+}
+
+// SignTransactionWithPublicKey signs a transaction with the key for pk, e.g.
+// the key a rekeyed sender is rekeyed to, and returns the encoded signed
+// transaction.
+func (w *FileWallet) SignTransactionWithPublicKey(password string, tx types.Transaction, pk ed25519.PublicKey) ([]byte, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkPassword(password); err != nil {
+		return nil, err
+	}
+	sk, err := w.secretKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	_, stx, err := crypto.SignTransaction(sk, tx)
+	return stx, err
+}
+
+// SignProgram signs a program with the key of an address, for a delegated
+// logic signature.
+func (w *FileWallet) SignProgram(password string, addr types.Address, program []byte) (types.Signature, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkPassword(password); err != nil {
+		return types.Signature{}, err
+	}
+	sk, err := w.secretKey(addr[:])
+	if err != nil {
+		return types.Signature{}, err
+	}
+	lsa, err := crypto.MakeLogicSigAccountDelegated(program, nil, sk)
+	if err != nil {
+		return types.Signature{}, err
+	}
+	return lsa.Lsig.Sig, nil
+}
+
+// ImportMultisig stores the preimage of a multisig address in the wallet and
+// returns the address.
+func (w *FileWallet) ImportMultisig(version, threshold uint8, pks []ed25519.PublicKey) (types.Address, error) {
+	m := storedMultisig{Version: version, Threshold: threshold}
+	for _, pk := range pks {
+		m.Pks = append(m.Pks, append([]byte{}, pk...))
+	}
+	addr, err := m.account().Address()
+	if err != nil {
+		return types.Address{}, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.findMultisig(addr); !ok {
+		w.data.Multisigs = append(w.data.Multisigs, m)
+	}
+	return addr, w.save()
+}
+
+// ListMultisig returns the multisig addresses whose preimages are stored in
+// the wallet.
+func (w *FileWallet) ListMultisig() []types.Address {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var addrs []types.Address
+	for _, m := range w.data.Multisigs {
+		if addr, err := m.account().Address(); err == nil {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// ExportMultisig returns the preimage of a multisig address in the wallet.
+func (w *FileWallet) ExportMultisig(password string, addr types.Address) (crypto.MultisigAccount, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkPassword(password); err != nil {
+		return crypto.MultisigAccount{}, err
+	}
+	i, ok := w.findMultisig(addr)
+	if !ok {
+		return crypto.MultisigAccount{}, kmd.ErrMultisigNotFound
+	}
+	return w.data.Multisigs[i].account(), nil
+}
+
+// DeleteMultisig removes the preimage of a multisig address from the wallet.
+func (w *FileWallet) DeleteMultisig(password string, addr types.Address) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkPassword(password); err != nil {
+		return err
+	}
+	i, ok := w.findMultisig(addr)
+	if !ok {
+		return kmd.ErrMultisigNotFound
+	}
+	w.data.Multisigs = append(w.data.Multisigs[:i], w.data.Multisigs[i+1:]...)
+	return w.save()
+}
+
+func (w *FileWallet) findMultisig(addr types.Address) (int, bool) {
+	for i, m := range w.data.Multisigs {
+		if a, err := m.account().Address(); err == nil && a == addr {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// multisigAccount returns the multisig account of a partial signature, or
+// the stored preimage of addr if the partial signature is blank.
+func (w *FileWallet) multisigAccount(addr types.Address, partial types.MultisigSig) (crypto.MultisigAccount, error) {
+	if !partial.Blank() {
+		return crypto.MultisigAccountFromSig(partial)
+	}
+	i, ok := w.findMultisig(addr)
+	if !ok {
+		return crypto.MultisigAccount{}, kmd.ErrMultisigNotFound
+	}
+	return w.data.Multisigs[i].account(), nil
+}
+
+// MultisigSignTransaction adds the signature of the key for pk to a multisig
+// transaction. If partial is blank, the preimage of the transaction's sender
+// must be stored in the wallet.
+func (w *FileWallet) MultisigSignTransaction(password string, tx types.Transaction, pk ed25519.PublicKey, partial types.MultisigSig) (types.MultisigSig, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkPassword(password); err != nil {
+		return types.MultisigSig{}, err
+	}
+	sk, err := w.secretKey(pk)
+	if err != nil {
+		return types.MultisigSig{}, err
+	}
+	ma, err := w.multisigAccount(tx.Sender, partial)
+	if err != nil {
+		return types.MultisigSig{}, err
+	}
+
+	_, stxBytes, err := crypto.SignMultisigTransaction(sk, ma, tx)
+	if err != nil {
+		return types.MultisigSig{}, err
+	}
+	var stx types.SignedTxn
+	if err := msgpack.Decode(stxBytes, &stx); err != nil {
+		return types.MultisigSig{}, err
+	}
+	return mergeMultisig(partial, stx.Msig), nil
+}
+
+// MultisigSignProgram adds the signature of the key for pk to a multisig
+// program signature, for a delegated multisig logic signature. If partial is
+// blank, the preimage of addr must be stored in the wallet.
+func (w *FileWallet) MultisigSignProgram(password string, addr types.Address, program []byte, pk ed25519.PublicKey, partial types.MultisigSig) (types.MultisigSig, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkPassword(password); err != nil {
+		return types.MultisigSig{}, err
+	}
+	sk, err := w.secretKey(pk)
+	if err != nil {
+		return types.MultisigSig{}, err
+	}
+	ma, err := w.multisigAccount(addr, partial)
+	if err != nil {
+		return types.MultisigSig{}, err
+	}
+
+	lsa, err := crypto.MakeLogicSigAccountDelegatedMsig(program, nil, ma, sk)
+	if err != nil {
+		return types.MultisigSig{}, err
+	}
+	return mergeMultisig(partial, lsa.Lsig.Msig), nil
+}
+
+// mergeMultisig adds the signatures of signed to partial, which must be for
+// the same multisig account.
+func mergeMultisig(partial, signed types.MultisigSig) types.MultisigSig {
+	if partial.Blank() {
+		return signed
+	}
+	merged := partial
+	merged.Subsigs = append([]types.MultisigSubsig{}, partial.Subsigs...)
+	for i, subsig := range signed.Subsigs {
+		if subsig.Sig != (types.Signature{}) {
+			merged.Subsigs[i].Sig = subsig.Sig
+		}
+	}
+	return merged
+}
+
+func publicKey(sk ed25519.PrivateKey) []byte {
+	return sk.Public().(ed25519.PublicKey)
+}
+
+func addressOf(sk ed25519.PrivateKey) (addr types.Address) {
+	copy(addr[:], publicKey(sk))
+	return
+}
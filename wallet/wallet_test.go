@@ -0,0 +1,209 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/kmd"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/encoding/msgpack"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+const password = "correct horse"
+
+func createWallet(t *testing.T, mdk types.MasterDerivationKey) (*FileWallet, string) {
+	path := filepath.Join(t.TempDir(), "test.wallet")
+	w, err := Create(path, "test", password, mdk)
+	require.NoError(t, err)
+	return w, path
+}
+
+func TestCreateOpen(t *testing.T) {
+	w, path := createWallet(t, types.MasterDerivationKey{})
+	addr, err := w.GenerateKey()
+	require.NoError(t, err)
+
+	_, err = Create(path, "again", password, types.MasterDerivationKey{})
+	require.ErrorIs(t, err, ErrWalletExists)
+
+	_, err = Open(path, "wrong")
+	require.ErrorIs(t, err, kmd.ErrWrongPassword)
+
+	opened, err := Open(path, password)
+	require.NoError(t, err)
+	require.Equal(t, w.ID(), opened.ID())
+	require.Equal(t, "test", opened.Name())
+	require.Equal(t, []types.Address{addr}, opened.ListKeys())
+
+	require.ErrorIs(t, opened.Rename("wrong", "renamed"), kmd.ErrWrongPassword)
+	require.NoError(t, opened.Rename(password, "renamed"))
+	opened, err = Open(path, password)
+	require.NoError(t, err)
+	require.Equal(t, "renamed", opened.Name())
+}
+
+func TestGenerateKeyDeterministic(t *testing.T) {
+	var mdk types.MasterDerivationKey
+	mdk[0] = 1
+	first, _ := createWallet(t, mdk)
+	second, _ := createWallet(t, mdk)
+
+	for i := 0; i < 3; i++ {
+		a, err := first.GenerateKey()
+		require.NoError(t, err)
+		b, err := second.GenerateKey()
+		require.NoError(t, err)
+		require.Equal(t, a, b)
+	}
+
+	exported, err := first.ExportMasterDerivationKey(password)
+	require.NoError(t, err)
+	require.Equal(t, mdk, exported)
+}
+
+func TestDeriveKey(t *testing.T) {
+	// kmd derives the key with an index as the ed25519 key of the seed
+	// HKDF-Expand(SHA-512/256, mdk, "AlgorandDeterministicKey-<index>")
+	var mdk types.MasterDerivationKey
+	for i := range mdk {
+		mdk[i] = byte(i)
+	}
+	tests := []struct {
+		index   uint64
+		seed    string
+		address string
+	}{
+		{1, "724db5dd2b5bff822d5e968fc401fdf80f778cf3a6812f2adc9629599b31b559", "RARAQGGTYYIAKPP7M44M2ITTE2AXASJD5VIMPRQDU5VJTK3XAA62OLW7IM"},
+		{2, "2fa706a61643be89ffb83b7f4b0a417b8011786a597222d2ac5a85411ba5136d", "AY3EXNCEATTJ3KUPVEVDFUYMO6CT3R3S6QQSVIGTXKCFG26L4WGLJU5PF4"},
+		{10, "71dc042553cdaa69ca594fca16b681f05c2f7b17a0ced25197d833e861cac68c", "UF6LAEXODSNQTANAHQSD7ENBDYCUNESMZKANRDN5JS4HPQ2VSGRST4FISU"},
+	}
+	for _, test := range tests {
+		sk := deriveKey(mdk, test.index)
+		require.Equal(t, test.seed, hex.EncodeToString(sk.Seed()), test.index)
+		require.Equal(t, test.address, addressOf(sk).String(), test.index)
+	}
+
+	// the first key generated is the one with index 1, as in kmd
+	w, _ := createWallet(t, mdk)
+	addr, err := w.GenerateKey()
+	require.NoError(t, err)
+	require.Equal(t, tests[0].address, addr.String())
+}
+
+func TestImportExportDeleteKey(t *testing.T) {
+	w, _ := createWallet(t, types.MasterDerivationKey{})
+	account := crypto.GenerateAccount()
+
+	addr, err := w.ImportKey(account.PrivateKey)
+	require.NoError(t, err)
+	require.Equal(t, account.Address, addr)
+	_, err = w.ImportKey(account.PrivateKey)
+	require.ErrorIs(t, err, ErrKeyExists)
+
+	_, err = w.ExportKey("wrong", addr)
+	require.ErrorIs(t, err, kmd.ErrWrongPassword)
+	sk, err := w.ExportKey(password, addr)
+	require.NoError(t, err)
+	require.Equal(t, account.PrivateKey, sk)
+
+	require.NoError(t, w.DeleteKey(password, addr))
+	require.Empty(t, w.ListKeys())
+	require.ErrorIs(t, w.DeleteKey(password, addr), kmd.ErrKeyNotFound)
+	_, err = w.ExportKey(password, addr)
+	require.ErrorIs(t, err, kmd.ErrKeyNotFound)
+}
+
+func TestSign(t *testing.T) {
+	w, _ := createWallet(t, types.MasterDerivationKey{})
+	account := crypto.GenerateAccount()
+	_, err := w.ImportKey(account.PrivateKey)
+	require.NoError(t, err)
+
+	tx := types.Transaction{Type: types.PaymentTx, Header: types.Header{Sender: account.Address, FirstValid: 1, LastValid: 2}}
+	stx, err := w.SignTransaction(password, tx)
+	require.NoError(t, err)
+	_, expected, err := crypto.SignTransaction(account.PrivateKey, tx)
+	require.NoError(t, err)
+	require.Equal(t, expected, stx)
+
+	_, err = w.SignTransaction(password, types.Transaction{Type: types.PaymentTx})
+	require.ErrorIs(t, err, kmd.ErrKeyNotFound)
+
+	program := []byte{0x06, 0x81, 0x01}
+	sig, err := w.SignProgram(password, account.Address, program)
+	require.NoError(t, err)
+	lsa, err := crypto.MakeLogicSigAccountDelegated(program, nil, account.PrivateKey)
+	require.NoError(t, err)
+	require.Equal(t, lsa.Lsig.Sig, sig)
+}
+
+func TestMultisig(t *testing.T) {
+	w, _ := createWallet(t, types.MasterDerivationKey{})
+	var pks []ed25519.PublicKey
+	for i := 0; i < 2; i++ {
+		addr, err := w.GenerateKey()
+		require.NoError(t, err)
+		pks = append(pks, addr[:])
+	}
+
+	msigAddr, err := w.ImportMultisig(1, 2, pks)
+	require.NoError(t, err)
+	require.Equal(t, []types.Address{msigAddr}, w.ListMultisig())
+
+	ma, err := w.ExportMultisig(password, msigAddr)
+	require.NoError(t, err)
+	require.Equal(t, uint8(2), ma.Threshold)
+
+	tx := types.Transaction{Type: types.PaymentTx, Header: types.Header{Sender: msigAddr, FirstValid: 1, LastValid: 2}}
+	partial, err := w.MultisigSignTransaction(password, tx, pks[0], types.MultisigSig{})
+	require.NoError(t, err)
+	require.NotEqual(t, types.Signature{}, partial.Subsigs[0].Sig)
+	require.Equal(t, types.Signature{}, partial.Subsigs[1].Sig)
+
+	full, err := w.MultisigSignTransaction(password, tx, pks[1], partial)
+	require.NoError(t, err)
+	require.Equal(t, partial.Subsigs[0].Sig, full.Subsigs[0].Sig)
+	require.True(t, crypto.VerifyMultisig(msigAddr, append([]byte("TX"), msgpack.Encode(tx)...), full))
+
+	program := []byte{0x06, 0x81, 0x01}
+	partial, err = w.MultisigSignProgram(password, msigAddr, program, pks[1], types.MultisigSig{})
+	require.NoError(t, err)
+	full, err = w.MultisigSignProgram(password, msigAddr, program, pks[0], partial)
+	require.NoError(t, err)
+	require.True(t, crypto.VerifyMultisig(msigAddr, append([]byte("Program"), program...), full))
+
+	require.NoError(t, w.DeleteMultisig(password, msigAddr))
+	require.Empty(t, w.ListMultisig())
+	_, err = w.MultisigSignTransaction(password, tx, pks[0], types.MultisigSig{})
+	require.ErrorIs(t, err, kmd.ErrMultisigNotFound)
+}
+
+func TestListKeysFunc(t *testing.T) {
+	w, _ := createWallet(t, types.MasterDerivationKey{})
+	for i := 0; i < 3; i++ {
+		_, err := w.GenerateKey()
+		require.NoError(t, err)
+	}
+
+	var addrs []types.Address
+	require.NoError(t, w.ListKeysFunc(func(addr types.Address) error {
+		addrs = append(addrs, addr)
+		return nil
+	}))
+	require.Equal(t, w.ListKeys(), addrs)
+
+	stop := errors.New("stop")
+	count := 0
+	err := w.ListKeysFunc(func(addr types.Address) error {
+		count++
+		return stop
+	})
+	require.Equal(t, stop, err)
+	require.Equal(t, 1, count)
+}
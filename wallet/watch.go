@@ -0,0 +1,56 @@
+package wallet
+
+import (
+	"github.com/algorand/go-algorand-sdk/v2/client/kmd"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+// ImportWatchOnly adds an address to the wallet without its private key, so
+// that it can be enumerated alongside the wallet's keys, e.g. by monitoring
+// setups which must never hold private keys. Signing for a watch-only address
+// returns ErrWatchOnly. Importing the address's private key later turns it
+// into a regular key.
+func (w *FileWallet) ImportWatchOnly(addr types.Address) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.find(addr[:]); ok {
+		return ErrKeyExists
+	}
+	if _, ok := w.findWatchOnly(addr); ok {
+		return ErrKeyExists
+	}
+	w.data.WatchOnly = append(w.data.WatchOnly, addr)
+	return w.save()
+}
+
+// ListWatchOnly returns the watch-only addresses of the wallet, in the order
+// they were added.
+func (w *FileWallet) ListWatchOnly() []types.Address {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]types.Address{}, w.data.WatchOnly...)
+}
+
+// DeleteWatchOnly removes a watch-only address from the wallet.
+func (w *FileWallet) DeleteWatchOnly(password string, addr types.Address) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.checkPassword(password); err != nil {
+		return err
+	}
+	i, ok := w.findWatchOnly(addr)
+	if !ok {
+		return kmd.ErrKeyNotFound
+	}
+	w.data.WatchOnly = append(w.data.WatchOnly[:i], w.data.WatchOnly[i+1:]...)
+	return w.save()
+}
+
+func (w *FileWallet) findWatchOnly(addr types.Address) (int, bool) {
+	for i, watched := range w.data.WatchOnly {
+		if watched == addr {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,48 @@
+package wallet
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand-sdk/v2/client/kmd"
+	"github.com/algorand/go-algorand-sdk/v2/crypto"
+	"github.com/algorand/go-algorand-sdk/v2/types"
+)
+
+func TestWatchOnly(t *testing.T) {
+	w, path := createWallet(t, types.MasterDerivationKey{})
+	account := crypto.GenerateAccount()
+
+	require.NoError(t, w.ImportWatchOnly(account.Address))
+	require.ErrorIs(t, w.ImportWatchOnly(account.Address), ErrKeyExists)
+	require.Equal(t, []types.Address{account.Address}, w.ListWatchOnly())
+	require.Empty(t, w.ListKeys())
+
+	tx := types.Transaction{Type: types.PaymentTx, Header: types.Header{Sender: account.Address}}
+	_, err := w.SignTransaction(password, tx)
+	require.ErrorIs(t, err, ErrWatchOnly)
+
+	opened, err := Open(path, password)
+	require.NoError(t, err)
+	require.Equal(t, []types.Address{account.Address}, opened.ListWatchOnly())
+
+	raw, err := w.Backup(password, passphrase)
+	require.NoError(t, err)
+	restored, err := Restore(filepath.Join(t.TempDir(), "restored.wallet"), password, raw, passphrase)
+	require.NoError(t, err)
+	require.Equal(t, []types.Address{account.Address}, restored.ListWatchOnly())
+
+	// importing the private key turns the address into a regular key
+	_, err = w.ImportKey(account.PrivateKey)
+	require.NoError(t, err)
+	require.Empty(t, w.ListWatchOnly())
+	require.Equal(t, []types.Address{account.Address}, w.ListKeys())
+	require.ErrorIs(t, w.ImportWatchOnly(account.Address), ErrKeyExists)
+
+	require.ErrorIs(t, restored.DeleteWatchOnly("wrong", account.Address), kmd.ErrWrongPassword)
+	require.NoError(t, restored.DeleteWatchOnly(password, account.Address))
+	require.Empty(t, restored.ListWatchOnly())
+	require.ErrorIs(t, restored.DeleteWatchOnly(password, account.Address), kmd.ErrKeyNotFound)
+}